@@ -2,6 +2,7 @@ package address
 
 import (
 	"fmt"
+	"net"
 	"strings"
 
 	"github.com/pivotal-cf/pcfdev-cli/network"
@@ -19,6 +20,17 @@ var allowedSubnets = []string{
 	"192.168.99.1",
 }
 
+var hostOnlyNetworkSubnets = []string{
+	"192.168.56.1",
+	"192.168.57.1",
+	"192.168.58.1",
+	"192.168.59.1",
+	"192.168.60.1",
+	"192.168.61.1",
+	"192.168.62.1",
+	"192.168.63.1",
+}
+
 var AllowedAddresses = map[string]string{
 	"192.168.11.11": "local.pcfdev.io",
 	"192.168.22.11": "local2.pcfdev.io",
@@ -44,15 +56,54 @@ func IPForSubnet(subnet string) string {
 	return subnet + "1"
 }
 
+// SubnetForIP returns the host address of the subnet that ip is on, which is
+// the first address in the subnet. The subnet is taken to be a /24 unless ip
+// is given in CIDR notation, such as 10.254.16.11/20.
 func SubnetForIP(ip string) (string, error) {
-	if !network.IsIPV4(ip) {
+	cidr := ip
+	if !strings.Contains(ip, "/") {
+		cidr = ip + "/24"
+	}
+
+	parsedIP, subnet, err := net.ParseCIDR(cidr)
+	if err != nil || !network.IsIPV4(parsedIP.String()) {
 		return "", fmt.Errorf("%s is not a supported IP address", ip)
 	}
 
-	splitIP := strings.Split(ip, ".")
-	splitIP[3] = "1"
+	return firstAddress(subnet).String(), nil
+}
 
-	return strings.Join(splitIP, "."), nil
+// SubnetsInPool splits a CIDR pool, such as 10.254.0.0/16, into the /24
+// subnets that it holds and returns the host address of each.
+func SubnetsInPool(pool string) ([]string, error) {
+	_, poolNet, err := net.ParseCIDR(pool)
+	if err != nil || poolNet.IP.To4() == nil {
+		return nil, fmt.Errorf("%s is not a valid IPv4 subnet pool", pool)
+	}
+	ones, _ := poolNet.Mask.Size()
+	if ones > 24 {
+		return nil, fmt.Errorf("subnet pool %s is smaller than a /24", pool)
+	}
+	if ones < 8 {
+		return nil, fmt.Errorf("subnet pool %s is larger than a /8", pool)
+	}
+
+	subnets := []string{}
+	for i := 0; i < 1<<uint(24-ones); i++ {
+		subnet := &net.IPNet{IP: make(net.IP, net.IPv4len), Mask: net.CIDRMask(24, 32)}
+		copy(subnet.IP, poolNet.IP.To4())
+		subnet.IP[1] += byte(i >> 8)
+		subnet.IP[2] += byte(i)
+		subnets = append(subnets, firstAddress(subnet).String())
+	}
+	return subnets, nil
+}
+
+func firstAddress(subnet *net.IPNet) net.IP {
+	ip := make(net.IP, net.IPv4len)
+	copy(ip, subnet.IP.To4().Mask(subnet.Mask))
+	ip[3]++
+	return ip
 }
 
 func SubnetForDomain(requestedDomain string) (string, error) {
@@ -79,3 +130,11 @@ func IsDomainAllowed(domain string) bool {
 	}
 	return false
 }
+
+func IsInHostOnlyNetworkRange(ip string) bool {
+	_, hostOnlyNetwork, err := net.ParseCIDR(network.HostOnlyNetworkRange)
+	if err != nil {
+		return false
+	}
+	return hostOnlyNetwork.Contains(net.ParseIP(ip))
+}
@@ -22,6 +22,11 @@ var _ = Describe("Address", func() {
 			Expect(address.SubnetForIP("192.168.53.53")).To(Equal("192.168.53.1"))
 		})
 
+		It("should use the netmask of an ip in CIDR notation", func() {
+			Expect(address.SubnetForIP("10.254.19.11/20")).To(Equal("10.254.16.1"))
+			Expect(address.SubnetForIP("172.16.200.7/16")).To(Equal("172.16.0.1"))
+		})
+
 		Context("when the ip is not a valid IPv4 address", func() {
 			It("should return an error", func() {
 				_, err := address.SubnetForIP("some-bad-ip")
@@ -30,6 +35,32 @@ var _ = Describe("Address", func() {
 		})
 	})
 
+	Describe("#SubnetsInPool", func() {
+		It("should split the pool into /24 subnets", func() {
+			Expect(address.SubnetsInPool("10.254.16.0/22")).To(Equal([]string{"10.254.16.1", "10.254.17.1", "10.254.18.1", "10.254.19.1"}))
+			Expect(address.SubnetsInPool("192.168.200.0/24")).To(Equal([]string{"192.168.200.1"}))
+
+			subnets, err := address.SubnetsInPool("10.0.0.0/8")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(subnets).To(HaveLen(65536))
+			Expect(subnets[257]).To(Equal("10.1.1.1"))
+		})
+
+		Context("when the pool is smaller than a /24", func() {
+			It("should return an error", func() {
+				_, err := address.SubnetsInPool("10.254.0.0/25")
+				Expect(err).To(MatchError("subnet pool 10.254.0.0/25 is smaller than a /24"))
+			})
+		})
+
+		Context("when the pool is not an IPv4 subnet", func() {
+			It("should return an error", func() {
+				_, err := address.SubnetsInPool("fd00::/64")
+				Expect(err).To(MatchError("fd00::/64 is not a valid IPv4 subnet pool"))
+			})
+		})
+	})
+
 	Describe("#SubnetForDomain", func() {
 		It("should convert a passed in domain to the correct ip", func() {
 			Expect(address.SubnetForDomain("local.pcfdev.io")).To(Equal("192.168.11.1"))
@@ -67,4 +98,14 @@ var _ = Describe("Address", func() {
 			})
 		})
 	})
+
+	Describe("#IsInHostOnlyNetworkRange", func() {
+		It("should return whether VirtualBox allows the ip for host-only networks", func() {
+			Expect(address.IsInHostOnlyNetworkRange("192.168.56.11")).To(BeTrue())
+			Expect(address.IsInHostOnlyNetworkRange("192.168.63.11")).To(BeTrue())
+			Expect(address.IsInHostOnlyNetworkRange("192.168.64.11")).To(BeFalse())
+			Expect(address.IsInHostOnlyNetworkRange("192.168.11.11")).To(BeFalse())
+			Expect(address.IsInHostOnlyNetworkRange("some-bad-ip")).To(BeFalse())
+		})
+	})
 })
@@ -38,3 +38,14 @@ func (_m *MockDriver) IsInterfaceInUse(_param0 string) (bool, error) {
 func (_mr *_MockDriverRecorder) IsInterfaceInUse(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "IsInterfaceInUse", arg0)
 }
+
+func (_m *MockDriver) NetworkModel() (string, error) {
+	ret := _m.ctrl.Call(_m, "NetworkModel")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockDriverRecorder) NetworkModel() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "NetworkModel")
+}
@@ -10,19 +10,30 @@ import (
 //go:generate mockgen -package mocks -destination mocks/network.go github.com/pivotal-cf/pcfdev-cli/address Network
 type Network interface {
 	Interfaces() (interfaces []*network.Interface, err error)
+	Routes() (routes []string, err error)
 }
 
 //go:generate mockgen -package mocks -destination mocks/driver.go github.com/pivotal-cf/pcfdev-cli/address Driver
 type Driver interface {
 	IsInterfaceInUse(interfaceName string) (inUse bool, err error)
+	NetworkModel() (model string, err error)
 }
 
+// Picker picks the subnet for a new VM. Subnets come from SubnetPool when it
+// is set, and from the nine subnets with PCF Dev domains otherwise. Subnets
+// that overlap a host interface or route are skipped.
 type Picker struct {
-	Network Network
-	Driver  Driver
+	Network    Network
+	Driver     Driver
+	SubnetPool string
 }
 
 func (p *Picker) SelectAvailableInterface(reusableInterfaces []*network.Interface, config *cfg.VMConfig) (*cfg.NetworkConfig, error) {
+	model, err := p.Driver.NetworkModel()
+	if err != nil {
+		return nil, err
+	}
+
 	if config.IP != "" || config.Domain != "" {
 		var subnetIP, ip, domain string
 		var err error
@@ -47,6 +58,10 @@ func (p *Picker) SelectAvailableInterface(reusableInterfaces []*network.Interfac
 			domain = DomainForIP(ip)
 		}
 
+		if model == network.ModelHostOnlyNetwork && !IsInHostOnlyNetworkRange(ip) {
+			return nil, fmt.Errorf("%s is not in the range %s that VirtualBox allows for host-only networks", ip, network.HostOnlyNetworkRange)
+		}
+
 		var networkInterface *network.Interface
 		if addrs := p.addrsInSet(subnetIP, reusableInterfaces); len(addrs) > 0 {
 			networkInterface = addrs[0]
@@ -69,8 +84,22 @@ func (p *Picker) SelectAvailableInterface(reusableInterfaces []*network.Interfac
 		return nil, err
 	}
 
-	for _, subnetIP := range allowedSubnets {
-		if p.nonReusableInterfaceExists(subnetIP, reusableInterfaces, allInterfaces) {
+	routes, err := p.Network.Routes()
+	if err != nil {
+		return nil, err
+	}
+
+	subnets, err := p.subnets(model)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, subnetIP := range subnets {
+		if p.nonReusableInterfaceExists(subnetIP, reusableInterfaces, allInterfaces, model) {
+			continue
+		}
+
+		if p.overlapsHostNetwork(subnetIP, reusableInterfaces, allInterfaces, routes, model) {
 			continue
 		}
 
@@ -108,6 +137,34 @@ func (p *Picker) SelectAvailableInterface(reusableInterfaces []*network.Interfac
 	return nil, fmt.Errorf("all allowed network interfaces are currently taken")
 }
 
+func (p *Picker) subnets(model string) ([]string, error) {
+	if p.SubnetPool == "" {
+		if model == network.ModelHostOnlyNetwork {
+			return hostOnlyNetworkSubnets, nil
+		}
+		return allowedSubnets, nil
+	}
+
+	subnets, err := SubnetsInPool(p.SubnetPool)
+	if err != nil {
+		return nil, err
+	}
+	if model != network.ModelHostOnlyNetwork {
+		return subnets, nil
+	}
+
+	allowed := []string{}
+	for _, subnet := range subnets {
+		if IsInHostOnlyNetworkRange(subnet) {
+			allowed = append(allowed, subnet)
+		}
+	}
+	if len(allowed) == 0 {
+		return nil, fmt.Errorf("subnet pool %s is not in the range %s that VirtualBox allows for host-only networks", p.SubnetPool, network.HostOnlyNetworkRange)
+	}
+	return allowed, nil
+}
+
 func (p *Picker) addrsInSet(ip string, set []*network.Interface) (addrs []*network.Interface) {
 	addrs = make([]*network.Interface, 0, 1)
 	for _, addr := range set {
@@ -119,16 +176,54 @@ func (p *Picker) addrsInSet(ip string, set []*network.Interface) (addrs []*netwo
 	return addrs
 }
 
-func (p *Picker) nonReusableInterfaceExists(ip string, reusableInterfaces []*network.Interface, allInterfaces []*network.Interface) bool {
+func (p *Picker) nonReusableInterfaceExists(ip string, reusableInterfaces []*network.Interface, allInterfaces []*network.Interface, model string) bool {
 	for _, iface := range allInterfaces {
-		reusable := false
-		for _, reusableIface := range reusableInterfaces {
-			if iface.HardwareAddress == reusableIface.HardwareAddress {
-				reusable = true
-			}
+		if !p.isReusable(iface, reusableInterfaces, model) && ip == iface.IP {
+			return true
+		}
+	}
+	return false
+}
+
+// overlapsHostNetwork reports whether the /24 subnet of ip overlaps the subnet
+// of a host interface or a host route. The subnet of a reusable interface,
+// and the route the host adds for it, are not counted when ip is that
+// interface's address.
+func (p *Picker) overlapsHostNetwork(ip string, reusableInterfaces []*network.Interface, allInterfaces []*network.Interface, routes []string, model string) bool {
+	subnet := network.SubnetOf(ip, "255.255.255.0")
+	reused := len(p.addrsInSet(ip, reusableInterfaces)) > 0
+
+	for _, iface := range allInterfaces {
+		ifaceSubnet := iface.Subnet()
+		if !network.Overlaps(subnet, ifaceSubnet) {
+			continue
 		}
+		if reused && ifaceSubnet == subnet && p.isReusable(iface, reusableInterfaces, model) {
+			continue
+		}
+		return true
+	}
 
-		if !reusable && ip == iface.IP {
+	for _, route := range routes {
+		if !network.Overlaps(subnet, route) {
+			continue
+		}
+		if reused && route == subnet {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func (p *Picker) isReusable(iface *network.Interface, reusableInterfaces []*network.Interface, model string) bool {
+	for _, reusableIface := range reusableInterfaces {
+		if iface.HardwareAddress == reusableIface.HardwareAddress {
+			return true
+		}
+		// Host-only networks have no hardware address of their own, so the
+		// host side of one can only be recognised by its address.
+		if model == network.ModelHostOnlyNetwork && iface.IP == reusableIface.IP {
 			return true
 		}
 	}
@@ -30,6 +30,9 @@ var _ = Describe("Picker", func() {
 			Network: mockNetwork,
 			Driver:  mockDriver,
 		}
+
+		mockDriver.EXPECT().NetworkModel().Return(network.ModelHostOnlyInterface, nil).AnyTimes()
+		mockNetwork.EXPECT().Routes().Return([]string{}, nil).AnyTimes()
 	})
 
 	AfterEach(func() {
@@ -416,6 +419,100 @@ var _ = Describe("Picker", func() {
 			})
 		})
 
+		Context("when host routes or interface subnets overlap the allowed subnets", func() {
+			var mockRoutesNetwork *mocks.MockNetwork
+
+			BeforeEach(func() {
+				mockRoutesNetwork = mocks.NewMockNetwork(mockCtrl)
+				picker.Network = mockRoutesNetwork
+			})
+
+			It("should skip the overlapping subnets", func() {
+				allInterfaces := []*network.Interface{
+					&network.Interface{
+						IP:              "192.168.20.5",
+						Netmask:         "255.255.240.0",
+						HardwareAddress: "some-hardware-address",
+						Exists:          true,
+					},
+				}
+
+				mockRoutesNetwork.EXPECT().Interfaces().Return(allInterfaces, nil)
+				mockRoutesNetwork.EXPECT().Routes().Return([]string{"10.8.0.0/16", "192.168.11.0/24"}, nil)
+
+				Expect(picker.SelectAvailableInterface([]*network.Interface{}, &config.VMConfig{})).To(Equal(&config.NetworkConfig{
+					VMIP:     "192.168.33.11",
+					VMDomain: "local3.pcfdev.io",
+					Interface: &network.Interface{
+						IP:     "192.168.33.1",
+						Exists: false,
+					},
+				}))
+			})
+
+			It("should reuse a vbox interface despite its own subnet and route", func() {
+				vboxInterfaces := []*network.Interface{
+					&network.Interface{
+						Name:            "some-vbox-interface",
+						IP:              "192.168.11.1",
+						HardwareAddress: "some-vbox-hardware-address",
+						Exists:          true,
+					},
+				}
+				allInterfaces := []*network.Interface{
+					&network.Interface{
+						IP:              "192.168.11.1",
+						Netmask:         "255.255.255.0",
+						HardwareAddress: "some-vbox-hardware-address",
+						Exists:          true,
+					},
+				}
+
+				mockRoutesNetwork.EXPECT().Interfaces().Return(allInterfaces, nil)
+				mockRoutesNetwork.EXPECT().Routes().Return([]string{"192.168.11.0/24"}, nil)
+				mockDriver.EXPECT().IsInterfaceInUse("some-vbox-interface").Return(false, nil)
+
+				Expect(picker.SelectAvailableInterface(vboxInterfaces, &config.VMConfig{})).To(Equal(&config.NetworkConfig{
+					VMIP:      "192.168.11.11",
+					VMDomain:  "local.pcfdev.io",
+					Interface: vboxInterfaces[0],
+				}))
+			})
+
+			It("should not reuse a vbox interface whose subnet is inside a wider route", func() {
+				vboxInterfaces := []*network.Interface{
+					&network.Interface{
+						Name:            "some-vbox-interface",
+						IP:              "192.168.11.1",
+						HardwareAddress: "some-vbox-hardware-address",
+						Exists:          true,
+					},
+				}
+
+				mockRoutesNetwork.EXPECT().Interfaces().Return(vboxInterfaces, nil)
+				mockRoutesNetwork.EXPECT().Routes().Return([]string{"192.168.0.0/20"}, nil)
+
+				Expect(picker.SelectAvailableInterface(vboxInterfaces, &config.VMConfig{})).To(Equal(&config.NetworkConfig{
+					VMIP:     "192.168.22.11",
+					VMDomain: "local2.pcfdev.io",
+					Interface: &network.Interface{
+						IP:     "192.168.22.1",
+						Exists: false,
+					},
+				}))
+			})
+
+			Context("when there is an error getting the host routes", func() {
+				It("should return the error", func() {
+					mockRoutesNetwork.EXPECT().Interfaces().Return([]*network.Interface{}, nil)
+					mockRoutesNetwork.EXPECT().Routes().Return(nil, errors.New("some-error"))
+
+					_, err := picker.SelectAvailableInterface([]*network.Interface{}, &config.VMConfig{})
+					Expect(err).To(MatchError("some-error"))
+				})
+			})
+		})
+
 		Context("when there is an error checking if an interface is in use", func() {
 			It("should return the error", func() {
 				vboxInterfaces := []*network.Interface{
@@ -437,5 +534,122 @@ var _ = Describe("Picker", func() {
 				Expect(err).To(MatchError("some-error"))
 			})
 		})
+
+		Context("when there is an error determining the network model", func() {
+			It("should return the error", func() {
+				mockModelDriver := mocks.NewMockDriver(mockCtrl)
+				picker.Driver = mockModelDriver
+				mockModelDriver.EXPECT().NetworkModel().Return("", errors.New("some-error"))
+
+				_, err := picker.SelectAvailableInterface([]*network.Interface{}, &config.VMConfig{})
+				Expect(err).To(MatchError("some-error"))
+			})
+		})
+
+		Context("when VirtualBox uses host-only networks", func() {
+			var mockModelDriver *mocks.MockDriver
+
+			BeforeEach(func() {
+				mockModelDriver = mocks.NewMockDriver(mockCtrl)
+				picker.Driver = mockModelDriver
+				mockModelDriver.EXPECT().NetworkModel().Return(network.ModelHostOnlyNetwork, nil)
+			})
+
+			It("should pick a subnet in the range that VirtualBox allows", func() {
+				mockNetwork.EXPECT().Interfaces().Return([]*network.Interface{}, nil)
+
+				Expect(picker.SelectAvailableInterface([]*network.Interface{}, &config.VMConfig{})).To(Equal(&config.NetworkConfig{
+					VMIP:     "192.168.56.11",
+					VMDomain: "192.168.56.11.xip.io",
+					Interface: &network.Interface{
+						IP:     "192.168.56.1",
+						Exists: false,
+					},
+				}))
+			})
+
+			It("should reuse a host-only network by its address, since it has no hardware address", func() {
+				hostOnlyNetworks := []*network.Interface{
+					&network.Interface{
+						Name:   "pcfdev-192.168.56.1",
+						IP:     "192.168.56.1",
+						Exists: true,
+					},
+				}
+				allInterfaces := []*network.Interface{
+					&network.Interface{
+						IP:              "192.168.56.1",
+						HardwareAddress: "some-bridge-hardware-address",
+						Exists:          true,
+					},
+				}
+
+				gomock.InOrder(
+					mockNetwork.EXPECT().Interfaces().Return(allInterfaces, nil),
+					mockModelDriver.EXPECT().IsInterfaceInUse("pcfdev-192.168.56.1").Return(false, nil),
+				)
+
+				Expect(picker.SelectAvailableInterface(hostOnlyNetworks, &config.VMConfig{})).To(Equal(&config.NetworkConfig{
+					VMIP:      "192.168.56.11",
+					VMDomain:  "192.168.56.11.xip.io",
+					Interface: hostOnlyNetworks[0],
+				}))
+			})
+
+			Context("when the desired ip is outside of the allowed range", func() {
+				It("should return an error", func() {
+					_, err := picker.SelectAvailableInterface([]*network.Interface{}, &config.VMConfig{
+						IP: "192.168.11.11",
+					})
+					Expect(err).To(MatchError("192.168.11.11 is not in the range 192.168.56.0/21 that VirtualBox allows for host-only networks"))
+				})
+			})
+
+			Context("when the subnet pool is outside of the allowed range", func() {
+				It("should return an error", func() {
+					picker.SubnetPool = "10.254.0.0/16"
+					mockNetwork.EXPECT().Interfaces().Return([]*network.Interface{}, nil)
+
+					_, err := picker.SelectAvailableInterface([]*network.Interface{}, &config.VMConfig{})
+					Expect(err).To(MatchError("subnet pool 10.254.0.0/16 is not in the range 192.168.56.0/21 that VirtualBox allows for host-only networks"))
+				})
+			})
+		})
+
+		Context("when a subnet pool is configured", func() {
+			BeforeEach(func() {
+				picker.SubnetPool = "10.254.0.0/16"
+			})
+
+			It("should pick the first free /24 in the pool", func() {
+				allInterfaces := []*network.Interface{
+					&network.Interface{
+						IP:              "10.254.0.1",
+						HardwareAddress: "some-vpn-hardware-address",
+						Exists:          true,
+					},
+				}
+				mockNetwork.EXPECT().Interfaces().Return(allInterfaces, nil)
+
+				Expect(picker.SelectAvailableInterface([]*network.Interface{}, &config.VMConfig{})).To(Equal(&config.NetworkConfig{
+					VMIP:     "10.254.1.11",
+					VMDomain: "10.254.1.11.xip.io",
+					Interface: &network.Interface{
+						IP:     "10.254.1.1",
+						Exists: false,
+					},
+				}))
+			})
+
+			Context("when the pool is not a valid subnet", func() {
+				It("should return an error", func() {
+					picker.SubnetPool = "some-bad-pool"
+					mockNetwork.EXPECT().Interfaces().Return([]*network.Interface{}, nil)
+
+					_, err := picker.SelectAvailableInterface([]*network.Interface{}, &config.VMConfig{})
+					Expect(err).To(MatchError("some-bad-pool is not a valid IPv4 subnet pool"))
+				})
+			})
+		})
 	})
 })
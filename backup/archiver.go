@@ -0,0 +1,236 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
+)
+
+//go:generate mockgen -package mocks -destination mocks/fs.go github.com/pivotal-cf/pcfdev-cli/backup FS
+type FS interface {
+	Exists(path string) (exists bool, err error)
+	Read(path string) (contents []byte, err error)
+	Open(path string) (contents io.ReadCloser, err error)
+	Write(path string, contents io.Reader, append bool) error
+	Copy(source string, destination string) error
+	Remove(path string) error
+	Compress(name string, path string, contentPaths []string) error
+	Decompress(archivePath string, destinationPath string) error
+	TempDir() (tempDir string, err error)
+}
+
+//go:generate mockgen -package mocks -destination mocks/ssh.go github.com/pivotal-cf/pcfdev-cli/backup SSH
+type SSH interface {
+	RunSSHCommand(ctx context.Context, command string, addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration, stdout io.Writer, stderr io.Writer) error
+	RunSSHCommandWithStdin(ctx context.Context, command string, addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration, stdin io.Reader, stdout io.Writer, stderr io.Writer) error
+	GetSSHOutput(ctx context.Context, command string, addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration) (combinedOutput string, err error)
+}
+
+type Archiver struct {
+	FS  FS
+	SSH SSH
+
+	VMConfig *config.VMConfig
+	Config   *config.Config
+}
+
+type guestData struct {
+	filename      string
+	exportCommand string
+	importCommand string
+}
+
+const (
+	archiveName               = "pcfdev-backup"
+	provisionOptionsFilename  = "provision-options.json"
+	provisionOptionsGuestPath = "/var/pcfdev/provision-options.json"
+
+	mysqldump = "sudo /var/vcap/packages/mariadb/bin/mysqldump --defaults-file=/var/vcap/jobs/mysql/config/mylogin.cnf --single-transaction --add-drop-database --databases"
+	mysql     = "sudo /var/vcap/packages/mariadb/bin/mysql --defaults-file=/var/vcap/jobs/mysql/config/mylogin.cnf"
+	blobstore = "/var/vcap/store/shared"
+)
+
+var guestDataFiles = []guestData{
+	{
+		filename:      "ccdb.sql",
+		exportCommand: mysqldump + " ccdb",
+		importCommand: mysql,
+	},
+	{
+		filename:      "uaadb.sql",
+		exportCommand: mysqldump + " uaadb",
+		importCommand: mysql,
+	},
+	{
+		filename:      "blobstore.tgz",
+		exportCommand: "sudo tar -C " + blobstore + " -czf - .",
+		importCommand: "sudo tar -C " + blobstore + " -xzpf -",
+	},
+}
+
+func (a *Archiver) Backup(ctx context.Context, path string) error {
+	privateKeyBytes, err := a.FS.Read(a.Config.PrivateKeyPath)
+	if err != nil {
+		return err
+	}
+
+	dir, err := a.FS.TempDir()
+	if err != nil {
+		return err
+	}
+	defer a.FS.Remove(dir)
+
+	contentPaths := []string{}
+	for _, data := range guestDataFiles {
+		contentPath := filepath.Join(dir, data.filename)
+		if err := a.exportToFile(ctx, data.exportCommand, contentPath, privateKeyBytes); err != nil {
+			return fmt.Errorf("failed to export %s: %s", data.filename, err)
+		}
+		contentPaths = append(contentPaths, contentPath)
+	}
+
+	provisionOptions, err := a.SSH.GetSSHOutput(ctx, "sudo cat "+provisionOptionsGuestPath, a.addresses(), privateKeyBytes, a.Config.SSHTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to export %s: %s", provisionOptionsFilename, err)
+	}
+	contentPath := filepath.Join(dir, provisionOptionsFilename)
+	if err := a.FS.Write(contentPath, strings.NewReader(provisionOptions), false); err != nil {
+		return err
+	}
+	contentPaths = append(contentPaths, contentPath)
+
+	if err := a.FS.Compress(archiveName, dir, contentPaths); err != nil {
+		return err
+	}
+
+	return a.FS.Copy(filepath.Join(dir, archiveName+".tgz"), path)
+}
+
+func (a *Archiver) Restore(ctx context.Context, path string) error {
+	exists, err := a.FS.Exists(path)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("no file found at %s", path)
+	}
+
+	privateKeyBytes, err := a.FS.Read(a.Config.PrivateKeyPath)
+	if err != nil {
+		return err
+	}
+
+	dir, err := a.FS.TempDir()
+	if err != nil {
+		return err
+	}
+	defer a.FS.Remove(dir)
+
+	if err := a.FS.Decompress(path, dir); err != nil {
+		return err
+	}
+	contentDir := filepath.Join(dir, archiveName)
+
+	backupProvisionConfig, err := a.readProvisionConfig(filepath.Join(contentDir, provisionOptionsFilename))
+	if err != nil {
+		return err
+	}
+
+	for _, data := range guestDataFiles {
+		if err := a.importFromFile(ctx, data.importCommand, filepath.Join(contentDir, data.filename), privateKeyBytes); err != nil {
+			return fmt.Errorf("failed to import %s: %s", data.filename, err)
+		}
+	}
+
+	return a.restoreProvisionConfig(ctx, backupProvisionConfig, privateKeyBytes)
+}
+
+func (a *Archiver) ReadProvisionConfig(path string) (*config.ProvisionConfig, error) {
+	dir, err := a.FS.TempDir()
+	if err != nil {
+		return nil, err
+	}
+	defer a.FS.Remove(dir)
+
+	if err := a.FS.Decompress(path, dir); err != nil {
+		return nil, err
+	}
+
+	return a.readProvisionConfig(filepath.Join(dir, archiveName, provisionOptionsFilename))
+}
+
+func (a *Archiver) exportToFile(ctx context.Context, command string, path string, privateKeyBytes []byte) error {
+	reader, writer := io.Pipe()
+	sshErr := make(chan error, 1)
+	go func() {
+		err := a.SSH.RunSSHCommand(ctx, command, a.addresses(), privateKeyBytes, a.Config.SSHTimeout, writer, ioutil.Discard)
+		writer.CloseWithError(err)
+		sshErr <- err
+	}()
+
+	if err := a.FS.Write(path, reader, false); err != nil {
+		reader.CloseWithError(err)
+		<-sshErr
+		return err
+	}
+
+	return <-sshErr
+}
+
+func (a *Archiver) importFromFile(ctx context.Context, command string, path string, privateKeyBytes []byte) error {
+	file, err := a.FS.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return a.SSH.RunSSHCommandWithStdin(ctx, command, a.addresses(), privateKeyBytes, a.Config.SSHTimeout, file, ioutil.Discard, os.Stderr)
+}
+
+func (a *Archiver) readProvisionConfig(path string) (*config.ProvisionConfig, error) {
+	data, err := a.FS.Read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	provisionConfig := &config.ProvisionConfig{}
+	if err := json.Unmarshal(data, provisionConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %s", provisionOptionsFilename, err)
+	}
+	return provisionConfig, nil
+}
+
+func (a *Archiver) restoreProvisionConfig(ctx context.Context, backupProvisionConfig *config.ProvisionConfig, privateKeyBytes []byte) error {
+	output, err := a.SSH.GetSSHOutput(ctx, "sudo cat "+provisionOptionsGuestPath, a.addresses(), privateKeyBytes, a.Config.SSHTimeout)
+	if err != nil {
+		return err
+	}
+
+	provisionConfig := &config.ProvisionConfig{}
+	if err := json.Unmarshal([]byte(output), provisionConfig); err != nil {
+		return err
+	}
+	provisionConfig.Services = backupProvisionConfig.Services
+	provisionConfig.Registries = backupProvisionConfig.Registries
+
+	data, err := json.Marshal(provisionConfig)
+	if err != nil {
+		return err
+	}
+
+	return a.SSH.RunSSHCommandWithStdin(ctx, "sudo tee "+provisionOptionsGuestPath+" >/dev/null", a.addresses(), privateKeyBytes, a.Config.SSHTimeout, bytes.NewReader(data), ioutil.Discard, os.Stderr)
+}
+
+func (a *Archiver) addresses() []ssh.SSHAddress {
+	return ssh.SSHAddresses(a.VMConfig)
+}
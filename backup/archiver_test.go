@@ -0,0 +1,378 @@
+package backup_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pivotal-cf/pcfdev-cli/backup"
+	"github.com/pivotal-cf/pcfdev-cli/backup/mocks"
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	mysqldump = "sudo /var/vcap/packages/mariadb/bin/mysqldump --defaults-file=/var/vcap/jobs/mysql/config/mylogin.cnf --single-transaction --add-drop-database --databases"
+	mysql     = "sudo /var/vcap/packages/mariadb/bin/mysql --defaults-file=/var/vcap/jobs/mysql/config/mylogin.cnf"
+)
+
+var _ = Describe("Archiver", func() {
+	var (
+		mockCtrl  *gomock.Controller
+		mockSSH   *mocks.MockSSH
+		mockFS    *mocks.MockFS
+		archiver  *backup.Archiver
+		addresses []ssh.SSHAddress
+	)
+
+	sendOutput := func(output string) func(context.Context, string, []ssh.SSHAddress, []byte, time.Duration, io.Writer, io.Writer) {
+		return func(_ context.Context, _ string, _ []ssh.SSHAddress, _ []byte, _ time.Duration, stdout io.Writer, _ io.Writer) {
+			stdout.Write([]byte(output))
+		}
+	}
+
+	expectContents := func(expectedContents string) func(string, io.Reader, bool) {
+		return func(_ string, contents io.Reader, _ bool) {
+			Expect(ioutil.ReadAll(contents)).To(Equal([]byte(expectedContents)))
+		}
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockSSH = mocks.NewMockSSH(mockCtrl)
+		mockFS = mocks.NewMockFS(mockCtrl)
+		archiver = &backup.Archiver{
+			SSH: mockSSH,
+			FS:  mockFS,
+
+			VMConfig: &config.VMConfig{
+				IP:      "some-ip",
+				SSHPort: "some-port",
+			},
+
+			Config: &config.Config{
+				SSHTimeout:     7 * time.Minute,
+				PrivateKeyPath: "some-private-key-path",
+			},
+		}
+		addresses = []ssh.SSHAddress{
+			{
+				IP:   "127.0.0.1",
+				Port: "some-port",
+			},
+			{
+				IP:   "some-ip",
+				Port: "22",
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("#Backup", func() {
+		It("should export the databases, blobstore and provision options to an archive", func() {
+			gomock.InOrder(
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), mysqldump+" ccdb", addresses, []byte("some-private-key"), 7*time.Minute, gomock.Any(), gomock.Any()).Do(sendOutput("some-ccdb")),
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), mysqldump+" uaadb", addresses, []byte("some-private-key"), 7*time.Minute, gomock.Any(), gomock.Any()).Do(sendOutput("some-uaadb")),
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), "sudo tar -C /var/vcap/store/shared -czf - .", addresses, []byte("some-private-key"), 7*time.Minute, gomock.Any(), gomock.Any()).Do(sendOutput("some-blobstore")),
+				mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/pcfdev/provision-options.json", addresses, []byte("some-private-key"), 7*time.Minute).Return("some-provision-options", nil),
+			)
+			gomock.InOrder(
+				mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
+				mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
+				mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "ccdb.sql"), gomock.Any(), false).Do(expectContents("some-ccdb")),
+				mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "uaadb.sql"), gomock.Any(), false).Do(expectContents("some-uaadb")),
+				mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "blobstore.tgz"), gomock.Any(), false).Do(expectContents("some-blobstore")),
+				mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "provision-options.json"), strings.NewReader("some-provision-options"), false),
+				mockFS.EXPECT().Compress("pcfdev-backup", "some-temp-dir", []string{
+					filepath.Join("some-temp-dir", "ccdb.sql"),
+					filepath.Join("some-temp-dir", "uaadb.sql"),
+					filepath.Join("some-temp-dir", "blobstore.tgz"),
+					filepath.Join("some-temp-dir", "provision-options.json"),
+				}),
+				mockFS.EXPECT().Copy(filepath.Join("some-temp-dir", "pcfdev-backup.tgz"), "some-archive-path"),
+				mockFS.EXPECT().Remove("some-temp-dir"),
+			)
+
+			Expect(archiver.Backup(context.Background(), "some-archive-path")).To(Succeed())
+		})
+
+		Context("when reading the private key fails", func() {
+			It("should return an error", func() {
+				mockFS.EXPECT().Read("some-private-key-path").Return(nil, errors.New("some-error"))
+
+				Expect(archiver.Backup(context.Background(), "some-archive-path")).To(MatchError("some-error"))
+			})
+		})
+
+		Context("when creating a temp dir fails", func() {
+			It("should return an error", func() {
+				gomock.InOrder(
+					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
+					mockFS.EXPECT().TempDir().Return("", errors.New("some-error")),
+				)
+
+				Expect(archiver.Backup(context.Background(), "some-archive-path")).To(MatchError("some-error"))
+			})
+		})
+
+		Context("when exporting data from the VM fails", func() {
+			It("should return an error", func() {
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), mysqldump+" ccdb", addresses, []byte("some-private-key"), 7*time.Minute, gomock.Any(), gomock.Any()).Return(errors.New("some-error"))
+				gomock.InOrder(
+					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
+					mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
+					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "ccdb.sql"), gomock.Any(), false).Do(func(_ string, contents io.Reader, _ bool) {
+						_, err := ioutil.ReadAll(contents)
+						Expect(err).To(MatchError("some-error"))
+					}),
+					mockFS.EXPECT().Remove("some-temp-dir"),
+				)
+
+				Expect(archiver.Backup(context.Background(), "some-archive-path")).To(MatchError("failed to export ccdb.sql: some-error"))
+			})
+		})
+
+		Context("when writing exported data fails", func() {
+			It("should return an error", func() {
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), mysqldump+" ccdb", addresses, []byte("some-private-key"), 7*time.Minute, gomock.Any(), gomock.Any()).Do(sendOutput("some-ccdb"))
+				gomock.InOrder(
+					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
+					mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
+					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "ccdb.sql"), gomock.Any(), false).Return(errors.New("some-error")),
+					mockFS.EXPECT().Remove("some-temp-dir"),
+				)
+
+				Expect(archiver.Backup(context.Background(), "some-archive-path")).To(MatchError("failed to export ccdb.sql: some-error"))
+			})
+		})
+
+		Context("when reading the provision options fails", func() {
+			It("should return an error", func() {
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), gomock.Any(), addresses, []byte("some-private-key"), 7*time.Minute, gomock.Any(), gomock.Any()).Times(3)
+				mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/pcfdev/provision-options.json", addresses, []byte("some-private-key"), 7*time.Minute).Return("", errors.New("some-error"))
+				gomock.InOrder(
+					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
+					mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
+					mockFS.EXPECT().Write(gomock.Any(), gomock.Any(), false).Do(expectContents("")).Times(3),
+					mockFS.EXPECT().Remove("some-temp-dir"),
+				)
+
+				Expect(archiver.Backup(context.Background(), "some-archive-path")).To(MatchError("failed to export provision-options.json: some-error"))
+			})
+		})
+
+		Context("when compressing the archive fails", func() {
+			It("should return an error", func() {
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), gomock.Any(), addresses, []byte("some-private-key"), 7*time.Minute, gomock.Any(), gomock.Any()).Times(3)
+				mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/pcfdev/provision-options.json", addresses, []byte("some-private-key"), 7*time.Minute).Return("some-provision-options", nil)
+				gomock.InOrder(
+					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
+					mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
+					mockFS.EXPECT().Write(gomock.Any(), gomock.Any(), false).Do(expectContents("")).Times(3),
+					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "provision-options.json"), strings.NewReader("some-provision-options"), false),
+					mockFS.EXPECT().Compress("pcfdev-backup", "some-temp-dir", gomock.Any()).Return(errors.New("some-error")),
+					mockFS.EXPECT().Remove("some-temp-dir"),
+				)
+
+				Expect(archiver.Backup(context.Background(), "some-archive-path")).To(MatchError("some-error"))
+			})
+		})
+
+		Context("when copying the archive fails", func() {
+			It("should return an error", func() {
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), gomock.Any(), addresses, []byte("some-private-key"), 7*time.Minute, gomock.Any(), gomock.Any()).Times(3)
+				mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/pcfdev/provision-options.json", addresses, []byte("some-private-key"), 7*time.Minute).Return("some-provision-options", nil)
+				gomock.InOrder(
+					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
+					mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
+					mockFS.EXPECT().Write(gomock.Any(), gomock.Any(), false).Do(expectContents("")).Times(3),
+					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "provision-options.json"), strings.NewReader("some-provision-options"), false),
+					mockFS.EXPECT().Compress("pcfdev-backup", "some-temp-dir", gomock.Any()),
+					mockFS.EXPECT().Copy(filepath.Join("some-temp-dir", "pcfdev-backup.tgz"), "some-archive-path").Return(errors.New("some-error")),
+					mockFS.EXPECT().Remove("some-temp-dir"),
+				)
+
+				Expect(archiver.Backup(context.Background(), "some-archive-path")).To(MatchError("some-error"))
+			})
+		})
+	})
+
+	Describe("#Restore", func() {
+		var (
+			ccdb      io.ReadCloser
+			uaadb     io.ReadCloser
+			blobstore io.ReadCloser
+		)
+
+		BeforeEach(func() {
+			ccdb = ioutil.NopCloser(strings.NewReader("some-ccdb"))
+			uaadb = ioutil.NopCloser(strings.NewReader("some-uaadb"))
+			blobstore = ioutil.NopCloser(strings.NewReader("some-blobstore"))
+		})
+
+		It("should import the databases and blobstore and restore the services and registries", func() {
+			gomock.InOrder(
+				mockFS.EXPECT().Exists("some-archive-path").Return(true, nil),
+				mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
+				mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
+				mockFS.EXPECT().Decompress("some-archive-path", "some-temp-dir"),
+				mockFS.EXPECT().Read(filepath.Join("some-temp-dir", "pcfdev-backup", "provision-options.json")).Return([]byte(`{"domain":"some-old-domain","ip":"some-old-ip","services":"some-'services","registries":["some-registry"],"provider":"some-provider"}`), nil),
+				mockFS.EXPECT().Open(filepath.Join("some-temp-dir", "pcfdev-backup", "ccdb.sql")).Return(ccdb, nil),
+				mockSSH.EXPECT().RunSSHCommandWithStdin(gomock.Any(), mysql, addresses, []byte("some-private-key"), 7*time.Minute, ccdb, gomock.Any(), gomock.Any()),
+				mockFS.EXPECT().Open(filepath.Join("some-temp-dir", "pcfdev-backup", "uaadb.sql")).Return(uaadb, nil),
+				mockSSH.EXPECT().RunSSHCommandWithStdin(gomock.Any(), mysql, addresses, []byte("some-private-key"), 7*time.Minute, uaadb, gomock.Any(), gomock.Any()),
+				mockFS.EXPECT().Open(filepath.Join("some-temp-dir", "pcfdev-backup", "blobstore.tgz")).Return(blobstore, nil),
+				mockSSH.EXPECT().RunSSHCommandWithStdin(gomock.Any(), "sudo tar -C /var/vcap/store/shared -xzpf -", addresses, []byte("some-private-key"), 7*time.Minute, blobstore, gomock.Any(), gomock.Any()),
+				mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/pcfdev/provision-options.json", addresses, []byte("some-private-key"), 7*time.Minute).Return(`{"domain":"some-domain","ip":"some-ip","services":"","registries":[],"provider":"some-provider"}`, nil),
+				mockSSH.EXPECT().RunSSHCommandWithStdin(gomock.Any(), "sudo tee /var/pcfdev/provision-options.json >/dev/null", addresses, []byte("some-private-key"), 7*time.Minute, gomock.Any(), gomock.Any(), gomock.Any()).Do(
+					func(_ context.Context, _ string, _ []ssh.SSHAddress, _ []byte, _ time.Duration, stdin io.Reader, _ io.Writer, _ io.Writer) {
+						Expect(ioutil.ReadAll(stdin)).To(MatchJSON(`{"domain":"some-domain","ip":"some-ip","services":"some-'services","registries":["some-registry"],"provider":"some-provider"}`))
+					},
+				),
+				mockFS.EXPECT().Remove("some-temp-dir"),
+			)
+
+			Expect(archiver.Restore(context.Background(), "some-archive-path")).To(Succeed())
+		})
+
+		Context("when the archive does not exist", func() {
+			It("should return an error", func() {
+				mockFS.EXPECT().Exists("some-archive-path").Return(false, nil)
+
+				Expect(archiver.Restore(context.Background(), "some-archive-path")).To(MatchError("no file found at some-archive-path"))
+			})
+		})
+
+		Context("when checking for the archive fails", func() {
+			It("should return an error", func() {
+				mockFS.EXPECT().Exists("some-archive-path").Return(false, errors.New("some-error"))
+
+				Expect(archiver.Restore(context.Background(), "some-archive-path")).To(MatchError("some-error"))
+			})
+		})
+
+		Context("when decompressing the archive fails", func() {
+			It("should return an error", func() {
+				gomock.InOrder(
+					mockFS.EXPECT().Exists("some-archive-path").Return(true, nil),
+					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
+					mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
+					mockFS.EXPECT().Decompress("some-archive-path", "some-temp-dir").Return(errors.New("some-error")),
+					mockFS.EXPECT().Remove("some-temp-dir"),
+				)
+
+				Expect(archiver.Restore(context.Background(), "some-archive-path")).To(MatchError("some-error"))
+			})
+		})
+
+		Context("when the archive contains malformed provision options", func() {
+			It("should return an error", func() {
+				gomock.InOrder(
+					mockFS.EXPECT().Exists("some-archive-path").Return(true, nil),
+					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
+					mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
+					mockFS.EXPECT().Decompress("some-archive-path", "some-temp-dir"),
+					mockFS.EXPECT().Read(filepath.Join("some-temp-dir", "pcfdev-backup", "provision-options.json")).Return([]byte("some-bad-json"), nil),
+					mockFS.EXPECT().Remove("some-temp-dir"),
+				)
+
+				Expect(archiver.Restore(context.Background(), "some-archive-path")).To(MatchError(ContainSubstring("failed to parse provision-options.json:")))
+			})
+		})
+
+		Context("when the archive is missing data", func() {
+			It("should return an error", func() {
+				gomock.InOrder(
+					mockFS.EXPECT().Exists("some-archive-path").Return(true, nil),
+					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
+					mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
+					mockFS.EXPECT().Decompress("some-archive-path", "some-temp-dir"),
+					mockFS.EXPECT().Read(filepath.Join("some-temp-dir", "pcfdev-backup", "provision-options.json")).Return([]byte("{}"), nil),
+					mockFS.EXPECT().Open(filepath.Join("some-temp-dir", "pcfdev-backup", "ccdb.sql")).Return(nil, errors.New("some-error")),
+					mockFS.EXPECT().Remove("some-temp-dir"),
+				)
+
+				Expect(archiver.Restore(context.Background(), "some-archive-path")).To(MatchError("failed to import ccdb.sql: some-error"))
+			})
+		})
+
+		Context("when importing data into the VM fails", func() {
+			It("should return an error", func() {
+				gomock.InOrder(
+					mockFS.EXPECT().Exists("some-archive-path").Return(true, nil),
+					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
+					mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
+					mockFS.EXPECT().Decompress("some-archive-path", "some-temp-dir"),
+					mockFS.EXPECT().Read(filepath.Join("some-temp-dir", "pcfdev-backup", "provision-options.json")).Return([]byte("{}"), nil),
+					mockFS.EXPECT().Open(filepath.Join("some-temp-dir", "pcfdev-backup", "ccdb.sql")).Return(ccdb, nil),
+					mockSSH.EXPECT().RunSSHCommandWithStdin(gomock.Any(), mysql, addresses, []byte("some-private-key"), 7*time.Minute, ccdb, gomock.Any(), gomock.Any()).Return(errors.New("some-error")),
+					mockFS.EXPECT().Remove("some-temp-dir"),
+				)
+
+				Expect(archiver.Restore(context.Background(), "some-archive-path")).To(MatchError("failed to import ccdb.sql: some-error"))
+			})
+		})
+
+		Context("when writing the provision options fails", func() {
+			It("should return an error", func() {
+				mockFS.EXPECT().Open(gomock.Any()).Return(ccdb, nil).Times(3)
+				mockSSH.EXPECT().RunSSHCommandWithStdin(gomock.Any(), gomock.Any(), addresses, []byte("some-private-key"), 7*time.Minute, ccdb, gomock.Any(), gomock.Any()).Times(3)
+				gomock.InOrder(
+					mockFS.EXPECT().Exists("some-archive-path").Return(true, nil),
+					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
+					mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
+					mockFS.EXPECT().Decompress("some-archive-path", "some-temp-dir"),
+					mockFS.EXPECT().Read(filepath.Join("some-temp-dir", "pcfdev-backup", "provision-options.json")).Return([]byte("{}"), nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/pcfdev/provision-options.json", addresses, []byte("some-private-key"), 7*time.Minute).Return("{}", nil),
+					mockSSH.EXPECT().RunSSHCommandWithStdin(gomock.Any(), "sudo tee /var/pcfdev/provision-options.json >/dev/null", addresses, []byte("some-private-key"), 7*time.Minute, gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("some-error")),
+					mockFS.EXPECT().Remove("some-temp-dir"),
+				)
+
+				Expect(archiver.Restore(context.Background(), "some-archive-path")).To(MatchError("some-error"))
+			})
+		})
+	})
+
+	Describe("#ReadProvisionConfig", func() {
+		It("should return the provision options stored in the archive", func() {
+			gomock.InOrder(
+				mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
+				mockFS.EXPECT().Decompress("some-archive-path", "some-temp-dir"),
+				mockFS.EXPECT().Read(filepath.Join("some-temp-dir", "pcfdev-backup", "provision-options.json")).Return([]byte(`{"domain":"some-domain","ip":"some-ip","services":"some-services","registries":["some-registry"],"provider":"some-provider"}`), nil),
+				mockFS.EXPECT().Remove("some-temp-dir"),
+			)
+
+			Expect(archiver.ReadProvisionConfig("some-archive-path")).To(Equal(&config.ProvisionConfig{
+				Domain:     "some-domain",
+				IP:         "some-ip",
+				Services:   "some-services",
+				Registries: []string{"some-registry"},
+				Provider:   "some-provider",
+			}))
+		})
+
+		Context("when decompressing the archive fails", func() {
+			It("should return an error", func() {
+				gomock.InOrder(
+					mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
+					mockFS.EXPECT().Decompress("some-archive-path", "some-temp-dir").Return(errors.New("some-error")),
+					mockFS.EXPECT().Remove("some-temp-dir"),
+				)
+
+				_, err := archiver.ReadProvisionConfig("some-archive-path")
+				Expect(err).To(MatchError("some-error"))
+			})
+		})
+	})
+})
@@ -1,10 +1,14 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/pivotal-cf/pcfdev-cli/user"
@@ -16,10 +20,17 @@ type Config struct {
 	OVADir                   string
 	OVAPath                  string
 	PartialOVAPath           string
+	EULAAcceptedPath         string
 	VMDir                    string
 	HTTPProxy                string
 	HTTPSProxy               string
 	NoProxy                  string
+	ProxyPAC                 string
+	ProxySaved               bool
+	Provider                 string
+	SubnetPool               string
+	OVASource                string
+	S3Endpoint               string
 	MinMemory                uint64
 	MaxMemory                uint64
 	TotalMemory              uint64
@@ -30,11 +41,51 @@ type Config struct {
 	SpringCloudMaxMemory     uint64
 	DefaultCPUs              func() (int, error)
 	ExpectedMD5              string
+	ExpectedSHA256           string
+	ManifestPublicKey        string
 	InsecurePrivateKey       []byte
 	PrivateKeyPath           string
+	KnownHostsPath           string
+	SSHTimeout               time.Duration
+	ProvisionTimeout         time.Duration
+	ProvisionRunTimeout      time.Duration
+	HealthCheckTimeout       time.Duration
+	DebugLogTimeout          time.Duration
+	APITunnelTimeout         time.Duration
 	Version                  *Version
 }
 
+// File holds the settings that can be given in config.json in the PCF Dev
+// home directory.
+type File struct {
+	SubnetPool string           `json:"subnet_pool"`
+	Proxy      *ProxySettings   `json:"proxy"`
+	Timeouts   *TimeoutSettings `json:"timeouts"`
+	OVASource  string           `json:"ova_source"`
+	S3Endpoint string           `json:"s3_endpoint"`
+}
+
+// TimeoutSettings override how long PCF Dev waits on the VM, as durations
+// such as "10m". The PCFDEV_*_TIMEOUT environment variables take precedence
+// over them.
+type TimeoutSettings struct {
+	SSH          string `json:"ssh"`
+	Provision    string `json:"provision"`
+	ProvisionRun string `json:"provision_run"`
+	HealthCheck  string `json:"health_check"`
+	DebugLog     string `json:"debug_log"`
+	APITunnel    string `json:"api_tunnel"`
+}
+
+// ProxySettings are saved by 'cf dev proxy set', and are used instead of the
+// proxy environment variables of the host.
+type ProxySettings struct {
+	HTTPProxy  string `json:"http_proxy"`
+	HTTPSProxy string `json:"https_proxy"`
+	NoProxy    string `json:"no_proxy"`
+	PAC        string `json:"pac,omitempty"`
+}
+
 type Version struct {
 	BuildVersion    string
 	BuildSHA        string
@@ -48,7 +99,7 @@ type System interface {
 	PhysicalCores() (int, error)
 }
 
-func New(defaultVMName string, expectedMD5 string, insecurePrivateKey []byte, system System, version *Version) (*Config, error) {
+func New(defaultVMName string, expectedMD5 string, expectedSHA256 string, manifestPublicKey string, insecurePrivateKey []byte, system System, version *Version) (*Config, error) {
 	pcfdevHome, err := getPCFDevHome()
 	if err != nil {
 		return nil, err
@@ -61,22 +112,70 @@ func New(defaultVMName string, expectedMD5 string, insecurePrivateKey []byte, sy
 	if err != nil {
 		return nil, err
 	}
+	configPath := filepath.Join(pcfdevHome, "config.json")
+	configFile, err := readConfigFile(configPath)
+	if err != nil {
+		return nil, err
+	}
 	minMemory := uint64(3072)
 	maxMemory := uint64(4096)
 	springCloudMinMemory := uint64(6144)
 	springCloudMaxMemory := uint64(8192)
 
+	proxy := environmentProxySettings()
+	if configFile.Proxy != nil {
+		proxy = configFile.Proxy
+	}
+
+	timeouts := configFile.Timeouts
+	if timeouts == nil {
+		timeouts = &TimeoutSettings{}
+	}
+	sshTimeout, err := getTimeout("PCFDEV_SSH_TIMEOUT", "ssh", timeouts.SSH, configPath, 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	provisionTimeout, err := getTimeout("PCFDEV_PROVISION_TIMEOUT", "provision", timeouts.Provision, configPath, 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	provisionRunTimeout, err := getTimeout("PCFDEV_PROVISION_RUN_TIMEOUT", "provision_run", timeouts.ProvisionRun, configPath, time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	healthCheckTimeout, err := getTimeout("PCFDEV_HEALTH_CHECK_TIMEOUT", "health_check", timeouts.HealthCheck, configPath, 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	debugLogTimeout, err := getTimeout("PCFDEV_DEBUG_LOG_TIMEOUT", "debug_log", timeouts.DebugLog, configPath, 20*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	apiTunnelTimeout, err := getTimeout("PCFDEV_API_TUNNEL_TIMEOUT", "api_tunnel", timeouts.APITunnel, configPath, time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
 		DefaultVMName:            defaultVMName,
 		ExpectedMD5:              expectedMD5,
+		ExpectedSHA256:           expectedSHA256,
+		ManifestPublicKey:        manifestPublicKey,
 		PCFDevHome:               pcfdevHome,
 		OVADir:                   filepath.Join(pcfdevHome, "ova"),
 		VMDir:                    filepath.Join(pcfdevHome, "vms"),
 		OVAPath:                  filepath.Join(pcfdevHome, "ova", defaultVMName+".ova"),
 		PartialOVAPath:           filepath.Join(pcfdevHome, "ova", defaultVMName+".ova.partial"),
-		HTTPProxy:                getHTTPProxy(),
-		HTTPSProxy:               getHTTPSProxy(),
-		NoProxy:                  getNoProxy(),
+		EULAAcceptedPath:         filepath.Join(pcfdevHome, "ova", defaultVMName+".eula-accepted"),
+		HTTPProxy:                proxy.HTTPProxy,
+		HTTPSProxy:               proxy.HTTPSProxy,
+		NoProxy:                  proxy.NoProxy,
+		ProxyPAC:                 proxy.PAC,
+		ProxySaved:               configFile.Proxy != nil,
+		Provider:                 getProvider(),
+		SubnetPool:               configFile.SubnetPool,
+		OVASource:                getSetting("PCFDEV_OVA_SOURCE", configFile.OVASource),
+		S3Endpoint:               getSetting("PCFDEV_S3_ENDPOINT", configFile.S3Endpoint),
 		MinMemory:                minMemory,
 		MaxMemory:                maxMemory,
 		TotalMemory:              totalMemory,
@@ -88,10 +187,107 @@ func New(defaultVMName string, expectedMD5 string, insecurePrivateKey []byte, sy
 		DefaultCPUs:              system.PhysicalCores,
 		InsecurePrivateKey:       insecurePrivateKey,
 		PrivateKeyPath:           filepath.Join(pcfdevHome, "vms", "key.pem"),
+		KnownHostsPath:           filepath.Join(pcfdevHome, "vms", "known_hosts"),
+		SSHTimeout:               sshTimeout,
+		ProvisionTimeout:         provisionTimeout,
+		ProvisionRunTimeout:      provisionRunTimeout,
+		HealthCheckTimeout:       healthCheckTimeout,
+		DebugLogTimeout:          debugLogTimeout,
+		APITunnelTimeout:         apiTunnelTimeout,
 		Version:                  version,
 	}, nil
 }
 
+// SaveProxySettings saves proxy settings to config.json, so that they are
+// used instead of the proxy environment variables from now on.
+func (c *Config) SaveProxySettings(settings *ProxySettings) error {
+	settings = &ProxySettings{
+		HTTPProxy:  stripWhitespace(settings.HTTPProxy),
+		HTTPSProxy: stripWhitespace(settings.HTTPSProxy),
+		NoProxy:    stripWhitespace(settings.NoProxy),
+		PAC:        strings.TrimSpace(settings.PAC),
+	}
+	if err := c.updateConfigFile("proxy", settings); err != nil {
+		return err
+	}
+
+	c.setProxySettings(settings)
+	c.ProxySaved = true
+	return nil
+}
+
+// ExpectedDigest is the digest that the OVA for this version of the plugin must
+// have: its SHA-256, or its MD5 for builds that were not given a SHA-256.
+func (c *Config) ExpectedDigest() string {
+	if c.ExpectedSHA256 != "" {
+		return c.ExpectedSHA256
+	}
+	return c.ExpectedMD5
+}
+
+// ClearProxySettings removes the proxy settings saved by SaveProxySettings,
+// so that the proxy environment variables are used again.
+func (c *Config) ClearProxySettings() error {
+	if err := c.updateConfigFile("proxy", nil); err != nil {
+		return err
+	}
+
+	c.setProxySettings(environmentProxySettings())
+	c.ProxySaved = false
+	return nil
+}
+
+func (c *Config) setProxySettings(settings *ProxySettings) {
+	c.HTTPProxy = settings.HTTPProxy
+	c.HTTPSProxy = settings.HTTPSProxy
+	c.NoProxy = settings.NoProxy
+	c.ProxyPAC = settings.PAC
+}
+
+// updateConfigFile sets a single key in config.json, or removes it when value
+// is nil, leaving every other setting in the file as it was. The file can hold
+// proxy credentials, so only the user may read it, even if it was written
+// before with wider permissions.
+func (c *Config) updateConfigFile(key string, value interface{}) error {
+	path := filepath.Join(c.PCFDevHome, "config.json")
+
+	settings := map[string]json.RawMessage{}
+	contents, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %s", path, err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(contents, &settings); err != nil {
+			return fmt.Errorf("failed to parse %s: %s", path, err)
+		}
+	}
+
+	if value == nil {
+		delete(settings, key)
+	} else {
+		valueJSON, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		settings[key] = valueJSON
+	}
+
+	contents, err = json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.PCFDevHome, 0755); err != nil {
+		return fmt.Errorf("failed to write %s: %s", path, err)
+	}
+	if err := os.Chmod(path, 0600); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to write %s: %s", path, err)
+	}
+	if err := ioutil.WriteFile(path, append(contents, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %s", path, err)
+	}
+	return nil
+}
+
 func getPCFDevHome() (string, error) {
 	if pcfdevHome := os.Getenv("PCFDEV_HOME"); pcfdevHome != "" {
 		return pcfdevHome, nil
@@ -105,6 +301,36 @@ func getPCFDevHome() (string, error) {
 	return filepath.Join(homeDir, ".pcfdev"), nil
 }
 
+func readConfigFile(path string) (*File, error) {
+	contents, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &File{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", path, err)
+	}
+
+	configFile := &File{}
+	if err := json.Unmarshal(contents, configFile); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %s", path, err)
+	}
+
+	if configFile.SubnetPool != "" {
+		if _, pool, err := net.ParseCIDR(configFile.SubnetPool); err != nil || pool.IP.To4() == nil {
+			return nil, fmt.Errorf("invalid subnet_pool '%s' in %s: it must be an IPv4 subnet such as 10.254.0.0/16", configFile.SubnetPool, path)
+		}
+	}
+	return configFile, nil
+}
+
+func environmentProxySettings() *ProxySettings {
+	return &ProxySettings{
+		HTTPProxy:  getHTTPProxy(),
+		HTTPSProxy: getHTTPSProxy(),
+		NoProxy:    getNoProxy(),
+		PAC:        strings.TrimSpace(os.Getenv("PCFDEV_PROXY_PAC")),
+	}
+}
+
 func getHTTPProxy() string {
 	if proxy := os.Getenv("HTTP_PROXY"); proxy != "" {
 		return stripWhitespace(proxy)
@@ -126,6 +352,42 @@ func getNoProxy() string {
 	return stripWhitespace(os.Getenv("no_proxy"))
 }
 
+func getProvider() string {
+	if provider := os.Getenv("PCFDEV_PROVIDER"); provider != "" {
+		return strings.TrimSpace(provider)
+	}
+	return "virtualbox"
+}
+
+// getSetting returns the value of the environment variable, or else the value
+// from config.json.
+func getSetting(envVar string, fileValue string) string {
+	if value := strings.TrimSpace(os.Getenv(envVar)); value != "" {
+		return value
+	}
+	return strings.TrimSpace(fileValue)
+}
+
+// getTimeout returns the duration in the environment variable, or else the one
+// under key in the timeouts of config.json, or else defaultTimeout.
+func getTimeout(envVar string, key string, fileValue string, configPath string, defaultTimeout time.Duration) (time.Duration, error) {
+	if value := strings.TrimSpace(os.Getenv(envVar)); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return 0, fmt.Errorf("invalid %s '%s': it must be a positive duration such as 10m", envVar, value)
+		}
+		return timeout, nil
+	}
+	if fileValue != "" {
+		timeout, err := time.ParseDuration(fileValue)
+		if err != nil || timeout <= 0 {
+			return 0, fmt.Errorf("invalid timeouts.%s '%s' in %s: it must be a positive duration such as 10m", key, fileValue, configPath)
+		}
+		return timeout, nil
+	}
+	return defaultTimeout, nil
+}
+
 func getDefaultMemory(totalMemory, minMemory, maxMemory uint64) uint64 {
 	halfTotal := totalMemory / 2
 	if halfTotal <= minMemory {
@@ -1,12 +1,21 @@
 package config
 
+const (
+	NetworkModeHostOnly   = "hostonly"
+	NetworkModeBridged    = "bridged"
+	NetworkModeNATNetwork = "natnetwork"
+)
+
 type VMConfig struct {
-	Name     string
-	OVAPath  string
-	Domain   string
-	IP       string
-	Memory   uint64
-	CPUs     int
-	SSHPort  string
-	Provider string
+	Name        string
+	OVAPath     string
+	Domain      string
+	IP          string
+	GuestIP     string
+	Memory      uint64
+	CPUs        int
+	SSHPort     string
+	SSHIP       string
+	Provider    string
+	NetworkMode string
 }
@@ -1,6 +1,7 @@
 package debug
 
 import (
+	"context"
 	"io"
 	"path/filepath"
 	"strings"
@@ -20,18 +21,25 @@ type FS interface {
 
 //go:generate mockgen -package mocks -destination mocks/ssh.go github.com/pivotal-cf/pcfdev-cli/debug SSH
 type SSH interface {
-	GetSSHOutput(command string, addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration) (combinedOutput string, err error)
+	GetSSHOutput(ctx context.Context, command string, addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration) (combinedOutput string, err error)
 }
 
-//go:generate mockgen -package mocks -destination mocks/driver.go github.com/pivotal-cf/pcfdev-cli/debug Driver
-type Driver interface {
-	VBoxManage(arg ...string) (output []byte, err error)
+// HostLog is the output of a command run on the host to show how the provider
+// of the VM sees the VM and its network.
+type HostLog struct {
+	Filename string
+	Output   []byte
+}
+
+//go:generate mockgen -package mocks -destination mocks/host_logger.go github.com/pivotal-cf/pcfdev-cli/debug HostLogger
+type HostLogger interface {
+	HostLogs(vmConfig *config.VMConfig) (logs []*HostLog, err error)
 }
 
 type LogFetcher struct {
-	FS     FS
-	SSH    SSH
-	Driver Driver
+	FS         FS
+	SSH        SSH
+	HostLogger HostLogger
 
 	VMConfig *config.VMConfig
 	Config   *config.Config
@@ -39,70 +47,40 @@ type LogFetcher struct {
 
 type logFile struct {
 	command   []string
-	reciever  string
 	filename  string
 	sensitive bool
 }
 
-const (
-	ReceiverGuest = "Guest"
-	ReceiverHost  = "Host"
-)
-
-func (l *LogFetcher) FetchLogs() error {
+func (l *LogFetcher) FetchLogs(ctx context.Context) error {
 	logFiles := []logFile{
 		logFile{
 			command:   []string{"sudo", "cat", "/var/pcfdev/provision.log"},
 			filename:  "provision.log",
-			reciever:  ReceiverGuest,
 			sensitive: true,
 		},
 		logFile{
 			command:   []string{"sudo", "cat", "/var/pcfdev/reset.log"},
 			filename:  "reset.log",
-			reciever:  ReceiverGuest,
 			sensitive: true,
 		},
 		logFile{
 			command:   []string{"sudo", "cat", "/var/log/kern.log"},
 			filename:  "kern.log",
-			reciever:  ReceiverGuest,
 			sensitive: true,
 		},
 		logFile{
 			command:   []string{"sudo", "cat", "/var/log/dmesg"},
 			filename:  "dmesg",
-			reciever:  ReceiverGuest,
 			sensitive: true,
 		},
 		logFile{
 			command:   []string{"ifconfig"},
 			filename:  "ifconfig",
-			reciever:  ReceiverGuest,
 			sensitive: false,
 		},
 		logFile{
 			command:   []string{"route", "-n"},
 			filename:  "routes",
-			reciever:  ReceiverGuest,
-			sensitive: false,
-		},
-		logFile{
-			command:   []string{"list", "vms", "--long"},
-			filename:  "vm-list",
-			reciever:  ReceiverHost,
-			sensitive: false,
-		},
-		logFile{
-			command:   []string{"showvminfo", l.VMConfig.Name},
-			filename:  "vm-info",
-			reciever:  ReceiverHost,
-			sensitive: false,
-		},
-		logFile{
-			command:   []string{"list", "hostonlyifs", "--long"},
-			filename:  "vm-hostonlyifs",
-			reciever:  ReceiverHost,
 			sensitive: false,
 		},
 	}
@@ -119,58 +97,45 @@ func (l *LogFetcher) FetchLogs() error {
 		return err
 	}
 
-	addresses := []ssh.SSHAddress{
-		{
-			IP:   "127.0.0.1",
-			Port: l.VMConfig.SSHPort,
-		},
-		{
-			IP:   l.VMConfig.IP,
-			Port: "22",
-		},
-	}
+	addresses := ssh.SSHAddresses(l.VMConfig)
 
 	for _, logFile := range logFiles {
-		switch logFile.reciever {
-		case ReceiverGuest:
-			output, err := l.SSH.GetSSHOutput(strings.Join(logFile.command, " "), addresses, privateKeyBytes, 20*time.Second)
-			if err != nil {
-				return err
-			}
-
-			if logFile.sensitive {
-				output = sensitiveInformationScrubber.Scrub(output)
-			}
-
-			if err := l.FS.Write(
-				filepath.Join(dir, logFile.filename),
-				strings.NewReader(output),
-				false,
-			); err != nil {
-				return err
-			}
-		case ReceiverHost:
-			output, err := l.Driver.VBoxManage(logFile.command...)
-			if err != nil {
-				return err
-			}
-
-			scrubbedOutput := string(output)
-			if logFile.sensitive {
-				scrubbedOutput = sensitiveInformationScrubber.Scrub(scrubbedOutput)
-			}
-
-			if err := l.FS.Write(
-				filepath.Join(dir, logFile.filename),
-				strings.NewReader(scrubbedOutput),
-				false,
-			); err != nil {
-				return err
-			}
+		output, err := l.SSH.GetSSHOutput(ctx, strings.Join(logFile.command, " "), addresses, privateKeyBytes, l.Config.DebugLogTimeout)
+		if err != nil {
+			return err
+		}
+
+		if logFile.sensitive {
+			output = sensitiveInformationScrubber.Scrub(output)
+		}
+
+		if err := l.FS.Write(
+			filepath.Join(dir, logFile.filename),
+			strings.NewReader(output),
+			false,
+		); err != nil {
+			return err
+		}
+	}
+
+	hostLogs, err := l.HostLogger.HostLogs(l.VMConfig)
+	if err != nil {
+		return err
+	}
+
+	logFileNames := l.getLogFileNames(logFiles, dir)
+	for _, hostLog := range hostLogs {
+		if err := l.FS.Write(
+			filepath.Join(dir, hostLog.Filename),
+			strings.NewReader(string(hostLog.Output)),
+			false,
+		); err != nil {
+			return err
 		}
+		logFileNames = append(logFileNames, filepath.Join(dir, hostLog.Filename))
 	}
 
-	if err := l.FS.Compress("pcfdev-debug", ".", l.getLogFileNames(logFiles, dir)); err != nil {
+	if err := l.FS.Compress("pcfdev-debug", ".", logFileNames); err != nil {
 		return err
 	}
 
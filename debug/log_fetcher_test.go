@@ -1,6 +1,7 @@
 package debug_test
 
 import (
+	"context"
 	"errors"
 	"path/filepath"
 	"strings"
@@ -18,22 +19,22 @@ import (
 
 var _ = Describe("LogFetcher", func() {
 	var (
-		mockCtrl   *gomock.Controller
-		mockSSH    *mocks.MockSSH
-		mockFS     *mocks.MockFS
-		mockDriver *mocks.MockDriver
-		logFetcher *debug.LogFetcher
+		mockCtrl       *gomock.Controller
+		mockSSH        *mocks.MockSSH
+		mockFS         *mocks.MockFS
+		mockHostLogger *mocks.MockHostLogger
+		logFetcher     *debug.LogFetcher
 	)
 
 	BeforeEach(func() {
 		mockCtrl = gomock.NewController(GinkgoT())
 		mockSSH = mocks.NewMockSSH(mockCtrl)
 		mockFS = mocks.NewMockFS(mockCtrl)
-		mockDriver = mocks.NewMockDriver(mockCtrl)
+		mockHostLogger = mocks.NewMockHostLogger(mockCtrl)
 		logFetcher = &debug.LogFetcher{
-			SSH:    mockSSH,
-			FS:     mockFS,
-			Driver: mockDriver,
+			SSH:        mockSSH,
+			FS:         mockFS,
+			HostLogger: mockHostLogger,
 
 			VMConfig: &config.VMConfig{
 				IP:      "some-ip",
@@ -42,7 +43,8 @@ var _ = Describe("LogFetcher", func() {
 			},
 
 			Config: &config.Config{
-				PrivateKeyPath: "some-private-key-path",
+				DebugLogTimeout: 25 * time.Second,
+				PrivateKeyPath:  "some-private-key-path",
 			},
 		}
 	})
@@ -67,24 +69,26 @@ var _ = Describe("LogFetcher", func() {
 			gomock.InOrder(
 				mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
 				mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
-				mockSSH.EXPECT().GetSSHOutput("sudo cat /var/pcfdev/provision.log", addresses, []byte("some-private-key"), 20*time.Second).Return("some-pcfdev-provision-log", nil),
+				mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/pcfdev/provision.log", addresses, []byte("some-private-key"), 25*time.Second).Return("some-pcfdev-provision-log", nil),
 				mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "provision.log"), strings.NewReader("some-pcfdev-provision-log"), false),
-				mockSSH.EXPECT().GetSSHOutput("sudo cat /var/pcfdev/reset.log", addresses, []byte("some-private-key"), 20*time.Second).Return("some-pcfdev-reset-log", nil),
+				mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/pcfdev/reset.log", addresses, []byte("some-private-key"), 25*time.Second).Return("some-pcfdev-reset-log", nil),
 				mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "reset.log"), strings.NewReader("some-pcfdev-reset-log"), false),
-				mockSSH.EXPECT().GetSSHOutput("sudo cat /var/log/kern.log", addresses, []byte("some-private-key"), 20*time.Second).Return("some-kern-log", nil),
+				mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/log/kern.log", addresses, []byte("some-private-key"), 25*time.Second).Return("some-kern-log", nil),
 				mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "kern.log"), strings.NewReader("some-kern-log"), false),
-				mockSSH.EXPECT().GetSSHOutput("sudo cat /var/log/dmesg", addresses, []byte("some-private-key"), 20*time.Second).Return("some-dmesg-log", nil),
+				mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/log/dmesg", addresses, []byte("some-private-key"), 25*time.Second).Return("some-dmesg-log", nil),
 				mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "dmesg"), strings.NewReader("some-dmesg-log"), false),
-				mockSSH.EXPECT().GetSSHOutput("ifconfig", addresses, []byte("some-private-key"), 20*time.Second).Return("some-ifconfig-log", nil),
+				mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "ifconfig", addresses, []byte("some-private-key"), 25*time.Second).Return("some-ifconfig-log", nil),
 				mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "ifconfig"), strings.NewReader("some-ifconfig-log"), false),
-				mockSSH.EXPECT().GetSSHOutput("route -n", addresses, []byte("some-private-key"), 20*time.Second).Return("some-routes-log", nil),
+				mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "route -n", addresses, []byte("some-private-key"), 25*time.Second).Return("some-routes-log", nil),
 				mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "routes"), strings.NewReader("some-routes-log"), false),
 
-				mockDriver.EXPECT().VBoxManage("list", "vms", "--long").Return([]byte("some-vm-list"), nil),
+				mockHostLogger.EXPECT().HostLogs(logFetcher.VMConfig).Return([]*debug.HostLog{
+					{Filename: "vm-list", Output: []byte("some-vm-list")},
+					{Filename: "vm-info", Output: []byte("some-vm-info")},
+					{Filename: "vm-hostonlyifs", Output: []byte("some-vm-hostonlyifs")},
+				}, nil),
 				mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "vm-list"), strings.NewReader("some-vm-list"), false),
-				mockDriver.EXPECT().VBoxManage("showvminfo", "some-vm-name").Return([]byte("some-vm-info"), nil),
 				mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "vm-info"), strings.NewReader("some-vm-info"), false),
-				mockDriver.EXPECT().VBoxManage("list", "hostonlyifs", "--long").Return([]byte("some-vm-hostonlyifs"), nil),
 				mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "vm-hostonlyifs"), strings.NewReader("some-vm-hostonlyifs"), false),
 
 				mockFS.EXPECT().Compress(
@@ -103,7 +107,7 @@ var _ = Describe("LogFetcher", func() {
 					}),
 			)
 
-			Expect(logFetcher.FetchLogs()).To(Succeed())
+			Expect(logFetcher.FetchLogs(context.Background())).To(Succeed())
 		})
 
 		Context("when there is sensitive information", func() {
@@ -121,24 +125,26 @@ var _ = Describe("LogFetcher", func() {
 				gomock.InOrder(
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
 					mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
-					mockSSH.EXPECT().GetSSHOutput("sudo cat /var/pcfdev/provision.log", addresses, []byte("some-private-key"), 20*time.Second).Return("http://some-private-domain.com", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/pcfdev/provision.log", addresses, []byte("some-private-key"), 25*time.Second).Return("http://some-private-domain.com", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "provision.log"), strings.NewReader("<redacted uri>"), false),
-					mockSSH.EXPECT().GetSSHOutput("sudo cat /var/pcfdev/reset.log", addresses, []byte("some-private-key"), 20*time.Second).Return("some-pcfdev-reset-log", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/pcfdev/reset.log", addresses, []byte("some-private-key"), 25*time.Second).Return("some-pcfdev-reset-log", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "reset.log"), strings.NewReader("some-pcfdev-reset-log"), false),
-					mockSSH.EXPECT().GetSSHOutput("sudo cat /var/log/kern.log", addresses, []byte("some-private-key"), 20*time.Second).Return("some-kern-log", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/log/kern.log", addresses, []byte("some-private-key"), 25*time.Second).Return("some-kern-log", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "kern.log"), strings.NewReader("some-kern-log"), false),
-					mockSSH.EXPECT().GetSSHOutput("sudo cat /var/log/dmesg", addresses, []byte("some-private-key"), 20*time.Second).Return("some-dmesg-log", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/log/dmesg", addresses, []byte("some-private-key"), 25*time.Second).Return("some-dmesg-log", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "dmesg"), strings.NewReader("some-dmesg-log"), false),
-					mockSSH.EXPECT().GetSSHOutput("ifconfig", addresses, []byte("some-private-key"), 20*time.Second).Return("some-ifconfig-log", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "ifconfig", addresses, []byte("some-private-key"), 25*time.Second).Return("some-ifconfig-log", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "ifconfig"), strings.NewReader("some-ifconfig-log"), false),
-					mockSSH.EXPECT().GetSSHOutput("route -n", addresses, []byte("some-private-key"), 20*time.Second).Return("http://some-private-domain.com", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "route -n", addresses, []byte("some-private-key"), 25*time.Second).Return("http://some-private-domain.com", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "routes"), strings.NewReader("http://some-private-domain.com"), false),
 
-					mockDriver.EXPECT().VBoxManage("list", "vms", "--long").Return([]byte("http://some-private-domain.com"), nil),
+					mockHostLogger.EXPECT().HostLogs(logFetcher.VMConfig).Return([]*debug.HostLog{
+						{Filename: "vm-list", Output: []byte("http://some-private-domain.com")},
+						{Filename: "vm-info", Output: []byte("some-vm-info")},
+						{Filename: "vm-hostonlyifs", Output: []byte("http://some-private-domain.com")},
+					}, nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "vm-list"), strings.NewReader("http://some-private-domain.com"), false),
-					mockDriver.EXPECT().VBoxManage("showvminfo", "some-vm-name").Return([]byte("some-vm-info"), nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "vm-info"), strings.NewReader("some-vm-info"), false),
-					mockDriver.EXPECT().VBoxManage("list", "hostonlyifs", "--long").Return([]byte("http://some-private-domain.com"), nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "vm-hostonlyifs"), strings.NewReader("http://some-private-domain.com"), false),
 
 					mockFS.EXPECT().Compress(
@@ -157,7 +163,7 @@ var _ = Describe("LogFetcher", func() {
 						}),
 				)
 
-				Expect(logFetcher.FetchLogs()).To(Succeed())
+				Expect(logFetcher.FetchLogs(context.Background())).To(Succeed())
 			})
 		})
 
@@ -168,7 +174,7 @@ var _ = Describe("LogFetcher", func() {
 					mockFS.EXPECT().TempDir().Return("", errors.New("some-error")),
 				)
 
-				Expect(logFetcher.FetchLogs()).To(MatchError("some-error"))
+				Expect(logFetcher.FetchLogs(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -187,10 +193,10 @@ var _ = Describe("LogFetcher", func() {
 				gomock.InOrder(
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
 					mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
-					mockSSH.EXPECT().GetSSHOutput("sudo cat /var/pcfdev/provision.log", addresses, []byte("some-private-key"), 20*time.Second).Return("", errors.New("some-error")),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/pcfdev/provision.log", addresses, []byte("some-private-key"), 25*time.Second).Return("", errors.New("some-error")),
 				)
 
-				Expect(logFetcher.FetchLogs()).To(MatchError("some-error"))
+				Expect(logFetcher.FetchLogs(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -209,15 +215,15 @@ var _ = Describe("LogFetcher", func() {
 				gomock.InOrder(
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
 					mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
-					mockSSH.EXPECT().GetSSHOutput("sudo cat /var/pcfdev/provision.log", addresses, []byte("some-private-key"), 20*time.Second).Return("some-pcfdev-provision-log", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/pcfdev/provision.log", addresses, []byte("some-private-key"), 25*time.Second).Return("some-pcfdev-provision-log", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "provision.log"), strings.NewReader("some-pcfdev-provision-log"), false).Return(errors.New("some-error")),
 				)
 
-				Expect(logFetcher.FetchLogs()).To(MatchError("some-error"))
+				Expect(logFetcher.FetchLogs(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
-		Context("when there is an error getting output of logging shell command", func() {
+		Context("when there is an error getting the host logs", func() {
 			It("should return the error", func() {
 				addresses := []ssh.SSHAddress{
 					{
@@ -232,27 +238,27 @@ var _ = Describe("LogFetcher", func() {
 				gomock.InOrder(
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
 					mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
-					mockSSH.EXPECT().GetSSHOutput("sudo cat /var/pcfdev/provision.log", addresses, []byte("some-private-key"), 20*time.Second).Return("some-pcfdev-provision-log", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/pcfdev/provision.log", addresses, []byte("some-private-key"), 25*time.Second).Return("some-pcfdev-provision-log", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "provision.log"), strings.NewReader("some-pcfdev-provision-log"), false),
-					mockSSH.EXPECT().GetSSHOutput("sudo cat /var/pcfdev/reset.log", addresses, []byte("some-private-key"), 20*time.Second).Return("some-pcfdev-reset-log", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/pcfdev/reset.log", addresses, []byte("some-private-key"), 25*time.Second).Return("some-pcfdev-reset-log", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "reset.log"), strings.NewReader("some-pcfdev-reset-log"), false),
-					mockSSH.EXPECT().GetSSHOutput("sudo cat /var/log/kern.log", addresses, []byte("some-private-key"), 20*time.Second).Return("some-kern-log", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/log/kern.log", addresses, []byte("some-private-key"), 25*time.Second).Return("some-kern-log", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "kern.log"), strings.NewReader("some-kern-log"), false),
-					mockSSH.EXPECT().GetSSHOutput("sudo cat /var/log/dmesg", addresses, []byte("some-private-key"), 20*time.Second).Return("some-dmesg-log", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/log/dmesg", addresses, []byte("some-private-key"), 25*time.Second).Return("some-dmesg-log", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "dmesg"), strings.NewReader("some-dmesg-log"), false),
-					mockSSH.EXPECT().GetSSHOutput("ifconfig", addresses, []byte("some-private-key"), 20*time.Second).Return("some-ifconfig-log", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "ifconfig", addresses, []byte("some-private-key"), 25*time.Second).Return("some-ifconfig-log", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "ifconfig"), strings.NewReader("some-ifconfig-log"), false),
-					mockSSH.EXPECT().GetSSHOutput("route -n", addresses, []byte("some-private-key"), 20*time.Second).Return("some-routes-log", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "route -n", addresses, []byte("some-private-key"), 25*time.Second).Return("some-routes-log", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "routes"), strings.NewReader("some-routes-log"), false),
 
-					mockDriver.EXPECT().VBoxManage("list", "vms", "--long").Return(nil, errors.New("some-error")),
+					mockHostLogger.EXPECT().HostLogs(logFetcher.VMConfig).Return(nil, errors.New("some-error")),
 				)
 
-				Expect(logFetcher.FetchLogs()).To(MatchError("some-error"))
+				Expect(logFetcher.FetchLogs(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
-		Context("when there is an error writing the temporary file for the logging shell command", func() {
+		Context("when there is an error writing the temporary file for a host log", func() {
 			It("should return the error", func() {
 				addresses := []ssh.SSHAddress{
 					{
@@ -267,24 +273,26 @@ var _ = Describe("LogFetcher", func() {
 				gomock.InOrder(
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
 					mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
-					mockSSH.EXPECT().GetSSHOutput("sudo cat /var/pcfdev/provision.log", addresses, []byte("some-private-key"), 20*time.Second).Return("some-pcfdev-provision-log", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/pcfdev/provision.log", addresses, []byte("some-private-key"), 25*time.Second).Return("some-pcfdev-provision-log", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "provision.log"), strings.NewReader("some-pcfdev-provision-log"), false),
-					mockSSH.EXPECT().GetSSHOutput("sudo cat /var/pcfdev/reset.log", addresses, []byte("some-private-key"), 20*time.Second).Return("some-pcfdev-reset-log", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/pcfdev/reset.log", addresses, []byte("some-private-key"), 25*time.Second).Return("some-pcfdev-reset-log", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "reset.log"), strings.NewReader("some-pcfdev-reset-log"), false),
-					mockSSH.EXPECT().GetSSHOutput("sudo cat /var/log/kern.log", addresses, []byte("some-private-key"), 20*time.Second).Return("some-kern-log", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/log/kern.log", addresses, []byte("some-private-key"), 25*time.Second).Return("some-kern-log", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "kern.log"), strings.NewReader("some-kern-log"), false),
-					mockSSH.EXPECT().GetSSHOutput("sudo cat /var/log/dmesg", addresses, []byte("some-private-key"), 20*time.Second).Return("some-dmesg-log", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/log/dmesg", addresses, []byte("some-private-key"), 25*time.Second).Return("some-dmesg-log", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "dmesg"), strings.NewReader("some-dmesg-log"), false),
-					mockSSH.EXPECT().GetSSHOutput("ifconfig", addresses, []byte("some-private-key"), 20*time.Second).Return("some-ifconfig-log", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "ifconfig", addresses, []byte("some-private-key"), 25*time.Second).Return("some-ifconfig-log", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "ifconfig"), strings.NewReader("some-ifconfig-log"), false),
-					mockSSH.EXPECT().GetSSHOutput("route -n", addresses, []byte("some-private-key"), 20*time.Second).Return("some-routes-log", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "route -n", addresses, []byte("some-private-key"), 25*time.Second).Return("some-routes-log", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "routes"), strings.NewReader("some-routes-log"), false),
 
-					mockDriver.EXPECT().VBoxManage("list", "vms", "--long").Return([]byte("some-vm-list"), nil),
+					mockHostLogger.EXPECT().HostLogs(logFetcher.VMConfig).Return([]*debug.HostLog{
+						{Filename: "vm-list", Output: []byte("some-vm-list")},
+					}, nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "vm-list"), strings.NewReader("some-vm-list"), false).Return(errors.New("some-error")),
 				)
 
-				Expect(logFetcher.FetchLogs()).To(MatchError("some-error"))
+				Expect(logFetcher.FetchLogs(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -303,24 +311,26 @@ var _ = Describe("LogFetcher", func() {
 				gomock.InOrder(
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
 					mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
-					mockSSH.EXPECT().GetSSHOutput("sudo cat /var/pcfdev/provision.log", addresses, []byte("some-private-key"), 20*time.Second).Return("some-pcfdev-provision-log", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/pcfdev/provision.log", addresses, []byte("some-private-key"), 25*time.Second).Return("some-pcfdev-provision-log", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "provision.log"), strings.NewReader("some-pcfdev-provision-log"), false),
-					mockSSH.EXPECT().GetSSHOutput("sudo cat /var/pcfdev/reset.log", addresses, []byte("some-private-key"), 20*time.Second).Return("some-pcfdev-reset-log", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/pcfdev/reset.log", addresses, []byte("some-private-key"), 25*time.Second).Return("some-pcfdev-reset-log", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "reset.log"), strings.NewReader("some-pcfdev-reset-log"), false),
-					mockSSH.EXPECT().GetSSHOutput("sudo cat /var/log/kern.log", addresses, []byte("some-private-key"), 20*time.Second).Return("some-kern-log", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/log/kern.log", addresses, []byte("some-private-key"), 25*time.Second).Return("some-kern-log", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "kern.log"), strings.NewReader("some-kern-log"), false),
-					mockSSH.EXPECT().GetSSHOutput("sudo cat /var/log/dmesg", addresses, []byte("some-private-key"), 20*time.Second).Return("some-dmesg-log", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo cat /var/log/dmesg", addresses, []byte("some-private-key"), 25*time.Second).Return("some-dmesg-log", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "dmesg"), strings.NewReader("some-dmesg-log"), false),
-					mockSSH.EXPECT().GetSSHOutput("ifconfig", addresses, []byte("some-private-key"), 20*time.Second).Return("some-ifconfig-log", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "ifconfig", addresses, []byte("some-private-key"), 25*time.Second).Return("some-ifconfig-log", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "ifconfig"), strings.NewReader("some-ifconfig-log"), false),
-					mockSSH.EXPECT().GetSSHOutput("route -n", addresses, []byte("some-private-key"), 20*time.Second).Return("some-routes-log", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "route -n", addresses, []byte("some-private-key"), 25*time.Second).Return("some-routes-log", nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "routes"), strings.NewReader("some-routes-log"), false),
 
-					mockDriver.EXPECT().VBoxManage("list", "vms", "--long").Return([]byte("some-vm-list"), nil),
+					mockHostLogger.EXPECT().HostLogs(logFetcher.VMConfig).Return([]*debug.HostLog{
+						{Filename: "vm-list", Output: []byte("some-vm-list")},
+						{Filename: "vm-info", Output: []byte("some-vm-info")},
+						{Filename: "vm-hostonlyifs", Output: []byte("some-vm-hostonlyifs")},
+					}, nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "vm-list"), strings.NewReader("some-vm-list"), false),
-					mockDriver.EXPECT().VBoxManage("showvminfo", "some-vm-name").Return([]byte("some-vm-info"), nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "vm-info"), strings.NewReader("some-vm-info"), false),
-					mockDriver.EXPECT().VBoxManage("list", "hostonlyifs", "--long").Return([]byte("some-vm-hostonlyifs"), nil),
 					mockFS.EXPECT().Write(filepath.Join("some-temp-dir", "vm-hostonlyifs"), strings.NewReader("some-vm-hostonlyifs"), false),
 
 					mockFS.EXPECT().Compress(
@@ -339,7 +349,7 @@ var _ = Describe("LogFetcher", func() {
 						}).Return(errors.New("some-error")),
 				)
 
-				Expect(logFetcher.FetchLogs()).To(MatchError("some-error"))
+				Expect(logFetcher.FetchLogs(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -347,7 +357,7 @@ var _ = Describe("LogFetcher", func() {
 			It("should return the error", func() {
 				mockFS.EXPECT().Read("some-private-key-path").Return(nil, errors.New("some-error"))
 
-				Expect(logFetcher.FetchLogs()).To(MatchError("some-error"))
+				Expect(logFetcher.FetchLogs(context.Background())).To(MatchError("some-error"))
 			})
 		})
 	})
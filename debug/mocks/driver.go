@@ -1,44 +0,0 @@
-// Automatically generated by MockGen. DO NOT EDIT!
-// Source: github.com/pivotal-cf/pcfdev-cli/debug (interfaces: Driver)
-
-package mocks
-
-import (
-	gomock "github.com/golang/mock/gomock"
-)
-
-// Mock of Driver interface
-type MockDriver struct {
-	ctrl     *gomock.Controller
-	recorder *_MockDriverRecorder
-}
-
-// Recorder for MockDriver (not exported)
-type _MockDriverRecorder struct {
-	mock *MockDriver
-}
-
-func NewMockDriver(ctrl *gomock.Controller) *MockDriver {
-	mock := &MockDriver{ctrl: ctrl}
-	mock.recorder = &_MockDriverRecorder{mock}
-	return mock
-}
-
-func (_m *MockDriver) EXPECT() *_MockDriverRecorder {
-	return _m.recorder
-}
-
-func (_m *MockDriver) VBoxManage(_param0 ...string) ([]byte, error) {
-	_s := []interface{}{}
-	for _, _x := range _param0 {
-		_s = append(_s, _x)
-	}
-	ret := _m.ctrl.Call(_m, "VBoxManage", _s...)
-	ret0, _ := ret[0].([]byte)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-func (_mr *_MockDriverRecorder) VBoxManage(arg0 ...interface{}) *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "VBoxManage", arg0...)
-}
@@ -0,0 +1,194 @@
+package dns
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/pivotal-cf/pcfdev-cli/helpers"
+)
+
+// DefaultAddress is where the responder listens unless told otherwise. It is
+// on an unprivileged port, so that it can be run without root.
+const DefaultAddress = "127.0.0.1:5354"
+
+const (
+	headerLength = 12
+	answerTTL    = 60
+
+	typeA   = 1
+	typeANY = 255
+	classIN = 1
+
+	flagResponse           = 1 << 15
+	flagAuthoritative      = 1 << 10
+	flagRecursionDesired   = 1 << 8
+	opcodeMask             = 0xf << 11
+	rcodeFormatError       = 1
+	rcodeNotImplemented    = 4
+	rcodeRefused           = 5
+	maxMessageLength       = 512
+	compressedQuestionName = 0xc000 | headerLength
+)
+
+// Server runs a Responder on a UDP socket.
+type Server struct{}
+
+// ListenAndServe answers queries on address until ctx is done.
+func (s *Server) ListenAndServe(ctx context.Context, address string, ip string, domains []string) error {
+	responder, err := NewResponder(ip, domains)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.ListenPacket("udp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen for DNS queries on %s: %s", address, err)
+	}
+	defer conn.Close()
+
+	stop := helpers.CloseWhenDone(ctx, conn)
+	defer stop()
+	if err := responder.Serve(conn); err != nil && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+// Responder answers A queries for each domain, and for every name under it,
+// with the address of the PCF Dev VM. Queries for other names are refused,
+// so that the responder can only be used as a resolver for its own domains.
+type Responder struct {
+	IP      net.IP
+	Domains []string
+}
+
+func NewResponder(ip string, domains []string) (*Responder, error) {
+	parsedIP := net.ParseIP(ip).To4()
+	if parsedIP == nil {
+		return nil, fmt.Errorf("%s is not a valid IPv4 address", ip)
+	}
+	if len(domains) == 0 {
+		return nil, errors.New("at least one domain must be given")
+	}
+
+	responder := &Responder{IP: parsedIP}
+	for _, domain := range domains {
+		responder.Domains = append(responder.Domains, canonicalName(domain))
+	}
+	return responder, nil
+}
+
+// Serve answers queries read from conn until reading from it fails. Queries
+// that cannot be parsed are dropped.
+func (r *Responder) Serve(conn net.PacketConn) error {
+	buffer := make([]byte, maxMessageLength)
+	for {
+		n, addr, err := conn.ReadFrom(buffer)
+		if err != nil {
+			return err
+		}
+
+		response, err := r.Answer(buffer[:n])
+		if err != nil {
+			continue
+		}
+		conn.WriteTo(response, addr)
+	}
+}
+
+// Answer returns the response to a single DNS query message.
+func (r *Responder) Answer(query []byte) (response []byte, err error) {
+	if len(query) < headerLength {
+		return nil, errors.New("DNS message is shorter than its header")
+	}
+
+	id := binary.BigEndian.Uint16(query[0:2])
+	flags := binary.BigEndian.Uint16(query[2:4])
+	questionCount := binary.BigEndian.Uint16(query[4:6])
+	if flags&flagResponse != 0 {
+		return nil, errors.New("DNS message is not a query")
+	}
+
+	responseFlags := uint16(flagResponse|flagAuthoritative) | flags&(opcodeMask|flagRecursionDesired)
+	if flags&opcodeMask != 0 {
+		return header(id, responseFlags|rcodeNotImplemented, 0, 0), nil
+	}
+	if questionCount != 1 {
+		return header(id, responseFlags|rcodeFormatError, 0, 0), nil
+	}
+
+	name, questionEnd, err := parseQuestionName(query)
+	if err != nil || questionEnd+4 > len(query) {
+		return header(id, responseFlags|rcodeFormatError, 0, 0), nil
+	}
+	questionType := binary.BigEndian.Uint16(query[questionEnd : questionEnd+2])
+	questionClass := binary.BigEndian.Uint16(query[questionEnd+2 : questionEnd+4])
+	question := query[headerLength : questionEnd+4]
+
+	if !r.isAuthoritativeFor(name) {
+		return append(header(id, responseFlags|rcodeRefused, 1, 0), question...), nil
+	}
+
+	if questionClass != classIN || (questionType != typeA && questionType != typeANY) {
+		return append(header(id, responseFlags, 1, 0), question...), nil
+	}
+
+	response = append(header(id, responseFlags, 1, 1), question...)
+	answer := make([]byte, 16)
+	binary.BigEndian.PutUint16(answer[0:2], compressedQuestionName)
+	binary.BigEndian.PutUint16(answer[2:4], typeA)
+	binary.BigEndian.PutUint16(answer[4:6], classIN)
+	binary.BigEndian.PutUint32(answer[6:10], answerTTL)
+	binary.BigEndian.PutUint16(answer[10:12], net.IPv4len)
+	copy(answer[12:16], r.IP)
+	return append(response, answer...), nil
+}
+
+func (r *Responder) isAuthoritativeFor(name string) bool {
+	for _, domain := range r.Domains {
+		if name == domain || strings.HasSuffix(name, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func header(id uint16, flags uint16, questionCount uint16, answerCount uint16) []byte {
+	message := make([]byte, headerLength)
+	binary.BigEndian.PutUint16(message[0:2], id)
+	binary.BigEndian.PutUint16(message[2:4], flags)
+	binary.BigEndian.PutUint16(message[4:6], questionCount)
+	binary.BigEndian.PutUint16(message[6:8], answerCount)
+	return message
+}
+
+// parseQuestionName reads the name of the first question, which directly
+// follows the header. Queries never compress the name of their only question.
+func parseQuestionName(message []byte) (name string, end int, err error) {
+	labels := []string{}
+	offset := headerLength
+	for {
+		if offset >= len(message) {
+			return "", 0, errors.New("DNS question name is truncated")
+		}
+		length := int(message[offset])
+		offset++
+		if length == 0 {
+			break
+		}
+		if length > 63 || offset+length > len(message) {
+			return "", 0, errors.New("DNS question name is malformed")
+		}
+		labels = append(labels, string(message[offset:offset+length]))
+		offset += length
+	}
+	return canonicalName(strings.Join(labels, ".")), offset, nil
+}
+
+func canonicalName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
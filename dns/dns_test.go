@@ -0,0 +1,163 @@
+package dns_test
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/dns"
+)
+
+var _ = Describe("Responder", func() {
+	var responder *dns.Responder
+
+	BeforeEach(func() {
+		var err error
+		responder, err = dns.NewResponder("192.168.11.11", []string{"local.pcfdev.io", "some-custom.domain."})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	query := func(name string, questionType uint16) []byte {
+		message := []byte{0x12, 0x34, 0x01, 0x00, 0, 1, 0, 0, 0, 0, 0, 0}
+		for _, label := range strings.Split(name, ".") {
+			message = append(message, byte(len(label)))
+			message = append(message, label...)
+		}
+		message = append(message, 0, 0, 0, 0, 1)
+		binary.BigEndian.PutUint16(message[len(message)-4:], questionType)
+		return message
+	}
+
+	rcode := func(response []byte) byte {
+		return response[3] & 0xf
+	}
+
+	answerCount := func(response []byte) uint16 {
+		return binary.BigEndian.Uint16(response[6:8])
+	}
+
+	Describe("#Answer", func() {
+		It("should answer A queries for the domain with the VM IP", func() {
+			request := query("local.pcfdev.io", 1)
+			response, err := responder.Answer(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response[0:2]).To(Equal([]byte{0x12, 0x34}))
+			Expect(response[2] & 0x80).NotTo(BeZero())
+			Expect(rcode(response)).To(BeZero())
+			Expect(answerCount(response)).To(Equal(uint16(1)))
+			Expect(response[12:len(request)]).To(Equal(request[12:]))
+			Expect(response[len(response)-4:]).To(Equal([]byte{192, 168, 11, 11}))
+		})
+
+		It("should answer A queries for any name under the domains", func() {
+			for _, name := range []string{"api.local.pcfdev.io", "some.APP.Local.PCFDev.io", "app.some-custom.domain"} {
+				response, err := responder.Answer(query(name, 1))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rcode(response)).To(BeZero())
+				Expect(response[len(response)-4:]).To(Equal([]byte{192, 168, 11, 11}))
+			}
+		})
+
+		It("should answer other record types for the domains with no records", func() {
+			response, err := responder.Answer(query("api.local.pcfdev.io", 28))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rcode(response)).To(BeZero())
+			Expect(answerCount(response)).To(BeZero())
+		})
+
+		It("should refuse queries for other domains", func() {
+			for _, name := range []string{"example.com", "notlocal.pcfdev.io"} {
+				response, err := responder.Answer(query(name, 1))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rcode(response)).To(Equal(byte(5)))
+				Expect(answerCount(response)).To(BeZero())
+			}
+		})
+
+		Context("when the query has no question", func() {
+			It("should return a format error", func() {
+				response, err := responder.Answer([]byte{0x12, 0x34, 0x01, 0x00, 0, 0, 0, 0, 0, 0, 0, 0})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rcode(response)).To(Equal(byte(1)))
+			})
+		})
+
+		Context("when the question is truncated", func() {
+			It("should return a format error", func() {
+				request := query("local.pcfdev.io", 1)
+				response, err := responder.Answer(request[:len(request)-6])
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rcode(response)).To(Equal(byte(1)))
+			})
+		})
+
+		Context("when the message is not a query", func() {
+			It("should return an error", func() {
+				request := query("local.pcfdev.io", 1)
+				request[2] |= 0x80
+				_, err := responder.Answer(request)
+				Expect(err).To(MatchError("DNS message is not a query"))
+			})
+		})
+
+		Context("when the message is shorter than a header", func() {
+			It("should return an error", func() {
+				_, err := responder.Answer([]byte{0x12, 0x34})
+				Expect(err).To(MatchError("DNS message is shorter than its header"))
+			})
+		})
+	})
+
+	Describe("#Serve", func() {
+		It("should resolve names under the domains over UDP", func() {
+			conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			defer conn.Close()
+			go responder.Serve(conn)
+
+			resolver := &net.Resolver{
+				PreferGo: true,
+				Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+					return (&net.Dialer{}).DialContext(ctx, "udp", conn.LocalAddr().String())
+				},
+			}
+			Expect(resolver.LookupHost(context.Background(), "some-app.local.pcfdev.io")).To(Equal([]string{"192.168.11.11"}))
+		})
+	})
+
+	Describe("Server", func() {
+		Describe("#ListenAndServe", func() {
+			It("should stop listening when the context is done", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				finished := make(chan error, 1)
+				go func() {
+					finished <- (&dns.Server{}).ListenAndServe(ctx, "127.0.0.1:0", "192.168.11.11", []string{"local.pcfdev.io"})
+				}()
+
+				Consistently(finished).ShouldNot(Receive())
+				cancel()
+				Eventually(finished).Should(Receive(BeNil()))
+			})
+		})
+	})
+
+	Describe(".NewResponder", func() {
+		Context("when the IP is not valid", func() {
+			It("should return an error", func() {
+				_, err := dns.NewResponder("some-bad-ip", []string{"local.pcfdev.io"})
+				Expect(err).To(MatchError("some-bad-ip is not a valid IPv4 address"))
+			})
+		})
+
+		Context("when there are no domains", func() {
+			It("should return an error", func() {
+				_, err := dns.NewResponder("192.168.11.11", nil)
+				Expect(err).To(MatchError("at least one domain must be given"))
+			})
+		})
+	})
+})
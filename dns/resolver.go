@@ -0,0 +1,35 @@
+package dns
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SystemdResolvedDropIn is where the configuration that sends queries for the
+// PCF Dev domains to the responder is installed on Linux.
+const SystemdResolvedDropIn = "/etc/systemd/resolved.conf.d/pcfdev.conf"
+
+// SystemdResolvedConfig returns a systemd-resolved drop-in that uses the
+// responder at address for the given domains only. The '~' prefix makes each
+// domain a routing-only domain, so other queries keep using the usual DNS
+// servers. Addresses with a port need systemd 246 or later.
+func SystemdResolvedConfig(address string, domains []string) string {
+	routingDomains := make([]string, len(domains))
+	for i, domain := range domains {
+		routingDomains[i] = "~" + canonicalName(domain)
+	}
+	return fmt.Sprintf("[Resolve]\nDNS=%s\nDomains=%s\n", address, strings.Join(routingDomains, " "))
+}
+
+// SystemdResolvedInstructions returns the commands that install the drop-in
+// from SystemdResolvedConfig.
+func SystemdResolvedInstructions(address string, domains []string) string {
+	config := strings.Replace(SystemdResolvedConfig(address, domains), "\n", `\n`, -1)
+	return strings.Join([]string{
+		"To resolve these domains through systemd-resolved, run:",
+		"  sudo mkdir -p /etc/systemd/resolved.conf.d",
+		fmt.Sprintf("  printf '%s' | sudo tee %s", config, SystemdResolvedDropIn),
+		"  sudo systemctl restart systemd-resolved",
+		"To stop, remove " + SystemdResolvedDropIn + " and restart systemd-resolved.",
+	}, "\n")
+}
@@ -0,0 +1,25 @@
+package dns_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/dns"
+)
+
+var _ = Describe("systemd-resolved", func() {
+	Describe(".SystemdResolvedConfig", func() {
+		It("should route only the given domains to the responder", func() {
+			Expect(dns.SystemdResolvedConfig("127.0.0.1:5354", []string{"local.pcfdev.io", "Some-Domain.Test."})).To(Equal(
+				"[Resolve]\nDNS=127.0.0.1:5354\nDomains=~local.pcfdev.io ~some-domain.test\n",
+			))
+		})
+	})
+
+	Describe(".SystemdResolvedInstructions", func() {
+		It("should install the drop-in and restart systemd-resolved", func() {
+			instructions := dns.SystemdResolvedInstructions("127.0.0.1:5354", []string{"local.pcfdev.io"})
+			Expect(instructions).To(ContainSubstring(`printf '[Resolve]\nDNS=127.0.0.1:5354\nDomains=~local.pcfdev.io\n' | sudo tee /etc/systemd/resolved.conf.d/pcfdev.conf`))
+			Expect(instructions).To(ContainSubstring("sudo systemctl restart systemd-resolved"))
+		})
+	})
+})
@@ -1,6 +1,7 @@
 package downloader
 
 import (
+	"context"
 	"io"
 	"time"
 
@@ -20,7 +21,7 @@ type ConcreteOVADownloader struct {
 
 //go:generate mockgen -package mocks -destination mocks/client.go github.com/pivotal-cf/pcfdev-cli/downloader Client
 type Client interface {
-	DownloadOVA(startAtByte int64) (ova *pivnet.DownloadReader, err error)
+	DownloadOVA(ctx context.Context, startAtByte int64) (ova *pivnet.DownloadReader, err error)
 }
 
 //go:generate mockgen -package mocks -destination mocks/fs.go github.com/pivotal-cf/pcfdev-cli/downloader FS
@@ -28,6 +29,7 @@ type FS interface {
 	Remove(path string) error
 	Exists(path string) (exists bool, err error)
 	MD5(path string) (md5 string, err error)
+	SHA256(path string) (sha256 string, err error)
 	CreateDir(path string) error
 	Length(path string) (bytes int64, err error)
 	Write(path string, contents io.Reader, append bool) error
@@ -49,11 +51,11 @@ func (d *ConcreteOVADownloader) IsOVACurrent() (bool, error) {
 		return false, nil
 	}
 
-	md5, err := d.FS.MD5(d.Config.OVAPath)
+	digest, err := Digest(d.FS, d.Config, d.Config.OVAPath)
 	if err != nil {
 		return false, err
 	}
-	if md5 != d.Config.ExpectedMD5 {
+	if digest != d.Config.ExpectedDigest() {
 		return false, nil
 	}
 
@@ -65,11 +67,11 @@ func (d *ConcreteOVADownloader) Setup() error {
 		return err
 	}
 
-	return d.FS.DeleteAllExcept(d.Config.OVADir, []string{d.Config.DefaultVMName + ".ova", d.Config.DefaultVMName + ".ova.partial"})
+	return d.FS.DeleteAllExcept(d.Config.OVADir, []string{d.Config.DefaultVMName + ".ova", d.Config.DefaultVMName + ".ova.partial", d.Config.DefaultVMName + ".eula-accepted"})
 }
 
-func (d *ConcreteOVADownloader) Download() (string, error) {
-	err := helpers.ExecuteWithAttempts(func() error {
+func (d *ConcreteOVADownloader) Download(ctx context.Context) (string, error) {
+	err := helpers.ExecuteWithAttempts(ctx, func() error {
 		exists, err := d.FS.Exists(d.Config.PartialOVAPath)
 		if err != nil {
 			return err
@@ -85,17 +87,22 @@ func (d *ConcreteOVADownloader) Download() (string, error) {
 			startAtBytes = int64(0)
 		}
 
-		ova, err := d.PivnetClient.DownloadOVA(startAtBytes)
+		ova, err := d.PivnetClient.DownloadOVA(ctx, startAtBytes)
 		if err != nil {
 			return err
 		}
 		defer ova.Close()
 
-		if err := d.Token.Save(); err != nil {
-			return err
+		if d.Token != nil {
+			if err := d.Token.Save(); err != nil {
+				return err
+			}
 		}
 
-		if err := d.FS.Write(d.Config.PartialOVAPath, ova, true); err != nil {
+		// A source that cannot resume the download sends the whole OVA
+		// again, which replaces the partial OVA.
+		resumed := ova.ExistingLength == startAtBytes
+		if err := d.FS.Write(d.Config.PartialOVAPath, ova, resumed); err != nil {
 			return err
 		}
 
@@ -106,5 +113,21 @@ func (d *ConcreteOVADownloader) Download() (string, error) {
 		return "", err
 	}
 
-	return d.FS.MD5(d.Config.PartialOVAPath)
+	return Digest(d.FS, d.Config, d.Config.PartialOVAPath)
+}
+
+// DigestFS hashes OVAs.
+type DigestFS interface {
+	MD5(path string) (md5 string, err error)
+	SHA256(path string) (sha256 string, err error)
+}
+
+// Digest returns the digest of the OVA at path that is compared with
+// conf.ExpectedDigest: its SHA-256, or its MD5 for builds that were not given
+// a SHA-256.
+func Digest(fs DigestFS, conf *config.Config, path string) (string, error) {
+	if conf.ExpectedSHA256 != "" {
+		return fs.SHA256(path)
+	}
+	return fs.MD5(path)
 }
@@ -1,6 +1,7 @@
 package downloader_test
 
 import (
+	"context"
 	"errors"
 	"io/ioutil"
 	"strings"
@@ -70,6 +71,32 @@ var _ = Describe("ConcreteOVADownloader", func() {
 			})
 		})
 
+		Context("when the plugin expects a SHA-256", func() {
+			BeforeEach(func() {
+				downloader.Config.ExpectedSHA256 = "some-sha256"
+			})
+
+			It("should compare the SHA-256 of the OVA instead of its MD5", func() {
+				gomock.InOrder(
+					mockFS.EXPECT().Exists("some-ova-path").Return(true, nil),
+					mockFS.EXPECT().SHA256("some-ova-path").Return("some-sha256", nil),
+				)
+
+				Expect(downloader.IsOVACurrent()).To(BeTrue())
+			})
+
+			Context("when the SHA-256 is incorrect", func() {
+				It("should return false", func() {
+					gomock.InOrder(
+						mockFS.EXPECT().Exists("some-ova-path").Return(true, nil),
+						mockFS.EXPECT().SHA256("some-ova-path").Return("some-bad-sha256", nil),
+					)
+
+					Expect(downloader.IsOVACurrent()).To(BeFalse())
+				})
+			})
+		})
+
 		Context("when OVA exists and has incorrect MD5", func() {
 			It("should return false", func() {
 				gomock.InOrder(
@@ -109,7 +136,7 @@ var _ = Describe("ConcreteOVADownloader", func() {
 		It("should get ready for a download", func() {
 			gomock.InOrder(
 				mockFS.EXPECT().CreateDir("some-ova-dir"),
-				mockFS.EXPECT().DeleteAllExcept("some-ova-dir", []string{"some-vm.ova", "some-vm.ova.partial"}),
+				mockFS.EXPECT().DeleteAllExcept("some-ova-dir", []string{"some-vm.ova", "some-vm.ova.partial", "some-vm.eula-accepted"}),
 			)
 
 			Expect(downloader.Setup()).To(Succeed())
@@ -127,7 +154,7 @@ var _ = Describe("ConcreteOVADownloader", func() {
 			It("should return an error", func() {
 				gomock.InOrder(
 					mockFS.EXPECT().CreateDir("some-ova-dir"),
-					mockFS.EXPECT().DeleteAllExcept("some-ova-dir", []string{"some-vm.ova", "some-vm.ova.partial"}).Return(errors.New("some-error")),
+					mockFS.EXPECT().DeleteAllExcept("some-ova-dir", []string{"some-vm.ova", "some-vm.ova.partial", "some-vm.eula-accepted"}).Return(errors.New("some-error")),
 				)
 
 				Expect(downloader.Setup()).To(MatchError("some-error"))
@@ -141,30 +168,65 @@ var _ = Describe("ConcreteOVADownloader", func() {
 				readCloser := &pivnet.DownloadReader{ReadCloser: ioutil.NopCloser(strings.NewReader("some-ova-contents"))}
 				gomock.InOrder(
 					mockFS.EXPECT().Exists("some-partial-ova-path").Return(false, nil),
-					mockClient.EXPECT().DownloadOVA(int64(0)).Return(readCloser, nil),
+					mockClient.EXPECT().DownloadOVA(gomock.Any(), int64(0)).Return(readCloser, nil),
 					mockToken.EXPECT().Save(),
 					mockFS.EXPECT().Write("some-partial-ova-path", readCloser, true),
 					mockFS.EXPECT().MD5("some-partial-ova-path").Return("some-md5", nil),
 				)
 
-				md5, err := downloader.Download()
+				md5, err := downloader.Download(context.Background())
 				Expect(err).NotTo(HaveOccurred())
 				Expect(md5).To(Equal("some-md5"))
 			})
 
+			Context("when the plugin expects a SHA-256", func() {
+				It("should return the SHA-256 of the download", func() {
+					downloader.Config.ExpectedSHA256 = "some-sha256"
+					readCloser := &pivnet.DownloadReader{ReadCloser: ioutil.NopCloser(strings.NewReader("some-ova-contents"))}
+					gomock.InOrder(
+						mockFS.EXPECT().Exists("some-partial-ova-path").Return(false, nil),
+						mockClient.EXPECT().DownloadOVA(gomock.Any(), int64(0)).Return(readCloser, nil),
+						mockToken.EXPECT().Save(),
+						mockFS.EXPECT().Write("some-partial-ova-path", readCloser, true),
+						mockFS.EXPECT().SHA256("some-partial-ova-path").Return("some-sha256", nil),
+					)
+
+					digest, err := downloader.Download(context.Background())
+					Expect(err).NotTo(HaveOccurred())
+					Expect(digest).To(Equal("some-sha256"))
+				})
+			})
+
+			Context("when there is no token", func() {
+				It("should download the file without saving a token", func() {
+					downloader.Token = nil
+					readCloser := &pivnet.DownloadReader{ReadCloser: ioutil.NopCloser(strings.NewReader("some-ova-contents"))}
+					gomock.InOrder(
+						mockFS.EXPECT().Exists("some-partial-ova-path").Return(false, nil),
+						mockClient.EXPECT().DownloadOVA(gomock.Any(), int64(0)).Return(readCloser, nil),
+						mockFS.EXPECT().Write("some-partial-ova-path", readCloser, true),
+						mockFS.EXPECT().MD5("some-partial-ova-path").Return("some-md5", nil),
+					)
+
+					md5, err := downloader.Download(context.Background())
+					Expect(err).NotTo(HaveOccurred())
+					Expect(md5).To(Equal("some-md5"))
+				})
+			})
+
 			Context("when there is an issue seeing if the partial ova exists", func() {
 				It("should retry the check", func() {
 					readCloser := &pivnet.DownloadReader{ReadCloser: ioutil.NopCloser(strings.NewReader("some-ova-contents"))}
 					gomock.InOrder(
 						mockFS.EXPECT().Exists("some-partial-ova-path").Return(false, errors.New("some-error")),
 						mockFS.EXPECT().Exists("some-partial-ova-path").Return(false, nil),
-						mockClient.EXPECT().DownloadOVA(int64(0)).Return(readCloser, nil),
+						mockClient.EXPECT().DownloadOVA(gomock.Any(), int64(0)).Return(readCloser, nil),
 						mockToken.EXPECT().Save(),
 						mockFS.EXPECT().Write("some-partial-ova-path", readCloser, true),
 						mockFS.EXPECT().MD5("some-partial-ova-path").Return("some-md5", nil),
 					)
 
-					md5, err := downloader.Download()
+					md5, err := downloader.Download(context.Background())
 					Expect(err).NotTo(HaveOccurred())
 					Expect(md5).To(Equal("some-md5"))
 				})
@@ -177,7 +239,7 @@ var _ = Describe("ConcreteOVADownloader", func() {
 						mockFS.EXPECT().Exists("some-partial-ova-path").Return(false, errors.New("some-error")),
 					)
 
-					_, err := downloader.Download()
+					_, err := downloader.Download(context.Background())
 					Expect(err).To(MatchError("some-error"))
 				})
 			})
@@ -187,17 +249,17 @@ var _ = Describe("ConcreteOVADownloader", func() {
 					readCloser := &pivnet.DownloadReader{ReadCloser: ioutil.NopCloser(strings.NewReader("some-ova-contents"))}
 					gomock.InOrder(
 						mockFS.EXPECT().Exists("some-partial-ova-path").Return(false, nil),
-						mockClient.EXPECT().DownloadOVA(int64(0)).Return(nil, errors.New("some-error")),
+						mockClient.EXPECT().DownloadOVA(gomock.Any(), int64(0)).Return(nil, errors.New("some-error")),
 
 						mockFS.EXPECT().Exists("some-partial-ova-path").Return(false, nil),
-						mockClient.EXPECT().DownloadOVA(int64(0)).Return(readCloser, nil),
+						mockClient.EXPECT().DownloadOVA(gomock.Any(), int64(0)).Return(readCloser, nil),
 
 						mockToken.EXPECT().Save(),
 						mockFS.EXPECT().Write("some-partial-ova-path", readCloser, true),
 						mockFS.EXPECT().MD5("some-partial-ova-path").Return("some-md5", nil),
 					)
 
-					md5, err := downloader.Download()
+					md5, err := downloader.Download(context.Background())
 					Expect(err).NotTo(HaveOccurred())
 					Expect(md5).To(Equal("some-md5"))
 				})
@@ -207,13 +269,13 @@ var _ = Describe("ConcreteOVADownloader", func() {
 				It("should return an error", func() {
 					gomock.InOrder(
 						mockFS.EXPECT().Exists("some-partial-ova-path").Return(false, nil),
-						mockClient.EXPECT().DownloadOVA(int64(0)).Return(nil, errors.New("some-error")),
+						mockClient.EXPECT().DownloadOVA(gomock.Any(), int64(0)).Return(nil, errors.New("some-error")),
 
 						mockFS.EXPECT().Exists("some-partial-ova-path").Return(false, nil),
-						mockClient.EXPECT().DownloadOVA(int64(0)).Return(nil, errors.New("some-error")),
+						mockClient.EXPECT().DownloadOVA(gomock.Any(), int64(0)).Return(nil, errors.New("some-error")),
 					)
 
-					_, err := downloader.Download()
+					_, err := downloader.Download(context.Background())
 					Expect(err).To(MatchError("some-error"))
 				})
 			})
@@ -223,18 +285,18 @@ var _ = Describe("ConcreteOVADownloader", func() {
 					readCloser := &pivnet.DownloadReader{ReadCloser: ioutil.NopCloser(strings.NewReader("some-ova-contents"))}
 					gomock.InOrder(
 						mockFS.EXPECT().Exists("some-partial-ova-path").Return(false, nil),
-						mockClient.EXPECT().DownloadOVA(int64(0)).Return(readCloser, nil),
+						mockClient.EXPECT().DownloadOVA(gomock.Any(), int64(0)).Return(readCloser, nil),
 						mockToken.EXPECT().Save().Return(errors.New("some-error")),
 
 						mockFS.EXPECT().Exists("some-partial-ova-path").Return(false, nil),
-						mockClient.EXPECT().DownloadOVA(int64(0)).Return(readCloser, nil),
+						mockClient.EXPECT().DownloadOVA(gomock.Any(), int64(0)).Return(readCloser, nil),
 						mockToken.EXPECT().Save(),
 
 						mockFS.EXPECT().Write("some-partial-ova-path", readCloser, true),
 						mockFS.EXPECT().MD5("some-partial-ova-path").Return("some-md5", nil),
 					)
 
-					md5, err := downloader.Download()
+					md5, err := downloader.Download(context.Background())
 					Expect(err).NotTo(HaveOccurred())
 					Expect(md5).To(Equal("some-md5"))
 				})
@@ -245,15 +307,15 @@ var _ = Describe("ConcreteOVADownloader", func() {
 					readCloser := &pivnet.DownloadReader{ReadCloser: ioutil.NopCloser(strings.NewReader("some-ova-contents"))}
 					gomock.InOrder(
 						mockFS.EXPECT().Exists("some-partial-ova-path").Return(false, nil),
-						mockClient.EXPECT().DownloadOVA(int64(0)).Return(readCloser, nil),
+						mockClient.EXPECT().DownloadOVA(gomock.Any(), int64(0)).Return(readCloser, nil),
 						mockToken.EXPECT().Save().Return(errors.New("some-error")),
 
 						mockFS.EXPECT().Exists("some-partial-ova-path").Return(false, nil),
-						mockClient.EXPECT().DownloadOVA(int64(0)).Return(readCloser, nil),
+						mockClient.EXPECT().DownloadOVA(gomock.Any(), int64(0)).Return(readCloser, nil),
 						mockToken.EXPECT().Save().Return(errors.New("some-error")),
 					)
 
-					_, err := downloader.Download()
+					_, err := downloader.Download(context.Background())
 					Expect(err).To(MatchError("some-error"))
 				})
 			})
@@ -263,18 +325,18 @@ var _ = Describe("ConcreteOVADownloader", func() {
 					readCloser := &pivnet.DownloadReader{ReadCloser: ioutil.NopCloser(strings.NewReader("some-ova-contents"))}
 					gomock.InOrder(
 						mockFS.EXPECT().Exists("some-partial-ova-path").Return(false, nil),
-						mockClient.EXPECT().DownloadOVA(int64(0)).Return(readCloser, nil),
+						mockClient.EXPECT().DownloadOVA(gomock.Any(), int64(0)).Return(readCloser, nil),
 						mockToken.EXPECT().Save(),
 						mockFS.EXPECT().Write("some-partial-ova-path", readCloser, true).Return(errors.New("some-error")),
 
 						mockFS.EXPECT().Exists("some-partial-ova-path").Return(false, nil),
-						mockClient.EXPECT().DownloadOVA(int64(0)).Return(readCloser, nil),
+						mockClient.EXPECT().DownloadOVA(gomock.Any(), int64(0)).Return(readCloser, nil),
 						mockToken.EXPECT().Save(),
 						mockFS.EXPECT().Write("some-partial-ova-path", readCloser, true),
 						mockFS.EXPECT().MD5("some-partial-ova-path").Return("some-md5", nil),
 					)
 
-					md5, err := downloader.Download()
+					md5, err := downloader.Download(context.Background())
 					Expect(err).NotTo(HaveOccurred())
 					Expect(md5).To(Equal("some-md5"))
 				})
@@ -285,17 +347,17 @@ var _ = Describe("ConcreteOVADownloader", func() {
 					readCloser := &pivnet.DownloadReader{ReadCloser: ioutil.NopCloser(strings.NewReader("some-ova-contents"))}
 					gomock.InOrder(
 						mockFS.EXPECT().Exists("some-partial-ova-path").Return(false, nil),
-						mockClient.EXPECT().DownloadOVA(int64(0)).Return(readCloser, nil),
+						mockClient.EXPECT().DownloadOVA(gomock.Any(), int64(0)).Return(readCloser, nil),
 						mockToken.EXPECT().Save(),
 						mockFS.EXPECT().Write("some-partial-ova-path", readCloser, true).Return(errors.New("some-error")),
 
 						mockFS.EXPECT().Exists("some-partial-ova-path").Return(false, nil),
-						mockClient.EXPECT().DownloadOVA(int64(0)).Return(readCloser, nil),
+						mockClient.EXPECT().DownloadOVA(gomock.Any(), int64(0)).Return(readCloser, nil),
 						mockToken.EXPECT().Save(),
 						mockFS.EXPECT().Write("some-partial-ova-path", readCloser, true).Return(errors.New("some-error")),
 					)
 
-					_, err := downloader.Download()
+					_, err := downloader.Download(context.Background())
 					Expect(err).To(MatchError("some-error"))
 				})
 			})
@@ -305,13 +367,13 @@ var _ = Describe("ConcreteOVADownloader", func() {
 					readCloser := &pivnet.DownloadReader{ReadCloser: ioutil.NopCloser(strings.NewReader("some-ova-contents"))}
 					gomock.InOrder(
 						mockFS.EXPECT().Exists("some-partial-ova-path").Return(false, nil),
-						mockClient.EXPECT().DownloadOVA(int64(0)).Return(readCloser, nil),
+						mockClient.EXPECT().DownloadOVA(gomock.Any(), int64(0)).Return(readCloser, nil),
 						mockToken.EXPECT().Save(),
 						mockFS.EXPECT().Write("some-partial-ova-path", readCloser, true),
 						mockFS.EXPECT().MD5("some-partial-ova-path").Return("", errors.New("some-error")),
 					)
 
-					md5, err := downloader.Download()
+					md5, err := downloader.Download(context.Background())
 					Expect(err).To(MatchError("some-error"))
 					Expect(md5).To(BeEmpty())
 				})
@@ -320,40 +382,59 @@ var _ = Describe("ConcreteOVADownloader", func() {
 
 		Context("when there is a partial ova present", func() {
 			It("should resume the download of the partial ova", func() {
-				readCloser := &pivnet.DownloadReader{ReadCloser: ioutil.NopCloser(strings.NewReader("some-ova-contents"))}
+				readCloser := &pivnet.DownloadReader{ReadCloser: ioutil.NopCloser(strings.NewReader("some-ova-contents")), ExistingLength: 24}
 				gomock.InOrder(
 					mockFS.EXPECT().Exists("some-partial-ova-path").Return(true, nil),
 					mockFS.EXPECT().Length("some-partial-ova-path").Return(int64(24), nil),
-					mockClient.EXPECT().DownloadOVA(int64(24)).Return(readCloser, nil),
+					mockClient.EXPECT().DownloadOVA(gomock.Any(), int64(24)).Return(readCloser, nil),
 					mockToken.EXPECT().Save(),
 					mockFS.EXPECT().Write("some-partial-ova-path", readCloser, true),
 					mockFS.EXPECT().MD5("some-partial-ova-path").Return("some-md5", nil),
 				)
 
-				md5, err := downloader.Download()
+				md5, err := downloader.Download(context.Background())
 				Expect(err).NotTo(HaveOccurred())
 				Expect(md5).To(Equal("some-md5"))
 			})
 
+			Context("when the source cannot resume the download", func() {
+				It("should replace the partial ova with the whole ova", func() {
+					readCloser := &pivnet.DownloadReader{ReadCloser: ioutil.NopCloser(strings.NewReader("some-ova-contents"))}
+					gomock.InOrder(
+						mockFS.EXPECT().Exists("some-partial-ova-path").Return(true, nil),
+						mockFS.EXPECT().Length("some-partial-ova-path").Return(int64(24), nil),
+						mockClient.EXPECT().DownloadOVA(gomock.Any(), int64(24)).Return(readCloser, nil),
+						mockToken.EXPECT().Save(),
+						mockFS.EXPECT().Write("some-partial-ova-path", readCloser, false),
+						mockFS.EXPECT().MD5("some-partial-ova-path").Return("some-md5", nil),
+					)
+
+					md5, err := downloader.Download(context.Background())
+					Expect(err).NotTo(HaveOccurred())
+					Expect(md5).To(Equal("some-md5"))
+				})
+			})
+
 			Context("when something goes wrong saving the file", func() {
 				It("should retry the download from where it failed", func() {
-					readCloser := &pivnet.DownloadReader{ReadCloser: ioutil.NopCloser(strings.NewReader("some-ova-contents"))}
+					readCloser := &pivnet.DownloadReader{ReadCloser: ioutil.NopCloser(strings.NewReader("some-ova-contents")), ExistingLength: 24}
+					secondReadCloser := &pivnet.DownloadReader{ReadCloser: ioutil.NopCloser(strings.NewReader("some-ova-contents")), ExistingLength: 48}
 					gomock.InOrder(
 						mockFS.EXPECT().Exists("some-partial-ova-path").Return(true, nil),
 						mockFS.EXPECT().Length("some-partial-ova-path").Return(int64(24), nil),
-						mockClient.EXPECT().DownloadOVA(int64(24)).Return(readCloser, nil),
+						mockClient.EXPECT().DownloadOVA(gomock.Any(), int64(24)).Return(readCloser, nil),
 						mockToken.EXPECT().Save(),
 						mockFS.EXPECT().Write("some-partial-ova-path", readCloser, true).Return(errors.New("some-error")),
 
 						mockFS.EXPECT().Exists("some-partial-ova-path").Return(true, nil),
 						mockFS.EXPECT().Length("some-partial-ova-path").Return(int64(48), nil),
-						mockClient.EXPECT().DownloadOVA(int64(48)).Return(readCloser, nil),
+						mockClient.EXPECT().DownloadOVA(gomock.Any(), int64(48)).Return(secondReadCloser, nil),
 						mockToken.EXPECT().Save(),
-						mockFS.EXPECT().Write("some-partial-ova-path", readCloser, true),
+						mockFS.EXPECT().Write("some-partial-ova-path", secondReadCloser, true),
 						mockFS.EXPECT().MD5("some-partial-ova-path").Return("some-md5", nil),
 					)
 
-					md5, err := downloader.Download()
+					md5, err := downloader.Download(context.Background())
 					Expect(err).NotTo(HaveOccurred())
 					Expect(md5).To(Equal("some-md5"))
 				})
@@ -368,7 +449,7 @@ var _ = Describe("ConcreteOVADownloader", func() {
 						mockFS.EXPECT().Length("some-partial-ova-path").Return(int64(24), errors.New("some-error")),
 					)
 
-					_, err := downloader.Download()
+					_, err := downloader.Download(context.Background())
 					Expect(err).To(MatchError("some-error"))
 				})
 			})
@@ -1,6 +1,7 @@
 package downloader
 
 import (
+	"context"
 	"time"
 
 	"github.com/pivotal-cf/pcfdev-cli/config"
@@ -9,13 +10,13 @@ import (
 //go:generate mockgen -package mocks -destination mocks/ova_downloader.go github.com/pivotal-cf/pcfdev-cli/downloader OVADownloader
 type OVADownloader interface {
 	Setup() error
-	Download() (md5 string, err error)
+	Download(ctx context.Context) (digest string, err error)
 	IsOVACurrent() (current bool, err error)
 }
 
 type Downloader interface {
 	IsOVACurrent() (current bool, err error)
-	Download() error
+	Download(ctx context.Context) error
 }
 
 type DownloaderFactory struct {
@@ -1,6 +1,7 @@
 package downloader
 
 import (
+	"context"
 	"errors"
 
 	"github.com/pivotal-cf/pcfdev-cli/config"
@@ -16,17 +17,17 @@ func (f *FullDownloader) IsOVACurrent() (bool, error) {
 	return f.Downloader.IsOVACurrent()
 }
 
-func (f *FullDownloader) Download() error {
+func (f *FullDownloader) Download(ctx context.Context) error {
 	if err := f.Downloader.Setup(); err != nil {
 		return err
 	}
 
-	md5, err := f.Downloader.Download()
+	digest, err := f.Downloader.Download(ctx)
 	if err != nil {
 		return err
 	}
 
-	if md5 != f.Config.ExpectedMD5 {
+	if digest != f.Config.ExpectedDigest() {
 		return errors.New("download failed")
 	}
 
@@ -1,6 +1,7 @@
 package downloader_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/golang/mock/gomock"
@@ -70,18 +71,18 @@ var _ = Describe("FullDownloader", func() {
 		It("should download the file", func() {
 			gomock.InOrder(
 				mockOVADownloader.EXPECT().Setup(),
-				mockOVADownloader.EXPECT().Download().Return("some-md5", nil),
+				mockOVADownloader.EXPECT().Download(gomock.Any()).Return("some-md5", nil),
 				mockFS.EXPECT().Move("some-partial-ova-path", "some-ova-path"),
 			)
 
-			Expect(downloader.Download()).To(Succeed())
+			Expect(downloader.Download(context.Background())).To(Succeed())
 		})
 
 		Context("when setup fails", func() {
 			It("should return an error", func() {
 				mockOVADownloader.EXPECT().Setup().Return(errors.New("some-error"))
 
-				Expect(downloader.Download()).To(MatchError("some-error"))
+				Expect(downloader.Download(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -89,10 +90,37 @@ var _ = Describe("FullDownloader", func() {
 			It("should return an error", func() {
 				gomock.InOrder(
 					mockOVADownloader.EXPECT().Setup(),
-					mockOVADownloader.EXPECT().Download().Return("some-other-md5", nil),
+					mockOVADownloader.EXPECT().Download(gomock.Any()).Return("some-other-md5", nil),
 				)
 
-				Expect(downloader.Download()).To(MatchError("download failed"))
+				Expect(downloader.Download(context.Background())).To(MatchError("download failed"))
+			})
+		})
+
+		Context("when the plugin expects a SHA-256", func() {
+			BeforeEach(func() {
+				downloader.Config.ExpectedSHA256 = "some-sha256"
+			})
+
+			It("should compare the SHA-256 of the download", func() {
+				gomock.InOrder(
+					mockOVADownloader.EXPECT().Setup(),
+					mockOVADownloader.EXPECT().Download(gomock.Any()).Return("some-sha256", nil),
+					mockFS.EXPECT().Move("some-partial-ova-path", "some-ova-path"),
+				)
+
+				Expect(downloader.Download(context.Background())).To(Succeed())
+			})
+
+			Context("when only the MD5 matches", func() {
+				It("should return an error", func() {
+					gomock.InOrder(
+						mockOVADownloader.EXPECT().Setup(),
+						mockOVADownloader.EXPECT().Download(gomock.Any()).Return("some-md5", nil),
+					)
+
+					Expect(downloader.Download(context.Background())).To(MatchError("download failed"))
+				})
 			})
 		})
 
@@ -100,10 +128,10 @@ var _ = Describe("FullDownloader", func() {
 			It("should return an error", func() {
 				gomock.InOrder(
 					mockOVADownloader.EXPECT().Setup(),
-					mockOVADownloader.EXPECT().Download().Return("", errors.New("some-error")),
+					mockOVADownloader.EXPECT().Download(gomock.Any()).Return("", errors.New("some-error")),
 				)
 
-				Expect(downloader.Download()).To(MatchError("some-error"))
+				Expect(downloader.Download(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -111,11 +139,11 @@ var _ = Describe("FullDownloader", func() {
 			It("should return an error", func() {
 				gomock.InOrder(
 					mockOVADownloader.EXPECT().Setup(),
-					mockOVADownloader.EXPECT().Download().Return("some-md5", nil),
+					mockOVADownloader.EXPECT().Download(gomock.Any()).Return("some-md5", nil),
 					mockFS.EXPECT().Move("some-partial-ova-path", "some-ova-path").Return(errors.New("some-error")),
 				)
 
-				Expect(downloader.Download()).To(MatchError("some-error"))
+				Expect(downloader.Download(context.Background())).To(MatchError("some-error"))
 			})
 		})
 	})
@@ -102,6 +102,17 @@ func (_mr *_MockFSRecorder) Remove(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Remove", arg0)
 }
 
+func (_m *MockFS) SHA256(_param0 string) (string, error) {
+	ret := _m.ctrl.Call(_m, "SHA256", _param0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockFSRecorder) SHA256(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "SHA256", arg0)
+}
+
 func (_m *MockFS) Write(_param0 string, _param1 io.Reader, _param2 bool) error {
 	ret := _m.ctrl.Call(_m, "Write", _param0, _param1, _param2)
 	ret0, _ := ret[0].(error)
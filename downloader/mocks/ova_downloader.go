@@ -4,6 +4,7 @@
 package mocks
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -28,15 +29,15 @@ func (_m *MockOVADownloader) EXPECT() *_MockOVADownloaderRecorder {
 	return _m.recorder
 }
 
-func (_m *MockOVADownloader) Download() (string, error) {
-	ret := _m.ctrl.Call(_m, "Download")
+func (_m *MockOVADownloader) Download(_param0 context.Context) (string, error) {
+	ret := _m.ctrl.Call(_m, "Download", _param0)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (_mr *_MockOVADownloaderRecorder) Download() *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "Download")
+func (_mr *_MockOVADownloaderRecorder) Download(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Download", arg0)
 }
 
 func (_m *MockOVADownloader) IsOVACurrent() (bool, error) {
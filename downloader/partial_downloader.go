@@ -1,6 +1,7 @@
 package downloader
 
 import (
+	"context"
 	"errors"
 
 	"github.com/pivotal-cf/pcfdev-cli/config"
@@ -16,27 +17,27 @@ func (p *PartialDownloader) IsOVACurrent() (bool, error) {
 	return p.Downloader.IsOVACurrent()
 }
 
-func (p *PartialDownloader) Download() error {
+func (p *PartialDownloader) Download(ctx context.Context) error {
 	if err := p.Downloader.Setup(); err != nil {
 		return err
 	}
 
-	md5, err := p.Downloader.Download()
+	digest, err := p.Downloader.Download(ctx)
 	if err != nil {
 		return err
 	}
 
-	if md5 != p.Config.ExpectedMD5 {
+	if digest != p.Config.ExpectedDigest() {
 		if err := p.FS.Remove(p.Config.PartialOVAPath); err != nil {
 			return err
 		}
 
-		md5, err = p.Downloader.Download()
+		digest, err = p.Downloader.Download(ctx)
 		if err != nil {
 			return err
 		}
 
-		if md5 != p.Config.ExpectedMD5 {
+		if digest != p.Config.ExpectedDigest() {
 			return errors.New("download failed")
 		}
 
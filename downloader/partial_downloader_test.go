@@ -1,6 +1,7 @@
 package downloader_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/golang/mock/gomock"
@@ -70,11 +71,11 @@ var _ = Describe("PartialDownloader", func() {
 		It("should download the file", func() {
 			gomock.InOrder(
 				mockOVADownloader.EXPECT().Setup(),
-				mockOVADownloader.EXPECT().Download().Return("some-md5", nil),
+				mockOVADownloader.EXPECT().Download(gomock.Any()).Return("some-md5", nil),
 				mockFS.EXPECT().Move("some-partial-ova-path", "some-ova-path"),
 			)
 
-			Expect(downloader.Download()).To(Succeed())
+			Expect(downloader.Download(context.Background())).To(Succeed())
 		})
 
 		Context("when the download setup fails", func() {
@@ -83,7 +84,7 @@ var _ = Describe("PartialDownloader", func() {
 					mockOVADownloader.EXPECT().Setup().Return(errors.New("some-error")),
 				)
 
-				Expect(downloader.Download()).To(MatchError("some-error"))
+				Expect(downloader.Download(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -91,10 +92,10 @@ var _ = Describe("PartialDownloader", func() {
 			It("should return the error", func() {
 				gomock.InOrder(
 					mockOVADownloader.EXPECT().Setup(),
-					mockOVADownloader.EXPECT().Download().Return("", errors.New("some-error")),
+					mockOVADownloader.EXPECT().Download(gomock.Any()).Return("", errors.New("some-error")),
 				)
 
-				Expect(downloader.Download()).To(MatchError("some-error"))
+				Expect(downloader.Download(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -102,13 +103,13 @@ var _ = Describe("PartialDownloader", func() {
 			It("should delete the partially downloaded file and download again", func() {
 				gomock.InOrder(
 					mockOVADownloader.EXPECT().Setup(),
-					mockOVADownloader.EXPECT().Download().Return("some-other-md5", nil),
+					mockOVADownloader.EXPECT().Download(gomock.Any()).Return("some-other-md5", nil),
 					mockFS.EXPECT().Remove("some-partial-ova-path"),
-					mockOVADownloader.EXPECT().Download().Return("some-md5", nil),
+					mockOVADownloader.EXPECT().Download(gomock.Any()).Return("some-md5", nil),
 					mockFS.EXPECT().Move("some-partial-ova-path", "some-ova-path"),
 				)
 
-				Expect(downloader.Download()).To(Succeed())
+				Expect(downloader.Download(context.Background())).To(Succeed())
 			})
 		})
 
@@ -116,11 +117,11 @@ var _ = Describe("PartialDownloader", func() {
 			It("return the error", func() {
 				gomock.InOrder(
 					mockOVADownloader.EXPECT().Setup(),
-					mockOVADownloader.EXPECT().Download().Return("some-other-md5", nil),
+					mockOVADownloader.EXPECT().Download(gomock.Any()).Return("some-other-md5", nil),
 					mockFS.EXPECT().Remove("some-partial-ova-path").Return(errors.New("some-error")),
 				)
 
-				Expect(downloader.Download()).To(MatchError("some-error"))
+				Expect(downloader.Download(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -128,12 +129,12 @@ var _ = Describe("PartialDownloader", func() {
 			It("should delete the partially downloaded file and download again", func() {
 				gomock.InOrder(
 					mockOVADownloader.EXPECT().Setup(),
-					mockOVADownloader.EXPECT().Download().Return("some-other-md5", nil),
+					mockOVADownloader.EXPECT().Download(gomock.Any()).Return("some-other-md5", nil),
 					mockFS.EXPECT().Remove("some-partial-ova-path"),
-					mockOVADownloader.EXPECT().Download().Return("some-other-bad-md5", nil),
+					mockOVADownloader.EXPECT().Download(gomock.Any()).Return("some-other-bad-md5", nil),
 				)
 
-				Expect(downloader.Download()).To(MatchError("download failed"))
+				Expect(downloader.Download(context.Background())).To(MatchError("download failed"))
 			})
 		})
 
@@ -141,12 +142,12 @@ var _ = Describe("PartialDownloader", func() {
 			It("should return the error", func() {
 				gomock.InOrder(
 					mockOVADownloader.EXPECT().Setup(),
-					mockOVADownloader.EXPECT().Download().Return("some-other-md5", nil),
+					mockOVADownloader.EXPECT().Download(gomock.Any()).Return("some-other-md5", nil),
 					mockFS.EXPECT().Remove("some-partial-ova-path"),
-					mockOVADownloader.EXPECT().Download().Return("", errors.New("some-error")),
+					mockOVADownloader.EXPECT().Download(gomock.Any()).Return("", errors.New("some-error")),
 				)
 
-				Expect(downloader.Download()).To(MatchError("some-error"))
+				Expect(downloader.Download(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -154,11 +155,11 @@ var _ = Describe("PartialDownloader", func() {
 			It("should return the error", func() {
 				gomock.InOrder(
 					mockOVADownloader.EXPECT().Setup(),
-					mockOVADownloader.EXPECT().Download().Return("some-md5", nil),
+					mockOVADownloader.EXPECT().Download(gomock.Any()).Return("some-md5", nil),
 					mockFS.EXPECT().Move("some-partial-ova-path", "some-ova-path").Return(errors.New("some-error")),
 				)
 
-				Expect(downloader.Download()).To(MatchError("some-error"))
+				Expect(downloader.Download(context.Background())).To(MatchError("some-error"))
 			})
 		})
 	})
@@ -0,0 +1,91 @@
+package dryrun
+
+import (
+	"context"
+
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/downloader"
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
+)
+
+//go:generate mockgen -package mocks -destination mocks/client.go github.com/pivotal-cf/pcfdev-cli/dryrun Client
+type Client interface {
+	Status(ctx context.Context, sshAddresses []ssh.SSHAddress, privateKey []byte) (string, error)
+	ReplaceSecrets(ctx context.Context, sshAddresses []ssh.SSHAddress, password string, privateKey []byte) error
+}
+
+//go:generate mockgen -package mocks -destination mocks/downloader_factory.go github.com/pivotal-cf/pcfdev-cli/dryrun DownloaderFactory
+type DownloaderFactory interface {
+	Create() (downloader downloader.Downloader, err error)
+}
+
+// VMClient stands in for the client of the PCF Dev API running on the VM.
+// Once the dry run has started a VM, that VM is reported as unprovisioned,
+// because nothing has actually booted it.
+type VMClient struct {
+	Recorder  *Recorder
+	Client    Client
+	CmdRunner *CmdRunner
+}
+
+func (c *VMClient) Status(ctx context.Context, sshAddresses []ssh.SSHAddress, privateKey []byte) (string, error) {
+	if c.CmdRunner.Started() {
+		return "Unprovisioned", nil
+	}
+	return c.Client.Status(ctx, sshAddresses, privateKey)
+}
+
+func (c *VMClient) ReplaceSecrets(ctx context.Context, sshAddresses []ssh.SSHAddress, password string, privateKey []byte) error {
+	c.Recorder.Record("replace secrets on %s", sshAddresses[0].IP)
+	return nil
+}
+
+// PivnetClient records the EULA acceptance instead of contacting Pivotal
+// Network, or recording the acceptance of the EULA of a mirrored OVA.
+type PivnetClient struct {
+	Recorder *Recorder
+}
+
+func (c *PivnetClient) IsEULAAccepted(ctx context.Context) (bool, error) {
+	c.Recorder.Record("accept the PCF Dev EULA, if not yet accepted")
+	return true, nil
+}
+
+func (c *PivnetClient) AcceptEULA(ctx context.Context) error {
+	return nil
+}
+
+func (c *PivnetClient) GetEULA(ctx context.Context) (string, error) {
+	return "", nil
+}
+
+// RecordingDownloaderFactory checks the OVA already on disk, but only records
+// the download of a new one.
+type RecordingDownloaderFactory struct {
+	Recorder          *Recorder
+	DownloaderFactory DownloaderFactory
+	Config            *config.Config
+}
+
+func (f *RecordingDownloaderFactory) Create() (downloader.Downloader, error) {
+	d, err := f.DownloaderFactory.Create()
+	if err != nil {
+		return nil, err
+	}
+	return &recordingDownloader{
+		Downloader: d,
+		Recorder:   f.Recorder,
+		Config:     f.Config,
+	}, nil
+}
+
+type recordingDownloader struct {
+	downloader.Downloader
+	Recorder *Recorder
+	Config   *config.Config
+}
+
+func (d *recordingDownloader) Download(ctx context.Context) error {
+	d.Recorder.Record("download PCF Dev OVA to %s", d.Config.OVAPath)
+	return nil
+}
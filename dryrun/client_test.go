@@ -0,0 +1,100 @@
+package dryrun_test
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/dryrun"
+	"github.com/pivotal-cf/pcfdev-cli/dryrun/mocks"
+	cmdMocks "github.com/pivotal-cf/pcfdev-cli/plugin/cmd/mocks"
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
+	"github.com/pivotal-cf/pcfdev-cli/vbox/fake"
+	"github.com/pivotal-cf/pcfdev-cli/vboxdriver"
+)
+
+var _ = Describe("Clients", func() {
+	var (
+		mockCtrl *gomock.Controller
+		mockUI   *mocks.MockUI
+		recorder *dryrun.Recorder
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockUI = mocks.NewMockUI(mockCtrl)
+		mockUI.EXPECT().Say(gomock.Any(), gomock.Any()).AnyTimes()
+		recorder = &dryrun.Recorder{UI: mockUI}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("VMClient", func() {
+		var (
+			mockClient *mocks.MockClient
+			mockSource *mocks.MockSource
+			runner     *dryrun.CmdRunner
+			client     *dryrun.VMClient
+		)
+
+		BeforeEach(func() {
+			mockClient = mocks.NewMockClient(mockCtrl)
+			mockSource = mocks.NewMockSource(mockCtrl)
+			runner = &dryrun.CmdRunner{Recorder: recorder, Driver: fake.NewDriver(), Source: mockSource}
+			client = &dryrun.VMClient{Recorder: recorder, Client: mockClient, CmdRunner: runner}
+		})
+
+		It("should ask the real VM until the dry run starts one", func() {
+			mockClient.EXPECT().Status(gomock.Any(), []ssh.SSHAddress{{IP: "some-ip", Port: "22"}}, []byte("some-key")).Return("Running", nil)
+			Expect(client.Status(context.Background(), []ssh.SSHAddress{{IP: "some-ip", Port: "22"}}, []byte("some-key"))).To(Equal("Running"))
+
+			mockSource.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5}, nil)
+			mockSource.EXPECT().GetHostOnlyInterfaces()
+			mockSource.EXPECT().GetBridgedInterfaces()
+			mockSource.EXPECT().GetNATNetworks()
+			mockSource.EXPECT().Disks()
+			mockSource.EXPECT().VMs()
+			Expect(runner.Run("VBoxManage", "createvm", "--name", "some-vm")).To(BeEmpty())
+			Expect(runner.Run("VBoxManage", "startvm", "some-vm")).To(BeEmpty())
+
+			Expect(client.Status(context.Background(), []ssh.SSHAddress{{IP: "some-ip", Port: "22"}}, []byte("some-key"))).To(Equal("Unprovisioned"))
+		})
+
+		It("should record replacing secrets without revealing the password", func() {
+			Expect(client.ReplaceSecrets(context.Background(), []ssh.SSHAddress{{IP: "some-ip", Port: "22"}}, "some-password", []byte("some-key"))).To(Succeed())
+			Expect(recorder.Operations()).To(Equal([]string{"replace secrets on some-ip"}))
+		})
+	})
+
+	Describe("RecordingDownloaderFactory", func() {
+		It("should check the existing OVA but only record the download", func() {
+			mockFactory := mocks.NewMockDownloaderFactory(mockCtrl)
+			mockDownloader := cmdMocks.NewMockDownloader(mockCtrl)
+			factory := &dryrun.RecordingDownloaderFactory{
+				Recorder:          recorder,
+				DownloaderFactory: mockFactory,
+				Config:            &config.Config{OVAPath: "some-ova-path"},
+			}
+
+			mockFactory.EXPECT().Create().Return(mockDownloader, nil)
+			mockDownloader.EXPECT().IsOVACurrent().Return(false, nil)
+
+			downloader, err := factory.Create()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(downloader.IsOVACurrent()).To(BeFalse())
+			Expect(downloader.Download(context.Background())).To(Succeed())
+			Expect(recorder.Operations()).To(Equal([]string{"download PCF Dev OVA to some-ova-path"}))
+		})
+	})
+
+	Describe("PivnetClient", func() {
+		It("should record accepting the EULA", func() {
+			client := &dryrun.PivnetClient{Recorder: recorder}
+			Expect(client.IsEULAAccepted(context.Background())).To(BeTrue())
+			Expect(recorder.Operations()).To(HaveLen(1))
+		})
+	})
+})
@@ -0,0 +1,225 @@
+package dryrun
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pivotal-cf/pcfdev-cli/network"
+	"github.com/pivotal-cf/pcfdev-cli/vbox/fake"
+	"github.com/pivotal-cf/pcfdev-cli/vboxdriver"
+)
+
+//go:generate mockgen -package mocks -destination mocks/source.go github.com/pivotal-cf/pcfdev-cli/dryrun Source
+type Source interface {
+	Version() (version *vboxdriver.VBoxDriverVersion, err error)
+	VMs() (vms []string, err error)
+	VMInfo(vmName string) (info *vboxdriver.VMInfo, err error)
+	GetHostOnlyInterfaces() (interfaces []*network.Interface, err error)
+	GetBridgedInterfaces() (interfaces []*network.Interface, err error)
+	GetNATNetworks() (networks []*network.Interface, err error)
+	Disks() (disks []string, err error)
+}
+
+//go:generate mockgen -package mocks -destination mocks/runner.go github.com/pivotal-cf/pcfdev-cli/dryrun Runner
+type Runner interface {
+	Run(command string, args ...string) (output []byte, err error)
+}
+
+// virshQueries are the virsh subcommands that only read the state of libvirt.
+// They are run for real, so that a dry run with the libvirt provider sees the
+// VMs and networks that exist.
+var virshQueries = map[string]bool{
+	"--version":   true,
+	"list":        true,
+	"dominfo":     true,
+	"domiflist":   true,
+	"net-list":    true,
+	"net-info":    true,
+	"net-dumpxml": true,
+}
+
+// CmdRunner records the commands it is asked to run instead of running them.
+// VBoxManage invocations are answered by a fake driver that starts out as a
+// copy of the VMs, disks and networks known to the real
+// VirtualBox, so that later steps of a command see the effects of earlier ones.
+// virsh queries are answered by Runner, and other virsh invocations do nothing.
+type CmdRunner struct {
+	Recorder *Recorder
+	Driver   *fake.Driver
+	Source   Source
+	Runner   Runner
+
+	seedOnce sync.Once
+	seedErr  error
+	mutex    sync.Mutex
+	started  bool
+}
+
+// RunContext runs command like Run. Nothing that it does takes long enough to
+// be worth cancelling.
+func (c *CmdRunner) RunContext(ctx context.Context, command string, args ...string) ([]byte, error) {
+	return c.Run(command, args...)
+}
+
+func (c *CmdRunner) Run(command string, args ...string) ([]byte, error) {
+	name := strings.TrimSuffix(filepath.Base(command), ".exe")
+	c.Recorder.Record("%s", strings.Join(append([]string{name}, args...), " "))
+
+	if name == "virsh" {
+		return c.runVirsh(command, args...)
+	}
+	if name != "VBoxManage" {
+		return nil, nil
+	}
+
+	c.seedOnce.Do(func() { c.seedErr = c.seed() })
+	if c.seedErr != nil {
+		return nil, fmt.Errorf("failed to read VirtualBox state: %s", c.seedErr)
+	}
+
+	output, err := c.Driver.VBoxManage(args...)
+	if err == nil && len(args) > 0 && args[0] == "startvm" {
+		c.mutex.Lock()
+		c.started = true
+		c.mutex.Unlock()
+	}
+	return output, err
+}
+
+// Started reports whether a VM has been started during the dry run.
+func (c *CmdRunner) Started() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.started
+}
+
+func (c *CmdRunner) runVirsh(command string, args ...string) ([]byte, error) {
+	subcommandArgs := args
+	if len(subcommandArgs) > 1 && subcommandArgs[0] == "-c" {
+		subcommandArgs = subcommandArgs[2:]
+	}
+	if len(subcommandArgs) == 0 {
+		return nil, nil
+	}
+
+	if virshQueries[subcommandArgs[0]] {
+		return c.Runner.Run(command, args...)
+	}
+	if subcommandArgs[0] == "start" {
+		c.mutex.Lock()
+		c.started = true
+		c.mutex.Unlock()
+	}
+	return nil, nil
+}
+
+func (c *CmdRunner) seed() error {
+	version, err := c.Source.Version()
+	if err != nil {
+		return err
+	}
+	c.Driver.VBoxVersion = *version
+
+	interfaces, err := c.Source.GetHostOnlyInterfaces()
+	if err != nil {
+		return err
+	}
+	for _, iface := range interfaces {
+		c.Driver.AddHostOnlyInterface(iface)
+	}
+
+	bridgedInterfaces, err := c.Source.GetBridgedInterfaces()
+	if err != nil {
+		return err
+	}
+	for _, iface := range bridgedInterfaces {
+		c.Driver.AddBridgedInterface(iface)
+	}
+
+	natNetworks, err := c.Source.GetNATNetworks()
+	if err != nil {
+		return err
+	}
+	for _, natNetwork := range natNetworks {
+		c.Driver.AddNATNetwork(natNetwork)
+	}
+
+	disks, err := c.Source.Disks()
+	if err != nil {
+		return err
+	}
+	registeredDisks := map[string]bool{}
+	for _, disk := range disks {
+		if err := c.Driver.CloneDisk(context.Background(), "", disk); err != nil {
+			return err
+		}
+		registeredDisks[disk] = true
+	}
+
+	vms, err := c.Source.VMs()
+	if err != nil {
+		return err
+	}
+	for _, vm := range vms {
+		if err := c.seedVM(vm, registeredDisks); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *CmdRunner) seedVM(vmName string, registeredDisks map[string]bool) error {
+	if err := c.Driver.CreateVM(vmName, ""); err != nil {
+		return err
+	}
+
+	info, err := c.Source.VMInfo(vmName)
+	if err != nil {
+		return err
+	}
+
+	if err := c.Driver.SetMemory(vmName, info.Memory); err != nil {
+		return err
+	}
+	if err := c.Driver.SetCPUs(vmName, info.CPUs); err != nil {
+		return err
+	}
+	for _, rule := range info.ForwardingRules {
+		if err := c.Driver.ForwardPort(vmName, rule.Name, rule.HostPort, rule.GuestPort); err != nil {
+			return err
+		}
+	}
+	if nic, exists := info.NIC(2); exists {
+		if err := c.seedNIC(vmName, nic); err != nil {
+			return err
+		}
+	}
+	for _, attachment := range info.StorageAttachments {
+		if !registeredDisks[attachment.Medium] {
+			continue
+		}
+		if err := c.Driver.AttachDisk(vmName, attachment.Medium); err != nil {
+			return err
+		}
+	}
+
+	return c.Driver.SetState(vmName, info.State)
+}
+
+func (c *CmdRunner) seedNIC(vmName string, nic vboxdriver.NIC) error {
+	switch {
+	case nic.BridgeAdapter != "":
+		return c.Driver.AttachBridgedInterface(nic.BridgeAdapter, vmName)
+	case nic.NATNetwork != "":
+		return c.Driver.AttachNATNetwork(nic.NATNetwork, vmName)
+	case nic.HostOnlyAdapter != "":
+		return c.Driver.AttachNetworkInterface(nic.HostOnlyAdapter, vmName)
+	case nic.HostOnlyNetwork != "":
+		return c.Driver.AttachNetworkInterface(nic.HostOnlyNetwork, vmName)
+	}
+	return nil
+}
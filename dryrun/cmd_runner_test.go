@@ -0,0 +1,204 @@
+package dryrun_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/address"
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/dryrun"
+	"github.com/pivotal-cf/pcfdev-cli/dryrun/mocks"
+	"github.com/pivotal-cf/pcfdev-cli/fs"
+	"github.com/pivotal-cf/pcfdev-cli/network"
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
+	"github.com/pivotal-cf/pcfdev-cli/vbox"
+	"github.com/pivotal-cf/pcfdev-cli/vbox/fake"
+	"github.com/pivotal-cf/pcfdev-cli/vboxdriver"
+)
+
+var _ = Describe("CmdRunner", func() {
+	var (
+		mockCtrl   *gomock.Controller
+		mockUI     *mocks.MockUI
+		mockSource *mocks.MockSource
+		mockRunner *mocks.MockRunner
+		recorder   *dryrun.Recorder
+		runner     *dryrun.CmdRunner
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockUI = mocks.NewMockUI(mockCtrl)
+		mockSource = mocks.NewMockSource(mockCtrl)
+		mockRunner = mocks.NewMockRunner(mockCtrl)
+		mockUI.EXPECT().Say(gomock.Any(), gomock.Any()).AnyTimes()
+		recorder = &dryrun.Recorder{UI: mockUI}
+		runner = &dryrun.CmdRunner{
+			Recorder: recorder,
+			Driver:   fake.NewDriver(),
+			Source:   mockSource,
+			Runner:   mockRunner,
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	expectEmptySource := func() {
+		mockSource.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5, Minor: 0, Build: 4}, nil)
+		mockSource.EXPECT().GetHostOnlyInterfaces().Return([]*network.Interface{}, nil)
+		mockSource.EXPECT().GetBridgedInterfaces().Return([]*network.Interface{}, nil)
+		mockSource.EXPECT().GetNATNetworks().Return([]*network.Interface{}, nil)
+		mockSource.EXPECT().Disks().Return([]string{}, nil)
+		mockSource.EXPECT().VMs().Return([]string{}, nil)
+	}
+
+	Describe("#Run", func() {
+		It("should record VBoxManage invocations and answer them from the simulated state", func() {
+			expectEmptySource()
+
+			Expect(runner.Run("/some/path/VBoxManage", "--version")).To(Equal([]byte("5.0.4r0\n")))
+			Expect(runner.Run("VBoxManage", "createvm", "--name", "some-vm", "--register")).To(BeEmpty())
+			Expect(runner.Started()).To(BeFalse())
+			Expect(runner.Run("VBoxManage", "startvm", "some-vm", "--type", "headless")).To(BeEmpty())
+			Expect(runner.Started()).To(BeTrue())
+
+			_, err := runner.Run("VBoxManage", "startvm", "some-other-vm")
+			Expect(err).To(MatchError(ContainSubstring("Could not find a registered machine named 'some-other-vm'")))
+
+			Expect(recorder.Operations()).To(Equal([]string{
+				"VBoxManage --version",
+				"VBoxManage createvm --name some-vm --register",
+				"VBoxManage startvm some-vm --type headless",
+				"VBoxManage startvm some-other-vm",
+			}))
+		})
+
+		It("should start from the VMs, disks and interfaces that already exist", func() {
+			gomock.InOrder(
+				mockSource.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5, Minor: 1, Build: 22}, nil),
+				mockSource.EXPECT().GetHostOnlyInterfaces().Return([]*network.Interface{
+					{Name: "vboxnet0", IP: "192.168.11.1", HardwareAddress: "some-hardware-address"},
+				}, nil),
+				mockSource.EXPECT().GetBridgedInterfaces().Return([]*network.Interface{
+					{Name: "en0: Wi-Fi (AirPort)", IP: "192.168.1.23"},
+				}, nil),
+				mockSource.EXPECT().GetNATNetworks().Return([]*network.Interface{
+					{Name: "pcfdev-nat-192.168.22.1", IP: "192.168.22.1"},
+				}, nil),
+				mockSource.EXPECT().Disks().Return([]string{"some-disk"}, nil),
+				mockSource.EXPECT().VMs().Return([]string{"pcfdev-some-vm"}, nil),
+				mockSource.EXPECT().VMInfo("pcfdev-some-vm").Return(&vboxdriver.VMInfo{
+					Name:            "pcfdev-some-vm",
+					State:           vboxdriver.StateRunning,
+					Memory:          4096,
+					CPUs:            2,
+					NICs:            []vboxdriver.NIC{{Index: 1, Type: "nat"}, {Index: 2, Type: "hostonly", HostOnlyAdapter: "vboxnet0"}},
+					ForwardingRules: []vboxdriver.ForwardingRule{{Name: "ssh", Protocol: "tcp", HostIP: "127.0.0.1", HostPort: "2222", GuestPort: "22"}},
+					StorageAttachments: []vboxdriver.StorageAttachment{
+						{Controller: "SATA", Medium: "some-disk"},
+					},
+				}, nil),
+			)
+
+			Expect(runner.Run("VBoxManage", "list", "runningvms")).To(MatchRegexp(`^"pcfdev-some-vm" `))
+			Expect(runner.Driver.GetMemory("pcfdev-some-vm")).To(Equal(uint64(4096)))
+			Expect(runner.Driver.GetHostForwardPort("pcfdev-some-vm", "ssh")).To(Equal("2222"))
+			Expect(runner.Driver.IsInterfaceInUse("vboxnet0")).To(BeTrue())
+			Expect(runner.Driver.IsInterfaceInUse("pcfdev-nat-192.168.22.1")).To(BeFalse())
+			Expect(runner.Driver.GetBridgedInterfaces()).To(Equal([]*network.Interface{
+				{Name: "en0: Wi-Fi (AirPort)", IP: "192.168.1.23", Exists: true},
+			}))
+			Expect(runner.Driver.Disks()).To(Equal([]string{"some-disk"}))
+		})
+
+		It("should record other commands without running them", func() {
+			Expect(runner.Run("security", "delete-keychain", "pcfdev.keychain")).To(BeEmpty())
+			Expect(recorder.Operations()).To(Equal([]string{"security delete-keychain pcfdev.keychain"}))
+		})
+
+		It("should run virsh queries and only record the other virsh commands", func() {
+			mockRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "list", "--all", "--name").Return([]byte("pcfdev-some-vm\n"), nil)
+
+			Expect(runner.Run("virsh", "-c", "qemu:///system", "list", "--all", "--name")).To(Equal([]byte("pcfdev-some-vm\n")))
+			Expect(runner.Run("virsh", "-c", "qemu:///system", "define", "some-domain.xml")).To(BeEmpty())
+			Expect(runner.Started()).To(BeFalse())
+			Expect(runner.Run("virsh", "-c", "qemu:///system", "start", "pcfdev-some-vm")).To(BeEmpty())
+			Expect(runner.Started()).To(BeTrue())
+			Expect(recorder.Operations()).To(Equal([]string{
+				"virsh -c qemu:///system list --all --name",
+				"virsh -c qemu:///system define some-domain.xml",
+				"virsh -c qemu:///system start pcfdev-some-vm",
+			}))
+		})
+
+		Context("when the existing VirtualBox state cannot be read", func() {
+			It("should return an error", func() {
+				mockSource.EXPECT().Version().Return(nil, errors.New("some-error"))
+
+				_, err := runner.Run("VBoxManage", "list", "vms")
+				Expect(err).To(MatchError("failed to read VirtualBox state: some-error"))
+			})
+		})
+	})
+
+	Context("when it backs a vbox.VBox", func() {
+		var (
+			vbx    *vbox.VBox
+			tmpDir string
+		)
+
+		BeforeEach(func() {
+			var err error
+			tmpDir, err = ioutil.TempDir("", "pcfdev-dryrun")
+			Expect(err).NotTo(HaveOccurred())
+
+			driver := &vboxdriver.VBoxDriver{FS: &fs.FS{}, CmdRunner: runner}
+			vbx = &vbox.VBox{
+				Driver: driver,
+				FS:     &dryrun.OverlayFS{Recorder: recorder, FS: &fs.FS{}},
+				SSH:    &dryrun.SSH{Recorder: recorder, SSH: &ssh.SSH{}},
+				Picker: &address.Picker{Network: runner.Driver, Driver: driver},
+				Config: &config.Config{
+					VMDir:          filepath.Join(tmpDir, "vms"),
+					PrivateKeyPath: filepath.Join(tmpDir, "key.pem"),
+				},
+			}
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(tmpDir)).To(Succeed())
+		})
+
+		It("should plan importing, starting and destroying a VM without touching the host", func() {
+			expectEmptySource()
+
+			vmConfig := &config.VMConfig{Name: "pcfdev-some-vm", Memory: 4096, CPUs: 2, OVAPath: "some-ova-path"}
+			Expect(vbx.ImportVM(context.Background(), vmConfig)).To(Succeed())
+			Expect(vbx.VMStatus("pcfdev-some-vm")).To(Equal(vbox.StatusStopped))
+
+			vmConfig, err := vbx.VMConfig("pcfdev-some-vm")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vbx.StartVM(context.Background(), vmConfig)).To(Succeed())
+			Expect(vbx.VMStatus("pcfdev-some-vm")).To(Equal(vbox.StatusRunning))
+
+			Expect(vbx.DestroyPCFDevVMs(context.Background())).To(Succeed())
+			Expect(vbx.VMStatus("pcfdev-some-vm")).To(Equal(vbox.StatusNotCreated))
+
+			operations := recorder.Operations()
+			Expect(operations).To(ContainElement(HavePrefix("VBoxManage createvm --name pcfdev-some-vm")))
+			Expect(operations).To(ContainElement("VBoxManage startvm pcfdev-some-vm --type headless"))
+			Expect(operations).To(ContainElement(HavePrefix("guest: ")))
+			Expect(operations).To(ContainElement("VBoxManage unregistervm pcfdev-some-vm --delete"))
+
+			Expect(ioutil.ReadDir(tmpDir)).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,185 @@
+package dryrun
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+//go:generate mockgen -package mocks -destination mocks/fs.go github.com/pivotal-cf/pcfdev-cli/dryrun FS
+type FS interface {
+	Exists(path string) (exists bool, err error)
+	Read(path string) (contents []byte, err error)
+	MD5(path string) (md5 string, err error)
+	SHA256(path string) (sha256 string, err error)
+	TempDir() (tempDir string, err error)
+}
+
+// OverlayFS reads from the real file system but only records changes to it.
+// Files written during the dry run are kept in memory and shadow the real
+// ones, and removed paths are hidden, so that later reads see the planned
+// state of the file system.
+type OverlayFS struct {
+	Recorder *Recorder
+	FS       FS
+
+	mutex   sync.Mutex
+	files   map[string][]byte
+	removed map[string]bool
+}
+
+func (o *OverlayFS) Exists(path string) (bool, error) {
+	if contents, removed := o.lookup(path); removed {
+		return false, nil
+	} else if contents != nil {
+		return true, nil
+	}
+	return o.FS.Exists(path)
+}
+
+func (o *OverlayFS) Read(path string) ([]byte, error) {
+	if contents, removed := o.lookup(path); removed {
+		return nil, fmt.Errorf("open %s: no such file or directory", path)
+	} else if contents != nil {
+		return contents, nil
+	}
+	return o.FS.Read(path)
+}
+
+func (o *OverlayFS) Open(path string) (io.ReadCloser, error) {
+	contents, err := o.Read(path)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.NopCloser(bytes.NewReader(contents)), nil
+}
+
+func (o *OverlayFS) MD5(path string) (string, error) {
+	return o.FS.MD5(path)
+}
+
+func (o *OverlayFS) SHA256(path string) (string, error) {
+	return o.FS.SHA256(path)
+}
+
+func (o *OverlayFS) TempDir() (string, error) {
+	return o.FS.TempDir()
+}
+
+func (o *OverlayFS) Write(path string, contents io.Reader, append bool) error {
+	o.Recorder.Record("write %s", path)
+
+	data, err := ioutil.ReadAll(contents)
+	if err != nil {
+		return err
+	}
+	if append {
+		existing, err := o.Read(path)
+		if err == nil {
+			data = bytes.Join([][]byte{existing, data}, nil)
+		}
+	}
+	o.store(path, data)
+	return nil
+}
+
+func (o *OverlayFS) CreateDir(path string) error {
+	o.Recorder.Record("create directory %s", path)
+	o.store(path, []byte{})
+	return nil
+}
+
+func (o *OverlayFS) Remove(path string) error {
+	o.Recorder.Record("remove %s", path)
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if o.removed == nil {
+		o.removed = map[string]bool{}
+	}
+	o.removed[filepath.Clean(path)] = true
+	for file := range o.files {
+		if isWithin(file, path) {
+			delete(o.files, file)
+		}
+	}
+	return nil
+}
+
+func (o *OverlayFS) Copy(source string, destination string) error {
+	o.Recorder.Record("copy %s to %s", source, destination)
+	o.store(destination, []byte{})
+	return nil
+}
+
+func (o *OverlayFS) Move(source string, destination string) error {
+	o.Recorder.Record("move %s to %s", source, destination)
+
+	contents, err := o.Read(source)
+	if err != nil {
+		return err
+	}
+	o.store(destination, contents)
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	delete(o.files, filepath.Clean(source))
+	if o.removed == nil {
+		o.removed = map[string]bool{}
+	}
+	o.removed[filepath.Clean(source)] = true
+	return nil
+}
+
+func (o *OverlayFS) Extract(archivePath string, destinationPath string, pattern string) error {
+	o.Recorder.Record("extract %s from %s to %s", pattern, archivePath, destinationPath)
+	o.store(destinationPath, []byte{})
+	return nil
+}
+
+func (o *OverlayFS) Compress(name string, path string, contentPaths []string) error {
+	o.Recorder.Record("compress %s into %s", strings.Join(contentPaths, ", "), filepath.Join(path, name+".tgz"))
+	return nil
+}
+
+func (o *OverlayFS) Chmod(path string, mode os.FileMode) error {
+	o.Recorder.Record("chmod %#o %s", mode, path)
+	return nil
+}
+
+func (o *OverlayFS) lookup(path string) (contents []byte, removed bool) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if contents, exists := o.files[filepath.Clean(path)]; exists {
+		return contents, false
+	}
+	for removedPath := range o.removed {
+		if isWithin(path, removedPath) {
+			return nil, true
+		}
+	}
+	return nil, false
+}
+
+func (o *OverlayFS) store(path string, contents []byte) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if o.files == nil {
+		o.files = map[string][]byte{}
+	}
+	o.files[filepath.Clean(path)] = contents
+	delete(o.removed, filepath.Clean(path))
+}
+
+func isWithin(path string, dir string) bool {
+	path, dir = filepath.Clean(path), filepath.Clean(dir)
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
@@ -0,0 +1,102 @@
+package dryrun_test
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/dryrun"
+	"github.com/pivotal-cf/pcfdev-cli/dryrun/mocks"
+)
+
+var _ = Describe("OverlayFS", func() {
+	var (
+		mockCtrl  *gomock.Controller
+		mockUI    *mocks.MockUI
+		mockFS    *mocks.MockFS
+		recorder  *dryrun.Recorder
+		overlayFS *dryrun.OverlayFS
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockUI = mocks.NewMockUI(mockCtrl)
+		mockFS = mocks.NewMockFS(mockCtrl)
+		mockUI.EXPECT().Say(gomock.Any(), gomock.Any()).AnyTimes()
+		recorder = &dryrun.Recorder{UI: mockUI}
+		overlayFS = &dryrun.OverlayFS{Recorder: recorder, FS: mockFS}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("should read from the real file system until a path is changed", func() {
+		gomock.InOrder(
+			mockFS.EXPECT().Exists("/some/dir/some-file").Return(true, nil),
+			mockFS.EXPECT().Read("/some/dir/some-file").Return([]byte("some-contents"), nil),
+		)
+
+		Expect(overlayFS.Exists("/some/dir/some-file")).To(BeTrue())
+		Expect(overlayFS.Read("/some/dir/some-file")).To(Equal([]byte("some-contents")))
+
+		Expect(overlayFS.Write("/some/dir/some-file", strings.NewReader("some-new-contents"), false)).To(Succeed())
+		Expect(overlayFS.Read("/some/dir/some-file")).To(Equal([]byte("some-new-contents")))
+
+		Expect(overlayFS.Remove("/some/dir")).To(Succeed())
+		Expect(overlayFS.Exists("/some/dir/some-file")).To(BeFalse())
+		_, err := overlayFS.Read("/some/dir/some-file")
+		Expect(err).To(MatchError("open /some/dir/some-file: no such file or directory"))
+
+		Expect(overlayFS.Copy("/some/ova", "/some/dir/some-copy")).To(Succeed())
+		Expect(overlayFS.Exists("/some/dir/some-copy")).To(BeTrue())
+
+		Expect(recorder.Operations()).To(Equal([]string{
+			"write /some/dir/some-file",
+			"remove /some/dir",
+			"copy /some/ova to /some/dir/some-copy",
+		}))
+	})
+
+	It("should append to existing contents", func() {
+		mockFS.EXPECT().Read("some-file").Return([]byte("some-"), nil)
+
+		Expect(overlayFS.Write("some-file", strings.NewReader("contents"), true)).To(Succeed())
+		Expect(overlayFS.Read("some-file")).To(Equal([]byte("some-contents")))
+	})
+
+	It("should move contents from one path to another", func() {
+		Expect(overlayFS.Write("some-new-key", strings.NewReader("some-key"), false)).To(Succeed())
+		Expect(overlayFS.Move("some-new-key", "some-key")).To(Succeed())
+
+		Expect(overlayFS.Read("some-key")).To(Equal([]byte("some-key")))
+		Expect(overlayFS.Exists("some-new-key")).To(BeFalse())
+		Expect(recorder.Operations()).To(Equal([]string{
+			"write some-new-key",
+			"move some-new-key to some-key",
+		}))
+	})
+
+	It("should record extracting, creating directories and changing permissions", func() {
+		Expect(overlayFS.Extract("some-ova", "some-disk", `\w+\.vmdk`)).To(Succeed())
+		Expect(overlayFS.CreateDir("some-dir")).To(Succeed())
+		Expect(overlayFS.Chmod("some-key", 0600)).To(Succeed())
+
+		Expect(recorder.Operations()).To(Equal([]string{
+			`extract \w+\.vmdk from some-ova to some-disk`,
+			"create directory some-dir",
+			"chmod 0600 some-key",
+		}))
+	})
+
+	Context("when reading from the real file system fails", func() {
+		It("should return the error", func() {
+			mockFS.EXPECT().Read("some-file").Return(nil, errors.New("some-error"))
+
+			_, err := overlayFS.Read("some-file")
+			Expect(err).To(MatchError("some-error"))
+		})
+	})
+})
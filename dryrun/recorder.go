@@ -0,0 +1,37 @@
+package dryrun
+
+import (
+	"fmt"
+	"sync"
+)
+
+//go:generate mockgen -package mocks -destination mocks/ui.go github.com/pivotal-cf/pcfdev-cli/dryrun UI
+type UI interface {
+	Say(message string, args ...interface{})
+}
+
+// Recorder keeps the operations that a dry run would have performed, in the
+// order they were planned, and prints each one as it is recorded.
+type Recorder struct {
+	UI UI
+
+	mutex      sync.Mutex
+	operations []string
+}
+
+func (r *Recorder) Record(format string, args ...interface{}) {
+	operation := fmt.Sprintf(format, args...)
+
+	r.mutex.Lock()
+	r.operations = append(r.operations, operation)
+	r.mutex.Unlock()
+
+	r.UI.Say("[dry-run] %s", operation)
+}
+
+func (r *Recorder) Operations() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return append([]string{}, r.operations...)
+}
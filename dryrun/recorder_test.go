@@ -0,0 +1,44 @@
+package dryrun_test
+
+import (
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/dryrun"
+	"github.com/pivotal-cf/pcfdev-cli/dryrun/mocks"
+)
+
+var _ = Describe("Recorder", func() {
+	var (
+		mockCtrl *gomock.Controller
+		mockUI   *mocks.MockUI
+		recorder *dryrun.Recorder
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockUI = mocks.NewMockUI(mockCtrl)
+		recorder = &dryrun.Recorder{UI: mockUI}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("#Record", func() {
+		It("should print and keep each operation in order", func() {
+			gomock.InOrder(
+				mockUI.EXPECT().Say("[dry-run] %s", "VBoxManage startvm some-vm"),
+				mockUI.EXPECT().Say("[dry-run] %s", "guest: some-command 100%"),
+			)
+
+			recorder.Record("VBoxManage startvm %s", "some-vm")
+			recorder.Record("guest: %s", "some-command 100%")
+
+			Expect(recorder.Operations()).To(Equal([]string{
+				"VBoxManage startvm some-vm",
+				"guest: some-command 100%",
+			}))
+		})
+	})
+})
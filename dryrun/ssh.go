@@ -0,0 +1,87 @@
+package dryrun
+
+import (
+	"context"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
+)
+
+//go:generate mockgen -package mocks -destination mocks/keypair_generator.go github.com/pivotal-cf/pcfdev-cli/dryrun KeypairGenerator
+type KeypairGenerator interface {
+	GenerateAddress() (host string, port string, err error)
+	GenerateKeypair(keyType string) (privateKey []byte, publicKey []byte, err error)
+}
+
+var (
+	guestWriteRegex = regexp.MustCompile(`(?s)^echo '(.*)' \| sudo tee (\S+)`)
+	guestReadRegex  = regexp.MustCompile(`^cat (\S+)$`)
+)
+
+// SSH records the commands that would be run on the guest instead of
+// connecting to it. Files written on the guest with "echo ... | sudo tee" are
+// remembered, so that a later "cat" of the same file returns what was written.
+type SSH struct {
+	Recorder *Recorder
+	SSH      KeypairGenerator
+
+	mutex sync.Mutex
+	files map[string]string
+}
+
+func (s *SSH) GenerateAddress() (host string, port string, err error) {
+	return s.SSH.GenerateAddress()
+}
+
+func (s *SSH) GenerateKeypair(keyType string) (privateKey []byte, publicKey []byte, err error) {
+	return s.SSH.GenerateKeypair(keyType)
+}
+
+func (s *SSH) StartSSHSession(ctx context.Context, addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration, options *ssh.SessionOptions, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	s.Recorder.Record("guest: <interactive session>")
+	return nil
+}
+
+func (s *SSH) WaitForSSH(ctx context.Context, addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration) error {
+	return nil
+}
+
+func (s *SSH) RunSSHCommand(ctx context.Context, command string, addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration, stdout io.Writer, stderr io.Writer) error {
+	s.run(command)
+	return nil
+}
+
+func (s *SSH) RunSSHCommandWithStdin(ctx context.Context, command string, addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	s.run(command)
+	return nil
+}
+
+func (s *SSH) GetSSHOutput(ctx context.Context, command string, addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration) (string, error) {
+	s.run(command)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if matches := guestReadRegex.FindStringSubmatch(strings.TrimSpace(command)); len(matches) > 1 {
+		return s.files[matches[1]], nil
+	}
+	return "", nil
+}
+
+func (s *SSH) run(command string) {
+	s.Recorder.Record("guest: %s", command)
+
+	if matches := guestWriteRegex.FindStringSubmatch(command); len(matches) > 2 {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		if s.files == nil {
+			s.files = map[string]string{}
+		}
+		s.files[matches[2]] = matches[1]
+	}
+}
@@ -0,0 +1,72 @@
+package dryrun_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/dryrun"
+	"github.com/pivotal-cf/pcfdev-cli/dryrun/mocks"
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
+)
+
+var _ = Describe("SSH", func() {
+	var (
+		mockCtrl      *gomock.Controller
+		mockUI        *mocks.MockUI
+		mockGenerator *mocks.MockKeypairGenerator
+		recorder      *dryrun.Recorder
+		dryRunSSH     *dryrun.SSH
+		addresses     []ssh.SSHAddress
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockUI = mocks.NewMockUI(mockCtrl)
+		mockGenerator = mocks.NewMockKeypairGenerator(mockCtrl)
+		mockUI.EXPECT().Say(gomock.Any(), gomock.Any()).AnyTimes()
+		recorder = &dryrun.Recorder{UI: mockUI}
+		dryRunSSH = &dryrun.SSH{Recorder: recorder, SSH: mockGenerator}
+		addresses = []ssh.SSHAddress{{IP: "127.0.0.1", Port: "2222"}}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("should record guest commands instead of running them", func() {
+		Expect(dryRunSSH.WaitForSSH(context.Background(), addresses, []byte("some-key"), time.Minute)).To(Succeed())
+		Expect(dryRunSSH.RunSSHCommand(context.Background(), "some-command", addresses, []byte("some-key"), time.Minute, nil, nil)).To(Succeed())
+		Expect(dryRunSSH.RunSSHCommandWithStdin(context.Background(), "some-other-command", addresses, []byte("some-key"), time.Minute, nil, nil, nil)).To(Succeed())
+		Expect(dryRunSSH.GetSSHOutput(context.Background(), "some-query", addresses, []byte("some-key"), time.Minute)).To(BeEmpty())
+
+		Expect(recorder.Operations()).To(Equal([]string{
+			"guest: some-command",
+			"guest: some-other-command",
+			"guest: some-query",
+		}))
+	})
+
+	It("should return the contents of files written on the guest", func() {
+		Expect(dryRunSSH.RunSSHCommand(context.Background(), `echo '{"domain":"some-domain"}' | sudo tee /var/some-file >/dev/null`, addresses, []byte("some-key"), time.Minute, nil, nil)).To(Succeed())
+
+		Expect(dryRunSSH.GetSSHOutput(context.Background(), "cat /var/some-file", addresses, []byte("some-key"), time.Minute)).To(Equal(`{"domain":"some-domain"}`))
+		Expect(dryRunSSH.GetSSHOutput(context.Background(), "cat /var/some-other-file", addresses, []byte("some-key"), time.Minute)).To(BeEmpty())
+	})
+
+	It("should generate keypairs and addresses with the real client", func() {
+		mockGenerator.EXPECT().GenerateKeypair("some-key-type").Return([]byte("some-private-key"), []byte("some-public-key"), nil)
+		mockGenerator.EXPECT().GenerateAddress().Return("127.0.0.1", "2222", nil)
+
+		privateKey, publicKey, err := dryRunSSH.GenerateKeypair("some-key-type")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(privateKey).To(Equal([]byte("some-private-key")))
+		Expect(publicKey).To(Equal([]byte("some-public-key")))
+
+		_, port, err := dryRunSSH.GenerateAddress()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(port).To(Equal("2222"))
+	})
+})
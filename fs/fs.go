@@ -4,12 +4,15 @@ import (
 	"archive/tar"
 	"compress/gzip"
 	cMD5 "crypto/md5"
+	cSHA256 "crypto/sha256"
 	"fmt"
+	"hash"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 )
 
 type FS struct{}
@@ -28,6 +31,14 @@ func (fs *FS) Read(path string) (contents []byte, err error) {
 	return ioutil.ReadFile(path)
 }
 
+func (fs *FS) Open(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
 func (fs *FS) Write(path string, contents io.Reader, append bool) error {
 	var flag int
 	if append {
@@ -82,19 +93,25 @@ func (fs *FS) Remove(path string) error {
 }
 
 func (fs *FS) MD5(path string) (md5 string, err error) {
+	return fs.digest(path, cMD5.New())
+}
+
+func (fs *FS) SHA256(path string) (sha256 string, err error) {
+	return fs.digest(path, cSHA256.New())
+}
+
+func (fs *FS) digest(path string, h hash.Hash) (string, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return "", fmt.Errorf("failed to open %s: %s", path, err)
 	}
 	defer file.Close()
 
-	hash := cMD5.New()
-
-	if _, err = io.Copy(hash, file); err != nil {
+	if _, err = io.Copy(h, file); err != nil {
 		return "", fmt.Errorf("failed to read %s: %s", path, err)
 	}
 
-	return fmt.Sprintf("%x", hash.Sum([]byte{})), nil
+	return fmt.Sprintf("%x", h.Sum([]byte{})), nil
 }
 
 func (fs *FS) Length(path string) (int64, error) {
@@ -206,6 +223,45 @@ func (fs *FS) Compress(name string, path string, contentPaths []string) error {
 	return nil
 }
 
+func (fs *FS) Decompress(archivePath string, destinationPath string) error {
+	archive, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %s", archivePath, err)
+	}
+	defer archive.Close()
+
+	gzipReader, err := gzip.NewReader(archive)
+	if err != nil {
+		return fmt.Errorf("malformed tgz %s:%s", archivePath, err)
+	}
+	defer gzipReader.Close()
+	reader := tar.NewReader(gzipReader)
+
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("malformed tgz %s:%s", archivePath, err)
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+
+		path := filepath.Join(destinationPath, header.Name)
+		if !strings.HasPrefix(path, filepath.Clean(destinationPath)+string(filepath.Separator)) {
+			return fmt.Errorf("malformed tgz %s: %s is outside of the archive", archivePath, header.Name)
+		}
+		if err := fs.CreateDir(filepath.Dir(path)); err != nil {
+			return err
+		}
+		if err := fs.Write(path, reader, false); err != nil {
+			return err
+		}
+	}
+}
+
 func (fs *FS) TempDir() (string, error) {
 	return ioutil.TempDir("", "")
 }
@@ -62,6 +62,24 @@ var _ = Describe("Filesystem", func() {
 		})
 	})
 
+	Describe("#Open", func() {
+		It("should return a reader for the file", func() {
+			Expect(ioutil.WriteFile(filepath.Join(tmpDir, "some-file"), []byte("some-contents"), 0644)).To(Succeed())
+
+			file, err := fs.Open(filepath.Join(tmpDir, "some-file"))
+			Expect(err).NotTo(HaveOccurred())
+			defer file.Close()
+			Expect(ioutil.ReadAll(file)).To(Equal([]byte("some-contents")))
+		})
+
+		Context("when the file does not exist", func() {
+			It("should return an error", func() {
+				_, err := fs.Open(filepath.Join(tmpDir, "some-bad-file"))
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
 	Describe("#Write", func() {
 		Context("when path is valid", func() {
 			It("should create a file with path and writes contents", func() {
@@ -177,6 +195,26 @@ var _ = Describe("Filesystem", func() {
 		})
 	})
 
+	Describe("#SHA256", func() {
+		Context("when the file exists", func() {
+			BeforeEach(func() {
+				Expect(ioutil.WriteFile(filepath.Join(tmpDir, "some-file"), []byte("some-contents"), 0644)).To(Succeed())
+			})
+
+			It("should return the sha256 of the given file", func() {
+				Expect(fs.SHA256(filepath.Join(tmpDir, "some-file"))).To(Equal("6e32ea34db1b3755d7dec972eb72c705338f0dd8e0be881d966963438fb2e800"))
+			})
+		})
+
+		Context("when the file does not exist", func() {
+			It("should return an error", func() {
+				sha256, err := fs.SHA256(filepath.Join(tmpDir, "some-non-existent-file"))
+				Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf("failed to open %s:", filepath.Join(tmpDir, "some-non-existent-file")))))
+				Expect(sha256).To(Equal(""))
+			})
+		})
+	})
+
 	Describe("#Length", func() {
 		Context("when the file exists", func() {
 			BeforeEach(func() {
@@ -387,6 +425,32 @@ var _ = Describe("Filesystem", func() {
 		})
 	})
 
+	Describe("#Decompress", func() {
+		It("should extract the files written by Compress", func() {
+			Expect(ioutil.WriteFile(filepath.Join(tmpDir, "some-file"), []byte("some-contents"), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(tmpDir, "some-other-file"), []byte("some-other-contents"), 0644)).To(Succeed())
+			Expect(fs.Compress("some-tgz-name", tmpDir, []string{filepath.Join(tmpDir, "some-file"), filepath.Join(tmpDir, "some-other-file")})).To(Succeed())
+
+			Expect(fs.Decompress(filepath.Join(tmpDir, "some-tgz-name.tgz"), filepath.Join(tmpDir, "some-dir"))).To(Succeed())
+			Expect(ioutil.ReadFile(filepath.Join(tmpDir, "some-dir", "some-tgz-name", "some-file"))).To(Equal([]byte("some-contents")))
+			Expect(ioutil.ReadFile(filepath.Join(tmpDir, "some-dir", "some-tgz-name", "some-other-file"))).To(Equal([]byte("some-other-contents")))
+		})
+
+		Context("when the archive does not exist", func() {
+			It("should return an error", func() {
+				Expect(fs.Decompress("some-bad-archive", tmpDir)).To(MatchError(ContainSubstring("failed to open some-bad-archive:")))
+			})
+		})
+
+		Context("when the archive is malformed", func() {
+			It("should return an error", func() {
+				Expect(ioutil.WriteFile(filepath.Join(tmpDir, "some-bad-tgz"), []byte("not-an-archive"), 0644)).To(Succeed())
+
+				Expect(fs.Decompress(filepath.Join(tmpDir, "some-bad-tgz"), tmpDir)).To(MatchError(ContainSubstring(fmt.Sprintf("malformed tgz %s:", filepath.Join(tmpDir, "some-bad-tgz")))))
+			})
+		})
+	})
+
 	Describe("#TempDir", func() {
 		It("should create a temp directory", func() {
 			Expect(fs.TempDir()).To(BeAnExistingFile())
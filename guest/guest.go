@@ -0,0 +1,407 @@
+package guest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pivotal-cf/pcfdev-cli/address"
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/helpers"
+	"github.com/pivotal-cf/pcfdev-cli/proxy"
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
+)
+
+//go:generate mockgen -package mocks -destination mocks/fs.go github.com/pivotal-cf/pcfdev-cli/guest FS
+type FS interface {
+	Exists(path string) (exists bool, err error)
+	Write(path string, contents io.Reader, append bool) error
+	Read(path string) (contents []byte, err error)
+	Chmod(path string, mode os.FileMode) error
+	Remove(path string) error
+	Move(source string, destination string) error
+}
+
+//go:generate mockgen -package mocks -destination mocks/ssh.go github.com/pivotal-cf/pcfdev-cli/guest SSH
+type SSH interface {
+	GenerateKeypair(keyType string) (privateKey []byte, publicKey []byte, err error)
+	RunSSHCommand(ctx context.Context, command string, addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration, stdout io.Writer, stderr io.Writer) error
+	RunSSHCommandWithStdin(ctx context.Context, command string, addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration, stdin io.Reader, stdout io.Writer, stderr io.Writer) error
+}
+
+type Guest struct {
+	Config *config.Config
+	FS     FS
+	SSH    SSH
+}
+
+type VMProperties struct {
+	IPAddress string
+	DHCP      bool
+}
+
+type ProxyTypes struct {
+	HTTPProxy  string
+	HTTPSProxy string
+	NOProxy    string
+}
+
+var (
+	networkTemplate = `
+auto lo
+iface lo inet loopback
+
+auto eth0
+iface eth0 inet dhcp
+
+auto eth1
+{{if .DHCP}}iface eth1 inet dhcp{{else}}iface eth1 inet static
+address {{.IPAddress}}
+netmask 255.255.255.0{{end}}`
+
+	proxyTemplate = `
+PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin:/usr/games:/usr/local/games
+{{if .HTTPProxy}}HTTP_PROXY={{.HTTPProxy}}{{end}}
+{{if .HTTPSProxy}}HTTPS_PROXY={{.HTTPSProxy}}{{end}}
+NO_PROXY={{.NOProxy}}
+{{if .HTTPProxy}}http_proxy={{.HTTPProxy}}{{end}}
+{{if .HTTPSProxy}}https_proxy={{.HTTPSProxy}}{{end}}
+no_proxy={{.NOProxy}}`
+
+	inetRegex = regexp.MustCompile(`inet (\d+\.\d+\.\d+\.\d+)/`)
+)
+
+// ProxyCredentialsPath holds the proxy settings with their credentials, which
+// are left out of /etc/environment, since anyone on the VM can read it.
+const ProxyCredentialsPath = "/var/pcfdev/proxy.env"
+
+var restartProxiedServicesCommand = WithProxyCredentials("if [ -e /etc/init/docker.conf ] || [ -e /etc/init.d/docker ]; then service docker restart; fi; /var/vcap/bosh/bin/monit restart all")
+
+// pacDestination is the destination that a PAC file is asked about to choose
+// the proxies of the VM, which can only have one proxy for each scheme. The
+// help text of 'proxy set' names it, so keep the two in step.
+const pacDestination = "github.com"
+
+// natGatewayIP is the address at which a VM reaches the host's loopback
+// interface through its NAT adapter.
+const natGatewayIP = "10.0.2.2"
+
+func (g *Guest) InsertSecureKeypair(ctx context.Context, vmConfig *config.VMConfig) error {
+	exists, err := g.FS.Exists(g.Config.PrivateKeyPath)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return nil
+	}
+
+	privateKey, publicKey, err := g.SSH.GenerateKeypair(ssh.KeyTypeRSA)
+	if err != nil {
+		return err
+	}
+
+	// There is a new VM, so its host key is trusted on first contact.
+	if err := g.FS.Remove(g.Config.KnownHostsPath); err != nil {
+		return err
+	}
+
+	if err = g.SSH.RunSSHCommand(
+		ctx,
+		fmt.Sprintf(`echo -n "%s" > /home/vcap/.ssh/authorized_keys`, publicKey),
+		ssh.SSHAddresses(vmConfig),
+		g.Config.InsecurePrivateKey,
+		g.Config.SSHTimeout,
+		ioutil.Discard,
+		ioutil.Discard,
+	); err != nil {
+		return err
+	}
+
+	return g.writePrivateKey(privateKey)
+}
+
+// RotateKeypair replaces the secure keypair of the VM with a new one of
+// keyType, or of the type of the current one when keyType is empty. The new
+// key is authorized alongside the current one and checked before it replaces
+// the stored key, so that the VM stays reachable if any step fails.
+func (g *Guest) RotateKeypair(ctx context.Context, vmConfig *config.VMConfig, keyType string) error {
+	currentPrivateKey, err := g.FS.Read(g.Config.PrivateKeyPath)
+	if err != nil {
+		return err
+	}
+
+	if keyType == "" {
+		if keyType, err = ssh.KeyType(currentPrivateKey); err != nil {
+			return err
+		}
+	}
+
+	privateKey, publicKey, err := g.SSH.GenerateKeypair(keyType)
+	if err != nil {
+		return err
+	}
+
+	if err := g.SSH.RunSSHCommand(
+		ctx,
+		fmt.Sprintf(`echo -n "%s" >> /home/vcap/.ssh/authorized_keys`, publicKey),
+		ssh.SSHAddresses(vmConfig),
+		currentPrivateKey,
+		g.Config.SSHTimeout,
+		ioutil.Discard,
+		ioutil.Discard,
+	); err != nil {
+		return fmt.Errorf("failed to authorize the new key: %s", err)
+	}
+
+	if err := g.SSH.RunSSHCommand(ctx, "true", ssh.SSHAddresses(vmConfig), privateKey, g.Config.SSHTimeout, ioutil.Discard, ioutil.Discard); err != nil {
+		return fmt.Errorf("failed to log in with the new key: %s", err)
+	}
+
+	newPrivateKeyPath := g.Config.PrivateKeyPath + ".new"
+	if err := g.FS.Write(newPrivateKeyPath, bytes.NewReader(privateKey), false); err != nil {
+		return err
+	}
+	if err := g.FS.Chmod(newPrivateKeyPath, 0600); err != nil {
+		return err
+	}
+	if err := g.FS.Move(newPrivateKeyPath, g.Config.PrivateKeyPath); err != nil {
+		return err
+	}
+
+	if err := g.SSH.RunSSHCommand(
+		ctx,
+		fmt.Sprintf(`echo -n "%s" > /home/vcap/.ssh/authorized_keys`, publicKey),
+		ssh.SSHAddresses(vmConfig),
+		privateKey,
+		g.Config.SSHTimeout,
+		ioutil.Discard,
+		ioutil.Discard,
+	); err != nil {
+		return fmt.Errorf("failed to revoke the old key: %s", err)
+	}
+	return nil
+}
+
+func (g *Guest) writePrivateKey(privateKey []byte) error {
+	if err := g.FS.Write(g.Config.PrivateKeyPath, bytes.NewReader(privateKey), false); err != nil {
+		return err
+	}
+	return g.FS.Chmod(g.Config.PrivateKeyPath, 0600)
+}
+
+func (g *Guest) ConfigureNetwork(ctx context.Context, vmConfig *config.VMConfig) error {
+	privateKeyBytes, err := g.FS.Read(g.Config.PrivateKeyPath)
+	if err != nil {
+		return err
+	}
+
+	t, err := template.New("properties template").Parse(networkTemplate)
+	if err != nil {
+		return err
+	}
+
+	ip := vmConfig.IP
+	if vmConfig.GuestIP != "" {
+		ip = vmConfig.GuestIP
+	}
+
+	var sshCommand bytes.Buffer
+	if err = t.Execute(&sshCommand, VMProperties{
+		IPAddress: ip,
+		DHCP:      vmConfig.NetworkMode == config.NetworkModeBridged,
+	}); err != nil {
+		return err
+	}
+
+	return g.SSH.RunSSHCommandWithStdin(
+		ctx,
+		"sudo tee /etc/network/interfaces",
+		ssh.SSHAddresses(vmConfig),
+		privateKeyBytes,
+		g.Config.SSHTimeout,
+		strings.NewReader(sshCommand.String()),
+		ioutil.Discard,
+		ioutil.Discard,
+	)
+}
+
+func (g *Guest) ConfigureEnvironment(ctx context.Context, vmConfig *config.VMConfig) error {
+	proxyTypes, err := g.ProxySettings(vmConfig)
+	if err != nil {
+		return err
+	}
+
+	environment, err := g.proxySettings(&ProxyTypes{
+		HTTPProxy:  proxy.StripCredentials(proxyTypes.HTTPProxy),
+		HTTPSProxy: proxy.StripCredentials(proxyTypes.HTTPSProxy),
+		NOProxy:    proxyTypes.NOProxy,
+	})
+	if err != nil {
+		return err
+	}
+
+	privateKeyBytes, err := g.FS.Read(g.Config.PrivateKeyPath)
+	if err != nil {
+		return err
+	}
+
+	addresses := ssh.SSHAddresses(vmConfig)
+	if err := g.SSH.RunSSHCommandWithStdin(ctx, "sudo tee /etc/environment", addresses, privateKeyBytes, g.Config.SSHTimeout, strings.NewReader(environment), ioutil.Discard, ioutil.Discard); err != nil {
+		return err
+	}
+
+	credentials := proxyCredentials(proxyTypes)
+	if credentials == "" {
+		return g.SSH.RunSSHCommand(ctx, "sudo rm -f "+ProxyCredentialsPath, addresses, privateKeyBytes, g.Config.SSHTimeout, ioutil.Discard, ioutil.Discard)
+	}
+	return g.SSH.RunSSHCommandWithStdin(ctx, "sudo sh -c 'umask 077 && cat > "+ProxyCredentialsPath+"'", addresses, privateKeyBytes, g.Config.SSHTimeout, strings.NewReader(credentials), ioutil.Discard, ioutil.Discard)
+}
+
+// ProxySettings returns the proxy settings of the VM. With a PAC file, they
+// are the proxies that it chooses for pacDestination. A proxy on the host's
+// loopback address is reached through the host's address on the VM network,
+// and the VM's own addresses are always added to NO_PROXY.
+func (g *Guest) ProxySettings(vmConfig *config.VMConfig) (settings *ProxyTypes, err error) {
+	hostIP := natGatewayIP
+	if vmConfig.NetworkMode == "" || vmConfig.NetworkMode == config.NetworkModeHostOnly {
+		hostIP, err = address.SubnetForIP(vmConfig.IP)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	httpProxy, httpsProxy := g.Config.HTTPProxy, g.Config.HTTPSProxy
+	if g.Config.ProxyPAC != "" {
+		resolver := &proxy.Resolver{Config: g.Config}
+		if httpProxy, err = resolver.ProxyForURL(&url.URL{Scheme: "http", Host: pacDestination}); err != nil {
+			return nil, err
+		}
+		if httpsProxy, err = resolver.ProxyForURL(&url.URL{Scheme: "https", Host: pacDestination}); err != nil {
+			return nil, err
+		}
+	}
+
+	httpProxy = proxy.RewriteLoopback(httpProxy, hostIP)
+	httpsProxy = proxy.RewriteLoopback(httpsProxy, hostIP)
+	noProxy := strings.Join([]string{
+		"localhost",
+		"127.0.0.1",
+		hostIP,
+	}, ",")
+	if vmConfig.IP != "" {
+		noProxy = strings.Join([]string{noProxy, vmConfig.IP, vmConfig.Domain, "." + vmConfig.Domain}, ",")
+	}
+	if vmConfig.GuestIP != "" {
+		noProxy = strings.Join([]string{noProxy, vmConfig.GuestIP}, ",")
+	}
+	if g.Config.NoProxy != "" {
+		noProxy = strings.Join([]string{noProxy, g.Config.NoProxy}, ",")
+	}
+
+	return &ProxyTypes{HTTPProxy: httpProxy, HTTPSProxy: httpsProxy, NOProxy: noProxy}, nil
+}
+
+func (g *Guest) proxySettings(proxyTypes *ProxyTypes) (settings string, err error) {
+	t, err := template.New("proxy template").Parse(proxyTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	var proxySettings bytes.Buffer
+	if err = t.Execute(&proxySettings, proxyTypes); err != nil {
+		return "", err
+	}
+
+	return proxySettings.String(), nil
+}
+
+// proxyCredentials returns the lines of ProxyCredentialsPath, or "" when no
+// proxy has credentials.
+func proxyCredentials(proxyTypes *ProxyTypes) string {
+	if !proxy.HasCredentials(proxyTypes.HTTPProxy) && !proxy.HasCredentials(proxyTypes.HTTPSProxy) {
+		return ""
+	}
+
+	lines := []string{}
+	if proxyTypes.HTTPProxy != "" {
+		lines = append(lines, "HTTP_PROXY="+proxyTypes.HTTPProxy, "http_proxy="+proxyTypes.HTTPProxy)
+	}
+	if proxyTypes.HTTPSProxy != "" {
+		lines = append(lines, "HTTPS_PROXY="+proxyTypes.HTTPSProxy, "https_proxy="+proxyTypes.HTTPSProxy)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// WithProxyCredentials returns a command that runs command as root with the
+// proxy settings in ProxyCredentialsPath, if there are any. Services that are
+// started on the VM must be started this way to get the proxy credentials.
+// The command must not contain single quotes.
+func WithProxyCredentials(command string) string {
+	return "sudo -H sh -c 'if [ -e " + ProxyCredentialsPath + " ]; then set -a; . " + ProxyCredentialsPath + "; set +a; fi; " + command + "'"
+}
+
+// RestartProxiedServices restarts the services that only read the proxy
+// settings in /etc/environment when they start: the Docker daemon, and the CF
+// components, which are run by monit. They are restarted with the proxy
+// credentials, if there are any.
+func (g *Guest) RestartProxiedServices(ctx context.Context, vmConfig *config.VMConfig) error {
+	privateKeyBytes, err := g.FS.Read(g.Config.PrivateKeyPath)
+	if err != nil {
+		return err
+	}
+
+	return g.SSH.RunSSHCommand(
+		ctx,
+		restartProxiedServicesCommand,
+		ssh.SSHAddresses(vmConfig),
+		privateKeyBytes,
+		g.Config.SSHTimeout,
+		ioutil.Discard,
+		ioutil.Discard,
+	)
+}
+
+// BridgedIP waits for eth1 to be given an address by the DHCP server of the
+// LAN that the VM is bridged to, and returns that address.
+func (g *Guest) BridgedIP(ctx context.Context, vmConfig *config.VMConfig) (ip string, err error) {
+	privateKeyBytes, err := g.FS.Read(g.Config.PrivateKeyPath)
+	if err != nil {
+		return "", err
+	}
+
+	err = helpers.ExecuteWithTimeout(ctx, func() error {
+		var stdout bytes.Buffer
+		if err := g.SSH.RunSSHCommand(
+			ctx,
+			"ip -4 -o addr show dev eth1",
+			ssh.SSHAddresses(vmConfig),
+			privateKeyBytes,
+			g.Config.SSHTimeout,
+			&stdout,
+			ioutil.Discard,
+		); err != nil {
+			return err
+		}
+
+		matches := inetRegex.FindStringSubmatch(stdout.String())
+		if matches == nil {
+			return errors.New("eth1 has no address")
+		}
+		ip = matches[1]
+		return nil
+	}, 2*time.Minute, time.Second)
+	if err != nil {
+		return "", fmt.Errorf("failed to get an address for the VM on the bridged network: %s", err)
+	}
+	return ip, nil
+}
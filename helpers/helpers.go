@@ -1,6 +1,10 @@
 package helpers
 
-import "time"
+import (
+	"context"
+	"io"
+	"time"
+)
 
 func RemoveDuplicates(collection []string) []string {
 	mapping := make(map[string]bool, 0)
@@ -17,7 +21,9 @@ func RemoveDuplicates(collection []string) []string {
 	return uniqueCollection
 }
 
-func ExecuteWithAttempts(command func() error, attempts int, delay time.Duration) error {
+// ExecuteWithAttempts runs command until it succeeds, at most attempts times,
+// waiting delay between attempts. It stops waiting when ctx is done.
+func ExecuteWithAttempts(ctx context.Context, command func() error, attempts int, delay time.Duration) error {
 	var err error
 	for attempts > 0 {
 		if err = command(); err == nil {
@@ -25,12 +31,18 @@ func ExecuteWithAttempts(command func() error, attempts int, delay time.Duration
 		}
 
 		attempts = attempts - 1
-		time.Sleep(delay)
+		if attempts > 0 {
+			if sleepErr := Sleep(ctx, delay); sleepErr != nil {
+				return sleepErr
+			}
+		}
 	}
 	return err
 }
 
-func ExecuteWithTimeout(command func() error, timeout time.Duration, delay time.Duration) error {
+// ExecuteWithTimeout runs command until it succeeds or timeout passes,
+// waiting delay between attempts. It stops waiting when ctx is done.
+func ExecuteWithTimeout(ctx context.Context, command func() error, timeout time.Duration, delay time.Duration) error {
 	timeoutChan := time.After(timeout)
 	var err error
 
@@ -38,15 +50,50 @@ func ExecuteWithTimeout(command func() error, timeout time.Duration, delay time.
 		select {
 		case <-timeoutChan:
 			return err
+		case <-ctx.Done():
+			return ctx.Err()
 		default:
 			if err = command(); err == nil {
 				return nil
 			}
-			time.Sleep(delay)
+			if sleepErr := Sleep(ctx, delay); sleepErr != nil {
+				return sleepErr
+			}
 		}
 	}
 }
 
+// Sleep waits for duration, or returns the error of ctx as soon as it is done.
+func Sleep(ctx context.Context, duration time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// CloseWhenDone closes closer as soon as ctx is done, which interrupts
+// whatever is blocked on it. The returned function stops watching ctx.
+func CloseWhenDone(ctx context.Context, closer io.Closer) (stop func()) {
+	stopped := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			IgnoreErrorFrom(closer.Close())
+		case <-stopped:
+		}
+	}()
+	return func() { close(stopped) }
+}
+
 func IgnoreErrorFrom(_ ...interface{}) {
 	// Used as documentation of methods that return errors we are ignoring
 	// This makes Errcheck stop complaining.
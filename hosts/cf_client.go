@@ -0,0 +1,161 @@
+package hosts
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pivotal-cf/pcfdev-cli/tracer"
+)
+
+// CFClient reads the routes of a PCF Dev VM from its CF API, as the 'user'
+// user. Every request is sent straight to the VM IP, so that the routes can
+// be read before the hosts file has any entries for the PCF Dev domain.
+type CFClient struct {
+	Tracer  *tracer.Tracer
+	Timeout time.Duration
+	// Port is the HTTPS port of the VM. It defaults to 443.
+	Port string
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+}
+
+type routesResponse struct {
+	NextURL   *string `json:"next_url"`
+	Resources []struct {
+		Entity struct {
+			Host   string `json:"host"`
+			Domain struct {
+				Entity struct {
+					Name string `json:"name"`
+				} `json:"entity"`
+			} `json:"domain"`
+		} `json:"entity"`
+	} `json:"resources"`
+}
+
+// Hostnames returns the hostname of every route the 'user' user can read.
+// Wildcard routes are left out, since a hosts file cannot express them.
+func (c *CFClient) Hostnames(ip string, domain string) (hostnames []string, err error) {
+	httpClient := c.httpClient(ip)
+
+	token, err := c.token(httpClient, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	hostnames = []string{}
+	path := "/v2/routes?inline-relations-depth=1&results-per-page=100"
+	for path != "" {
+		routes := &routesResponse{}
+		if err := c.get(httpClient, fmt.Sprintf("https://api.%s%s", domain, path), token, routes); err != nil {
+			return nil, err
+		}
+
+		for _, resource := range routes.Resources {
+			host := resource.Entity.Host
+			routeDomain := resource.Entity.Domain.Entity.Name
+			switch {
+			case routeDomain == "" || host == "*":
+				continue
+			case host == "":
+				hostnames = append(hostnames, routeDomain)
+			default:
+				hostnames = append(hostnames, host+"."+routeDomain)
+			}
+		}
+
+		path = ""
+		if routes.NextURL != nil {
+			path = *routes.NextURL
+		}
+	}
+
+	return hostnames, nil
+}
+
+func (c *CFClient) token(httpClient *http.Client, domain string) (string, error) {
+	form := url.Values{
+		"grant_type": {"password"},
+		"username":   {"user"},
+		"password":   {"pass"},
+	}
+	req, err := http.NewRequest("POST", fmt.Sprintf("https://login.%s/oauth/token", domain), strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth("cf", "")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	token := &tokenResponse{}
+	if err := c.do(httpClient, req, token); err != nil {
+		return "", fmt.Errorf("failed to log in to PCF Dev: %s", err)
+	}
+	return token.AccessToken, nil
+}
+
+func (c *CFClient) get(httpClient *http.Client, url string, token string, result interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "bearer "+token)
+
+	if err := c.do(httpClient, req, result); err != nil {
+		return fmt.Errorf("failed to read routes from the CF API: %s", err)
+	}
+	return nil
+}
+
+func (c *CFClient) do(httpClient *http.Client, req *http.Request, result interface{}) error {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", req.URL.Host, resp.Status)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, result)
+}
+
+// httpClient returns a client that connects to ip whatever host is in the
+// URL. PCF Dev uses a self-signed certificate, so it is not verified, as with
+// 'cf login --skip-ssl-validation'.
+func (c *CFClient) httpClient(ip string) *http.Client {
+	port := c.Port
+	if port == "" {
+		port = "443"
+	}
+	dialer := &net.Dialer{Timeout: c.Timeout}
+
+	return &http.Client{
+		Timeout: c.Timeout,
+		Transport: &tracer.Transport{
+			Transport: &http.Transport{
+				Proxy: nil,
+				DialContext: func(ctx context.Context, network string, address string) (net.Conn, error) {
+					return dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+				},
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			},
+			Tracer: c.Tracer,
+		},
+	}
+}
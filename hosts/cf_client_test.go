@@ -0,0 +1,105 @@
+package hosts_test
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/hosts"
+)
+
+var _ = Describe("CFClient", func() {
+	var (
+		server   *httptest.Server
+		client   *hosts.CFClient
+		handlers map[string]http.HandlerFunc
+	)
+
+	BeforeEach(func() {
+		handlers = map[string]http.HandlerFunc{
+			"login.local.pcfdev.io/oauth/token": func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.Method).To(Equal("POST"))
+				Expect(r.ParseForm()).To(Succeed())
+				Expect(r.Form.Get("grant_type")).To(Equal("password"))
+				Expect(r.Form.Get("username")).To(Equal("user"))
+				Expect(r.Form.Get("password")).To(Equal("pass"))
+				username, _, _ := r.BasicAuth()
+				Expect(username).To(Equal("cf"))
+				fmt.Fprint(w, `{"access_token":"some-token"}`)
+			},
+			"api.local.pcfdev.io/v2/routes": func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.Header.Get("Authorization")).To(Equal("bearer some-token"))
+				if r.URL.Query().Get("page") == "2" {
+					fmt.Fprint(w, `{"next_url":null,"resources":[
+						{"entity":{"host":"","domain":{"entity":{"name":"some-private.domain"}}}}
+					]}`)
+					return
+				}
+				fmt.Fprint(w, `{"next_url":"/v2/routes?inline-relations-depth=1&results-per-page=100&page=2","resources":[
+					{"entity":{"host":"some-app","domain":{"entity":{"name":"local.pcfdev.io"}}}},
+					{"entity":{"host":"*","domain":{"entity":{"name":"local.pcfdev.io"}}}}
+				]}`)
+			},
+		}
+
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			handler, ok := handlers[r.Host+r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			handler(w, r)
+		}))
+
+		_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+		Expect(err).NotTo(HaveOccurred())
+		client = &hosts.CFClient{Port: port, Timeout: 5 * time.Second}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("#Hostnames", func() {
+		It("should return the hostname of every route on every page", func() {
+			Expect(client.Hostnames("127.0.0.1", "local.pcfdev.io")).To(Equal([]string{
+				"some-app.local.pcfdev.io",
+				"some-private.domain",
+			}))
+		})
+
+		Context("when logging in fails", func() {
+			It("should return an error", func() {
+				handlers["login.local.pcfdev.io/oauth/token"] = func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusUnauthorized)
+				}
+
+				_, err := client.Hostnames("127.0.0.1", "local.pcfdev.io")
+				Expect(err).To(MatchError("failed to log in to PCF Dev: login.local.pcfdev.io returned 401 Unauthorized"))
+			})
+		})
+
+		Context("when reading the routes fails", func() {
+			It("should return an error", func() {
+				delete(handlers, "api.local.pcfdev.io/v2/routes")
+
+				_, err := client.Hostnames("127.0.0.1", "local.pcfdev.io")
+				Expect(err).To(MatchError("failed to read routes from the CF API: api.local.pcfdev.io returned 404 Not Found"))
+			})
+		})
+
+		Context("when the VM cannot be reached", func() {
+			It("should return an error", func() {
+				server.Close()
+
+				_, err := client.Hostnames("127.0.0.1", "local.pcfdev.io")
+				Expect(err).To(MatchError(ContainSubstring("failed to log in to PCF Dev")))
+			})
+		})
+	})
+})
@@ -0,0 +1,19 @@
+package hosts
+
+import "io/ioutil"
+
+// File is the hosts file of the workstation.
+type File struct {
+	Path string
+}
+
+func (f *File) path() string {
+	if f.Path == "" {
+		return DefaultPath()
+	}
+	return f.Path
+}
+
+func (f *File) Read() (contents []byte, err error) {
+	return ioutil.ReadFile(f.path())
+}
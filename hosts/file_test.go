@@ -0,0 +1,41 @@
+package hosts_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/hosts"
+)
+
+var _ = Describe("File", func() {
+	var (
+		tempDir string
+		file    *hosts.File
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "pcfdev-hosts")
+		Expect(err).NotTo(HaveOccurred())
+		file = &hosts.File{Path: filepath.Join(tempDir, "hosts")}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should read back what it writes", func() {
+		Expect(file.Write([]byte("127.0.0.1 localhost\n"))).To(Succeed())
+		Expect(file.Read()).To(Equal([]byte("127.0.0.1 localhost\n")))
+	})
+
+	Context("when the file does not exist", func() {
+		It("should return an error", func() {
+			_, err := file.Read()
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,48 @@
+//go:build !windows
+// +build !windows
+
+package hosts
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+)
+
+func DefaultPath() string {
+	return "/etc/hosts"
+}
+
+// Write replaces the contents of the hosts file. The hosts file is usually
+// only writable by root, so when it cannot be written directly the contents
+// are copied over it with sudo, which may prompt for a password.
+func (f *File) Write(contents []byte) error {
+	err := ioutil.WriteFile(f.path(), contents, 0644)
+	if err == nil || !os.IsPermission(err) {
+		return err
+	}
+
+	tempFile, err := ioutil.TempFile("", "pcfdev-hosts")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+	defer tempFile.Close()
+
+	if _, err := tempFile.Write(contents); err != nil {
+		return err
+	}
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+
+	command := exec.Command("sudo", "cp", tempFile.Name(), f.path())
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	if err := command.Run(); err != nil {
+		return fmt.Errorf("failed to write %s with sudo: %s", f.path(), err)
+	}
+	return nil
+}
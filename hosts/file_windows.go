@@ -0,0 +1,20 @@
+package hosts
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+func DefaultPath() string {
+	return filepath.Join(os.Getenv("SystemRoot"), "System32", "drivers", "etc", "hosts")
+}
+
+func (f *File) Write(contents []byte) error {
+	err := ioutil.WriteFile(f.path(), contents, 0644)
+	if os.IsPermission(err) {
+		return fmt.Errorf("failed to write %s: run the command from a command prompt opened as administrator", f.path())
+	}
+	return err
+}
@@ -0,0 +1,72 @@
+package hosts
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// The entries written by PCF Dev are kept between these markers, so that
+// they can be replaced or removed without touching the rest of the file.
+const (
+	BeginMarker = "# BEGIN PCF Dev entries (managed by cf dev hosts)"
+	EndMarker   = "# END PCF Dev entries"
+)
+
+// SystemHostnames returns the hostnames of the PCF Dev system components that
+// are needed to log in and push apps.
+func SystemHostnames(domain string) []string {
+	return []string{
+		"api." + domain,
+		"login." + domain,
+		"uaa." + domain,
+		"apps." + domain,
+	}
+}
+
+// Render returns contents with its PCF Dev entries replaced by an entry
+// mapping each hostname to ip.
+func Render(contents []byte, ip string, hostnames []string) []byte {
+	newline := lineEnding(contents)
+	lines := []string{BeginMarker}
+	for _, hostname := range hostnames {
+		lines = append(lines, fmt.Sprintf("%s %s", ip, hostname))
+	}
+	lines = append(lines, EndMarker)
+
+	rendered := Remove(contents)
+	if len(rendered) > 0 && !bytes.HasSuffix(rendered, []byte("\n")) {
+		rendered = append(rendered, newline...)
+	}
+	return append(rendered, strings.Join(lines, newline)+newline...)
+}
+
+// Remove returns contents without its PCF Dev entries.
+func Remove(contents []byte) []byte {
+	lines := strings.SplitAfter(string(contents), "\n")
+	kept := make([]string, 0, len(lines))
+	inBlock := false
+	for _, line := range lines {
+		switch strings.TrimSpace(line) {
+		case BeginMarker:
+			inBlock = true
+			continue
+		case EndMarker:
+			if inBlock {
+				inBlock = false
+				continue
+			}
+		}
+		if !inBlock {
+			kept = append(kept, line)
+		}
+	}
+	return []byte(strings.Join(kept, ""))
+}
+
+func lineEnding(contents []byte) string {
+	if bytes.Contains(contents, []byte("\r\n")) {
+		return "\r\n"
+	}
+	return "\n"
+}
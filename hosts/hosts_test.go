@@ -0,0 +1,79 @@
+package hosts_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/hosts"
+)
+
+var _ = Describe("Hosts", func() {
+	Describe(".Render", func() {
+		It("should append the entries between markers", func() {
+			Expect(string(hosts.Render([]byte("127.0.0.1 localhost\n"), "192.168.11.11", []string{"api.local.pcfdev.io", "app.local.pcfdev.io"}))).To(Equal(
+				"127.0.0.1 localhost\n" +
+					"# BEGIN PCF Dev entries (managed by cf dev hosts)\n" +
+					"192.168.11.11 api.local.pcfdev.io\n" +
+					"192.168.11.11 app.local.pcfdev.io\n" +
+					"# END PCF Dev entries\n",
+			))
+		})
+
+		It("should replace existing entries", func() {
+			contents := "127.0.0.1 localhost\n" +
+				"# BEGIN PCF Dev entries (managed by cf dev hosts)\n" +
+				"192.168.11.11 api.local.pcfdev.io\n" +
+				"# END PCF Dev entries\n" +
+				"10.0.0.1 some-host\n"
+
+			Expect(string(hosts.Render([]byte(contents), "192.168.22.11", []string{"api.local2.pcfdev.io"}))).To(Equal(
+				"127.0.0.1 localhost\n" +
+					"10.0.0.1 some-host\n" +
+					"# BEGIN PCF Dev entries (managed by cf dev hosts)\n" +
+					"192.168.22.11 api.local2.pcfdev.io\n" +
+					"# END PCF Dev entries\n",
+			))
+		})
+
+		It("should keep Windows line endings", func() {
+			Expect(string(hosts.Render([]byte("127.0.0.1 localhost"+"\r\n"), "192.168.11.11", []string{"api.local.pcfdev.io"}))).To(Equal(
+				"127.0.0.1 localhost\r\n" +
+					"# BEGIN PCF Dev entries (managed by cf dev hosts)\r\n" +
+					"192.168.11.11 api.local.pcfdev.io\r\n" +
+					"# END PCF Dev entries\r\n",
+			))
+		})
+
+		It("should start the entries on a new line", func() {
+			Expect(string(hosts.Render([]byte("127.0.0.1 localhost"), "192.168.11.11", []string{"api.local.pcfdev.io"}))).To(HavePrefix(
+				"127.0.0.1 localhost\n# BEGIN PCF Dev entries",
+			))
+		})
+	})
+
+	Describe(".Remove", func() {
+		It("should only remove the entries between markers", func() {
+			contents := "127.0.0.1 localhost\r\n" +
+				"# BEGIN PCF Dev entries (managed by cf dev hosts)\r\n" +
+				"192.168.11.11 api.local.pcfdev.io\r\n" +
+				"# END PCF Dev entries\r\n" +
+				"# END PCF Dev entries\r\n"
+
+			Expect(string(hosts.Remove([]byte(contents)))).To(Equal("127.0.0.1 localhost\r\n# END PCF Dev entries\r\n"))
+		})
+
+		It("should leave a file without entries alone", func() {
+			Expect(string(hosts.Remove([]byte("127.0.0.1 localhost\n")))).To(Equal("127.0.0.1 localhost\n"))
+		})
+	})
+
+	Describe(".SystemHostnames", func() {
+		It("should return the hostnames of the system components", func() {
+			Expect(hosts.SystemHostnames("local.pcfdev.io")).To(Equal([]string{
+				"api.local.pcfdev.io",
+				"login.local.pcfdev.io",
+				"uaa.local.pcfdev.io",
+				"apps.local.pcfdev.io",
+			}))
+		})
+	})
+})
@@ -1,6 +1,7 @@
 package integration_test
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -312,11 +313,11 @@ var _ = Describe("PCF Dev", func() {
 		stderr := gbytes.NewBuffer()
 		sshClient := &ssh.SSH{}
 		sshPort := getForwardedPort(vBoxManagePath, "pcfdev-custom")
-		sshClient.RunSSHCommand("echo $HTTP_PROXY", []ssh.SSHAddress{{IP: "127.0.0.1", Port: sshPort}}, securePrivateKey, time.Minute, stdout, stderr)
+		sshClient.RunSSHCommand(context.Background(), "echo $HTTP_PROXY", []ssh.SSHAddress{{IP: "127.0.0.1", Port: sshPort}}, securePrivateKey, time.Minute, stdout, stderr)
 		Eventually(stdout, "30s").Should(gbytes.Say("192.168.93.23"))
-		sshClient.RunSSHCommand("echo $HTTPS_PROXY", []ssh.SSHAddress{{IP: "127.0.0.1", Port: sshPort}}, securePrivateKey, time.Minute, stdout, stderr)
+		sshClient.RunSSHCommand(context.Background(), "echo $HTTPS_PROXY", []ssh.SSHAddress{{IP: "127.0.0.1", Port: sshPort}}, securePrivateKey, time.Minute, stdout, stderr)
 		Eventually(stdout, "10s").Should(gbytes.Say("192.168.38.29"))
-		sshClient.RunSSHCommand("echo $NO_PROXY", []ssh.SSHAddress{{IP: "127.0.0.1", Port: sshPort}}, securePrivateKey, time.Minute, stdout, stderr)
+		sshClient.RunSSHCommand(context.Background(), "echo $NO_PROXY", []ssh.SSHAddress{{IP: "127.0.0.1", Port: sshPort}}, securePrivateKey, time.Minute, stdout, stderr)
 		Eventually(stdout, "10s").Should(gbytes.Say("192.168.98.98"))
 
 		response, err = getResponseFromFakeServerWithHostname("http://192.168.200.138.xip.io")
@@ -384,10 +385,10 @@ var _ = Describe("PCF Dev", func() {
 
 		sshClient := &ssh.SSH{}
 		sshPort := getForwardedPort(vBoxManagePath, "pcfdev-custom")
-		output, err := sshClient.GetSSHOutput("cat /var/pcfdev/provision-options.json", []ssh.SSHAddress{{IP: "127.0.0.1", Port: sshPort}}, securePrivateKey, time.Minute)
+		output, err := sshClient.GetSSHOutput(context.Background(), "cat /var/pcfdev/provision-options.json", []ssh.SSHAddress{{IP: "127.0.0.1", Port: sshPort}}, securePrivateKey, time.Minute)
 
 		var provisionOptions struct {
-			Services string
+			Services   string
 			Registries []string
 		}
 		Expect(json.Unmarshal([]byte(output), &provisionOptions)).To(Succeed())
@@ -0,0 +1,451 @@
+package libvirt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pivotal-cf/pcfdev-cli/address"
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/debug"
+	"github.com/pivotal-cf/pcfdev-cli/guest"
+	. "github.com/pivotal-cf/pcfdev-cli/helpers"
+	"github.com/pivotal-cf/pcfdev-cli/libvirtdriver"
+	"github.com/pivotal-cf/pcfdev-cli/network"
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
+	"github.com/pivotal-cf/pcfdev-cli/vbox"
+	"github.com/pivotal-cf/pcfdev-cli/vboxdriver"
+)
+
+//go:generate mockgen -package mocks -destination mocks/driver.go github.com/pivotal-cf/pcfdev-cli/libvirt Driver
+type Driver interface {
+	StartVM(vmName string) error
+	StopVM(ctx context.Context, vmName string) error
+	SuspendVM(ctx context.Context, vmName string) error
+	ResumeVM(vmName string) error
+	PowerOffVM(vmName string) error
+	DestroyVM(ctx context.Context, vmName string) error
+	DefineVM(xmlPath string) error
+	VMExists(vmName string) (exists bool, err error)
+	VMState(vmName string) (state string, err error)
+	VMs() (vms []string, err error)
+	GetMemory(vmName string) (memory uint64, err error)
+	ConvertDisk(src string, dst string) error
+	GetHostOnlyInterfaces() (interfaces []*network.Interface, err error)
+	CreateHostOnlyNetwork(xmlPath string, networkName string) error
+	StartNetwork(networkName string) error
+	ReserveDHCPAddress(networkName string, mac string, ip string) error
+	Version() (version *vboxdriver.VBoxDriverVersion, err error)
+	Virsh(arg ...string) (output []byte, err error)
+}
+
+//go:generate mockgen -package mocks -destination mocks/fs.go github.com/pivotal-cf/pcfdev-cli/libvirt FS
+type FS interface {
+	Exists(path string) (exists bool, err error)
+	CreateDir(path string) error
+	Extract(archivePath string, destinationPath string, filename string) error
+	Remove(path string) error
+	Write(path string, contents io.Reader, append bool) error
+	Read(path string) (contents []byte, err error)
+	Chmod(path string, mode os.FileMode) error
+	Move(source string, destination string) error
+}
+
+//go:generate mockgen -package mocks -destination mocks/ssh.go github.com/pivotal-cf/pcfdev-cli/libvirt SSH
+type SSH interface {
+	GenerateKeypair(keyType string) (privateKey []byte, publicKey []byte, err error)
+	RunSSHCommand(ctx context.Context, command string, addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration, stdout io.Writer, stderr io.Writer) error
+	RunSSHCommandWithStdin(ctx context.Context, command string, addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration, stdin io.Reader, stdout io.Writer, stderr io.Writer) error
+}
+
+//go:generate mockgen -package mocks -destination mocks/picker.go github.com/pivotal-cf/pcfdev-cli/libvirt NetworkPicker
+type NetworkPicker interface {
+	SelectAvailableInterface(networks []*network.Interface, vmConfig *config.VMConfig) (networkConfig *config.NetworkConfig, err error)
+}
+
+// Libvirt runs PCF Dev under QEMU/KVM. The VM reaches the outside world through
+// libvirt's "default" NAT network and is reached from the host through an
+// isolated pcfdev- network, so no SSH port is forwarded to the loopback address
+// and SSH goes straight to port 22 of the VM. The guest only has a static
+// address on the pcfdev- network once it has been configured over SSH, so on
+// first boot it gets that address by DHCP: the network reserves it for a MAC
+// address derived from it, which the VM's interface is given. Its vm_config is
+// kept in the directory of the VM, away from the one VirtualBox keeps in VMDir.
+type Libvirt struct {
+	Config *config.Config
+	Driver Driver
+	FS     FS
+	Picker NetworkPicker
+	SSH    SSH
+}
+
+const ProviderName = "libvirt"
+
+var (
+	networkTemplate = `<network>
+  <name>{{.Name}}</name>
+  <ip address='{{.IP}}' netmask='255.255.255.0'>
+    <dhcp>
+      <host mac='{{.VMMAC}}' ip='{{.VMIP}}'/>
+    </dhcp>
+  </ip>
+</network>`
+
+	domainTemplate = `<domain type='kvm'>
+  <name>{{.Name}}</name>
+  <memory unit='MiB'>{{.Memory}}</memory>
+  <vcpu>{{.CPUs}}</vcpu>
+  <os>
+    <type arch='x86_64'>hvm</type>
+  </os>
+  <features>
+    <acpi/>
+    <apic/>
+  </features>
+  <cpu mode='host-passthrough'/>
+  <devices>
+    <disk type='file' device='disk'>
+      <driver name='qemu' type='qcow2'/>
+      <source file='{{.DiskPath}}'/>
+      <target dev='vda' bus='virtio'/>
+    </disk>
+    <interface type='network'>
+      <source network='default'/>
+      <model type='virtio'/>
+    </interface>
+    <interface type='network'>
+      <source network='{{.Network}}'/>
+      <mac address='{{.MAC}}'/>
+      <model type='virtio'/>
+    </interface>
+    <serial type='pty'/>
+  </devices>
+</domain>`
+)
+
+type NetworkProperties struct {
+	Name  string
+	IP    string
+	VMIP  string
+	VMMAC string
+}
+
+type DomainProperties struct {
+	Name     string
+	Memory   uint64
+	CPUs     int
+	DiskPath string
+	Network  string
+	MAC      string
+}
+
+func (l *Libvirt) ImportVM(ctx context.Context, vmConfig *config.VMConfig) error {
+	if vmConfig.NetworkMode != "" && vmConfig.NetworkMode != config.NetworkModeHostOnly {
+		return fmt.Errorf("the %s network mode is not supported by the libvirt provider", vmConfig.NetworkMode)
+	}
+
+	vmDir := filepath.Join(l.Config.VMDir, vmConfig.Name)
+	if err := l.FS.CreateDir(vmDir); err != nil {
+		return err
+	}
+
+	compressedDisk := filepath.Join(vmDir, vmConfig.Name+"-disk1.vmdk")
+	disk := filepath.Join(vmDir, vmConfig.Name+"-disk1.qcow2")
+	if err := l.FS.Extract(vmConfig.OVAPath, compressedDisk, `\w+\.vmdk`); err != nil {
+		return err
+	}
+
+	if err := l.Driver.ConvertDisk(compressedDisk, disk); err != nil {
+		return err
+	}
+
+	if err := l.FS.Remove(compressedDisk); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	networks, err := l.Driver.GetHostOnlyInterfaces()
+	if err != nil {
+		return err
+	}
+
+	networkConfig, err := l.Picker.SelectAvailableInterface(networks, vmConfig)
+	if err != nil {
+		return err
+	}
+
+	mac, err := macAddress(networkConfig.VMIP)
+	if err != nil {
+		return err
+	}
+
+	if networkConfig.Interface.Exists {
+		if err := l.Driver.StartNetwork(networkConfig.Interface.Name); err != nil {
+			return err
+		}
+		if err := l.Driver.ReserveDHCPAddress(networkConfig.Interface.Name, mac, networkConfig.VMIP); err != nil {
+			return err
+		}
+	} else {
+		networkName := "pcfdev-" + networkConfig.Interface.IP
+		networkPath := filepath.Join(vmDir, "network.xml")
+		if err := l.writeTemplate(networkPath, networkTemplate, NetworkProperties{
+			Name:  networkName,
+			IP:    networkConfig.Interface.IP,
+			VMIP:  networkConfig.VMIP,
+			VMMAC: mac,
+		}); err != nil {
+			return err
+		}
+		if err := l.Driver.CreateHostOnlyNetwork(networkPath, networkName); err != nil {
+			return err
+		}
+		networkConfig.Interface.Name = networkName
+	}
+
+	if err := l.FS.Write(
+		filepath.Join(vmDir, "vm_config"),
+		strings.NewReader(fmt.Sprintf(`{"ip":"%s","domain":"%s"}`, networkConfig.VMIP, networkConfig.VMDomain)),
+		false,
+	); err != nil {
+		return err
+	}
+
+	domainPath := filepath.Join(vmDir, vmConfig.Name+".xml")
+	if err := l.writeTemplate(domainPath, domainTemplate, DomainProperties{
+		Name:     vmConfig.Name,
+		Memory:   vmConfig.Memory,
+		CPUs:     vmConfig.CPUs,
+		DiskPath: disk,
+		Network:  networkConfig.Interface.Name,
+		MAC:      mac,
+	}); err != nil {
+		return err
+	}
+
+	return l.Driver.DefineVM(domainPath)
+}
+
+func (l *Libvirt) StartVM(ctx context.Context, vmConfig *config.VMConfig) error {
+	if err := l.Driver.StartVM(vmConfig.Name); err != nil {
+		return err
+	}
+
+	g := &guest.Guest{
+		Config: l.Config,
+		FS:     l.FS,
+		SSH:    l.SSH,
+	}
+	if err := g.InsertSecureKeypair(ctx, vmConfig); err != nil {
+		return err
+	}
+
+	if err := g.ConfigureNetwork(ctx, vmConfig); err != nil {
+		return err
+	}
+	if err := g.ConfigureEnvironment(ctx, vmConfig); err != nil {
+		return err
+	}
+
+	if err := l.Driver.StopVM(ctx, vmConfig.Name); err != nil {
+		return err
+	}
+
+	return l.Driver.StartVM(vmConfig.Name)
+}
+
+func (l *Libvirt) StopVM(ctx context.Context, vmConfig *config.VMConfig) error {
+	return l.Driver.StopVM(ctx, vmConfig.Name)
+}
+
+func (l *Libvirt) SuspendVM(ctx context.Context, vmConfig *config.VMConfig) error {
+	return l.Driver.SuspendVM(ctx, vmConfig.Name)
+}
+
+func (l *Libvirt) ResumeSavedVM(vmConfig *config.VMConfig) error {
+	return l.Driver.StartVM(vmConfig.Name)
+}
+
+func (l *Libvirt) ResumePausedVM(vmConfig *config.VMConfig) error {
+	return l.Driver.ResumeVM(vmConfig.Name)
+}
+
+func (l *Libvirt) PowerOffVM(vmConfig *config.VMConfig) error {
+	return l.Driver.PowerOffVM(vmConfig.Name)
+}
+
+func (l *Libvirt) DestroyVM(ctx context.Context, vmConfig *config.VMConfig) error {
+	if err := l.Driver.DestroyVM(ctx, vmConfig.Name); err != nil {
+		return err
+	}
+	return l.FS.Remove(filepath.Join(l.Config.VMDir, vmConfig.Name))
+}
+
+func (l *Libvirt) GetVMName() (name string, err error) {
+	vms, err := l.Driver.VMs()
+	if err != nil {
+		return "", err
+	}
+	for _, vm := range vms {
+		if strings.HasPrefix(vm, "pcfdev-") {
+			if name == "" {
+				name = vm
+			} else {
+				return "", errors.New("multiple PCF Dev VMs found")
+			}
+		}
+	}
+	return name, nil
+}
+
+func (l *Libvirt) DestroyPCFDevVMs(ctx context.Context) error {
+	vms, err := l.Driver.VMs()
+	if err != nil {
+		return err
+	}
+
+	for _, vm := range vms {
+		if strings.HasPrefix(vm, "pcfdev-") {
+			IgnoreErrorFrom(l.Driver.PowerOffVM(vm))
+			IgnoreErrorFrom(l.Driver.DestroyVM(ctx, vm))
+			IgnoreErrorFrom(l.FS.Remove(filepath.Join(l.Config.VMDir, vm)))
+		}
+	}
+
+	vms, err = l.Driver.VMs()
+	if err != nil {
+		return err
+	}
+
+	for _, vm := range vms {
+		if strings.HasPrefix(vm, "pcfdev-") {
+			return errors.New("failed to destroy all pcfdev vms")
+		}
+	}
+	return nil
+}
+
+func (l *Libvirt) VMConfig(vmName string) (*config.VMConfig, error) {
+	memory, err := l.Driver.GetMemory(vmName)
+	if err != nil {
+		return nil, err
+	}
+	vmConfigBytes, err := l.FS.Read(filepath.Join(l.Config.VMDir, vmName, "vm_config"))
+	if err != nil {
+		return nil, err
+	}
+
+	vmConfig := &config.VMConfig{
+		Memory:   memory,
+		Name:     vmName,
+		Provider: ProviderName,
+	}
+	if err := json.Unmarshal(vmConfigBytes, &vmConfig); err != nil {
+		return nil, err
+	}
+	vmConfig.SSHIP = vmConfig.IP
+	vmConfig.SSHPort = "22"
+
+	return vmConfig, nil
+}
+
+func (l *Libvirt) VMStatus(vmName string) (status string, err error) {
+	exists, err := l.Driver.VMExists(vmName)
+	if err != nil {
+		return "", err
+	}
+
+	if !exists {
+		return vbox.StatusNotCreated, nil
+	}
+
+	state, err := l.Driver.VMState(vmName)
+	if err != nil {
+		return "", err
+	}
+
+	switch state {
+	case libvirtdriver.StateRunning:
+		return vbox.StatusRunning, nil
+	case libvirtdriver.StateStopped, libvirtdriver.StateCrashed:
+		return vbox.StatusStopped, nil
+	case libvirtdriver.StateSaved:
+		return vbox.StatusSaved, nil
+	case libvirtdriver.StatePaused:
+		return vbox.StatusPaused, nil
+	default:
+		return vbox.StatusUnknown, nil
+	}
+}
+
+func (l *Libvirt) Version() (version *vboxdriver.VBoxDriverVersion, err error) {
+	return l.Driver.Version()
+}
+
+type hostLogCommand struct {
+	filename string
+	args     []string
+}
+
+// HostLogs returns what libvirt reports about the VMs, the domain and its
+// pcfdev- network, for the debug logs of the VM.
+func (l *Libvirt) HostLogs(vmConfig *config.VMConfig) ([]*debug.HostLog, error) {
+	commands := []hostLogCommand{
+		{"vm-list", []string{"list", "--all"}},
+		{"vm-info", []string{"dominfo", vmConfig.Name}},
+		{"vm-interfaces", []string{"domiflist", vmConfig.Name}},
+	}
+	if vmConfig.IP != "" {
+		subnet, err := address.SubnetForIP(vmConfig.IP)
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, hostLogCommand{"vm-network", []string{"net-dumpxml", "pcfdev-" + subnet}})
+	}
+
+	logs := []*debug.HostLog{}
+	for _, command := range commands {
+		output, err := l.Driver.Virsh(command.args...)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, &debug.HostLog{Filename: command.filename, Output: output})
+	}
+	return logs, nil
+}
+
+func (l *Libvirt) writeTemplate(path string, text string, properties interface{}) error {
+	t, err := template.New(filepath.Base(path)).Parse(text)
+	if err != nil {
+		return err
+	}
+
+	var contents bytes.Buffer
+	if err := t.Execute(&contents, properties); err != nil {
+		return err
+	}
+
+	return l.FS.Write(path, &contents, false)
+}
+
+// macAddress derives the MAC address of the VM on its pcfdev- network from the
+// VM's IP, in the range of locally administered addresses used by QEMU, so
+// that a VM imported again with the same IP keeps its DHCP reservation.
+func macAddress(ip string) (string, error) {
+	ipv4 := net.ParseIP(ip).To4()
+	if ipv4 == nil {
+		return "", fmt.Errorf("%s is not an IPv4 address", ip)
+	}
+	return fmt.Sprintf("52:54:00:%02x:%02x:%02x", ipv4[1], ipv4[2], ipv4[3]), nil
+}
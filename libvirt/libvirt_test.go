@@ -0,0 +1,421 @@
+package libvirt_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/debug"
+	"github.com/pivotal-cf/pcfdev-cli/libvirt"
+	"github.com/pivotal-cf/pcfdev-cli/libvirt/mocks"
+	"github.com/pivotal-cf/pcfdev-cli/libvirtdriver"
+	"github.com/pivotal-cf/pcfdev-cli/network"
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
+	"github.com/pivotal-cf/pcfdev-cli/vbox"
+)
+
+var _ = Describe("Libvirt", func() {
+	var (
+		mockCtrl   *gomock.Controller
+		mockDriver *mocks.MockDriver
+		mockFS     *mocks.MockFS
+		mockSSH    *mocks.MockSSH
+		mockPicker *mocks.MockNetworkPicker
+		lv         *libvirt.Libvirt
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockDriver = mocks.NewMockDriver(mockCtrl)
+		mockFS = mocks.NewMockFS(mockCtrl)
+		mockSSH = mocks.NewMockSSH(mockCtrl)
+		mockPicker = mocks.NewMockNetworkPicker(mockCtrl)
+
+		lv = &libvirt.Libvirt{
+			Config: &config.Config{
+				SSHTimeout:         7 * time.Minute,
+				VMDir:              "some-vm-dir",
+				InsecurePrivateKey: []byte("some-insecure-private-key"),
+				PrivateKeyPath:     "some-private-key-path",
+				KnownHostsPath:     "some-known-hosts-path",
+			},
+			Driver: mockDriver,
+			FS:     mockFS,
+			SSH:    mockSSH,
+			Picker: mockPicker,
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("#ImportVM", func() {
+		var (
+			vmConfig   *config.VMConfig
+			vmDir      string
+			domainXML  string
+			networkXML string
+		)
+
+		BeforeEach(func() {
+			vmConfig = &config.VMConfig{
+				Name:    "pcfdev-some-vm",
+				OVAPath: "some-ova-path",
+				Memory:  uint64(4096),
+				CPUs:    2,
+			}
+			vmDir = filepath.Join("some-vm-dir", "pcfdev-some-vm")
+		})
+
+		captureWrite := func(contents *string) func(string, io.Reader, bool) error {
+			return func(_ string, reader io.Reader, _ bool) error {
+				data, err := ioutil.ReadAll(reader)
+				*contents = string(data)
+				return err
+			}
+		}
+
+		It("should convert the disk, create a host-only network and define the domain", func() {
+			networks := []*network.Interface{}
+			gomock.InOrder(
+				mockFS.EXPECT().CreateDir(vmDir),
+				mockFS.EXPECT().Extract("some-ova-path", filepath.Join(vmDir, "pcfdev-some-vm-disk1.vmdk"), `\w+\.vmdk`),
+				mockDriver.EXPECT().ConvertDisk(filepath.Join(vmDir, "pcfdev-some-vm-disk1.vmdk"), filepath.Join(vmDir, "pcfdev-some-vm-disk1.qcow2")),
+				mockFS.EXPECT().Remove(filepath.Join(vmDir, "pcfdev-some-vm-disk1.vmdk")),
+				mockDriver.EXPECT().GetHostOnlyInterfaces().Return(networks, nil),
+				mockPicker.EXPECT().SelectAvailableInterface(networks, vmConfig).Return(&config.NetworkConfig{
+					Interface: &network.Interface{IP: "192.168.11.1"},
+					VMIP:      "192.168.11.11",
+					VMDomain:  "local.pcfdev.io",
+				}, nil),
+				mockFS.EXPECT().Write(filepath.Join(vmDir, "network.xml"), gomock.Any(), false).Do(captureWrite(&networkXML)),
+				mockDriver.EXPECT().CreateHostOnlyNetwork(filepath.Join(vmDir, "network.xml"), "pcfdev-192.168.11.1"),
+				mockFS.EXPECT().Write(filepath.Join(vmDir, "vm_config"), strings.NewReader(`{"ip":"192.168.11.11","domain":"local.pcfdev.io"}`), false),
+				mockFS.EXPECT().Write(filepath.Join(vmDir, "pcfdev-some-vm.xml"), gomock.Any(), false).Do(captureWrite(&domainXML)),
+				mockDriver.EXPECT().DefineVM(filepath.Join(vmDir, "pcfdev-some-vm.xml")),
+			)
+
+			Expect(lv.ImportVM(context.Background(), vmConfig)).To(Succeed())
+
+			Expect(networkXML).To(ContainSubstring("<name>pcfdev-192.168.11.1</name>"))
+			Expect(networkXML).To(ContainSubstring("<ip address='192.168.11.1' netmask='255.255.255.0'>"))
+			Expect(networkXML).To(ContainSubstring("<host mac='52:54:00:a8:0b:0b' ip='192.168.11.11'/>"))
+			Expect(domainXML).To(ContainSubstring("<name>pcfdev-some-vm</name>"))
+			Expect(domainXML).To(ContainSubstring("<memory unit='MiB'>4096</memory>"))
+			Expect(domainXML).To(ContainSubstring("<vcpu>2</vcpu>"))
+			Expect(domainXML).To(ContainSubstring("<source file='" + filepath.Join(vmDir, "pcfdev-some-vm-disk1.qcow2") + "'/>"))
+			Expect(domainXML).To(ContainSubstring("<source network='default'/>"))
+			Expect(domainXML).To(ContainSubstring("<source network='pcfdev-192.168.11.1'/>\n      <mac address='52:54:00:a8:0b:0b'/>"))
+		})
+
+		Context("when a network mode other than hostonly is requested", func() {
+			It("should return an error", func() {
+				vmConfig.NetworkMode = "bridged"
+				Expect(lv.ImportVM(context.Background(), vmConfig)).To(MatchError("the bridged network mode is not supported by the libvirt provider"))
+			})
+		})
+
+		Context("when the picked network already exists", func() {
+			It("should start it and reserve the VM's address instead of creating a new one", func() {
+				networks := []*network.Interface{{Name: "pcfdev-192.168.11.1", IP: "192.168.11.1", Exists: true}}
+				gomock.InOrder(
+					mockFS.EXPECT().CreateDir(vmDir),
+					mockFS.EXPECT().Extract(gomock.Any(), gomock.Any(), gomock.Any()),
+					mockDriver.EXPECT().ConvertDisk(gomock.Any(), gomock.Any()),
+					mockFS.EXPECT().Remove(gomock.Any()),
+					mockDriver.EXPECT().GetHostOnlyInterfaces().Return(networks, nil),
+					mockPicker.EXPECT().SelectAvailableInterface(networks, vmConfig).Return(&config.NetworkConfig{
+						Interface: networks[0],
+						VMIP:      "192.168.11.11",
+						VMDomain:  "local.pcfdev.io",
+					}, nil),
+					mockDriver.EXPECT().StartNetwork("pcfdev-192.168.11.1"),
+					mockDriver.EXPECT().ReserveDHCPAddress("pcfdev-192.168.11.1", "52:54:00:a8:0b:0b", "192.168.11.11"),
+					mockFS.EXPECT().Write(filepath.Join(vmDir, "vm_config"), gomock.Any(), false),
+					mockFS.EXPECT().Write(filepath.Join(vmDir, "pcfdev-some-vm.xml"), gomock.Any(), false).Do(captureWrite(&domainXML)),
+					mockDriver.EXPECT().DefineVM(filepath.Join(vmDir, "pcfdev-some-vm.xml")),
+				)
+
+				Expect(lv.ImportVM(context.Background(), vmConfig)).To(Succeed())
+				Expect(domainXML).To(ContainSubstring("<source network='pcfdev-192.168.11.1'/>\n      <mac address='52:54:00:a8:0b:0b'/>"))
+			})
+		})
+
+		Context("when reserving the VM's address on the picked network fails", func() {
+			It("should return the error", func() {
+				networks := []*network.Interface{{Name: "pcfdev-192.168.11.1", IP: "192.168.11.1", Exists: true}}
+				gomock.InOrder(
+					mockFS.EXPECT().CreateDir(vmDir),
+					mockFS.EXPECT().Extract(gomock.Any(), gomock.Any(), gomock.Any()),
+					mockDriver.EXPECT().ConvertDisk(gomock.Any(), gomock.Any()),
+					mockFS.EXPECT().Remove(gomock.Any()),
+					mockDriver.EXPECT().GetHostOnlyInterfaces().Return(networks, nil),
+					mockPicker.EXPECT().SelectAvailableInterface(networks, vmConfig).Return(&config.NetworkConfig{
+						Interface: networks[0],
+						VMIP:      "192.168.11.11",
+						VMDomain:  "local.pcfdev.io",
+					}, nil),
+					mockDriver.EXPECT().StartNetwork("pcfdev-192.168.11.1"),
+					mockDriver.EXPECT().ReserveDHCPAddress("pcfdev-192.168.11.1", "52:54:00:a8:0b:0b", "192.168.11.11").Return(errors.New("some-error")),
+				)
+
+				Expect(lv.ImportVM(context.Background(), vmConfig)).To(MatchError("some-error"))
+			})
+		})
+
+		Context("when converting the disk fails", func() {
+			It("should return the error", func() {
+				gomock.InOrder(
+					mockFS.EXPECT().CreateDir(vmDir),
+					mockFS.EXPECT().Extract(gomock.Any(), gomock.Any(), gomock.Any()),
+					mockDriver.EXPECT().ConvertDisk(gomock.Any(), gomock.Any()).Return(errors.New("some-error")),
+				)
+
+				Expect(lv.ImportVM(context.Background(), vmConfig)).To(MatchError("some-error"))
+			})
+		})
+
+		Context("when picking a network fails", func() {
+			It("should return the error", func() {
+				gomock.InOrder(
+					mockFS.EXPECT().CreateDir(vmDir),
+					mockFS.EXPECT().Extract(gomock.Any(), gomock.Any(), gomock.Any()),
+					mockDriver.EXPECT().ConvertDisk(gomock.Any(), gomock.Any()),
+					mockFS.EXPECT().Remove(gomock.Any()),
+					mockDriver.EXPECT().GetHostOnlyInterfaces().Return(nil, nil),
+					mockPicker.EXPECT().SelectAvailableInterface(nil, vmConfig).Return(nil, errors.New("some-error")),
+				)
+
+				Expect(lv.ImportVM(context.Background(), vmConfig)).To(MatchError("some-error"))
+			})
+		})
+	})
+
+	Describe("#StartVM", func() {
+		It("should start the VM, configure the guest and restart it", func() {
+			vmConfig := &config.VMConfig{Name: "some-vm", IP: "192.168.11.11", Domain: "local.pcfdev.io"}
+			gomock.InOrder(
+				mockDriver.EXPECT().StartVM("some-vm"),
+				mockFS.EXPECT().Exists("some-private-key-path").Return(true, nil),
+				mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
+				mockSSH.EXPECT().RunSSHCommandWithStdin(gomock.Any(), "sudo tee /etc/network/interfaces", gomock.Any(), []byte("some-private-key"), 7*time.Minute, gomock.Any(), ioutil.Discard, ioutil.Discard),
+				mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
+				mockSSH.EXPECT().RunSSHCommandWithStdin(gomock.Any(), "sudo tee /etc/environment", gomock.Any(), []byte("some-private-key"), 7*time.Minute, gomock.Any(), ioutil.Discard, ioutil.Discard),
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), "sudo rm -f /var/pcfdev/proxy.env", gomock.Any(), []byte("some-private-key"), 7*time.Minute, ioutil.Discard, ioutil.Discard),
+				mockDriver.EXPECT().StopVM(gomock.Any(), "some-vm"),
+				mockDriver.EXPECT().StartVM("some-vm"),
+			)
+
+			Expect(lv.StartVM(context.Background(), vmConfig)).To(Succeed())
+		})
+
+		Context("when the VM is started for the first time", func() {
+			It("should authorize a new key over SSH to the address reserved for the VM", func() {
+				vmConfig := &config.VMConfig{Name: "some-vm", IP: "192.168.11.11", Domain: "local.pcfdev.io", SSHIP: "192.168.11.11", SSHPort: "22"}
+				addresses := []ssh.SSHAddress{{IP: "192.168.11.11", Port: "22"}}
+				gomock.InOrder(
+					mockDriver.EXPECT().StartVM("some-vm"),
+					mockFS.EXPECT().Exists("some-private-key-path").Return(false, nil),
+					mockSSH.EXPECT().GenerateKeypair(ssh.KeyTypeRSA).Return([]byte("some-private-key"), []byte("some-public-key"), nil),
+					mockFS.EXPECT().Remove("some-known-hosts-path"),
+					mockSSH.EXPECT().RunSSHCommand(gomock.Any(), `echo -n "some-public-key" > /home/vcap/.ssh/authorized_keys`, addresses, []byte("some-insecure-private-key"), 7*time.Minute, ioutil.Discard, ioutil.Discard),
+					mockFS.EXPECT().Write("some-private-key-path", bytes.NewReader([]byte("some-private-key")), false),
+					mockFS.EXPECT().Chmod("some-private-key-path", os.FileMode(0600)),
+					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
+					mockSSH.EXPECT().RunSSHCommandWithStdin(gomock.Any(), "sudo tee /etc/network/interfaces", addresses, []byte("some-private-key"), 7*time.Minute, gomock.Any(), ioutil.Discard, ioutil.Discard),
+					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
+					mockSSH.EXPECT().RunSSHCommandWithStdin(gomock.Any(), "sudo tee /etc/environment", addresses, []byte("some-private-key"), 7*time.Minute, gomock.Any(), ioutil.Discard, ioutil.Discard),
+					mockSSH.EXPECT().RunSSHCommand(gomock.Any(), "sudo rm -f /var/pcfdev/proxy.env", addresses, []byte("some-private-key"), 7*time.Minute, ioutil.Discard, ioutil.Discard),
+					mockDriver.EXPECT().StopVM(gomock.Any(), "some-vm"),
+					mockDriver.EXPECT().StartVM("some-vm"),
+				)
+
+				Expect(lv.StartVM(context.Background(), vmConfig)).To(Succeed())
+			})
+		})
+
+		Context("when starting the VM fails", func() {
+			It("should return the error", func() {
+				mockDriver.EXPECT().StartVM("some-vm").Return(errors.New("some-error"))
+
+				Expect(lv.StartVM(context.Background(), &config.VMConfig{Name: "some-vm"})).To(MatchError("some-error"))
+			})
+		})
+	})
+
+	Describe("lifecycle", func() {
+		It("should delegate to the driver", func() {
+			vmConfig := &config.VMConfig{Name: "some-vm"}
+			gomock.InOrder(
+				mockDriver.EXPECT().StopVM(gomock.Any(), "some-vm"),
+				mockDriver.EXPECT().SuspendVM(gomock.Any(), "some-vm"),
+				mockDriver.EXPECT().StartVM("some-vm"),
+				mockDriver.EXPECT().ResumeVM("some-vm"),
+				mockDriver.EXPECT().PowerOffVM("some-vm"),
+			)
+
+			Expect(lv.StopVM(context.Background(), vmConfig)).To(Succeed())
+			Expect(lv.SuspendVM(context.Background(), vmConfig)).To(Succeed())
+			Expect(lv.ResumeSavedVM(vmConfig)).To(Succeed())
+			Expect(lv.ResumePausedVM(vmConfig)).To(Succeed())
+			Expect(lv.PowerOffVM(vmConfig)).To(Succeed())
+		})
+	})
+
+	Describe("#DestroyVM", func() {
+		It("should undefine the domain and remove its files", func() {
+			gomock.InOrder(
+				mockDriver.EXPECT().DestroyVM(gomock.Any(), "some-vm"),
+				mockFS.EXPECT().Remove(filepath.Join("some-vm-dir", "some-vm")),
+			)
+
+			Expect(lv.DestroyVM(context.Background(), &config.VMConfig{Name: "some-vm"})).To(Succeed())
+		})
+
+		Context("when undefining the domain fails", func() {
+			It("should return the error", func() {
+				mockDriver.EXPECT().DestroyVM(gomock.Any(), "some-vm").Return(errors.New("some-error"))
+
+				Expect(lv.DestroyVM(context.Background(), &config.VMConfig{Name: "some-vm"})).To(MatchError("some-error"))
+			})
+		})
+	})
+
+	Describe("#GetVMName", func() {
+		It("should return the name of the PCF Dev VM", func() {
+			mockDriver.EXPECT().VMs().Return([]string{"some-other-vm", "pcfdev-some-vm"}, nil)
+
+			Expect(lv.GetVMName()).To(Equal("pcfdev-some-vm"))
+		})
+
+		Context("when there are multiple PCF Dev VMs", func() {
+			It("should return an error", func() {
+				mockDriver.EXPECT().VMs().Return([]string{"pcfdev-some-vm", "pcfdev-some-other-vm"}, nil)
+
+				_, err := lv.GetVMName()
+				Expect(err).To(MatchError("multiple PCF Dev VMs found"))
+			})
+		})
+	})
+
+	Describe("#DestroyPCFDevVMs", func() {
+		It("should destroy all PCF Dev VMs", func() {
+			gomock.InOrder(
+				mockDriver.EXPECT().VMs().Return([]string{"some-other-vm", "pcfdev-some-vm"}, nil),
+				mockDriver.EXPECT().PowerOffVM("pcfdev-some-vm"),
+				mockDriver.EXPECT().DestroyVM(gomock.Any(), "pcfdev-some-vm"),
+				mockFS.EXPECT().Remove(filepath.Join("some-vm-dir", "pcfdev-some-vm")),
+				mockDriver.EXPECT().VMs().Return([]string{"some-other-vm"}, nil),
+			)
+
+			Expect(lv.DestroyPCFDevVMs(context.Background())).To(Succeed())
+		})
+
+		Context("when a PCF Dev VM is left behind", func() {
+			It("should return an error", func() {
+				gomock.InOrder(
+					mockDriver.EXPECT().VMs().Return([]string{"pcfdev-some-vm"}, nil),
+					mockDriver.EXPECT().PowerOffVM("pcfdev-some-vm").Return(errors.New("some-error")),
+					mockDriver.EXPECT().DestroyVM(gomock.Any(), "pcfdev-some-vm").Return(errors.New("some-error")),
+					mockFS.EXPECT().Remove(filepath.Join("some-vm-dir", "pcfdev-some-vm")),
+					mockDriver.EXPECT().VMs().Return([]string{"pcfdev-some-vm"}, nil),
+				)
+
+				Expect(lv.DestroyPCFDevVMs(context.Background())).To(MatchError("failed to destroy all pcfdev vms"))
+			})
+		})
+	})
+
+	Describe("#VMConfig", func() {
+		It("should return the config of the VM, reached over SSH on port 22 of the VM", func() {
+			gomock.InOrder(
+				mockDriver.EXPECT().GetMemory("some-vm").Return(uint64(4096), nil),
+				mockFS.EXPECT().Read(filepath.Join("some-vm-dir", "some-vm", "vm_config")).Return([]byte(`{"ip":"192.168.11.11","domain":"local.pcfdev.io"}`), nil),
+			)
+
+			Expect(lv.VMConfig("some-vm")).To(Equal(&config.VMConfig{
+				Name:     "some-vm",
+				Memory:   uint64(4096),
+				IP:       "192.168.11.11",
+				Domain:   "local.pcfdev.io",
+				SSHIP:    "192.168.11.11",
+				SSHPort:  "22",
+				Provider: "libvirt",
+			}))
+		})
+
+		Context("when reading the memory fails", func() {
+			It("should return the error", func() {
+				mockDriver.EXPECT().GetMemory("some-vm").Return(uint64(0), errors.New("some-error"))
+
+				_, err := lv.VMConfig("some-vm")
+				Expect(err).To(MatchError("some-error"))
+			})
+		})
+	})
+
+	Describe("#HostLogs", func() {
+		It("should return what libvirt reports about the VM and its network", func() {
+			gomock.InOrder(
+				mockDriver.EXPECT().Virsh("list", "--all").Return([]byte("some-vm-list"), nil),
+				mockDriver.EXPECT().Virsh("dominfo", "some-vm").Return([]byte("some-vm-info"), nil),
+				mockDriver.EXPECT().Virsh("domiflist", "some-vm").Return([]byte("some-vm-interfaces"), nil),
+				mockDriver.EXPECT().Virsh("net-dumpxml", "pcfdev-192.168.11.1").Return([]byte("some-vm-network"), nil),
+			)
+
+			Expect(lv.HostLogs(&config.VMConfig{Name: "some-vm", IP: "192.168.11.11"})).To(Equal([]*debug.HostLog{
+				{Filename: "vm-list", Output: []byte("some-vm-list")},
+				{Filename: "vm-info", Output: []byte("some-vm-info")},
+				{Filename: "vm-interfaces", Output: []byte("some-vm-interfaces")},
+				{Filename: "vm-network", Output: []byte("some-vm-network")},
+			}))
+		})
+
+		Context("when virsh fails", func() {
+			It("should return the error", func() {
+				mockDriver.EXPECT().Virsh("list", "--all").Return(nil, errors.New("some-error"))
+
+				_, err := lv.HostLogs(&config.VMConfig{Name: "some-vm", IP: "192.168.11.11"})
+				Expect(err).To(MatchError("some-error"))
+			})
+		})
+	})
+
+	Describe("#VMStatus", func() {
+		It("should map the domain state to a VM status", func() {
+			states := map[string]string{
+				libvirtdriver.StateRunning: vbox.StatusRunning,
+				libvirtdriver.StateStopped: vbox.StatusStopped,
+				libvirtdriver.StateCrashed: vbox.StatusStopped,
+				libvirtdriver.StateSaved:   vbox.StatusSaved,
+				libvirtdriver.StatePaused:  vbox.StatusPaused,
+				"some-state":               vbox.StatusUnknown,
+			}
+			for state, status := range states {
+				mockDriver.EXPECT().VMExists("some-vm").Return(true, nil)
+				mockDriver.EXPECT().VMState("some-vm").Return(state, nil)
+
+				Expect(lv.VMStatus("some-vm")).To(Equal(status))
+			}
+		})
+
+		Context("when the VM does not exist", func() {
+			It("should return not created", func() {
+				mockDriver.EXPECT().VMExists("some-vm").Return(false, nil)
+
+				Expect(lv.VMStatus("some-vm")).To(Equal(vbox.StatusNotCreated))
+			})
+		})
+	})
+})
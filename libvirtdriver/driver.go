@@ -0,0 +1,312 @@
+package libvirtdriver
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pivotal-cf/pcfdev-cli/helpers"
+	"github.com/pivotal-cf/pcfdev-cli/network"
+	"github.com/pivotal-cf/pcfdev-cli/vboxdriver"
+)
+
+//go:generate mockgen -package mocks -destination mocks/cmd_runner.go github.com/pivotal-cf/pcfdev-cli/libvirtdriver CmdRunner
+type CmdRunner interface {
+	Run(command string, args ...string) (output []byte, err error)
+}
+
+type LibvirtDriver struct {
+	CmdRunner CmdRunner
+	URI       string
+}
+
+const (
+	StateRunning = "running"
+	StatePaused  = "paused"
+	StateStopped = "shut off"
+	StateCrashed = "crashed"
+	StateSaved   = "saved"
+)
+
+type networkXML struct {
+	MAC struct {
+		Address string `xml:"address,attr"`
+	} `xml:"mac"`
+	IPs []struct {
+		Address   string `xml:"address,attr"`
+		DHCPHosts []struct {
+			MAC string `xml:"mac,attr"`
+			IP  string `xml:"ip,attr"`
+		} `xml:"dhcp>host"`
+	} `xml:"ip"`
+}
+
+func (d *LibvirtDriver) Virsh(arg ...string) (output []byte, err error) {
+	if d.URI != "" {
+		arg = append([]string{"-c", d.URI}, arg...)
+	}
+	return d.CmdRunner.Run("virsh", arg...)
+}
+
+func (d *LibvirtDriver) StartVM(vmName string) error {
+	_, err := d.Virsh("start", vmName)
+	return err
+}
+
+func (d *LibvirtDriver) DefineVM(xmlPath string) error {
+	_, err := d.Virsh("define", xmlPath)
+	return err
+}
+
+func (d *LibvirtDriver) VMExists(vmName string) (exists bool, err error) {
+	vms, err := d.VMs()
+	if err != nil {
+		return false, err
+	}
+
+	for _, vm := range vms {
+		if vm == vmName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (d *LibvirtDriver) VMState(vmName string) (string, error) {
+	output, err := d.Virsh("dominfo", vmName)
+	if err != nil {
+		return "", err
+	}
+
+	regex := regexp.MustCompile(`(?m:^State:\s+(.*))`)
+	matches := regex.FindStringSubmatch(string(output))
+	if len(matches) <= 1 {
+		return "", fmt.Errorf("no state identified for VM")
+	}
+	state := strings.TrimSpace(matches[1])
+
+	if state == StateStopped && regexp.MustCompile(`(?m:^Managed save:\s+yes)`).Match(output) {
+		return StateSaved, nil
+	}
+	return state, nil
+}
+
+func (d *LibvirtDriver) StopVM(ctx context.Context, vmName string) error {
+	if _, err := d.Virsh("shutdown", vmName); err != nil {
+		return err
+	}
+
+	return helpers.ExecuteWithTimeout(ctx, func() error {
+		state, err := d.VMState(vmName)
+		if err != nil {
+			return fmt.Errorf("timed out waiting for vm to stop: %s", err)
+		}
+		if state != StateStopped {
+			return fmt.Errorf("timed out waiting for vm to stop")
+		}
+		return nil
+	},
+		time.Minute,
+		time.Second,
+	)
+}
+
+func (d *LibvirtDriver) SuspendVM(ctx context.Context, vmName string) error {
+	_, err := d.Virsh("managedsave", vmName)
+	return err
+}
+
+func (d *LibvirtDriver) ResumeVM(vmName string) error {
+	_, err := d.Virsh("resume", vmName)
+	return err
+}
+
+func (d *LibvirtDriver) PowerOffVM(vmName string) error {
+	_, err := d.Virsh("destroy", vmName)
+	return err
+}
+
+func (d *LibvirtDriver) DestroyVM(ctx context.Context, vmName string) error {
+	_, err := d.Virsh("undefine", vmName, "--managed-save", "--remove-all-storage")
+	return err
+}
+
+func (d *LibvirtDriver) VMs() ([]string, error) {
+	output, err := d.Virsh("list", "--all", "--name")
+	if err != nil {
+		return nil, err
+	}
+
+	vms := []string{}
+	for _, line := range strings.Split(string(output), "\n") {
+		if vm := strings.TrimSpace(line); vm != "" {
+			vms = append(vms, vm)
+		}
+	}
+	return vms, nil
+}
+
+func (d *LibvirtDriver) GetMemory(vmName string) (uint64, error) {
+	output, err := d.Virsh("dominfo", vmName)
+	if err != nil {
+		return uint64(0), err
+	}
+
+	regex := regexp.MustCompile(`(?m:^Max memory:\s+(\d+) KiB)`)
+	if matches := regex.FindStringSubmatch(string(output)); len(matches) > 1 {
+		memory, err := strconv.ParseUint(matches[1], 10, 64)
+		if err != nil {
+			return uint64(0), err
+		}
+		return memory / 1024, nil
+	}
+
+	return uint64(0), fmt.Errorf("failed to determine VM memory for '%s'", vmName)
+}
+
+func (d *LibvirtDriver) ConvertDisk(src string, dst string) error {
+	_, err := d.CmdRunner.Run("qemu-img", "convert", "-O", "qcow2", src, dst)
+	return err
+}
+
+func (d *LibvirtDriver) CreateHostOnlyNetwork(xmlPath string, networkName string) error {
+	if _, err := d.Virsh("net-define", xmlPath); err != nil {
+		return err
+	}
+	if _, err := d.Virsh("net-autostart", networkName); err != nil {
+		return err
+	}
+	_, err := d.Virsh("net-start", networkName)
+	return err
+}
+
+func (d *LibvirtDriver) StartNetwork(networkName string) error {
+	output, err := d.Virsh("net-info", networkName)
+	if err != nil {
+		return err
+	}
+
+	if regexp.MustCompile(`(?m:^Active:\s+yes)`).Match(output) {
+		return nil
+	}
+
+	_, err = d.Virsh("net-start", networkName)
+	return err
+}
+
+// ReserveDHCPAddress has the running network hand out ip by DHCP to the VM
+// with the MAC address mac, unless it already does.
+func (d *LibvirtDriver) ReserveDHCPAddress(networkName string, mac string, ip string) error {
+	output, err := d.Virsh("net-dumpxml", networkName)
+	if err != nil {
+		return err
+	}
+
+	definition := &networkXML{}
+	if err := xml.Unmarshal(output, definition); err != nil {
+		return fmt.Errorf("failed to parse network %s: %s", networkName, err)
+	}
+	for _, networkIP := range definition.IPs {
+		for _, host := range networkIP.DHCPHosts {
+			if strings.EqualFold(host.MAC, mac) && host.IP == ip {
+				return nil
+			}
+		}
+	}
+
+	_, err = d.Virsh("net-update", networkName, "add-last", "ip-dhcp-host", fmt.Sprintf("<host mac='%s' ip='%s'/>", mac, ip), "--live", "--config")
+	return err
+}
+
+func (d *LibvirtDriver) GetHostOnlyInterfaces() (interfaces []*network.Interface, err error) {
+	output, err := d.Virsh("net-list", "--all", "--name")
+	if err != nil {
+		return nil, err
+	}
+
+	interfaces = []*network.Interface{}
+	for _, line := range strings.Split(string(output), "\n") {
+		networkName := strings.TrimSpace(line)
+		if !strings.HasPrefix(networkName, "pcfdev-") {
+			continue
+		}
+
+		xmlOutput, err := d.Virsh("net-dumpxml", networkName)
+		if err != nil {
+			return nil, err
+		}
+
+		definition := &networkXML{}
+		if err := xml.Unmarshal(xmlOutput, definition); err != nil {
+			return nil, fmt.Errorf("failed to parse network %s: %s", networkName, err)
+		}
+		if len(definition.IPs) == 0 {
+			continue
+		}
+
+		interfaces = append(interfaces, &network.Interface{
+			Name:            networkName,
+			IP:              definition.IPs[0].Address,
+			HardwareAddress: definition.MAC.Address,
+			Exists:          true,
+		})
+	}
+
+	return interfaces, nil
+}
+
+func (d *LibvirtDriver) IsInterfaceInUse(interfaceName string) (bool, error) {
+	vms, err := d.VMs()
+	if err != nil {
+		return false, err
+	}
+
+	for _, vm := range vms {
+		output, err := d.Virsh("domiflist", vm)
+		if err != nil {
+			return false, err
+		}
+
+		for _, line := range strings.Split(string(output), "\n") {
+			if fields := strings.Fields(line); len(fields) > 2 && fields[1] == "network" && fields[2] == interfaceName {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// NetworkModel reports that libvirt networks, like VirtualBox host-only
+// interfaces, can use any address range.
+func (d *LibvirtDriver) NetworkModel() (string, error) {
+	return network.ModelHostOnlyInterface, nil
+}
+
+func (d *LibvirtDriver) Version() (*vboxdriver.VBoxDriverVersion, error) {
+	output, err := d.Virsh("--version")
+	if err != nil {
+		return nil, err
+	}
+
+	regex := regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)`)
+	if matches := regex.FindStringSubmatch(strings.TrimSpace(string(output))); len(matches) > 3 {
+		majorVersion, errMajor := strconv.Atoi(matches[1])
+		minorVersion, errMinor := strconv.Atoi(matches[2])
+		buildVersion, errBuild := strconv.Atoi(matches[3])
+
+		if errMajor == nil && errMinor == nil && errBuild == nil {
+			return &vboxdriver.VBoxDriverVersion{
+				Major: majorVersion,
+				Minor: minorVersion,
+				Build: buildVersion,
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("failed to parse version from 'virsh --version': %s", string(output))
+}
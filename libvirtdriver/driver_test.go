@@ -0,0 +1,306 @@
+package libvirtdriver_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/libvirtdriver"
+	"github.com/pivotal-cf/pcfdev-cli/libvirtdriver/mocks"
+	"github.com/pivotal-cf/pcfdev-cli/network"
+	"github.com/pivotal-cf/pcfdev-cli/vboxdriver"
+)
+
+var _ = Describe("LibvirtDriver", func() {
+	var (
+		mockCtrl      *gomock.Controller
+		mockCmdRunner *mocks.MockCmdRunner
+		driver        *libvirtdriver.LibvirtDriver
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockCmdRunner = mocks.NewMockCmdRunner(mockCtrl)
+		driver = &libvirtdriver.LibvirtDriver{
+			CmdRunner: mockCmdRunner,
+			URI:       "qemu:///system",
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("#Virsh", func() {
+		It("should run virsh against the configured URI", func() {
+			mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "some-arg").Return([]byte("some-output"), nil)
+
+			Expect(driver.Virsh("some-arg")).To(Equal([]byte("some-output")))
+		})
+
+		Context("when no URI is configured", func() {
+			It("should let virsh pick the URI", func() {
+				driver.URI = ""
+				mockCmdRunner.EXPECT().Run("virsh", "some-arg").Return([]byte("some-output"), nil)
+
+				Expect(driver.Virsh("some-arg")).To(Equal([]byte("some-output")))
+			})
+		})
+	})
+
+	Describe("#VMs", func() {
+		It("should return the names of all domains", func() {
+			mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "list", "--all", "--name").Return([]byte("pcfdev-some-vm\nsome-other-vm\n\n"), nil)
+
+			Expect(driver.VMs()).To(Equal([]string{"pcfdev-some-vm", "some-other-vm"}))
+		})
+
+		Context("when virsh fails", func() {
+			It("should return the error", func() {
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "list", "--all", "--name").Return(nil, errors.New("some-error"))
+
+				_, err := driver.VMs()
+				Expect(err).To(MatchError("some-error"))
+			})
+		})
+	})
+
+	Describe("#VMExists", func() {
+		It("should return whether the domain exists", func() {
+			mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "list", "--all", "--name").Return([]byte("pcfdev-some-vm\n"), nil).Times(2)
+
+			Expect(driver.VMExists("pcfdev-some-vm")).To(BeTrue())
+			Expect(driver.VMExists("pcfdev-some-other-vm")).To(BeFalse())
+		})
+	})
+
+	Describe("#VMState", func() {
+		It("should return the state of the domain", func() {
+			mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "dominfo", "some-vm").Return([]byte("Name:           some-vm\nState:          running\nManaged save:   no\n"), nil)
+
+			Expect(driver.VMState("some-vm")).To(Equal(libvirtdriver.StateRunning))
+		})
+
+		Context("when a stopped domain has a managed save image", func() {
+			It("should return saved", func() {
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "dominfo", "some-vm").Return([]byte("State:          shut off\nManaged save:   yes\n"), nil)
+
+				Expect(driver.VMState("some-vm")).To(Equal(libvirtdriver.StateSaved))
+			})
+		})
+
+		Context("when there is no state in the output", func() {
+			It("should return an error", func() {
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "dominfo", "some-vm").Return([]byte("some-output"), nil)
+
+				_, err := driver.VMState("some-vm")
+				Expect(err).To(MatchError("no state identified for VM"))
+			})
+		})
+	})
+
+	Describe("#StopVM", func() {
+		It("should shut down the domain and wait for it to stop", func() {
+			gomock.InOrder(
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "shutdown", "some-vm"),
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "dominfo", "some-vm").Return([]byte("State:          shut off\n"), nil),
+			)
+
+			Expect(driver.StopVM(context.Background(), "some-vm")).To(Succeed())
+		})
+
+		Context("when the shutdown fails", func() {
+			It("should return the error", func() {
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "shutdown", "some-vm").Return(nil, errors.New("some-error"))
+
+				Expect(driver.StopVM(context.Background(), "some-vm")).To(MatchError("some-error"))
+			})
+		})
+	})
+
+	Describe("lifecycle commands", func() {
+		It("should run the matching virsh commands", func() {
+			gomock.InOrder(
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "start", "some-vm"),
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "managedsave", "some-vm"),
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "resume", "some-vm"),
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "destroy", "some-vm"),
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "undefine", "some-vm", "--managed-save", "--remove-all-storage"),
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "define", "some-xml-path"),
+			)
+
+			Expect(driver.StartVM("some-vm")).To(Succeed())
+			Expect(driver.SuspendVM(context.Background(), "some-vm")).To(Succeed())
+			Expect(driver.ResumeVM("some-vm")).To(Succeed())
+			Expect(driver.PowerOffVM("some-vm")).To(Succeed())
+			Expect(driver.DestroyVM(context.Background(), "some-vm")).To(Succeed())
+			Expect(driver.DefineVM("some-xml-path")).To(Succeed())
+		})
+	})
+
+	Describe("#GetMemory", func() {
+		It("should return the memory of the domain in megabytes", func() {
+			mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "dominfo", "some-vm").Return([]byte("Max memory:     4194304 KiB\nUsed memory:    4194304 KiB\n"), nil)
+
+			Expect(driver.GetMemory("some-vm")).To(Equal(uint64(4096)))
+		})
+
+		Context("when there is no memory in the output", func() {
+			It("should return an error", func() {
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "dominfo", "some-vm").Return([]byte("some-output"), nil)
+
+				_, err := driver.GetMemory("some-vm")
+				Expect(err).To(MatchError("failed to determine VM memory for 'some-vm'"))
+			})
+		})
+	})
+
+	Describe("#ConvertDisk", func() {
+		It("should convert the disk to qcow2", func() {
+			mockCmdRunner.EXPECT().Run("qemu-img", "convert", "-O", "qcow2", "some-src", "some-dst")
+
+			Expect(driver.ConvertDisk("some-src", "some-dst")).To(Succeed())
+		})
+	})
+
+	Describe("#CreateHostOnlyNetwork", func() {
+		It("should define, autostart and start the network", func() {
+			gomock.InOrder(
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "net-define", "some-xml-path"),
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "net-autostart", "some-network"),
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "net-start", "some-network"),
+			)
+
+			Expect(driver.CreateHostOnlyNetwork("some-xml-path", "some-network")).To(Succeed())
+		})
+	})
+
+	Describe("#StartNetwork", func() {
+		It("should start an inactive network", func() {
+			gomock.InOrder(
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "net-info", "some-network").Return([]byte("Name:           some-network\nActive:         no\n"), nil),
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "net-start", "some-network"),
+			)
+
+			Expect(driver.StartNetwork("some-network")).To(Succeed())
+		})
+
+		Context("when the network is already active", func() {
+			It("should do nothing", func() {
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "net-info", "some-network").Return([]byte("Active:         yes\n"), nil)
+
+				Expect(driver.StartNetwork("some-network")).To(Succeed())
+			})
+		})
+	})
+
+	Describe("#ReserveDHCPAddress", func() {
+		It("should add a DHCP host to the network", func() {
+			gomock.InOrder(
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "net-dumpxml", "some-network").Return([]byte(`<network>
+  <name>some-network</name>
+  <ip address='192.168.11.1' netmask='255.255.255.0'/>
+</network>`), nil),
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "net-update", "some-network", "add-last", "ip-dhcp-host", "<host mac='52:54:00:a8:0b:0b' ip='192.168.11.11'/>", "--live", "--config"),
+			)
+
+			Expect(driver.ReserveDHCPAddress("some-network", "52:54:00:a8:0b:0b", "192.168.11.11")).To(Succeed())
+		})
+
+		Context("when the network already has the DHCP host", func() {
+			It("should do nothing", func() {
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "net-dumpxml", "some-network").Return([]byte(`<network>
+  <name>some-network</name>
+  <ip address='192.168.11.1' netmask='255.255.255.0'>
+    <dhcp>
+      <host mac='52:54:00:a8:0b:0b' ip='192.168.11.11'/>
+    </dhcp>
+  </ip>
+</network>`), nil)
+
+				Expect(driver.ReserveDHCPAddress("some-network", "52:54:00:a8:0b:0b", "192.168.11.11")).To(Succeed())
+			})
+		})
+
+		Context("when updating the network fails", func() {
+			It("should return the error", func() {
+				gomock.InOrder(
+					mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "net-dumpxml", "some-network").Return([]byte(`<network/>`), nil),
+					mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "net-update", "some-network", "add-last", "ip-dhcp-host", "<host mac='52:54:00:a8:0b:0b' ip='192.168.11.11'/>", "--live", "--config").Return(nil, errors.New("some-error")),
+				)
+
+				Expect(driver.ReserveDHCPAddress("some-network", "52:54:00:a8:0b:0b", "192.168.11.11")).To(MatchError("some-error"))
+			})
+		})
+	})
+
+	Describe("#GetHostOnlyInterfaces", func() {
+		It("should return the PCF Dev networks", func() {
+			gomock.InOrder(
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "net-list", "--all", "--name").Return([]byte("default\npcfdev-192.168.11.1\n"), nil),
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "net-dumpxml", "pcfdev-192.168.11.1").Return([]byte(`<network>
+  <name>pcfdev-192.168.11.1</name>
+  <mac address='52:54:00:aa:bb:cc'/>
+  <ip address='192.168.11.1' netmask='255.255.255.0'/>
+</network>`), nil),
+			)
+
+			Expect(driver.GetHostOnlyInterfaces()).To(Equal([]*network.Interface{
+				{
+					Name:            "pcfdev-192.168.11.1",
+					IP:              "192.168.11.1",
+					HardwareAddress: "52:54:00:aa:bb:cc",
+					Exists:          true,
+				},
+			}))
+		})
+
+		Context("when the network definition is malformed", func() {
+			It("should return an error", func() {
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "net-list", "--all", "--name").Return([]byte("pcfdev-some-network\n"), nil)
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "net-dumpxml", "pcfdev-some-network").Return([]byte("<network"), nil)
+
+				_, err := driver.GetHostOnlyInterfaces()
+				Expect(err).To(MatchError(ContainSubstring("failed to parse network pcfdev-some-network")))
+			})
+		})
+	})
+
+	Describe("#IsInterfaceInUse", func() {
+		BeforeEach(func() {
+			mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "list", "--all", "--name").Return([]byte("some-vm\n"), nil)
+			mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "domiflist", "some-vm").Return([]byte(` Interface   Type      Source               Model    MAC
+------------------------------------------------------------------------
+ vnet0       network   default              virtio   52:54:00:00:00:01
+ vnet1       network   pcfdev-192.168.11.1  virtio   52:54:00:00:00:02
+`), nil)
+		})
+
+		It("should return true when a domain is attached to the network", func() {
+			Expect(driver.IsInterfaceInUse("pcfdev-192.168.11.1")).To(BeTrue())
+		})
+
+		It("should return false when no domain is attached to the network", func() {
+			Expect(driver.IsInterfaceInUse("pcfdev-192.168.22.1")).To(BeFalse())
+		})
+	})
+
+	Describe("#Version", func() {
+		It("should return the libvirt version", func() {
+			mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "--version").Return([]byte("6.0.0\n"), nil)
+
+			Expect(driver.Version()).To(Equal(&vboxdriver.VBoxDriverVersion{Major: 6, Minor: 0, Build: 0}))
+		})
+
+		Context("when the version cannot be parsed", func() {
+			It("should return an error", func() {
+				mockCmdRunner.EXPECT().Run("virsh", "-c", "qemu:///system", "--version").Return([]byte("some-version"), nil)
+
+				_, err := driver.Version()
+				Expect(err).To(MatchError("failed to parse version from 'virsh --version': some-version"))
+			})
+		})
+	})
+})
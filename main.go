@@ -11,18 +11,33 @@ import (
 	"github.com/cloudfoundry/cli/cf/trace"
 	"github.com/pivotal-cf/pcfdev-cli/address"
 	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/debug"
+	"github.com/pivotal-cf/pcfdev-cli/dns"
 	"github.com/pivotal-cf/pcfdev-cli/downloader"
+	"github.com/pivotal-cf/pcfdev-cli/dryrun"
 	"github.com/pivotal-cf/pcfdev-cli/exit"
 	"github.com/pivotal-cf/pcfdev-cli/fs"
+	"github.com/pivotal-cf/pcfdev-cli/guest"
+	"github.com/pivotal-cf/pcfdev-cli/helpers"
+	"github.com/pivotal-cf/pcfdev-cli/hosts"
+	"github.com/pivotal-cf/pcfdev-cli/libvirt"
+	"github.com/pivotal-cf/pcfdev-cli/libvirtdriver"
+	"github.com/pivotal-cf/pcfdev-cli/manifest"
+	"github.com/pivotal-cf/pcfdev-cli/mirror"
 	"github.com/pivotal-cf/pcfdev-cli/network"
 	"github.com/pivotal-cf/pcfdev-cli/pivnet"
 	"github.com/pivotal-cf/pcfdev-cli/plugin"
 	"github.com/pivotal-cf/pcfdev-cli/plugin/cmd"
+	"github.com/pivotal-cf/pcfdev-cli/provider"
+	"github.com/pivotal-cf/pcfdev-cli/proxy"
 	"github.com/pivotal-cf/pcfdev-cli/runner"
+	"github.com/pivotal-cf/pcfdev-cli/socks"
 	"github.com/pivotal-cf/pcfdev-cli/ssh"
 	"github.com/pivotal-cf/pcfdev-cli/system"
+	"github.com/pivotal-cf/pcfdev-cli/tracer"
 	"github.com/pivotal-cf/pcfdev-cli/ui"
 	"github.com/pivotal-cf/pcfdev-cli/vbox"
+	"github.com/pivotal-cf/pcfdev-cli/vbox/fake"
 	"github.com/pivotal-cf/pcfdev-cli/vm"
 
 	"github.com/cloudfoundry/cli/cf/terminal"
@@ -39,6 +54,8 @@ var (
 	releaseId          string
 	productFileId      string
 	md5                string
+	sha256             string
+	manifestPublicKey  string
 	vmName             string
 	insecurePrivateKey string
 )
@@ -53,14 +70,26 @@ func main() {
 
 	confirmInstalled(cfui)
 
+	pcfdevTracer, err := tracer.New(os.Getenv("PCFDEV_TRACE"), &debug.SensitiveInformationScrubber{})
+	if err != nil {
+		cfui.Failed("Error: %s", err)
+		os.Exit(1)
+	}
+	cmdRunner := &tracer.TracingCmdRunner{
+		CmdRunner: &runner.CmdRunner{},
+		Tracer:    pcfdevTracer,
+	}
+
 	fileSystem := &fs.FS{}
 	driver := &vboxdriver.VBoxDriver{
 		FS:        fileSystem,
-		CmdRunner: &runner.CmdRunner{},
+		CmdRunner: cmdRunner,
 	}
 	conf, err := config.New(
 		vmName,
 		md5,
+		sha256,
+		manifestPublicKey,
 		[]byte(insecurePrivateKey),
 		&system.System{
 			FS: fileSystem,
@@ -79,69 +108,241 @@ func main() {
 		FS:     fileSystem,
 		UI:     cfui,
 	}
+	proxyResolver := &proxy.Resolver{Config: conf}
+	pivnetTransport := &tracer.Transport{
+		Transport: &http.Transport{
+			Proxy:               proxyResolver.Proxy,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+		Tracer: pcfdevTracer,
+	}
 	client := &pivnet.Client{
 		Host:          "https://network.pivotal.io",
 		ReleaseId:     releaseId,
 		ProductFileId: productFileId,
 		Token:         token,
+		Transport:     pivnetTransport,
 	}
 	token.Client = client
+	var (
+		eulaClient     cmd.Client        = client
+		ovaSource      downloader.Client = client
+		ovaToken       downloader.Token  = token
+		manifestClient cmd.ManifestClient
+	)
+	if conf.OVASource != "" {
+		mirrorSource, err := mirror.New(conf.OVASource, conf.S3Endpoint, pivnetTransport)
+		if err != nil {
+			cfui.Failed("Error: %s", err)
+			os.Exit(1)
+		}
+		eulaClient = &mirror.EULA{FS: fileSystem, AcceptedPath: conf.EULAAcceptedPath}
+		ovaSource = mirrorSource
+		ovaToken = nil
+		manifestClient = mirrorSource
+	}
 	sshClient := &ssh.SSH{
 		Terminal: &ssh.TerminalWrapper{},
 		WindowResizer: &ssh.ConcreteWindowResizer{
 			DoneChannel: make(chan bool),
 		},
+		Tracer:     pcfdevTracer,
+		KnownHosts: &ssh.KnownHosts{Path: conf.KnownHostsPath},
 	}
-	vbx := &vbox.VBox{
-		SSH:    sshClient,
-		FS:     fileSystem,
-		Driver: driver,
-		Picker: &address.Picker{
-			Network: &network.Network{},
-			Driver:  driver,
-		},
-		Config: conf,
+	vbx := &provider.Selector{
+		Providers: providers(conf, driver, cmdRunner, fileSystem, sshClient),
+		Default:   conf.Provider,
 	}
 	httpClientIgnoringEnvironmentProxies := &http.Client{
-		Transport: &http.Transport{
-			Proxy: nil,
+		Transport: &tracer.Transport{
+			Transport: &http.Transport{
+				Proxy: nil,
+			},
+			Tracer: pcfdevTracer,
 		},
 	}
+	downloaderFactory := &downloader.DownloaderFactory{
+		PivnetClient:         ovaSource,
+		FS:                   fileSystem,
+		Token:                ovaToken,
+		Config:               conf,
+		DownloadAttempts:     10,
+		DownloadAttemptDelay: time.Second,
+	}
+	pcfdevClient := &vmClient.Client{
+		Timeout:       time.Second * 20,
+		TunnelTimeout: conf.APITunnelTimeout,
+		HttpClient:    httpClientIgnoringEnvironmentProxies,
+		SSHClient:     sshClient,
+	}
+	vmGuest := &guest.Guest{
+		Config: conf,
+		FS:     fileSystem,
+		SSH:    sshClient,
+	}
+	ovaVerifier := &manifest.Verifier{
+		FS:        fileSystem,
+		PublicKey: conf.ManifestPublicKey,
+	}
+	routeLister := &hosts.CFClient{
+		Tracer:  pcfdevTracer,
+		Timeout: 20 * time.Second,
+	}
 	cfplugin.Start(&plugin.Plugin{
 		UI:     &plugin.NonTranslatingUI{cfui},
 		Config: conf,
 		Exit:   &exit.Exit{},
 		CmdBuilder: &cmd.Builder{
-			Client: client,
-			Config: conf,
-			DownloaderFactory: &downloader.DownloaderFactory{
-				PivnetClient:         client,
-				FS:                   fileSystem,
-				Token:                token,
-				Config:               conf,
-				DownloadAttempts:     10,
-				DownloadAttemptDelay: time.Second,
-			},
-			EULAUI: &ui.UI{},
-			FS:     fileSystem,
-			UI:     cfui,
-			VBox:   vbx,
+			Client:            eulaClient,
+			CmdRunner:         cmdRunner,
+			Config:            conf,
+			DNSServer:         &dns.Server{},
+			DownloaderFactory: downloaderFactory,
+			EULAUI:            &ui.UI{},
+			FS:                fileSystem,
+			Guest:             vmGuest,
+			HostsFile:         &hosts.File{},
+			ManifestClient:    manifestClient,
+			OVAVerifier:       ovaVerifier,
+			RouteLister:       routeLister,
+			SOCKSServer:       &socks.Server{},
+			SSH:               sshClient,
+			UI:                cfui,
+			VBox:              vbx,
 			VMBuilder: &vm.VBoxBuilder{
-				VBox:   vbx,
-				Config: conf,
-				FS:     fileSystem,
-				SSH:    sshClient,
-				UI:     &plugin.NonTranslatingUI{cfui},
-				Client: &vmClient.Client{
-					Timeout:    time.Second * 20,
-					HttpClient: httpClientIgnoringEnvironmentProxies,
-					SSHClient:  sshClient,
-				},
+				VBox:      vbx,
+				Config:    conf,
+				FS:        fileSystem,
+				SSH:       sshClient,
+				UI:        &plugin.NonTranslatingUI{cfui},
+				Client:    pcfdevClient,
+				CmdRunner: cmdRunner,
 			},
 		},
+		DryRunCmdBuilder: dryRunCmdBuilder(cfui, conf, driver, cmdRunner, fileSystem, sshClient, downloaderFactory, pcfdevClient, ovaVerifier),
 	})
 }
 
+func dryRunCmdBuilder(
+	cfui terminal.UI,
+	conf *config.Config,
+	driver *vboxdriver.VBoxDriver,
+	realCmdRunner dryrun.Runner,
+	fileSystem *fs.FS,
+	sshClient *ssh.SSH,
+	downloaderFactory *downloader.DownloaderFactory,
+	pcfdevClient *vmClient.Client,
+	ovaVerifier *manifest.Verifier,
+) *cmd.Builder {
+	recorder := &dryrun.Recorder{UI: cfui}
+	cmdRunner := &dryrun.CmdRunner{
+		Recorder: recorder,
+		Driver:   fake.NewDriver(),
+		Source:   driver,
+		Runner:   realCmdRunner,
+	}
+	overlayFS := &dryrun.OverlayFS{
+		Recorder: recorder,
+		FS:       fileSystem,
+	}
+	dryRunSSH := &dryrun.SSH{
+		Recorder: recorder,
+		SSH:      sshClient,
+	}
+	dryRunDriver := &vboxdriver.VBoxDriver{
+		FS:        fileSystem,
+		CmdRunner: cmdRunner,
+	}
+	vbx := &provider.Selector{
+		Providers: providers(conf, dryRunDriver, cmdRunner, overlayFS, dryRunSSH),
+		Default:   conf.Provider,
+	}
+
+	return &cmd.Builder{
+		Client:    &dryrun.PivnetClient{Recorder: recorder},
+		CmdRunner: cmdRunner,
+		Config:    conf,
+		DownloaderFactory: &dryrun.RecordingDownloaderFactory{
+			Recorder:          recorder,
+			DownloaderFactory: downloaderFactory,
+			Config:            conf,
+		},
+		EULAUI:      &ui.UI{},
+		FS:          overlayFS,
+		OVAVerifier: ovaVerifier,
+		UI:          cfui,
+		VBox:        vbx,
+		VMBuilder: &vm.VBoxBuilder{
+			VBox:   vbx,
+			Config: conf,
+			FS:     overlayFS,
+			SSH:    dryRunSSH,
+			UI:     &plugin.NonTranslatingUI{cfui},
+			Client: &dryrun.VMClient{
+				Recorder:  recorder,
+				Client:    pcfdevClient,
+				CmdRunner: cmdRunner,
+			},
+			CmdRunner: cmdRunner,
+		},
+	}
+}
+
+// providerFS and providerSSH are what both the VirtualBox and libvirt
+// providers need of the file system and SSH client.
+type providerFS interface {
+	vbox.FS
+	libvirt.FS
+}
+
+type providerSSH interface {
+	vbox.SSH
+	libvirt.SSH
+}
+
+// providers returns the VM providers whose tools are installed, along with the
+// configured one, so that its absence is reported when it is used.
+func providers(
+	conf *config.Config,
+	driver *vboxdriver.VBoxDriver,
+	cmdRunner libvirtdriver.CmdRunner,
+	fileSystem providerFS,
+	sshClient providerSSH,
+) map[string]provider.Provider {
+	providers := map[string]provider.Provider{}
+	if _, err := helpers.VBoxManagePath(); err == nil || conf.Provider == vbox.ProviderName {
+		providers[vbox.ProviderName] = &vbox.VBox{
+			SSH:    sshClient,
+			FS:     fileSystem,
+			Driver: driver,
+			Picker: &address.Picker{
+				Network:    &network.Network{},
+				Driver:     driver,
+				SubnetPool: conf.SubnetPool,
+			},
+			Config: conf,
+		}
+	}
+	if _, err := exec.LookPath("virsh"); err == nil || conf.Provider == libvirt.ProviderName {
+		libvirtDriver := &libvirtdriver.LibvirtDriver{
+			CmdRunner: cmdRunner,
+			URI:       "qemu:///system",
+		}
+		providers[libvirt.ProviderName] = &libvirt.Libvirt{
+			SSH:    sshClient,
+			FS:     fileSystem,
+			Driver: libvirtDriver,
+			Picker: &address.Picker{
+				Network:    &network.Network{},
+				Driver:     libvirtDriver,
+				SubnetPool: conf.SubnetPool,
+			},
+			Config: conf,
+		}
+	}
+	return providers
+}
+
 func confirmInstalled(ui terminal.UI) {
 	var firstArg string
 	if len(os.Args) > 1 {
@@ -0,0 +1,32 @@
+package manifest
+
+import "fmt"
+
+type InvalidSignatureError struct {
+	Path string
+}
+
+func (e *InvalidSignatureError) Error() string {
+	return fmt.Sprintf("the signature of %s is not valid for the key this version of the cf CLI plugin was built with", e.Path)
+}
+
+type MismatchError struct {
+	Path     string
+	Field    string
+	Expected string
+	Actual   string
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("%s does not match its manifest: its %s is %s, but the manifest expects %s", e.Path, e.Field, e.Actual, e.Expected)
+}
+
+type VersionMismatchError struct {
+	Path     string
+	Expected string
+	Actual   string
+}
+
+func (e *VersionMismatchError) Error() string {
+	return fmt.Sprintf("%s is for OVA version %s, but this version of the cf CLI plugin expects OVA version %s", e.Path, e.Actual, e.Expected)
+}
@@ -0,0 +1,110 @@
+package manifest
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Manifest describes an OVA. Whoever builds the OVA writes the manifest next
+// to it, as <ova>.manifest, and signs it with an Ed25519 key. The signature
+// is the base64 of the raw signature over the manifest file, in
+// <ova>.manifest.sig.
+type Manifest struct {
+	Version string `json:"version"`
+	Size    int64  `json:"size"`
+	MD5     string `json:"md5"`
+	SHA256  string `json:"sha256"`
+}
+
+type FS interface {
+	Read(path string) (contents []byte, err error)
+	Open(path string) (io.ReadCloser, error)
+}
+
+// Verifier checks OVAs against their signed manifests. PublicKey is the base64
+// of the raw Ed25519 public key that the plugin was built with.
+type Verifier struct {
+	FS        FS
+	PublicKey string
+}
+
+// Verify checks the OVA at ovaPath against its signed manifest, which must be
+// for expectedVersion unless that is empty, as it is for custom OVAs.
+func (v *Verifier) Verify(ctx context.Context, ovaPath string, expectedVersion string) (*Manifest, error) {
+	publicKey, err := base64.StdEncoding.DecodeString(v.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return nil, errors.New("this version of the cf CLI plugin was not built with a key to verify OVA manifests")
+	}
+
+	manifestPath := ovaPath + ".manifest"
+	contents, err := v.FS.Read(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the manifest of %s: %s", ovaPath, err)
+	}
+	encodedSignature, err := v.FS.Read(manifestPath + ".sig")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the signature of %s: %s", manifestPath, err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encodedSignature)))
+	if err != nil || !ed25519.Verify(ed25519.PublicKey(publicKey), contents, signature) {
+		return nil, &InvalidSignatureError{Path: manifestPath}
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(contents, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %s", manifestPath, err)
+	}
+	if manifest.SHA256 == "" {
+		return nil, fmt.Errorf("failed to parse %s: it has no sha256", manifestPath)
+	}
+	if expectedVersion != "" && manifest.Version != expectedVersion {
+		return nil, &VersionMismatchError{Path: manifestPath, Expected: expectedVersion, Actual: manifest.Version}
+	}
+
+	ova, err := v.FS.Open(ovaPath)
+	if err != nil {
+		return nil, err
+	}
+	defer ova.Close()
+
+	md5Hash := md5.New()
+	sha256Hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(md5Hash, sha256Hash), &contextReader{ctx: ctx, reader: ova})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", ovaPath, err)
+	}
+
+	if size != manifest.Size {
+		return nil, &MismatchError{Path: ovaPath, Field: "size", Expected: fmt.Sprintf("%d bytes", manifest.Size), Actual: fmt.Sprintf("%d bytes", size)}
+	}
+	if actual := fmt.Sprintf("%x", sha256Hash.Sum(nil)); actual != strings.ToLower(manifest.SHA256) {
+		return nil, &MismatchError{Path: ovaPath, Field: "sha256", Expected: manifest.SHA256, Actual: actual}
+	}
+	if actual := fmt.Sprintf("%x", md5Hash.Sum(nil)); manifest.MD5 != "" && actual != strings.ToLower(manifest.MD5) {
+		return nil, &MismatchError{Path: ovaPath, Field: "md5", Expected: manifest.MD5, Actual: actual}
+	}
+
+	return manifest, nil
+}
+
+// contextReader stops reading once the context is done, so that hashing a
+// large OVA can be interrupted.
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.reader.Read(p)
+}
@@ -0,0 +1,187 @@
+package manifest_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pivotal-cf/pcfdev-cli/fs"
+	"github.com/pivotal-cf/pcfdev-cli/manifest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Verifier", func() {
+	var (
+		tmpDir     string
+		ovaPath    string
+		privateKey ed25519.PrivateKey
+		verifier   *manifest.Verifier
+	)
+
+	writeManifest := func(contents string) {
+		Expect(ioutil.WriteFile(ovaPath+".manifest", []byte(contents), 0644)).To(Succeed())
+		signature := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, []byte(contents)))
+		Expect(ioutil.WriteFile(ovaPath+".manifest.sig", []byte(signature+"\n"), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "pcfdev-manifest")
+		Expect(err).NotTo(HaveOccurred())
+		ovaPath = filepath.Join(tmpDir, "some.ova")
+		Expect(ioutil.WriteFile(ovaPath, []byte("some-contents"), 0644)).To(Succeed())
+
+		var publicKey ed25519.PublicKey
+		publicKey, privateKey, err = ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+
+		verifier = &manifest.Verifier{
+			FS:        &fs.FS{},
+			PublicKey: base64.StdEncoding.EncodeToString(publicKey),
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	Describe("#Verify", func() {
+		It("should return the manifest of an OVA that matches it", func() {
+			writeManifest(`{"version":"some-version","size":13,"md5":"0b9791ad102b5f5f06ef68cef2aae26e","sha256":"6e32ea34db1b3755d7dec972eb72c705338f0dd8e0be881d966963438fb2e800"}`)
+
+			Expect(verifier.Verify(context.Background(), ovaPath, "some-version")).To(Equal(&manifest.Manifest{
+				Version: "some-version",
+				Size:    13,
+				MD5:     "0b9791ad102b5f5f06ef68cef2aae26e",
+				SHA256:  "6e32ea34db1b3755d7dec972eb72c705338f0dd8e0be881d966963438fb2e800",
+			}))
+		})
+
+		Context("when the manifest has no MD5", func() {
+			It("should only check the SHA-256 and size", func() {
+				writeManifest(`{"version":"some-version","size":13,"sha256":"6e32ea34db1b3755d7dec972eb72c705338f0dd8e0be881d966963438fb2e800"}`)
+
+				_, err := verifier.Verify(context.Background(), ovaPath, "some-version")
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("when the SHA-256 does not match", func() {
+			It("should return an error", func() {
+				writeManifest(`{"version":"some-version","size":13,"sha256":"some-other-sha256"}`)
+
+				_, err := verifier.Verify(context.Background(), ovaPath, "some-version")
+				Expect(err).To(MatchError(ovaPath + " does not match its manifest: its sha256 is 6e32ea34db1b3755d7dec972eb72c705338f0dd8e0be881d966963438fb2e800, but the manifest expects some-other-sha256"))
+			})
+		})
+
+		Context("when the MD5 does not match", func() {
+			It("should return an error", func() {
+				writeManifest(`{"version":"some-version","size":13,"md5":"some-other-md5","sha256":"6e32ea34db1b3755d7dec972eb72c705338f0dd8e0be881d966963438fb2e800"}`)
+
+				_, err := verifier.Verify(context.Background(), ovaPath, "some-version")
+				Expect(err).To(MatchError(ovaPath + " does not match its manifest: its md5 is 0b9791ad102b5f5f06ef68cef2aae26e, but the manifest expects some-other-md5"))
+			})
+		})
+
+		Context("when the size does not match", func() {
+			It("should return an error", func() {
+				writeManifest(`{"version":"some-version","size":12,"sha256":"6e32ea34db1b3755d7dec972eb72c705338f0dd8e0be881d966963438fb2e800"}`)
+
+				_, err := verifier.Verify(context.Background(), ovaPath, "some-version")
+				Expect(err).To(MatchError(ovaPath + " does not match its manifest: its size is 13 bytes, but the manifest expects 12 bytes"))
+			})
+		})
+
+		Context("when the manifest has been changed since it was signed", func() {
+			It("should return an error", func() {
+				writeManifest(`{"version":"some-version","size":13,"sha256":"some-sha256"}`)
+				Expect(ioutil.WriteFile(ovaPath+".manifest", []byte(`{"version":"some-version","size":13,"sha256":"6e32ea34db1b3755d7dec972eb72c705338f0dd8e0be881d966963438fb2e800"}`), 0644)).To(Succeed())
+
+				_, err := verifier.Verify(context.Background(), ovaPath, "some-version")
+				Expect(err).To(MatchError(&manifest.InvalidSignatureError{Path: ovaPath + ".manifest"}))
+			})
+		})
+
+		Context("when the manifest was signed with another key", func() {
+			It("should return an error", func() {
+				_, privateKey, _ = ed25519.GenerateKey(rand.Reader)
+				writeManifest(`{"version":"some-version","size":13,"sha256":"6e32ea34db1b3755d7dec972eb72c705338f0dd8e0be881d966963438fb2e800"}`)
+
+				_, err := verifier.Verify(context.Background(), ovaPath, "some-version")
+				Expect(err).To(MatchError(fmt.Sprintf("the signature of %s.manifest is not valid for the key this version of the cf CLI plugin was built with", ovaPath)))
+			})
+		})
+
+		Context("when there is no manifest", func() {
+			It("should return an error", func() {
+				_, err := verifier.Verify(context.Background(), ovaPath, "some-version")
+				Expect(err).To(MatchError(ContainSubstring("failed to read the manifest of " + ovaPath)))
+			})
+		})
+
+		Context("when there is no signature", func() {
+			It("should return an error", func() {
+				Expect(ioutil.WriteFile(ovaPath+".manifest", []byte(`{}`), 0644)).To(Succeed())
+
+				_, err := verifier.Verify(context.Background(), ovaPath, "some-version")
+				Expect(err).To(MatchError(ContainSubstring("failed to read the signature of " + ovaPath + ".manifest")))
+			})
+		})
+
+		Context("when the manifest has no SHA-256", func() {
+			It("should return an error", func() {
+				writeManifest(`{"version":"some-version","size":13,"md5":"0b9791ad102b5f5f06ef68cef2aae26e"}`)
+
+				_, err := verifier.Verify(context.Background(), ovaPath, "some-version")
+				Expect(err).To(MatchError(fmt.Sprintf("failed to parse %s.manifest: it has no sha256", ovaPath)))
+			})
+		})
+
+		Context("when the manifest is for another OVA version", func() {
+			It("should return an error", func() {
+				writeManifest(`{"version":"some-other-version","size":13,"sha256":"6e32ea34db1b3755d7dec972eb72c705338f0dd8e0be881d966963438fb2e800"}`)
+
+				_, err := verifier.Verify(context.Background(), ovaPath, "some-version")
+				Expect(err).To(MatchError(fmt.Sprintf("%s.manifest is for OVA version some-other-version, but this version of the cf CLI plugin expects OVA version some-version", ovaPath)))
+			})
+
+			Context("when no OVA version is expected", func() {
+				It("should return the manifest", func() {
+					writeManifest(`{"version":"some-other-version","size":13,"sha256":"6e32ea34db1b3755d7dec972eb72c705338f0dd8e0be881d966963438fb2e800"}`)
+
+					ovaManifest, err := verifier.Verify(context.Background(), ovaPath, "")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(ovaManifest.Version).To(Equal("some-other-version"))
+				})
+			})
+		})
+
+		Context("when the plugin was not built with a public key", func() {
+			It("should return an error", func() {
+				verifier.PublicKey = ""
+
+				_, err := verifier.Verify(context.Background(), ovaPath, "some-version")
+				Expect(err).To(MatchError("this version of the cf CLI plugin was not built with a key to verify OVA manifests"))
+			})
+		})
+
+		Context("when the context is done", func() {
+			It("should stop reading the OVA", func() {
+				writeManifest(`{"version":"some-version","size":13,"sha256":"6e32ea34db1b3755d7dec972eb72c705338f0dd8e0be881d966963438fb2e800"}`)
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				_, err := verifier.Verify(ctx, ovaPath, "some-version")
+				Expect(err).To(MatchError(ContainSubstring("context canceled")))
+			})
+		})
+	})
+})
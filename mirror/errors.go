@@ -0,0 +1,21 @@
+package mirror
+
+import "fmt"
+
+type UnreachableError struct {
+	Host string
+	Err  error
+}
+
+func (e *UnreachableError) Error() string {
+	return fmt.Sprintf("failed to reach %s: %s", e.Host, e.Err)
+}
+
+type UnexpectedResponseError struct {
+	Host   string
+	Status string
+}
+
+func (e *UnexpectedResponseError) Error() string {
+	return fmt.Sprintf("%s returned: %s", e.Host, e.Status)
+}
@@ -0,0 +1,45 @@
+package mirror
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/pivotal-cf/pcfdev-cli/pivnet"
+)
+
+// File copies the OVA from a path on this host, which can be on a network
+// share.
+type File struct {
+	Path string
+}
+
+func (f *File) DownloadOVA(ctx context.Context, startAtByte int64) (*pivnet.DownloadReader, error) {
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err := file.Seek(startAtByte, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return newDownloadReader(ctx, file, info.Size()-startAtByte, startAtByte), nil
+}
+
+func (f *File) DownloadManifest(ctx context.Context) ([]byte, []byte, error) {
+	return downloadManifest(func(suffix string) ([]byte, error) {
+		contents, err := ioutil.ReadFile(f.Path + suffix)
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return contents, err
+	})
+}
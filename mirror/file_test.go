@@ -0,0 +1,73 @@
+package mirror_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pivotal-cf/pcfdev-cli/mirror"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("File", func() {
+	var (
+		tmpDir string
+		source *mirror.File
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "pcfdev-mirror")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ioutil.WriteFile(filepath.Join(tmpDir, "pcfdev.ova"), []byte("some-ova contents"), 0644)).To(Succeed())
+		source = &mirror.File{Path: filepath.Join(tmpDir, "pcfdev.ova")}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	Describe("#DownloadOVA", func() {
+		It("should read the OVA from the byte to start at", func() {
+			ova, err := source.DownloadOVA(context.Background(), 4)
+			Expect(err).NotTo(HaveOccurred())
+			defer ova.ReadCloser.Close()
+			Expect(ova.ExistingLength).To(Equal(int64(4)))
+			Expect(ova.ContentLength).To(Equal(int64(13)))
+			Expect(ioutil.ReadAll(ova.ReadCloser)).To(Equal([]byte("-ova contents")))
+		})
+
+		Context("when the file does not exist", func() {
+			It("should return an error", func() {
+				source.Path = filepath.Join(tmpDir, "some-missing.ova")
+
+				_, err := source.DownloadOVA(context.Background(), 0)
+				Expect(os.IsNotExist(err)).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("#DownloadManifest", func() {
+		It("should read the manifest and its signature from next to the OVA", func() {
+			Expect(ioutil.WriteFile(filepath.Join(tmpDir, "pcfdev.ova.manifest"), []byte("some-manifest"), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(tmpDir, "pcfdev.ova.manifest.sig"), []byte("some-signature"), 0644)).To(Succeed())
+
+			manifest, signature, err := source.DownloadManifest(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manifest).To(Equal([]byte("some-manifest")))
+			Expect(signature).To(Equal([]byte("some-signature")))
+		})
+
+		Context("when there is no manifest", func() {
+			It("should return no manifest", func() {
+				manifest, signature, err := source.DownloadManifest(context.Background())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(manifest).To(BeNil())
+				Expect(signature).To(BeNil())
+			})
+		})
+	})
+})
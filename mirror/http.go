@@ -0,0 +1,40 @@
+package mirror
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/pivotal-cf/pcfdev-cli/pivnet"
+)
+
+// HTTP downloads the OVA from a URL, such as a file in an Artifactory
+// repository. Credentials for basic authentication can be given in the URL.
+type HTTP struct {
+	URL       string
+	Transport http.RoundTripper
+}
+
+func (h *HTTP) DownloadOVA(ctx context.Context, startAtByte int64) (*pivnet.DownloadReader, error) {
+	req, err := newRequest(ctx, h.URL, startAtByte)
+	if err != nil {
+		return nil, err
+	}
+	return download(ctx, h.Transport, req, startAtByte)
+}
+
+func (h *HTTP) DownloadManifest(ctx context.Context) ([]byte, []byte, error) {
+	return downloadManifest(func(suffix string) ([]byte, error) {
+		u, err := url.Parse(h.URL)
+		if err != nil {
+			return nil, err
+		}
+		u.Path += suffix
+		u.RawPath = ""
+		req, err := newRequest(ctx, u.String(), 0)
+		if err != nil {
+			return nil, err
+		}
+		return fetch(h.Transport, req, http.StatusNotFound)
+	})
+}
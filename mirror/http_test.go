@@ -0,0 +1,192 @@
+package mirror_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/pivotal-cf/pcfdev-cli/mirror"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HTTP", func() {
+	var (
+		server *httptest.Server
+		source *mirror.HTTP
+	)
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("#DownloadOVA", func() {
+		It("should resume the download with a range request", func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+
+				Expect(r.URL.Path).To(Equal("/pcfdev.ova"))
+				Expect(r.Header.Get("Range")).To(Equal("bytes=4-"))
+				http.ServeContent(w, r, "pcfdev.ova", time.Time{}, bytes.NewReader([]byte("some-ova contents")))
+			}))
+			source = &mirror.HTTP{URL: server.URL + "/pcfdev.ova"}
+
+			ova, err := source.DownloadOVA(context.Background(), 4)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ova.ExistingLength).To(Equal(int64(4)))
+			Expect(ova.ContentLength).To(Equal(int64(13)))
+			Expect(ioutil.ReadAll(ova.ReadCloser)).To(Equal([]byte("-ova contents")))
+		})
+
+		It("should send the credentials in the URL", func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+
+				username, password, ok := r.BasicAuth()
+				Expect(ok).To(BeTrue())
+				Expect(username).To(Equal("some-user"))
+				Expect(password).To(Equal("some-password"))
+				Expect(r.Header.Get("Range")).To(BeEmpty())
+				w.Write([]byte("some-ova"))
+			}))
+			source = &mirror.HTTP{URL: "http://some-user:some-password@" + server.Listener.Addr().String() + "/pcfdev.ova"}
+
+			ova, err := source.DownloadOVA(context.Background(), 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ioutil.ReadAll(ova.ReadCloser)).To(Equal([]byte("some-ova")))
+		})
+
+		Context("when the mirror does not support ranges", func() {
+			It("should download the whole OVA again", func() {
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte("some-ova contents"))
+				}))
+				source = &mirror.HTTP{URL: server.URL}
+
+				ova, err := source.DownloadOVA(context.Background(), 4)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ova.ExistingLength).To(BeZero())
+				Expect(ova.ContentLength).To(Equal(int64(17)))
+				Expect(ioutil.ReadAll(ova.ReadCloser)).To(Equal([]byte("some-ova contents")))
+			})
+		})
+
+		Context("when the whole OVA has been downloaded", func() {
+			It("should return nothing more to download", func() {
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					http.ServeContent(w, r, "pcfdev.ova", time.Time{}, bytes.NewReader([]byte("some-ova")))
+				}))
+				source = &mirror.HTTP{URL: server.URL}
+
+				ova, err := source.DownloadOVA(context.Background(), 8)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ioutil.ReadAll(ova.ReadCloser)).To(BeEmpty())
+			})
+		})
+
+		Context("when the mirror returns an error", func() {
+			It("should return an error", func() {
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusNotFound)
+				}))
+				source = &mirror.HTTP{URL: server.URL}
+
+				_, err := source.DownloadOVA(context.Background(), 0)
+				Expect(err).To(MatchError(server.Listener.Addr().String() + " returned: 404 Not Found"))
+			})
+		})
+
+		Context("when the mirror cannot be reached", func() {
+			It("should return an error", func() {
+				server = httptest.NewServer(http.NotFoundHandler())
+				source = &mirror.HTTP{URL: server.URL}
+				server.Close()
+
+				_, err := source.DownloadOVA(context.Background(), 0)
+				Expect(err).To(BeAssignableToTypeOf(&mirror.UnreachableError{}))
+			})
+		})
+
+		Context("when the context is done", func() {
+			It("should stop the download", func() {
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte("some-ova"))
+				}))
+				source = &mirror.HTTP{URL: server.URL}
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				_, err := source.DownloadOVA(ctx, 0)
+				Expect(err).To(MatchError(ContainSubstring("context canceled")))
+			})
+		})
+	})
+
+	Describe("#DownloadManifest", func() {
+		It("should download the manifest and its signature from next to the OVA", func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+
+				Expect(r.URL.Query().Get("some-param")).To(Equal("some-value"))
+				switch r.URL.Path {
+				case "/pcfdev.ova.manifest":
+					w.Write([]byte("some-manifest"))
+				case "/pcfdev.ova.manifest.sig":
+					w.Write([]byte("some-signature"))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			source = &mirror.HTTP{URL: server.URL + "/pcfdev.ova?some-param=some-value"}
+
+			manifest, signature, err := source.DownloadManifest(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manifest).To(Equal([]byte("some-manifest")))
+			Expect(signature).To(Equal([]byte("some-signature")))
+		})
+
+		Context("when the mirror has no manifest", func() {
+			It("should return no manifest", func() {
+				server = httptest.NewServer(http.NotFoundHandler())
+				source = &mirror.HTTP{URL: server.URL + "/pcfdev.ova"}
+
+				manifest, signature, err := source.DownloadManifest(context.Background())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(manifest).To(BeNil())
+				Expect(signature).To(BeNil())
+			})
+		})
+
+		Context("when the mirror has a manifest but no signature", func() {
+			It("should return an error", func() {
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path == "/pcfdev.ova.manifest" {
+						w.Write([]byte("some-manifest"))
+						return
+					}
+					w.WriteHeader(http.StatusNotFound)
+				}))
+				source = &mirror.HTTP{URL: server.URL + "/pcfdev.ova"}
+
+				_, _, err := source.DownloadManifest(context.Background())
+				Expect(err).To(MatchError("the OVA source has a manifest of the OVA, but no signature of the manifest"))
+			})
+		})
+
+		Context("when the mirror returns an error", func() {
+			It("should return an error", func() {
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusInternalServerError)
+				}))
+				source = &mirror.HTTP{URL: server.URL + "/pcfdev.ova"}
+
+				_, _, err := source.DownloadManifest(context.Background())
+				Expect(err).To(MatchError(server.Listener.Addr().String() + " returned: 500 Internal Server Error"))
+			})
+		})
+	})
+})
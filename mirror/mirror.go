@@ -0,0 +1,223 @@
+package mirror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pivotal-cf/pcfdev-cli/pivnet"
+)
+
+const defaultS3Endpoint = "https://s3.amazonaws.com"
+
+// Source is where the OVA comes from instead of Pivotal Network. A source can
+// also serve the signed manifest of the OVA next to it, as <ova>.manifest and
+// <ova>.manifest.sig. DownloadManifest returns no manifest when it has none.
+type Source interface {
+	DownloadOVA(ctx context.Context, startAtByte int64) (ova *pivnet.DownloadReader, err error)
+	DownloadManifest(ctx context.Context) (manifest []byte, signature []byte, err error)
+}
+
+// New returns the OVA source that source names: an http or https URL, an
+// s3://bucket/key URL, or else a file path or file:// URL. S3 requests go to
+// s3Endpoint, and use the credentials and region in the AWS_* environment
+// variables.
+func New(source string, s3Endpoint string, transport http.RoundTripper) (Source, error) {
+	u, err := url.Parse(source)
+	// A Windows path such as C:\pcfdev.ova either does not parse or parses
+	// with a one-letter scheme.
+	if err != nil || len(u.Scheme) <= 1 {
+		return &File{Path: source}, nil
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		return &HTTP{URL: source, Transport: transport}, nil
+	case "s3":
+		key := strings.TrimPrefix(u.Path, "/")
+		if u.Host == "" || key == "" {
+			return nil, fmt.Errorf("invalid OVA source '%s': an S3 source must look like s3://bucket/path/to/pcfdev.ova", source)
+		}
+		if s3Endpoint == "" {
+			s3Endpoint = defaultS3Endpoint
+		} else if !strings.Contains(s3Endpoint, "://") {
+			s3Endpoint = "https://" + s3Endpoint
+		}
+		return &S3{
+			Endpoint:        s3Endpoint,
+			Region:          firstSet(os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"), "us-east-1"),
+			Bucket:          u.Host,
+			Key:             key,
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+			Transport:       transport,
+			Now:             time.Now,
+		}, nil
+	case "file":
+		path := u.Path
+		if u.Host != "" {
+			path = "//" + u.Host + path
+		} else if len(path) > 2 && path[0] == '/' && path[2] == ':' {
+			path = path[1:]
+		}
+		return &File{Path: filepath.FromSlash(path)}, nil
+	default:
+		return nil, fmt.Errorf("invalid OVA source '%s': it must be an http, https, s3 or file URL, or a file path", source)
+	}
+}
+
+// FS records the acceptance of the EULA.
+type FS interface {
+	CreateDir(path string) error
+	Exists(path string) (exists bool, err error)
+	Write(path string, contents io.Reader, append bool) error
+}
+
+// eulaText is shown instead of the EULA on Pivotal Network, which may not be
+// reachable when the OVA comes from a mirror.
+const eulaText = `PCF Dev is licensed to you under the Pivotal Software End User License
+Agreement, which is shown on Pivotal Network for the PCF Dev release.
+
+This PCF Dev image comes from a mirror instead of Pivotal Network. By
+accepting, you agree to the same End User License Agreement as if you had
+downloaded PCF Dev from Pivotal Network.`
+
+// EULA stands in for Pivotal Network when the OVA comes from a mirror, since
+// Pivotal Network may not be reachable at all. The EULA is still shown and
+// accepted, and its acceptance is recorded in AcceptedPath.
+type EULA struct {
+	FS           FS
+	AcceptedPath string
+}
+
+func (e *EULA) IsEULAAccepted(ctx context.Context) (bool, error) {
+	return e.FS.Exists(e.AcceptedPath)
+}
+
+func (e *EULA) AcceptEULA(ctx context.Context) error {
+	if err := e.FS.CreateDir(filepath.Dir(e.AcceptedPath)); err != nil {
+		return err
+	}
+	return e.FS.Write(e.AcceptedPath, strings.NewReader(""), false)
+}
+
+func (e *EULA) GetEULA(ctx context.Context) (string, error) {
+	return eulaText, nil
+}
+
+// newRequest returns a request for the OVA from startAtByte onwards.
+func newRequest(ctx context.Context, uri string, startAtByte int64) (*http.Request, error) {
+	req, err := http.NewRequest("GET", uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	if startAtByte > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", startAtByte))
+	}
+	return req.WithContext(ctx), nil
+}
+
+func download(ctx context.Context, transport http.RoundTripper, req *http.Request, startAtByte int64) (*pivnet.DownloadReader, error) {
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return nil, &UnreachableError{Host: req.URL.Host, Err: err}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		return newDownloadReader(ctx, resp.Body, resp.ContentLength, startAtByte), nil
+	case http.StatusOK:
+		// The mirror does not support ranges, so the whole OVA is downloaded
+		// again, to replace the part that has been downloaded already.
+		return newDownloadReader(ctx, resp.Body, resp.ContentLength, 0), nil
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The whole OVA has been downloaded already.
+		resp.Body.Close()
+		return newDownloadReader(ctx, ioutil.NopCloser(strings.NewReader("")), 0, startAtByte), nil
+	default:
+		resp.Body.Close()
+		return nil, &UnexpectedResponseError{Host: req.URL.Host, Status: resp.Status}
+	}
+}
+
+// downloadManifest fetches the manifest and its signature with fetch, which
+// returns nil for a file that the source does not have.
+func downloadManifest(fetch func(suffix string) ([]byte, error)) ([]byte, []byte, error) {
+	manifest, err := fetch(".manifest")
+	if err != nil || manifest == nil {
+		return nil, nil, err
+	}
+	signature, err := fetch(".manifest.sig")
+	if err != nil {
+		return nil, nil, err
+	}
+	if signature == nil {
+		return nil, nil, errors.New("the OVA source has a manifest of the OVA, but no signature of the manifest")
+	}
+	return manifest, signature, nil
+}
+
+// fetch returns the body of a small file such as a manifest, or nil when the
+// response has one of the notFound statuses.
+func fetch(transport http.RoundTripper, req *http.Request, notFound ...int) ([]byte, error) {
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return nil, &UnreachableError{Host: req.URL.Host, Err: err}
+	}
+	defer resp.Body.Close()
+
+	for _, status := range notFound {
+		if resp.StatusCode == status {
+			return nil, nil
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &UnexpectedResponseError{Host: req.URL.Host, Status: resp.Status}
+	}
+	contents, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &UnreachableError{Host: req.URL.Host, Err: err}
+	}
+	return contents, nil
+}
+
+func newDownloadReader(ctx context.Context, ova io.ReadCloser, contentLength int64, startAtByte int64) *pivnet.DownloadReader {
+	return &pivnet.DownloadReader{
+		ReadCloser:     &contextReader{ctx: ctx, ReadCloser: ova},
+		Writer:         os.Stdout,
+		ContentLength:  contentLength,
+		ExistingLength: startAtByte,
+	}
+}
+
+// contextReader stops reading once ctx is done, so that copying from a file
+// on a slow network share can be interrupted.
+type contextReader struct {
+	io.ReadCloser
+	ctx context.Context
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.ReadCloser.Read(p)
+}
+
+func firstSet(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/pivotal-cf/pcfdev-cli/pivnet"
+)
+
+// emptyPayloadHash is the SHA-256 of the empty body of a GET request.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3 downloads the OVA from a bucket in S3, or in an S3-compatible store such
+// as MinIO, using path-style requests. Requests are signed with AWS Signature
+// Version 4 when there is an access key, and are anonymous otherwise.
+type S3 struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	Key             string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Transport       http.RoundTripper
+	Now             func() time.Time
+}
+
+func (s *S3) DownloadOVA(ctx context.Context, startAtByte int64) (*pivnet.DownloadReader, error) {
+	req, err := s.newRequest(ctx, s.Key, startAtByte)
+	if err != nil {
+		return nil, err
+	}
+	return download(ctx, s.Transport, req, startAtByte)
+}
+
+// DownloadManifest treats 403 Forbidden as no manifest too, since S3 returns
+// it instead of 404 Not Found to those who may not list the bucket.
+func (s *S3) DownloadManifest(ctx context.Context) ([]byte, []byte, error) {
+	return downloadManifest(func(suffix string) ([]byte, error) {
+		req, err := s.newRequest(ctx, s.Key+suffix, 0)
+		if err != nil {
+			return nil, err
+		}
+		return fetch(s.Transport, req, http.StatusNotFound, http.StatusForbidden)
+	})
+}
+
+func (s *S3) newRequest(ctx context.Context, key string, startAtByte int64) (*http.Request, error) {
+	uri := strings.TrimSuffix(s.Endpoint, "/") + "/" + uriEncode(s.Bucket) + "/" + uriEncode(key)
+	req, err := newRequest(ctx, uri, startAtByte)
+	if err != nil {
+		return nil, err
+	}
+	if s.AccessKeyID != "" {
+		if err := s.sign(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+func (s *S3) sign(ctx context.Context, req *http.Request) error {
+	req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+	credentials := aws.Credentials{
+		AccessKeyID:     s.AccessKeyID,
+		SecretAccessKey: s.SecretAccessKey,
+		SessionToken:    s.SessionToken,
+	}
+	signer := v4.NewSigner(func(options *v4.SignerOptions) {
+		// The key is escaped once already, which is all that S3 expects.
+		options.DisableURIPathEscaping = true
+	})
+	return signer.SignHTTP(ctx, credentials, req, emptyPayloadHash, "s3", s.Region, s.Now())
+}
+
+// uriEncode escapes everything in an object key but the unreserved characters
+// and slashes, as Signature Version 4 requires.
+func uriEncode(s string) string {
+	encoded := ""
+	for _, b := range []byte(s) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9',
+			b == '-', b == '.', b == '_', b == '~', b == '/':
+			encoded += string(b)
+		default:
+			encoded += fmt.Sprintf("%%%02X", b)
+		}
+	}
+	return encoded
+}
@@ -0,0 +1,165 @@
+package mirror_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pivotal-cf/pcfdev-cli/mirror"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+var _ = Describe("S3", func() {
+	var (
+		source  *mirror.S3
+		request *http.Request
+	)
+
+	BeforeEach(func() {
+		request = nil
+		source = &mirror.S3{
+			Endpoint: "https://minio.example.com:9000",
+			Region:   "some-region",
+			Bucket:   "some-bucket",
+			Key:      "pcfdev/pcfdev v1.ova",
+			Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				request = req
+				return &http.Response{
+					StatusCode:    http.StatusPartialContent,
+					Status:        "206 Partial Content",
+					ContentLength: 8,
+					Body:          ioutil.NopCloser(bytes.NewReader([]byte("some-ova"))),
+					Request:       req,
+				}, nil
+			}),
+			Now: func() time.Time {
+				return time.Date(2016, time.May, 24, 12, 30, 0, 0, time.UTC)
+			},
+		}
+	})
+
+	Describe("#DownloadOVA", func() {
+		It("should download the object with a path-style request", func() {
+			ova, err := source.DownloadOVA(context.Background(), 4)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ova.ExistingLength).To(Equal(int64(4)))
+			Expect(ova.ContentLength).To(Equal(int64(8)))
+			Expect(ioutil.ReadAll(ova.ReadCloser)).To(Equal([]byte("some-ova")))
+
+			Expect(request.URL.String()).To(Equal("https://minio.example.com:9000/some-bucket/pcfdev/pcfdev%20v1.ova"))
+			Expect(request.Header.Get("Range")).To(Equal("bytes=4-"))
+		})
+
+		Context("when there is no access key", func() {
+			It("should make an anonymous request", func() {
+				_, err := source.DownloadOVA(context.Background(), 0)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(request.Header.Get("Authorization")).To(BeEmpty())
+			})
+		})
+
+		Context("when there is an access key", func() {
+			BeforeEach(func() {
+				source.AccessKeyID = "some-access-key-id"
+				source.SecretAccessKey = "some-secret-access-key"
+			})
+
+			It("should sign the request with Signature Version 4", func() {
+				_, err := source.DownloadOVA(context.Background(), 4)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(request.Header.Get("X-Amz-Date")).To(Equal("20160524T123000Z"))
+				Expect(request.Header.Get("X-Amz-Content-Sha256")).To(Equal("e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"))
+				Expect(request.Header.Get("Authorization")).To(Equal(
+					"AWS4-HMAC-SHA256 Credential=some-access-key-id/20160524/some-region/s3/aws4_request, " +
+						"SignedHeaders=host;range;x-amz-content-sha256;x-amz-date, " +
+						"Signature=4eac70304bc72c2410401b74b0582782727b0c82f1300d569ca9c84b9cbdd6fa",
+				))
+			})
+
+			Context("when there is a session token", func() {
+				It("should sign the token", func() {
+					source.SessionToken = "some-session-token"
+
+					_, err := source.DownloadOVA(context.Background(), 0)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(request.Header.Get("X-Amz-Security-Token")).To(Equal("some-session-token"))
+					Expect(request.Header.Get("Authorization")).To(ContainSubstring("SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-security-token,"))
+				})
+			})
+		})
+
+		Context("when the bucket returns an error", func() {
+			It("should return an error", func() {
+				source.Transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusForbidden,
+						Status:     "403 Forbidden",
+						Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+						Request:    req,
+					}, nil
+				})
+
+				_, err := source.DownloadOVA(context.Background(), 0)
+				Expect(err).To(MatchError("minio.example.com:9000 returned: 403 Forbidden"))
+			})
+		})
+	})
+
+	Describe("#DownloadManifest", func() {
+		var paths []string
+
+		BeforeEach(func() {
+			paths = []string{}
+			source.AccessKeyID = "some-access-key-id"
+			source.SecretAccessKey = "some-secret-access-key"
+			source.Transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				paths = append(paths, req.URL.EscapedPath())
+				Expect(req.Header.Get("Authorization")).To(HavePrefix("AWS4-HMAC-SHA256 Credential=some-access-key-id/"))
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Status:     "200 OK",
+					Body:       ioutil.NopCloser(bytes.NewReader([]byte("some-contents"))),
+					Request:    req,
+				}, nil
+			})
+		})
+
+		It("should download the signed manifest from next to the OVA with signed requests", func() {
+			manifest, signature, err := source.DownloadManifest(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manifest).To(Equal([]byte("some-contents")))
+			Expect(signature).To(Equal([]byte("some-contents")))
+			Expect(paths).To(Equal([]string{
+				"/some-bucket/pcfdev/pcfdev%20v1.ova.manifest",
+				"/some-bucket/pcfdev/pcfdev%20v1.ova.manifest.sig",
+			}))
+		})
+
+		Context("when the bucket forbids reading the manifest", func() {
+			It("should return no manifest", func() {
+				source.Transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusForbidden,
+						Status:     "403 Forbidden",
+						Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+						Request:    req,
+					}, nil
+				})
+
+				manifest, _, err := source.DownloadManifest(context.Background())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(manifest).To(BeNil())
+			})
+		})
+	})
+})
@@ -7,27 +7,68 @@ import (
 
 type Network struct{}
 
+// Host-only networking models. Host-only networks, which replace host-only
+// interfaces in VirtualBox 7 on macOS, only allow addresses within
+// HostOnlyNetworkRange.
+const (
+	ModelHostOnlyInterface = "hostonlyif"
+	ModelHostOnlyNetwork   = "hostonlynet"
+
+	HostOnlyNetworkRange = "192.168.56.0/21"
+)
+
 type Interface struct {
 	HardwareAddress string
 	IP              string
+	Netmask         string
 	Name            string
 	Exists          bool
 }
 
+// Subnet returns the subnet of the interface in CIDR notation, or an empty
+// string when its netmask is not known.
+func (i *Interface) Subnet() string {
+	return SubnetOf(i.IP, i.Netmask)
+}
+
+// HasIPCollision reports whether ip is taken by a host interface, or whether
+// its /24 subnet overlaps the subnet of a host interface or a host route,
+// such as one pushed by a VPN.
 func (n *Network) HasIPCollision(ip string) (bool, error) {
+	if !IsIPV4(ip) {
+		return false, nil
+	}
+
 	interfaces, err := n.Interfaces()
 	if err != nil {
 		return false, err
 	}
 
+	routes, err := n.Routes()
+	if err != nil {
+		return false, err
+	}
+
+	subnet := SubnetOf(ip, "255.255.255.0")
 	for _, networkInterface := range interfaces {
-		if networkInterface.IP == ip {
+		if networkInterface.IP == ip || Overlaps(subnet, networkInterface.Subnet()) {
+			return true, nil
+		}
+	}
+	for _, route := range routes {
+		if Overlaps(subnet, route) {
 			return true, nil
 		}
 	}
 	return false, nil
 }
 
+// Routes returns the destinations of the host's IPv4 routes in CIDR notation,
+// leaving out the default route.
+func (n *Network) Routes() (routes []string, err error) {
+	return hostRoutes()
+}
+
 func (n *Network) Interfaces() (interfaces []*Interface, err error) {
 	ifaces, err := net.Interfaces()
 	if err != nil {
@@ -47,6 +88,7 @@ func (n *Network) Interfaces() (interfaces []*Interface, err error) {
 			if IsIPV4(addrString) {
 				interfaces = append(interfaces, &Interface{
 					IP:              addrString,
+					Netmask:         netmask(addr),
 					HardwareAddress: iface.HardwareAddr.String(),
 					Exists:          true,
 				})
@@ -57,6 +99,14 @@ func (n *Network) Interfaces() (interfaces []*Interface, err error) {
 	return interfaces, nil
 }
 
+func netmask(addr net.Addr) string {
+	ipNet, ok := addr.(*net.IPNet)
+	if !ok || len(ipNet.Mask) != net.IPv4len {
+		return ""
+	}
+	return net.IP(ipNet.Mask).String()
+}
+
 func IsIPV4(ip string) bool {
 	ip4 := net.ParseIP(ip).To4()
 	if ip4 == nil {
@@ -66,7 +66,7 @@ var _ = Describe("Network", func() {
 		It("should return the network interfaces", func() {
 			interfaces, err := net.Interfaces()
 			Expect(err).NotTo(HaveOccurred())
-			expectedInterface := &network.Interface{IP: expectedIP, HardwareAddress: expectedHardwareAddress, Exists: true}
+			expectedInterface := &network.Interface{IP: expectedIP, Netmask: "255.255.255.0", HardwareAddress: expectedHardwareAddress, Exists: true}
 			Expect(interfaces).To(ContainElement(expectedInterface))
 		})
 	})
@@ -76,6 +76,10 @@ var _ = Describe("Network", func() {
 			Expect(net.HasIPCollision(expectedIP)).To(BeTrue())
 		})
 
+		It("should return true when the subnet of the ip overlaps the subnet of an interface", func() {
+			Expect(net.HasIPCollision("192.168.56.1")).To(BeTrue())
+		})
+
 		It("should return false when ip does not collide", func() {
 			Expect(net.HasIPCollision("some-non-colliding-ip")).To(BeFalse())
 		})
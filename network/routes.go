@@ -0,0 +1,148 @@
+package network
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SubnetOf returns the subnet of ip under netmask, in CIDR notation.
+func SubnetOf(ip string, netmask string) string {
+	parsedIP := net.ParseIP(ip).To4()
+	mask := net.ParseIP(netmask).To4()
+	if parsedIP == nil || mask == nil {
+		return ""
+	}
+	return (&net.IPNet{IP: parsedIP.Mask(net.IPMask(mask)), Mask: net.IPMask(mask)}).String()
+}
+
+// Overlaps reports whether two subnets in CIDR notation share any address.
+func Overlaps(subnet string, otherSubnet string) bool {
+	_, a, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return false
+	}
+	_, b, err := net.ParseCIDR(otherSubnet)
+	if err != nil {
+		return false
+	}
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// ParseProcNetRoute reads the routes in /proc/net/route, where addresses are
+// written as little-endian hex.
+func ParseProcNetRoute(contents []byte) []string {
+	routes := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 || fields[0] == "Iface" {
+			continue
+		}
+		destination, err := parseHexIP(fields[1])
+		if err != nil {
+			continue
+		}
+		mask, err := parseHexIP(fields[7])
+		if err != nil {
+			continue
+		}
+		routes = appendRoute(routes, &net.IPNet{IP: destination, Mask: net.IPMask(mask)})
+	}
+	return routes
+}
+
+// ParseNetstatRoutes reads the output of 'netstat -rn -f inet' on macOS,
+// which leaves out trailing zero octets, and the netmask when it falls on an
+// octet boundary.
+func ParseNetstatRoutes(output []byte) []string {
+	routes := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		destination := fields[0]
+		prefixLength := -1
+		if parts := strings.SplitN(destination, "/", 2); len(parts) == 2 {
+			destination = parts[0]
+			length, err := strconv.Atoi(parts[1])
+			if err != nil {
+				continue
+			}
+			prefixLength = length
+		}
+
+		octets := strings.Split(destination, ".")
+		if len(octets) > 4 {
+			continue
+		}
+		if prefixLength == -1 {
+			prefixLength = 8 * len(octets)
+		}
+		for len(octets) < 4 {
+			octets = append(octets, "0")
+		}
+
+		ip := net.ParseIP(strings.Join(octets, ".")).To4()
+		if ip == nil || prefixLength > 32 {
+			continue
+		}
+		routes = appendRoute(routes, &net.IPNet{IP: ip, Mask: net.CIDRMask(prefixLength, 32)})
+	}
+	return routes
+}
+
+var routePrintRegex = regexp.MustCompile(`^\s*(\d+\.\d+\.\d+\.\d+)\s+(\d+\.\d+\.\d+\.\d+)\s+\S+\s+\S+\s+\d+\s*$`)
+
+// ParseRoutePrint reads the active IPv4 routes printed by 'route print -4' on
+// Windows.
+func ParseRoutePrint(output []byte) []string {
+	routes := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		matches := routePrintRegex.FindStringSubmatch(scanner.Text())
+		if matches == nil {
+			continue
+		}
+		ip := net.ParseIP(matches[1]).To4()
+		mask := net.ParseIP(matches[2]).To4()
+		if ip == nil || mask == nil {
+			continue
+		}
+		routes = appendRoute(routes, &net.IPNet{IP: ip, Mask: net.IPMask(mask)})
+	}
+	return routes
+}
+
+// appendRoute leaves out default routes, which overlap every subnet, and
+// multicast and broadcast routes, which no VM is given an address in. Any
+// route shorter than a /8 is taken to be a default route, such as the
+// 0.0.0.0/1 and 128.0.0.0/1 pair that VPN clients add in place of 0.0.0.0/0.
+func appendRoute(routes []string, route *net.IPNet) []string {
+	if ones, _ := route.Mask.Size(); ones < 8 {
+		return routes
+	}
+	if route.IP.IsMulticast() || route.IP.Equal(net.IPv4bcast) {
+		return routes
+	}
+	route.IP = route.IP.Mask(route.Mask)
+	return append(routes, route.String())
+}
+
+func parseHexIP(hexIP string) (net.IP, error) {
+	bytes, err := hex.DecodeString(hexIP)
+	if err != nil || len(bytes) != 4 {
+		return nil, &net.ParseError{Type: "IP address", Text: hexIP}
+	}
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, binary.LittleEndian.Uint32(bytes))
+	return ip, nil
+}
@@ -0,0 +1,11 @@
+package network
+
+import "os/exec"
+
+func hostRoutes() (routes []string, err error) {
+	output, err := exec.Command("netstat", "-rn", "-f", "inet").Output()
+	if err != nil {
+		return nil, err
+	}
+	return ParseNetstatRoutes(output), nil
+}
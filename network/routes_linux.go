@@ -0,0 +1,11 @@
+package network
+
+import "io/ioutil"
+
+func hostRoutes() (routes []string, err error) {
+	contents, err := ioutil.ReadFile("/proc/net/route")
+	if err != nil {
+		return nil, err
+	}
+	return ParseProcNetRoute(contents), nil
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !windows
+// +build !linux,!darwin,!windows
+
+package network
+
+func hostRoutes() (routes []string, err error) {
+	return []string{}, nil
+}
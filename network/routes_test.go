@@ -0,0 +1,90 @@
+package network_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/network"
+)
+
+var _ = Describe("Routes", func() {
+	Describe(".SubnetOf", func() {
+		It("should return the subnet in CIDR notation", func() {
+			Expect(network.SubnetOf("10.8.3.7", "255.255.0.0")).To(Equal("10.8.0.0/16"))
+		})
+
+		It("should return an empty string when the netmask is not known", func() {
+			Expect(network.SubnetOf("10.8.3.7", "")).To(BeEmpty())
+		})
+	})
+
+	Describe(".Overlaps", func() {
+		It("should return true when one subnet contains the other", func() {
+			Expect(network.Overlaps("192.168.11.0/24", "192.168.0.0/16")).To(BeTrue())
+			Expect(network.Overlaps("192.168.0.0/16", "192.168.11.0/24")).To(BeTrue())
+			Expect(network.Overlaps("192.168.11.0/24", "192.168.11.128/25")).To(BeTrue())
+		})
+
+		It("should return false when the subnets are disjoint", func() {
+			Expect(network.Overlaps("192.168.11.0/24", "192.168.22.0/24")).To(BeFalse())
+		})
+
+		It("should return false when a subnet is not valid", func() {
+			Expect(network.Overlaps("192.168.11.0/24", "")).To(BeFalse())
+		})
+	})
+
+	Describe(".ParseProcNetRoute", func() {
+		It("should return the routes other than the default route", func() {
+			Expect(network.ParseProcNetRoute([]byte(
+				"Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT\n" +
+					"eth0\t00000000\t0101A8C0\t0003\t0\t0\t100\t00000000\t0\t0\t0\n" +
+					"eth0\t0001A8C0\t00000000\t0001\t0\t0\t100\t00FFFFFF\t0\t0\t0\n" +
+					"tun0\t0000080A\t00000000\t0001\t0\t0\t50\t0000FFFF\t0\t0\t0\n",
+			))).To(Equal([]string{"192.168.1.0/24", "10.8.0.0/16"}))
+		})
+
+		It("should leave out the halves of the default route that VPN clients add", func() {
+			Expect(network.ParseProcNetRoute([]byte(
+				"Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT\n" +
+					"tun0\t00000000\t0100080A\t0003\t0\t0\t0\t00000080\t0\t0\t0\n" +
+					"tun0\t00000080\t0100080A\t0003\t0\t0\t0\t00000080\t0\t0\t0\n" +
+					"tun0\t0000080A\t00000000\t0001\t0\t0\t50\t0000FFFF\t0\t0\t0\n",
+			))).To(Equal([]string{"10.8.0.0/16"}))
+		})
+	})
+
+	Describe(".ParseNetstatRoutes", func() {
+		It("should expand abbreviated destinations", func() {
+			Expect(network.ParseNetstatRoutes([]byte(
+				"Routing tables\n\n" +
+					"Internet:\n" +
+					"Destination        Gateway            Flags        Netif Expire\n" +
+					"default            192.168.1.1        UGScg          en0\n" +
+					"10.8/16            utun3              USc          utun3\n" +
+					"127                127.0.0.1          UCS            lo0\n" +
+					"192.168.1          link#6             UCS            en0      !\n" +
+					"192.168.1.57/32    link#6             UCS            en0      !\n" +
+					"224.0.0/4          link#6             UmCS           en0      !\n",
+			))).To(Equal([]string{"10.8.0.0/16", "127.0.0.0/8", "192.168.1.0/24", "192.168.1.57/32"}))
+		})
+	})
+
+	Describe(".ParseRoutePrint", func() {
+		It("should return the active routes other than the default route", func() {
+			Expect(network.ParseRoutePrint([]byte(
+				"IPv4 Route Table\r\n" +
+					"===========================================================================\r\n" +
+					"Active Routes:\r\n" +
+					"Network Destination        Netmask          Gateway       Interface  Metric\r\n" +
+					"          0.0.0.0          0.0.0.0      192.168.1.1    192.168.1.57     35\r\n" +
+					"         10.8.0.0      255.255.0.0         On-link        10.8.0.12    291\r\n" +
+					"      192.168.1.0    255.255.255.0         On-link     192.168.1.57    291\r\n" +
+					"        224.0.0.0        240.0.0.0         On-link        127.0.0.1    331\r\n" +
+					"  255.255.255.255  255.255.255.255         On-link        127.0.0.1    331\r\n" +
+					"===========================================================================\r\n" +
+					"Persistent Routes:\r\n" +
+					"  None\r\n",
+			))).To(Equal([]string{"10.8.0.0/16", "192.168.1.0/24"}))
+		})
+	})
+})
@@ -0,0 +1,11 @@
+package network
+
+import "os/exec"
+
+func hostRoutes() (routes []string, err error) {
+	output, err := exec.Command("route", "print", "-4").Output()
+	if err != nil {
+		return nil, err
+	}
+	return ParseRoutePrint(output), nil
+}
@@ -1,6 +1,7 @@
 package pivnet
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -22,6 +23,7 @@ type Client struct {
 	Host          string
 	ReleaseId     string
 	ProductFileId string
+	Transport     http.RoundTripper
 }
 
 type ReleaseResponse struct {
@@ -46,8 +48,8 @@ type EULAResponse struct {
 	Content string `json:"content"`
 }
 
-func (c *Client) DownloadOVA(startAtByte int64) (ova *DownloadReader, err error) {
-	resp, err := c.requestOva(fmt.Sprintf("bytes=%d-", startAtByte))
+func (c *Client) DownloadOVA(ctx context.Context, startAtByte int64) (ova *DownloadReader, err error) {
+	resp, err := c.requestOva(ctx, fmt.Sprintf("bytes=%d-", startAtByte))
 	if err != nil {
 		return nil, err
 	}
@@ -63,8 +65,8 @@ func (c *Client) DownloadOVA(startAtByte int64) (ova *DownloadReader, err error)
 	}
 }
 
-func (c *Client) IsEULAAccepted() (bool, error) {
-	resp, err := c.requestOva("bytes=0-0")
+func (c *Client) IsEULAAccepted(ctx context.Context) (bool, error) {
+	resp, err := c.requestOva(ctx, "bytes=0-0")
 	if err != nil {
 		return false, err
 	}
@@ -82,9 +84,9 @@ func (c *Client) IsEULAAccepted() (bool, error) {
 	}
 }
 
-func (c *Client) GetEULA() (eula string, err error) {
+func (c *Client) GetEULA(ctx context.Context) (eula string, err error) {
 	uri := fmt.Sprintf("%s/api/v2/products/pcfdev/releases/%s", c.Host, c.ReleaseId)
-	resp, err := c.makeRequest(uri, "GET", http.DefaultClient)
+	resp, err := c.makeRequest(ctx, uri, "GET", c.httpClient())
 	if err != nil {
 		return "", &PivNetUnreachableError{err}
 	}
@@ -110,7 +112,7 @@ func (c *Client) GetEULA() (eula string, err error) {
 	}
 
 	uri = fmt.Sprintf(releaseResponse.EULAS.Links.Self.HREF)
-	resp, err = c.makeRequest(uri, "GET", http.DefaultClient)
+	resp, err = c.makeRequest(ctx, uri, "GET", c.httpClient())
 	if err != nil {
 		return "", &PivNetUnreachableError{err}
 	}
@@ -139,8 +141,8 @@ func (c *Client) GetEULA() (eula string, err error) {
 	return sanitize.HTML(eulaResponse.Content), nil
 }
 
-func (c *Client) AcceptEULA() error {
-	resp, err := c.requestOva("bytes=0-0")
+func (c *Client) AcceptEULA(ctx context.Context) error {
+	resp, err := c.requestOva(ctx, "bytes=0-0")
 	if err != nil {
 		return &PivNetUnreachableError{err}
 	}
@@ -167,7 +169,7 @@ func (c *Client) AcceptEULA() error {
 
 	uri := fmt.Sprintf(eulaAcceptanceResponse.Links.Agreement.HREF)
 
-	resp, err = c.makeRequest(uri, "POST", http.DefaultClient)
+	resp, err = c.makeRequest(ctx, uri, "POST", c.httpClient())
 	if err != nil {
 		return &PivNetUnreachableError{err}
 	}
@@ -200,7 +202,7 @@ func (c *Client) GetToken(username string, password string) (string, error) {
 	query.Add("password", password)
 	req.URL.RawQuery = query.Encode()
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.httpClient().Do(req)
 	if err != nil {
 		return "", err
 	}
@@ -234,22 +236,28 @@ func (c *Client) unexpectedResponseError(resp *http.Response) error {
 	return &UnexpectedResponseError{fmt.Errorf("Pivotal Network returned: %s", resp.Status)}
 }
 
-func (c *Client) requestOva(byteRange string) (*http.Response, error) {
+func (c *Client) requestOva(ctx context.Context, byteRange string) (*http.Response, error) {
 	uri := fmt.Sprintf("%s/api/v2/products/pcfdev/releases/%s/product_files/%s/download", c.Host, c.ReleaseId, c.ProductFileId)
 	client := &http.Client{
+		Transport: c.Transport,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			req.Header.Set("Range", byteRange)
 			return nil
 		},
 	}
-	return c.makeRequest(uri, "POST", client)
+	return c.makeRequest(ctx, uri, "POST", client)
 }
 
-func (c *Client) makeRequest(uri string, method string, client *http.Client) (*http.Response, error) {
+func (c *Client) httpClient() *http.Client {
+	return &http.Client{Transport: c.Transport}
+}
+
+func (c *Client) makeRequest(ctx context.Context, uri string, method string, client *http.Client) (*http.Response, error) {
 	req, err := http.NewRequest(method, uri, nil)
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
 
 	token, err := c.Token.Get()
 	if err != nil {
@@ -1,6 +1,7 @@
 package pivnet_test
 
 import (
+	"context"
 	"errors"
 	"io/ioutil"
 	"net/http"
@@ -59,7 +60,7 @@ var _ = Describe("Pivnet Client", func() {
 				client.Host = httptest.NewServer(http.HandlerFunc(handler)).URL
 
 				mockToken.EXPECT().Get().Return("some-token", nil)
-				ova, err := client.DownloadOVA(int64(4))
+				ova, err := client.DownloadOVA(context.Background(), int64(4))
 				Expect(err).NotTo(HaveOccurred())
 				Expect(ova.ExistingLength).To(Equal(int64(4)))
 				Expect(ova.ContentLength).To(Equal(int64(12)))
@@ -90,7 +91,7 @@ var _ = Describe("Pivnet Client", func() {
 				client.Host = httptest.NewServer(http.HandlerFunc(handler)).URL
 
 				mockToken.EXPECT().Get().Return("some-token", nil)
-				ova, err := client.DownloadOVA(int64(4))
+				ova, err := client.DownloadOVA(context.Background(), int64(4))
 				Expect(err).NotTo(HaveOccurred())
 				Expect(ova.ExistingLength).To(Equal(int64(4)))
 				Expect(ova.ContentLength).To(Equal(int64(12)))
@@ -105,7 +106,7 @@ var _ = Describe("Pivnet Client", func() {
 				client.Host = "some-bad-host"
 
 				mockToken.EXPECT().Get().Return("some-token", nil)
-				_, err := client.DownloadOVA(int64(0))
+				_, err := client.DownloadOVA(context.Background(), int64(0))
 				Expect(err).To(MatchError(ContainSubstring("failed to reach Pivotal Network:")))
 			})
 		})
@@ -115,7 +116,7 @@ var _ = Describe("Pivnet Client", func() {
 				client.Host = "some-bad-host"
 
 				mockToken.EXPECT().Get().Return("some-token", errors.New("some-error"))
-				_, err := client.DownloadOVA(int64(0))
+				_, err := client.DownloadOVA(context.Background(), int64(0))
 				Expect(err).To(MatchError("some-error"))
 			})
 		})
@@ -130,7 +131,7 @@ var _ = Describe("Pivnet Client", func() {
 
 				mockToken.EXPECT().Get().Return("some-token", nil)
 
-				_, err := client.DownloadOVA(int64(0))
+				_, err := client.DownloadOVA(context.Background(), int64(0))
 				Expect(err).To(MatchError(ContainSubstring("Pivotal Network returned:")))
 			})
 		})
@@ -147,7 +148,7 @@ var _ = Describe("Pivnet Client", func() {
 					mockToken.EXPECT().Get().Return("some-bad-token", nil),
 					mockToken.EXPECT().Destroy(),
 				)
-				_, err := client.DownloadOVA(int64(0))
+				_, err := client.DownloadOVA(context.Background(), int64(0))
 				Expect(err).To(MatchError(MatchRegexp("invalid Pivotal Network API token")))
 			})
 		})
@@ -175,7 +176,7 @@ var _ = Describe("Pivnet Client", func() {
 			client.Host = httptest.NewServer(http.HandlerFunc(handler)).URL
 			mockToken.EXPECT().Get().Return("some-token", nil).Times(2)
 
-			Expect(client.AcceptEULA()).To(Succeed())
+			Expect(client.AcceptEULA(context.Background())).To(Succeed())
 		})
 
 		Context("when getting a token returns an error", func() {
@@ -184,7 +185,7 @@ var _ = Describe("Pivnet Client", func() {
 
 				mockToken.EXPECT().Get().Return("some-token", errors.New("some-error"))
 
-				Expect(client.AcceptEULA()).To(MatchError(ContainSubstring("failed to reach Pivotal Network:")))
+				Expect(client.AcceptEULA(context.Background())).To(MatchError(ContainSubstring("failed to reach Pivotal Network:")))
 			})
 		})
 
@@ -208,7 +209,7 @@ var _ = Describe("Pivnet Client", func() {
 
 				mockToken.EXPECT().Get().Return("some-token", nil)
 
-				Expect(client.AcceptEULA()).To(MatchError(ContainSubstring("failed to parse network response:")))
+				Expect(client.AcceptEULA(context.Background())).To(MatchError(ContainSubstring("failed to parse network response:")))
 			})
 		})
 
@@ -224,7 +225,7 @@ var _ = Describe("Pivnet Client", func() {
 					mockToken.EXPECT().Get().Return("some-bad-token", nil),
 					mockToken.EXPECT().Destroy(),
 				)
-				Expect(client.AcceptEULA()).To(MatchError("invalid Pivotal Network API token"))
+				Expect(client.AcceptEULA(context.Background())).To(MatchError("invalid Pivotal Network API token"))
 			})
 		})
 
@@ -237,7 +238,7 @@ var _ = Describe("Pivnet Client", func() {
 				client.Host = httptest.NewServer(http.HandlerFunc(handler)).URL
 				mockToken.EXPECT().Get().Return("some-token", nil)
 
-				Expect(client.AcceptEULA()).To(MatchError("Pivotal Network returned: 501 Not Implemented"))
+				Expect(client.AcceptEULA(context.Background())).To(MatchError("Pivotal Network returned: 501 Not Implemented"))
 			})
 		})
 
@@ -246,7 +247,7 @@ var _ = Describe("Pivnet Client", func() {
 				client.Host = "some-bad-host"
 				mockToken.EXPECT().Get().Return("some-token", nil)
 
-				Expect(client.AcceptEULA()).To(MatchError(ContainSubstring("failed to reach Pivotal Network:")))
+				Expect(client.AcceptEULA(context.Background())).To(MatchError(ContainSubstring("failed to reach Pivotal Network:")))
 			})
 		})
 
@@ -269,7 +270,7 @@ var _ = Describe("Pivnet Client", func() {
 				client.Host = httptest.NewServer(http.HandlerFunc(handler)).URL
 				mockToken.EXPECT().Get().Return("some-token", nil).Times(2)
 
-				Expect(client.AcceptEULA()).To(MatchError(ContainSubstring("failed to reach Pivotal Network:")))
+				Expect(client.AcceptEULA(context.Background())).To(MatchError(ContainSubstring("failed to reach Pivotal Network:")))
 			})
 		})
 
@@ -298,7 +299,7 @@ var _ = Describe("Pivnet Client", func() {
 					mockToken.EXPECT().Get().Return("some-token", nil).Times(2),
 					mockToken.EXPECT().Destroy(),
 				)
-				Expect(client.AcceptEULA()).To(MatchError("invalid Pivotal Network API token"))
+				Expect(client.AcceptEULA(context.Background())).To(MatchError("invalid Pivotal Network API token"))
 			})
 		})
 
@@ -324,7 +325,7 @@ var _ = Describe("Pivnet Client", func() {
 				client.Host = httptest.NewServer(http.HandlerFunc(handler)).URL
 				mockToken.EXPECT().Get().Return("some-token", nil).Times(2)
 
-				Expect(client.AcceptEULA()).To(MatchError("Pivotal Network returned: 500 Internal Server Error"))
+				Expect(client.AcceptEULA(context.Background())).To(MatchError("Pivotal Network returned: 500 Internal Server Error"))
 			})
 		})
 	})
@@ -352,7 +353,7 @@ var _ = Describe("Pivnet Client", func() {
 				}
 				client.Host = httptest.NewServer(http.HandlerFunc(handler)).URL
 				mockToken.EXPECT().Get().Return("some-token", nil)
-				Expect(client.IsEULAAccepted()).To(BeTrue())
+				Expect(client.IsEULAAccepted(context.Background())).To(BeTrue())
 			})
 		})
 
@@ -378,14 +379,14 @@ var _ = Describe("Pivnet Client", func() {
 				}
 				client.Host = httptest.NewServer(http.HandlerFunc(handler)).URL
 				mockToken.EXPECT().Get().Return("some-token", nil)
-				Expect(client.IsEULAAccepted()).To(BeTrue())
+				Expect(client.IsEULAAccepted(context.Background())).To(BeTrue())
 			})
 		})
 
 		Context("when getting the token returns an error", func() {
 			It("should return the error", func() {
 				mockToken.EXPECT().Get().Return("some-token", errors.New("some-error"))
-				_, err := client.IsEULAAccepted()
+				_, err := client.IsEULAAccepted(context.Background())
 				Expect(err).To(MatchError("some-error"))
 			})
 		})
@@ -400,7 +401,7 @@ var _ = Describe("Pivnet Client", func() {
 				client.Host = httptest.NewServer(http.HandlerFunc(handler)).URL
 				mockToken.EXPECT().Get().Return("some-bad-token", nil)
 				mockToken.EXPECT().Destroy()
-				_, err := client.IsEULAAccepted()
+				_, err := client.IsEULAAccepted(context.Background())
 				Expect(err).To(MatchError(&pivnet.InvalidTokenError{}))
 			})
 		})
@@ -414,7 +415,7 @@ var _ = Describe("Pivnet Client", func() {
 
 				client.Host = httptest.NewServer(http.HandlerFunc(handler)).URL
 				mockToken.EXPECT().Get().Return("some-token", nil)
-				Expect(client.IsEULAAccepted()).To(BeFalse())
+				Expect(client.IsEULAAccepted(context.Background())).To(BeFalse())
 			})
 		})
 	})
@@ -440,13 +441,13 @@ var _ = Describe("Pivnet Client", func() {
 			}
 			client.Host = httptest.NewServer(http.HandlerFunc(handler)).URL
 			mockToken.EXPECT().Get().Return("some-token", nil).Times(2)
-			Expect(client.GetEULA()).To(Equal("some-eula-text\n"))
+			Expect(client.GetEULA(context.Background())).To(Equal("some-eula-text\n"))
 		})
 
 		Context("when getting the token returns an error", func() {
 			It("should return the error", func() {
 				mockToken.EXPECT().Get().Return("some-token", errors.New("some-error"))
-				_, err := client.GetEULA()
+				_, err := client.GetEULA(context.Background())
 				Expect(err).To(MatchError(ContainSubstring("failed to reach Pivotal Network:")))
 			})
 		})
@@ -456,7 +457,7 @@ var _ = Describe("Pivnet Client", func() {
 				client.Host = "some-bad-host"
 
 				mockToken.EXPECT().Get().Return("some-token", nil)
-				_, err := client.GetEULA()
+				_, err := client.GetEULA(context.Background())
 				Expect(err).To(MatchError(ContainSubstring("failed to reach Pivotal Network:")))
 			})
 		})
@@ -473,7 +474,7 @@ var _ = Describe("Pivnet Client", func() {
 					mockToken.EXPECT().Get().Return("some-bad-token", nil),
 					mockToken.EXPECT().Destroy(),
 				)
-				_, err := client.GetEULA()
+				_, err := client.GetEULA(context.Background())
 				Expect(err).To(MatchError(MatchRegexp("invalid Pivotal Network API token")))
 			})
 		})
@@ -486,7 +487,7 @@ var _ = Describe("Pivnet Client", func() {
 				}
 				client.Host = httptest.NewServer(http.HandlerFunc(handler)).URL
 				mockToken.EXPECT().Get().Return("some-token", nil)
-				_, err := client.GetEULA()
+				_, err := client.GetEULA(context.Background())
 				Expect(err).To(MatchError("Pivotal Network returned: 400 Bad Request"))
 			})
 		})
@@ -500,7 +501,7 @@ var _ = Describe("Pivnet Client", func() {
 				}
 				client.Host = httptest.NewServer(http.HandlerFunc(handler)).URL
 				mockToken.EXPECT().Get().Return("some-token", nil)
-				_, err := client.GetEULA()
+				_, err := client.GetEULA(context.Background())
 				Expect(err).To(MatchError(ContainSubstring("failed to parse network response:")))
 			})
 		})
@@ -519,7 +520,7 @@ var _ = Describe("Pivnet Client", func() {
 				}
 				client.Host = httptest.NewServer(http.HandlerFunc(handler)).URL
 				mockToken.EXPECT().Get().Return("some-token", nil).Times(2)
-				_, err := client.GetEULA()
+				_, err := client.GetEULA(context.Background())
 				Expect(err).To(MatchError(ContainSubstring("failed to reach Pivotal Network:")))
 			})
 		})
@@ -544,7 +545,7 @@ var _ = Describe("Pivnet Client", func() {
 					mockToken.EXPECT().Get().Return("some-token", nil).Times(2),
 					mockToken.EXPECT().Destroy(),
 				)
-				_, err := client.GetEULA()
+				_, err := client.GetEULA(context.Background())
 				Expect(err).To(MatchError(MatchRegexp("invalid Pivotal Network API token")))
 			})
 		})
@@ -565,7 +566,7 @@ var _ = Describe("Pivnet Client", func() {
 				}
 				client.Host = httptest.NewServer(http.HandlerFunc(handler)).URL
 				mockToken.EXPECT().Get().Return("some-token", nil).Times(2)
-				_, err := client.GetEULA()
+				_, err := client.GetEULA(context.Background())
 				Expect(err).To(MatchError("Pivotal Network returned: 400 Bad Request"))
 			})
 		})
@@ -591,7 +592,7 @@ var _ = Describe("Pivnet Client", func() {
 				}
 				client.Host = httptest.NewServer(http.HandlerFunc(handler)).URL
 				mockToken.EXPECT().Get().Return("some-token", nil).Times(2)
-				_, err := client.GetEULA()
+				_, err := client.GetEULA(context.Background())
 				Expect(err).To(MatchError(ContainSubstring("failed to parse network response:")))
 			})
 		})
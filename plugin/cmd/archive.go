@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/cloudfoundry/cli/cf/flags"
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/vm"
+)
+
+const ARCHIVE_ARGS = 1
+
+// ArchiveCmd holds what backup and restore share: they take the path of an
+// archive and act on the current VM.
+type ArchiveCmd struct {
+	VBox        VBox
+	VMBuilder   VMBuilder
+	Config      *config.Config
+	ArchivePath string
+}
+
+type BackupCmd struct {
+	ArchiveCmd
+}
+
+type RestoreCmd struct {
+	ArchiveCmd
+}
+
+func (a *ArchiveCmd) Parse(args []string) error {
+	if err := parse(flags.New(), args, ARCHIVE_ARGS); err != nil {
+		return err
+	}
+	a.ArchivePath = args[0]
+	return nil
+}
+
+func (b *BackupCmd) Run(ctx context.Context) error {
+	vm, err := b.getVM(ctx)
+	if err != nil {
+		return err
+	}
+	return vm.Backup(ctx, b.ArchivePath)
+}
+
+func (r *RestoreCmd) Run(ctx context.Context) error {
+	vm, err := r.getVM(ctx)
+	if err != nil {
+		return err
+	}
+	return vm.Restore(ctx, r.ArchivePath)
+}
+
+func (a *ArchiveCmd) getVM(ctx context.Context) (vm vm.VM, err error) {
+	name, err := a.VBox.GetVMName()
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = a.Config.DefaultVMName
+	}
+	if name != a.Config.DefaultVMName && name != "pcfdev-custom" {
+		return nil, &OldVMError{}
+	}
+
+	return a.VMBuilder.VM(ctx, name)
+}
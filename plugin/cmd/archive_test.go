@@ -0,0 +1,167 @@
+package cmd_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/plugin/cmd"
+	"github.com/pivotal-cf/pcfdev-cli/plugin/cmd/mocks"
+	vmMocks "github.com/pivotal-cf/pcfdev-cli/vm/mocks"
+)
+
+var _ = Describe("ArchiveCmd", func() {
+	var (
+		mockCtrl      *gomock.Controller
+		mockVMBuilder *mocks.MockVMBuilder
+		mockVBox      *mocks.MockVBox
+		mockVM        *vmMocks.MockVM
+		archiveCmd    cmd.ArchiveCmd
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockVMBuilder = mocks.NewMockVMBuilder(mockCtrl)
+		mockVBox = mocks.NewMockVBox(mockCtrl)
+		mockVM = vmMocks.NewMockVM(mockCtrl)
+		archiveCmd = cmd.ArchiveCmd{
+			VBox:      mockVBox,
+			VMBuilder: mockVMBuilder,
+			Config: &config.Config{
+				DefaultVMName: "some-default-vm-name",
+			},
+			ArchivePath: "some-archive-path",
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("Parse", func() {
+		Context("when the correct number of arguments are passed", func() {
+			It("should succeed", func() {
+				Expect(archiveCmd.Parse([]string{"some-other-archive-path"})).To(Succeed())
+				Expect(archiveCmd.ArchivePath).To(Equal("some-other-archive-path"))
+			})
+		})
+		Context("when no archive path is passed", func() {
+			It("should fail", func() {
+				Expect(archiveCmd.Parse([]string{})).NotTo(Succeed())
+			})
+		})
+		Context("when the wrong number of arguments are passed", func() {
+			It("should fail", func() {
+				Expect(archiveCmd.Parse([]string{"some-archive-path", "some-bad-arg"})).NotTo(Succeed())
+			})
+		})
+		Context("when an unknown flag is passed", func() {
+			It("should fail", func() {
+				Expect(archiveCmd.Parse([]string{"some-archive-path", "--some-bad-flag"})).NotTo(Succeed())
+			})
+		})
+	})
+
+	for _, c := range []struct {
+		name    string
+		newCmd  func(cmd.ArchiveCmd) cmd.Cmd
+		archive func(*vmMocks.MockVM) *gomock.Call
+	}{
+		{
+			name:   "BackupCmd",
+			newCmd: func(a cmd.ArchiveCmd) cmd.Cmd { return &cmd.BackupCmd{ArchiveCmd: a} },
+			archive: func(mockVM *vmMocks.MockVM) *gomock.Call {
+				return mockVM.EXPECT().Backup(gomock.Any(), "some-archive-path")
+			},
+		},
+		{
+			name:   "RestoreCmd",
+			newCmd: func(a cmd.ArchiveCmd) cmd.Cmd { return &cmd.RestoreCmd{ArchiveCmd: a} },
+			archive: func(mockVM *vmMocks.MockVM) *gomock.Call {
+				return mockVM.EXPECT().Restore(gomock.Any(), "some-archive-path")
+			},
+		},
+	} {
+		c := c
+
+		Describe(c.name+" Run", func() {
+			Context("when the default vm is present", func() {
+				It("should succeed", func() {
+					gomock.InOrder(
+						mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+						mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
+						c.archive(mockVM),
+					)
+
+					Expect(c.newCmd(archiveCmd).Run(context.Background())).To(Succeed())
+				})
+			})
+
+			Context("when the custom vm is present", func() {
+				It("should succeed", func() {
+					gomock.InOrder(
+						mockVBox.EXPECT().GetVMName().Return("pcfdev-custom", nil),
+						mockVMBuilder.EXPECT().VM(gomock.Any(), "pcfdev-custom").Return(mockVM, nil),
+						c.archive(mockVM),
+					)
+
+					Expect(c.newCmd(archiveCmd).Run(context.Background())).To(Succeed())
+				})
+			})
+
+			Context("when there is no vm present", func() {
+				It("should use the default VM", func() {
+					gomock.InOrder(
+						mockVBox.EXPECT().GetVMName().Return("", nil),
+						mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
+						c.archive(mockVM),
+					)
+
+					Expect(c.newCmd(archiveCmd).Run(context.Background())).To(Succeed())
+				})
+			})
+
+			Context("when there is an old vm present", func() {
+				It("should tell the user to destroy pcfdev", func() {
+					mockVBox.EXPECT().GetVMName().Return("some-old-vm-name", nil)
+
+					Expect(c.newCmd(archiveCmd).Run(context.Background())).To(MatchError("old version of PCF Dev already running, please run `cf dev upgrade` or `cf dev destroy` to continue"))
+				})
+			})
+
+			Context("when there is an error getting the VM name", func() {
+				It("should return the error", func() {
+					mockVBox.EXPECT().GetVMName().Return("", errors.New("some-error"))
+
+					Expect(c.newCmd(archiveCmd).Run(context.Background())).To(MatchError("some-error"))
+				})
+			})
+
+			Context("when the VM fails to archive", func() {
+				It("should return an error", func() {
+					gomock.InOrder(
+						mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+						mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
+						c.archive(mockVM).Return(errors.New("some-error")),
+					)
+
+					Expect(c.newCmd(archiveCmd).Run(context.Background())).To(MatchError("some-error"))
+				})
+			})
+
+			Context("when it fails to get VM", func() {
+				It("should return an error", func() {
+					gomock.InOrder(
+						mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+						mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(nil, errors.New("some-error")),
+					)
+
+					Expect(c.newCmd(archiveCmd).Run(context.Background())).To(MatchError("some-error"))
+				})
+			})
+		})
+	}
+})
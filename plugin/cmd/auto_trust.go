@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"github.com/pivotal-cf/pcfdev-cli/config"
 	"github.com/pivotal-cf/pcfdev-cli/vm"
 )
@@ -11,15 +12,15 @@ type AutoTrustCmd struct {
 	Config    *config.Config
 }
 
-func (t *AutoTrustCmd) Run() error {
-	currentVM, err := t.getVM()
+func (t *AutoTrustCmd) Run(ctx context.Context) error {
+	currentVM, err := t.getVM(ctx)
 	if err != nil {
 		return err
 	}
-	return currentVM.Trust(&vm.StartOpts{})
+	return currentVM.Trust(ctx, &vm.StartOpts{})
 }
 
-func (t *AutoTrustCmd) getVM() (vm vm.VM, err error) {
+func (t *AutoTrustCmd) getVM(ctx context.Context) (vm vm.VM, err error) {
 	name, err := t.VBox.GetVMName()
 	if err != nil {
 		return nil, err
@@ -31,5 +32,5 @@ func (t *AutoTrustCmd) getVM() (vm vm.VM, err error) {
 		return nil, &OldVMError{}
 	}
 
-	return t.VMBuilder.VM(name)
+	return t.VMBuilder.VM(ctx, name)
 }
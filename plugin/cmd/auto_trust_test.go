@@ -1,6 +1,7 @@
 package cmd_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/golang/mock/gomock"
@@ -44,18 +45,18 @@ var _ = Describe("AutoTrustCmd", func() {
 		It("should call Trust on the VM", func() {
 			gomock.InOrder(
 				mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
-				mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
-				mockVM.EXPECT().Trust(&vm.StartOpts{}),
+				mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
+				mockVM.EXPECT().Trust(gomock.Any(), &vm.StartOpts{}),
 			)
 
-			Expect(autoTrustCmd.Run()).To(Succeed())
+			Expect(autoTrustCmd.Run(context.Background())).To(Succeed())
 		})
 
 		Context("when there is an error getting the VM name", func() {
 			It("should return the error", func() {
 				mockVBox.EXPECT().GetVMName().Return("", errors.New("some-error"))
 
-				Expect(autoTrustCmd.Run()).To(MatchError("some-error"))
+				Expect(autoTrustCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -63,10 +64,10 @@ var _ = Describe("AutoTrustCmd", func() {
 			It("should return the error", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(nil, errors.New("some-error")),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(nil, errors.New("some-error")),
 				)
 
-				Expect(autoTrustCmd.Run()).To(MatchError("some-error"))
+				Expect(autoTrustCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -74,11 +75,11 @@ var _ = Describe("AutoTrustCmd", func() {
 			It("should return the error", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
-					mockVM.EXPECT().Trust(&vm.StartOpts{}).Return(errors.New("some-error")),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
+					mockVM.EXPECT().Trust(gomock.Any(), &vm.StartOpts{}).Return(errors.New("some-error")),
 				)
 
-				Expect(autoTrustCmd.Run()).To(MatchError("some-error"))
+				Expect(autoTrustCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 	})
@@ -1,14 +1,18 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"io"
+	"time"
 
 	"github.com/cloudfoundry/cli/cf/flags"
+	"github.com/pivotal-cf/pcfdev-cli/backup"
 	"github.com/pivotal-cf/pcfdev-cli/cert"
 	"github.com/pivotal-cf/pcfdev-cli/config"
 	"github.com/pivotal-cf/pcfdev-cli/downloader"
-	"github.com/pivotal-cf/pcfdev-cli/runner"
+	"github.com/pivotal-cf/pcfdev-cli/fs"
+	"github.com/pivotal-cf/pcfdev-cli/manifest"
 	"github.com/pivotal-cf/pcfdev-cli/vboxdriver"
 	"github.com/pivotal-cf/pcfdev-cli/vm"
 )
@@ -24,7 +28,10 @@ type UI interface {
 type VBox interface {
 	GetVMName() (name string, err error)
 	VMConfig(vmName string) (vmConfig *config.VMConfig, err error)
-	DestroyPCFDevVMs() (err error)
+	VMStatus(vmName string) (status string, err error)
+	DestroyPCFDevVMs(ctx context.Context) (err error)
+	PowerOffVM(vmConfig *config.VMConfig) (err error)
+	DestroyVM(ctx context.Context, vmConfig *config.VMConfig) (err error)
 	Version() (version *vboxdriver.VBoxDriverVersion, err error)
 }
 
@@ -32,16 +39,23 @@ type VBox interface {
 type FS interface {
 	Write(path string, contents io.Reader, append bool) error
 	Copy(source string, destination string) error
+	Move(source string, destination string) error
 	Exists(path string) (exists bool, err error)
 	MD5(path string) (md5 string, err error)
+	SHA256(path string) (sha256 string, err error)
 	Read(path string) (contents []byte, err error)
 	Remove(path string) error
 	TempDir() (string, error)
 }
 
+//go:generate mockgen -package mocks -destination mocks/ova_verifier.go github.com/pivotal-cf/pcfdev-cli/plugin/cmd OVAVerifier
+type OVAVerifier interface {
+	Verify(ctx context.Context, ovaPath string, expectedVersion string) (ovaManifest *manifest.Manifest, err error)
+}
+
 //go:generate mockgen -package mocks -destination mocks/vm_builder.go github.com/pivotal-cf/pcfdev-cli/plugin/cmd VMBuilder
 type VMBuilder interface {
-	VM(name string) (vm vm.VM, err error)
+	VM(ctx context.Context, name string) (vm vm.VM, err error)
 }
 
 //go:generate mockgen -package mocks -destination mocks/downloader_factory.go github.com/pivotal-cf/pcfdev-cli/plugin/cmd DownloaderFactory
@@ -54,12 +68,12 @@ type DownloaderFactory interface {
 //go:generate mockgen -package mocks -destination mocks/cmd.go github.com/pivotal-cf/pcfdev-cli/plugin/cmd Cmd
 type Cmd interface {
 	Parse([]string) error
-	Run() error
+	Run(ctx context.Context) error
 }
 
 //go:generate mockgen -package mocks -destination mocks/auto_cmd.go github.com/pivotal-cf/pcfdev-cli/plugin/cmd AutoCmd
 type AutoCmd interface {
-	Run() error
+	Run(ctx context.Context) error
 }
 
 //go:generate mockgen -package mocks -destination mocks/cert_store.go github.com/pivotal-cf/pcfdev-cli/plugin/cmd CertStore
@@ -67,6 +81,11 @@ type CertStore interface {
 	Unstore() error
 }
 
+type CmdRunner interface {
+	Run(command string, args ...string) (output []byte, err error)
+	RunContext(ctx context.Context, command string, args ...string) (output []byte, err error)
+}
+
 func parse(flagContext flags.FlagContext, args []string, expectedLength int) error {
 	if err := flagContext.Parse(args...); err != nil {
 		return err
@@ -77,12 +96,38 @@ func parse(flagContext flags.FlagContext, args []string, expectedLength int) err
 	return nil
 }
 
+// getVMConfig returns the config of the current PCF Dev VM, which must
+// already have been created.
+func getVMConfig(vbox VBox, conf *config.Config) (*config.VMConfig, error) {
+	name, err := vbox.GetVMName()
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, &NotCreatedError{}
+	}
+	if name != conf.DefaultVMName && name != "pcfdev-custom" {
+		return nil, &OldVMError{}
+	}
+
+	return vbox.VMConfig(name)
+}
+
 type Builder struct {
 	Client            Client
+	CmdRunner         CmdRunner
 	Config            *config.Config
+	DNSServer         DNSServer
 	DownloaderFactory DownloaderFactory
 	EULAUI            EULAUI
 	FS                FS
+	Guest             Guest
+	HostsFile         HostsFile
+	ManifestClient    ManifestClient
+	OVAVerifier       OVAVerifier
+	RouteLister       RouteLister
+	SOCKSServer       SOCKSServer
+	SSH               SSH
 	UI                UI
 	VBox              VBox
 	VMBuilder         VMBuilder
@@ -100,7 +145,7 @@ func (b *Builder) Cmd(subcommand string) (Cmd, error) {
 				CertStore: &cert.CertStore{
 					SystemStore: &cert.ConcreteSystemStore{
 						FS:        b.FS,
-						CmdRunner: &runner.CmdRunner{},
+						CmdRunner: b.CmdRunner,
 					},
 				},
 			},
@@ -112,6 +157,8 @@ func (b *Builder) Cmd(subcommand string) (Cmd, error) {
 			EULAUI:            b.EULAUI,
 			Client:            b.Client,
 			DownloaderFactory: b.DownloaderFactory,
+			ManifestClient:    b.ManifestClient,
+			OVAVerifier:       b.OVAVerifier,
 			FS:                b.FS,
 			Config:            b.Config,
 		}, nil
@@ -130,15 +177,19 @@ func (b *Builder) Cmd(subcommand string) (Cmd, error) {
 		}, nil
 	case "start":
 		return &StartCmd{
-			VBox:      b.VBox,
-			VMBuilder: b.VMBuilder,
-			Config:    b.Config,
+			VBox:        b.VBox,
+			VMBuilder:   b.VMBuilder,
+			Config:      b.Config,
+			OVAVerifier: b.OVAVerifier,
+			UI:          b.UI,
 			DownloadCmd: &DownloadCmd{
 				VBox:              b.VBox,
 				UI:                b.UI,
 				EULAUI:            b.EULAUI,
 				Client:            b.Client,
 				DownloaderFactory: b.DownloaderFactory,
+				ManifestClient:    b.ManifestClient,
+				OVAVerifier:       b.OVAVerifier,
 				FS:                b.FS,
 				Config:            b.Config,
 			},
@@ -184,6 +235,18 @@ func (b *Builder) Cmd(subcommand string) (Cmd, error) {
 			VBox:      b.VBox,
 			Config:    b.Config,
 		}, nil
+	case "backup":
+		return &BackupCmd{ArchiveCmd{
+			VBox:      b.VBox,
+			VMBuilder: b.VMBuilder,
+			Config:    b.Config,
+		}}, nil
+	case "restore":
+		return &RestoreCmd{ArchiveCmd{
+			VBox:      b.VBox,
+			VMBuilder: b.VMBuilder,
+			Config:    b.Config,
+		}}, nil
 	case "trust":
 		return &TrustCmd{
 			VBox:      b.VBox,
@@ -195,7 +258,7 @@ func (b *Builder) Cmd(subcommand string) (Cmd, error) {
 			CertStore: &cert.CertStore{
 				SystemStore: &cert.ConcreteSystemStore{
 					FS:        b.FS,
-					CmdRunner: &runner.CmdRunner{},
+					CmdRunner: b.CmdRunner,
 				},
 			},
 		}, nil
@@ -206,12 +269,75 @@ func (b *Builder) Cmd(subcommand string) (Cmd, error) {
 			Config:     b.Config,
 			AutoTarget: false,
 		}, nil
+	case "upgrade":
+		return &UpgradeCmd{
+			VBox:      b.VBox,
+			VMBuilder: b.VMBuilder,
+			Config:    b.Config,
+			UI:        b.UI,
+			FS:        b.FS,
+			DownloadCmd: &DownloadCmd{
+				VBox:              b.VBox,
+				UI:                b.UI,
+				EULAUI:            b.EULAUI,
+				Client:            b.Client,
+				DownloaderFactory: b.DownloaderFactory,
+				ManifestClient:    b.ManifestClient,
+				OVAVerifier:       b.OVAVerifier,
+				FS:                b.FS,
+				Config:            b.Config,
+				IgnoreOldVM:       true,
+			},
+			BackupReader: &backup.Archiver{
+				FS: &fs.FS{},
+			},
+		}, nil
+	case "dns":
+		return &DNSCmd{
+			VBox:      b.VBox,
+			UI:        b.UI,
+			Config:    b.Config,
+			DNSServer: b.DNSServer,
+		}, nil
+	case "hosts":
+		return &HostsCmd{
+			VBox:          b.VBox,
+			UI:            b.UI,
+			Config:        b.Config,
+			HostsFile:     b.HostsFile,
+			RouteLister:   b.RouteLister,
+			WatchInterval: 30 * time.Second,
+		}, nil
+	case "proxy":
+		return &ProxyCmd{
+			VBox:       b.VBox,
+			UI:         b.UI,
+			Config:     b.Config,
+			ProxyStore: b.Config,
+			Guest:      b.Guest,
+		}, nil
 	case "ssh":
 		return &SSHCmd{
 			VBox:      b.VBox,
 			VMBuilder: b.VMBuilder,
 			Config:    b.Config,
 		}, nil
+	case "socks":
+		return &SOCKSCmd{
+			VBox:        b.VBox,
+			UI:          b.UI,
+			Config:      b.Config,
+			FS:          b.FS,
+			SSH:         b.SSH,
+			SOCKSServer: b.SOCKSServer,
+		}, nil
+	case "ssh-key":
+		return &SSHKeyCmd{
+			VBox:   b.VBox,
+			UI:     b.UI,
+			Config: b.Config,
+			Guest:  b.Guest,
+		}, nil
 	default:
 		return nil, errors.New("")
 	}
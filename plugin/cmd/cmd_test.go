@@ -2,16 +2,25 @@ package cmd_test
 
 import (
 	"os"
+	"time"
 
 	"github.com/cloudfoundry/cli/cf/terminal"
 	"github.com/cloudfoundry/cli/cf/trace"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/dns"
 	"github.com/pivotal-cf/pcfdev-cli/downloader"
 	"github.com/pivotal-cf/pcfdev-cli/fs"
+	"github.com/pivotal-cf/pcfdev-cli/guest"
+	"github.com/pivotal-cf/pcfdev-cli/hosts"
+	"github.com/pivotal-cf/pcfdev-cli/manifest"
+	"github.com/pivotal-cf/pcfdev-cli/mirror"
 	"github.com/pivotal-cf/pcfdev-cli/pivnet"
 	"github.com/pivotal-cf/pcfdev-cli/plugin/cmd"
+	"github.com/pivotal-cf/pcfdev-cli/runner"
+	"github.com/pivotal-cf/pcfdev-cli/socks"
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
 	"github.com/pivotal-cf/pcfdev-cli/ui"
 	"github.com/pivotal-cf/pcfdev-cli/vbox"
 	"github.com/pivotal-cf/pcfdev-cli/vm"
@@ -23,6 +32,7 @@ var _ = Describe("Builder", func() {
 		BeforeEach(func() {
 			builder = &cmd.Builder{
 				VBox:              &vbox.VBox{},
+				CmdRunner:         &runner.CmdRunner{},
 				DownloaderFactory: &downloader.DownloaderFactory{},
 				FS:                &fs.FS{},
 				UI: terminal.NewUI(
@@ -31,10 +41,18 @@ var _ = Describe("Builder", func() {
 					terminal.NewTeePrinter(os.Stdout),
 					trace.NewWriterPrinter(os.Stdout, true),
 				),
-				VMBuilder: &vm.VBoxBuilder{},
-				Config:    &config.Config{},
-				EULAUI:    &ui.UI{},
-				Client:    &pivnet.Client{},
+				VMBuilder:      &vm.VBoxBuilder{},
+				Config:         &config.Config{},
+				EULAUI:         &ui.UI{},
+				Client:         &pivnet.Client{},
+				DNSServer:      &dns.Server{},
+				Guest:          &guest.Guest{},
+				HostsFile:      &hosts.File{},
+				ManifestClient: &mirror.File{},
+				OVAVerifier:    &manifest.Verifier{},
+				RouteLister:    &hosts.CFClient{},
+				SOCKSServer:    &socks.Server{},
+				SSH:            &ssh.SSH{},
 			}
 		})
 
@@ -68,6 +86,8 @@ var _ = Describe("Builder", func() {
 					Expect(c.EULAUI).To(BeIdenticalTo(builder.EULAUI))
 					Expect(c.Client).To(BeIdenticalTo(builder.Client))
 					Expect(c.DownloaderFactory).To(BeIdenticalTo(builder.DownloaderFactory))
+					Expect(c.ManifestClient).To(BeIdenticalTo(builder.ManifestClient))
+					Expect(c.OVAVerifier).To(BeIdenticalTo(builder.OVAVerifier))
 					Expect(c.FS).To(BeIdenticalTo(builder.FS))
 					Expect(c.Config).To(BeIdenticalTo(builder.Config))
 				default:
@@ -119,12 +139,16 @@ var _ = Describe("Builder", func() {
 					Expect(c.VBox).To(BeIdenticalTo(builder.VBox))
 					Expect(c.VMBuilder).To(BeIdenticalTo(builder.VMBuilder))
 					Expect(c.Config).To(BeIdenticalTo(builder.Config))
+					Expect(c.OVAVerifier).To(BeIdenticalTo(builder.OVAVerifier))
+					Expect(c.UI).To(BeIdenticalTo(builder.UI))
 					Expect(c.DownloadCmd).To(Equal(&cmd.DownloadCmd{
 						VBox:              builder.VBox,
 						UI:                builder.UI,
 						EULAUI:            builder.EULAUI,
 						Client:            builder.Client,
 						DownloaderFactory: builder.DownloaderFactory,
+						ManifestClient:    builder.ManifestClient,
+						OVAVerifier:       builder.OVAVerifier,
 						FS:                builder.FS,
 						Config:            builder.Config,
 					}))
@@ -239,6 +263,73 @@ var _ = Describe("Builder", func() {
 			})
 		})
 
+		Context("when it is passed backup", func() {
+			It("should return a backup command", func() {
+				backupCmd, err := builder.Cmd("backup")
+				Expect(err).NotTo(HaveOccurred())
+
+				switch c := backupCmd.(type) {
+				case *cmd.BackupCmd:
+					Expect(c.VBox).To(BeIdenticalTo(builder.VBox))
+					Expect(c.VMBuilder).To(BeIdenticalTo(builder.VMBuilder))
+					Expect(c.Config).To(BeIdenticalTo(builder.Config))
+				default:
+					Fail("wrong type")
+				}
+			})
+		})
+
+		Context("when it is passed restore", func() {
+			It("should return a restore command", func() {
+				restoreCmd, err := builder.Cmd("restore")
+				Expect(err).NotTo(HaveOccurred())
+
+				switch c := restoreCmd.(type) {
+				case *cmd.RestoreCmd:
+					Expect(c.VBox).To(BeIdenticalTo(builder.VBox))
+					Expect(c.VMBuilder).To(BeIdenticalTo(builder.VMBuilder))
+					Expect(c.Config).To(BeIdenticalTo(builder.Config))
+				default:
+					Fail("wrong type")
+				}
+			})
+		})
+
+		Context("when it is passed upgrade", func() {
+			It("should return an upgrade command", func() {
+				upgradeCmd, err := builder.Cmd("upgrade")
+				Expect(err).NotTo(HaveOccurred())
+
+				switch c := upgradeCmd.(type) {
+				case *cmd.UpgradeCmd:
+					Expect(c.VBox).To(BeIdenticalTo(builder.VBox))
+					Expect(c.VMBuilder).To(BeIdenticalTo(builder.VMBuilder))
+					Expect(c.Config).To(BeIdenticalTo(builder.Config))
+					Expect(c.UI).To(BeIdenticalTo(builder.UI))
+					Expect(c.FS).To(BeIdenticalTo(builder.FS))
+					Expect(c.BackupReader).NotTo(BeNil())
+
+					switch d := c.DownloadCmd.(type) {
+					case *cmd.DownloadCmd:
+						Expect(d.VBox).To(BeIdenticalTo(builder.VBox))
+						Expect(d.UI).To(BeIdenticalTo(builder.UI))
+						Expect(d.EULAUI).To(BeIdenticalTo(builder.EULAUI))
+						Expect(d.Client).To(BeIdenticalTo(builder.Client))
+						Expect(d.DownloaderFactory).To(BeIdenticalTo(builder.DownloaderFactory))
+						Expect(d.ManifestClient).To(BeIdenticalTo(builder.ManifestClient))
+						Expect(d.OVAVerifier).To(BeIdenticalTo(builder.OVAVerifier))
+						Expect(d.FS).To(BeIdenticalTo(builder.FS))
+						Expect(d.Config).To(BeIdenticalTo(builder.Config))
+						Expect(d.IgnoreOldVM).To(BeTrue())
+					default:
+						Fail("wrong type")
+					}
+				default:
+					Fail("wrong type")
+				}
+			})
+		})
+
 		Context("when is is passed 'trust'", func() {
 			It("should return a trust command", func() {
 				trustCmd, err := builder.Cmd("trust")
@@ -302,6 +393,96 @@ var _ = Describe("Builder", func() {
 			})
 		})
 
+		Context("when is is passed 'dns'", func() {
+			It("should return a dns command", func() {
+				dnsCmd, err := builder.Cmd("dns")
+				Expect(err).NotTo(HaveOccurred())
+
+				switch c := dnsCmd.(type) {
+				case *cmd.DNSCmd:
+					Expect(c.VBox).To(BeIdenticalTo(builder.VBox))
+					Expect(c.UI).To(BeIdenticalTo(builder.UI))
+					Expect(c.Config).To(BeIdenticalTo(builder.Config))
+					Expect(c.DNSServer).To(BeIdenticalTo(builder.DNSServer))
+				default:
+					Fail("wrong type")
+				}
+			})
+		})
+
+		Context("when is is passed 'hosts'", func() {
+			It("should return a hosts command", func() {
+				hostsCmd, err := builder.Cmd("hosts")
+				Expect(err).NotTo(HaveOccurred())
+
+				switch c := hostsCmd.(type) {
+				case *cmd.HostsCmd:
+					Expect(c.VBox).To(BeIdenticalTo(builder.VBox))
+					Expect(c.UI).To(BeIdenticalTo(builder.UI))
+					Expect(c.Config).To(BeIdenticalTo(builder.Config))
+					Expect(c.HostsFile).To(BeIdenticalTo(builder.HostsFile))
+					Expect(c.RouteLister).To(BeIdenticalTo(builder.RouteLister))
+					Expect(c.WatchInterval).To(Equal(30 * time.Second))
+				default:
+					Fail("wrong type")
+				}
+			})
+		})
+
+		Context("when is is passed 'proxy'", func() {
+			It("should return a proxy command", func() {
+				proxyCmd, err := builder.Cmd("proxy")
+				Expect(err).NotTo(HaveOccurred())
+
+				switch c := proxyCmd.(type) {
+				case *cmd.ProxyCmd:
+					Expect(c.VBox).To(BeIdenticalTo(builder.VBox))
+					Expect(c.UI).To(BeIdenticalTo(builder.UI))
+					Expect(c.Config).To(BeIdenticalTo(builder.Config))
+					Expect(c.ProxyStore).To(BeIdenticalTo(builder.Config))
+					Expect(c.Guest).To(BeIdenticalTo(builder.Guest))
+				default:
+					Fail("wrong type")
+				}
+			})
+		})
+
+		Context("when is is passed 'socks'", func() {
+			It("should return a socks command", func() {
+				socksCmd, err := builder.Cmd("socks")
+				Expect(err).NotTo(HaveOccurred())
+
+				switch c := socksCmd.(type) {
+				case *cmd.SOCKSCmd:
+					Expect(c.VBox).To(BeIdenticalTo(builder.VBox))
+					Expect(c.UI).To(BeIdenticalTo(builder.UI))
+					Expect(c.Config).To(BeIdenticalTo(builder.Config))
+					Expect(c.FS).To(BeIdenticalTo(builder.FS))
+					Expect(c.SSH).To(BeIdenticalTo(builder.SSH))
+					Expect(c.SOCKSServer).To(BeIdenticalTo(builder.SOCKSServer))
+				default:
+					Fail("wrong type")
+				}
+			})
+		})
+
+		Context("when is is passed 'ssh-key'", func() {
+			It("should return an ssh-key command", func() {
+				sshKeyCmd, err := builder.Cmd("ssh-key")
+				Expect(err).NotTo(HaveOccurred())
+
+				switch c := sshKeyCmd.(type) {
+				case *cmd.SSHKeyCmd:
+					Expect(c.VBox).To(BeIdenticalTo(builder.VBox))
+					Expect(c.UI).To(BeIdenticalTo(builder.UI))
+					Expect(c.Config).To(BeIdenticalTo(builder.Config))
+					Expect(c.Guest).To(BeIdenticalTo(builder.Guest))
+				default:
+					Fail("wrong type")
+				}
+			})
+		})
+
 		Context("when it is passed an unknown subcommand", func() {
 			It("should return an error", func() {
 				_, err := builder.Cmd("some-bad-subcommand")
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"github.com/cloudfoundry/cli/cf/flags"
 	"github.com/pivotal-cf/pcfdev-cli/config"
 	"github.com/pivotal-cf/pcfdev-cli/vm"
@@ -18,15 +19,15 @@ func (d *DebugCmd) Parse(args []string) error {
 	return parse(flags.New(), args, DEBUG_ARGS)
 }
 
-func (d *DebugCmd) Run() error {
-	vm, err := d.getVM()
+func (d *DebugCmd) Run(ctx context.Context) error {
+	vm, err := d.getVM(ctx)
 	if err != nil {
 		return err
 	}
-	return vm.GetDebugLogs()
+	return vm.GetDebugLogs(ctx)
 }
 
-func (d *DebugCmd) getVM() (vm vm.VM, err error) {
+func (d *DebugCmd) getVM(ctx context.Context) (vm vm.VM, err error) {
 	name, err := d.VBox.GetVMName()
 	if err != nil {
 		return nil, err
@@ -38,5 +39,5 @@ func (d *DebugCmd) getVM() (vm vm.VM, err error) {
 		return nil, &OldVMError{}
 	}
 
-	return d.VMBuilder.VM(name)
+	return d.VMBuilder.VM(ctx, name)
 }
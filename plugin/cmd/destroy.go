@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -23,14 +24,14 @@ func (d *DestroyCmd) Parse(args []string) error {
 	return parse(flags.New(), args, DESTROY_ARGS)
 }
 
-func (d *DestroyCmd) Run() error {
+func (d *DestroyCmd) Run(ctx context.Context) error {
 	var errs []string
 
-	if err := d.UntrustCmd.Run(); err != nil {
+	if err := d.UntrustCmd.Run(ctx); err != nil {
 		errs = append(errs, fmt.Sprintf("error removing certificates from trust store: %s", err))
 	}
 
-	if err := d.VBox.DestroyPCFDevVMs(); err != nil {
+	if err := d.VBox.DestroyPCFDevVMs(ctx); err != nil {
 		errs = append(errs, fmt.Sprintf("error destroying PCF Dev VM: %s", err))
 	} else {
 		d.UI.Say("PCF Dev VM has been destroyed.")
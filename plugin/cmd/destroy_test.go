@@ -1,6 +1,7 @@
 package cmd_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/golang/mock/gomock"
@@ -63,62 +64,62 @@ var _ = Describe("DestroyCmd", func() {
 	Describe("Run", func() {
 		It("should destroy all PCF Dev VMs created by the CLI and the VM dir", func() {
 			gomock.InOrder(
-				mockUntrustCmd.EXPECT().Run(),
-				mockVBox.EXPECT().DestroyPCFDevVMs(),
+				mockUntrustCmd.EXPECT().Run(gomock.Any()),
+				mockVBox.EXPECT().DestroyPCFDevVMs(gomock.Any()),
 				mockUI.EXPECT().Say("PCF Dev VM has been destroyed."),
 				mockFS.EXPECT().Remove("some-vm-dir"),
 			)
 
-			Expect(destroyCmd.Run()).To(Succeed())
+			Expect(destroyCmd.Run(context.Background())).To(Succeed())
 		})
 
 		Context("when there is an error destroying PCF Dev VMs", func() {
 			It("should remove the VM dir and return an errpr", func() {
 				gomock.InOrder(
-					mockUntrustCmd.EXPECT().Run(),
-					mockVBox.EXPECT().DestroyPCFDevVMs().Return(errors.New("some-error")),
+					mockUntrustCmd.EXPECT().Run(gomock.Any()),
+					mockVBox.EXPECT().DestroyPCFDevVMs(gomock.Any()).Return(errors.New("some-error")),
 					mockFS.EXPECT().Remove("some-vm-dir"),
 				)
 
-				Expect(destroyCmd.Run()).To(MatchError("error destroying PCF Dev VM: some-error"))
+				Expect(destroyCmd.Run(context.Background())).To(MatchError("error destroying PCF Dev VM: some-error"))
 			})
 		})
 
 		Context("when there is an error removing the VM dir", func() {
 			It("should return an error", func() {
 				gomock.InOrder(
-					mockUntrustCmd.EXPECT().Run(),
-					mockVBox.EXPECT().DestroyPCFDevVMs(),
+					mockUntrustCmd.EXPECT().Run(gomock.Any()),
+					mockVBox.EXPECT().DestroyPCFDevVMs(gomock.Any()),
 					mockUI.EXPECT().Say("PCF Dev VM has been destroyed."),
 					mockFS.EXPECT().Remove("some-vm-dir").Return(errors.New("some-error")),
 				)
 
-				Expect(destroyCmd.Run()).To(MatchError("error removing some-vm-dir: some-error"))
+				Expect(destroyCmd.Run(context.Background())).To(MatchError("error removing some-vm-dir: some-error"))
 			})
 		})
 
 		Context("when there is an error destroying PCF Dev VMs and removing the VM dir", func() {
 			It("should return an error", func() {
 				gomock.InOrder(
-					mockUntrustCmd.EXPECT().Run(),
-					mockVBox.EXPECT().DestroyPCFDevVMs().Return(errors.New("some-error")),
+					mockUntrustCmd.EXPECT().Run(gomock.Any()),
+					mockVBox.EXPECT().DestroyPCFDevVMs(gomock.Any()).Return(errors.New("some-error")),
 					mockFS.EXPECT().Remove("some-vm-dir").Return(errors.New("some-error")),
 				)
 
-				Expect(destroyCmd.Run()).To(MatchError("error destroying PCF Dev VM: some-error\nerror removing some-vm-dir: some-error"))
+				Expect(destroyCmd.Run(context.Background())).To(MatchError("error destroying PCF Dev VM: some-error\nerror removing some-vm-dir: some-error"))
 			})
 		})
 
 		Context("when there is an error deleting from the trust store", func() {
 			It("should remove the VM dir and keep going and return an error", func() {
 				gomock.InOrder(
-					mockUntrustCmd.EXPECT().Run().Return(errors.New("some-error")),
-					mockVBox.EXPECT().DestroyPCFDevVMs(),
+					mockUntrustCmd.EXPECT().Run(gomock.Any()).Return(errors.New("some-error")),
+					mockVBox.EXPECT().DestroyPCFDevVMs(gomock.Any()),
 					mockUI.EXPECT().Say("PCF Dev VM has been destroyed."),
 					mockFS.EXPECT().Remove("some-vm-dir"),
 				)
 
-				Expect(destroyCmd.Run()).To(MatchError("error removing certificates from trust store: some-error"))
+				Expect(destroyCmd.Run(context.Background())).To(MatchError("error removing certificates from trust store: some-error"))
 			})
 		})
 	})
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"github.com/cloudfoundry/cli/cf/flags"
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/dns"
+)
+
+const DNS_ARGS = 0
+
+//go:generate mockgen -package mocks -destination mocks/dns_server.go github.com/pivotal-cf/pcfdev-cli/plugin/cmd DNSServer
+type DNSServer interface {
+	ListenAndServe(ctx context.Context, address string, ip string, domains []string) error
+}
+
+// DNSCmd answers DNS queries for the domain of the PCF Dev VM, and any custom
+// domains, with the address of the VM until it is interrupted.
+type DNSCmd struct {
+	VBox          VBox
+	UI            UI
+	Config        *config.Config
+	DNSServer     DNSServer
+	address       string
+	customDomains []string
+	resolved      bool
+}
+
+func (d *DNSCmd) Parse(args []string) error {
+	flagContext := flags.New()
+	flagContext.NewStringFlag("a", "", "<address>")
+	flagContext.NewStringSliceFlag("d", "", "<domain>")
+	flagContext.NewBoolFlag("resolved", "", "<resolved>")
+	if err := parse(flagContext, args, DNS_ARGS); err != nil {
+		return err
+	}
+
+	d.address = flagContext.String("a")
+	if d.address == "" {
+		d.address = dns.DefaultAddress
+	}
+	d.customDomains = flagContext.StringSlice("d")
+	d.resolved = flagContext.Bool("resolved")
+	return nil
+}
+
+func (d *DNSCmd) Run(ctx context.Context) error {
+	vmConfig, err := getVMConfig(d.VBox, d.Config)
+	if err != nil {
+		return err
+	}
+
+	domains := append([]string{vmConfig.Domain}, d.customDomains...)
+	if d.resolved {
+		d.UI.Say(dns.SystemdResolvedInstructions(d.address, domains))
+		return nil
+	}
+
+	for _, domain := range domains {
+		d.UI.Say("Resolving *.%s to %s", domain, vmConfig.IP)
+	}
+	d.UI.Say("Listening for DNS queries on %s. Press Ctrl-C to stop.", d.address)
+	return d.DNSServer.ListenAndServe(ctx, d.address, vmConfig.IP, domains)
+}
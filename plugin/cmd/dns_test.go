@@ -0,0 +1,155 @@
+package cmd_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/plugin/cmd"
+	"github.com/pivotal-cf/pcfdev-cli/plugin/cmd/mocks"
+)
+
+var _ = Describe("DNSCmd", func() {
+	var (
+		dnsCmd        *cmd.DNSCmd
+		mockCtrl      *gomock.Controller
+		mockVBox      *mocks.MockVBox
+		mockUI        *mocks.MockUI
+		mockDNSServer *mocks.MockDNSServer
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockVBox = mocks.NewMockVBox(mockCtrl)
+		mockUI = mocks.NewMockUI(mockCtrl)
+		mockDNSServer = mocks.NewMockDNSServer(mockCtrl)
+		dnsCmd = &cmd.DNSCmd{
+			VBox:      mockVBox,
+			UI:        mockUI,
+			DNSServer: mockDNSServer,
+			Config: &config.Config{
+				DefaultVMName: "some-default-vm-name",
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("Parse", func() {
+		Context("when the correct number of arguments are passed", func() {
+			It("should succeed", func() {
+				Expect(dnsCmd.Parse([]string{"-a", "127.0.0.1:53", "-d", "some-domain", "--resolved"})).To(Succeed())
+			})
+		})
+		Context("when the wrong number of arguments are passed", func() {
+			It("should fail", func() {
+				Expect(dnsCmd.Parse([]string{"some-bad-arg"})).NotTo(Succeed())
+			})
+		})
+		Context("when an unknown flag is passed", func() {
+			It("should fail", func() {
+				Expect(dnsCmd.Parse([]string{"--some-bad-flag"})).NotTo(Succeed())
+			})
+		})
+	})
+
+	Describe("Run", func() {
+		It("should serve the VM domain on the default address", func() {
+			Expect(dnsCmd.Parse([]string{})).To(Succeed())
+
+			gomock.InOrder(
+				mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+				mockVBox.EXPECT().VMConfig("some-default-vm-name").Return(&config.VMConfig{IP: "192.168.11.11", Domain: "local.pcfdev.io"}, nil),
+				mockUI.EXPECT().Say("Resolving *.%s to %s", "local.pcfdev.io", "192.168.11.11"),
+				mockUI.EXPECT().Say("Listening for DNS queries on %s. Press Ctrl-C to stop.", "127.0.0.1:5354"),
+				mockDNSServer.EXPECT().ListenAndServe(gomock.Any(), "127.0.0.1:5354", "192.168.11.11", []string{"local.pcfdev.io"}),
+			)
+
+			Expect(dnsCmd.Run(context.Background())).To(Succeed())
+		})
+
+		Context("when custom domains and an address are given", func() {
+			It("should serve the custom domains as well", func() {
+				Expect(dnsCmd.Parse([]string{"-a", "127.0.0.1:53", "-d", "some-domain", "-d", "some-other-domain"})).To(Succeed())
+
+				gomock.InOrder(
+					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+					mockVBox.EXPECT().VMConfig("some-default-vm-name").Return(&config.VMConfig{IP: "192.168.11.11", Domain: "local.pcfdev.io"}, nil),
+					mockUI.EXPECT().Say("Resolving *.%s to %s", "local.pcfdev.io", "192.168.11.11"),
+					mockUI.EXPECT().Say("Resolving *.%s to %s", "some-domain", "192.168.11.11"),
+					mockUI.EXPECT().Say("Resolving *.%s to %s", "some-other-domain", "192.168.11.11"),
+					mockUI.EXPECT().Say("Listening for DNS queries on %s. Press Ctrl-C to stop.", "127.0.0.1:53"),
+					mockDNSServer.EXPECT().ListenAndServe(gomock.Any(), "127.0.0.1:53", "192.168.11.11", []string{"local.pcfdev.io", "some-domain", "some-other-domain"}),
+				)
+
+				Expect(dnsCmd.Run(context.Background())).To(Succeed())
+			})
+		})
+
+		Context("when --resolved is given", func() {
+			It("should print the systemd-resolved setup instead of serving", func() {
+				Expect(dnsCmd.Parse([]string{"--resolved"})).To(Succeed())
+
+				gomock.InOrder(
+					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+					mockVBox.EXPECT().VMConfig("some-default-vm-name").Return(&config.VMConfig{IP: "192.168.11.11", Domain: "local.pcfdev.io"}, nil),
+					mockUI.EXPECT().Say(gomock.Any()).Do(func(message string, args ...interface{}) {
+						Expect(message).To(ContainSubstring(`DNS=127.0.0.1:5354\nDomains=~local.pcfdev.io\n`))
+					}),
+				)
+
+				Expect(dnsCmd.Run(context.Background())).To(Succeed())
+			})
+		})
+
+		Context("when the VM has not been created", func() {
+			It("should return an error", func() {
+				Expect(dnsCmd.Parse([]string{})).To(Succeed())
+				mockVBox.EXPECT().GetVMName().Return("", nil)
+
+				Expect(dnsCmd.Run(context.Background())).To(MatchError("PCF Dev VM has not been created"))
+			})
+		})
+
+		Context("when an old VM exists", func() {
+			It("should return an error", func() {
+				Expect(dnsCmd.Parse([]string{})).To(Succeed())
+				mockVBox.EXPECT().GetVMName().Return("some-old-vm-name", nil)
+
+				Expect(dnsCmd.Run(context.Background())).To(MatchError(&cmd.OldVMError{}))
+			})
+		})
+
+		Context("when there is an error getting the VM config", func() {
+			It("should return the error", func() {
+				Expect(dnsCmd.Parse([]string{})).To(Succeed())
+				gomock.InOrder(
+					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+					mockVBox.EXPECT().VMConfig("some-default-vm-name").Return(nil, errors.New("some-error")),
+				)
+
+				Expect(dnsCmd.Run(context.Background())).To(MatchError("some-error"))
+			})
+		})
+
+		Context("when the server fails", func() {
+			It("should return the error", func() {
+				Expect(dnsCmd.Parse([]string{})).To(Succeed())
+				gomock.InOrder(
+					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+					mockVBox.EXPECT().VMConfig("some-default-vm-name").Return(&config.VMConfig{IP: "192.168.11.11", Domain: "local.pcfdev.io"}, nil),
+					mockUI.EXPECT().Say("Resolving *.%s to %s", "local.pcfdev.io", "192.168.11.11"),
+					mockUI.EXPECT().Say("Listening for DNS queries on %s. Press Ctrl-C to stop.", "127.0.0.1:5354"),
+					mockDNSServer.EXPECT().ListenAndServe(gomock.Any(), "127.0.0.1:5354", "192.168.11.11", []string{"local.pcfdev.io"}).Return(errors.New("some-error")),
+				)
+
+				Expect(dnsCmd.Run(context.Background())).To(MatchError("some-error"))
+			})
+		})
+	})
+})
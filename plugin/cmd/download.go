@@ -1,17 +1,27 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+
 	"github.com/cloudfoundry/cli/cf/flags"
 	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/helpers"
 )
 
 const DOWNLOAD_ARGS = 0
 
 //go:generate mockgen -package mocks -destination mocks/client.go github.com/pivotal-cf/pcfdev-cli/plugin/cmd Client
 type Client interface {
-	AcceptEULA() error
-	IsEULAAccepted() (bool, error)
-	GetEULA() (eula string, err error)
+	AcceptEULA(ctx context.Context) error
+	IsEULAAccepted(ctx context.Context) (bool, error)
+	GetEULA(ctx context.Context) (eula string, err error)
+}
+
+//go:generate mockgen -package mocks -destination mocks/manifest_client.go github.com/pivotal-cf/pcfdev-cli/plugin/cmd ManifestClient
+type ManifestClient interface {
+	DownloadManifest(ctx context.Context) (manifest []byte, signature []byte, err error)
 }
 
 //go:generate mockgen -package mocks -destination mocks/eula_ui.go github.com/pivotal-cf/pcfdev-cli/plugin/cmd EULAUI
@@ -23,7 +33,7 @@ type EULAUI interface {
 
 type Downloader interface {
 	IsOVACurrent() (bool, error)
-	Download() error
+	Download(ctx context.Context) error
 }
 
 type DownloadCmd struct {
@@ -32,20 +42,23 @@ type DownloadCmd struct {
 	EULAUI            EULAUI
 	Client            Client
 	DownloaderFactory DownloaderFactory
+	ManifestClient    ManifestClient
+	OVAVerifier       OVAVerifier
 	FS                FS
 	Config            *config.Config
+	IgnoreOldVM       bool
 }
 
 func (d *DownloadCmd) Parse(args []string) error {
 	return parse(flags.New(), args, DOWNLOAD_ARGS)
 }
 
-func (d *DownloadCmd) Run() error {
+func (d *DownloadCmd) Run(ctx context.Context) error {
 	existingVMName, err := d.VBox.GetVMName()
 	if err != nil {
 		return err
 	}
-	if existingVMName != "" && existingVMName != d.Config.DefaultVMName {
+	if existingVMName != "" && existingVMName != d.Config.DefaultVMName && !d.IgnoreOldVM {
 		return &OldVMError{}
 	}
 
@@ -63,32 +76,60 @@ func (d *DownloadCmd) Run() error {
 		return nil
 	}
 
-	accepted, err := d.Client.IsEULAAccepted()
+	accepted, err := d.Client.IsEULAAccepted(ctx)
 	if err != nil {
 		return err
 	}
 
 	if !accepted {
-		if err := d.confirmEULA(); err != nil {
+		if err := d.confirmEULA(ctx); err != nil {
 			return err
 		}
-		if err := d.Client.AcceptEULA(); err != nil {
+		if err := d.Client.AcceptEULA(ctx); err != nil {
 			return err
 		}
 	}
 
 	d.UI.Say("Downloading VM...")
 
-	if err := downloader.Download(); err != nil {
+	if err := downloader.Download(ctx); err != nil {
 		return err
 	}
 
 	d.UI.Say("\nVM downloaded.")
+	return d.verifyManifest(ctx)
+}
+
+// verifyManifest checks the downloaded OVA against the signed manifest that
+// the OVA source serves next to it, if there is one and the plugin was built
+// with a key to verify it. An OVA that does not match is removed.
+func (d *DownloadCmd) verifyManifest(ctx context.Context) error {
+	if d.ManifestClient == nil || d.Config.ManifestPublicKey == "" {
+		return nil
+	}
+
+	manifest, signature, err := d.ManifestClient.DownloadManifest(ctx)
+	if err != nil || manifest == nil {
+		return err
+	}
+	if err := d.FS.Write(d.Config.OVAPath+".manifest", bytes.NewReader(manifest), false); err != nil {
+		return err
+	}
+	if err := d.FS.Write(d.Config.OVAPath+".manifest.sig", bytes.NewReader(signature), false); err != nil {
+		return err
+	}
+
+	ovaManifest, err := d.OVAVerifier.Verify(ctx, d.Config.OVAPath, d.Config.Version.OVABuildVersion)
+	if err != nil {
+		helpers.IgnoreErrorFrom(d.FS.Remove(d.Config.OVAPath))
+		return err
+	}
+	d.UI.Say(fmt.Sprintf("Verified the signed manifest of OVA version %s.", ovaManifest.Version))
 	return nil
 }
 
-func (d *DownloadCmd) confirmEULA() error {
-	eula, err := d.Client.GetEULA()
+func (d *DownloadCmd) confirmEULA(ctx context.Context) error {
+	eula, err := d.Client.GetEULA(ctx)
 	if err != nil {
 		return err
 	}
@@ -1,12 +1,15 @@
 package cmd_test
 
 import (
+	"bytes"
+	"context"
 	"errors"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/manifest"
 	"github.com/pivotal-cf/pcfdev-cli/plugin/cmd"
 	"github.com/pivotal-cf/pcfdev-cli/plugin/cmd/mocks"
 )
@@ -21,6 +24,8 @@ var _ = Describe("DownloadCmd", func() {
 		mockDownloader        *mocks.MockDownloader
 		mockDownloaderFactory *mocks.MockDownloaderFactory
 		mockClient            *mocks.MockClient
+		mockManifestClient    *mocks.MockManifestClient
+		mockOVAVerifier       *mocks.MockOVAVerifier
 		downloadCmd           *cmd.DownloadCmd
 	)
 
@@ -33,6 +38,8 @@ var _ = Describe("DownloadCmd", func() {
 		mockDownloader = mocks.NewMockDownloader(mockCtrl)
 		mockDownloaderFactory = mocks.NewMockDownloaderFactory(mockCtrl)
 		mockFS = mocks.NewMockFS(mockCtrl)
+		mockManifestClient = mocks.NewMockManifestClient(mockCtrl)
+		mockOVAVerifier = mocks.NewMockOVAVerifier(mockCtrl)
 		downloadCmd = &cmd.DownloadCmd{
 			UI:                mockUI,
 			EULAUI:            mockEULAUI,
@@ -80,7 +87,7 @@ var _ = Describe("DownloadCmd", func() {
 					mockUI.EXPECT().Say("Using existing image."),
 				)
 
-				downloadCmd.Run()
+				downloadCmd.Run(context.Background())
 			})
 		})
 
@@ -88,7 +95,21 @@ var _ = Describe("DownloadCmd", func() {
 			It("should tell the user to destroy downloadCmd", func() {
 				mockVBox.EXPECT().GetVMName().Return("some-old-downloadCmd-ova", nil)
 
-				Expect(downloadCmd.Run()).To(MatchError("old version of PCF Dev already running, please run `cf dev destroy` to continue"))
+				Expect(downloadCmd.Run(context.Background())).To(MatchError("old version of PCF Dev already running, please run `cf dev upgrade` or `cf dev destroy` to continue"))
+			})
+		})
+
+		Context("when there is an old vm present and old VMs are ignored", func() {
+			It("should download the OVA", func() {
+				downloadCmd.IgnoreOldVM = true
+				gomock.InOrder(
+					mockVBox.EXPECT().GetVMName().Return("some-old-vm-name", nil),
+					mockDownloaderFactory.EXPECT().Create().Return(mockDownloader, nil),
+					mockDownloader.EXPECT().IsOVACurrent().Return(true, nil),
+					mockUI.EXPECT().Say("Using existing image."),
+				)
+
+				Expect(downloadCmd.Run(context.Background())).To(Succeed())
 			})
 		})
 
@@ -96,7 +117,7 @@ var _ = Describe("DownloadCmd", func() {
 			It("should return the error", func() {
 				mockVBox.EXPECT().GetVMName().Return("", errors.New("some-error"))
 
-				Expect(downloadCmd.Run()).To(MatchError("some-error"))
+				Expect(downloadCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -107,7 +128,7 @@ var _ = Describe("DownloadCmd", func() {
 					mockDownloaderFactory.EXPECT().Create().Return(nil, errors.New("some-error")),
 				)
 
-				Expect(downloadCmd.Run()).To(MatchError("some-error"))
+				Expect(downloadCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -119,7 +140,7 @@ var _ = Describe("DownloadCmd", func() {
 					mockDownloader.EXPECT().IsOVACurrent().Return(false, errors.New("some-error")),
 				)
 
-				Expect(downloadCmd.Run()).To(MatchError("some-error"))
+				Expect(downloadCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -129,13 +150,115 @@ var _ = Describe("DownloadCmd", func() {
 					mockVBox.EXPECT().GetVMName().Return("", nil),
 					mockDownloaderFactory.EXPECT().Create().Return(mockDownloader, nil),
 					mockDownloader.EXPECT().IsOVACurrent().Return(false, nil),
-					mockClient.EXPECT().IsEULAAccepted().Return(true, nil),
+					mockClient.EXPECT().IsEULAAccepted(gomock.Any()).Return(true, nil),
 					mockUI.EXPECT().Say("Downloading VM..."),
-					mockDownloader.EXPECT().Download(),
+					mockDownloader.EXPECT().Download(gomock.Any()),
 					mockUI.EXPECT().Say("\nVM downloaded."),
 				)
 
-				downloadCmd.Run()
+				downloadCmd.Run(context.Background())
+			})
+
+			Context("when the OVA source serves a signed manifest", func() {
+				BeforeEach(func() {
+					downloadCmd.ManifestClient = mockManifestClient
+					downloadCmd.OVAVerifier = mockOVAVerifier
+					downloadCmd.FS = mockFS
+					downloadCmd.Config.OVAPath = "some-ova-path"
+					downloadCmd.Config.ManifestPublicKey = "some-public-key"
+					downloadCmd.Config.Version = &config.Version{OVABuildVersion: "some-ova-version"}
+				})
+
+				It("should verify the OVA against the manifest", func() {
+					gomock.InOrder(
+						mockVBox.EXPECT().GetVMName().Return("", nil),
+						mockDownloaderFactory.EXPECT().Create().Return(mockDownloader, nil),
+						mockDownloader.EXPECT().IsOVACurrent().Return(false, nil),
+						mockClient.EXPECT().IsEULAAccepted(gomock.Any()).Return(true, nil),
+						mockUI.EXPECT().Say("Downloading VM..."),
+						mockDownloader.EXPECT().Download(gomock.Any()),
+						mockUI.EXPECT().Say("\nVM downloaded."),
+						mockManifestClient.EXPECT().DownloadManifest(gomock.Any()).Return([]byte("some-manifest"), []byte("some-signature"), nil),
+						mockFS.EXPECT().Write("some-ova-path.manifest", bytes.NewReader([]byte("some-manifest")), false),
+						mockFS.EXPECT().Write("some-ova-path.manifest.sig", bytes.NewReader([]byte("some-signature")), false),
+						mockOVAVerifier.EXPECT().Verify(gomock.Any(), "some-ova-path", "some-ova-version").Return(&manifest.Manifest{Version: "some-ova-version"}, nil),
+						mockUI.EXPECT().Say("Verified the signed manifest of OVA version some-ova-version."),
+					)
+
+					Expect(downloadCmd.Run(context.Background())).To(Succeed())
+				})
+
+				Context("when the OVA does not match the manifest", func() {
+					It("should remove the OVA and return an error", func() {
+						gomock.InOrder(
+							mockVBox.EXPECT().GetVMName().Return("", nil),
+							mockDownloaderFactory.EXPECT().Create().Return(mockDownloader, nil),
+							mockDownloader.EXPECT().IsOVACurrent().Return(false, nil),
+							mockClient.EXPECT().IsEULAAccepted(gomock.Any()).Return(true, nil),
+							mockUI.EXPECT().Say("Downloading VM..."),
+							mockDownloader.EXPECT().Download(gomock.Any()),
+							mockUI.EXPECT().Say("\nVM downloaded."),
+							mockManifestClient.EXPECT().DownloadManifest(gomock.Any()).Return([]byte("some-manifest"), []byte("some-signature"), nil),
+							mockFS.EXPECT().Write("some-ova-path.manifest", gomock.Any(), false),
+							mockFS.EXPECT().Write("some-ova-path.manifest.sig", gomock.Any(), false),
+							mockOVAVerifier.EXPECT().Verify(gomock.Any(), "some-ova-path", "some-ova-version").Return(nil, errors.New("some-error")),
+							mockFS.EXPECT().Remove("some-ova-path"),
+						)
+
+						Expect(downloadCmd.Run(context.Background())).To(MatchError("some-error"))
+					})
+				})
+
+				Context("when the OVA source has no manifest", func() {
+					It("should not verify the OVA", func() {
+						gomock.InOrder(
+							mockVBox.EXPECT().GetVMName().Return("", nil),
+							mockDownloaderFactory.EXPECT().Create().Return(mockDownloader, nil),
+							mockDownloader.EXPECT().IsOVACurrent().Return(false, nil),
+							mockClient.EXPECT().IsEULAAccepted(gomock.Any()).Return(true, nil),
+							mockUI.EXPECT().Say("Downloading VM..."),
+							mockDownloader.EXPECT().Download(gomock.Any()),
+							mockUI.EXPECT().Say("\nVM downloaded."),
+							mockManifestClient.EXPECT().DownloadManifest(gomock.Any()).Return(nil, nil, nil),
+						)
+
+						Expect(downloadCmd.Run(context.Background())).To(Succeed())
+					})
+				})
+
+				Context("when the plugin was not built with a key to verify manifests", func() {
+					It("should not download the manifest", func() {
+						downloadCmd.Config.ManifestPublicKey = ""
+						gomock.InOrder(
+							mockVBox.EXPECT().GetVMName().Return("", nil),
+							mockDownloaderFactory.EXPECT().Create().Return(mockDownloader, nil),
+							mockDownloader.EXPECT().IsOVACurrent().Return(false, nil),
+							mockClient.EXPECT().IsEULAAccepted(gomock.Any()).Return(true, nil),
+							mockUI.EXPECT().Say("Downloading VM..."),
+							mockDownloader.EXPECT().Download(gomock.Any()),
+							mockUI.EXPECT().Say("\nVM downloaded."),
+						)
+
+						Expect(downloadCmd.Run(context.Background())).To(Succeed())
+					})
+				})
+
+				Context("when downloading the manifest fails", func() {
+					It("should return an error", func() {
+						gomock.InOrder(
+							mockVBox.EXPECT().GetVMName().Return("", nil),
+							mockDownloaderFactory.EXPECT().Create().Return(mockDownloader, nil),
+							mockDownloader.EXPECT().IsOVACurrent().Return(false, nil),
+							mockClient.EXPECT().IsEULAAccepted(gomock.Any()).Return(true, nil),
+							mockUI.EXPECT().Say("Downloading VM..."),
+							mockDownloader.EXPECT().Download(gomock.Any()),
+							mockUI.EXPECT().Say("\nVM downloaded."),
+							mockManifestClient.EXPECT().DownloadManifest(gomock.Any()).Return(nil, nil, errors.New("some-error")),
+						)
+
+						Expect(downloadCmd.Run(context.Background())).To(MatchError("some-error"))
+					})
+				})
 			})
 
 			Context("when EULA check fails", func() {
@@ -144,10 +267,10 @@ var _ = Describe("DownloadCmd", func() {
 						mockVBox.EXPECT().GetVMName().Return("", nil),
 						mockDownloaderFactory.EXPECT().Create().Return(mockDownloader, nil),
 						mockDownloader.EXPECT().IsOVACurrent().Return(false, nil),
-						mockClient.EXPECT().IsEULAAccepted().Return(false, errors.New("some-error")),
+						mockClient.EXPECT().IsEULAAccepted(gomock.Any()).Return(false, errors.New("some-error")),
 					)
 
-					Expect(downloadCmd.Run()).To(MatchError("some-error"))
+					Expect(downloadCmd.Run(context.Background())).To(MatchError("some-error"))
 
 				})
 			})
@@ -158,12 +281,12 @@ var _ = Describe("DownloadCmd", func() {
 						mockVBox.EXPECT().GetVMName().Return("", nil),
 						mockDownloaderFactory.EXPECT().Create().Return(mockDownloader, nil),
 						mockDownloader.EXPECT().IsOVACurrent().Return(false, nil),
-						mockClient.EXPECT().IsEULAAccepted().Return(true, nil),
+						mockClient.EXPECT().IsEULAAccepted(gomock.Any()).Return(true, nil),
 						mockUI.EXPECT().Say("Downloading VM..."),
-						mockDownloader.EXPECT().Download().Return(errors.New("some-error")),
+						mockDownloader.EXPECT().Download(gomock.Any()).Return(errors.New("some-error")),
 					)
 
-					Expect(downloadCmd.Run()).To(MatchError("some-error"))
+					Expect(downloadCmd.Run(context.Background())).To(MatchError("some-error"))
 				})
 			})
 
@@ -173,18 +296,18 @@ var _ = Describe("DownloadCmd", func() {
 						mockVBox.EXPECT().GetVMName().Return("", nil),
 						mockDownloaderFactory.EXPECT().Create().Return(mockDownloader, nil),
 						mockDownloader.EXPECT().IsOVACurrent().Return(false, nil),
-						mockClient.EXPECT().IsEULAAccepted().Return(false, nil),
-						mockClient.EXPECT().GetEULA().Return("some-eula", nil),
+						mockClient.EXPECT().IsEULAAccepted(gomock.Any()).Return(false, nil),
+						mockClient.EXPECT().GetEULA(gomock.Any()).Return("some-eula", nil),
 						mockEULAUI.EXPECT().Init(),
 						mockEULAUI.EXPECT().ConfirmText("some-eula").Return(true),
 						mockEULAUI.EXPECT().Close(),
-						mockClient.EXPECT().AcceptEULA(),
+						mockClient.EXPECT().AcceptEULA(gomock.Any()),
 						mockUI.EXPECT().Say("Downloading VM..."),
-						mockDownloader.EXPECT().Download(),
+						mockDownloader.EXPECT().Download(gomock.Any()),
 						mockUI.EXPECT().Say("\nVM downloaded."),
 					)
 
-					downloadCmd.Run()
+					downloadCmd.Run(context.Background())
 				})
 			})
 
@@ -194,14 +317,14 @@ var _ = Describe("DownloadCmd", func() {
 						mockVBox.EXPECT().GetVMName().Return("", nil),
 						mockDownloaderFactory.EXPECT().Create().Return(mockDownloader, nil),
 						mockDownloader.EXPECT().IsOVACurrent().Return(false, nil),
-						mockClient.EXPECT().IsEULAAccepted().Return(false, nil),
-						mockClient.EXPECT().GetEULA().Return("some-eula", nil),
+						mockClient.EXPECT().IsEULAAccepted(gomock.Any()).Return(false, nil),
+						mockClient.EXPECT().GetEULA(gomock.Any()).Return("some-eula", nil),
 						mockEULAUI.EXPECT().Init(),
 						mockEULAUI.EXPECT().ConfirmText("some-eula").Return(false),
 						mockEULAUI.EXPECT().Close(),
 					)
 
-					Expect(downloadCmd.Run()).To(MatchError("you must accept the end user license agreement to use PCF Dev"))
+					Expect(downloadCmd.Run(context.Background())).To(MatchError("you must accept the end user license agreement to use PCF Dev"))
 				})
 			})
 
@@ -211,15 +334,15 @@ var _ = Describe("DownloadCmd", func() {
 						mockVBox.EXPECT().GetVMName().Return("", nil),
 						mockDownloaderFactory.EXPECT().Create().Return(mockDownloader, nil),
 						mockDownloader.EXPECT().IsOVACurrent().Return(false, nil),
-						mockClient.EXPECT().IsEULAAccepted().Return(false, nil),
-						mockClient.EXPECT().GetEULA().Return("some-eula", nil),
+						mockClient.EXPECT().IsEULAAccepted(gomock.Any()).Return(false, nil),
+						mockClient.EXPECT().GetEULA(gomock.Any()).Return("some-eula", nil),
 						mockEULAUI.EXPECT().Init(),
 						mockEULAUI.EXPECT().ConfirmText("some-eula").Return(true),
 						mockEULAUI.EXPECT().Close(),
-						mockClient.EXPECT().AcceptEULA().Return(errors.New("some-error")),
+						mockClient.EXPECT().AcceptEULA(gomock.Any()).Return(errors.New("some-error")),
 					)
 
-					Expect(downloadCmd.Run()).To(MatchError("some-error"))
+					Expect(downloadCmd.Run(context.Background())).To(MatchError("some-error"))
 				})
 			})
 
@@ -229,14 +352,14 @@ var _ = Describe("DownloadCmd", func() {
 						mockVBox.EXPECT().GetVMName().Return("", nil),
 						mockDownloaderFactory.EXPECT().Create().Return(mockDownloader, nil),
 						mockDownloader.EXPECT().IsOVACurrent().Return(false, nil),
-						mockClient.EXPECT().IsEULAAccepted().Return(false, nil),
-						mockClient.EXPECT().GetEULA().Return("some-eula", nil),
+						mockClient.EXPECT().IsEULAAccepted(gomock.Any()).Return(false, nil),
+						mockClient.EXPECT().GetEULA(gomock.Any()).Return("some-eula", nil),
 						mockEULAUI.EXPECT().Init(),
 						mockEULAUI.EXPECT().ConfirmText("some-eula").Return(false),
 						mockEULAUI.EXPECT().Close().Return(errors.New("some-error")),
 					)
 
-					Expect(downloadCmd.Run()).To(MatchError("some-error"))
+					Expect(downloadCmd.Run(context.Background())).To(MatchError("some-error"))
 				})
 			})
 
@@ -246,14 +369,14 @@ var _ = Describe("DownloadCmd", func() {
 						mockVBox.EXPECT().GetVMName().Return("", nil),
 						mockDownloaderFactory.EXPECT().Create().Return(mockDownloader, nil),
 						mockDownloader.EXPECT().IsOVACurrent().Return(false, nil),
-						mockClient.EXPECT().IsEULAAccepted().Return(false, nil),
-						mockClient.EXPECT().GetEULA().Return("some-eula", nil),
+						mockClient.EXPECT().IsEULAAccepted(gomock.Any()).Return(false, nil),
+						mockClient.EXPECT().GetEULA(gomock.Any()).Return("some-eula", nil),
 						mockEULAUI.EXPECT().Init(),
 						mockEULAUI.EXPECT().ConfirmText("some-eula").Return(true),
 						mockEULAUI.EXPECT().Close().Return(errors.New("some-error")),
 					)
 
-					Expect(downloadCmd.Run()).To(MatchError("some-error"))
+					Expect(downloadCmd.Run(context.Background())).To(MatchError("some-error"))
 				})
 			})
 
@@ -263,11 +386,11 @@ var _ = Describe("DownloadCmd", func() {
 						mockVBox.EXPECT().GetVMName().Return("", nil),
 						mockDownloaderFactory.EXPECT().Create().Return(mockDownloader, nil),
 						mockDownloader.EXPECT().IsOVACurrent().Return(false, nil),
-						mockClient.EXPECT().IsEULAAccepted().Return(false, nil),
-						mockClient.EXPECT().GetEULA().Return("", errors.New("some-error")),
+						mockClient.EXPECT().IsEULAAccepted(gomock.Any()).Return(false, nil),
+						mockClient.EXPECT().GetEULA(gomock.Any()).Return("", errors.New("some-error")),
 					)
 
-					Expect(downloadCmd.Run()).To(MatchError("some-error"))
+					Expect(downloadCmd.Run(context.Background())).To(MatchError("some-error"))
 				})
 			})
 		})
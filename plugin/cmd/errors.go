@@ -19,11 +19,52 @@ func (e *DestroyVMError) Error() string {
 type OldVMError struct{}
 
 func (e *OldVMError) Error() string {
-	return "old version of PCF Dev already running, please run `cf dev destroy` to continue"
+	return "old version of PCF Dev already running, please run `cf dev upgrade` or `cf dev destroy` to continue"
 }
 
-type OldDriverError struct{}
+type OldDriverError struct {
+	Provider string
+}
 
 func (e *OldDriverError) Error() string {
+	if e.Provider == "libvirt" {
+		return "please install libvirt version 5 or greater"
+	}
 	return "please install Virtualbox version 5 or greater"
 }
+
+type UpgradeVMError struct {
+	Err error
+}
+
+func (e *UpgradeVMError) Error() string {
+	return fmt.Sprintf("failed to upgrade VM: %s", e.Err)
+}
+
+type NotCreatedError struct{}
+
+func (e *NotCreatedError) Error() string {
+	return "PCF Dev VM has not been created"
+}
+
+type ProxyUpdateError struct {
+	Err error
+}
+
+func (e *ProxyUpdateError) Error() string {
+	return fmt.Sprintf("failed to update the proxy settings of the VM: %s", e.Err)
+}
+
+type NotRunningError struct{}
+
+func (e *NotRunningError) Error() string {
+	return "PCF Dev VM is not running"
+}
+
+type SSHKeyRotateError struct {
+	Err error
+}
+
+func (e *SSHKeyRotateError) Error() string {
+	return fmt.Sprintf("failed to rotate the SSH key: %s", e.Err)
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/flags"
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/helpers"
+	"github.com/pivotal-cf/pcfdev-cli/hosts"
+)
+
+const HOSTS_ARGS = 1
+
+//go:generate mockgen -package mocks -destination mocks/hosts_file.go github.com/pivotal-cf/pcfdev-cli/plugin/cmd HostsFile
+type HostsFile interface {
+	Read() (contents []byte, err error)
+	Write(contents []byte) error
+}
+
+//go:generate mockgen -package mocks -destination mocks/route_lister.go github.com/pivotal-cf/pcfdev-cli/plugin/cmd RouteLister
+type RouteLister interface {
+	Hostnames(ip string, domain string) (hostnames []string, err error)
+}
+
+// HostsCmd writes entries for the PCF Dev system components and app routes to
+// the hosts file, for workstations that cannot resolve the PCF Dev domain.
+type HostsCmd struct {
+	VBox          VBox
+	UI            UI
+	Config        *config.Config
+	HostsFile     HostsFile
+	RouteLister   RouteLister
+	WatchInterval time.Duration
+	action        string
+	watch         bool
+}
+
+func (h *HostsCmd) Parse(args []string) error {
+	flagContext := flags.New()
+	flagContext.NewBoolFlag("watch", "", "<watch>")
+	if err := parse(flagContext, args, HOSTS_ARGS); err != nil {
+		return err
+	}
+
+	h.action = flagContext.Args()[0]
+	h.watch = flagContext.Bool("watch")
+	switch {
+	case h.action != "sync" && h.action != "remove":
+		return errors.New("unknown hosts action")
+	case h.action == "remove" && h.watch:
+		return errors.New("--watch can only be used with sync")
+	}
+	return nil
+}
+
+func (h *HostsCmd) Run(ctx context.Context) error {
+	if h.action == "remove" {
+		return h.remove()
+	}
+
+	vmConfig, err := getVMConfig(h.VBox, h.Config)
+	if err != nil {
+		return err
+	}
+
+	if h.watch {
+		h.UI.Say("Checking for new routes every %s. Press Ctrl-C to stop.", h.WatchInterval)
+	}
+
+	synced := false
+	for {
+		routes, err := h.RouteLister.Hostnames(vmConfig.IP, vmConfig.Domain)
+		if err != nil {
+			h.UI.Say("Warning: could not read the app routes from the CF API: %s", err)
+		}
+
+		if err == nil || !synced {
+			hostnames := uniqueHostnames(append(hosts.SystemHostnames(vmConfig.Domain), routes...))
+			if err := h.sync(vmConfig.IP, hostnames, synced); err != nil {
+				return err
+			}
+			synced = true
+		}
+
+		if !h.watch {
+			return nil
+		}
+		if err := helpers.Sleep(ctx, h.WatchInterval); err != nil {
+			return nil
+		}
+	}
+}
+
+func (h *HostsCmd) sync(ip string, hostnames []string, quiet bool) error {
+	contents, err := h.HostsFile.Read()
+	if err != nil {
+		return err
+	}
+
+	synced := hosts.Render(contents, ip, hostnames)
+	if bytes.Equal(synced, contents) {
+		if !quiet {
+			h.UI.Say("The PCF Dev entries in the hosts file are up to date.")
+		}
+		return nil
+	}
+
+	if err := h.HostsFile.Write(synced); err != nil {
+		return err
+	}
+	h.UI.Say("Wrote %d PCF Dev entries to the hosts file.", len(hostnames))
+	return nil
+}
+
+func (h *HostsCmd) remove() error {
+	contents, err := h.HostsFile.Read()
+	if err != nil {
+		return err
+	}
+
+	removed := hosts.Remove(contents)
+	if bytes.Equal(removed, contents) {
+		h.UI.Say("There are no PCF Dev entries in the hosts file.")
+		return nil
+	}
+
+	if err := h.HostsFile.Write(removed); err != nil {
+		return err
+	}
+	h.UI.Say("Removed the PCF Dev entries from the hosts file.")
+	return nil
+}
+
+func uniqueHostnames(hostnames []string) []string {
+	seen := map[string]bool{}
+	unique := make([]string, 0, len(hostnames))
+	for _, hostname := range hostnames {
+		hostname = strings.ToLower(hostname)
+		if !seen[hostname] {
+			seen[hostname] = true
+			unique = append(unique, hostname)
+		}
+	}
+	return unique
+}
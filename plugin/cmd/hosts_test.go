@@ -0,0 +1,269 @@
+package cmd_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/plugin/cmd"
+	"github.com/pivotal-cf/pcfdev-cli/plugin/cmd/mocks"
+)
+
+var _ = Describe("HostsCmd", func() {
+	var (
+		hostsCmd        *cmd.HostsCmd
+		mockCtrl        *gomock.Controller
+		mockVBox        *mocks.MockVBox
+		mockUI          *mocks.MockUI
+		mockHostsFile   *mocks.MockHostsFile
+		mockRouteLister *mocks.MockRouteLister
+		vmConfig        *config.VMConfig
+	)
+
+	const (
+		initialHosts = "127.0.0.1 localhost\n"
+		syncedHosts  = "127.0.0.1 localhost\n" +
+			"# BEGIN PCF Dev entries (managed by cf dev hosts)\n" +
+			"192.168.11.11 api.local.pcfdev.io\n" +
+			"192.168.11.11 login.local.pcfdev.io\n" +
+			"192.168.11.11 uaa.local.pcfdev.io\n" +
+			"192.168.11.11 apps.local.pcfdev.io\n" +
+			"192.168.11.11 some-app.local.pcfdev.io\n" +
+			"# END PCF Dev entries\n"
+		systemHosts = "127.0.0.1 localhost\n" +
+			"# BEGIN PCF Dev entries (managed by cf dev hosts)\n" +
+			"192.168.11.11 api.local.pcfdev.io\n" +
+			"192.168.11.11 login.local.pcfdev.io\n" +
+			"192.168.11.11 uaa.local.pcfdev.io\n" +
+			"192.168.11.11 apps.local.pcfdev.io\n" +
+			"# END PCF Dev entries\n"
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockVBox = mocks.NewMockVBox(mockCtrl)
+		mockUI = mocks.NewMockUI(mockCtrl)
+		mockHostsFile = mocks.NewMockHostsFile(mockCtrl)
+		mockRouteLister = mocks.NewMockRouteLister(mockCtrl)
+		hostsCmd = &cmd.HostsCmd{
+			VBox:        mockVBox,
+			UI:          mockUI,
+			HostsFile:   mockHostsFile,
+			RouteLister: mockRouteLister,
+			Config: &config.Config{
+				DefaultVMName: "some-default-vm-name",
+			},
+		}
+		vmConfig = &config.VMConfig{IP: "192.168.11.11", Domain: "local.pcfdev.io"}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("Parse", func() {
+		It("should accept sync and remove", func() {
+			Expect(hostsCmd.Parse([]string{"sync"})).To(Succeed())
+			Expect(hostsCmd.Parse([]string{"sync", "--watch"})).To(Succeed())
+			Expect(hostsCmd.Parse([]string{"remove"})).To(Succeed())
+		})
+
+		Context("when the wrong number of arguments are passed", func() {
+			It("should fail", func() {
+				Expect(hostsCmd.Parse([]string{})).NotTo(Succeed())
+				Expect(hostsCmd.Parse([]string{"sync", "some-bad-arg"})).NotTo(Succeed())
+			})
+		})
+
+		Context("when an unknown action is passed", func() {
+			It("should fail", func() {
+				Expect(hostsCmd.Parse([]string{"some-bad-action"})).NotTo(Succeed())
+			})
+		})
+
+		Context("when --watch is passed to remove", func() {
+			It("should fail", func() {
+				Expect(hostsCmd.Parse([]string{"remove", "--watch"})).NotTo(Succeed())
+			})
+		})
+
+		Context("when an unknown flag is passed", func() {
+			It("should fail", func() {
+				Expect(hostsCmd.Parse([]string{"sync", "--some-bad-flag"})).NotTo(Succeed())
+			})
+		})
+	})
+
+	Describe("Run", func() {
+		Context("when syncing", func() {
+			BeforeEach(func() {
+				Expect(hostsCmd.Parse([]string{"sync"})).To(Succeed())
+			})
+
+			It("should write entries for the system components and the app routes", func() {
+				gomock.InOrder(
+					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+					mockVBox.EXPECT().VMConfig("some-default-vm-name").Return(vmConfig, nil),
+					mockRouteLister.EXPECT().Hostnames("192.168.11.11", "local.pcfdev.io").Return([]string{"some-app.local.pcfdev.io", "API.local.pcfdev.io"}, nil),
+					mockHostsFile.EXPECT().Read().Return([]byte(initialHosts), nil),
+					mockHostsFile.EXPECT().Write([]byte(syncedHosts)),
+					mockUI.EXPECT().Say("Wrote %d PCF Dev entries to the hosts file.", 5),
+				)
+
+				Expect(hostsCmd.Run(context.Background())).To(Succeed())
+			})
+
+			Context("when the entries are up to date", func() {
+				It("should not write the hosts file", func() {
+					gomock.InOrder(
+						mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+						mockVBox.EXPECT().VMConfig("some-default-vm-name").Return(vmConfig, nil),
+						mockRouteLister.EXPECT().Hostnames("192.168.11.11", "local.pcfdev.io").Return([]string{"some-app.local.pcfdev.io"}, nil),
+						mockHostsFile.EXPECT().Read().Return([]byte(syncedHosts), nil),
+						mockUI.EXPECT().Say("The PCF Dev entries in the hosts file are up to date."),
+					)
+
+					Expect(hostsCmd.Run(context.Background())).To(Succeed())
+				})
+			})
+
+			Context("when the routes cannot be read", func() {
+				It("should warn and write entries for the system components", func() {
+					gomock.InOrder(
+						mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+						mockVBox.EXPECT().VMConfig("some-default-vm-name").Return(vmConfig, nil),
+						mockRouteLister.EXPECT().Hostnames("192.168.11.11", "local.pcfdev.io").Return(nil, errors.New("some-error")),
+						mockUI.EXPECT().Say("Warning: could not read the app routes from the CF API: %s", errors.New("some-error")),
+						mockHostsFile.EXPECT().Read().Return([]byte(initialHosts), nil),
+						mockHostsFile.EXPECT().Write([]byte(systemHosts)),
+						mockUI.EXPECT().Say("Wrote %d PCF Dev entries to the hosts file.", 4),
+					)
+
+					Expect(hostsCmd.Run(context.Background())).To(Succeed())
+				})
+			})
+
+			Context("when the VM has not been created", func() {
+				It("should return an error", func() {
+					mockVBox.EXPECT().GetVMName().Return("", nil)
+
+					Expect(hostsCmd.Run(context.Background())).To(MatchError("PCF Dev VM has not been created"))
+				})
+			})
+
+			Context("when there is an error reading the hosts file", func() {
+				It("should return the error", func() {
+					gomock.InOrder(
+						mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+						mockVBox.EXPECT().VMConfig("some-default-vm-name").Return(vmConfig, nil),
+						mockRouteLister.EXPECT().Hostnames("192.168.11.11", "local.pcfdev.io").Return([]string{}, nil),
+						mockHostsFile.EXPECT().Read().Return(nil, errors.New("some-error")),
+					)
+
+					Expect(hostsCmd.Run(context.Background())).To(MatchError("some-error"))
+				})
+			})
+
+			Context("when there is an error writing the hosts file", func() {
+				It("should return the error", func() {
+					gomock.InOrder(
+						mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+						mockVBox.EXPECT().VMConfig("some-default-vm-name").Return(vmConfig, nil),
+						mockRouteLister.EXPECT().Hostnames("192.168.11.11", "local.pcfdev.io").Return([]string{}, nil),
+						mockHostsFile.EXPECT().Read().Return([]byte(initialHosts), nil),
+						mockHostsFile.EXPECT().Write([]byte(systemHosts)).Return(errors.New("some-error")),
+					)
+
+					Expect(hostsCmd.Run(context.Background())).To(MatchError("some-error"))
+				})
+			})
+		})
+
+		Context("when watching", func() {
+			BeforeEach(func() {
+				Expect(hostsCmd.Parse([]string{"sync", "--watch"})).To(Succeed())
+			})
+
+			It("should keep syncing, and keep the last entries while the routes cannot be read", func() {
+				gomock.InOrder(
+					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+					mockVBox.EXPECT().VMConfig("some-default-vm-name").Return(vmConfig, nil),
+					mockUI.EXPECT().Say("Checking for new routes every %s. Press Ctrl-C to stop.", hostsCmd.WatchInterval),
+					mockRouteLister.EXPECT().Hostnames("192.168.11.11", "local.pcfdev.io").Return([]string{}, nil),
+					mockHostsFile.EXPECT().Read().Return([]byte(initialHosts), nil),
+					mockHostsFile.EXPECT().Write([]byte(systemHosts)),
+					mockUI.EXPECT().Say("Wrote %d PCF Dev entries to the hosts file.", 4),
+					mockRouteLister.EXPECT().Hostnames("192.168.11.11", "local.pcfdev.io").Return(nil, errors.New("some-error")),
+					mockUI.EXPECT().Say("Warning: could not read the app routes from the CF API: %s", errors.New("some-error")),
+					mockRouteLister.EXPECT().Hostnames("192.168.11.11", "local.pcfdev.io").Return([]string{}, nil),
+					mockHostsFile.EXPECT().Read().Return([]byte(systemHosts), nil),
+					mockRouteLister.EXPECT().Hostnames("192.168.11.11", "local.pcfdev.io").Return([]string{"some-app.local.pcfdev.io"}, nil),
+					mockHostsFile.EXPECT().Read().Return([]byte(systemHosts), nil),
+					mockHostsFile.EXPECT().Write([]byte(syncedHosts)),
+					mockUI.EXPECT().Say("Wrote %d PCF Dev entries to the hosts file.", 5),
+					mockRouteLister.EXPECT().Hostnames("192.168.11.11", "local.pcfdev.io").Return([]string{}, nil),
+					mockHostsFile.EXPECT().Read().Return(nil, errors.New("some-read-error")),
+				)
+
+				Expect(hostsCmd.Run(context.Background())).To(MatchError("some-read-error"))
+			})
+
+			Context("when the context is done", func() {
+				It("should stop watching", func() {
+					ctx, cancel := context.WithCancel(context.Background())
+					gomock.InOrder(
+						mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+						mockVBox.EXPECT().VMConfig("some-default-vm-name").Return(vmConfig, nil),
+						mockUI.EXPECT().Say("Checking for new routes every %s. Press Ctrl-C to stop.", hostsCmd.WatchInterval),
+						mockRouteLister.EXPECT().Hostnames("192.168.11.11", "local.pcfdev.io").Return([]string{}, nil),
+						mockHostsFile.EXPECT().Read().Return([]byte(systemHosts), nil),
+						mockUI.EXPECT().Say("The PCF Dev entries in the hosts file are up to date.").Do(func(string, ...interface{}) { cancel() }),
+					)
+
+					Expect(hostsCmd.Run(ctx)).To(Succeed())
+				})
+			})
+		})
+
+		Context("when removing", func() {
+			BeforeEach(func() {
+				Expect(hostsCmd.Parse([]string{"remove"})).To(Succeed())
+			})
+
+			It("should remove the entries without needing the VM", func() {
+				gomock.InOrder(
+					mockHostsFile.EXPECT().Read().Return([]byte(syncedHosts), nil),
+					mockHostsFile.EXPECT().Write([]byte(initialHosts)),
+					mockUI.EXPECT().Say("Removed the PCF Dev entries from the hosts file."),
+				)
+
+				Expect(hostsCmd.Run(context.Background())).To(Succeed())
+			})
+
+			Context("when there are no entries", func() {
+				It("should not write the hosts file", func() {
+					gomock.InOrder(
+						mockHostsFile.EXPECT().Read().Return([]byte(initialHosts), nil),
+						mockUI.EXPECT().Say("There are no PCF Dev entries in the hosts file."),
+					)
+
+					Expect(hostsCmd.Run(context.Background())).To(Succeed())
+				})
+			})
+
+			Context("when there is an error writing the hosts file", func() {
+				It("should return the error", func() {
+					gomock.InOrder(
+						mockHostsFile.EXPECT().Read().Return([]byte(syncedHosts), nil),
+						mockHostsFile.EXPECT().Write([]byte(initialHosts)).Return(errors.New("some-error")),
+					)
+
+					Expect(hostsCmd.Run(context.Background())).To(MatchError("some-error"))
+				})
+			})
+		})
+	})
+})
@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 
 	"github.com/cloudfoundry/cli/cf/flags"
 	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/downloader"
 )
 
 const IMPORT_ARGS = 1
@@ -26,19 +28,19 @@ func (i *ImportCmd) Parse(args []string) error {
 	return nil
 }
 
-func (i *ImportCmd) Run() error {
-	md5, err := i.FS.MD5(i.OVAPath)
+func (i *ImportCmd) Run(ctx context.Context) error {
+	digest, err := downloader.Digest(i.FS, i.Config, i.OVAPath)
 	if err != nil {
 		return err
 	}
-	if md5 != i.Config.ExpectedMD5 {
+	if digest != i.Config.ExpectedDigest() {
 		return fmt.Errorf("specified OVA version does not match the expected OVA version (%s) for this version of the cf CLI plugin", i.Config.Version.OVABuildVersion)
 	}
-	downloader, err := i.DownloaderFactory.Create()
+	ovaDownloader, err := i.DownloaderFactory.Create()
 	if err != nil {
 		return err
 	}
-	ovaIsCurrent, err := downloader.IsOVACurrent()
+	ovaIsCurrent, err := ovaDownloader.IsOVACurrent()
 	if err != nil {
 		return err
 	}
@@ -1,6 +1,7 @@
 package cmd_test
 
 import (
+	"context"
 	"errors"
 	"path/filepath"
 
@@ -88,7 +89,7 @@ var _ = Describe("ImportCmd", func() {
 				mockUI.EXPECT().Say("OVA version some-ova-version imported successfully."),
 			)
 
-			Expect(importCmd.Run()).To(Succeed())
+			Expect(importCmd.Run(context.Background())).To(Succeed())
 		})
 
 		Context("when move returns an error", func() {
@@ -99,7 +100,7 @@ var _ = Describe("ImportCmd", func() {
 					mockDownloader.EXPECT().IsOVACurrent().Return(false, nil),
 					mockFS.EXPECT().Copy("some-ova-path", filepath.Join("some-ova-dir", "some-vm-name.ova")).Return(errors.New("some-error")),
 				)
-				Expect(importCmd.Run()).To(MatchError("some-error"))
+				Expect(importCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -107,7 +108,33 @@ var _ = Describe("ImportCmd", func() {
 			It("should print an error message", func() {
 				mockFS.EXPECT().MD5("some-ova-path").Return("some-bad-md5", nil)
 
-				Expect(importCmd.Run()).To(MatchError("specified OVA version does not match the expected OVA version (some-ova-version) for this version of the cf CLI plugin"))
+				Expect(importCmd.Run(context.Background())).To(MatchError("specified OVA version does not match the expected OVA version (some-ova-version) for this version of the cf CLI plugin"))
+			})
+		})
+
+		Context("when the plugin expects a SHA-256", func() {
+			BeforeEach(func() {
+				importCmd.Config.ExpectedSHA256 = "some-sha256"
+			})
+
+			It("should compare the SHA-256 of the ova", func() {
+				gomock.InOrder(
+					mockFS.EXPECT().SHA256("some-ova-path").Return("some-sha256", nil),
+					mockDownloaderFactory.EXPECT().Create().Return(mockDownloader, nil),
+					mockDownloader.EXPECT().IsOVACurrent().Return(false, nil),
+					mockFS.EXPECT().Copy("some-ova-path", filepath.Join("some-ova-dir", "some-vm-name.ova")),
+					mockUI.EXPECT().Say("OVA version some-ova-version imported successfully."),
+				)
+
+				Expect(importCmd.Run(context.Background())).To(Succeed())
+			})
+
+			Context("when the SHA-256 does not match", func() {
+				It("should print an error message", func() {
+					mockFS.EXPECT().SHA256("some-ova-path").Return("some-bad-sha256", nil)
+
+					Expect(importCmd.Run(context.Background())).To(MatchError("specified OVA version does not match the expected OVA version (some-ova-version) for this version of the cf CLI plugin"))
+				})
 			})
 		})
 
@@ -115,7 +142,7 @@ var _ = Describe("ImportCmd", func() {
 			It("should print an error message", func() {
 				mockFS.EXPECT().MD5("some-ova-path").Return("some-bad-md5", errors.New("some-error"))
 
-				Expect(importCmd.Run()).To(MatchError("some-error"))
+				Expect(importCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -125,7 +152,7 @@ var _ = Describe("ImportCmd", func() {
 					mockFS.EXPECT().MD5("some-ova-path").Return("some-md5", nil),
 					mockDownloaderFactory.EXPECT().Create().Return(nil, errors.New("some-error")),
 				)
-				Expect(importCmd.Run()).To(MatchError("some-error"))
+				Expect(importCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -139,7 +166,7 @@ var _ = Describe("ImportCmd", func() {
 					mockUI.EXPECT().Say("PCF Dev OVA is already installed."),
 				)
 
-				Expect(importCmd.Run()).To(Succeed())
+				Expect(importCmd.Run(context.Background())).To(Succeed())
 			})
 		})
 
@@ -151,7 +178,7 @@ var _ = Describe("ImportCmd", func() {
 					mockDownloader.EXPECT().IsOVACurrent().Return(true, errors.New("some-error")),
 				)
 
-				Expect(importCmd.Run()).To(MatchError("some-error"))
+				Expect(importCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 	})
@@ -4,6 +4,7 @@
 package mocks
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -28,12 +29,12 @@ func (_m *MockAutoCmd) EXPECT() *_MockAutoCmdRecorder {
 	return _m.recorder
 }
 
-func (_m *MockAutoCmd) Run() error {
-	ret := _m.ctrl.Call(_m, "Run")
+func (_m *MockAutoCmd) Run(_param0 context.Context) error {
+	ret := _m.ctrl.Call(_m, "Run", _param0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockAutoCmdRecorder) Run() *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "Run")
+func (_mr *_MockAutoCmdRecorder) Run(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Run", arg0)
 }
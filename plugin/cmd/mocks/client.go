@@ -4,6 +4,7 @@
 package mocks
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -28,34 +29,34 @@ func (_m *MockClient) EXPECT() *_MockClientRecorder {
 	return _m.recorder
 }
 
-func (_m *MockClient) AcceptEULA() error {
-	ret := _m.ctrl.Call(_m, "AcceptEULA")
+func (_m *MockClient) AcceptEULA(_param0 context.Context) error {
+	ret := _m.ctrl.Call(_m, "AcceptEULA", _param0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockClientRecorder) AcceptEULA() *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "AcceptEULA")
+func (_mr *_MockClientRecorder) AcceptEULA(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "AcceptEULA", arg0)
 }
 
-func (_m *MockClient) GetEULA() (string, error) {
-	ret := _m.ctrl.Call(_m, "GetEULA")
+func (_m *MockClient) GetEULA(_param0 context.Context) (string, error) {
+	ret := _m.ctrl.Call(_m, "GetEULA", _param0)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (_mr *_MockClientRecorder) GetEULA() *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetEULA")
+func (_mr *_MockClientRecorder) GetEULA(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetEULA", arg0)
 }
 
-func (_m *MockClient) IsEULAAccepted() (bool, error) {
-	ret := _m.ctrl.Call(_m, "IsEULAAccepted")
+func (_m *MockClient) IsEULAAccepted(_param0 context.Context) (bool, error) {
+	ret := _m.ctrl.Call(_m, "IsEULAAccepted", _param0)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (_mr *_MockClientRecorder) IsEULAAccepted() *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "IsEULAAccepted")
+func (_mr *_MockClientRecorder) IsEULAAccepted(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "IsEULAAccepted", arg0)
 }
@@ -4,6 +4,7 @@
 package mocks
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -28,14 +29,14 @@ func (_m *MockDownloader) EXPECT() *_MockDownloaderRecorder {
 	return _m.recorder
 }
 
-func (_m *MockDownloader) Download() error {
-	ret := _m.ctrl.Call(_m, "Download")
+func (_m *MockDownloader) Download(_param0 context.Context) error {
+	ret := _m.ctrl.Call(_m, "Download", _param0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockDownloaderRecorder) Download() *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "Download")
+func (_mr *_MockDownloaderRecorder) Download(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Download", arg0)
 }
 
 func (_m *MockDownloader) IsOVACurrent() (bool, error) {
@@ -61,6 +61,16 @@ func (_mr *_MockFSRecorder) MD5(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "MD5", arg0)
 }
 
+func (_m *MockFS) Move(_param0 string, _param1 string) error {
+	ret := _m.ctrl.Call(_m, "Move", _param0, _param1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockFSRecorder) Move(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Move", arg0, arg1)
+}
+
 func (_m *MockFS) Read(_param0 string) ([]byte, error) {
 	ret := _m.ctrl.Call(_m, "Read", _param0)
 	ret0, _ := ret[0].([]byte)
@@ -82,6 +92,17 @@ func (_mr *_MockFSRecorder) Remove(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Remove", arg0)
 }
 
+func (_m *MockFS) SHA256(_param0 string) (string, error) {
+	ret := _m.ctrl.Call(_m, "SHA256", _param0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockFSRecorder) SHA256(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "SHA256", arg0)
+}
+
 func (_m *MockFS) TempDir() (string, error) {
 	ret := _m.ctrl.Call(_m, "TempDir")
 	ret0, _ := ret[0].(string)
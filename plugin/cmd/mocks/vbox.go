@@ -4,6 +4,7 @@
 package mocks
 
 import (
+	"context"
 	"github.com/golang/mock/gomock"
 	"github.com/pivotal-cf/pcfdev-cli/config"
 	"github.com/pivotal-cf/pcfdev-cli/vboxdriver"
@@ -30,14 +31,24 @@ func (_m *MockVBox) EXPECT() *_MockVBoxRecorder {
 	return _m.recorder
 }
 
-func (_m *MockVBox) DestroyPCFDevVMs() error {
-	ret := _m.ctrl.Call(_m, "DestroyPCFDevVMs")
+func (_m *MockVBox) DestroyPCFDevVMs(_param0 context.Context) error {
+	ret := _m.ctrl.Call(_m, "DestroyPCFDevVMs", _param0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockVBoxRecorder) DestroyPCFDevVMs() *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "DestroyPCFDevVMs")
+func (_mr *_MockVBoxRecorder) DestroyPCFDevVMs(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "DestroyPCFDevVMs", arg0)
+}
+
+func (_m *MockVBox) DestroyVM(_param0 context.Context, _param1 *config.VMConfig) error {
+	ret := _m.ctrl.Call(_m, "DestroyVM", _param0, _param1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockVBoxRecorder) DestroyVM(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "DestroyVM", arg0, arg1)
 }
 
 func (_m *MockVBox) GetVMName() (string, error) {
@@ -51,6 +62,16 @@ func (_mr *_MockVBoxRecorder) GetVMName() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetVMName")
 }
 
+func (_m *MockVBox) PowerOffVM(_param0 *config.VMConfig) error {
+	ret := _m.ctrl.Call(_m, "PowerOffVM", _param0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockVBoxRecorder) PowerOffVM(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "PowerOffVM", arg0)
+}
+
 func (_m *MockVBox) VMConfig(_param0 string) (*config.VMConfig, error) {
 	ret := _m.ctrl.Call(_m, "VMConfig", _param0)
 	ret0, _ := ret[0].(*config.VMConfig)
@@ -62,6 +83,17 @@ func (_mr *_MockVBoxRecorder) VMConfig(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "VMConfig", arg0)
 }
 
+func (_m *MockVBox) VMStatus(_param0 string) (string, error) {
+	ret := _m.ctrl.Call(_m, "VMStatus", _param0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVBoxRecorder) VMStatus(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "VMStatus", arg0)
+}
+
 func (_m *MockVBox) Version() (*vboxdriver.VBoxDriverVersion, error) {
 	ret := _m.ctrl.Call(_m, "Version")
 	ret0, _ := ret[0].(*vboxdriver.VBoxDriverVersion)
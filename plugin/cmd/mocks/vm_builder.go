@@ -4,6 +4,7 @@
 package mocks
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 	vm "github.com/pivotal-cf/pcfdev-cli/vm"
 )
@@ -29,13 +30,13 @@ func (_m *MockVMBuilder) EXPECT() *_MockVMBuilderRecorder {
 	return _m.recorder
 }
 
-func (_m *MockVMBuilder) VM(_param0 string) (vm.VM, error) {
-	ret := _m.ctrl.Call(_m, "VM", _param0)
+func (_m *MockVMBuilder) VM(_param0 context.Context, _param1 string) (vm.VM, error) {
+	ret := _m.ctrl.Call(_m, "VM", _param0, _param1)
 	ret0, _ := ret[0].(vm.VM)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (_mr *_MockVMBuilderRecorder) VM(arg0 interface{}) *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "VM", arg0)
+func (_mr *_MockVMBuilderRecorder) VM(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "VM", arg0, arg1)
 }
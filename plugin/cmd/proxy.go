@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/cli/cf/flags"
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/guest"
+	"github.com/pivotal-cf/pcfdev-cli/proxy"
+	"github.com/pivotal-cf/pcfdev-cli/vbox"
+)
+
+const PROXY_ARGS = 1
+
+//go:generate mockgen -package mocks -destination mocks/proxy_store.go github.com/pivotal-cf/pcfdev-cli/plugin/cmd ProxyStore
+type ProxyStore interface {
+	SaveProxySettings(settings *config.ProxySettings) error
+	ClearProxySettings() error
+}
+
+//go:generate mockgen -package mocks -destination mocks/guest.go github.com/pivotal-cf/pcfdev-cli/plugin/cmd Guest
+type Guest interface {
+	ProxySettings(vmConfig *config.VMConfig) (settings *guest.ProxyTypes, err error)
+	ConfigureEnvironment(ctx context.Context, vmConfig *config.VMConfig) error
+	RestartProxiedServices(ctx context.Context, vmConfig *config.VMConfig) error
+	RotateKeypair(ctx context.Context, vmConfig *config.VMConfig, keyType string) error
+}
+
+// ProxyCmd changes the proxy settings of the PCF Dev VM without recreating
+// it. The settings are saved, so that they are also used by later starts.
+type ProxyCmd struct {
+	VBox        VBox
+	UI          UI
+	Config      *config.Config
+	ProxyStore  ProxyStore
+	Guest       Guest
+	action      string
+	flagContext flags.FlagContext
+}
+
+func (p *ProxyCmd) Parse(args []string) error {
+	p.flagContext = flags.New()
+	p.flagContext.NewStringFlag("http-proxy", "", "<http proxy>")
+	p.flagContext.NewStringFlag("https-proxy", "", "<https proxy>")
+	p.flagContext.NewStringFlag("no-proxy", "", "<no proxy>")
+	p.flagContext.NewStringFlag("pac", "", "<PAC file URL or path>")
+	if err := parse(p.flagContext, args, PROXY_ARGS); err != nil {
+		return err
+	}
+
+	p.action = p.flagContext.Args()[0]
+	settingFlagGiven := p.flagContext.IsSet("http-proxy") || p.flagContext.IsSet("https-proxy") || p.flagContext.IsSet("no-proxy") || p.flagContext.IsSet("pac")
+	switch {
+	case p.action != "set" && p.action != "unset" && p.action != "show":
+		return errors.New("unknown proxy action")
+	case p.action == "set" && !settingFlagGiven:
+		return errors.New("at least one of --http-proxy, --https-proxy, --no-proxy or --pac must be given")
+	case p.action != "set" && settingFlagGiven:
+		return errors.New("proxy flags can only be used with set")
+	}
+	return nil
+}
+
+func (p *ProxyCmd) Run(ctx context.Context) error {
+	switch p.action {
+	case "set":
+		settings := &config.ProxySettings{
+			HTTPProxy:  p.Config.HTTPProxy,
+			HTTPSProxy: p.Config.HTTPSProxy,
+			NoProxy:    p.Config.NoProxy,
+			PAC:        p.Config.ProxyPAC,
+		}
+		if p.flagContext.IsSet("http-proxy") {
+			settings.HTTPProxy = p.flagContext.String("http-proxy")
+		}
+		if p.flagContext.IsSet("https-proxy") {
+			settings.HTTPSProxy = p.flagContext.String("https-proxy")
+		}
+		if p.flagContext.IsSet("no-proxy") {
+			settings.NoProxy = p.flagContext.String("no-proxy")
+		}
+		if p.flagContext.IsSet("pac") {
+			settings.PAC = p.flagContext.String("pac")
+		}
+		if err := p.ProxyStore.SaveProxySettings(settings); err != nil {
+			return err
+		}
+		return p.apply(ctx)
+	case "unset":
+		if err := p.ProxyStore.ClearProxySettings(); err != nil {
+			return err
+		}
+		return p.apply(ctx)
+	default:
+		return p.show()
+	}
+}
+
+func (p *ProxyCmd) apply(ctx context.Context) error {
+	name, err := p.VBox.GetVMName()
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		p.UI.Say("Proxy settings saved. They will be used when PCF Dev is created.")
+		return nil
+	}
+
+	status, err := p.VBox.VMStatus(name)
+	if err != nil {
+		return err
+	}
+	if status != vbox.StatusRunning {
+		p.UI.Say("Proxy settings saved. They will be applied when PCF Dev is next started.")
+		return nil
+	}
+
+	vmConfig, err := getVMConfig(p.VBox, p.Config)
+	if err != nil {
+		return err
+	}
+	if err := p.Guest.ConfigureEnvironment(ctx, vmConfig); err != nil {
+		return &ProxyUpdateError{err}
+	}
+	p.UI.Say("Restarting services to apply the proxy settings...")
+	if err := p.Guest.RestartProxiedServices(ctx, vmConfig); err != nil {
+		return &ProxyUpdateError{err}
+	}
+	p.UI.Say("Proxy settings applied. PCF Dev may take a few minutes to become available again.")
+	return nil
+}
+
+func (p *ProxyCmd) show() error {
+	source := "the proxy environment variables of this host"
+	if p.Config.ProxySaved {
+		source = "'cf dev proxy set'"
+	}
+
+	vmConfig, err := getVMConfig(p.VBox, p.Config)
+	if _, notCreated := err.(*NotCreatedError); notCreated {
+		p.UI.Say("Proxy settings from %s:\nHTTP_PROXY=%s\nHTTPS_PROXY=%s\nNO_PROXY=%s\nPAC=%s", source, proxy.Redact(p.Config.HTTPProxy), proxy.Redact(p.Config.HTTPSProxy), p.Config.NoProxy, p.Config.ProxyPAC)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	settings, err := p.Guest.ProxySettings(vmConfig)
+	if err != nil {
+		return err
+	}
+	p.UI.Say("Proxy settings of the PCF Dev VM, from %s:\nHTTP_PROXY=%s\nHTTPS_PROXY=%s\nNO_PROXY=%s", source, proxy.Redact(settings.HTTPProxy), proxy.Redact(settings.HTTPSProxy), settings.NOProxy)
+	return nil
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"github.com/cloudfoundry/cli/cf/flags"
 	"github.com/pivotal-cf/pcfdev-cli/config"
 	"github.com/pivotal-cf/pcfdev-cli/vm"
@@ -18,15 +19,15 @@ func (r *ResumeCmd) Parse(args []string) error {
 	return parse(flags.New(), args, RESUME_ARGS)
 }
 
-func (r *ResumeCmd) Run() error {
-	vm, err := r.getVM()
+func (r *ResumeCmd) Run(ctx context.Context) error {
+	vm, err := r.getVM(ctx)
 	if err != nil {
 		return err
 	}
-	return vm.Resume()
+	return vm.Resume(ctx)
 }
 
-func (r *ResumeCmd) getVM() (vm vm.VM, err error) {
+func (r *ResumeCmd) getVM(ctx context.Context) (vm vm.VM, err error) {
 	name, err := r.VBox.GetVMName()
 	if err != nil {
 		return nil, err
@@ -38,5 +39,5 @@ func (r *ResumeCmd) getVM() (vm vm.VM, err error) {
 		return nil, &OldVMError{}
 	}
 
-	return r.VMBuilder.VM(name)
+	return r.VMBuilder.VM(ctx, name)
 }
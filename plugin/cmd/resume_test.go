@@ -1,6 +1,7 @@
 package cmd_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/golang/mock/gomock"
@@ -62,11 +63,11 @@ var _ = Describe("ResumeCmd", func() {
 			It("should resume the VM", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
-					mockVM.EXPECT().Resume(),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
+					mockVM.EXPECT().Resume(gomock.Any()),
 				)
 
-				Expect(resumeCmd.Run()).To(Succeed())
+				Expect(resumeCmd.Run(context.Background())).To(Succeed())
 			})
 		})
 
@@ -74,11 +75,11 @@ var _ = Describe("ResumeCmd", func() {
 			It("should resume the VM", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("pcfdev-custom", nil),
-					mockVMBuilder.EXPECT().VM("pcfdev-custom").Return(mockVM, nil),
-					mockVM.EXPECT().Resume(),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "pcfdev-custom").Return(mockVM, nil),
+					mockVM.EXPECT().Resume(gomock.Any()),
 				)
 
-				Expect(resumeCmd.Run()).To(Succeed())
+				Expect(resumeCmd.Run(context.Background())).To(Succeed())
 			})
 		})
 
@@ -86,11 +87,11 @@ var _ = Describe("ResumeCmd", func() {
 			It("should resume the default VM", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
-					mockVM.EXPECT().Resume(),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
+					mockVM.EXPECT().Resume(gomock.Any()),
 				)
 
-				Expect(resumeCmd.Run()).To(Succeed())
+				Expect(resumeCmd.Run(context.Background())).To(Succeed())
 			})
 		})
 
@@ -98,7 +99,7 @@ var _ = Describe("ResumeCmd", func() {
 			It("should tell the user to destroy pcfdev", func() {
 				mockVBox.EXPECT().GetVMName().Return("some-old-vm-name", nil)
 
-				Expect(resumeCmd.Run()).To(MatchError("old version of PCF Dev already running, please run `cf dev destroy` to continue"))
+				Expect(resumeCmd.Run(context.Background())).To(MatchError("old version of PCF Dev already running, please run `cf dev upgrade` or `cf dev destroy` to continue"))
 			})
 		})
 
@@ -106,7 +107,7 @@ var _ = Describe("ResumeCmd", func() {
 			It("should return the error", func() {
 				mockVBox.EXPECT().GetVMName().Return("", errors.New("some-error"))
 
-				Expect(resumeCmd.Run()).To(MatchError("some-error"))
+				Expect(resumeCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -114,10 +115,10 @@ var _ = Describe("ResumeCmd", func() {
 			It("should return an error", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(nil, errors.New("some-error")),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(nil, errors.New("some-error")),
 				)
 
-				Expect(resumeCmd.Run()).To(MatchError("some-error"))
+				Expect(resumeCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -125,11 +126,11 @@ var _ = Describe("ResumeCmd", func() {
 			It("should return an error", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
-					mockVM.EXPECT().Resume().Return(errors.New("some-error")),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
+					mockVM.EXPECT().Resume(gomock.Any()).Return(errors.New("some-error")),
 				)
 
-				Expect(resumeCmd.Run()).To(MatchError("some-error"))
+				Expect(resumeCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 	})
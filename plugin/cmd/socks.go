@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/flags"
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/socks"
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
+	"github.com/pivotal-cf/pcfdev-cli/vbox"
+)
+
+const (
+	SOCKS_ARGS       = 0
+	defaultSOCKSPort = 1080
+	socksDialTimeout = 30 * time.Second
+	socksListenHost  = "127.0.0.1"
+)
+
+//go:generate mockgen -package mocks -destination mocks/socks_server.go github.com/pivotal-cf/pcfdev-cli/plugin/cmd SOCKSServer
+type SOCKSServer interface {
+	ListenAndServe(ctx context.Context, address string, dial socks.DialFunc) error
+}
+
+//go:generate mockgen -package mocks -destination mocks/ssh.go github.com/pivotal-cf/pcfdev-cli/plugin/cmd SSH
+type SSH interface {
+	Dial(ctx context.Context, network string, address string, sshAddresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration) (net.Conn, error)
+}
+
+// SOCKSCmd runs a SOCKS5 server on this host that makes every connection
+// from inside the PCF Dev VM, over SSH, so that the VM can be reached when
+// its host-only network cannot.
+type SOCKSCmd struct {
+	VBox        VBox
+	UI          UI
+	Config      *config.Config
+	FS          FS
+	SSH         SSH
+	SOCKSServer SOCKSServer
+	port        int
+}
+
+func (s *SOCKSCmd) Parse(args []string) error {
+	flagContext := flags.New()
+	flagContext.NewIntFlag("port", "", "<port>")
+	if err := parse(flagContext, args, SOCKS_ARGS); err != nil {
+		return err
+	}
+
+	s.port = defaultSOCKSPort
+	if flagContext.IsSet("port") {
+		s.port = flagContext.Int("port")
+	}
+	if s.port < 1 || s.port > 65535 {
+		return errors.New("--port must be between 1 and 65535")
+	}
+	return nil
+}
+
+func (s *SOCKSCmd) Run(ctx context.Context) error {
+	vmConfig, err := getVMConfig(s.VBox, s.Config)
+	if err != nil {
+		return err
+	}
+
+	status, err := s.VBox.VMStatus(vmConfig.Name)
+	if err != nil {
+		return err
+	}
+	if status != vbox.StatusRunning {
+		return &NotRunningError{}
+	}
+
+	privateKey, err := s.FS.Read(s.Config.PrivateKeyPath)
+	if err != nil {
+		return err
+	}
+	sshAddresses := ssh.SSHAddresses(vmConfig)
+
+	address := net.JoinHostPort(socksListenHost, strconv.Itoa(s.port))
+	s.UI.Say("Listening for SOCKS5 connections on %s, which are made from inside the PCF Dev VM. Press Ctrl-C to stop.", address)
+	s.UI.Say("For example: curl --socks5-hostname %s https://api.%s", address, vmConfig.Domain)
+	return s.SOCKSServer.ListenAndServe(ctx, address, func(network string, destination string) (net.Conn, error) {
+		return s.SSH.Dial(ctx, network, destination, sshAddresses, privateKey, socksDialTimeout)
+	})
+}
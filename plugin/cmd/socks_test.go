@@ -0,0 +1,175 @@
+package cmd_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/plugin/cmd"
+	"github.com/pivotal-cf/pcfdev-cli/plugin/cmd/mocks"
+	"github.com/pivotal-cf/pcfdev-cli/socks"
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
+)
+
+var _ = Describe("SOCKSCmd", func() {
+	var (
+		socksCmd        *cmd.SOCKSCmd
+		mockCtrl        *gomock.Controller
+		mockVBox        *mocks.MockVBox
+		mockUI          *mocks.MockUI
+		mockFS          *mocks.MockFS
+		mockSSH         *mocks.MockSSH
+		mockSOCKSServer *mocks.MockSOCKSServer
+		vmConfig        *config.VMConfig
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockVBox = mocks.NewMockVBox(mockCtrl)
+		mockUI = mocks.NewMockUI(mockCtrl)
+		mockFS = mocks.NewMockFS(mockCtrl)
+		mockSSH = mocks.NewMockSSH(mockCtrl)
+		mockSOCKSServer = mocks.NewMockSOCKSServer(mockCtrl)
+		socksCmd = &cmd.SOCKSCmd{
+			VBox:        mockVBox,
+			UI:          mockUI,
+			FS:          mockFS,
+			SSH:         mockSSH,
+			SOCKSServer: mockSOCKSServer,
+			Config: &config.Config{
+				DefaultVMName:  "some-default-vm-name",
+				PrivateKeyPath: "some-private-key-path",
+			},
+		}
+		vmConfig = &config.VMConfig{
+			Name:    "some-default-vm-name",
+			IP:      "192.168.11.11",
+			SSHPort: "some-port",
+			Domain:  "local.pcfdev.io",
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("Parse", func() {
+		It("should accept a port", func() {
+			Expect(socksCmd.Parse([]string{})).To(Succeed())
+			Expect(socksCmd.Parse([]string{"--port", "1081"})).To(Succeed())
+		})
+
+		Context("when the wrong number of arguments are passed", func() {
+			It("should fail", func() {
+				Expect(socksCmd.Parse([]string{"some-bad-arg"})).NotTo(Succeed())
+			})
+		})
+
+		Context("when the port is out of range", func() {
+			It("should fail", func() {
+				Expect(socksCmd.Parse([]string{"--port", "0"})).To(MatchError("--port must be between 1 and 65535"))
+				Expect(socksCmd.Parse([]string{"--port", "65536"})).To(MatchError("--port must be between 1 and 65535"))
+			})
+		})
+	})
+
+	Describe("Run", func() {
+		It("should serve SOCKS5 on port 1080, dialing through the VM", func() {
+			Expect(socksCmd.Parse([]string{})).To(Succeed())
+			destination, _ := net.Pipe()
+
+			gomock.InOrder(
+				mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+				mockVBox.EXPECT().VMConfig("some-default-vm-name").Return(vmConfig, nil),
+				mockVBox.EXPECT().VMStatus("some-default-vm-name").Return("Running", nil),
+				mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
+				mockUI.EXPECT().Say("Listening for SOCKS5 connections on %s, which are made from inside the PCF Dev VM. Press Ctrl-C to stop.", "127.0.0.1:1080"),
+				mockUI.EXPECT().Say("For example: curl --socks5-hostname %s https://api.%s", "127.0.0.1:1080", "local.pcfdev.io"),
+				mockSOCKSServer.EXPECT().ListenAndServe(gomock.Any(), "127.0.0.1:1080", gomock.Any()).Do(func(_ context.Context, address string, dial socks.DialFunc) {
+					Expect(dial("tcp", "api.local.pcfdev.io:443")).To(BeIdenticalTo(destination))
+				}),
+			)
+			mockSSH.EXPECT().Dial(gomock.Any(), "tcp", "api.local.pcfdev.io:443", []ssh.SSHAddress{
+				{IP: "127.0.0.1", Port: "some-port"},
+				{IP: "192.168.11.11", Port: "22"},
+			}, []byte("some-private-key"), 30*time.Second).Return(destination, nil)
+
+			Expect(socksCmd.Run(context.Background())).To(Succeed())
+		})
+
+		Context("when the VM is on a NAT network", func() {
+			It("should only dial the forwarded SSH port", func() {
+				Expect(socksCmd.Parse([]string{})).To(Succeed())
+				vmConfig.IP = "127.0.0.1"
+				destination, _ := net.Pipe()
+
+				gomock.InOrder(
+					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+					mockVBox.EXPECT().VMConfig("some-default-vm-name").Return(vmConfig, nil),
+					mockVBox.EXPECT().VMStatus("some-default-vm-name").Return("Running", nil),
+					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
+					mockUI.EXPECT().Say(gomock.Any(), gomock.Any()),
+					mockUI.EXPECT().Say(gomock.Any(), gomock.Any(), gomock.Any()),
+					mockSOCKSServer.EXPECT().ListenAndServe(gomock.Any(), "127.0.0.1:1080", gomock.Any()).Do(func(_ context.Context, address string, dial socks.DialFunc) {
+						Expect(dial("tcp", "api.local.pcfdev.io:443")).To(BeIdenticalTo(destination))
+					}),
+				)
+				mockSSH.EXPECT().Dial(gomock.Any(), "tcp", "api.local.pcfdev.io:443", []ssh.SSHAddress{
+					{IP: "127.0.0.1", Port: "some-port"},
+				}, []byte("some-private-key"), 30*time.Second).Return(destination, nil)
+
+				Expect(socksCmd.Run(context.Background())).To(Succeed())
+			})
+		})
+
+		It("should listen on the given port", func() {
+			Expect(socksCmd.Parse([]string{"--port", "1081"})).To(Succeed())
+
+			gomock.InOrder(
+				mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+				mockVBox.EXPECT().VMConfig("some-default-vm-name").Return(vmConfig, nil),
+				mockVBox.EXPECT().VMStatus("some-default-vm-name").Return("Running", nil),
+				mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
+				mockUI.EXPECT().Say(gomock.Any(), "127.0.0.1:1081"),
+				mockUI.EXPECT().Say(gomock.Any(), "127.0.0.1:1081", "local.pcfdev.io"),
+				mockSOCKSServer.EXPECT().ListenAndServe(gomock.Any(), "127.0.0.1:1081", gomock.Any()).Return(errors.New("some-error")),
+			)
+
+			Expect(socksCmd.Run(context.Background())).To(MatchError("some-error"))
+		})
+
+		Context("when the VM is not running", func() {
+			It("should return an error", func() {
+				Expect(socksCmd.Parse([]string{})).To(Succeed())
+
+				gomock.InOrder(
+					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+					mockVBox.EXPECT().VMConfig("some-default-vm-name").Return(vmConfig, nil),
+					mockVBox.EXPECT().VMStatus("some-default-vm-name").Return("Stopped", nil),
+				)
+
+				Expect(socksCmd.Run(context.Background())).To(MatchError("PCF Dev VM is not running"))
+			})
+		})
+
+		Context("when the private key cannot be read", func() {
+			It("should return the error", func() {
+				Expect(socksCmd.Parse([]string{})).To(Succeed())
+
+				gomock.InOrder(
+					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+					mockVBox.EXPECT().VMConfig("some-default-vm-name").Return(vmConfig, nil),
+					mockVBox.EXPECT().VMStatus("some-default-vm-name").Return("Running", nil),
+					mockFS.EXPECT().Read("some-private-key-path").Return(nil, errors.New("some-error")),
+				)
+
+				Expect(socksCmd.Run(context.Background())).To(MatchError("some-error"))
+			})
+		})
+	})
+})
@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"context"
+	"strings"
+
 	"github.com/cloudfoundry/cli/cf/flags"
 	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
 	"github.com/pivotal-cf/pcfdev-cli/vm"
 )
 
@@ -12,21 +16,58 @@ type SSHCmd struct {
 	VMBuilder VMBuilder
 	VBox      VBox
 	Config    *config.Config
+	opts      *ssh.SessionOptions
 }
 
 func (s *SSHCmd) Parse(args []string) error {
-	return parse(flags.New(), args, SSH_ARGS)
+	var command []string
+	for i, arg := range args {
+		if arg == "--" {
+			args, command = args[:i], args[i+1:]
+			break
+		}
+	}
+
+	flagContext := flags.New()
+	flagContext.NewBoolFlag("t", "", "<force tty>")
+	flagContext.NewBoolFlag("A", "", "<agent forwarding>")
+	flagContext.NewStringSliceFlag("L", "", "<local forward>")
+	flagContext.NewStringSliceFlag("R", "", "<remote forward>")
+	if err := parse(flagContext, args, SSH_ARGS); err != nil {
+		return err
+	}
+
+	s.opts = &ssh.SessionOptions{
+		Command:         strings.Join(command, " "),
+		TTY:             flagContext.Bool("t"),
+		AgentForwarding: flagContext.Bool("A"),
+	}
+	for _, spec := range flagContext.StringSlice("L") {
+		forward, err := ssh.ParseForward(spec)
+		if err != nil {
+			return err
+		}
+		s.opts.LocalForwards = append(s.opts.LocalForwards, forward)
+	}
+	for _, spec := range flagContext.StringSlice("R") {
+		forward, err := ssh.ParseForward(spec)
+		if err != nil {
+			return err
+		}
+		s.opts.RemoteForwards = append(s.opts.RemoteForwards, forward)
+	}
+	return nil
 }
 
-func (s *SSHCmd) Run() error {
-	vm, err := s.getVM()
+func (s *SSHCmd) Run(ctx context.Context) error {
+	vm, err := s.getVM(ctx)
 	if err != nil {
 		return err
 	}
-	return vm.SSH()
+	return vm.SSH(ctx, s.opts)
 }
 
-func (s *SSHCmd) getVM() (vm vm.VM, err error) {
+func (s *SSHCmd) getVM(ctx context.Context) (vm vm.VM, err error) {
 	name, err := s.VBox.GetVMName()
 	if err != nil {
 		return nil, err
@@ -38,5 +79,5 @@ func (s *SSHCmd) getVM() (vm vm.VM, err error) {
 		return nil, &OldVMError{}
 	}
 
-	return s.VMBuilder.VM(name)
+	return s.VMBuilder.VM(ctx, name)
 }
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/cli/cf/flags"
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
+	"github.com/pivotal-cf/pcfdev-cli/vbox"
+)
+
+const SSH_KEY_ARGS = 1
+
+// SSHKeyCmd replaces the keypair that is used to SSH into the PCF Dev VM.
+type SSHKeyCmd struct {
+	VBox    VBox
+	UI      UI
+	Config  *config.Config
+	Guest   Guest
+	keyType string
+}
+
+func (s *SSHKeyCmd) Parse(args []string) error {
+	flagContext := flags.New()
+	flagContext.NewStringFlag("type", "", "<key type>")
+	if err := parse(flagContext, args, SSH_KEY_ARGS); err != nil {
+		return err
+	}
+
+	if flagContext.Args()[0] != "rotate" {
+		return errors.New("unknown ssh-key action")
+	}
+	s.keyType = flagContext.String("type")
+	switch s.keyType {
+	case "", ssh.KeyTypeRSA, ssh.KeyTypeEd25519:
+		return nil
+	}
+	return errors.New("--type must be rsa or ed25519")
+}
+
+func (s *SSHKeyCmd) Run(ctx context.Context) error {
+	vmConfig, err := getVMConfig(s.VBox, s.Config)
+	if err != nil {
+		return err
+	}
+
+	status, err := s.VBox.VMStatus(vmConfig.Name)
+	if err != nil {
+		return err
+	}
+	if status != vbox.StatusRunning {
+		return &NotRunningError{}
+	}
+
+	s.UI.Say("Rotating the SSH key of the PCF Dev VM...")
+	if err := s.Guest.RotateKeypair(ctx, vmConfig, s.keyType); err != nil {
+		return &SSHKeyRotateError{err}
+	}
+	s.UI.Say("SSH key rotated.")
+	return nil
+}
@@ -0,0 +1,122 @@
+package cmd_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/plugin/cmd"
+	"github.com/pivotal-cf/pcfdev-cli/plugin/cmd/mocks"
+)
+
+var _ = Describe("SSHKeyCmd", func() {
+	var (
+		sshKeyCmd *cmd.SSHKeyCmd
+		mockCtrl  *gomock.Controller
+		mockVBox  *mocks.MockVBox
+		mockUI    *mocks.MockUI
+		mockGuest *mocks.MockGuest
+		vmConfig  *config.VMConfig
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockVBox = mocks.NewMockVBox(mockCtrl)
+		mockUI = mocks.NewMockUI(mockCtrl)
+		mockGuest = mocks.NewMockGuest(mockCtrl)
+		sshKeyCmd = &cmd.SSHKeyCmd{
+			VBox:   mockVBox,
+			UI:     mockUI,
+			Guest:  mockGuest,
+			Config: &config.Config{DefaultVMName: "some-default-vm-name"},
+		}
+		vmConfig = &config.VMConfig{Name: "some-default-vm-name"}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("Parse", func() {
+		It("should accept rotate with an optional key type", func() {
+			Expect(sshKeyCmd.Parse([]string{"rotate"})).To(Succeed())
+			Expect(sshKeyCmd.Parse([]string{"rotate", "--type", "rsa"})).To(Succeed())
+			Expect(sshKeyCmd.Parse([]string{"rotate", "--type", "ed25519"})).To(Succeed())
+		})
+
+		Context("when the wrong number of arguments are passed", func() {
+			It("should fail", func() {
+				Expect(sshKeyCmd.Parse([]string{})).NotTo(Succeed())
+				Expect(sshKeyCmd.Parse([]string{"rotate", "some-bad-arg"})).NotTo(Succeed())
+			})
+		})
+
+		Context("when an unknown action is passed", func() {
+			It("should fail", func() {
+				Expect(sshKeyCmd.Parse([]string{"some-bad-action"})).To(MatchError("unknown ssh-key action"))
+			})
+		})
+
+		Context("when an unknown key type is passed", func() {
+			It("should fail", func() {
+				Expect(sshKeyCmd.Parse([]string{"rotate", "--type", "dsa"})).To(MatchError("--type must be rsa or ed25519"))
+			})
+		})
+	})
+
+	Describe("Run", func() {
+		BeforeEach(func() {
+			Expect(sshKeyCmd.Parse([]string{"rotate", "--type", "ed25519"})).To(Succeed())
+		})
+
+		It("should rotate the keypair of the VM", func() {
+			gomock.InOrder(
+				mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+				mockVBox.EXPECT().VMConfig("some-default-vm-name").Return(vmConfig, nil),
+				mockVBox.EXPECT().VMStatus("some-default-vm-name").Return("Running", nil),
+				mockUI.EXPECT().Say("Rotating the SSH key of the PCF Dev VM..."),
+				mockGuest.EXPECT().RotateKeypair(gomock.Any(), vmConfig, "ed25519"),
+				mockUI.EXPECT().Say("SSH key rotated."),
+			)
+
+			Expect(sshKeyCmd.Run(context.Background())).To(Succeed())
+		})
+
+		Context("when the VM has not been created", func() {
+			It("should return an error", func() {
+				mockVBox.EXPECT().GetVMName().Return("", nil)
+
+				Expect(sshKeyCmd.Run(context.Background())).To(MatchError("PCF Dev VM has not been created"))
+			})
+		})
+
+		Context("when the VM is not running", func() {
+			It("should return an error", func() {
+				gomock.InOrder(
+					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+					mockVBox.EXPECT().VMConfig("some-default-vm-name").Return(vmConfig, nil),
+					mockVBox.EXPECT().VMStatus("some-default-vm-name").Return("Stopped", nil),
+				)
+
+				Expect(sshKeyCmd.Run(context.Background())).To(MatchError("PCF Dev VM is not running"))
+			})
+		})
+
+		Context("when rotating the keypair fails", func() {
+			It("should return an error", func() {
+				gomock.InOrder(
+					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+					mockVBox.EXPECT().VMConfig("some-default-vm-name").Return(vmConfig, nil),
+					mockVBox.EXPECT().VMStatus("some-default-vm-name").Return("Running", nil),
+					mockUI.EXPECT().Say("Rotating the SSH key of the PCF Dev VM..."),
+					mockGuest.EXPECT().RotateKeypair(gomock.Any(), vmConfig, "ed25519").Return(errors.New("some-error")),
+				)
+
+				Expect(sshKeyCmd.Run(context.Background())).To(MatchError("failed to rotate the SSH key: some-error"))
+			})
+		})
+	})
+})
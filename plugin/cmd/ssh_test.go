@@ -1,6 +1,7 @@
 package cmd_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/golang/mock/gomock"
@@ -9,6 +10,7 @@ import (
 	"github.com/pivotal-cf/pcfdev-cli/config"
 	"github.com/pivotal-cf/pcfdev-cli/plugin/cmd"
 	"github.com/pivotal-cf/pcfdev-cli/plugin/cmd/mocks"
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
 	vmMocks "github.com/pivotal-cf/pcfdev-cli/vm/mocks"
 )
 
@@ -45,6 +47,41 @@ var _ = Describe("SSHCmd", func() {
 				Expect(sshCmd.Parse([]string{})).To(Succeed())
 			})
 		})
+		Context("when a command and options are passed", func() {
+			It("should pass them to the session", func() {
+				Expect(sshCmd.Parse([]string{
+					"-t", "-A",
+					"-L", "8080:localhost:80",
+					"-L", "0.0.0.0:8443:some-host:443",
+					"-R", "9000:localhost:9000",
+					"--", "ls", "-la", "/var/vcap",
+				})).To(Succeed())
+
+				gomock.InOrder(
+					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
+					mockVM.EXPECT().SSH(gomock.Any(), &ssh.SessionOptions{
+						Command:         "ls -la /var/vcap",
+						TTY:             true,
+						AgentForwarding: true,
+						LocalForwards: []ssh.Forward{
+							{Port: "8080", Host: "localhost", HostPort: "80"},
+							{BindAddress: "0.0.0.0", Port: "8443", Host: "some-host", HostPort: "443"},
+						},
+						RemoteForwards: []ssh.Forward{
+							{Port: "9000", Host: "localhost", HostPort: "9000"},
+						},
+					}),
+				)
+
+				Expect(sshCmd.Run(context.Background())).To(Succeed())
+			})
+		})
+		Context("when a port forward is invalid", func() {
+			It("should fail", func() {
+				Expect(sshCmd.Parse([]string{"-L", "8080"})).To(MatchError("invalid port forward '8080', expected [bind_address:]port:host:hostport"))
+			})
+		})
 		Context("when the wrong number of arguments are passed", func() {
 			It("should fail", func() {
 				Expect(sshCmd.Parse([]string{"some-bad-arg"})).NotTo(Succeed())
@@ -58,21 +95,25 @@ var _ = Describe("SSHCmd", func() {
 	})
 
 	Describe("Run", func() {
-		It("should call SSH on the VM", func() {
+		BeforeEach(func() {
+			Expect(sshCmd.Parse([]string{})).To(Succeed())
+		})
+
+		It("should start a shell on the VM", func() {
 			gomock.InOrder(
 				mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
-				mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
-				mockVM.EXPECT().SSH(),
+				mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
+				mockVM.EXPECT().SSH(gomock.Any(), &ssh.SessionOptions{}),
 			)
 
-			Expect(sshCmd.Run()).To(Succeed())
+			Expect(sshCmd.Run(context.Background())).To(Succeed())
 		})
 
 		Context("when there is an error getting the VM name", func() {
 			It("should return the error", func() {
 				mockVBox.EXPECT().GetVMName().Return("", errors.New("some-error"))
 
-				Expect(sshCmd.Run()).To(MatchError("some-error"))
+				Expect(sshCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -80,10 +121,10 @@ var _ = Describe("SSHCmd", func() {
 			It("should return the error", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(nil, errors.New("some-error")),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(nil, errors.New("some-error")),
 				)
 
-				Expect(sshCmd.Run()).To(MatchError("some-error"))
+				Expect(sshCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -91,11 +132,11 @@ var _ = Describe("SSHCmd", func() {
 			It("should return the error", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
-					mockVM.EXPECT().SSH().Return(errors.New("some-error")),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
+					mockVM.EXPECT().SSH(gomock.Any(), &ssh.SessionOptions{}).Return(errors.New("some-error")),
 				)
 
-				Expect(sshCmd.Run()).To(MatchError("some-error"))
+				Expect(sshCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 	})
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"context"
 	"errors"
+	"fmt"
 
 	"github.com/cloudfoundry/cli/cf/flags"
 	"github.com/pivotal-cf/pcfdev-cli/config"
@@ -16,6 +18,7 @@ type StartCmd struct {
 	VBox         VBox
 	VMBuilder    VMBuilder
 	Config       *config.Config
+	OVAVerifier  OVAVerifier
 	AutoTrustCmd AutoCmd
 	DownloadCmd  Cmd
 	TargetCmd    Cmd
@@ -37,9 +40,14 @@ func (s *StartCmd) Parse(args []string) error {
 	s.flagContext.NewStringFlag("d", "", "<domain>")
 	s.flagContext.NewStringFlag("i", "", "<IP>")
 	s.flagContext.NewBoolFlag("x", "", "<master password>")
+	s.flagContext.NewStringFlag("network-mode", "", "<network mode>")
+	s.flagContext.NewBoolFlag("verify", "", "<verify custom ova>")
 	if err := parse(s.flagContext, args, START_ARGS); err != nil {
 		return err
 	}
+	if s.flagContext.Bool("verify") && s.flagContext.String("o") == "" {
+		return errors.New("--verify can only be used with -o")
+	}
 
 	var password string
 	if s.flagContext.Bool("x") {
@@ -61,18 +69,19 @@ func (s *StartCmd) Parse(args []string) error {
 		Domain:         s.flagContext.String("d"),
 		IP:             s.flagContext.String("i"),
 		MasterPassword: password,
+		NetworkMode:    s.flagContext.String("network-mode"),
 	}
 	return nil
 }
 
-func (s *StartCmd) Run() error {
+func (s *StartCmd) Run(ctx context.Context) error {
 	version, err := s.VBox.Version()
 	if err != nil {
 		return err
 	}
 
 	if version.Major < 5 {
-		return &OldDriverError{}
+		return &OldDriverError{Provider: s.Config.Provider}
 	}
 
 	var name string
@@ -103,35 +112,42 @@ func (s *StartCmd) Run() error {
 		name = "pcfdev-custom"
 	}
 
-	v, err := s.VMBuilder.VM(name)
+	v, err := s.VMBuilder.VM(ctx, name)
 	if err != nil {
 		return err
 	}
 
 	if s.flagContext.Bool("p") {
-		return v.Provision(&vm.StartOpts{})
+		return v.Provision(ctx, &vm.StartOpts{})
 	} else {
 		if err := v.VerifyStartOpts(s.Opts); err != nil {
 			return err
 		}
+		if s.Opts.OVAPath != "" && s.flagContext.Bool("verify") {
+			ovaManifest, err := s.OVAVerifier.Verify(ctx, s.Opts.OVAPath, "")
+			if err != nil {
+				return err
+			}
+			s.UI.Say(fmt.Sprintf("Verified the signed manifest of OVA version %s.", ovaManifest.Version))
+		}
 		if s.Opts.OVAPath == "" && existingVMName != "pcfdev-custom" {
-			if err := s.DownloadCmd.Run(); err != nil {
+			if err := s.DownloadCmd.Run(ctx); err != nil {
 				return err
 			}
 		}
 
-		if err := v.Start(s.Opts); err != nil {
+		if err := v.Start(ctx, s.Opts); err != nil {
 			return err
 		}
 
 		if s.flagContext.Bool("k") {
-			if err := s.AutoTrustCmd.Run(); err != nil {
+			if err := s.AutoTrustCmd.Run(ctx); err != nil {
 				return err
 			}
 		}
 
 		if s.flagContext.Bool("t") {
-			return s.TargetCmd.Run()
+			return s.TargetCmd.Run(ctx)
 		}
 
 		return nil
@@ -1,12 +1,20 @@
 package cmd_test
 
 import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
 	"errors"
+	"io/ioutil"
+	"path/filepath"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/fs"
+	"github.com/pivotal-cf/pcfdev-cli/manifest"
 	"github.com/pivotal-cf/pcfdev-cli/plugin/cmd"
 	"github.com/pivotal-cf/pcfdev-cli/plugin/cmd/mocks"
 	"github.com/pivotal-cf/pcfdev-cli/vboxdriver"
@@ -27,6 +35,7 @@ var _ = Describe("StartCmd", func() {
 		mockAutoTrustCmd *mocks.MockAutoCmd
 		mockDownloadCmd  *mocks.MockCmd
 		mockTargetCmd    *mocks.MockCmd
+		mockOVAVerifier  *mocks.MockOVAVerifier
 	)
 
 	BeforeEach(func() {
@@ -39,6 +48,7 @@ var _ = Describe("StartCmd", func() {
 		mockAutoTrustCmd = mocks.NewMockAutoCmd(mockCtrl)
 		mockTargetCmd = mocks.NewMockCmd(mockCtrl)
 		mockUI = mocks.NewMockUI(mockCtrl)
+		mockOVAVerifier = mocks.NewMockOVAVerifier(mockCtrl)
 		startCmd = &cmd.StartCmd{
 			VBox:      mockVBox,
 			VMBuilder: mockVMBuilder,
@@ -50,6 +60,7 @@ var _ = Describe("StartCmd", func() {
 			AutoTrustCmd: mockAutoTrustCmd,
 			TargetCmd:    mockTargetCmd,
 			UI:           mockUI,
+			OVAVerifier:  mockOVAVerifier,
 		}
 	})
 
@@ -71,6 +82,7 @@ var _ = Describe("StartCmd", func() {
 					"-t",
 					"-i", "some-ip",
 					"-d", "some-domain",
+					"--network-mode", "bridged",
 				})).To(Succeed())
 
 				Expect(startCmd.Opts.CPUs).To(Equal(2))
@@ -83,6 +95,7 @@ var _ = Describe("StartCmd", func() {
 				Expect(startCmd.Opts.Domain).To(Equal("some-domain"))
 				Expect(startCmd.Opts.IP).To(Equal("some-ip"))
 				Expect(startCmd.Opts.MasterPassword).To(Equal(""))
+				Expect(startCmd.Opts.NetworkMode).To(Equal("bridged"))
 			})
 		})
 
@@ -99,6 +112,13 @@ var _ = Describe("StartCmd", func() {
 				Expect(startCmd.Opts.Domain).To(BeEmpty())
 				Expect(startCmd.Opts.IP).To(BeEmpty())
 				Expect(startCmd.Opts.MasterPassword).To(BeEmpty())
+				Expect(startCmd.Opts.NetworkMode).To(BeEmpty())
+			})
+		})
+
+		Context("when --verify is passed without -o", func() {
+			It("should return an error", func() {
+				Expect(startCmd.Parse([]string{"--verify"})).To(MatchError("--verify can only be used with -o"))
 			})
 		})
 
@@ -188,13 +208,13 @@ var _ = Describe("StartCmd", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5}, nil),
 					mockVBox.EXPECT().GetVMName().Return("", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
 					mockVM.EXPECT().VerifyStartOpts(startOpts),
-					mockDownloadCmd.EXPECT().Run(),
-					mockVM.EXPECT().Start(startOpts),
+					mockDownloadCmd.EXPECT().Run(gomock.Any()),
+					mockVM.EXPECT().Start(gomock.Any(), startOpts),
 				)
 
-				Expect(startCmd.Run()).To(Succeed())
+				Expect(startCmd.Run(context.Background())).To(Succeed())
 			})
 
 			Context("when the trust option is passed", func() {
@@ -204,14 +224,14 @@ var _ = Describe("StartCmd", func() {
 					gomock.InOrder(
 						mockVBox.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5}, nil),
 						mockVBox.EXPECT().GetVMName().Return("", nil),
-						mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
+						mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
 						mockVM.EXPECT().VerifyStartOpts(&vm.StartOpts{}),
-						mockDownloadCmd.EXPECT().Run(),
-						mockVM.EXPECT().Start(&vm.StartOpts{}),
-						mockAutoTrustCmd.EXPECT().Run(),
+						mockDownloadCmd.EXPECT().Run(gomock.Any()),
+						mockVM.EXPECT().Start(gomock.Any(), &vm.StartOpts{}),
+						mockAutoTrustCmd.EXPECT().Run(gomock.Any()),
 					)
 
-					Expect(startCmd.Run()).To(Succeed())
+					Expect(startCmd.Run(context.Background())).To(Succeed())
 				})
 			})
 
@@ -222,14 +242,14 @@ var _ = Describe("StartCmd", func() {
 					gomock.InOrder(
 						mockVBox.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5}, nil),
 						mockVBox.EXPECT().GetVMName().Return("", nil),
-						mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
+						mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
 						mockVM.EXPECT().VerifyStartOpts(&vm.StartOpts{Target: true}),
-						mockDownloadCmd.EXPECT().Run(),
-						mockVM.EXPECT().Start(&vm.StartOpts{Target: true}),
-						mockTargetCmd.EXPECT().Run(),
+						mockDownloadCmd.EXPECT().Run(gomock.Any()),
+						mockVM.EXPECT().Start(gomock.Any(), &vm.StartOpts{Target: true}),
+						mockTargetCmd.EXPECT().Run(gomock.Any()),
 					)
 
-					Expect(startCmd.Run()).To(Succeed())
+					Expect(startCmd.Run(context.Background())).To(Succeed())
 				})
 			})
 
@@ -240,14 +260,14 @@ var _ = Describe("StartCmd", func() {
 					gomock.InOrder(
 						mockVBox.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5}, nil),
 						mockVBox.EXPECT().GetVMName().Return("", nil),
-						mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
+						mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
 						mockVM.EXPECT().VerifyStartOpts(&vm.StartOpts{Target: true}),
-						mockDownloadCmd.EXPECT().Run(),
-						mockVM.EXPECT().Start(&vm.StartOpts{Target: true}),
-						mockTargetCmd.EXPECT().Run().Return(errors.New("some-error")),
+						mockDownloadCmd.EXPECT().Run(gomock.Any()),
+						mockVM.EXPECT().Start(gomock.Any(), &vm.StartOpts{Target: true}),
+						mockTargetCmd.EXPECT().Run(gomock.Any()).Return(errors.New("some-error")),
 					)
 
-					Expect(startCmd.Run()).To(MatchError("some-error"))
+					Expect(startCmd.Run(context.Background())).To(MatchError("some-error"))
 				})
 			})
 
@@ -258,15 +278,15 @@ var _ = Describe("StartCmd", func() {
 					gomock.InOrder(
 						mockVBox.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5}, nil),
 						mockVBox.EXPECT().GetVMName().Return("", nil),
-						mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
+						mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
 						mockVM.EXPECT().VerifyStartOpts(&vm.StartOpts{Target: true}),
-						mockDownloadCmd.EXPECT().Run(),
-						mockVM.EXPECT().Start(&vm.StartOpts{Target: true}),
-						mockAutoTrustCmd.EXPECT().Run(),
-						mockTargetCmd.EXPECT().Run(),
+						mockDownloadCmd.EXPECT().Run(gomock.Any()),
+						mockVM.EXPECT().Start(gomock.Any(), &vm.StartOpts{Target: true}),
+						mockAutoTrustCmd.EXPECT().Run(gomock.Any()),
+						mockTargetCmd.EXPECT().Run(gomock.Any()),
 					)
 
-					Expect(startCmd.Run()).To(Succeed())
+					Expect(startCmd.Run(context.Background())).To(Succeed())
 				})
 			})
 
@@ -274,7 +294,16 @@ var _ = Describe("StartCmd", func() {
 				It("should tell the user to upgrade virtualbox", func() {
 					mockVBox.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 4}, nil)
 
-					Expect(startCmd.Run()).To(MatchError("please install Virtualbox version 5 or greater"))
+					Expect(startCmd.Run(context.Background())).To(MatchError("please install Virtualbox version 5 or greater"))
+				})
+			})
+
+			Context("when libvirt version is too old", func() {
+				It("should tell the user to upgrade libvirt", func() {
+					startCmd.Config.Provider = "libvirt"
+					mockVBox.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 4}, nil)
+
+					Expect(startCmd.Run(context.Background())).To(MatchError("please install libvirt version 5 or greater"))
 				})
 			})
 
@@ -282,7 +311,7 @@ var _ = Describe("StartCmd", func() {
 				It("should return the error", func() {
 					mockVBox.EXPECT().Version().Return(nil, errors.New("some-error"))
 
-					Expect(startCmd.Run()).To(MatchError("some-error"))
+					Expect(startCmd.Run(context.Background())).To(MatchError("some-error"))
 				})
 			})
 
@@ -291,7 +320,7 @@ var _ = Describe("StartCmd", func() {
 					mockVBox.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5}, nil)
 					mockVBox.EXPECT().GetVMName().Return("some-old-vm-name", nil)
 
-					Expect(startCmd.Run()).To(MatchError("old version of PCF Dev already running, please run `cf dev destroy` to continue"))
+					Expect(startCmd.Run(context.Background())).To(MatchError("old version of PCF Dev already running, please run `cf dev upgrade` or `cf dev destroy` to continue"))
 				})
 			})
 
@@ -300,7 +329,7 @@ var _ = Describe("StartCmd", func() {
 					mockVBox.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5}, nil)
 					mockVBox.EXPECT().GetVMName().Return("", errors.New("some-error"))
 
-					Expect(startCmd.Run()).To(MatchError("some-error"))
+					Expect(startCmd.Run(context.Background())).To(MatchError("some-error"))
 				})
 			})
 
@@ -309,9 +338,9 @@ var _ = Describe("StartCmd", func() {
 					gomock.InOrder(
 						mockVBox.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5}, nil),
 						mockVBox.EXPECT().GetVMName().Return("", nil),
-						mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(nil, errors.New("some-error")),
+						mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(nil, errors.New("some-error")),
 					)
-					Expect(startCmd.Run()).To(MatchError("some-error"))
+					Expect(startCmd.Run(context.Background())).To(MatchError("some-error"))
 				})
 			})
 
@@ -320,11 +349,11 @@ var _ = Describe("StartCmd", func() {
 					gomock.InOrder(
 						mockVBox.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5}, nil),
 						mockVBox.EXPECT().GetVMName().Return("", nil),
-						mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
+						mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
 						mockVM.EXPECT().VerifyStartOpts(&vm.StartOpts{}).Return(errors.New("some-error")),
 					)
 
-					Expect(startCmd.Run()).To(MatchError("some-error"))
+					Expect(startCmd.Run(context.Background())).To(MatchError("some-error"))
 				})
 			})
 
@@ -333,12 +362,12 @@ var _ = Describe("StartCmd", func() {
 					gomock.InOrder(
 						mockVBox.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5}, nil),
 						mockVBox.EXPECT().GetVMName().Return("", nil),
-						mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
+						mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
 						mockVM.EXPECT().VerifyStartOpts(&vm.StartOpts{}),
-						mockDownloadCmd.EXPECT().Run().Return(errors.New("some-error")),
+						mockDownloadCmd.EXPECT().Run(gomock.Any()).Return(errors.New("some-error")),
 					)
 
-					Expect(startCmd.Run()).To(MatchError("some-error"))
+					Expect(startCmd.Run(context.Background())).To(MatchError("some-error"))
 				})
 			})
 
@@ -347,13 +376,13 @@ var _ = Describe("StartCmd", func() {
 					gomock.InOrder(
 						mockVBox.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5}, nil),
 						mockVBox.EXPECT().GetVMName().Return("", nil),
-						mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
+						mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
 						mockVM.EXPECT().VerifyStartOpts(&vm.StartOpts{}),
-						mockDownloadCmd.EXPECT().Run(),
-						mockVM.EXPECT().Start(&vm.StartOpts{}).Return(errors.New("some-error")),
+						mockDownloadCmd.EXPECT().Run(gomock.Any()),
+						mockVM.EXPECT().Start(gomock.Any(), &vm.StartOpts{}).Return(errors.New("some-error")),
 					)
 
-					Expect(startCmd.Run()).To(MatchError("some-error"))
+					Expect(startCmd.Run(context.Background())).To(MatchError("some-error"))
 				})
 			})
 
@@ -364,14 +393,14 @@ var _ = Describe("StartCmd", func() {
 					gomock.InOrder(
 						mockVBox.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5}, nil),
 						mockVBox.EXPECT().GetVMName().Return("", nil),
-						mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
+						mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
 						mockVM.EXPECT().VerifyStartOpts(&vm.StartOpts{}),
-						mockDownloadCmd.EXPECT().Run(),
-						mockVM.EXPECT().Start(&vm.StartOpts{}),
-						mockAutoTrustCmd.EXPECT().Run().Return(errors.New("some-error")),
+						mockDownloadCmd.EXPECT().Run(gomock.Any()),
+						mockVM.EXPECT().Start(gomock.Any(), &vm.StartOpts{}),
+						mockAutoTrustCmd.EXPECT().Run(gomock.Any()).Return(errors.New("some-error")),
 					)
 
-					Expect(startCmd.Run()).To(MatchError("some-error"))
+					Expect(startCmd.Run(context.Background())).To(MatchError("some-error"))
 				})
 			})
 		})
@@ -385,12 +414,95 @@ var _ = Describe("StartCmd", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5}, nil),
 					mockVBox.EXPECT().GetVMName().Return("", nil),
-					mockVMBuilder.EXPECT().VM("pcfdev-custom").Return(mockVM, nil),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "pcfdev-custom").Return(mockVM, nil),
 					mockVM.EXPECT().VerifyStartOpts(startOpts),
-					mockVM.EXPECT().Start(startOpts),
+					mockVM.EXPECT().Start(gomock.Any(), startOpts),
 				)
 
-				Expect(startCmd.Run()).To(Succeed())
+				Expect(startCmd.Run(context.Background())).To(Succeed())
+			})
+
+			Context("when --verify is passed", func() {
+				var startOpts *vm.StartOpts
+
+				BeforeEach(func() {
+					Expect(startCmd.Parse([]string{"-o", "some-custom-ova", "--verify"})).To(Succeed())
+					startOpts = &vm.StartOpts{
+						OVAPath: "some-custom-ova",
+					}
+					startCmd.Opts = startOpts
+				})
+
+				It("should verify the custom ova against its signed manifest before starting it", func() {
+					gomock.InOrder(
+						mockVBox.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5}, nil),
+						mockVBox.EXPECT().GetVMName().Return("", nil),
+						mockVMBuilder.EXPECT().VM(gomock.Any(), "pcfdev-custom").Return(mockVM, nil),
+						mockVM.EXPECT().VerifyStartOpts(startOpts),
+						mockOVAVerifier.EXPECT().Verify(gomock.Any(), "some-custom-ova", "").Return(&manifest.Manifest{Version: "some-ova-version"}, nil),
+						mockUI.EXPECT().Say("Verified the signed manifest of OVA version some-ova-version."),
+						mockVM.EXPECT().Start(gomock.Any(), startOpts),
+					)
+
+					Expect(startCmd.Run(context.Background())).To(Succeed())
+				})
+
+				Context("when the custom ova does not match its manifest", func() {
+					It("should return an error without starting it", func() {
+						gomock.InOrder(
+							mockVBox.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5}, nil),
+							mockVBox.EXPECT().GetVMName().Return("", nil),
+							mockVMBuilder.EXPECT().VM(gomock.Any(), "pcfdev-custom").Return(mockVM, nil),
+							mockVM.EXPECT().VerifyStartOpts(startOpts),
+							mockOVAVerifier.EXPECT().Verify(gomock.Any(), "some-custom-ova", "").Return(nil, errors.New("some-error")),
+						)
+
+						Expect(startCmd.Run(context.Background())).To(MatchError("some-error"))
+					})
+				})
+
+				Context("when the custom ova is signed for another OVA version", func() {
+					var tmpDir string
+
+					BeforeEach(func() {
+						var err error
+						tmpDir, err = ioutil.TempDir("", "pcfdev-start")
+						Expect(err).NotTo(HaveOccurred())
+
+						startOpts.OVAPath = filepath.Join(tmpDir, "some-custom.ova")
+						Expect(ioutil.WriteFile(startOpts.OVAPath, []byte("some-contents"), 0644)).To(Succeed())
+						contents := []byte(`{"version":"some-other-ova-version","size":13,"sha256":"6e32ea34db1b3755d7dec972eb72c705338f0dd8e0be881d966963438fb2e800"}`)
+						Expect(ioutil.WriteFile(startOpts.OVAPath+".manifest", contents, 0644)).To(Succeed())
+
+						publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+						Expect(err).NotTo(HaveOccurred())
+						signature := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, contents))
+						Expect(ioutil.WriteFile(startOpts.OVAPath+".manifest.sig", []byte(signature), 0644)).To(Succeed())
+
+						startCmd.Config.Version = &config.Version{OVABuildVersion: "some-ova-version"}
+						startCmd.OVAVerifier = &manifest.Verifier{
+							FS:        &fs.FS{},
+							PublicKey: base64.StdEncoding.EncodeToString(publicKey),
+						}
+					})
+
+					AfterEach(func() {
+						os.RemoveAll(tmpDir)
+					})
+
+					It("should start it", func() {
+						gomock.InOrder(
+							mockVBox.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5}, nil),
+							mockVBox.EXPECT().GetVMName().Return("", nil),
+							mockVMBuilder.EXPECT().VM(gomock.Any(), "pcfdev-custom").Return(mockVM, nil),
+							mockVM.EXPECT().VerifyStartOpts(startOpts),
+							mockUI.EXPECT().Say("Verified the signed manifest of OVA version some-other-ova-version."),
+							mockVM.EXPECT().Start(gomock.Any(), startOpts),
+						)
+
+						Expect(startCmd.Run(context.Background())).To(Succeed())
+					})
+				})
 			})
 
 			Context("when the custom VM is already present and OVAPath is not set", func() {
@@ -398,11 +510,11 @@ var _ = Describe("StartCmd", func() {
 					gomock.InOrder(
 						mockVBox.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5}, nil),
 						mockVBox.EXPECT().GetVMName().Return("pcfdev-custom", nil),
-						mockVMBuilder.EXPECT().VM("pcfdev-custom").Return(mockVM, nil),
+						mockVMBuilder.EXPECT().VM(gomock.Any(), "pcfdev-custom").Return(mockVM, nil),
 						mockVM.EXPECT().VerifyStartOpts(&vm.StartOpts{}),
-						mockVM.EXPECT().Start(&vm.StartOpts{}),
+						mockVM.EXPECT().Start(gomock.Any(), &vm.StartOpts{}),
 					)
-					Expect(startCmd.Run()).To(Succeed())
+					Expect(startCmd.Run(context.Background())).To(Succeed())
 				})
 			})
 
@@ -415,11 +527,11 @@ var _ = Describe("StartCmd", func() {
 					gomock.InOrder(
 						mockVBox.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5}, nil),
 						mockVBox.EXPECT().GetVMName().Return("pcfdev-custom", nil),
-						mockVMBuilder.EXPECT().VM("pcfdev-custom").Return(mockVM, nil),
+						mockVMBuilder.EXPECT().VM(gomock.Any(), "pcfdev-custom").Return(mockVM, nil),
 						mockVM.EXPECT().VerifyStartOpts(startOpts),
-						mockVM.EXPECT().Start(startOpts),
+						mockVM.EXPECT().Start(gomock.Any(), startOpts),
 					)
-					Expect(startCmd.Run()).To(Succeed())
+					Expect(startCmd.Run(context.Background())).To(Succeed())
 				})
 			})
 
@@ -431,7 +543,7 @@ var _ = Describe("StartCmd", func() {
 					startCmd.Opts = startOpts
 					mockVBox.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5}, nil)
 					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil)
-					Expect(startCmd.Run()).To(MatchError("you must destroy your existing VM to use a custom OVA"))
+					Expect(startCmd.Run(context.Background())).To(MatchError("you must destroy your existing VM to use a custom OVA"))
 				})
 			})
 
@@ -443,7 +555,7 @@ var _ = Describe("StartCmd", func() {
 					startCmd.Opts = startOpts
 					mockVBox.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5}, nil)
 					mockVBox.EXPECT().GetVMName().Return("some-old-vm-name", nil)
-					Expect(startCmd.Run()).To(MatchError("you must destroy your existing VM to use a custom OVA"))
+					Expect(startCmd.Run(context.Background())).To(MatchError("you must destroy your existing VM to use a custom OVA"))
 				})
 			})
 		})
@@ -455,11 +567,11 @@ var _ = Describe("StartCmd", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5}, nil),
 					mockVBox.EXPECT().GetVMName().Return("", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
-					mockVM.EXPECT().Provision(&vm.StartOpts{}),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
+					mockVM.EXPECT().Provision(gomock.Any(), &vm.StartOpts{}),
 				)
 
-				Expect(startCmd.Run()).To(Succeed())
+				Expect(startCmd.Run(context.Background())).To(Succeed())
 			})
 		})
 
@@ -470,11 +582,11 @@ var _ = Describe("StartCmd", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().Version().Return(&vboxdriver.VBoxDriverVersion{Major: 5}, nil),
 					mockVBox.EXPECT().GetVMName().Return("", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
-					mockVM.EXPECT().Provision(&vm.StartOpts{}).Return(errors.New("some-error")),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
+					mockVM.EXPECT().Provision(gomock.Any(), &vm.StartOpts{}).Return(errors.New("some-error")),
 				)
 
-				Expect(startCmd.Run()).To(MatchError("some-error"))
+				Expect(startCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 	})
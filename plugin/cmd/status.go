@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"github.com/cloudfoundry/cli/cf/flags"
 	"github.com/pivotal-cf/pcfdev-cli/config"
 	"github.com/pivotal-cf/pcfdev-cli/vm"
@@ -19,8 +20,8 @@ func (s *StatusCmd) Parse(args []string) error {
 	return parse(flags.New(), args, STATUS_ARGS)
 }
 
-func (s *StatusCmd) Run() error {
-	vm, err := s.getVM()
+func (s *StatusCmd) Run(ctx context.Context) error {
+	vm, err := s.getVM(ctx)
 	if err != nil {
 		return err
 	}
@@ -28,7 +29,7 @@ func (s *StatusCmd) Run() error {
 	return nil
 }
 
-func (s *StatusCmd) getVM() (vm vm.VM, err error) {
+func (s *StatusCmd) getVM(ctx context.Context) (vm vm.VM, err error) {
 	name, err := s.VBox.GetVMName()
 	if err != nil {
 		return nil, err
@@ -40,5 +41,5 @@ func (s *StatusCmd) getVM() (vm vm.VM, err error) {
 		return nil, &OldVMError{}
 	}
 
-	return s.VMBuilder.VM(name)
+	return s.VMBuilder.VM(ctx, name)
 }
@@ -1,6 +1,7 @@
 package cmd_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/golang/mock/gomock"
@@ -65,12 +66,12 @@ var _ = Describe("StatusCmd", func() {
 			It("should return the status", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
 					mockVM.EXPECT().Status().Return("some-status"),
 					mockUI.EXPECT().Say("some-status"),
 				)
 
-				Expect(statusCmd.Run()).To(Succeed())
+				Expect(statusCmd.Run(context.Background())).To(Succeed())
 			})
 		})
 
@@ -78,12 +79,12 @@ var _ = Describe("StatusCmd", func() {
 			It("should return the status", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("pcfdev-custom", nil),
-					mockVMBuilder.EXPECT().VM("pcfdev-custom").Return(mockVM, nil),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "pcfdev-custom").Return(mockVM, nil),
 					mockVM.EXPECT().Status().Return("some-status"),
 					mockUI.EXPECT().Say("some-status"),
 				)
 
-				Expect(statusCmd.Run()).To(Succeed())
+				Expect(statusCmd.Run(context.Background())).To(Succeed())
 			})
 		})
 
@@ -91,12 +92,12 @@ var _ = Describe("StatusCmd", func() {
 			It("should return the status of the default VM", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
 					mockVM.EXPECT().Status().Return("some-status"),
 					mockUI.EXPECT().Say("some-status"),
 				)
 
-				Expect(statusCmd.Run()).To(Succeed())
+				Expect(statusCmd.Run(context.Background())).To(Succeed())
 			})
 		})
 
@@ -104,7 +105,7 @@ var _ = Describe("StatusCmd", func() {
 			It("should tell the user to destroy pcfdev", func() {
 				mockVBox.EXPECT().GetVMName().Return("some-old-vm-name", nil)
 
-				Expect(statusCmd.Run()).To(MatchError("old version of PCF Dev already running, please run `cf dev destroy` to continue"))
+				Expect(statusCmd.Run(context.Background())).To(MatchError("old version of PCF Dev already running, please run `cf dev upgrade` or `cf dev destroy` to continue"))
 			})
 		})
 
@@ -112,7 +113,7 @@ var _ = Describe("StatusCmd", func() {
 			It("should return the error", func() {
 				mockVBox.EXPECT().GetVMName().Return("", errors.New("some-error"))
 
-				Expect(statusCmd.Run()).To(MatchError("some-error"))
+				Expect(statusCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -120,10 +121,10 @@ var _ = Describe("StatusCmd", func() {
 			It("should return an error", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(nil, errors.New("some-error")),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(nil, errors.New("some-error")),
 				)
 
-				Expect(statusCmd.Run()).To(MatchError("some-error"))
+				Expect(statusCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 	})
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"github.com/cloudfoundry/cli/cf/flags"
 	"github.com/pivotal-cf/pcfdev-cli/config"
 	"github.com/pivotal-cf/pcfdev-cli/vm"
@@ -18,15 +19,15 @@ func (s *StopCmd) Parse(args []string) error {
 	return parse(flags.New(), args, STOP_ARGS)
 }
 
-func (s *StopCmd) Run() error {
-	vm, err := s.getVM()
+func (s *StopCmd) Run(ctx context.Context) error {
+	vm, err := s.getVM(ctx)
 	if err != nil {
 		return err
 	}
-	return vm.Stop()
+	return vm.Stop(ctx)
 }
 
-func (s *StopCmd) getVM() (vm vm.VM, err error) {
+func (s *StopCmd) getVM(ctx context.Context) (vm vm.VM, err error) {
 	name, err := s.VBox.GetVMName()
 	if err != nil {
 		return nil, err
@@ -38,5 +39,5 @@ func (s *StopCmd) getVM() (vm vm.VM, err error) {
 		return nil, &OldVMError{}
 	}
 
-	return s.VMBuilder.VM(name)
+	return s.VMBuilder.VM(ctx, name)
 }
@@ -1,6 +1,7 @@
 package cmd_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/golang/mock/gomock"
@@ -60,11 +61,11 @@ var _ = Describe("StopCmd", func() {
 			It("should stop the VM", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
-					mockVM.EXPECT().Stop(),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
+					mockVM.EXPECT().Stop(gomock.Any()),
 				)
 
-				Expect(stopCmd.Run()).To(Succeed())
+				Expect(stopCmd.Run(context.Background())).To(Succeed())
 			})
 		})
 
@@ -72,11 +73,11 @@ var _ = Describe("StopCmd", func() {
 			It("should stop the VM", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("pcfdev-custom", nil),
-					mockVMBuilder.EXPECT().VM("pcfdev-custom").Return(mockVM, nil),
-					mockVM.EXPECT().Stop(),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "pcfdev-custom").Return(mockVM, nil),
+					mockVM.EXPECT().Stop(gomock.Any()),
 				)
 
-				Expect(stopCmd.Run()).To(Succeed())
+				Expect(stopCmd.Run(context.Background())).To(Succeed())
 			})
 		})
 
@@ -84,11 +85,11 @@ var _ = Describe("StopCmd", func() {
 			It("should stop the default VM", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
-					mockVM.EXPECT().Stop(),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
+					mockVM.EXPECT().Stop(gomock.Any()),
 				)
 
-				Expect(stopCmd.Run()).To(Succeed())
+				Expect(stopCmd.Run(context.Background())).To(Succeed())
 			})
 		})
 
@@ -96,7 +97,7 @@ var _ = Describe("StopCmd", func() {
 			It("should tell the user to destroy pcfdev", func() {
 				mockVBox.EXPECT().GetVMName().Return("some-old-vm-name", nil)
 
-				Expect(stopCmd.Run()).To(MatchError("old version of PCF Dev already running, please run `cf dev destroy` to continue"))
+				Expect(stopCmd.Run(context.Background())).To(MatchError("old version of PCF Dev already running, please run `cf dev upgrade` or `cf dev destroy` to continue"))
 			})
 		})
 
@@ -104,7 +105,7 @@ var _ = Describe("StopCmd", func() {
 			It("should return the error", func() {
 				mockVBox.EXPECT().GetVMName().Return("", errors.New("some-error"))
 
-				Expect(stopCmd.Run()).To(MatchError("some-error"))
+				Expect(stopCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -112,10 +113,10 @@ var _ = Describe("StopCmd", func() {
 			It("should return an error", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(nil, errors.New("some-error")),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(nil, errors.New("some-error")),
 				)
 
-				Expect(stopCmd.Run()).To(MatchError("some-error"))
+				Expect(stopCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -123,11 +124,11 @@ var _ = Describe("StopCmd", func() {
 			It("should return an error", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
-					mockVM.EXPECT().Stop().Return(errors.New("some-error")),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
+					mockVM.EXPECT().Stop(gomock.Any()).Return(errors.New("some-error")),
 				)
 
-				Expect(stopCmd.Run()).To(MatchError("some-error"))
+				Expect(stopCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 	})
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"github.com/cloudfoundry/cli/cf/flags"
 	"github.com/pivotal-cf/pcfdev-cli/config"
 	"github.com/pivotal-cf/pcfdev-cli/vm"
@@ -18,15 +19,15 @@ func (s *SuspendCmd) Parse(args []string) error {
 	return parse(flags.New(), args, SUSPEND_ARGS)
 }
 
-func (s *SuspendCmd) Run() error {
-	vm, err := s.getVM()
+func (s *SuspendCmd) Run(ctx context.Context) error {
+	vm, err := s.getVM(ctx)
 	if err != nil {
 		return err
 	}
-	return vm.Suspend()
+	return vm.Suspend(ctx)
 }
 
-func (s *SuspendCmd) getVM() (vm vm.VM, err error) {
+func (s *SuspendCmd) getVM(ctx context.Context) (vm vm.VM, err error) {
 	name, err := s.VBox.GetVMName()
 	if err != nil {
 		return nil, err
@@ -38,5 +39,5 @@ func (s *SuspendCmd) getVM() (vm vm.VM, err error) {
 		return nil, &OldVMError{}
 	}
 
-	return s.VMBuilder.VM(name)
+	return s.VMBuilder.VM(ctx, name)
 }
@@ -1,6 +1,7 @@
 package cmd_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/golang/mock/gomock"
@@ -61,11 +62,11 @@ var _ = Describe("SuspendCmd", func() {
 			It("should suspend the VM", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
-					mockVM.EXPECT().Suspend(),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
+					mockVM.EXPECT().Suspend(gomock.Any()),
 				)
 
-				Expect(suspendCmd.Run()).To(Succeed())
+				Expect(suspendCmd.Run(context.Background())).To(Succeed())
 			})
 		})
 
@@ -73,11 +74,11 @@ var _ = Describe("SuspendCmd", func() {
 			It("should suspend the VM", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("pcfdev-custom", nil),
-					mockVMBuilder.EXPECT().VM("pcfdev-custom").Return(mockVM, nil),
-					mockVM.EXPECT().Suspend(),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "pcfdev-custom").Return(mockVM, nil),
+					mockVM.EXPECT().Suspend(gomock.Any()),
 				)
 
-				Expect(suspendCmd.Run()).To(Succeed())
+				Expect(suspendCmd.Run(context.Background())).To(Succeed())
 			})
 		})
 
@@ -85,11 +86,11 @@ var _ = Describe("SuspendCmd", func() {
 			It("should suspend the default VM", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
-					mockVM.EXPECT().Suspend(),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
+					mockVM.EXPECT().Suspend(gomock.Any()),
 				)
 
-				Expect(suspendCmd.Run()).To(Succeed())
+				Expect(suspendCmd.Run(context.Background())).To(Succeed())
 			})
 		})
 
@@ -97,7 +98,7 @@ var _ = Describe("SuspendCmd", func() {
 			It("should tell the user to destroy pcfdev", func() {
 				mockVBox.EXPECT().GetVMName().Return("some-old-vm-name", nil)
 
-				Expect(suspendCmd.Run()).To(MatchError("old version of PCF Dev already running, please run `cf dev destroy` to continue"))
+				Expect(suspendCmd.Run(context.Background())).To(MatchError("old version of PCF Dev already running, please run `cf dev upgrade` or `cf dev destroy` to continue"))
 			})
 		})
 
@@ -105,7 +106,7 @@ var _ = Describe("SuspendCmd", func() {
 			It("should return the error", func() {
 				mockVBox.EXPECT().GetVMName().Return("", errors.New("some-error"))
 
-				Expect(suspendCmd.Run()).To(MatchError("some-error"))
+				Expect(suspendCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -113,10 +114,10 @@ var _ = Describe("SuspendCmd", func() {
 			It("should return an error", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(nil, errors.New("some-error")),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(nil, errors.New("some-error")),
 				)
 
-				Expect(suspendCmd.Run()).To(MatchError("some-error"))
+				Expect(suspendCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -124,11 +125,11 @@ var _ = Describe("SuspendCmd", func() {
 			It("should return an error", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
-					mockVM.EXPECT().Suspend().Return(errors.New("some-error")),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
+					mockVM.EXPECT().Suspend(gomock.Any()).Return(errors.New("some-error")),
 				)
 
-				Expect(suspendCmd.Run()).To(MatchError("some-error"))
+				Expect(suspendCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 	})
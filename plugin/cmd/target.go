@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"github.com/cloudfoundry/cli/cf/flags"
 	"github.com/pivotal-cf/pcfdev-cli/config"
 	"github.com/pivotal-cf/pcfdev-cli/vm"
@@ -19,15 +20,15 @@ func (t *TargetCmd) Parse(args []string) error {
 	return parse(flags.New(), args, TARGET_ARGS)
 }
 
-func (t *TargetCmd) Run() error {
-	vm, err := t.getVM()
+func (t *TargetCmd) Run(ctx context.Context) error {
+	vm, err := t.getVM(ctx)
 	if err != nil {
 		return err
 	}
 	return vm.Target(t.AutoTarget)
 }
 
-func (t *TargetCmd) getVM() (vm vm.VM, err error) {
+func (t *TargetCmd) getVM(ctx context.Context) (vm vm.VM, err error) {
 	name, err := t.VBox.GetVMName()
 	if err != nil {
 		return nil, err
@@ -39,5 +40,5 @@ func (t *TargetCmd) getVM() (vm vm.VM, err error) {
 		return nil, &OldVMError{}
 	}
 
-	return t.VMBuilder.VM(name)
+	return t.VMBuilder.VM(ctx, name)
 }
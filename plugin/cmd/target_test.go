@@ -1,6 +1,7 @@
 package cmd_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/golang/mock/gomock"
@@ -61,11 +62,11 @@ var _ = Describe("TargetCmd", func() {
 		It("should call Target on the VM", func() {
 			gomock.InOrder(
 				mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
-				mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
+				mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
 				mockVM.EXPECT().Target(false),
 			)
 
-			Expect(targetCmd.Run()).To(Succeed())
+			Expect(targetCmd.Run(context.Background())).To(Succeed())
 		})
 
 		Context("when the VM is automatically targeted", func() {
@@ -73,11 +74,11 @@ var _ = Describe("TargetCmd", func() {
 				targetCmd.AutoTarget = true
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
 					mockVM.EXPECT().Target(true),
 				)
 
-				Expect(targetCmd.Run()).To(Succeed())
+				Expect(targetCmd.Run(context.Background())).To(Succeed())
 			})
 		})
 
@@ -85,7 +86,7 @@ var _ = Describe("TargetCmd", func() {
 			It("should return the error", func() {
 				mockVBox.EXPECT().GetVMName().Return("", errors.New("some-error"))
 
-				Expect(targetCmd.Run()).To(MatchError("some-error"))
+				Expect(targetCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -93,10 +94,10 @@ var _ = Describe("TargetCmd", func() {
 			It("should return the error", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(nil, errors.New("some-error")),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(nil, errors.New("some-error")),
 				)
 
-				Expect(targetCmd.Run()).To(MatchError("some-error"))
+				Expect(targetCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -104,11 +105,11 @@ var _ = Describe("TargetCmd", func() {
 			It("should return the error", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
 					mockVM.EXPECT().Target(false).Return(errors.New("some-error")),
 				)
 
-				Expect(targetCmd.Run()).To(MatchError("some-error"))
+				Expect(targetCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 	})
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"github.com/cloudfoundry/cli/cf/flags"
 	"github.com/pivotal-cf/pcfdev-cli/config"
 	"github.com/pivotal-cf/pcfdev-cli/vm"
@@ -30,15 +31,15 @@ func (t *TrustCmd) Parse(args []string) error {
 	return nil
 }
 
-func (t *TrustCmd) Run() error {
-	vm, err := t.getVM()
+func (t *TrustCmd) Run(ctx context.Context) error {
+	vm, err := t.getVM(ctx)
 	if err != nil {
 		return err
 	}
-	return vm.Trust(t.Opts)
+	return vm.Trust(ctx, t.Opts)
 }
 
-func (t *TrustCmd) getVM() (vm vm.VM, err error) {
+func (t *TrustCmd) getVM(ctx context.Context) (vm vm.VM, err error) {
 	name, err := t.VBox.GetVMName()
 	if err != nil {
 		return nil, err
@@ -50,5 +51,5 @@ func (t *TrustCmd) getVM() (vm vm.VM, err error) {
 		return nil, &OldVMError{}
 	}
 
-	return t.VMBuilder.VM(name)
+	return t.VMBuilder.VM(ctx, name)
 }
@@ -1,6 +1,7 @@
 package cmd_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/golang/mock/gomock"
@@ -77,11 +78,11 @@ var _ = Describe("TrustCmd", func() {
 			trustCmd.Opts = trustOpts
 			gomock.InOrder(
 				mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
-				mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
-				mockVM.EXPECT().Trust(trustOpts),
+				mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
+				mockVM.EXPECT().Trust(gomock.Any(), trustOpts),
 			)
 
-			Expect(trustCmd.Run()).To(Succeed())
+			Expect(trustCmd.Run(context.Background())).To(Succeed())
 		})
 
 		Context("when flags are passed in", func() {
@@ -93,11 +94,11 @@ var _ = Describe("TrustCmd", func() {
 
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
-					mockVM.EXPECT().Trust(trustOpts),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
+					mockVM.EXPECT().Trust(gomock.Any(), trustOpts),
 				)
 
-				Expect(trustCmd.Run()).To(Succeed())
+				Expect(trustCmd.Run(context.Background())).To(Succeed())
 			})
 		})
 
@@ -105,7 +106,7 @@ var _ = Describe("TrustCmd", func() {
 			It("should return the error", func() {
 				mockVBox.EXPECT().GetVMName().Return("", errors.New("some-error"))
 
-				Expect(trustCmd.Run()).To(MatchError("some-error"))
+				Expect(trustCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -113,10 +114,10 @@ var _ = Describe("TrustCmd", func() {
 			It("should return the error", func() {
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(nil, errors.New("some-error")),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(nil, errors.New("some-error")),
 				)
 
-				Expect(trustCmd.Run()).To(MatchError("some-error"))
+				Expect(trustCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 
@@ -126,11 +127,11 @@ var _ = Describe("TrustCmd", func() {
 				trustCmd.Opts = trustOpts
 				gomock.InOrder(
 					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
-					mockVMBuilder.EXPECT().VM("some-default-vm-name").Return(mockVM, nil),
-					mockVM.EXPECT().Trust(trustOpts).Return(errors.New("some-error")),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockVM, nil),
+					mockVM.EXPECT().Trust(gomock.Any(), trustOpts).Return(errors.New("some-error")),
 				)
 
-				Expect(trustCmd.Run()).To(MatchError("some-error"))
+				Expect(trustCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 	})
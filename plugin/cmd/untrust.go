@@ -1,6 +1,9 @@
 package cmd
 
-import "github.com/cloudfoundry/cli/cf/flags"
+import (
+	"context"
+	"github.com/cloudfoundry/cli/cf/flags"
+)
 
 const UNTRUST_ARGS = 0
 
@@ -12,6 +15,6 @@ func (u *UntrustCmd) Parse(args []string) error {
 	return parse(flags.New(), args, UNTRUST_ARGS)
 }
 
-func (u *UntrustCmd) Run() error {
+func (u *UntrustCmd) Run(ctx context.Context) error {
 	return u.CertStore.Unstore()
 }
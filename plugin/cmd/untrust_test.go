@@ -1,6 +1,7 @@
 package cmd_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/golang/mock/gomock"
@@ -51,14 +52,14 @@ var _ = Describe("UntrustCmd", func() {
 		It("should call 'Unstore' on the CertStore", func() {
 			mockCertStore.EXPECT().Unstore()
 
-			Expect(untrustCmd.Run()).To(Succeed())
+			Expect(untrustCmd.Run(context.Background())).To(Succeed())
 		})
 
 		Context("when there is an error running 'Unstore' on the CertStore", func() {
 			It("should return the error", func() {
 				mockCertStore.EXPECT().Unstore().Return(errors.New("some-error"))
 
-				Expect(untrustCmd.Run()).To(MatchError("some-error"))
+				Expect(untrustCmd.Run(context.Background())).To(MatchError("some-error"))
 			})
 		})
 	})
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf/flags"
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/helpers"
+	"github.com/pivotal-cf/pcfdev-cli/vm"
+)
+
+const UPGRADE_ARGS = 0
+
+//go:generate mockgen -package mocks -destination mocks/backup_reader.go github.com/pivotal-cf/pcfdev-cli/plugin/cmd BackupReader
+type BackupReader interface {
+	ReadProvisionConfig(archivePath string) (provisionConfig *config.ProvisionConfig, err error)
+}
+
+type UpgradeCmd struct {
+	VBox         VBox
+	VMBuilder    VMBuilder
+	Config       *config.Config
+	UI           UI
+	FS           FS
+	DownloadCmd  Cmd
+	BackupReader BackupReader
+}
+
+func (u *UpgradeCmd) Parse(args []string) error {
+	return parse(flags.New(), args, UPGRADE_ARGS)
+}
+
+func (u *UpgradeCmd) Run(ctx context.Context) error {
+	oldVMName, err := u.VBox.GetVMName()
+	if err != nil {
+		return err
+	}
+	switch oldVMName {
+	case "":
+		u.UI.Say("No VM created, cannot upgrade PCF Dev.")
+		return nil
+	case u.Config.DefaultVMName:
+		u.UI.Say("PCF Dev is already up to date.")
+		return nil
+	case "pcfdev-custom":
+		return errors.New("a VM created from a custom OVA cannot be upgraded")
+	}
+
+	if err := u.DownloadCmd.Run(ctx); err != nil {
+		return err
+	}
+
+	oldVMConfig, err := u.VBox.VMConfig(oldVMName)
+	if err != nil {
+		return &UpgradeVMError{err}
+	}
+
+	tempDir, err := u.FS.TempDir()
+	if err != nil {
+		return &UpgradeVMError{err}
+	}
+	defer u.FS.Remove(tempDir)
+	archivePath := filepath.Join(tempDir, "pcfdev-backup.tgz")
+
+	if err := u.exportOldVM(ctx, oldVMName, archivePath); err != nil {
+		return &UpgradeVMError{err}
+	}
+
+	provisionConfig, err := u.BackupReader.ReadProvisionConfig(archivePath)
+	if err != nil {
+		return &UpgradeVMError{err}
+	}
+
+	services := provisionConfig.Services
+	if services == "" {
+		services = "none"
+	}
+	opts := &vm.StartOpts{
+		IP:          oldVMConfig.IP,
+		Domain:      oldVMConfig.Domain,
+		Memory:      oldVMConfig.Memory,
+		Services:    services,
+		Registries:  strings.Join(provisionConfig.Registries, ","),
+		NetworkMode: oldVMConfig.NetworkMode,
+	}
+	if oldVMConfig.NetworkMode == config.NetworkModeBridged {
+		opts.IP, opts.Domain = "", ""
+	}
+
+	if err := u.setAsideOldVMFiles(); err != nil {
+		helpers.IgnoreErrorFrom(u.restoreOldVMFiles())
+		return &UpgradeVMError{err}
+	}
+
+	if err := u.importNewVM(ctx, opts, archivePath); err != nil {
+		newVMConfig := &config.VMConfig{Name: u.Config.DefaultVMName}
+		helpers.IgnoreErrorFrom(u.VBox.PowerOffVM(newVMConfig))
+		// The half-imported VM is removed even when ctx is done, so that an
+		// interrupted upgrade leaves only the old VM behind.
+		helpers.IgnoreErrorFrom(u.VBox.DestroyVM(context.Background(), newVMConfig))
+		helpers.IgnoreErrorFrom(u.restoreOldVMFiles())
+		return &UpgradeVMError{err}
+	}
+
+	if err := u.VBox.DestroyVM(ctx, oldVMConfig); err != nil {
+		return &UpgradeVMError{err}
+	}
+	for _, path := range u.oldVMFiles() {
+		helpers.IgnoreErrorFrom(u.FS.Remove(path + ".old"))
+	}
+
+	u.UI.Say("PCF Dev has been upgraded.")
+	return nil
+}
+
+func (u *UpgradeCmd) exportOldVM(ctx context.Context, name string, archivePath string) error {
+	oldVM, err := u.VMBuilder.VM(ctx, name)
+	if err != nil {
+		return err
+	}
+	if err := oldVM.Start(ctx, &vm.StartOpts{}); err != nil {
+		return err
+	}
+
+	oldVM, err = u.VMBuilder.VM(ctx, name)
+	if err != nil {
+		return err
+	}
+	if err := oldVM.Backup(ctx, archivePath); err != nil {
+		return err
+	}
+	if exists, err := u.FS.Exists(archivePath); err != nil {
+		return err
+	} else if !exists {
+		return errors.New("could not export data from the existing VM")
+	}
+
+	return oldVM.Stop(ctx)
+}
+
+func (u *UpgradeCmd) importNewVM(ctx context.Context, opts *vm.StartOpts, archivePath string) error {
+	newVM, err := u.VMBuilder.VM(ctx, u.Config.DefaultVMName)
+	if err != nil {
+		return err
+	}
+	if err := newVM.VerifyStartOpts(opts); err != nil {
+		return err
+	}
+	if err := newVM.Start(ctx, opts); err != nil {
+		return err
+	}
+
+	newVM, err = u.VMBuilder.VM(ctx, u.Config.DefaultVMName)
+	if err != nil {
+		return err
+	}
+	return newVM.Restore(ctx, archivePath)
+}
+
+// oldVMFiles are the files in the VM directory that belong to the old VM. A
+// new secure key is only authorized on the new VM, and its host key trusted,
+// when there is no key yet, and the import of the new VM overwrites vm_config.
+func (u *UpgradeCmd) oldVMFiles() []string {
+	return []string{
+		u.Config.PrivateKeyPath,
+		u.Config.KnownHostsPath,
+		filepath.Join(u.Config.VMDir, "vm_config"),
+	}
+}
+
+// setAsideOldVMFiles moves the old VM's key and known hosts out of the way of
+// the new VM and keeps a copy of its vm_config, all with an .old suffix.
+func (u *UpgradeCmd) setAsideOldVMFiles() error {
+	for _, path := range u.oldVMFiles() {
+		exists, err := u.FS.Exists(path)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+
+		if filepath.Base(path) == "vm_config" {
+			err = u.FS.Copy(path, path+".old")
+		} else {
+			err = u.FS.Move(path, path+".old")
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreOldVMFiles replaces whatever the new VM left in place of the old
+// VM's files with the ones set aside by setAsideOldVMFiles.
+func (u *UpgradeCmd) restoreOldVMFiles() error {
+	for _, path := range u.oldVMFiles() {
+		exists, err := u.FS.Exists(path + ".old")
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+
+		if err := u.FS.Remove(path); err != nil {
+			return err
+		}
+		if err := u.FS.Move(path+".old", path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,374 @@
+package cmd_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/plugin/cmd"
+	"github.com/pivotal-cf/pcfdev-cli/plugin/cmd/mocks"
+	"github.com/pivotal-cf/pcfdev-cli/vm"
+	vmMocks "github.com/pivotal-cf/pcfdev-cli/vm/mocks"
+)
+
+var _ = Describe("UpgradeCmd", func() {
+	var (
+		upgradeCmd       *cmd.UpgradeCmd
+		mockCtrl         *gomock.Controller
+		mockVMBuilder    *mocks.MockVMBuilder
+		mockVBox         *mocks.MockVBox
+		mockUI           *mocks.MockUI
+		mockFS           *mocks.MockFS
+		mockDownloadCmd  *mocks.MockCmd
+		mockBackupReader *mocks.MockBackupReader
+		mockOldVM        *vmMocks.MockVM
+		mockStartedOldVM *vmMocks.MockVM
+		mockNewVM        *vmMocks.MockVM
+		mockStartedNewVM *vmMocks.MockVM
+		oldVMConfig      *config.VMConfig
+		archivePath      string
+		keyPath          string
+		knownHostsPath   string
+		vmConfigPath     string
+		startOpts        *vm.StartOpts
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockVMBuilder = mocks.NewMockVMBuilder(mockCtrl)
+		mockVBox = mocks.NewMockVBox(mockCtrl)
+		mockUI = mocks.NewMockUI(mockCtrl)
+		mockFS = mocks.NewMockFS(mockCtrl)
+		mockDownloadCmd = mocks.NewMockCmd(mockCtrl)
+		mockBackupReader = mocks.NewMockBackupReader(mockCtrl)
+		mockOldVM = vmMocks.NewMockVM(mockCtrl)
+		mockStartedOldVM = vmMocks.NewMockVM(mockCtrl)
+		mockNewVM = vmMocks.NewMockVM(mockCtrl)
+		mockStartedNewVM = vmMocks.NewMockVM(mockCtrl)
+		upgradeCmd = &cmd.UpgradeCmd{
+			VBox:      mockVBox,
+			VMBuilder: mockVMBuilder,
+			Config: &config.Config{
+				DefaultVMName:  "some-default-vm-name",
+				VMDir:          "some-vm-dir",
+				PrivateKeyPath: filepath.Join("some-vm-dir", "key.pem"),
+				KnownHostsPath: filepath.Join("some-vm-dir", "known_hosts"),
+			},
+			UI:           mockUI,
+			FS:           mockFS,
+			DownloadCmd:  mockDownloadCmd,
+			BackupReader: mockBackupReader,
+		}
+		oldVMConfig = &config.VMConfig{
+			Name:   "some-old-vm-name",
+			IP:     "some-ip",
+			Domain: "some-domain",
+			Memory: 4096,
+		}
+		archivePath = filepath.Join("some-temp-dir", "pcfdev-backup.tgz")
+		keyPath = filepath.Join("some-vm-dir", "key.pem")
+		knownHostsPath = filepath.Join("some-vm-dir", "known_hosts")
+		vmConfigPath = filepath.Join("some-vm-dir", "vm_config")
+		startOpts = &vm.StartOpts{
+			IP:         "some-ip",
+			Domain:     "some-domain",
+			Memory:     4096,
+			Services:   "some-services",
+			Registries: "some-registry,some-other-registry",
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("Parse", func() {
+		Context("when the correct number of arguments are passed", func() {
+			It("should succeed", func() {
+				Expect(upgradeCmd.Parse([]string{})).To(Succeed())
+			})
+		})
+		Context("when the wrong number of arguments are passed", func() {
+			It("should fail", func() {
+				Expect(upgradeCmd.Parse([]string{"some-bad-arg"})).NotTo(Succeed())
+			})
+		})
+		Context("when an unknown flag is passed", func() {
+			It("should fail", func() {
+				Expect(upgradeCmd.Parse([]string{"--some-bad-flag"})).NotTo(Succeed())
+			})
+		})
+	})
+
+	Describe("Run", func() {
+		expectExport := func() {
+			gomock.InOrder(
+				mockVBox.EXPECT().GetVMName().Return("some-old-vm-name", nil),
+				mockDownloadCmd.EXPECT().Run(gomock.Any()),
+				mockVBox.EXPECT().VMConfig("some-old-vm-name").Return(oldVMConfig, nil),
+				mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
+				mockVMBuilder.EXPECT().VM(gomock.Any(), "some-old-vm-name").Return(mockOldVM, nil),
+				mockOldVM.EXPECT().Start(gomock.Any(), &vm.StartOpts{}),
+				mockVMBuilder.EXPECT().VM(gomock.Any(), "some-old-vm-name").Return(mockStartedOldVM, nil),
+				mockStartedOldVM.EXPECT().Backup(gomock.Any(), archivePath),
+				mockFS.EXPECT().Exists(archivePath).Return(true, nil),
+				mockStartedOldVM.EXPECT().Stop(gomock.Any()),
+				mockBackupReader.EXPECT().ReadProvisionConfig(archivePath).Return(&config.ProvisionConfig{
+					Services:   "some-services",
+					Registries: []string{"some-registry", "some-other-registry"},
+				}, nil),
+				mockFS.EXPECT().Exists(keyPath).Return(true, nil),
+				mockFS.EXPECT().Move(keyPath, keyPath+".old"),
+				mockFS.EXPECT().Exists(knownHostsPath).Return(true, nil),
+				mockFS.EXPECT().Move(knownHostsPath, knownHostsPath+".old"),
+				mockFS.EXPECT().Exists(vmConfigPath).Return(true, nil),
+				mockFS.EXPECT().Copy(vmConfigPath, vmConfigPath+".old"),
+			)
+		}
+
+		It("should replace the old VM with a new VM holding the same data", func() {
+			expectExport()
+			gomock.InOrder(
+				mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockNewVM, nil),
+				mockNewVM.EXPECT().VerifyStartOpts(startOpts),
+				mockNewVM.EXPECT().Start(gomock.Any(), startOpts),
+				mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockStartedNewVM, nil),
+				mockStartedNewVM.EXPECT().Restore(gomock.Any(), archivePath),
+				mockVBox.EXPECT().DestroyVM(gomock.Any(), oldVMConfig),
+				mockFS.EXPECT().Remove(keyPath+".old"),
+				mockFS.EXPECT().Remove(knownHostsPath+".old"),
+				mockFS.EXPECT().Remove(vmConfigPath+".old"),
+				mockUI.EXPECT().Say("PCF Dev has been upgraded."),
+				mockFS.EXPECT().Remove("some-temp-dir"),
+			)
+
+			Expect(upgradeCmd.Run(context.Background())).To(Succeed())
+		})
+
+		Context("when the old VM had no services", func() {
+			It("should start the new VM without services", func() {
+				gomock.InOrder(
+					mockVBox.EXPECT().GetVMName().Return("some-old-vm-name", nil),
+					mockDownloadCmd.EXPECT().Run(gomock.Any()),
+					mockVBox.EXPECT().VMConfig("some-old-vm-name").Return(oldVMConfig, nil),
+					mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-old-vm-name").Return(mockOldVM, nil),
+					mockOldVM.EXPECT().Start(gomock.Any(), &vm.StartOpts{}),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-old-vm-name").Return(mockStartedOldVM, nil),
+					mockStartedOldVM.EXPECT().Backup(gomock.Any(), archivePath),
+					mockFS.EXPECT().Exists(archivePath).Return(true, nil),
+					mockStartedOldVM.EXPECT().Stop(gomock.Any()),
+					mockBackupReader.EXPECT().ReadProvisionConfig(archivePath).Return(&config.ProvisionConfig{}, nil),
+					mockFS.EXPECT().Exists(keyPath).Return(true, nil),
+					mockFS.EXPECT().Move(keyPath, keyPath+".old"),
+					mockFS.EXPECT().Exists(knownHostsPath).Return(true, nil),
+					mockFS.EXPECT().Move(knownHostsPath, knownHostsPath+".old"),
+					mockFS.EXPECT().Exists(vmConfigPath).Return(true, nil),
+					mockFS.EXPECT().Copy(vmConfigPath, vmConfigPath+".old"),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockNewVM, nil),
+					mockNewVM.EXPECT().VerifyStartOpts(&vm.StartOpts{IP: "some-ip", Domain: "some-domain", Memory: 4096, Services: "none"}),
+					mockNewVM.EXPECT().Start(gomock.Any(), &vm.StartOpts{IP: "some-ip", Domain: "some-domain", Memory: 4096, Services: "none"}),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockStartedNewVM, nil),
+					mockStartedNewVM.EXPECT().Restore(gomock.Any(), archivePath),
+					mockVBox.EXPECT().DestroyVM(gomock.Any(), oldVMConfig),
+					mockFS.EXPECT().Remove(keyPath+".old"),
+					mockFS.EXPECT().Remove(knownHostsPath+".old"),
+					mockFS.EXPECT().Remove(vmConfigPath+".old"),
+					mockUI.EXPECT().Say("PCF Dev has been upgraded."),
+					mockFS.EXPECT().Remove("some-temp-dir"),
+				)
+
+				Expect(upgradeCmd.Run(context.Background())).To(Succeed())
+			})
+		})
+
+		Context("when there is no VM", func() {
+			It("should say a message", func() {
+				gomock.InOrder(
+					mockVBox.EXPECT().GetVMName().Return("", nil),
+					mockUI.EXPECT().Say("No VM created, cannot upgrade PCF Dev."),
+				)
+
+				Expect(upgradeCmd.Run(context.Background())).To(Succeed())
+			})
+		})
+
+		Context("when the VM is already up to date", func() {
+			It("should say a message", func() {
+				gomock.InOrder(
+					mockVBox.EXPECT().GetVMName().Return("some-default-vm-name", nil),
+					mockUI.EXPECT().Say("PCF Dev is already up to date."),
+				)
+
+				Expect(upgradeCmd.Run(context.Background())).To(Succeed())
+			})
+		})
+
+		Context("when the VM was created from a custom OVA", func() {
+			It("should return an error", func() {
+				mockVBox.EXPECT().GetVMName().Return("pcfdev-custom", nil)
+
+				Expect(upgradeCmd.Run(context.Background())).To(MatchError("a VM created from a custom OVA cannot be upgraded"))
+			})
+		})
+
+		Context("when getting the VM name fails", func() {
+			It("should return an error", func() {
+				mockVBox.EXPECT().GetVMName().Return("", errors.New("some-error"))
+
+				Expect(upgradeCmd.Run(context.Background())).To(MatchError("some-error"))
+			})
+		})
+
+		Context("when downloading the new OVA fails", func() {
+			It("should return an error", func() {
+				gomock.InOrder(
+					mockVBox.EXPECT().GetVMName().Return("some-old-vm-name", nil),
+					mockDownloadCmd.EXPECT().Run(gomock.Any()).Return(errors.New("some-error")),
+				)
+
+				Expect(upgradeCmd.Run(context.Background())).To(MatchError("some-error"))
+			})
+		})
+
+		Context("when backing up the old VM does not produce an archive", func() {
+			It("should return an error without touching the old VM", func() {
+				gomock.InOrder(
+					mockVBox.EXPECT().GetVMName().Return("some-old-vm-name", nil),
+					mockDownloadCmd.EXPECT().Run(gomock.Any()),
+					mockVBox.EXPECT().VMConfig("some-old-vm-name").Return(oldVMConfig, nil),
+					mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-old-vm-name").Return(mockOldVM, nil),
+					mockOldVM.EXPECT().Start(gomock.Any(), &vm.StartOpts{}),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-old-vm-name").Return(mockStartedOldVM, nil),
+					mockStartedOldVM.EXPECT().Backup(gomock.Any(), archivePath),
+					mockFS.EXPECT().Exists(archivePath).Return(false, nil),
+					mockFS.EXPECT().Remove("some-temp-dir"),
+				)
+
+				Expect(upgradeCmd.Run(context.Background())).To(MatchError("failed to upgrade VM: could not export data from the existing VM"))
+			})
+		})
+
+		Context("when backing up the old VM fails", func() {
+			It("should return an error", func() {
+				gomock.InOrder(
+					mockVBox.EXPECT().GetVMName().Return("some-old-vm-name", nil),
+					mockDownloadCmd.EXPECT().Run(gomock.Any()),
+					mockVBox.EXPECT().VMConfig("some-old-vm-name").Return(oldVMConfig, nil),
+					mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-old-vm-name").Return(mockOldVM, nil),
+					mockOldVM.EXPECT().Start(gomock.Any(), &vm.StartOpts{}),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-old-vm-name").Return(mockStartedOldVM, nil),
+					mockStartedOldVM.EXPECT().Backup(gomock.Any(), archivePath).Return(errors.New("some-error")),
+					mockFS.EXPECT().Remove("some-temp-dir"),
+				)
+
+				Expect(upgradeCmd.Run(context.Background())).To(MatchError("failed to upgrade VM: some-error"))
+			})
+		})
+
+		Context("when setting the old VM's files aside fails", func() {
+			It("should put back the files already set aside", func() {
+				gomock.InOrder(
+					mockVBox.EXPECT().GetVMName().Return("some-old-vm-name", nil),
+					mockDownloadCmd.EXPECT().Run(gomock.Any()),
+					mockVBox.EXPECT().VMConfig("some-old-vm-name").Return(oldVMConfig, nil),
+					mockFS.EXPECT().TempDir().Return("some-temp-dir", nil),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-old-vm-name").Return(mockOldVM, nil),
+					mockOldVM.EXPECT().Start(gomock.Any(), &vm.StartOpts{}),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-old-vm-name").Return(mockStartedOldVM, nil),
+					mockStartedOldVM.EXPECT().Backup(gomock.Any(), archivePath),
+					mockFS.EXPECT().Exists(archivePath).Return(true, nil),
+					mockStartedOldVM.EXPECT().Stop(gomock.Any()),
+					mockBackupReader.EXPECT().ReadProvisionConfig(archivePath).Return(&config.ProvisionConfig{}, nil),
+					mockFS.EXPECT().Exists(keyPath).Return(true, nil),
+					mockFS.EXPECT().Move(keyPath, keyPath+".old"),
+					mockFS.EXPECT().Exists(knownHostsPath).Return(true, nil),
+					mockFS.EXPECT().Move(knownHostsPath, knownHostsPath+".old").Return(errors.New("some-error")),
+					mockFS.EXPECT().Exists(keyPath+".old").Return(true, nil),
+					mockFS.EXPECT().Remove(keyPath),
+					mockFS.EXPECT().Move(keyPath+".old", keyPath),
+					mockFS.EXPECT().Exists(knownHostsPath+".old").Return(false, nil),
+					mockFS.EXPECT().Exists(vmConfigPath+".old").Return(false, nil),
+					mockFS.EXPECT().Remove("some-temp-dir"),
+				)
+
+				Expect(upgradeCmd.Run(context.Background())).To(MatchError("failed to upgrade VM: some-error"))
+			})
+		})
+
+		Context("when starting the new VM fails", func() {
+			It("should remove the new VM and keep the old VM with its key, known hosts and vm_config", func() {
+				expectExport()
+				gomock.InOrder(
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockNewVM, nil),
+					mockNewVM.EXPECT().VerifyStartOpts(startOpts),
+					mockNewVM.EXPECT().Start(gomock.Any(), startOpts).Return(errors.New("some-error")),
+					mockVBox.EXPECT().PowerOffVM(&config.VMConfig{Name: "some-default-vm-name"}),
+					mockVBox.EXPECT().DestroyVM(gomock.Any(), &config.VMConfig{Name: "some-default-vm-name"}),
+					mockFS.EXPECT().Exists(keyPath+".old").Return(true, nil),
+					mockFS.EXPECT().Remove(keyPath),
+					mockFS.EXPECT().Move(keyPath+".old", keyPath),
+					mockFS.EXPECT().Exists(knownHostsPath+".old").Return(true, nil),
+					mockFS.EXPECT().Remove(knownHostsPath),
+					mockFS.EXPECT().Move(knownHostsPath+".old", knownHostsPath),
+					mockFS.EXPECT().Exists(vmConfigPath+".old").Return(true, nil),
+					mockFS.EXPECT().Remove(vmConfigPath),
+					mockFS.EXPECT().Move(vmConfigPath+".old", vmConfigPath),
+					mockFS.EXPECT().Remove("some-temp-dir"),
+				)
+
+				Expect(upgradeCmd.Run(context.Background())).To(MatchError("failed to upgrade VM: some-error"))
+			})
+		})
+
+		Context("when restoring data into the new VM fails", func() {
+			It("should remove the new VM and keep the old VM", func() {
+				expectExport()
+				gomock.InOrder(
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockNewVM, nil),
+					mockNewVM.EXPECT().VerifyStartOpts(startOpts),
+					mockNewVM.EXPECT().Start(gomock.Any(), startOpts),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockStartedNewVM, nil),
+					mockStartedNewVM.EXPECT().Restore(gomock.Any(), archivePath).Return(errors.New("some-error")),
+					mockVBox.EXPECT().PowerOffVM(&config.VMConfig{Name: "some-default-vm-name"}),
+					mockVBox.EXPECT().DestroyVM(gomock.Any(), &config.VMConfig{Name: "some-default-vm-name"}),
+					mockFS.EXPECT().Exists(keyPath+".old").Return(true, nil),
+					mockFS.EXPECT().Remove(keyPath),
+					mockFS.EXPECT().Move(keyPath+".old", keyPath),
+					mockFS.EXPECT().Exists(knownHostsPath+".old").Return(true, nil),
+					mockFS.EXPECT().Remove(knownHostsPath),
+					mockFS.EXPECT().Move(knownHostsPath+".old", knownHostsPath),
+					mockFS.EXPECT().Exists(vmConfigPath+".old").Return(true, nil),
+					mockFS.EXPECT().Remove(vmConfigPath),
+					mockFS.EXPECT().Move(vmConfigPath+".old", vmConfigPath),
+					mockFS.EXPECT().Remove("some-temp-dir"),
+				)
+
+				Expect(upgradeCmd.Run(context.Background())).To(MatchError("failed to upgrade VM: some-error"))
+			})
+		})
+
+		Context("when removing the old VM fails", func() {
+			It("should return an error and keep the old VM's files", func() {
+				expectExport()
+				gomock.InOrder(
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockNewVM, nil),
+					mockNewVM.EXPECT().VerifyStartOpts(startOpts),
+					mockNewVM.EXPECT().Start(gomock.Any(), startOpts),
+					mockVMBuilder.EXPECT().VM(gomock.Any(), "some-default-vm-name").Return(mockStartedNewVM, nil),
+					mockStartedNewVM.EXPECT().Restore(gomock.Any(), archivePath),
+					mockVBox.EXPECT().DestroyVM(gomock.Any(), oldVMConfig).Return(errors.New("some-error")),
+					mockFS.EXPECT().Remove("some-temp-dir"),
+				)
+
+				Expect(upgradeCmd.Run(context.Background())).To(MatchError("failed to upgrade VM: some-error"))
+			})
+		})
+	})
+})
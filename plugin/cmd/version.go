@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/cloudfoundry/cli/cf/flags"
@@ -18,7 +19,7 @@ func (v *VersionCmd) Parse(args []string) error {
 	return parse(flags.New(), args, VERSION_ARGS)
 }
 
-func (v *VersionCmd) Run() error {
+func (v *VersionCmd) Run(ctx context.Context) error {
 	v.UI.Say(fmt.Sprintf("PCF Dev version %s (CLI: %s, OVA: %s)",
 		v.Config.Version.BuildVersion,
 		v.Config.Version.BuildSHA,
@@ -1,6 +1,7 @@
 package cmd_test
 
 import (
+	"context"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -53,7 +54,7 @@ var _ = Describe("VersionCmd", func() {
 	Describe("Run", func() {
 		It("should print out the versions", func() {
 			mockUI.EXPECT().Say("PCF Dev version some-build-version (CLI: some-sha, OVA: some-ova-version)")
-			Expect(versionCmd.Run()).To(Succeed())
+			Expect(versionCmd.Run(context.Background())).To(Succeed())
 		})
 	})
 })
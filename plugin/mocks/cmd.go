@@ -4,6 +4,7 @@
 package mocks
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -38,12 +39,12 @@ func (_mr *_MockCmdRecorder) Parse(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Parse", arg0)
 }
 
-func (_m *MockCmd) Run() error {
-	ret := _m.ctrl.Call(_m, "Run")
+func (_m *MockCmd) Run(_param0 context.Context) error {
+	ret := _m.ctrl.Call(_m, "Run", _param0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockCmdRecorder) Run() *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "Run")
+func (_mr *_MockCmdRecorder) Run(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Run", arg0)
 }
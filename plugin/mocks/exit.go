@@ -35,3 +35,11 @@ func (_m *MockExit) Exit() {
 func (_mr *_MockExitRecorder) Exit() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Exit")
 }
+
+func (_m *MockExit) ExitWithStatus(_param0 int) {
+	_m.ctrl.Call(_m, "ExitWithStatus", _param0)
+}
+
+func (_mr *_MockExitRecorder) ExitWithStatus(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ExitWithStatus", arg0)
+}
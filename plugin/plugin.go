@@ -1,22 +1,32 @@
 package plugin
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	cfplugin "github.com/cloudfoundry/cli/plugin"
 	"github.com/pivotal-cf/pcfdev-cli/config"
 	"github.com/pivotal-cf/pcfdev-cli/plugin/cmd"
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
 )
 
 type Plugin struct {
-	UI         UI
-	CmdBuilder CmdBuilder
-	Exit       Exit
-	Config     *config.Config
+	UI               UI
+	CmdBuilder       CmdBuilder
+	DryRunCmdBuilder CmdBuilder
+	Exit             Exit
+	Config           *config.Config
 }
 
+var dryRunSubcommands = []string{"start", "destroy", "import"}
+
 //go:generate mockgen -package mocks -destination mocks/ui.go github.com/pivotal-cf/pcfdev-cli/plugin UI
 type UI interface {
 	Failed(message string, args ...interface{})
@@ -32,6 +42,7 @@ type CmdBuilder interface {
 //go:generate mockgen -package mocks -destination mocks/exit.go github.com/pivotal-cf/pcfdev-cli/plugin Exit
 type Exit interface {
 	Exit()
+	ExitWithStatus(status int)
 }
 
 //go:generate mockgen -package mocks -destination mocks/cmd.go github.com/pivotal-cf/pcfdev-cli/plugin/cmd Cmd
@@ -44,12 +55,29 @@ func (p *Plugin) Run(cliConnection cfplugin.CliConnection, args []string) {
 	var subcommand string
 	var cmdArgs []string
 
+	args, dryRun := extractDryRunFlag(args)
+	args, timeout, err := extractTimeoutFlag(args)
+	if err != nil {
+		p.UI.Failed(getErrorText(err))
+		p.Exit.Exit()
+		return
+	}
 	if len(args) > 1 {
 		subcommand = args[1]
 		cmdArgs = args[2:]
 	}
 
-	cmd, err := p.CmdBuilder.Cmd(subcommand)
+	cmdBuilder := p.CmdBuilder
+	if dryRun {
+		if !isDryRunSubcommand(subcommand) {
+			p.UI.Failed(getErrorText(fmt.Errorf("--dry-run is only supported for %s", strings.Join(dryRunSubcommands, ", "))))
+			p.Exit.Exit()
+			return
+		}
+		cmdBuilder = p.DryRunCmdBuilder
+	}
+
+	cmd, err := cmdBuilder.Cmd(subcommand)
 	if err != nil {
 		p.showUsageMessage(cliConnection)
 		return
@@ -58,10 +86,106 @@ func (p *Plugin) Run(cliConnection cfplugin.CliConnection, args []string) {
 		p.showUsageMessage(cliConnection)
 		return
 	}
-	if err := cmd.Run(); err != nil {
+
+	ctx, cancel := newContext(timeout)
+	defer cancel()
+	if err := cmd.Run(ctx); err != nil {
+		// The command run by 'cf dev ssh' has printed its own errors, so only
+		// its exit status is passed on, as ssh does.
+		if exitErr, ok := err.(*ssh.ExitError); ok && ctx.Err() == nil {
+			p.Exit.ExitWithStatus(exitErr.Status)
+			return
+		}
+		switch ctx.Err() {
+		case context.Canceled:
+			err = errors.New("interrupted, run 'cf dev status' to see the state of PCF Dev")
+		case context.DeadlineExceeded:
+			err = fmt.Errorf("timed out after %s, run 'cf dev status' to see the state of PCF Dev", timeout)
+		}
 		p.UI.Failed(getErrorText(err))
 		p.Exit.Exit()
+		return
+	}
+	if dryRun {
+		p.UI.Say("Dry run complete. No changes were made.")
+	}
+}
+
+// extractDryRunFlag leaves the arguments after "--" alone, as they belong to
+// the command run on the VM.
+func extractDryRunFlag(args []string) (remainingArgs []string, dryRun bool) {
+	for i, arg := range args {
+		if arg == "--" {
+			return append(remainingArgs, args[i:]...), dryRun
+		}
+		if arg == "--dry-run" {
+			dryRun = true
+		} else {
+			remainingArgs = append(remainingArgs, arg)
+		}
 	}
+	return remainingArgs, dryRun
+}
+
+// extractTimeoutFlag, like extractDryRunFlag, leaves the arguments after "--"
+// alone.
+func extractTimeoutFlag(args []string) (remainingArgs []string, timeout time.Duration, err error) {
+	for i := 0; i < len(args); i++ {
+		var value string
+		switch {
+		case args[i] == "--":
+			return append(remainingArgs, args[i:]...), timeout, nil
+		case args[i] == "--timeout" && i+1 < len(args):
+			i++
+			value = args[i]
+		case strings.HasPrefix(args[i], "--timeout="):
+			value = strings.TrimPrefix(args[i], "--timeout=")
+		default:
+			remainingArgs = append(remainingArgs, args[i])
+			continue
+		}
+		if timeout, err = time.ParseDuration(value); err != nil || timeout <= 0 {
+			return nil, 0, fmt.Errorf("--timeout must be a positive duration such as 30m, not '%s'", value)
+		}
+	}
+	return remainingArgs, timeout, nil
+}
+
+// newContext returns a context that is cancelled on the first interrupt or
+// terminate signal, and after timeout when it is set. Once the first signal
+// has been caught, a second one kills the process as usual.
+func newContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancelSignals := context.WithCancel(context.Background())
+	cancel := cancelSignals
+	if timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, timeout)
+		cancel = func() {
+			cancelTimeout()
+			cancelSignals()
+		}
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(signals)
+	}()
+	return ctx, cancel
+}
+
+func isDryRunSubcommand(subcommand string) bool {
+	for _, dryRunSubcommand := range dryRunSubcommands {
+		if subcommand == dryRunSubcommand {
+			return true
+		}
+	}
+	return false
 }
 
 func (p *Plugin) showUsageMessage(cliConnection cfplugin.CliConnection) {
@@ -109,6 +233,12 @@ func (p *Plugin) GetMetadata() cfplugin.PluginMetadata {
 				UsageDetails: cfplugin.Usage{
 					Usage: `cf dev SUBCOMMAND
 
+GLOBAL OPTIONS:
+   [--dry-run]                       Print the VBoxManage and guest commands that start, destroy or import
+                                        would run, without running them.
+   [--timeout duration]              Give up on the subcommand after this long, for example 30m. Ctrl-C also
+                                        stops it. Either way, 'cf dev status' shows the state of the VM.
+
 SUBCOMMANDS:
    start                             Start the PCF Dev VM. When creating a VM, http proxy env vars are respected.
       [-c number-of-cores]           Number of processor cores used by VM. Default: number of physical cores.
@@ -116,24 +246,88 @@ SUBCOMMANDS:
       [-i ip-address]                Specify the IP Address that the PCF Dev VM will occupy.
       [-k]                           Import VM certificates into host's trusted certificate store.
       [-m memory-in-mb]              Memory to allocate for VM. Default: half of total memory, max 4 GB, max 8 GB with SCS.
+      [--network-mode mode]          How the VM is networked when it is created.
+                                        Options: hostonly (reachable from this host only), bridged (takes an
+                                        address on the LAN), natnetwork (shares a NAT network with other VMs
+                                        and is reached through SSH, HTTP and HTTPS forwarded from 127.0.0.1)
+                                        Default: hostonly
       [-r registry1,registry2,...]   Docker registries that PCF Dev will use without SSL validation. Specify in 'host:port' format.
       [-s service1,service2]         Specify the services started with PCF Dev.
                                         Options: redis, rabbitmq, spring-cloud-services (scs), default, all, none
                                         Default: redis, rabbitmq
                                         (MySQL is always available and cannot be disabled.)
       [-t]                           Perform a CF login to PCF Dev after starting, as the 'user' user.
+      [--verify]                     With -o path/to/custom.ova, check the OVA against its manifest, custom.ova.manifest,
+                                        and the manifest against its Ed25519 signature, custom.ova.manifest.sig.
    stop                              Shutdown the PCF Dev VM. All data is preserved.
    suspend                           Save the current state of the PCF Dev VM to disk and then stop the VM.
    resume                            Resume PCF Dev VM from suspended state.
    destroy                           Delete the PCF Dev VM. All data is destroyed.
    status                            Query for the status of the PCF Dev VM.
    import /path/to/ova               Import OVA from local filesystem.
-   ssh                               Start an SSH session into a running PCF Dev VM.
+   backup /path/to/archive           Back up the CF databases, blobstore and services of a running PCF Dev VM.
+   restore /path/to/archive          Restore a backup archive into a running PCF Dev VM.
+   upgrade                           Replace a VM from an older version of PCF Dev, keeping its data and settings.
+   ssh [-- command]                  Start an SSH session into a running PCF Dev VM, or run a command in it.
+      [-t]                           Use a terminal for the command, as a shell always does.
+      [-A]                           Forward the SSH agent of this host to the VM.
+      [-L [bind:]port:host:hostport] Forward a port on this host to host:hostport from the VM. Can be given more than once.
+      [-R [bind:]port:host:hostport] Forward a port on the VM to host:hostport from this host. Can be given more than once.
+   ssh-key rotate                    Replace the key used to SSH into a running PCF Dev VM with a new one.
+      [--type key-type]              Options: rsa, ed25519. Default: the type of the current key.
+   socks                             Run a SOCKS5 proxy on this host that connects from inside the PCF Dev VM,
+                                        for when the VM cannot be reached over its host-only network.
+      [--port port]                  Port to listen on, on 127.0.0.1. Default: 1080
+   dns                               Answer DNS queries for *.<VM domain> with the VM IP until interrupted,
+                                        so that PCF Dev works offline and behind DNS-rebinding protection.
+      [-a address]                   Address to listen on. Default: 127.0.0.1:5354
+      [-d domain]                    Also answer for this domain. Can be given more than once.
+      [--resolved]                   Print how to route the domains to this server with systemd-resolved.
+   hosts sync                        Write hosts file entries for the PCF Dev system components and every app
+                                        route, for workstations that cannot resolve the PCF Dev domain.
+      [--watch]                      Keep checking for new routes until interrupted.
+   hosts remove                      Remove the PCF Dev entries from the hosts file.
+   proxy set                         Save proxy settings to use instead of the host's proxy env vars, and apply
+                                        them to a running PCF Dev VM by restarting the services that use them.
+      [--http-proxy url]             HTTP proxy for the VM.
+      [--https-proxy url]            HTTPS proxy for the VM.
+      [--no-proxy host1,host2,...]   Hosts that the VM reaches without a proxy.
+      [--pac url-or-path]            PAC file that chooses the proxies for the VM and the OVA download. The VM has
+                                        one proxy for each scheme: the one that the PAC file chooses for
+                                        http://github.com or https://github.com. PAC files must be ES5 JavaScript.
+   proxy unset                       Go back to the host's proxy env vars, and apply them to a running VM.
+   proxy show                        Show the proxy settings of the PCF Dev VM, including the full NO_PROXY list.
    target                            Perform a CF login to PCF Dev, as the 'user' user.
    trust                             Import VM certificates into host's trusted certificate store.
       [-p]                           Print the PCF Dev Root CA Certificate to stdout.
    untrust                           Remove VM certificates from host's trusted certificate store.
-   version                           Display the release version of the CLI.`,
+   version                           Display the release version of the CLI.
+
+ENVIRONMENT:
+   PCFDEV_TRACE=path/to/trace.log    Append every VBoxManage, SSH and HTTP call to a log file, with secrets
+                                        redacted. Set to true to print the trace to stdout.
+   PCFDEV_SSH_TIMEOUT=5m             How long to wait for SSH to the VM.
+   PCFDEV_PROVISION_TIMEOUT=5m       How long to wait for SSH to the VM to start provisioning it.
+   PCFDEV_PROVISION_RUN_TIMEOUT=1h   How long provisioning the VM may take in all.
+   PCFDEV_HEALTH_CHECK_TIMEOUT=30s   How long to wait to remove the health check before provisioning again.
+   PCFDEV_DEBUG_LOG_TIMEOUT=20s      How long 'cf dev debug' waits for each log.
+   PCFDEV_API_TUNNEL_TIMEOUT=1m      How long to wait for the tunnel to the PCF Dev API on the VM.
+   PCFDEV_OVA_SOURCE=url-or-path     Download the OVA from an http(s) mirror, an s3://bucket/key or a file
+                                        path, instead of Pivotal Network. S3 requests are signed with the
+                                        AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_REGION env vars.
+                                        When the source has <ova>.manifest and <ova>.manifest.sig next to the
+                                        OVA, the downloaded OVA is checked against them as with --verify, and
+                                        the manifest must be for the OVA version that this plugin expects.
+   PCFDEV_S3_ENDPOINT=host:port      The endpoint of an S3-compatible store, instead of AWS.
+
+CONFIGURATION ($PCFDEV_HOME/config.json, by default ~/.pcfdev/config.json):
+   "subnet_pool": "10.254.0.0/16"    Pick the subnet of a new VM from the /24s in this pool, instead of
+                                        192.168.11.0/24 to 192.168.99.0/24.
+   "ova_source": "url-or-path"       Defaults for PCFDEV_OVA_SOURCE and PCFDEV_S3_ENDPOINT. The environment
+   "s3_endpoint": "host:port"           variables take precedence.
+   "timeouts": {"ssh": "10m"}        Defaults for the timeouts above, by the name after PCFDEV_, in lower case
+                                        and without _TIMEOUT: ssh, provision, provision_run, health_check,
+                                        debug_log, api_tunnel. The environment variables take precedence.`,
 				},
 			},
 		},
@@ -1,13 +1,16 @@
 package plugin_test
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	cfplugin "github.com/cloudfoundry/cli/plugin"
 	"github.com/cloudfoundry/cli/plugin/pluginfakes"
 	"github.com/pivotal-cf/pcfdev-cli/config"
 	"github.com/pivotal-cf/pcfdev-cli/plugin"
 	"github.com/pivotal-cf/pcfdev-cli/plugin/mocks"
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
 	"github.com/pivotal-cf/pcfdev-cli/user"
 
 	"github.com/golang/mock/gomock"
@@ -20,6 +23,7 @@ var _ = Describe("Plugin", func() {
 		mockCtrl          *gomock.Controller
 		mockUI            *mocks.MockUI
 		mockCmdBuilder    *mocks.MockCmdBuilder
+		mockDryRunBuilder *mocks.MockCmdBuilder
 		mockCmd           *mocks.MockCmd
 		mockExit          *mocks.MockExit
 		fakeCliConnection *pluginfakes.FakeCliConnection
@@ -30,13 +34,15 @@ var _ = Describe("Plugin", func() {
 		mockCtrl = gomock.NewController(GinkgoT())
 		mockUI = mocks.NewMockUI(mockCtrl)
 		mockCmdBuilder = mocks.NewMockCmdBuilder(mockCtrl)
+		mockDryRunBuilder = mocks.NewMockCmdBuilder(mockCtrl)
 		mockCmd = mocks.NewMockCmd(mockCtrl)
 		mockExit = mocks.NewMockExit(mockCtrl)
 		fakeCliConnection = &pluginfakes.FakeCliConnection{}
 		pcfdev = &plugin.Plugin{
-			UI:         mockUI,
-			CmdBuilder: mockCmdBuilder,
-			Exit:       mockExit,
+			UI:               mockUI,
+			CmdBuilder:       mockCmdBuilder,
+			DryRunCmdBuilder: mockDryRunBuilder,
+			Exit:             mockExit,
 		}
 	})
 
@@ -58,7 +64,7 @@ var _ = Describe("Plugin", func() {
 				gomock.InOrder(
 					mockCmdBuilder.EXPECT().Cmd("some-command").Return(mockCmd, nil),
 					mockCmd.EXPECT().Parse([]string{"some-arg"}),
-					mockCmd.EXPECT().Run(),
+					mockCmd.EXPECT().Run(gomock.Any()),
 				)
 
 				pcfdev.Run(fakeCliConnection, []string{"dev", "some-command", "some-arg"})
@@ -84,7 +90,7 @@ var _ = Describe("Plugin", func() {
 				gomock.InOrder(
 					mockCmdBuilder.EXPECT().Cmd("some-command").Return(mockCmd, nil),
 					mockCmd.EXPECT().Parse([]string{}),
-					mockCmd.EXPECT().Run().Return(errors.New("some-error")),
+					mockCmd.EXPECT().Run(gomock.Any()).Return(errors.New("some-error")),
 					mockUI.EXPECT().Failed("Error: some-error."),
 					mockExit.EXPECT().Exit(),
 				)
@@ -114,6 +120,138 @@ var _ = Describe("Plugin", func() {
 			})
 		})
 
+		Context("when it is called with --dry-run", func() {
+			It("should run the subcommand from the dry run builder", func() {
+				gomock.InOrder(
+					mockDryRunBuilder.EXPECT().Cmd("start").Return(mockCmd, nil),
+					mockCmd.EXPECT().Parse([]string{"-m", "4096"}),
+					mockCmd.EXPECT().Run(gomock.Any()),
+					mockUI.EXPECT().Say("Dry run complete. No changes were made."),
+				)
+
+				pcfdev.Run(fakeCliConnection, []string{"dev", "start", "--dry-run", "-m", "4096"})
+			})
+
+			It("should accept the flag before the subcommand", func() {
+				gomock.InOrder(
+					mockDryRunBuilder.EXPECT().Cmd("destroy").Return(mockCmd, nil),
+					mockCmd.EXPECT().Parse([]string{}),
+					mockCmd.EXPECT().Run(gomock.Any()),
+					mockUI.EXPECT().Say("Dry run complete. No changes were made."),
+				)
+
+				pcfdev.Run(fakeCliConnection, []string{"dev", "--dry-run", "destroy"})
+			})
+
+			Context("when the flag comes after --", func() {
+				It("should pass it on to the subcommand", func() {
+					gomock.InOrder(
+						mockCmdBuilder.EXPECT().Cmd("ssh").Return(mockCmd, nil),
+						mockCmd.EXPECT().Parse([]string{"--", "some-command", "--dry-run"}),
+						mockCmd.EXPECT().Run(gomock.Any()),
+					)
+
+					pcfdev.Run(fakeCliConnection, []string{"dev", "ssh", "--", "some-command", "--dry-run"})
+				})
+			})
+
+			Context("when the subcommand does not support dry runs", func() {
+				It("should print an error", func() {
+					gomock.InOrder(
+						mockUI.EXPECT().Failed("Error: --dry-run is only supported for start, destroy, import."),
+						mockExit.EXPECT().Exit(),
+					)
+
+					pcfdev.Run(fakeCliConnection, []string{"dev", "stop", "--dry-run"})
+				})
+			})
+
+			Context("when the subcommand fails", func() {
+				It("should print the error", func() {
+					gomock.InOrder(
+						mockDryRunBuilder.EXPECT().Cmd("import").Return(mockCmd, nil),
+						mockCmd.EXPECT().Parse([]string{"some-ova"}),
+						mockCmd.EXPECT().Run(gomock.Any()).Return(errors.New("some-error")),
+						mockUI.EXPECT().Failed("Error: some-error."),
+						mockExit.EXPECT().Exit(),
+					)
+
+					pcfdev.Run(fakeCliConnection, []string{"dev", "import", "some-ova", "--dry-run"})
+				})
+			})
+		})
+
+		Context("when it is called with --timeout", func() {
+			It("should run the subcommand with a deadline", func() {
+				gomock.InOrder(
+					mockCmdBuilder.EXPECT().Cmd("start").Return(mockCmd, nil),
+					mockCmd.EXPECT().Parse([]string{"-m", "4096"}),
+					mockCmd.EXPECT().Run(gomock.Any()).Do(func(ctx context.Context) {
+						deadline, ok := ctx.Deadline()
+						Expect(ok).To(BeTrue())
+						Expect(deadline).To(BeTemporally("~", time.Now().Add(30*time.Minute), time.Minute))
+					}),
+				)
+
+				pcfdev.Run(fakeCliConnection, []string{"dev", "--timeout", "30m", "start", "-m", "4096"})
+			})
+
+			Context("when the subcommand runs past the timeout", func() {
+				It("should say that it timed out", func() {
+					gomock.InOrder(
+						mockCmdBuilder.EXPECT().Cmd("start").Return(mockCmd, nil),
+						mockCmd.EXPECT().Parse([]string{}),
+						mockCmd.EXPECT().Run(gomock.Any()).Do(func(ctx context.Context) {
+							<-ctx.Done()
+						}).Return(errors.New("some-error")),
+						mockUI.EXPECT().Failed("Error: timed out after 10ms, run 'cf dev status' to see the state of PCF Dev."),
+						mockExit.EXPECT().Exit(),
+					)
+
+					pcfdev.Run(fakeCliConnection, []string{"dev", "start", "--timeout=10ms"})
+				})
+			})
+
+			Context("when the timeout is not a duration", func() {
+				It("should print an error", func() {
+					gomock.InOrder(
+						mockUI.EXPECT().Failed("Error: --timeout must be a positive duration such as 30m, not 'some-timeout'."),
+						mockExit.EXPECT().Exit(),
+					)
+
+					pcfdev.Run(fakeCliConnection, []string{"dev", "start", "--timeout", "some-timeout"})
+				})
+			})
+
+			Context("when the flag comes after --", func() {
+				It("should pass it on to the subcommand", func() {
+					gomock.InOrder(
+						mockCmdBuilder.EXPECT().Cmd("ssh").Return(mockCmd, nil),
+						mockCmd.EXPECT().Parse([]string{"--", "some-command", "--timeout", "some-timeout"}),
+						mockCmd.EXPECT().Run(gomock.Any()).Do(func(ctx context.Context) {
+							_, ok := ctx.Deadline()
+							Expect(ok).To(BeFalse())
+						}),
+					)
+
+					pcfdev.Run(fakeCliConnection, []string{"dev", "ssh", "--", "some-command", "--timeout", "some-timeout"})
+				})
+			})
+		})
+
+		Context("when the command run over SSH exits with a non-zero status", func() {
+			It("should exit with that status without printing an error", func() {
+				gomock.InOrder(
+					mockCmdBuilder.EXPECT().Cmd("ssh").Return(mockCmd, nil),
+					mockCmd.EXPECT().Parse([]string{"--", "false"}),
+					mockCmd.EXPECT().Run(gomock.Any()).Return(&ssh.ExitError{Status: 3}),
+					mockExit.EXPECT().ExitWithStatus(3),
+				)
+
+				pcfdev.Run(fakeCliConnection, []string{"dev", "ssh", "--", "false"})
+			})
+		})
+
 		Context("when printing the help text fails", func() {
 			It("should print an error", func() {
 				gomock.InOrder(
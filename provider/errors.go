@@ -0,0 +1,19 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/pivotal-cf/pcfdev-cli/libvirt"
+)
+
+type NotInstalledError struct {
+	Provider string
+	Err      error
+}
+
+func (e *NotInstalledError) Error() string {
+	if e.Provider == libvirt.ProviderName {
+		return fmt.Sprintf("failed to run virsh, please install libvirt: %s", e.Err)
+	}
+	return fmt.Sprintf("failed to run VBoxManage, please install Virtualbox: %s", e.Err)
+}
@@ -0,0 +1,206 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/debug"
+	"github.com/pivotal-cf/pcfdev-cli/vbox"
+	"github.com/pivotal-cf/pcfdev-cli/vboxdriver"
+)
+
+//go:generate mockgen -package mocks -destination mocks/provider.go github.com/pivotal-cf/pcfdev-cli/provider Provider
+type Provider interface {
+	ImportVM(ctx context.Context, vmConfig *config.VMConfig) error
+	StartVM(ctx context.Context, vmConfig *config.VMConfig) error
+	StopVM(ctx context.Context, vmConfig *config.VMConfig) error
+	SuspendVM(ctx context.Context, vmConfig *config.VMConfig) error
+	ResumeSavedVM(vmConfig *config.VMConfig) error
+	ResumePausedVM(vmConfig *config.VMConfig) error
+	PowerOffVM(vmConfig *config.VMConfig) error
+	DestroyVM(ctx context.Context, vmConfig *config.VMConfig) error
+	VMStatus(vmName string) (status string, err error)
+	VMConfig(vmName string) (vmConfig *config.VMConfig, err error)
+	GetVMName() (name string, err error)
+	DestroyPCFDevVMs(ctx context.Context) error
+	Version() (version *vboxdriver.VBoxDriverVersion, err error)
+	HostLogs(vmConfig *config.VMConfig) (logs []*debug.HostLog, err error)
+}
+
+type Selector struct {
+	Providers map[string]Provider
+	Default   string
+}
+
+func (s *Selector) ImportVM(ctx context.Context, vmConfig *config.VMConfig) error {
+	provider, err := s.providerFor(vmConfig)
+	if err != nil {
+		return err
+	}
+	return provider.ImportVM(ctx, vmConfig)
+}
+
+func (s *Selector) StartVM(ctx context.Context, vmConfig *config.VMConfig) error {
+	provider, err := s.providerFor(vmConfig)
+	if err != nil {
+		return err
+	}
+	return provider.StartVM(ctx, vmConfig)
+}
+
+func (s *Selector) StopVM(ctx context.Context, vmConfig *config.VMConfig) error {
+	provider, err := s.providerFor(vmConfig)
+	if err != nil {
+		return err
+	}
+	return provider.StopVM(ctx, vmConfig)
+}
+
+func (s *Selector) SuspendVM(ctx context.Context, vmConfig *config.VMConfig) error {
+	provider, err := s.providerFor(vmConfig)
+	if err != nil {
+		return err
+	}
+	return provider.SuspendVM(ctx, vmConfig)
+}
+
+func (s *Selector) ResumeSavedVM(vmConfig *config.VMConfig) error {
+	provider, err := s.providerFor(vmConfig)
+	if err != nil {
+		return err
+	}
+	return provider.ResumeSavedVM(vmConfig)
+}
+
+func (s *Selector) ResumePausedVM(vmConfig *config.VMConfig) error {
+	provider, err := s.providerFor(vmConfig)
+	if err != nil {
+		return err
+	}
+	return provider.ResumePausedVM(vmConfig)
+}
+
+func (s *Selector) PowerOffVM(vmConfig *config.VMConfig) error {
+	provider, err := s.providerFor(vmConfig)
+	if err != nil {
+		return err
+	}
+	return provider.PowerOffVM(vmConfig)
+}
+
+func (s *Selector) DestroyVM(ctx context.Context, vmConfig *config.VMConfig) error {
+	provider, err := s.providerFor(vmConfig)
+	if err != nil {
+		return err
+	}
+	return provider.DestroyVM(ctx, vmConfig)
+}
+
+func (s *Selector) VMStatus(vmName string) (string, error) {
+	for _, name := range s.names() {
+		status, err := s.Providers[name].VMStatus(vmName)
+		if err != nil {
+			return "", err
+		}
+		if status != vbox.StatusNotCreated {
+			return status, nil
+		}
+	}
+	return vbox.StatusNotCreated, nil
+}
+
+func (s *Selector) VMConfig(vmName string) (*config.VMConfig, error) {
+	for _, name := range s.names() {
+		status, err := s.Providers[name].VMStatus(vmName)
+		if err != nil {
+			return nil, err
+		}
+		if status != vbox.StatusNotCreated {
+			return s.Providers[name].VMConfig(vmName)
+		}
+	}
+
+	provider, err := s.defaultProvider()
+	if err != nil {
+		return nil, err
+	}
+	return provider.VMConfig(vmName)
+}
+
+func (s *Selector) GetVMName() (string, error) {
+	vmName := ""
+	for _, name := range s.names() {
+		providerVMName, err := s.Providers[name].GetVMName()
+		if err != nil {
+			return "", err
+		}
+		if providerVMName == "" {
+			continue
+		}
+		if vmName != "" {
+			return "", errors.New("multiple PCF Dev VMs found")
+		}
+		vmName = providerVMName
+	}
+	return vmName, nil
+}
+
+func (s *Selector) DestroyPCFDevVMs(ctx context.Context) error {
+	for _, name := range s.names() {
+		if err := s.Providers[name].DestroyPCFDevVMs(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Selector) Version() (*vboxdriver.VBoxDriverVersion, error) {
+	provider, err := s.defaultProvider()
+	if err != nil {
+		return nil, err
+	}
+	version, err := provider.Version()
+	if err != nil {
+		return nil, &NotInstalledError{Provider: s.Default, Err: err}
+	}
+	return version, nil
+}
+
+func (s *Selector) HostLogs(vmConfig *config.VMConfig) ([]*debug.HostLog, error) {
+	provider, err := s.providerFor(vmConfig)
+	if err != nil {
+		return nil, err
+	}
+	return provider.HostLogs(vmConfig)
+}
+
+func (s *Selector) providerFor(vmConfig *config.VMConfig) (Provider, error) {
+	if vmConfig.Provider == "" {
+		return s.defaultProvider()
+	}
+	return s.provider(vmConfig.Provider)
+}
+
+func (s *Selector) defaultProvider() (Provider, error) {
+	return s.provider(s.Default)
+}
+
+func (s *Selector) provider(name string) (Provider, error) {
+	provider, ok := s.Providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider: %s", name)
+	}
+	return provider, nil
+}
+
+func (s *Selector) names() []string {
+	names := make([]string, 0, len(s.Providers))
+	for name := range s.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
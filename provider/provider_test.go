@@ -0,0 +1,229 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/debug"
+	"github.com/pivotal-cf/pcfdev-cli/provider"
+	"github.com/pivotal-cf/pcfdev-cli/provider/mocks"
+	"github.com/pivotal-cf/pcfdev-cli/vbox"
+	"github.com/pivotal-cf/pcfdev-cli/vboxdriver"
+)
+
+var _ = Describe("Selector", func() {
+	var (
+		mockCtrl       *gomock.Controller
+		mockLibvirt    *mocks.MockProvider
+		mockVirtualBox *mocks.MockProvider
+		selector       *provider.Selector
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockLibvirt = mocks.NewMockProvider(mockCtrl)
+		mockVirtualBox = mocks.NewMockProvider(mockCtrl)
+		selector = &provider.Selector{
+			Providers: map[string]provider.Provider{
+				"libvirt":    mockLibvirt,
+				"virtualbox": mockVirtualBox,
+			},
+			Default: "virtualbox",
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("#StartVM", func() {
+		It("should start the VM with the provider named in the VM config", func() {
+			vmConfig := &config.VMConfig{Name: "some-vm", Provider: "libvirt"}
+			mockLibvirt.EXPECT().StartVM(gomock.Any(), vmConfig)
+
+			Expect(selector.StartVM(context.Background(), vmConfig)).To(Succeed())
+		})
+
+		Context("when the VM config does not name a provider", func() {
+			It("should use the default provider", func() {
+				vmConfig := &config.VMConfig{Name: "some-vm"}
+				mockVirtualBox.EXPECT().StartVM(gomock.Any(), vmConfig)
+
+				Expect(selector.StartVM(context.Background(), vmConfig)).To(Succeed())
+			})
+		})
+
+		Context("when the VM config names an unknown provider", func() {
+			It("should return an error", func() {
+				Expect(selector.StartVM(context.Background(), &config.VMConfig{Provider: "some-provider"})).To(MatchError("unknown provider: some-provider"))
+			})
+		})
+
+		Context("when the provider fails to start the VM", func() {
+			It("should return the error", func() {
+				vmConfig := &config.VMConfig{Name: "some-vm", Provider: "libvirt"}
+				mockLibvirt.EXPECT().StartVM(gomock.Any(), vmConfig).Return(errors.New("some-error"))
+
+				Expect(selector.StartVM(context.Background(), vmConfig)).To(MatchError("some-error"))
+			})
+		})
+	})
+
+	Describe("#ImportVM", func() {
+		It("should import the VM with the default provider", func() {
+			vmConfig := &config.VMConfig{Name: "some-vm"}
+			mockVirtualBox.EXPECT().ImportVM(gomock.Any(), vmConfig)
+
+			Expect(selector.ImportVM(context.Background(), vmConfig)).To(Succeed())
+		})
+
+		Context("when the default provider is unknown", func() {
+			It("should return an error", func() {
+				selector.Default = "some-provider"
+				Expect(selector.ImportVM(context.Background(), &config.VMConfig{Name: "some-vm"})).To(MatchError("unknown provider: some-provider"))
+			})
+		})
+	})
+
+	Describe("#VMStatus", func() {
+		It("should return the status from the provider that owns the VM", func() {
+			gomock.InOrder(
+				mockLibvirt.EXPECT().VMStatus("some-vm").Return(vbox.StatusNotCreated, nil),
+				mockVirtualBox.EXPECT().VMStatus("some-vm").Return(vbox.StatusRunning, nil),
+			)
+
+			Expect(selector.VMStatus("some-vm")).To(Equal(vbox.StatusRunning))
+		})
+
+		Context("when no provider has the VM", func() {
+			It("should return not created", func() {
+				mockLibvirt.EXPECT().VMStatus("some-vm").Return(vbox.StatusNotCreated, nil)
+				mockVirtualBox.EXPECT().VMStatus("some-vm").Return(vbox.StatusNotCreated, nil)
+
+				Expect(selector.VMStatus("some-vm")).To(Equal(vbox.StatusNotCreated))
+			})
+		})
+
+		Context("when a provider fails to get the status", func() {
+			It("should return the error", func() {
+				mockLibvirt.EXPECT().VMStatus("some-vm").Return("", errors.New("some-error"))
+
+				_, err := selector.VMStatus("some-vm")
+				Expect(err).To(MatchError("some-error"))
+			})
+		})
+	})
+
+	Describe("#VMConfig", func() {
+		It("should return the VM config from the provider that owns the VM", func() {
+			vmConfig := &config.VMConfig{Name: "some-vm", Provider: "libvirt"}
+			gomock.InOrder(
+				mockLibvirt.EXPECT().VMStatus("some-vm").Return(vbox.StatusStopped, nil),
+				mockLibvirt.EXPECT().VMConfig("some-vm").Return(vmConfig, nil),
+			)
+
+			Expect(selector.VMConfig("some-vm")).To(BeIdenticalTo(vmConfig))
+		})
+
+		Context("when no provider has the VM", func() {
+			It("should ask the default provider", func() {
+				mockLibvirt.EXPECT().VMStatus("some-vm").Return(vbox.StatusNotCreated, nil)
+				mockVirtualBox.EXPECT().VMStatus("some-vm").Return(vbox.StatusNotCreated, nil)
+				mockVirtualBox.EXPECT().VMConfig("some-vm").Return(nil, errors.New("some-error"))
+
+				_, err := selector.VMConfig("some-vm")
+				Expect(err).To(MatchError("some-error"))
+			})
+		})
+	})
+
+	Describe("#GetVMName", func() {
+		It("should return the name of the PCF Dev VM from any provider", func() {
+			mockLibvirt.EXPECT().GetVMName().Return("", nil)
+			mockVirtualBox.EXPECT().GetVMName().Return("some-vm", nil)
+
+			Expect(selector.GetVMName()).To(Equal("some-vm"))
+		})
+
+		Context("when more than one provider has a PCF Dev VM", func() {
+			It("should return an error", func() {
+				mockLibvirt.EXPECT().GetVMName().Return("some-vm", nil)
+				mockVirtualBox.EXPECT().GetVMName().Return("some-other-vm", nil)
+
+				_, err := selector.GetVMName()
+				Expect(err).To(MatchError("multiple PCF Dev VMs found"))
+			})
+		})
+
+		Context("when a provider fails to get the VM name", func() {
+			It("should return the error", func() {
+				mockLibvirt.EXPECT().GetVMName().Return("", errors.New("some-error"))
+
+				_, err := selector.GetVMName()
+				Expect(err).To(MatchError("some-error"))
+			})
+		})
+	})
+
+	Describe("#DestroyPCFDevVMs", func() {
+		It("should destroy PCF Dev VMs with every provider", func() {
+			mockLibvirt.EXPECT().DestroyPCFDevVMs(gomock.Any())
+			mockVirtualBox.EXPECT().DestroyPCFDevVMs(gomock.Any())
+
+			Expect(selector.DestroyPCFDevVMs(context.Background())).To(Succeed())
+		})
+
+		Context("when a provider fails to destroy its VMs", func() {
+			It("should return the error", func() {
+				mockLibvirt.EXPECT().DestroyPCFDevVMs(gomock.Any()).Return(errors.New("some-error"))
+
+				Expect(selector.DestroyPCFDevVMs(context.Background())).To(MatchError("some-error"))
+			})
+		})
+	})
+
+	Describe("#HostLogs", func() {
+		It("should return the host logs of the provider named in the VM config", func() {
+			vmConfig := &config.VMConfig{Name: "some-vm", Provider: "libvirt"}
+			logs := []*debug.HostLog{{Filename: "some-file", Output: []byte("some-output")}}
+			mockLibvirt.EXPECT().HostLogs(vmConfig).Return(logs, nil)
+
+			Expect(selector.HostLogs(vmConfig)).To(Equal(logs))
+		})
+
+		Context("when the VM config names an unknown provider", func() {
+			It("should return an error", func() {
+				_, err := selector.HostLogs(&config.VMConfig{Name: "some-vm", Provider: "some-provider"})
+				Expect(err).To(MatchError("unknown provider: some-provider"))
+			})
+		})
+	})
+
+	Describe("#Version", func() {
+		It("should return the version of the default provider", func() {
+			version := &vboxdriver.VBoxDriverVersion{Major: 5}
+			mockVirtualBox.EXPECT().Version().Return(version, nil)
+
+			Expect(selector.Version()).To(BeIdenticalTo(version))
+		})
+
+		Context("when the version of the default provider cannot be read", func() {
+			It("should tell the user to install that provider", func() {
+				mockVirtualBox.EXPECT().Version().Return(nil, errors.New("some-error"))
+
+				_, err := selector.Version()
+				Expect(err).To(MatchError("failed to run VBoxManage, please install Virtualbox: some-error"))
+
+				selector.Default = "libvirt"
+				mockLibvirt.EXPECT().Version().Return(nil, errors.New("some-error"))
+
+				_, err = selector.Version()
+				Expect(err).To(MatchError("failed to run virsh, please install libvirt: some-error"))
+			})
+		})
+	})
+})
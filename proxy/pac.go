@@ -0,0 +1,267 @@
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/robertkrimen/otto"
+)
+
+// PAC is a parsed proxy auto-config file, which is run by the otto JavaScript
+// interpreter. Otto only supports ES5, so PAC files that use later syntax,
+// such as let or arrow functions, fail to parse. The date and time PAC
+// functions are not supported.
+type PAC struct {
+	// LookupIP resolves hostnames for dnsResolve, isResolvable and isInNet.
+	// It defaults to net.LookupIP.
+	LookupIP func(host string) ([]net.IP, error)
+	// MyIPAddress returns the address of this host for myIpAddress. It
+	// defaults to the first non-loopback IPv4 address of this host.
+	MyIPAddress func() string
+	// Timeout limits how long the PAC file may run for. It defaults to ten
+	// seconds.
+	Timeout time.Duration
+
+	script *otto.Script
+}
+
+const defaultPACTimeout = 10 * time.Second
+
+var unsupportedPACFunctions = []string{"weekdayRange", "dateRange", "timeRange"}
+
+var errPACTimedOut = errors.New("PAC file timed out")
+
+// ParsePAC parses the source of a PAC file, which must define
+// FindProxyForURL.
+func ParsePAC(source string) (*PAC, error) {
+	script, err := otto.New().Compile("", source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PAC file, which must be ES5 JavaScript: %s", err)
+	}
+
+	p := &PAC{script: script}
+	value, err := p.run(func(vm *otto.Otto) (otto.Value, error) {
+		return vm.Get("FindProxyForURL")
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PAC file: %s", err)
+	}
+	if !value.IsFunction() {
+		return nil, errors.New("failed to parse PAC file: FindProxyForURL is not defined")
+	}
+	return p, nil
+}
+
+// FindProxyForURL returns the result of the FindProxyForURL function of the
+// PAC file, such as "PROXY proxy.example.com:8080; DIRECT".
+func (p *PAC) FindProxyForURL(url string, host string) (result string, err error) {
+	value, err := p.run(func(vm *otto.Otto) (otto.Value, error) {
+		return vm.Call("FindProxyForURL", nil, url, host)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to evaluate PAC file: %s", err)
+	}
+	if !value.IsString() {
+		return "", fmt.Errorf("failed to evaluate PAC file: FindProxyForURL returned %s", value)
+	}
+	return value.String(), nil
+}
+
+// run runs the PAC file in a new interpreter that has the PAC functions, and
+// then calls block, giving up on both after the Timeout.
+func (p *PAC) run(block func(vm *otto.Otto) (otto.Value, error)) (value otto.Value, err error) {
+	timeout := p.Timeout
+	if timeout == 0 {
+		timeout = defaultPACTimeout
+	}
+
+	vm := otto.New()
+	vm.Interrupt = make(chan func(), 1)
+	timer := time.AfterFunc(timeout, func() {
+		vm.Interrupt <- func() { panic(errPACTimedOut) }
+	})
+	defer timer.Stop()
+	defer func() {
+		if r := recover(); r != nil {
+			if r != errPACTimedOut {
+				panic(r)
+			}
+			err = fmt.Errorf("it did not finish within %s", timeout)
+		}
+	}()
+
+	if err := p.define(vm); err != nil {
+		return otto.Value{}, err
+	}
+	if _, err := vm.Run(p.script); err != nil {
+		return otto.Value{}, err
+	}
+	return block(vm)
+}
+
+// define gives the interpreter the PAC functions. The ones that are not
+// supported throw an error that names them.
+func (p *PAC) define(vm *otto.Otto) error {
+	functions := map[string]func(args []string) interface{}{
+		"isPlainHostName": func(args []string) interface{} {
+			return !strings.Contains(args[0], ".")
+		},
+		"dnsDomainIs": func(args []string) interface{} {
+			return strings.HasSuffix(strings.ToLower(args[0]), strings.ToLower(args[1]))
+		},
+		"localHostOrDomainIs": func(args []string) interface{} {
+			host, hostdom := strings.ToLower(args[0]), strings.ToLower(args[1])
+			return host == hostdom || (!strings.Contains(host, ".") && strings.HasPrefix(hostdom, host+"."))
+		},
+		"dnsDomainLevels": func(args []string) interface{} {
+			return strings.Count(args[0], ".")
+		},
+		"shExpMatch": func(args []string) interface{} {
+			return shExpMatch(args[0], args[1])
+		},
+		"isResolvable": func(args []string) interface{} {
+			return p.resolve(args[0]) != nil
+		},
+		"dnsResolve": func(args []string) interface{} {
+			if ip := p.resolve(args[0]); ip != nil {
+				return ip.String()
+			}
+			return nil
+		},
+		"isInNet": func(args []string) interface{} {
+			ip := p.resolve(args[0])
+			pattern := net.ParseIP(args[1]).To4()
+			mask := net.ParseIP(args[2]).To4()
+			if ip == nil || pattern == nil || mask == nil {
+				return false
+			}
+			return ip.Mask(net.IPMask(mask)).Equal(pattern.Mask(net.IPMask(mask)))
+		},
+		"myIpAddress": func(args []string) interface{} {
+			if p.MyIPAddress != nil {
+				return p.MyIPAddress()
+			}
+			return myIPAddress()
+		},
+		"alert": func(args []string) interface{} {
+			return nil
+		},
+	}
+
+	for name, function := range functions {
+		function := function
+		if err := vm.Set(name, func(call otto.FunctionCall) otto.Value {
+			args := make([]string, 3)
+			for i := range args {
+				if argument := call.Argument(i); argument.IsDefined() {
+					args[i] = argument.String()
+				}
+			}
+			result := function(args)
+			if result == nil {
+				return otto.NullValue()
+			}
+			value, err := vm.ToValue(result)
+			if err != nil {
+				panic(vm.MakeCustomError("Error", err.Error()))
+			}
+			return value
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range unsupportedPACFunctions {
+		name := name
+		if err := vm.Set(name, func(call otto.FunctionCall) otto.Value {
+			panic(vm.MakeCustomError("Error", fmt.Sprintf("unsupported function '%s'", name)))
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolve returns the IPv4 address of host, or nil if it has none.
+func (p *PAC) resolve(host string) net.IP {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.To4()
+	}
+
+	lookupIP := p.LookupIP
+	if lookupIP == nil {
+		lookupIP = net.LookupIP
+	}
+	ips, err := lookupIP(host)
+	if err != nil {
+		return nil
+	}
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			return ip.To4()
+		}
+	}
+	return nil
+}
+
+func shExpMatch(s string, pattern string) bool {
+	expression := regexp.QuoteMeta(pattern)
+	expression = strings.Replace(expression, `\*`, ".*", -1)
+	expression = strings.Replace(expression, `\?`, ".", -1)
+	matched, err := regexp.MatchString("^"+expression+"$", s)
+	return err == nil && matched
+}
+
+func myIPAddress() string {
+	addrs, err := net.InterfaceAddrs()
+	if err == nil {
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+				return ipNet.IP.String()
+			}
+		}
+	}
+	return "127.0.0.1"
+}
+
+// ParsePACResult returns the proxies in a FindProxyForURL result as URLs, in
+// order of preference. DIRECT is returned as "". SOCKS4 proxies are left out,
+// as they cannot be used.
+func ParsePACResult(result string) (proxies []string, err error) {
+	proxies = []string{}
+	for _, entry := range strings.Split(result, ";") {
+		fields := strings.Fields(entry)
+		if len(fields) == 0 {
+			continue
+		}
+
+		kind := strings.ToUpper(fields[0])
+		if kind == "DIRECT" && len(fields) == 1 {
+			proxies = append(proxies, "")
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid PAC result '%s'", result)
+		}
+
+		switch kind {
+		case "PROXY", "HTTP":
+			proxies = append(proxies, "http://"+fields[1])
+		case "HTTPS":
+			proxies = append(proxies, "https://"+fields[1])
+		case "SOCKS", "SOCKS5":
+			proxies = append(proxies, "socks5://"+fields[1])
+		case "SOCKS4":
+		default:
+			return nil, fmt.Errorf("invalid PAC result '%s'", result)
+		}
+	}
+	if len(proxies) == 0 {
+		return nil, fmt.Errorf("invalid PAC result '%s'", result)
+	}
+	return proxies, nil
+}
@@ -0,0 +1,165 @@
+package proxy_test
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/proxy"
+)
+
+var _ = Describe("PAC", func() {
+	const source = `
+		// Proxies everything but the intranet.
+		function isIntranet(host) {
+			return isPlainHostName(host) ||
+				dnsDomainIs(host, ".corp.example.com") ||
+				isInNet(host, "10.0.0.0", "255.0.0.0");
+		}
+
+		/* The HTTPS proxy only handles port 443. */
+		function FindProxyForURL(url, host) {
+			var lowerHost = host.toLowerCase();
+			if (isIntranet(lowerHost)) {
+				return "DIRECT";
+			} else if (url.substring(0, 6) == "https:" && url.indexOf(":8443") == -1) {
+				return "HTTPS secure-proxy.example.com:443";
+			}
+			if (shExpMatch(lowerHost, "*.socks.example.?om")) return 'SOCKS5 socks.example.com:1080';
+			if (dnsResolve(lowerHost) == "192.0.2.1" && myIpAddress() != "127.0.0.1") return "PROXY resolved-proxy:3128";
+			if (localHostOrDomainIs(lowerHost, "www.example.com") && dnsDomainLevels(lowerHost) > 1) return "PROXY www-proxy:3128";
+			return "PROXY proxy.example.com:8080; DIRECT";
+		}`
+
+	var pac *proxy.PAC
+
+	BeforeEach(func() {
+		var err error
+		pac, err = proxy.ParsePAC(source)
+		Expect(err).NotTo(HaveOccurred())
+		pac.LookupIP = func(host string) ([]net.IP, error) {
+			if host == "some.resolvable.host" {
+				return []net.IP{net.ParseIP("::1"), net.ParseIP("192.0.2.1")}, nil
+			}
+			return nil, errors.New("some-error")
+		}
+		pac.MyIPAddress = func() string {
+			return "192.168.1.2"
+		}
+	})
+
+	Describe("#FindProxyForURL", func() {
+		It("should evaluate FindProxyForURL", func() {
+			Expect(pac.FindProxyForURL("http://intranet/", "intranet")).To(Equal("DIRECT"))
+			Expect(pac.FindProxyForURL("http://wiki.corp.example.com/", "WIKI.corp.example.com")).To(Equal("DIRECT"))
+			Expect(pac.FindProxyForURL("http://10.1.2.3/", "10.1.2.3")).To(Equal("DIRECT"))
+			Expect(pac.FindProxyForURL("https://github.com/", "github.com")).To(Equal("HTTPS secure-proxy.example.com:443"))
+			Expect(pac.FindProxyForURL("https://github.com:8443/", "github.com")).To(Equal("PROXY proxy.example.com:8080; DIRECT"))
+			Expect(pac.FindProxyForURL("http://a.socks.example.com/", "a.socks.example.com")).To(Equal("SOCKS5 socks.example.com:1080"))
+			Expect(pac.FindProxyForURL("http://some.resolvable.host/", "some.resolvable.host")).To(Equal("PROXY resolved-proxy:3128"))
+			Expect(pac.FindProxyForURL("http://www.example.com/", "www.example.com")).To(Equal("PROXY www-proxy:3128"))
+			Expect(pac.FindProxyForURL("http://github.com/", "github.com")).To(Equal("PROXY proxy.example.com:8080; DIRECT"))
+		})
+
+		Context("when FindProxyForURL does not return a string", func() {
+			It("should return an error", func() {
+				pac, err := proxy.ParsePAC(`function FindProxyForURL(url, host) { if (false) return "DIRECT"; }`)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = pac.FindProxyForURL("http://github.com/", "github.com")
+				Expect(err).To(MatchError("failed to evaluate PAC file: FindProxyForURL returned undefined"))
+			})
+		})
+
+		It("should run ES5 that is not usually found in PAC files", func() {
+			pac, err := proxy.ParsePAC(`function FindProxyForURL(url, host) {
+				var proxies = ["PROXY a:1", "PROXY b:2"];
+				for (var i = 0; i < proxies.length; i++) {
+					if (host.charAt(0) === String.fromCharCode(97 + i)) return proxies[i];
+				}
+				return /^direct\./.test(host) ? "DIRECT" : proxies.join("; ");
+			}`)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(pac.FindProxyForURL("http://b.example.com/", "b.example.com")).To(Equal("PROXY b:2"))
+			Expect(pac.FindProxyForURL("http://direct.example.com/", "direct.example.com")).To(Equal("DIRECT"))
+			Expect(pac.FindProxyForURL("http://c.example.com/", "c.example.com")).To(Equal("PROXY a:1; PROXY b:2"))
+		})
+
+		Context("when FindProxyForURL does not finish", func() {
+			It("should give up after the timeout", func() {
+				pac, err := proxy.ParsePAC(`function FindProxyForURL(url, host) { for (;;) {} }`)
+				Expect(err).NotTo(HaveOccurred())
+				pac.Timeout = 10 * time.Millisecond
+
+				_, err = pac.FindProxyForURL("http://github.com/", "github.com")
+				Expect(err).To(MatchError("failed to evaluate PAC file: it did not finish within 10ms"))
+			})
+		})
+
+		Context("when the PAC file uses an unsupported function", func() {
+			It("should return an error", func() {
+				pac, err := proxy.ParsePAC(`function FindProxyForURL(url, host) { if (weekdayRange("MON", "FRI")) return "DIRECT"; return "PROXY p:1"; }`)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = pac.FindProxyForURL("http://github.com/", "github.com")
+				Expect(err).To(MatchError("failed to evaluate PAC file: Error: unsupported function 'weekdayRange'"))
+			})
+		})
+	})
+
+	Describe("ParsePAC", func() {
+		Context("when the PAC file does not define FindProxyForURL", func() {
+			It("should return an error", func() {
+				_, err := proxy.ParsePAC(`function findProxy(url, host) { return "DIRECT"; }`)
+				Expect(err).To(MatchError("failed to parse PAC file: FindProxyForURL is not defined"))
+			})
+		})
+
+		Context("when the PAC file uses syntax that is not ES5", func() {
+			It("should return an error", func() {
+				_, err := proxy.ParsePAC(`function FindProxyForURL(url, host) { let proxy = "DIRECT"; return proxy; }`)
+				Expect(err).To(MatchError(HavePrefix("failed to parse PAC file, which must be ES5 JavaScript: ")))
+
+				_, err = proxy.ParsePAC(`var FindProxyForURL = (url, host) => "DIRECT";`)
+				Expect(err).To(MatchError(HavePrefix("failed to parse PAC file, which must be ES5 JavaScript: ")))
+
+				_, err = proxy.ParsePAC(`function FindProxyForURL(url, host) { return "DIRECT"; `)
+				Expect(err).To(MatchError(HavePrefix("failed to parse PAC file, which must be ES5 JavaScript: ")))
+			})
+		})
+
+		Context("when the PAC file throws an error", func() {
+			It("should return the error", func() {
+				_, err := proxy.ParsePAC(`throw new Error("some-error"); function FindProxyForURL(url, host) { return "DIRECT"; }`)
+				Expect(err).To(MatchError("failed to parse PAC file: Error: some-error"))
+			})
+		})
+	})
+
+	Describe("ParsePACResult", func() {
+		It("should return the proxies as URLs in order", func() {
+			Expect(proxy.ParsePACResult("PROXY a:1; HTTPS b:2;SOCKS4 c:3; socks d:4; DIRECT")).To(Equal([]string{
+				"http://a:1",
+				"https://b:2",
+				"socks5://d:4",
+				"",
+			}))
+		})
+
+		Context("when the result is invalid", func() {
+			It("should return an error", func() {
+				_, err := proxy.ParsePACResult("PROXY")
+				Expect(err).To(MatchError("invalid PAC result 'PROXY'"))
+
+				_, err = proxy.ParsePACResult("SOME-TYPE a:1")
+				Expect(err).To(MatchError("invalid PAC result 'SOME-TYPE a:1'"))
+
+				_, err = proxy.ParsePACResult("")
+				Expect(err).To(MatchError("invalid PAC result ''"))
+			})
+		})
+	})
+})
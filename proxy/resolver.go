@@ -0,0 +1,148 @@
+package proxy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pivotal-cf/pcfdev-cli/config"
+)
+
+var windowsFileURLPath = regexp.MustCompile(`^/[A-Za-z]:`)
+
+// Resolver chooses the proxy for each destination from the proxy settings in
+// the Config: from the PAC file when one is given, or else from the proxy
+// variables.
+type Resolver struct {
+	Config *config.Config
+	// Client fetches PAC files that are given as URLs. It defaults to a
+	// client that does not use a proxy.
+	Client *http.Client
+
+	once   sync.Once
+	pac    *PAC
+	pacErr error
+}
+
+// Proxy can be used as the Proxy of an http.Transport.
+func (r *Resolver) Proxy(req *http.Request) (*url.URL, error) {
+	proxy, err := r.ProxyForURL(req.URL)
+	if err != nil || proxy == "" {
+		return nil, err
+	}
+	return ParseURL(proxy)
+}
+
+// ProxyForURL returns the proxy address to use for destination, or "" if it
+// should be reached directly.
+func (r *Resolver) ProxyForURL(destination *url.URL) (proxy string, err error) {
+	host := strings.ToLower(destination.Hostname())
+	if host == "localhost" || (net.ParseIP(host) != nil && net.ParseIP(host).IsLoopback()) {
+		return "", nil
+	}
+
+	if r.Config.ProxyPAC != "" {
+		r.once.Do(func() {
+			r.pac, r.pacErr = LoadPAC(r.Config.ProxyPAC, r.Client)
+		})
+		if r.pacErr != nil {
+			return "", r.pacErr
+		}
+
+		result, err := r.pac.FindProxyForURL(destination.String(), host)
+		if err != nil {
+			return "", err
+		}
+		proxies, err := ParsePACResult(result)
+		if err != nil {
+			return "", err
+		}
+		return proxies[0], nil
+	}
+
+	if bypassed(host, r.Config.NoProxy) {
+		return "", nil
+	}
+	if destination.Scheme == "https" {
+		return r.Config.HTTPSProxy, nil
+	}
+	return r.Config.HTTPProxy, nil
+}
+
+// LoadPAC reads a PAC file from an http or https URL, a file URL or a path.
+func LoadPAC(location string, client *http.Client) (*PAC, error) {
+	source, err := readPAC(location, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the PAC file %s: %s", location, err)
+	}
+	return ParsePAC(string(source))
+}
+
+func readPAC(location string, client *http.Client) ([]byte, error) {
+	lowerLocation := strings.ToLower(location)
+	switch {
+	case strings.HasPrefix(lowerLocation, "http://"), strings.HasPrefix(lowerLocation, "https://"):
+		if client == nil {
+			client = &http.Client{
+				Transport: &http.Transport{Proxy: nil},
+				Timeout:   30 * time.Second,
+			}
+		}
+		resp, err := client.Get(location)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("the server returned %s", resp.Status)
+		}
+		return ioutil.ReadAll(resp.Body)
+	case strings.HasPrefix(lowerLocation, "file://"):
+		fileURL, err := url.Parse(location)
+		if err != nil {
+			return nil, err
+		}
+		path := fileURL.Path
+		if windowsFileURLPath.MatchString(path) {
+			path = path[1:]
+		}
+		return ioutil.ReadFile(path)
+	}
+	return ioutil.ReadFile(location)
+}
+
+// bypassed returns whether host matches an entry in a NO_PROXY list, which
+// can be *, a domain, which matches its subdomains too, an IP address or a
+// subnet.
+func bypassed(host string, noProxy string) bool {
+	ip := net.ParseIP(host)
+	for _, entry := range strings.Split(strings.ToLower(noProxy), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+		if _, subnet, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && subnet.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if h, _, err := net.SplitHostPort(entry); err == nil {
+			entry = h
+		}
+		entry = strings.TrimPrefix(strings.TrimPrefix(entry, "*"), ".")
+		if host == entry || strings.HasSuffix(host, "."+entry) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package proxy
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// proxyAddress is a proxy address split into its parts. The scheme is
+// optional, as it is in the proxy environment variables, and every part is
+// kept as it was written, so that an address can be changed without
+// otherwise reformatting it.
+type proxyAddress struct {
+	scheme   string
+	userinfo string
+	host     string
+	port     string
+	rest     string
+}
+
+func splitProxy(proxy string) *proxyAddress {
+	address := &proxyAddress{}
+	if i := strings.Index(proxy, "://"); i != -1 {
+		address.scheme, proxy = proxy[:i+3], proxy[i+3:]
+	}
+	if i := strings.IndexAny(proxy, "/?#"); i != -1 {
+		proxy, address.rest = proxy[:i], proxy[i:]
+	}
+	if i := strings.LastIndex(proxy, "@"); i != -1 {
+		address.userinfo, proxy = proxy[:i+1], proxy[i+1:]
+	}
+
+	switch {
+	case strings.HasPrefix(proxy, "["):
+		if i := strings.Index(proxy, "]"); i != -1 {
+			address.host, address.port = proxy[:i+1], proxy[i+1:]
+			return address
+		}
+	case strings.Count(proxy, ":") == 1:
+		i := strings.Index(proxy, ":")
+		address.host, address.port = proxy[:i], proxy[i:]
+		return address
+	}
+	address.host = proxy
+	return address
+}
+
+func (a *proxyAddress) String() string {
+	return a.scheme + a.userinfo + a.host + a.port + a.rest
+}
+
+// RewriteLoopback replaces a loopback host in a proxy address, which is
+// 127.0.0.1, localhost or ::1, with ip.
+func RewriteLoopback(proxy string, ip string) string {
+	address := splitProxy(proxy)
+	switch strings.ToLower(strings.Trim(address.host, "[]")) {
+	case "127.0.0.1", "localhost", "::1":
+		address.host = ip
+		return address.String()
+	}
+	return proxy
+}
+
+// HasCredentials returns whether a proxy address has a user and password.
+func HasCredentials(proxy string) bool {
+	return splitProxy(proxy).userinfo != ""
+}
+
+// StripCredentials removes the user and password from a proxy address.
+func StripCredentials(proxy string) string {
+	address := splitProxy(proxy)
+	address.userinfo = ""
+	return address.String()
+}
+
+// Redact replaces the password in a proxy address, so that it can be shown.
+func Redact(proxy string) string {
+	address := splitProxy(proxy)
+	if address.userinfo == "" {
+		return proxy
+	}
+	user := strings.TrimSuffix(address.userinfo, "@")
+	if i := strings.Index(user, ":"); i != -1 {
+		user = user[:i]
+	}
+	address.userinfo = user + ":<redacted>@"
+	return address.String()
+}
+
+// ParseURL parses a proxy address, which is taken to be an HTTP proxy when
+// it has no scheme.
+func ParseURL(proxy string) (*url.URL, error) {
+	if !strings.Contains(proxy, "://") {
+		proxy = "http://" + proxy
+	}
+	proxyURL, err := url.Parse(proxy)
+	if err != nil || proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy address %s", Redact(proxy))
+	}
+	return proxyURL, nil
+}
@@ -1,18 +1,55 @@
 package runner
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"strings"
+	"syscall"
 )
 
 type CmdRunner struct{}
 
 func (c *CmdRunner) Run(command string, args ...string) ([]byte, error) {
-	output, err := exec.Command(command, args...).CombinedOutput()
+	return c.RunContext(context.Background(), command, args...)
+}
+
+// RunContext runs command like Run, and kills it when ctx is done.
+func (c *CmdRunner) RunContext(ctx context.Context, command string, args ...string) ([]byte, error) {
+	output, err := exec.CommandContext(ctx, command, args...).CombinedOutput()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute '%s %s': %s: %s", command, strings.Join(args, " "), err, output)
+		return nil, &ExecutionError{
+			Command: command,
+			Args:    args,
+			Output:  output,
+			Err:     err,
+		}
 	}
 
 	return output, nil
 }
+
+type ExecutionError struct {
+	Command string
+	Args    []string
+	Output  []byte
+	Err     error
+}
+
+func (e *ExecutionError) Error() string {
+	return fmt.Sprintf("failed to execute '%s %s': %s: %s", e.Command, strings.Join(e.Args, " "), e.Err, e.Output)
+}
+
+// ExitStatus returns the exit code of the command, or -1 if it did not exit
+// normally.
+func (e *ExecutionError) ExitStatus() int {
+	if exitErr, ok := e.Err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			return status.ExitStatus()
+		}
+	}
+	return -1
+}
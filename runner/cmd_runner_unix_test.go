@@ -1,7 +1,9 @@
 package runner_test
 
 import (
+	"context"
 	"runtime"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -32,6 +34,32 @@ var _ = Describe("CmdRunner", func() {
 				_, err := runner.Run("bash", "-c", "echo -n some-error && exit 1")
 				Expect(err).To(MatchError("failed to execute 'bash -c echo -n some-error && exit 1': exit status 1: some-error"))
 			})
+
+			It("should return the exit status and the output of the command", func() {
+				_, err := runner.Run("bash", "-c", "echo -n some-error && exit 3")
+				executionError, ok := err.(*rnr.ExecutionError)
+				Expect(ok).To(BeTrue())
+				Expect(executionError.ExitStatus()).To(Equal(3))
+				Expect(executionError.Output).To(Equal([]byte("some-error")))
+			})
+		})
+	})
+
+	Describe("#RunContext", func() {
+		It("should execute a command and return its output", func() {
+			Expect(runner.RunContext(context.Background(), "echo", "-n", "some-output")).To(Equal([]byte("some-output")))
+		})
+
+		Context("when the context is cancelled", func() {
+			It("should kill the command", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(100*time.Millisecond, cancel)
+
+				start := time.Now()
+				_, err := runner.RunContext(ctx, "sleep", "600")
+				Expect(err).To(MatchError(context.Canceled))
+				Expect(time.Since(start)).To(BeNumerically("<", 10*time.Second))
+			})
 		})
 	})
 })
@@ -0,0 +1,181 @@
+package socks
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+
+	. "github.com/pivotal-cf/pcfdev-cli/helpers"
+)
+
+const (
+	version5 = 0x05
+
+	methodNoAuthentication = 0x00
+	methodNoneAcceptable   = 0xff
+
+	commandConnect = 0x01
+
+	addressTypeIPv4   = 0x01
+	addressTypeDomain = 0x03
+	addressTypeIPv6   = 0x04
+
+	replySucceeded               = 0x00
+	replyGeneralFailure          = 0x01
+	replyCommandNotSupported     = 0x07
+	replyAddressTypeNotSupported = 0x08
+)
+
+// DialFunc opens a connection to an address, like net.Dial.
+type DialFunc func(network string, address string) (net.Conn, error)
+
+// Server is a SOCKS5 server that opens the connections its clients ask for
+// with a DialFunc. It only supports CONNECT without authentication, which is
+// all that browsers and command line tools need.
+type Server struct{}
+
+// ListenAndServe handles SOCKS connections on address until ctx is done.
+func (s *Server) ListenAndServe(ctx context.Context, address string, dial DialFunc) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen for SOCKS connections on %s: %s", address, err)
+	}
+	defer listener.Close()
+
+	stop := CloseWhenDone(ctx, listener)
+	defer stop()
+	if err := Serve(listener, dial); err != nil && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+// Serve handles the connections accepted by listener until accepting fails.
+func Serve(listener net.Listener, dial DialFunc) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			IgnoreErrorFrom(Handle(conn, dial))
+		}()
+	}
+}
+
+// Handle serves a single SOCKS5 client, and closes its connection when either
+// end of the proxied connection closes.
+func Handle(conn net.Conn, dial DialFunc) error {
+	defer conn.Close()
+
+	if err := negotiate(conn); err != nil {
+		return err
+	}
+
+	address, err := readRequest(conn)
+	if err != nil {
+		return err
+	}
+
+	destination, err := dial("tcp", address)
+	if err != nil {
+		IgnoreErrorFrom(reply(conn, replyGeneralFailure))
+		return fmt.Errorf("failed to connect to %s: %s", address, err)
+	}
+	defer destination.Close()
+
+	if err := reply(conn, replySucceeded); err != nil {
+		return err
+	}
+
+	go func() {
+		IgnoreErrorFrom(io.Copy(conn, destination))
+		IgnoreErrorFrom(conn.Close())
+	}()
+
+	_, err = io.Copy(destination, conn)
+	return err
+}
+
+func negotiate(conn net.Conn) error {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return err
+	}
+	if header[0] != version5 {
+		return fmt.Errorf("unsupported SOCKS version %d", header[0])
+	}
+
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return err
+	}
+	for _, method := range methods {
+		if method == methodNoAuthentication {
+			_, err := conn.Write([]byte{version5, methodNoAuthentication})
+			return err
+		}
+	}
+
+	IgnoreErrorFrom(conn.Write([]byte{version5, methodNoneAcceptable}))
+	return errors.New("the client does not support connecting without authentication")
+}
+
+func readRequest(conn net.Conn) (address string, err error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return "", err
+	}
+	if header[0] != version5 {
+		return "", fmt.Errorf("unsupported SOCKS version %d", header[0])
+	}
+
+	var host string
+	switch header[3] {
+	case addressTypeIPv4, addressTypeIPv6:
+		ip := make([]byte, net.IPv4len)
+		if header[3] == addressTypeIPv6 {
+			ip = make([]byte, net.IPv6len)
+		}
+		if _, err := io.ReadFull(conn, ip); err != nil {
+			return "", err
+		}
+		host = net.IP(ip).String()
+	case addressTypeDomain:
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return "", err
+		}
+		domain := make([]byte, length[0])
+		if _, err := io.ReadFull(conn, domain); err != nil {
+			return "", err
+		}
+		host = string(domain)
+	default:
+		IgnoreErrorFrom(reply(conn, replyAddressTypeNotSupported))
+		return "", fmt.Errorf("unsupported address type %d", header[3])
+	}
+
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return "", err
+	}
+
+	if header[1] != commandConnect {
+		IgnoreErrorFrom(reply(conn, replyCommandNotSupported))
+		return "", fmt.Errorf("unsupported command %d", header[1])
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))), nil
+}
+
+// reply sends a reply with an empty bound address, which clients do not need
+// for CONNECT and which is not known for connections through the VM.
+func reply(conn net.Conn, code byte) error {
+	_, err := conn.Write([]byte{version5, code, 0x00, addressTypeIPv4, 0, 0, 0, 0, 0, 0})
+	return err
+}
@@ -0,0 +1,151 @@
+package socks_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+
+	"github.com/pivotal-cf/pcfdev-cli/socks"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Server", func() {
+	Describe("#ListenAndServe", func() {
+		It("should stop listening when the context is done", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			finished := make(chan error, 1)
+			go func() {
+				finished <- (&socks.Server{}).ListenAndServe(ctx, "127.0.0.1:0", net.Dial)
+			}()
+
+			Consistently(finished).ShouldNot(Receive())
+			cancel()
+			Eventually(finished).Should(Receive(BeNil()))
+		})
+	})
+})
+
+var _ = Describe("SOCKS", func() {
+	var (
+		client         net.Conn
+		server         net.Conn
+		destination    net.Conn
+		dialedNetwork  string
+		dialedAddress  string
+		dialErr        error
+		handleFinished chan error
+	)
+
+	read := func(length int) []byte {
+		response := make([]byte, length)
+		_, err := io.ReadFull(client, response)
+		Expect(err).NotTo(HaveOccurred())
+		return response
+	}
+
+	BeforeEach(func() {
+		client, server = net.Pipe()
+		dialedNetwork, dialedAddress, dialErr = "", "", nil
+		handleFinished = make(chan error, 1)
+
+		var destinationServer net.Conn
+		destination, destinationServer = net.Pipe()
+		dial := func(network string, address string) (net.Conn, error) {
+			dialedNetwork, dialedAddress = network, address
+			if dialErr != nil {
+				return nil, dialErr
+			}
+			return destinationServer, nil
+		}
+
+		go func() {
+			handleFinished <- socks.Handle(server, dial)
+		}()
+	})
+
+	AfterEach(func() {
+		client.Close()
+		destination.Close()
+	})
+
+	It("should connect to a domain name and proxy the connection", func() {
+		_, err := client.Write([]byte{5, 1, 0})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(read(2)).To(Equal([]byte{5, 0}))
+
+		request := append([]byte{5, 1, 0, 3, byte(len("api.local.pcfdev.io"))}, "api.local.pcfdev.io"...)
+		_, err = client.Write(append(request, 0x01, 0xbb))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(read(10)).To(Equal([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0}))
+		Expect(dialedNetwork).To(Equal("tcp"))
+		Expect(dialedAddress).To(Equal("api.local.pcfdev.io:443"))
+
+		go client.Write([]byte("some-request"))
+		request = make([]byte, len("some-request"))
+		_, err = io.ReadFull(destination, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(request)).To(Equal("some-request"))
+
+		go destination.Write([]byte("some-response"))
+		Expect(string(read(len("some-response")))).To(Equal("some-response"))
+	})
+
+	It("should connect to an IP address", func() {
+		_, err := client.Write([]byte{5, 1, 0})
+		Expect(err).NotTo(HaveOccurred())
+		read(2)
+
+		_, err = client.Write([]byte{5, 1, 0, 1, 192, 168, 11, 11, 0, 80})
+		Expect(err).NotTo(HaveOccurred())
+		read(10)
+		Expect(dialedAddress).To(Equal("192.168.11.11:80"))
+	})
+
+	Context("when the client only supports authentication", func() {
+		It("should refuse it", func() {
+			_, err := client.Write([]byte{5, 1, 2})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(read(2)).To(Equal([]byte{5, 0xff}))
+			Eventually(handleFinished).Should(Receive(MatchError("the client does not support connecting without authentication")))
+		})
+	})
+
+	Context("when the client asks for a command other than CONNECT", func() {
+		It("should reply that the command is not supported", func() {
+			_, err := client.Write([]byte{5, 1, 0})
+			Expect(err).NotTo(HaveOccurred())
+			read(2)
+
+			_, err = client.Write([]byte{5, 2, 0, 1, 127, 0, 0, 1, 0, 80})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(read(10)[1]).To(Equal(byte(7)))
+			Eventually(handleFinished).Should(Receive(MatchError("unsupported command 2")))
+		})
+	})
+
+	Context("when the client uses another version of SOCKS", func() {
+		It("should return an error", func() {
+			_, err := client.Write([]byte{4, 1})
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(handleFinished).Should(Receive(MatchError("unsupported SOCKS version 4")))
+		})
+	})
+
+	Context("when the destination cannot be reached", func() {
+		It("should reply with a failure", func() {
+			dialErr = errors.New("some-error")
+
+			_, err := client.Write([]byte{5, 1, 0})
+			Expect(err).NotTo(HaveOccurred())
+			read(2)
+
+			_, err = client.Write([]byte{5, 1, 0, 1, 192, 168, 11, 11, 0, 80})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(read(10)[1]).To(Equal(byte(1)))
+			Eventually(handleFinished).Should(Receive(MatchError("failed to connect to 192.168.11.11:80: some-error")))
+		})
+	})
+})
@@ -0,0 +1,97 @@
+package ssh
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/pivotal-cf/pcfdev-cli/helpers"
+	"golang.org/x/crypto/ssh"
+)
+
+// keepaliveTimeout is how long a reused connection has to answer a keepalive
+// before it is taken to have dropped.
+const keepaliveTimeout = 5 * time.Second
+
+// client returns the open connection for the addresses and private key, so
+// that every session and tunnel to the VM is multiplexed over one connection,
+// and dials a new one when there is none or it has dropped. The lock is only
+// held to read and update the cache, so that a slow dial or keepalive does
+// not hold up sessions to other VMs.
+func (s *SSH) client(ctx context.Context, addresses []SSHAddress, privateKey []byte, timeout time.Duration) (*ssh.Client, error) {
+	key := connectionKey(addresses, privateKey)
+
+	if client := s.cachedClient(key); client != nil {
+		if isAlive(client) {
+			return client, nil
+		}
+		s.forget(client)
+	}
+
+	client, err := s.waitForSSH(ctx, addresses, privateKey, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	if existing, ok := s.clients[key]; ok {
+		s.mutex.Unlock()
+		IgnoreErrorFrom(client.Close())
+		return existing, nil
+	}
+	if s.clients == nil {
+		s.clients = map[string]*ssh.Client{}
+	}
+	s.clients[key] = client
+	s.mutex.Unlock()
+
+	go func() {
+		IgnoreErrorFrom(client.Wait())
+		s.forget(client)
+	}()
+	return client, nil
+}
+
+func (s *SSH) cachedClient(key string) *ssh.Client {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.clients[key]
+}
+
+// forget closes a connection and stops it from being reused.
+func (s *SSH) forget(client *ssh.Client) {
+	IgnoreErrorFrom(client.Close())
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for key, c := range s.clients {
+		if c == client {
+			delete(s.clients, key)
+		}
+	}
+}
+
+func isAlive(client *ssh.Client) bool {
+	result := make(chan error, 1)
+	go func() {
+		_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+		result <- err
+	}()
+
+	select {
+	case err := <-result:
+		return err == nil
+	case <-time.After(keepaliveTimeout):
+		return false
+	}
+}
+
+func connectionKey(addresses []SSHAddress, privateKey []byte) string {
+	hostPorts := []string{}
+	for _, address := range addresses {
+		hostPorts = append(hostPorts, address.IP+":"+address.Port)
+	}
+	return fmt.Sprintf("%s/%x", strings.Join(hostPorts, ","), sha256.Sum256(privateKey))
+}
@@ -0,0 +1,25 @@
+package ssh
+
+import "fmt"
+
+type HostKeyMismatchError struct {
+	Path string
+}
+
+func (e *HostKeyMismatchError) Error() string {
+	return fmt.Sprintf("the host key of the PCF Dev VM does not match the one in %s, so the connection may have been intercepted. If PCF Dev was recreated, remove %s to trust its new host key", e.Path, e.Path)
+}
+
+// ExitError is returned by StartSSHSession when the shell or command exits
+// with a non-zero status.
+type ExitError struct {
+	Status int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("command exited with status %d", e.Status)
+}
+
+func (e *ExitError) ExitStatus() int {
+	return e.Status
+}
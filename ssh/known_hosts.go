@@ -0,0 +1,94 @@
+package ssh
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// KnownHosts keeps the host key of the PCF Dev VM in a known_hosts file. There
+// is only ever one VM, so its key is checked at whichever address it is
+// reached. The first key seen is trusted while the file does not exist, which
+// is the case when a VM has just been created, since the file is removed then.
+type KnownHosts struct {
+	Path  string
+	mutex sync.Mutex
+}
+
+// Check returns an error unless key is the known host key of the VM, and
+// records key as that host key if there is none yet.
+func (k *KnownHosts) Check(address string, key ssh.PublicKey) error {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	knownKeys, err := k.keys()
+	if err != nil {
+		return err
+	}
+
+	if len(knownKeys) == 0 {
+		return k.add(address, key)
+	}
+
+	for _, knownKey := range knownKeys {
+		if bytes.Equal(knownKey.Marshal(), key.Marshal()) {
+			return nil
+		}
+	}
+	return &HostKeyMismatchError{Path: k.Path}
+}
+
+func (k *KnownHosts) keys() ([]ssh.PublicKey, error) {
+	contents, err := ioutil.ReadFile(k.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", k.Path, err)
+	}
+
+	keys := []ssh.PublicKey{}
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		_, _, key, _, _, err := ssh.ParseKnownHosts(line)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %s", k.Path, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (k *KnownHosts) add(address string, key ssh.PublicKey) error {
+	if err := os.MkdirAll(filepath.Dir(k.Path), 0755); err != nil {
+		return fmt.Errorf("failed to write %s: %s", k.Path, err)
+	}
+	line := fmt.Sprintf("%s %s", knownHostsPattern(address), ssh.MarshalAuthorizedKey(key))
+	if err := ioutil.WriteFile(k.Path, []byte(line), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %s", k.Path, err)
+	}
+	return nil
+}
+
+// knownHostsPattern writes an address the way that OpenSSH does in
+// known_hosts files, so that they can be given to ssh too.
+func knownHostsPattern(address string) string {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+	if port == "22" {
+		return host
+	}
+	return fmt.Sprintf("[%s]:%s", host, port)
+}
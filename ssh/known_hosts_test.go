@@ -0,0 +1,91 @@
+package ssh_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+var _ = Describe("KnownHosts", func() {
+	var (
+		tmpDir     string
+		knownHosts *ssh.KnownHosts
+		hostKey    gossh.PublicKey
+		otherKey   gossh.PublicKey
+	)
+
+	newPublicKey := func() gossh.PublicKey {
+		privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+		Expect(err).NotTo(HaveOccurred())
+		publicKey, err := gossh.NewPublicKey(privateKey.Public())
+		Expect(err).NotTo(HaveOccurred())
+		return publicKey
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "pcfdev-known-hosts")
+		Expect(err).NotTo(HaveOccurred())
+		knownHosts = &ssh.KnownHosts{Path: filepath.Join(tmpDir, "vms", "known_hosts")}
+		hostKey = newPublicKey()
+		otherKey = newPublicKey()
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	Describe("#Check", func() {
+		It("should trust the first key and record it as OpenSSH does", func() {
+			Expect(knownHosts.Check("127.0.0.1:2222", hostKey)).To(Succeed())
+
+			contents, err := ioutil.ReadFile(knownHosts.Path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("[127.0.0.1]:2222 " + string(gossh.MarshalAuthorizedKey(hostKey))))
+		})
+
+		It("should accept the known key at any address", func() {
+			Expect(knownHosts.Check("127.0.0.1:2222", hostKey)).To(Succeed())
+			Expect(knownHosts.Check("192.168.11.11:22", hostKey)).To(Succeed())
+		})
+
+		Context("when the key does not match the known key", func() {
+			It("should return an error", func() {
+				Expect(knownHosts.Check("127.0.0.1:2222", hostKey)).To(Succeed())
+
+				err := knownHosts.Check("127.0.0.1:2222", otherKey)
+				Expect(err).To(BeAssignableToTypeOf(&ssh.HostKeyMismatchError{}))
+				Expect(err).To(MatchError(ContainSubstring("remove " + knownHosts.Path + " to trust its new host key")))
+			})
+		})
+
+		Context("when the known_hosts file has been removed", func() {
+			It("should trust the new key", func() {
+				Expect(knownHosts.Check("127.0.0.1:2222", hostKey)).To(Succeed())
+				Expect(os.Remove(knownHosts.Path)).To(Succeed())
+
+				Expect(knownHosts.Check("192.168.11.11:22", otherKey)).To(Succeed())
+
+				contents, err := ioutil.ReadFile(knownHosts.Path)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(contents)).To(Equal("192.168.11.11 " + string(gossh.MarshalAuthorizedKey(otherKey))))
+			})
+		})
+
+		Context("when the known_hosts file is invalid", func() {
+			It("should return an error", func() {
+				Expect(os.MkdirAll(filepath.Dir(knownHosts.Path), 0755)).To(Succeed())
+				Expect(ioutil.WriteFile(knownHosts.Path, []byte("some-bad-line\n"), 0644)).To(Succeed())
+
+				Expect(knownHosts.Check("127.0.0.1:2222", hostKey)).To(MatchError(HavePrefix("failed to parse " + knownHosts.Path)))
+			})
+		})
+	})
+})
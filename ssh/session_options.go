@@ -0,0 +1,165 @@
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	. "github.com/pivotal-cf/pcfdev-cli/helpers"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// SessionOptions change what StartSSHSession runs. The zero value starts an
+// interactive shell.
+type SessionOptions struct {
+	// Command is run instead of a shell when it is not empty.
+	Command string
+	// TTY requests a terminal for Command. A shell always gets one.
+	TTY bool
+	// AgentForwarding forwards the agent at SSH_AUTH_SOCK to the VM.
+	AgentForwarding bool
+	// LocalForwards are ports on this host that are forwarded to the VM.
+	LocalForwards []Forward
+	// RemoteForwards are ports on the VM that are forwarded to this host.
+	RemoteForwards []Forward
+}
+
+// Forward is a port forward in the [bind_address:]port:host:hostport format
+// of the ssh -L and -R options.
+type Forward struct {
+	BindAddress string
+	Port        string
+	Host        string
+	HostPort    string
+}
+
+// ParseForward parses a port forward in the [bind_address:]port:host:hostport
+// format. IPv6 addresses are written in brackets.
+func ParseForward(spec string) (Forward, error) {
+	parts := splitForward(spec)
+	switch len(parts) {
+	case 3:
+		parts = append([]string{""}, parts...)
+	case 4:
+	default:
+		return Forward{}, fmt.Errorf("invalid port forward '%s', expected [bind_address:]port:host:hostport", spec)
+	}
+
+	for _, part := range parts[1:] {
+		if part == "" {
+			return Forward{}, fmt.Errorf("invalid port forward '%s', expected [bind_address:]port:host:hostport", spec)
+		}
+	}
+	return Forward{BindAddress: parts[0], Port: parts[1], Host: parts[2], HostPort: parts[3]}, nil
+}
+
+func splitForward(spec string) []string {
+	var (
+		parts    []string
+		current  string
+		brackets bool
+	)
+	for _, c := range spec {
+		switch {
+		case c == '[':
+			brackets = true
+		case c == ']':
+			brackets = false
+		case c == ':' && !brackets:
+			parts = append(parts, current)
+			current = ""
+		default:
+			current += string(c)
+		}
+	}
+	return append(parts, current)
+}
+
+func (f Forward) listenAddress() string {
+	bindAddress := f.BindAddress
+	if bindAddress == "" || bindAddress == "localhost" {
+		bindAddress = "127.0.0.1"
+	}
+	if bindAddress == "*" {
+		bindAddress = "0.0.0.0"
+	}
+	return net.JoinHostPort(bindAddress, f.Port)
+}
+
+func (f Forward) destinationAddress() string {
+	return net.JoinHostPort(f.Host, f.HostPort)
+}
+
+// forward sets up the agent and port forwarding of a session, and returns a
+// function that stops the port forwarding.
+func forward(client *ssh.Client, session *ssh.Session, options *SessionOptions) (stop func(), err error) {
+	var listeners []net.Listener
+	stop = func() {
+		for _, listener := range listeners {
+			IgnoreErrorFrom(listener.Close())
+		}
+	}
+
+	if options.AgentForwarding {
+		socket := os.Getenv("SSH_AUTH_SOCK")
+		if socket == "" {
+			return stop, errors.New("failed to forward the SSH agent: SSH_AUTH_SOCK is not set")
+		}
+		if err := agent.ForwardToRemote(client, socket); err != nil {
+			return stop, fmt.Errorf("failed to forward the SSH agent: %s", err)
+		}
+		if err := agent.RequestAgentForwarding(session); err != nil {
+			return stop, fmt.Errorf("failed to forward the SSH agent: %s", err)
+		}
+	}
+
+	for _, f := range options.LocalForwards {
+		listener, err := net.Listen("tcp", f.listenAddress())
+		if err != nil {
+			stop()
+			return stop, fmt.Errorf("failed to forward local port %s: %s", f.Port, err)
+		}
+		listeners = append(listeners, listener)
+		go acceptForwards(listener, f.destinationAddress(), client.Dial)
+	}
+
+	for _, f := range options.RemoteForwards {
+		listener, err := client.Listen("tcp", f.listenAddress())
+		if err != nil {
+			stop()
+			return stop, fmt.Errorf("failed to forward remote port %s: %s", f.Port, err)
+		}
+		listeners = append(listeners, listener)
+		go acceptForwards(listener, f.destinationAddress(), net.Dial)
+	}
+
+	return stop, nil
+}
+
+func acceptForwards(listener net.Listener, destination string, dial func(network string, address string) (net.Conn, error)) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go func(conn net.Conn) {
+			defer conn.Close()
+
+			destinationConn, err := dial("tcp", destination)
+			if err != nil {
+				return
+			}
+			defer destinationConn.Close()
+
+			go func() {
+				IgnoreErrorFrom(io.Copy(conn, destinationConn))
+			}()
+
+			IgnoreErrorFrom(io.Copy(destinationConn, conn))
+		}(conn)
+	}
+}
@@ -0,0 +1,31 @@
+package ssh_test
+
+import (
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseForward", func() {
+	It("should parse a forward without a bind address", func() {
+		Expect(ssh.ParseForward("8080:localhost:80")).To(Equal(ssh.Forward{Port: "8080", Host: "localhost", HostPort: "80"}))
+	})
+
+	It("should parse a forward with a bind address", func() {
+		Expect(ssh.ParseForward("*:8080:some-host:80")).To(Equal(ssh.Forward{BindAddress: "*", Port: "8080", Host: "some-host", HostPort: "80"}))
+	})
+
+	It("should parse IPv6 addresses in brackets", func() {
+		Expect(ssh.ParseForward("[::1]:8080:[fe80::1]:80")).To(Equal(ssh.Forward{BindAddress: "::1", Port: "8080", Host: "fe80::1", HostPort: "80"}))
+	})
+
+	Context("when the forward is invalid", func() {
+		It("should return an error", func() {
+			for _, spec := range []string{"8080", "8080:localhost", "8080::80", "a:b:c:d:e"} {
+				_, err := ssh.ParseForward(spec)
+				Expect(err).To(MatchError("invalid port forward '" + spec + "', expected [bind_address:]port:host:hostport"))
+			}
+		})
+	})
+})
@@ -2,6 +2,9 @@ package ssh
 
 import (
 	"bytes"
+	"context"
+	"crypto"
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -10,6 +13,7 @@ import (
 	"io"
 	"net"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/docker/pkg/term"
@@ -17,9 +21,21 @@ import (
 	"golang.org/x/crypto/ssh"
 )
 
+const (
+	KeyTypeRSA     = "rsa"
+	KeyTypeEd25519 = "ed25519"
+)
+
 type SSH struct {
 	Terminal      Terminal
 	WindowResizer WindowResizer
+	Tracer        Tracer
+	// KnownHosts verifies the host key of the VM. Any host key is accepted
+	// when it is nil.
+	KnownHosts *KnownHosts
+
+	mutex   sync.Mutex
+	clients map[string]*ssh.Client
 }
 
 //go:generate mockgen -package mocks -destination mocks/terminal.go github.com/pivotal-cf/pcfdev-cli/ssh Terminal
@@ -36,6 +52,11 @@ type WindowResizer interface {
 	StopResizing()
 }
 
+//go:generate mockgen -package mocks -destination mocks/tracer.go github.com/pivotal-cf/pcfdev-cli/ssh Tracer
+type Tracer interface {
+	TraceCommand(command string, duration time.Duration, output []byte, err error)
+}
+
 func (*SSH) GenerateAddress() (host string, port string, err error) {
 	conn, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
@@ -46,100 +67,197 @@ func (*SSH) GenerateAddress() (host string, port string, err error) {
 	return address[0], address[1], nil
 }
 
-func (s *SSH) GetSSHOutput(command string, addresses []SSHAddress, privateKey []byte, timeout time.Duration) (string, error) {
-	client, session, err := s.newSession(addresses, privateKey, timeout)
+func (s *SSH) GetSSHOutput(ctx context.Context, command string, addresses []SSHAddress, privateKey []byte, timeout time.Duration) (string, error) {
+	start := time.Now()
+	session, err := s.newSession(ctx, addresses, privateKey, timeout)
 	if err != nil {
+		s.trace(command, start, nil, err)
 		return "", err
 	}
-	defer client.Close()
 	defer session.Close()
 
-	output, err := session.CombinedOutput(command)
+	var output []byte
+	err = runUntilDone(ctx, session, func() error {
+		output, err = session.CombinedOutput(command)
+		return err
+	})
+	s.trace(command, start, output, err)
 	return string(output), err
 }
 
-func (s *SSH) RunSSHCommand(command string, addresses []SSHAddress, privateKey []byte, timeout time.Duration, stdout io.Writer, stderr io.Writer) (err error) {
-	client, session, err := s.newSession(addresses, privateKey, timeout)
+func (s *SSH) RunSSHCommand(ctx context.Context, command string, addresses []SSHAddress, privateKey []byte, timeout time.Duration, stdout io.Writer, stderr io.Writer) (err error) {
+	start := time.Now()
+	session, err := s.newSession(ctx, addresses, privateKey, timeout)
 	if err != nil {
+		s.trace(command, start, nil, err)
 		return err
 	}
-	defer client.Close()
 	defer session.Close()
 
 	session.Stdout = stdout
 	session.Stderr = stderr
 
-	return session.Run(command)
+	err = runUntilDone(ctx, session, func() error {
+		return session.Run(command)
+	})
+	s.trace(command, start, nil, err)
+	return err
 }
 
-func (s *SSH) StartSSHSession(addresses []SSHAddress, privateKey []byte, timeout time.Duration, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
-	client, session, err := s.newSession(addresses, privateKey, timeout)
+func (s *SSH) RunSSHCommandWithStdin(ctx context.Context, command string, addresses []SSHAddress, privateKey []byte, timeout time.Duration, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	start := time.Now()
+	session, err := s.newSession(ctx, addresses, privateKey, timeout)
 	if err != nil {
+		s.trace(command, start, nil, err)
 		return err
 	}
-	defer client.Close()
 	defer session.Close()
 
-	modes := ssh.TerminalModes{
-		ssh.ECHO:          1,
-		ssh.TTY_OP_ISPEED: 115200,
-		ssh.TTY_OP_OSPEED: 115200,
-	}
-
 	session.Stdin = stdin
 	session.Stdout = stdout
 	session.Stderr = stderr
 
-	stdinFd := s.Terminal.GetFdInfo(stdin)
-	stdoutFd := s.Terminal.GetFdInfo(stdout)
+	err = runUntilDone(ctx, session, func() error {
+		return session.Run(command)
+	})
+	s.trace(command, start, nil, err)
+	return err
+}
+
+// StartSSHSession starts a shell, or the command in options, on the VM, with
+// the forwarding that options asks for, and returns when it exits. A non-zero
+// exit status is returned as an *ExitError.
+func (s *SSH) StartSSHSession(ctx context.Context, addresses []SSHAddress, privateKey []byte, timeout time.Duration, options *SessionOptions, stdin io.Reader, stdout io.Writer, stderr io.Writer) (err error) {
+	if options == nil {
+		options = &SessionOptions{}
+	}
 
-	state, err := s.Terminal.SetRawTerminal(stdinFd)
+	start := time.Now()
+	command := options.Command
+	if command == "" {
+		command = "shell"
+	}
+	defer func() { s.trace(command, start, nil, err) }()
+
+	var (
+		client  *ssh.Client
+		session *ssh.Session
+	)
+	if options.AgentForwarding {
+		// The agent is offered to every session on a connection, so it is not
+		// forwarded over the connection that other sessions share.
+		if client, err = s.waitForSSH(ctx, addresses, privateKey, timeout); err != nil {
+			return err
+		}
+		defer client.Close()
+		session, err = client.NewSession()
+	} else {
+		client, session, err = s.newClientSession(ctx, addresses, privateKey, timeout)
+	}
 	if err != nil {
 		return err
 	}
-	defer s.Terminal.RestoreTerminal(stdinFd, state)
+	defer session.Close()
 
-	winSize, err := s.Terminal.GetWinSize(stdoutFd)
+	session.Stdin = stdin
+	session.Stdout = stdout
+	session.Stderr = stderr
+
+	stopForwarding, err := forward(client, session, options)
+	defer stopForwarding()
 	if err != nil {
 		return err
 	}
 
-	if err := session.RequestPty("xterm", int(winSize.Height), int(winSize.Width), modes); err != nil {
-		return err
+	if options.Command == "" || options.TTY {
+		stdinFd := s.Terminal.GetFdInfo(stdin)
+		stdoutFd := s.Terminal.GetFdInfo(stdout)
+
+		state, err := s.Terminal.SetRawTerminal(stdinFd)
+		if err != nil {
+			return err
+		}
+		defer s.Terminal.RestoreTerminal(stdinFd, state)
+
+		winSize, err := s.Terminal.GetWinSize(stdoutFd)
+		if err != nil {
+			return err
+		}
+
+		modes := ssh.TerminalModes{
+			ssh.ECHO:          1,
+			ssh.TTY_OP_ISPEED: 115200,
+			ssh.TTY_OP_OSPEED: 115200,
+		}
+		if err := session.RequestPty("xterm", int(winSize.Height), int(winSize.Width), modes); err != nil {
+			return err
+		}
+
+		s.WindowResizer.StartResizing(session)
+		defer s.WindowResizer.StopResizing()
 	}
 
-	s.WindowResizer.StartResizing(session)
-	defer s.WindowResizer.StopResizing()
+	if options.Command == "" {
+		if err := session.Shell(); err != nil {
+			return err
+		}
 
-	if err := session.Shell(); err != nil {
-		return err
+		return exitError(runUntilDone(ctx, session, session.Wait))
 	}
 
-	IgnoreErrorFrom(session.Wait())
-	return nil
+	return exitError(runUntilDone(ctx, session, func() error {
+		return session.Run(options.Command)
+	}))
 }
 
-func (s *SSH) WaitForSSH(addresses []SSHAddress, privateKey []byte, timeout time.Duration) error {
-	client, err := s.waitForSSH(addresses, privateKey, timeout)
-	if err == nil {
-		IgnoreErrorFrom(client.Close())
+func exitError(err error) error {
+	if exitErr, ok := err.(*ssh.ExitError); ok {
+		return &ExitError{Status: exitErr.ExitStatus()}
 	}
 	return err
 }
 
-func (s *SSH) GenerateKeypair() ([]byte, []byte, error) {
-	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
-	if err != nil {
-		return nil, nil, err
+func (s *SSH) WaitForSSH(ctx context.Context, addresses []SSHAddress, privateKey []byte, timeout time.Duration) error {
+	_, err := s.client(ctx, addresses, privateKey, timeout)
+	return err
+}
+
+// GenerateKeypair generates a keypair of keyType, which is KeyTypeRSA or
+// KeyTypeEd25519. The private key is PEM-encoded, in the OpenSSH format for
+// ed25519 keys so that OpenSSH clients can use it, and the public key is in
+// authorized_keys format.
+func (s *SSH) GenerateKeypair(keyType string) ([]byte, []byte, error) {
+	var (
+		cryptoPublicKey crypto.PublicKey
+		block           *pem.Block
+	)
+	switch keyType {
+	case KeyTypeRSA:
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, nil, err
+		}
+		cryptoPublicKey = rsaKey.Public()
+		block = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}
+	case KeyTypeEd25519:
+		publicKey, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		cryptoPublicKey = publicKey
+		if block, err = ssh.MarshalPrivateKey(ed25519Key, ""); err != nil {
+			return nil, nil, err
+		}
+	default:
+		return nil, nil, fmt.Errorf("unsupported key type '%s'", keyType)
 	}
 
 	encodedPrivateKey := new(bytes.Buffer)
-	marshaledPrivateKey := x509.MarshalPKCS1PrivateKey(privateKey)
-	if err = pem.Encode(encodedPrivateKey, &pem.Block{Type: "RSA PRIVATE KEY", Bytes: marshaledPrivateKey}); err != nil {
+	if err := pem.Encode(encodedPrivateKey, block); err != nil {
 		return nil, nil, err
 	}
 
-	publicKey, err := ssh.NewPublicKey(privateKey.Public())
+	publicKey, err := ssh.NewPublicKey(cryptoPublicKey)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -147,12 +265,23 @@ func (s *SSH) GenerateKeypair() ([]byte, []byte, error) {
 	return encodedPrivateKey.Bytes(), ssh.MarshalAuthorizedKey(publicKey), nil
 }
 
-func (s *SSH) WithSSHTunnel(remoteAddress string, sshAddresses []SSHAddress, privateKey []byte, timeout time.Duration, block func(forwardingAddress string)) error {
-	client, err := s.waitForSSH(sshAddresses, privateKey, timeout)
+// Dial connects to address from inside the VM, over the SSH connection to it.
+func (s *SSH) Dial(ctx context.Context, network string, address string, sshAddresses []SSHAddress, privateKey []byte, timeout time.Duration) (net.Conn, error) {
+	client, err := s.client(ctx, sshAddresses, privateKey, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return client.Dial(network, address)
+}
+
+func (s *SSH) WithSSHTunnel(ctx context.Context, remoteAddress string, sshAddresses []SSHAddress, privateKey []byte, timeout time.Duration, block func(forwardingAddress string)) (err error) {
+	start := time.Now()
+	defer func() { s.trace("tunnel to "+remoteAddress, start, nil, err) }()
+
+	client, err := s.client(ctx, sshAddresses, privateKey, timeout)
 	if err != nil {
 		return err
 	}
-	defer client.Close()
 
 	localListener, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
@@ -192,35 +321,55 @@ func (s *SSH) WithSSHTunnel(remoteAddress string, sshAddresses []SSHAddress, pri
 	return tunnelError
 }
 
-func (s *SSH) newSession(addresses []SSHAddress, privateKey []byte, timeout time.Duration) (*ssh.Client, *ssh.Session, error) {
-	client, err := s.waitForSSH(addresses, privateKey, timeout)
+func (s *SSH) trace(command string, start time.Time, output []byte, err error) {
+	if s.Tracer != nil {
+		s.Tracer.TraceCommand("ssh: "+command, time.Since(start), output, err)
+	}
+}
+
+// runUntilDone calls run, which uses session, and closes the session as soon
+// as ctx is done so that run returns the error of ctx.
+func runUntilDone(ctx context.Context, session *ssh.Session, run func() error) error {
+	stop := CloseWhenDone(ctx, session)
+	err := run()
+	stop()
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+func (s *SSH) newSession(ctx context.Context, addresses []SSHAddress, privateKey []byte, timeout time.Duration) (*ssh.Session, error) {
+	_, session, err := s.newClientSession(ctx, addresses, privateKey, timeout)
+	return session, err
+}
+
+func (s *SSH) newClientSession(ctx context.Context, addresses []SSHAddress, privateKey []byte, timeout time.Duration) (*ssh.Client, *ssh.Session, error) {
+	client, err := s.client(ctx, addresses, privateKey, timeout)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	session, err := client.NewSession()
 	if err != nil {
-		IgnoreErrorFrom(client.Close())
-		return nil, nil, err
+		// The connection may have dropped since it was checked, so try once
+		// more with a new one.
+		s.forget(client)
+		if client, err = s.client(ctx, addresses, privateKey, timeout); err != nil {
+			return nil, nil, err
+		}
+		session, err = client.NewSession()
 	}
-
-	return client, session, nil
+	return client, session, err
 }
 
-func (*SSH) waitForSSH(addresses []SSHAddress, privateKey []byte, timeout time.Duration) (*ssh.Client, error) {
+func (s *SSH) waitForSSH(ctx context.Context, addresses []SSHAddress, privateKey []byte, timeout time.Duration) (*ssh.Client, error) {
 	signer, err := ssh.ParsePrivateKey(privateKey)
 	if err != nil {
 		return nil, fmt.Errorf("could not parse private key: %s", err)
 	}
 
-	config := &ssh.ClientConfig{
-		User: "vcap",
-		Auth: []ssh.AuthMethod{
-			ssh.PublicKeys(signer),
-		},
-		Timeout: 10 * time.Second,
-	}
-
 	clientChan := make(chan *ssh.Client, len(addresses))
 	errorChan := make(chan error, len(addresses))
 	doneChan := make(chan bool)
@@ -229,6 +378,21 @@ func (*SSH) waitForSSH(addresses []SSHAddress, privateKey []byte, timeout time.D
 		go func(ip string, port string) {
 			var client *ssh.Client
 			var dialErr error
+			var hostKeyErr error
+			config := &ssh.ClientConfig{
+				User: "vcap",
+				Auth: []ssh.AuthMethod{
+					ssh.PublicKeys(signer),
+				},
+				HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+					if s.KnownHosts != nil {
+						hostKeyErr = s.KnownHosts.Check(hostname, key)
+					}
+					return hostKeyErr
+				},
+				Timeout: 10 * time.Second,
+			}
+
 			timeoutChan := time.After(timeout)
 			for {
 				select {
@@ -236,6 +400,10 @@ func (*SSH) waitForSSH(addresses []SSHAddress, privateKey []byte, timeout time.D
 					clientChan <- nil
 					errorChan <- fmt.Errorf("ssh connection timed out: %s", dialErr)
 					return
+				case <-ctx.Done():
+					clientChan <- nil
+					errorChan <- ctx.Err()
+					return
 				case <-doneChan:
 					return
 				default:
@@ -244,7 +412,12 @@ func (*SSH) waitForSSH(addresses []SSHAddress, privateKey []byte, timeout time.D
 						errorChan <- nil
 						return
 					}
-					time.Sleep(time.Second)
+					if hostKeyErr != nil {
+						clientChan <- nil
+						errorChan <- hostKeyErr
+						return
+					}
+					IgnoreErrorFrom(Sleep(ctx, time.Second))
 				}
 			}
 		}(address.IP, address.Port)
@@ -255,3 +428,20 @@ func (*SSH) waitForSSH(addresses []SSHAddress, privateKey []byte, timeout time.D
 	close(doneChan)
 	return client, err
 }
+
+// KeyType returns whether a PEM-encoded private key is a KeyTypeRSA or a
+// KeyTypeEd25519 key.
+func KeyType(privateKey []byte) (string, error) {
+	signer, err := ssh.ParsePrivateKey(privateKey)
+	if err != nil {
+		return "", fmt.Errorf("could not parse private key: %s", err)
+	}
+
+	switch signer.PublicKey().Type() {
+	case ssh.KeyAlgoRSA:
+		return KeyTypeRSA, nil
+	case ssh.KeyAlgoED25519:
+		return KeyTypeEd25519, nil
+	}
+	return "", fmt.Errorf("unsupported key type '%s'", signer.PublicKey().Type())
+}
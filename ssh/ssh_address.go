@@ -1,6 +1,34 @@
 package ssh
 
+import "github.com/pivotal-cf/pcfdev-cli/config"
+
 type SSHAddress struct {
 	IP   string
 	Port string
 }
+
+// SSHAddresses returns the addresses the SSH server of the VM can be reached
+// on: SSHPort on SSHIP, which is the loopback address VirtualBox forwards the
+// port to unless the provider says otherwise, and then the VM's own address
+// once it is known, as it is not when the VM is bridged and has yet to be
+// given one.
+func SSHAddresses(vmConfig *config.VMConfig) []SSHAddress {
+	sshIP := vmConfig.SSHIP
+	if sshIP == "" {
+		sshIP = "127.0.0.1"
+	}
+
+	addresses := []SSHAddress{
+		{
+			IP:   sshIP,
+			Port: vmConfig.SSHPort,
+		},
+	}
+	if vmConfig.IP != "" && vmConfig.IP != sshIP {
+		addresses = append(addresses, SSHAddress{
+			IP:   vmConfig.IP,
+			Port: "22",
+		})
+	}
+	return addresses
+}
@@ -0,0 +1,34 @@
+package ssh_test
+
+import (
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SSHAddresses", func() {
+	It("should return the forwarded port on the loopback address and the address of the VM", func() {
+		Expect(ssh.SSHAddresses(&config.VMConfig{IP: "192.168.11.11", SSHPort: "some-port"})).To(Equal([]ssh.SSHAddress{
+			{IP: "127.0.0.1", Port: "some-port"},
+			{IP: "192.168.11.11", Port: "22"},
+		}))
+	})
+
+	Context("when the address of the VM is not known yet", func() {
+		It("should return only the forwarded port", func() {
+			Expect(ssh.SSHAddresses(&config.VMConfig{SSHPort: "some-port"})).To(Equal([]ssh.SSHAddress{
+				{IP: "127.0.0.1", Port: "some-port"},
+			}))
+		})
+	})
+
+	Context("when the SSH port is reached on the address of the VM", func() {
+		It("should return that address once", func() {
+			Expect(ssh.SSHAddresses(&config.VMConfig{IP: "192.168.11.11", SSHIP: "192.168.11.11", SSHPort: "22"})).To(Equal([]ssh.SSHAddress{
+				{IP: "192.168.11.11", Port: "22"},
+			}))
+		})
+	})
+})
@@ -0,0 +1,38 @@
+package tracer
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pivotal-cf/pcfdev-cli/runner"
+)
+
+//go:generate mockgen -package mocks -destination mocks/cmd_runner.go github.com/pivotal-cf/pcfdev-cli/tracer CmdRunner
+type CmdRunner interface {
+	RunContext(ctx context.Context, command string, args ...string) (output []byte, err error)
+}
+
+// TracingCmdRunner traces every command run through CmdRunner, including each
+// VBoxManage invocation made by vboxdriver.VBoxDriver.
+type TracingCmdRunner struct {
+	CmdRunner CmdRunner
+	Tracer    *Tracer
+}
+
+func (r *TracingCmdRunner) Run(command string, args ...string) ([]byte, error) {
+	return r.RunContext(context.Background(), command, args...)
+}
+
+func (r *TracingCmdRunner) RunContext(ctx context.Context, command string, args ...string) ([]byte, error) {
+	start := time.Now()
+	output, err := r.CmdRunner.RunContext(ctx, command, args...)
+
+	tracedOutput := output
+	if executionErr, ok := err.(*runner.ExecutionError); ok {
+		tracedOutput = executionErr.Output
+	}
+	r.Tracer.TraceCommand(strings.Join(append([]string{command}, args...), " "), time.Since(start), tracedOutput, err)
+
+	return output, err
+}
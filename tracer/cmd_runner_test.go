@@ -0,0 +1,92 @@
+package tracer_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/runner"
+	"github.com/pivotal-cf/pcfdev-cli/tracer"
+	"github.com/pivotal-cf/pcfdev-cli/tracer/mocks"
+)
+
+var _ = Describe("TracingCmdRunner", func() {
+	var (
+		mockCtrl      *gomock.Controller
+		mockCmdRunner *mocks.MockCmdRunner
+		mockScrubber  *mocks.MockScrubber
+		buffer        *bytes.Buffer
+		cmdRunner     *tracer.TracingCmdRunner
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockCmdRunner = mocks.NewMockCmdRunner(mockCtrl)
+		mockScrubber = mocks.NewMockScrubber(mockCtrl)
+		buffer = &bytes.Buffer{}
+		cmdRunner = &tracer.TracingCmdRunner{
+			CmdRunner: mockCmdRunner,
+			Tracer: &tracer.Tracer{
+				Writer:   buffer,
+				Scrubber: mockScrubber,
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("#Run", func() {
+		It("should run the command and trace it", func() {
+			mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "showvminfo", "some-vm").Return([]byte("some-output"), nil)
+			mockScrubber.EXPECT().Scrub(gomock.Any()).Do(func(entry string) {
+				Expect(entry).To(ContainSubstring("\nVBoxManage showvminfo some-vm\nEXIT CODE: 0\n"))
+				Expect(entry).To(ContainSubstring("OUTPUT:\nsome-output\n"))
+			})
+
+			Expect(cmdRunner.Run("VBoxManage", "showvminfo", "some-vm")).To(Equal([]byte("some-output")))
+		})
+
+		Context("when the command fails", func() {
+			It("should trace the output of the failed command and return the error", func() {
+				executionErr := &runner.ExecutionError{
+					Command: "VBoxManage",
+					Args:    []string{"startvm", "some-vm"},
+					Output:  []byte("some-output"),
+					Err:     errors.New("some-error"),
+				}
+				mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "startvm", "some-vm").Return(nil, executionErr)
+				mockScrubber.EXPECT().Scrub(gomock.Any()).Do(func(entry string) {
+					Expect(entry).To(ContainSubstring("OUTPUT:\nsome-output\n"))
+				})
+
+				_, err := cmdRunner.Run("VBoxManage", "startvm", "some-vm")
+				Expect(err).To(BeIdenticalTo(executionErr))
+			})
+		})
+
+		Context("when tracing is disabled", func() {
+			It("should only run the command", func() {
+				cmdRunner.Tracer = nil
+				mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "--version").Return([]byte("some-output"), nil)
+
+				Expect(cmdRunner.Run("VBoxManage", "--version")).To(Equal([]byte("some-output")))
+			})
+		})
+	})
+
+	Describe("#RunContext", func() {
+		It("should run the command with the context", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			mockCmdRunner.EXPECT().RunContext(ctx, "VBoxManage", "--version").Return([]byte("some-output"), nil)
+			mockScrubber.EXPECT().Scrub(gomock.Any())
+
+			Expect(cmdRunner.RunContext(ctx, "VBoxManage", "--version")).To(Equal([]byte("some-output")))
+		})
+	})
+})
@@ -0,0 +1,94 @@
+package tracer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:generate mockgen -package mocks -destination mocks/scrubber.go github.com/pivotal-cf/pcfdev-cli/tracer Scrubber
+type Scrubber interface {
+	Scrub(information string) string
+}
+
+// Tracer writes a record of the external commands and HTTP requests made by
+// the plugin, in the spirit of CF_TRACE. Everything it writes is passed
+// through the Scrubber first. A nil Tracer traces nothing.
+type Tracer struct {
+	Writer   io.Writer
+	Scrubber Scrubber
+
+	mutex sync.Mutex
+}
+
+// New returns a Tracer for the value of PCFDEV_TRACE, which is either "true"
+// to trace to stdout or the path of a file to append to. It returns a nil
+// Tracer when tracing is disabled.
+func New(destination string, scrubber Scrubber) (*Tracer, error) {
+	switch strings.ToLower(destination) {
+	case "", "false":
+		return nil, nil
+	case "true":
+		return &Tracer{Writer: os.Stdout, Scrubber: scrubber}, nil
+	}
+
+	file, err := os.OpenFile(destination, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trace file: %s", err)
+	}
+	return &Tracer{Writer: file, Scrubber: scrubber}, nil
+}
+
+func (t *Tracer) TraceCommand(command string, duration time.Duration, output []byte, err error) {
+	if t == nil {
+		return
+	}
+
+	entry := fmt.Sprintf("COMMAND: [%s]\n%s\n", time.Now().Format(time.RFC3339), command)
+	entry += fmt.Sprintf("EXIT CODE: %d\n", exitStatus(err))
+	entry += fmt.Sprintf("DURATION: %s\n", duration)
+	if err != nil {
+		entry += fmt.Sprintf("ERROR: %s\n", err)
+	}
+	if len(output) > 0 {
+		entry += fmt.Sprintf("OUTPUT:\n%s\n", strings.TrimRight(string(output), "\n"))
+	}
+	t.write(entry)
+}
+
+func (t *Tracer) TraceRequest(method string, url string, duration time.Duration, status string, err error) {
+	if t == nil {
+		return
+	}
+
+	entry := fmt.Sprintf("REQUEST: [%s]\n%s %s\n", time.Now().Format(time.RFC3339), method, url)
+	if err != nil {
+		entry += fmt.Sprintf("ERROR: %s\n", err)
+	} else {
+		entry += fmt.Sprintf("STATUS: %s\n", status)
+	}
+	entry += fmt.Sprintf("DURATION: %s\n", duration)
+	t.write(entry)
+}
+
+func (t *Tracer) write(entry string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	fmt.Fprintln(t.Writer, t.Scrubber.Scrub(entry))
+}
+
+func exitStatus(err error) int {
+	if err == nil {
+		return 0
+	}
+	if exitErr, ok := err.(interface {
+		ExitStatus() int
+	}); ok {
+		return exitErr.ExitStatus()
+	}
+	return -1
+}
@@ -0,0 +1,160 @@
+package tracer_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/tracer"
+	"github.com/pivotal-cf/pcfdev-cli/tracer/mocks"
+)
+
+var _ = Describe("Tracer", func() {
+	var (
+		mockCtrl     *gomock.Controller
+		mockScrubber *mocks.MockScrubber
+		buffer       *bytes.Buffer
+		t            *tracer.Tracer
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockScrubber = mocks.NewMockScrubber(mockCtrl)
+		buffer = &bytes.Buffer{}
+		t = &tracer.Tracer{
+			Writer:   buffer,
+			Scrubber: mockScrubber,
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("#TraceCommand", func() {
+		It("should write the scrubbed command, exit code, duration and output", func() {
+			mockScrubber.EXPECT().Scrub(gomock.Any()).Do(func(entry string) {
+				Expect(entry).To(MatchRegexp(`^COMMAND: \[.+\]\nVBoxManage showvminfo some-vm\nEXIT CODE: 0\nDURATION: 2s\nOUTPUT:\nsome-output\n$`))
+			}).Return("some-scrubbed-entry")
+
+			t.TraceCommand("VBoxManage showvminfo some-vm", 2*time.Second, []byte("some-output\n"), nil)
+
+			Expect(buffer.String()).To(Equal("some-scrubbed-entry\n"))
+		})
+
+		Context("when the command fails", func() {
+			It("should write its exit code and the error", func() {
+				mockScrubber.EXPECT().Scrub(gomock.Any()).Do(func(entry string) {
+					Expect(entry).To(ContainSubstring("EXIT CODE: 3\n"))
+					Expect(entry).To(ContainSubstring("ERROR: some-error\nOUTPUT:\nsome-output\n"))
+				})
+
+				t.TraceCommand("VBoxManage startvm", time.Second, []byte("some-output"), &exitStatusError{3})
+			})
+		})
+
+		Context("when the command fails without an exit code", func() {
+			It("should write an exit code of -1", func() {
+				mockScrubber.EXPECT().Scrub(gomock.Any()).Do(func(entry string) {
+					Expect(entry).To(ContainSubstring("EXIT CODE: -1\nDURATION: 1s\nERROR: some-error\n"))
+				})
+
+				t.TraceCommand("ssh: some-command", time.Second, nil, errors.New("some-error"))
+			})
+		})
+	})
+
+	Describe("#TraceRequest", func() {
+		It("should write the scrubbed request, status and duration", func() {
+			mockScrubber.EXPECT().Scrub(gomock.Any()).Do(func(entry string) {
+				Expect(entry).To(MatchRegexp(`^REQUEST: \[.+\]\nGET some-host/some-path\nSTATUS: 200 OK\nDURATION: 1s\n$`))
+			}).Return("some-scrubbed-entry")
+
+			t.TraceRequest("GET", "some-host/some-path", time.Second, "200 OK", nil)
+
+			Expect(buffer.String()).To(Equal("some-scrubbed-entry\n"))
+		})
+
+		Context("when the request fails", func() {
+			It("should write the error", func() {
+				mockScrubber.EXPECT().Scrub(gomock.Any()).Do(func(entry string) {
+					Expect(entry).To(ContainSubstring("ERROR: some-error\n"))
+					Expect(entry).NotTo(ContainSubstring("STATUS"))
+				})
+
+				t.TraceRequest("GET", "some-host/some-path", time.Second, "", errors.New("some-error"))
+			})
+		})
+	})
+
+	Context("when the tracer is nil", func() {
+		It("should not trace anything", func() {
+			var nilTracer *tracer.Tracer
+			nilTracer.TraceCommand("some-command", time.Second, nil, nil)
+			nilTracer.TraceRequest("GET", "some-url", time.Second, "200 OK", nil)
+		})
+	})
+
+	Describe(".New", func() {
+		var tmpDir string
+
+		BeforeEach(func() {
+			var err error
+			tmpDir, err = ioutil.TempDir("", "pcfdev-tracer")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(tmpDir)).To(Succeed())
+		})
+
+		It("should return a tracer that appends to the given file", func() {
+			path := filepath.Join(tmpDir, "trace.log")
+			Expect(ioutil.WriteFile(path, []byte("some-previous-entry\n"), 0600)).To(Succeed())
+
+			t, err := tracer.New(path, mockScrubber)
+			Expect(err).NotTo(HaveOccurred())
+
+			mockScrubber.EXPECT().Scrub(gomock.Any()).Return("some-scrubbed-entry")
+			t.TraceRequest("GET", "some-url", time.Second, "200 OK", nil)
+
+			Expect(ioutil.ReadFile(path)).To(Equal([]byte("some-previous-entry\nsome-scrubbed-entry\n")))
+		})
+
+		It("should trace to stdout when the destination is true", func() {
+			t, err := tracer.New("true", mockScrubber)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t.Writer).To(Equal(os.Stdout))
+		})
+
+		It("should return a nil tracer when tracing is disabled", func() {
+			Expect(tracer.New("", mockScrubber)).To(BeNil())
+			Expect(tracer.New("false", mockScrubber)).To(BeNil())
+		})
+
+		Context("when the file cannot be opened", func() {
+			It("should return an error", func() {
+				_, err := tracer.New(filepath.Join(tmpDir, "some-missing-dir", "trace.log"), mockScrubber)
+				Expect(err).To(MatchError(ContainSubstring("failed to open trace file:")))
+			})
+		})
+	})
+})
+
+type exitStatusError struct {
+	status int
+}
+
+func (e *exitStatusError) Error() string {
+	return "some-error"
+}
+
+func (e *exitStatusError) ExitStatus() int {
+	return e.status
+}
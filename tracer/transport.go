@@ -0,0 +1,32 @@
+package tracer
+
+import (
+	"net/http"
+	"time"
+)
+
+// Transport traces the method, host, path and response status of each
+// request. The query string, headers and bodies are never traced, since they
+// carry Pivotal Network passwords and API tokens.
+type Transport struct {
+	Transport http.RoundTripper
+	Tracer    *Tracer
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	start := time.Now()
+	resp, err := transport.RoundTrip(req)
+
+	var status string
+	if resp != nil {
+		status = resp.Status
+	}
+	t.Tracer.TraceRequest(req.Method, req.URL.Host+req.URL.Path, time.Since(start), status, err)
+
+	return resp, err
+}
@@ -0,0 +1,70 @@
+package tracer_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/tracer"
+	"github.com/pivotal-cf/pcfdev-cli/tracer/mocks"
+)
+
+var _ = Describe("Transport", func() {
+	var (
+		mockCtrl     *gomock.Controller
+		mockScrubber *mocks.MockScrubber
+		server       *httptest.Server
+		client       *http.Client
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockScrubber = mocks.NewMockScrubber(mockCtrl)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		}))
+		client = &http.Client{
+			Transport: &tracer.Transport{
+				Tracer: &tracer.Tracer{
+					Writer:   &bytes.Buffer{},
+					Scrubber: mockScrubber,
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+		mockCtrl.Finish()
+	})
+
+	Describe("#RoundTrip", func() {
+		It("should trace the request without its query string", func() {
+			mockScrubber.EXPECT().Scrub(gomock.Any()).Do(func(entry string) {
+				Expect(entry).To(ContainSubstring(fmt.Sprintf("\nGET %s/some-path\nSTATUS: 418 I'm a teapot\n", server.Listener.Addr())))
+				Expect(entry).NotTo(ContainSubstring("some-password"))
+			})
+
+			resp, err := client.Get(server.URL + "/some-path?password=some-password")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusTeapot))
+			resp.Body.Close()
+		})
+
+		Context("when the request fails", func() {
+			It("should trace the error", func() {
+				server.Close()
+				mockScrubber.EXPECT().Scrub(gomock.Any()).Do(func(entry string) {
+					Expect(entry).To(ContainSubstring("ERROR: "))
+				})
+
+				_, err := client.Get(server.URL + "/some-path")
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+})
@@ -0,0 +1,1004 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pivotal-cf/pcfdev-cli/network"
+	"github.com/pivotal-cf/pcfdev-cli/vboxdriver"
+)
+
+// Driver is an in-memory stand-in for vboxdriver.VBoxDriver. It keeps track of
+// registered VMs, their power state, disks, host-only interfaces, bridged
+// adapters, NAT networks and NAT rules, and answers VBoxManage invocations from that state instead of shelling out.
+type Driver struct {
+	VBoxVersion vboxdriver.VBoxDriverVersion
+
+	mutex             sync.Mutex
+	vms               map[string]*VM
+	disks             []string
+	interfaces        []*network.Interface
+	bridgedInterfaces []*network.Interface
+	natNetworks       []*network.Interface
+	natNetworkRules   map[string]map[string]string
+}
+
+type VM struct {
+	Name              string
+	BaseDirectory     string
+	State             string
+	CPUs              int
+	Memory            uint64
+	Disk              string
+	HostOnlyInterface string
+	BridgedInterface  string
+	NATNetwork        string
+	DNSProxy          bool
+	ForwardedPorts    map[string]ForwardedPort
+}
+
+type ForwardedPort struct {
+	HostPort  string
+	GuestPort string
+}
+
+func NewDriver() *Driver {
+	return &Driver{
+		VBoxVersion:     vboxdriver.VBoxDriverVersion{Major: 5, Minor: 1, Build: 22},
+		vms:             map[string]*VM{},
+		natNetworkRules: map[string]map[string]string{},
+	}
+}
+
+func (d *Driver) VBoxManage(arg ...string) (output []byte, err error) {
+	if len(arg) == 0 {
+		return nil, d.usageError(arg)
+	}
+
+	switch arg[0] {
+	case "--version":
+		return []byte(fmt.Sprintf("%d.%d.%dr0\n", d.VBoxVersion.Major, d.VBoxVersion.Minor, d.VBoxVersion.Build)), nil
+	case "list":
+		return d.list(arg)
+	case "showvminfo":
+		if len(arg) < 2 {
+			return nil, d.usageError(arg)
+		}
+		return d.showVMInfo(arg[1])
+	case "createvm":
+		name, basefolder := flagValue(arg, "--name"), flagValue(arg, "--basefolder")
+		if name == "" {
+			return nil, d.usageError(arg)
+		}
+		return nil, d.CreateVM(name, basefolder)
+	case "startvm":
+		if len(arg) < 2 {
+			return nil, d.usageError(arg)
+		}
+		return nil, d.StartVM(arg[1])
+	case "controlvm":
+		return d.controlVM(arg)
+	case "modifyvm":
+		return d.modifyVM(arg)
+	case "unregistervm":
+		if len(arg) < 2 {
+			return nil, d.usageError(arg)
+		}
+		return nil, d.DestroyVM(context.Background(), arg[1])
+	case "storagectl":
+		if len(arg) < 2 {
+			return nil, d.usageError(arg)
+		}
+		return nil, d.modifyStoppedVM(arg[1], func(*VM) error { return nil })
+	case "storageattach":
+		if len(arg) < 2 {
+			return nil, d.usageError(arg)
+		}
+		return nil, d.AttachDisk(arg[1], flagValue(arg, "--medium"))
+	case "clonemedium":
+		if len(arg) < 4 {
+			return nil, d.usageError(arg)
+		}
+		return nil, d.registerDisk(arg[3])
+	case "closemedium":
+		if len(arg) < 3 {
+			return nil, d.usageError(arg)
+		}
+		return nil, d.DeleteDisk(arg[2])
+	case "hostonlyif":
+		return d.hostOnlyIf(arg)
+	case "hostonlynet":
+		return d.hostOnlyNet(arg)
+	case "natnetwork":
+		return d.natNetwork(arg)
+	}
+
+	return nil, d.usageError(arg)
+}
+
+func (d *Driver) StartVM(vmName string) error {
+	return d.transition(vmName, []string{vboxdriver.StateStopped, vboxdriver.StateAborted, vboxdriver.StateSaved}, vboxdriver.StateRunning)
+}
+
+func (d *Driver) StopVM(ctx context.Context, vmName string) error {
+	return d.transition(vmName, []string{vboxdriver.StateRunning}, vboxdriver.StateStopped)
+}
+
+func (d *Driver) PowerOffVM(vmName string) error {
+	return d.transition(vmName, []string{vboxdriver.StateRunning, vboxdriver.StatePaused}, vboxdriver.StateStopped)
+}
+
+func (d *Driver) SuspendVM(ctx context.Context, vmName string) error {
+	return d.transition(vmName, []string{vboxdriver.StateRunning, vboxdriver.StatePaused}, vboxdriver.StateSaved)
+}
+
+func (d *Driver) ResumeVM(vmName string) error {
+	return d.transition(vmName, []string{vboxdriver.StatePaused}, vboxdriver.StateRunning)
+}
+
+// SetState forces a VM into the given state. It stands in for things that
+// happen outside of the plugin, like a user pausing the VM from the VirtualBox
+// GUI or the VM crashing.
+func (d *Driver) SetState(vmName string, state string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	vm, err := d.vm(vmName)
+	if err != nil {
+		return err
+	}
+	vm.State = state
+	return nil
+}
+
+func (d *Driver) DestroyVM(ctx context.Context, vmName string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	vm, err := d.vm(vmName)
+	if err != nil {
+		return err
+	}
+	if vm.State == vboxdriver.StateRunning || vm.State == vboxdriver.StatePaused {
+		return d.lockedError(vmName)
+	}
+
+	if vm.Disk != "" {
+		d.removeDisk(vm.Disk)
+	}
+	delete(d.vms, vmName)
+	return nil
+}
+
+func (d *Driver) VMExists(vmName string) (exists bool, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	_, exists = d.vms[vmName]
+	return exists, nil
+}
+
+func (d *Driver) VMState(vmName string) (string, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	vm, err := d.vm(vmName)
+	if err != nil {
+		return "", err
+	}
+	return vm.State, nil
+}
+
+func (d *Driver) VMs() (vms []string, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.vmNames(func(*VM) bool { return true }), nil
+}
+
+func (d *Driver) RunningVMs() (vms []string, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.vmNames(func(vm *VM) bool { return vm.State == vboxdriver.StateRunning }), nil
+}
+
+func (d *Driver) CreateVM(vmName string, baseDirectory string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if _, exists := d.vms[vmName]; exists {
+		return fmt.Errorf("VBoxManage: error: Machine settings file '%s' already exists", filepath.Join(baseDirectory, vmName, vmName+".vbox"))
+	}
+
+	d.vms[vmName] = &VM{
+		Name:           vmName,
+		BaseDirectory:  baseDirectory,
+		State:          vboxdriver.StateStopped,
+		CPUs:           1,
+		Memory:         128,
+		ForwardedPorts: map[string]ForwardedPort{},
+	}
+	return nil
+}
+
+func (d *Driver) SetCPUs(vmName string, cpuNumber int) error {
+	return d.modifyStoppedVM(vmName, func(vm *VM) error {
+		vm.CPUs = cpuNumber
+		return nil
+	})
+}
+
+func (d *Driver) SetMemory(vmName string, memory uint64) error {
+	return d.modifyStoppedVM(vmName, func(vm *VM) error {
+		vm.Memory = memory
+		return nil
+	})
+}
+
+func (d *Driver) UseDNSProxy(vmName string) error {
+	return d.modifyStoppedVM(vmName, func(vm *VM) error {
+		vm.DNSProxy = true
+		return nil
+	})
+}
+
+func (d *Driver) GetMemory(vmName string) (uint64, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	vm, err := d.vm(vmName)
+	if err != nil {
+		return uint64(0), err
+	}
+	return vm.Memory, nil
+}
+
+func (d *Driver) VMInfo(vmName string) (*vboxdriver.VMInfo, error) {
+	output, err := d.showVMInfo(vmName)
+	if err != nil {
+		return nil, err
+	}
+	return vboxdriver.ParseVMInfo(output)
+}
+
+func (d *Driver) AttachDisk(vmName string, diskPath string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if !d.hasDisk(diskPath) {
+		return fmt.Errorf("VBoxManage: error: Could not find file for the medium '%s'", diskPath)
+	}
+
+	return d.modifyStoppedVMLocked(vmName, func(vm *VM) error {
+		vm.Disk = diskPath
+		return nil
+	})
+}
+
+func (d *Driver) CloneDisk(ctx context.Context, src string, dest string) error {
+	return d.registerDisk(dest)
+}
+
+func (d *Driver) DeleteDisk(diskPath string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.removeDisk(diskPath)
+	return nil
+}
+
+func (d *Driver) Disks() (disks []string, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return append([]string{}, d.disks...), nil
+}
+
+func (d *Driver) CreateHostOnlyInterface(ip string) (interfaceName string, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	index := len(d.interfaces)
+	for d.hasInterface(fmt.Sprintf("vboxnet%d", index)) {
+		index++
+	}
+	iface := &network.Interface{
+		Name:            fmt.Sprintf("vboxnet%d", index),
+		IP:              ip,
+		HardwareAddress: fmt.Sprintf("0a:00:27:00:00:%02x", index),
+		Exists:          true,
+	}
+	d.interfaces = append(d.interfaces, iface)
+	return iface.Name, nil
+}
+
+// AddHostOnlyInterface registers an interface that already exists on the host,
+// keeping its name and hardware address.
+func (d *Driver) AddHostOnlyInterface(iface *network.Interface) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	copied := *iface
+	copied.Exists = true
+	d.interfaces = append(d.interfaces, &copied)
+}
+
+func (d *Driver) ConfigureHostOnlyInterface(interfaceName string, ip string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for _, iface := range d.interfaces {
+		if iface.Name == interfaceName {
+			iface.IP = ip
+			return nil
+		}
+	}
+	return fmt.Errorf("VBoxManage: error: The host network interface named '%s' could not be found", interfaceName)
+}
+
+func (d *Driver) GetHostOnlyInterfaces() (interfaces []*network.Interface, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	interfaces = make([]*network.Interface, len(d.interfaces))
+	for i, iface := range d.interfaces {
+		copied := *iface
+		interfaces[i] = &copied
+	}
+	return interfaces, nil
+}
+
+// Interfaces reports the host-only interfaces as host network interfaces, so
+// that the fake can also stand in for the host network in address.Picker.
+func (d *Driver) Interfaces() (interfaces []*network.Interface, err error) {
+	return d.GetHostOnlyInterfaces()
+}
+
+// Routes reports no host routes, as the fake host is only connected to its
+// host-only interfaces.
+func (d *Driver) Routes() (routes []string, err error) {
+	return []string{}, nil
+}
+
+func (d *Driver) IsInterfaceInUse(interfaceName string) (bool, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for _, vm := range d.vms {
+		if vm.HostOnlyInterface == interfaceName || vm.NATNetwork == interfaceName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (d *Driver) AttachNetworkInterface(interfaceName string, vmName string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.modifyStoppedVMLocked(vmName, func(vm *VM) error {
+		vm.HostOnlyInterface, vm.BridgedInterface, vm.NATNetwork = interfaceName, "", ""
+		return nil
+	})
+}
+
+// AddBridgedInterface registers a host adapter that VMs can be bridged to.
+func (d *Driver) AddBridgedInterface(iface *network.Interface) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	copied := *iface
+	copied.Exists = true
+	d.bridgedInterfaces = append(d.bridgedInterfaces, &copied)
+}
+
+func (d *Driver) GetBridgedInterfaces() (interfaces []*network.Interface, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return copyInterfaces(d.bridgedInterfaces), nil
+}
+
+func (d *Driver) AttachBridgedInterface(interfaceName string, vmName string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.modifyStoppedVMLocked(vmName, func(vm *VM) error {
+		vm.HostOnlyInterface, vm.BridgedInterface, vm.NATNetwork = "", interfaceName, ""
+		return nil
+	})
+}
+
+// AddNATNetwork registers a NAT network that already exists, keeping its
+// name.
+func (d *Driver) AddNATNetwork(natNetwork *network.Interface) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	copied := *natNetwork
+	copied.Exists = true
+	d.natNetworks = append(d.natNetworks, &copied)
+}
+
+func (d *Driver) GetNATNetworks() (networks []*network.Interface, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return copyInterfaces(d.natNetworks), nil
+}
+
+func (d *Driver) CreateNATNetwork(ip string) (networkName string, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	name := vboxdriver.NATNetworkName(ip)
+	for _, natNetwork := range d.natNetworks {
+		if natNetwork.Name == name {
+			return "", fmt.Errorf("VBoxManage: error: NATNetwork server already exists")
+		}
+	}
+	d.natNetworks = append(d.natNetworks, &network.Interface{Name: name, IP: ip, Exists: true})
+	return name, nil
+}
+
+func (d *Driver) ConfigureNATNetwork(networkName string, ip string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for _, natNetwork := range d.natNetworks {
+		if natNetwork.Name == networkName {
+			natNetwork.IP = ip
+			return nil
+		}
+	}
+	return fmt.Errorf("VBoxManage: error: Failed to find NAT network '%s'", networkName)
+}
+
+func (d *Driver) AttachNATNetwork(networkName string, vmName string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.modifyStoppedVMLocked(vmName, func(vm *VM) error {
+		vm.HostOnlyInterface, vm.BridgedInterface, vm.NATNetwork = "", "", networkName
+		return nil
+	})
+}
+
+func (d *Driver) ForwardNATNetworkPort(networkName string, ruleName string, hostPort string, guestIP string, guestPort string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	delete(d.natNetworkRules[networkName], ruleName)
+	return d.addNATNetworkRuleLocked(networkName, fmt.Sprintf("%s:tcp:[127.0.0.1]:%s:[%s]:%s", ruleName, hostPort, guestIP, guestPort))
+}
+
+// NATNetworkPortForwards returns the port forwarding rules of a NAT network,
+// keyed by rule name, in the form that VBoxManage takes them.
+func (d *Driver) NATNetworkPortForwards(networkName string) map[string]string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	rules := map[string]string{}
+	for name, rule := range d.natNetworkRules[networkName] {
+		rules[name] = rule
+	}
+	return rules
+}
+
+func (d *Driver) addNATNetworkRuleLocked(networkName string, rule string) error {
+	found := false
+	for _, natNetwork := range d.natNetworks {
+		found = found || natNetwork.Name == networkName
+	}
+	if !found {
+		return fmt.Errorf("VBoxManage: error: Failed to find NAT network '%s'", networkName)
+	}
+
+	ruleName := rule[:strings.Index(rule, ":")]
+	if _, exists := d.natNetworkRules[networkName][ruleName]; exists {
+		return fmt.Errorf("VBoxManage: error: A NAT rule of this name already exists")
+	}
+	if d.natNetworkRules[networkName] == nil {
+		d.natNetworkRules[networkName] = map[string]string{}
+	}
+	d.natNetworkRules[networkName][ruleName] = rule
+	return nil
+}
+
+func (d *Driver) ForwardPort(vmName string, ruleName string, hostPort string, guestPort string) error {
+	return d.modifyStoppedVM(vmName, func(vm *VM) error {
+		if _, exists := vm.ForwardedPorts[ruleName]; exists {
+			return fmt.Errorf("VBoxManage: error: A NAT rule of this name already exists")
+		}
+		vm.ForwardedPorts[ruleName] = ForwardedPort{HostPort: hostPort, GuestPort: guestPort}
+		return nil
+	})
+}
+
+func (d *Driver) GetHostForwardPort(vmName string, ruleName string) (port string, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	vm, err := d.vm(vmName)
+	if err != nil {
+		return "", err
+	}
+	if forwardedPort, exists := vm.ForwardedPorts[ruleName]; exists {
+		return forwardedPort.HostPort, nil
+	}
+	return "", fmt.Errorf("could not find forwarded port")
+}
+
+func (d *Driver) Version() (version *vboxdriver.VBoxDriverVersion, err error) {
+	v := d.VBoxVersion
+	return &v, nil
+}
+
+func (d *Driver) NetworkModel() (string, error) {
+	return vboxdriver.NetworkModelForVersion(&d.VBoxVersion), nil
+}
+
+func (d *Driver) transition(vmName string, from []string, to string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	vm, err := d.vm(vmName)
+	if err != nil {
+		return err
+	}
+	for _, state := range from {
+		if vm.State == state {
+			vm.State = to
+			return nil
+		}
+	}
+	return fmt.Errorf("VBoxManage: error: Invalid machine state: %s", vm.State)
+}
+
+func (d *Driver) modifyStoppedVM(vmName string, modify func(vm *VM) error) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.modifyStoppedVMLocked(vmName, modify)
+}
+
+func (d *Driver) modifyStoppedVMLocked(vmName string, modify func(vm *VM) error) error {
+	vm, err := d.vm(vmName)
+	if err != nil {
+		return err
+	}
+	if vm.State == vboxdriver.StateRunning || vm.State == vboxdriver.StatePaused || vm.State == vboxdriver.StateSaved {
+		return d.lockedError(vmName)
+	}
+	return modify(vm)
+}
+
+func (d *Driver) vm(vmName string) (*VM, error) {
+	vm, exists := d.vms[vmName]
+	if !exists {
+		return nil, fmt.Errorf("VBoxManage: error: Could not find a registered machine named '%s'", vmName)
+	}
+	return vm, nil
+}
+
+func (d *Driver) vmNames(include func(*VM) bool) []string {
+	names := []string{}
+	for name, vm := range d.vms {
+		if include(vm) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (d *Driver) registerDisk(diskPath string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.hasDisk(diskPath) {
+		return fmt.Errorf("VBoxManage: error: Cannot register the hard disk '%s' because a hard disk with that location already exists", diskPath)
+	}
+	d.disks = append(d.disks, diskPath)
+	return nil
+}
+
+func (d *Driver) hasDisk(diskPath string) bool {
+	for _, disk := range d.disks {
+		if disk == diskPath {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Driver) removeDisk(diskPath string) {
+	for i, disk := range d.disks {
+		if disk == diskPath {
+			d.disks = append(d.disks[:i], d.disks[i+1:]...)
+			return
+		}
+	}
+}
+
+func (d *Driver) hasInterface(interfaceName string) bool {
+	for _, iface := range d.interfaces {
+		if iface.Name == interfaceName {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Driver) lockedError(vmName string) error {
+	return fmt.Errorf("VBoxManage: error: The machine '%s' is already locked for a session (or being unlocked)", vmName)
+}
+
+func (d *Driver) usageError(arg []string) error {
+	return fmt.Errorf("VBoxManage: error: unsupported command: %s", strings.Join(arg, " "))
+}
+
+func (d *Driver) list(arg []string) ([]byte, error) {
+	if len(arg) < 2 {
+		return nil, d.usageError(arg)
+	}
+
+	var output []string
+	switch arg[1] {
+	case "vms":
+		vms, _ := d.VMs()
+		for _, vm := range vms {
+			output = append(output, fmt.Sprintf(`"%s" {%s}`, vm, uuid(vm)))
+			if len(arg) > 2 && arg[2] == "--long" {
+				if attachment, name := d.vmAttachment(vm); attachment != "" {
+					output = append(output, fmt.Sprintf("NIC 2:           MAC: 080027000000, Attachment: %s '%s', Cable connected: on", attachment, name))
+				}
+			}
+		}
+	case "runningvms":
+		vms, _ := d.RunningVMs()
+		for _, vm := range vms {
+			output = append(output, fmt.Sprintf(`"%s" {%s}`, vm, uuid(vm)))
+		}
+	case "hostonlyifs":
+		interfaces, _ := d.GetHostOnlyInterfaces()
+		for _, iface := range interfaces {
+			output = append(output,
+				"Name:            "+iface.Name,
+				"IPAddress:       "+iface.IP,
+				"NetworkMask:     255.255.255.0",
+				"HardwareAddress: "+iface.HardwareAddress,
+				"",
+			)
+		}
+	case "hostonlynets":
+		interfaces, _ := d.GetHostOnlyInterfaces()
+		for _, iface := range interfaces {
+			lowerIP, upperIP := vboxdriver.HostOnlyNetworkRange(iface.IP)
+			output = append(output,
+				"Name:            "+iface.Name,
+				"NetworkMask:     255.255.255.0",
+				"LowerIP:         "+lowerIP,
+				"UpperIP:         "+upperIP,
+				"",
+			)
+		}
+	case "bridgedifs":
+		interfaces, _ := d.GetBridgedInterfaces()
+		for _, iface := range interfaces {
+			output = append(output,
+				"Name:            "+iface.Name,
+				"IPAddress:       "+iface.IP,
+				"NetworkMask:     255.255.255.0",
+				"HardwareAddress: "+iface.HardwareAddress,
+				"Status:          Up",
+				"",
+			)
+		}
+	case "natnets":
+		natNetworks, _ := d.GetNATNetworks()
+		for _, natNetwork := range natNetworks {
+			output = append(output,
+				"NetworkName:    "+natNetwork.Name,
+				"IP:             "+natNetwork.IP,
+				"Network:        "+natNetwork.IP[:strings.LastIndex(natNetwork.IP, ".")]+".0/24",
+				"Enabled:        Yes",
+				"",
+			)
+		}
+	case "hdds":
+		disks, _ := d.Disks()
+		for _, disk := range disks {
+			output = append(output, "UUID:           "+uuid(disk), "Location:       "+disk, "")
+		}
+	default:
+		return nil, d.usageError(arg)
+	}
+
+	return []byte(strings.Join(output, "\n") + "\n"), nil
+}
+
+func (d *Driver) showVMInfo(vmName string) ([]byte, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	vm, err := d.vm(vmName)
+	if err != nil {
+		return nil, err
+	}
+
+	output := []string{
+		fmt.Sprintf(`name="%s"`, vm.Name),
+		fmt.Sprintf("memory=%d", vm.Memory),
+		fmt.Sprintf("cpus=%d", vm.CPUs),
+		fmt.Sprintf(`VMState="%s"`, vm.State),
+		`nic1="nat"`,
+	}
+	switch {
+	case vm.BridgedInterface != "":
+		output = append(output, `nic2="bridged"`, fmt.Sprintf(`bridgeadapter2="%s"`, vm.BridgedInterface))
+	case vm.NATNetwork != "":
+		output = append(output, `nic2="natnetwork"`, fmt.Sprintf(`nat-network2="%s"`, vm.NATNetwork))
+	case vm.HostOnlyInterface != "":
+		if vboxdriver.NetworkModelForVersion(&d.VBoxVersion) == network.ModelHostOnlyNetwork {
+			output = append(output, `nic2="hostonlynet"`, fmt.Sprintf(`hostonly-network2="%s"`, vm.HostOnlyInterface))
+		} else {
+			output = append(output, `nic2="hostonly"`, fmt.Sprintf(`hostonlyadapter2="%s"`, vm.HostOnlyInterface))
+		}
+	}
+	if vm.Disk != "" {
+		output = append(output, fmt.Sprintf(`"SATA-0-0"="%s"`, vm.Disk))
+	}
+
+	rules := []string{}
+	for rule := range vm.ForwardedPorts {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+	for i, rule := range rules {
+		port := vm.ForwardedPorts[rule]
+		output = append(output, fmt.Sprintf(`Forwarding(%d)="%s,tcp,127.0.0.1,%s,,%s"`, i, rule, port.HostPort, port.GuestPort))
+	}
+
+	return []byte(strings.Join(output, "\n") + "\n"), nil
+}
+
+func (d *Driver) controlVM(arg []string) ([]byte, error) {
+	if len(arg) < 3 {
+		return nil, d.usageError(arg)
+	}
+
+	switch arg[2] {
+	case "acpipowerbutton":
+		return nil, d.StopVM(context.Background(), arg[1])
+	case "poweroff":
+		return nil, d.PowerOffVM(arg[1])
+	case "savestate":
+		return nil, d.SuspendVM(context.Background(), arg[1])
+	case "resume":
+		return nil, d.ResumeVM(arg[1])
+	case "pause":
+		return nil, d.transition(arg[1], []string{vboxdriver.StateRunning}, vboxdriver.StatePaused)
+	}
+	return nil, d.usageError(arg)
+}
+
+func (d *Driver) modifyVM(arg []string) ([]byte, error) {
+	if len(arg) < 2 {
+		return nil, d.usageError(arg)
+	}
+	vmName := arg[1]
+
+	if value := flagValue(arg, "--cpus"); value != "" {
+		cpus, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, d.usageError(arg)
+		}
+		if err := d.SetCPUs(vmName, cpus); err != nil {
+			return nil, err
+		}
+	}
+	if value := flagValue(arg, "--memory"); value != "" {
+		memory, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, d.usageError(arg)
+		}
+		if err := d.SetMemory(vmName, memory); err != nil {
+			return nil, err
+		}
+	}
+	for _, flag := range []string{"--hostonlyadapter2", "--host-only-net2"} {
+		if value := flagValue(arg, flag); value != "" {
+			if err := d.AttachNetworkInterface(value, vmName); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if value := flagValue(arg, "--bridgeadapter2"); value != "" {
+		if err := d.AttachBridgedInterface(value, vmName); err != nil {
+			return nil, err
+		}
+	}
+	if value := flagValue(arg, "--nat-network2"); value != "" {
+		if err := d.AttachNATNetwork(value, vmName); err != nil {
+			return nil, err
+		}
+	}
+	if value := flagValue(arg, "--natpf1"); value != "" {
+		rule := strings.Split(value, ",")
+		if len(rule) != 6 {
+			return nil, d.usageError(arg)
+		}
+		if err := d.ForwardPort(vmName, rule[0], rule[3], rule[5]); err != nil {
+			return nil, err
+		}
+	}
+	if flagValue(arg, "--natdnshostresolver1") == "on" {
+		if err := d.UseDNSProxy(vmName); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, d.modifyStoppedVM(vmName, func(*VM) error { return nil })
+}
+
+func (d *Driver) hostOnlyIf(arg []string) ([]byte, error) {
+	if len(arg) < 2 {
+		return nil, d.usageError(arg)
+	}
+
+	switch arg[1] {
+	case "create":
+		name, err := d.CreateHostOnlyInterface("")
+		if err != nil {
+			return nil, err
+		}
+		return []byte(fmt.Sprintf("Interface '%s' was successfully created\n", name)), nil
+	case "ipconfig":
+		if len(arg) < 3 {
+			return nil, d.usageError(arg)
+		}
+		return nil, d.ConfigureHostOnlyInterface(arg[2], flagValue(arg, "--ip"))
+	}
+	return nil, d.usageError(arg)
+}
+
+// hostOnlyNet keeps host-only networks in the same list as host-only
+// interfaces, with the host side of the network as their IP.
+func (d *Driver) hostOnlyNet(arg []string) ([]byte, error) {
+	if len(arg) < 2 {
+		return nil, d.usageError(arg)
+	}
+
+	name, lowerIP := flagValue(arg, "--name"), flagValue(arg, "--lower-ip")
+	if name == "" || lowerIP == "" {
+		return nil, d.usageError(arg)
+	}
+	ip := lowerIP[:strings.LastIndex(lowerIP, ".")] + ".1"
+
+	switch arg[1] {
+	case "add":
+		d.mutex.Lock()
+		defer d.mutex.Unlock()
+
+		if d.hasInterface(name) {
+			return nil, fmt.Errorf("VBoxManage: error: Host-only network '%s' already exists", name)
+		}
+		d.interfaces = append(d.interfaces, &network.Interface{Name: name, IP: ip, Exists: true})
+		return nil, nil
+	case "modify":
+		return nil, d.ConfigureHostOnlyInterface(name, ip)
+	}
+	return nil, d.usageError(arg)
+}
+
+func (d *Driver) natNetwork(arg []string) ([]byte, error) {
+	if len(arg) < 2 {
+		return nil, d.usageError(arg)
+	}
+
+	if rule := flagValue(arg, "--port-forward-4"); rule != "" && arg[1] == "modify" {
+		return nil, d.natNetworkPortForward(flagValue(arg, "--netname"), arg)
+	}
+
+	name, cidr := flagValue(arg, "--netname"), flagValue(arg, "--network")
+	if name == "" || cidr == "" {
+		return nil, d.usageError(arg)
+	}
+	ip := cidr[:strings.LastIndex(cidr, ".")] + ".1"
+
+	switch arg[1] {
+	case "add":
+		d.mutex.Lock()
+		defer d.mutex.Unlock()
+
+		for _, natNetwork := range d.natNetworks {
+			if natNetwork.Name == name {
+				return nil, fmt.Errorf("VBoxManage: error: NATNetwork server already exists")
+			}
+		}
+		d.natNetworks = append(d.natNetworks, &network.Interface{Name: name, IP: ip, Exists: true})
+		return nil, nil
+	case "modify":
+		return nil, d.ConfigureNATNetwork(name, ip)
+	}
+	return nil, d.usageError(arg)
+}
+
+// natNetworkPortForward adds a '--port-forward-4' rule to a NAT network, or
+// removes one when it is given as 'delete <name>'.
+func (d *Driver) natNetworkPortForward(networkName string, arg []string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	rule := flagValue(arg, "--port-forward-4")
+	if rule != "delete" {
+		if !strings.Contains(rule, ":") {
+			return d.usageError(arg)
+		}
+		return d.addNATNetworkRuleLocked(networkName, rule)
+	}
+
+	ruleName := flagValue(arg, "delete")
+	if _, exists := d.natNetworkRules[networkName][ruleName]; !exists {
+		return fmt.Errorf("VBoxManage: error: A NAT rule named '%s' does not exist", ruleName)
+	}
+	delete(d.natNetworkRules[networkName], ruleName)
+	return nil
+}
+
+// vmAttachment describes the second NIC of a VM the way that
+// 'list vms --long' does.
+func (d *Driver) vmAttachment(vmName string) (attachment string, name string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	vm, exists := d.vms[vmName]
+	if !exists {
+		return "", ""
+	}
+	switch {
+	case vm.BridgedInterface != "":
+		return "Bridged Interface", vm.BridgedInterface
+	case vm.NATNetwork != "":
+		return "NAT Network", vm.NATNetwork
+	case vm.HostOnlyInterface != "":
+		if vboxdriver.NetworkModelForVersion(&d.VBoxVersion) == network.ModelHostOnlyNetwork {
+			return "Host-only Network", vm.HostOnlyInterface
+		}
+		return "Host-only Interface", vm.HostOnlyInterface
+	}
+	return "", ""
+}
+
+func copyInterfaces(interfaces []*network.Interface) []*network.Interface {
+	copied := make([]*network.Interface, len(interfaces))
+	for i, iface := range interfaces {
+		c := *iface
+		copied[i] = &c
+	}
+	return copied
+}
+
+func flagValue(arg []string, flag string) string {
+	for i := 0; i < len(arg)-1; i++ {
+		if arg[i] == flag {
+			return arg[i+1]
+		}
+	}
+	return ""
+}
+
+func uuid(name string) string {
+	sum := uint32(0)
+	for _, c := range name {
+		sum = sum*31 + uint32(c)
+	}
+	return fmt.Sprintf("%08x-0000-4000-8000-000000000000", sum)
+}
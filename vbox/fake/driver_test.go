@@ -0,0 +1,192 @@
+package fake_test
+
+import (
+	"context"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/network"
+	"github.com/pivotal-cf/pcfdev-cli/vbox"
+	"github.com/pivotal-cf/pcfdev-cli/vbox/fake"
+	"github.com/pivotal-cf/pcfdev-cli/vboxdriver"
+)
+
+var _ vbox.Driver = &fake.Driver{}
+
+var _ = Describe("Driver", func() {
+	var driver *fake.Driver
+
+	BeforeEach(func() {
+		driver = fake.NewDriver()
+	})
+
+	Describe("VM lifecycle", func() {
+		BeforeEach(func() {
+			Expect(driver.CreateVM("some-vm", "some-base-dir")).To(Succeed())
+		})
+
+		It("should register a stopped VM", func() {
+			Expect(driver.VMExists("some-vm")).To(BeTrue())
+			Expect(driver.VMs()).To(Equal([]string{"some-vm"}))
+			Expect(driver.RunningVMs()).To(BeEmpty())
+			Expect(driver.VMState("some-vm")).To(Equal(vboxdriver.StateStopped))
+		})
+
+		It("should not register the same VM twice", func() {
+			Expect(driver.CreateVM("some-vm", "some-base-dir")).To(MatchError(ContainSubstring("already exists")))
+		})
+
+		It("should move through the VirtualBox power states", func() {
+			Expect(driver.StartVM("some-vm")).To(Succeed())
+			Expect(driver.VMState("some-vm")).To(Equal(vboxdriver.StateRunning))
+			Expect(driver.RunningVMs()).To(Equal([]string{"some-vm"}))
+
+			Expect(driver.SuspendVM(context.Background(), "some-vm")).To(Succeed())
+			Expect(driver.VMState("some-vm")).To(Equal(vboxdriver.StateSaved))
+
+			Expect(driver.StartVM("some-vm")).To(Succeed())
+			Expect(driver.SetState("some-vm", vboxdriver.StatePaused)).To(Succeed())
+			Expect(driver.ResumeVM("some-vm")).To(Succeed())
+			Expect(driver.VMState("some-vm")).To(Equal(vboxdriver.StateRunning))
+
+			Expect(driver.StopVM(context.Background(), "some-vm")).To(Succeed())
+			Expect(driver.VMState("some-vm")).To(Equal(vboxdriver.StateStopped))
+		})
+
+		It("should reject transitions from the wrong state", func() {
+			Expect(driver.StopVM(context.Background(), "some-vm")).To(MatchError("VBoxManage: error: Invalid machine state: poweroff"))
+			Expect(driver.ResumeVM("some-vm")).To(MatchError("VBoxManage: error: Invalid machine state: poweroff"))
+		})
+
+		It("should not modify or destroy a running VM", func() {
+			Expect(driver.StartVM("some-vm")).To(Succeed())
+
+			Expect(driver.SetMemory("some-vm", 4096)).To(MatchError(ContainSubstring("is already locked")))
+			Expect(driver.DestroyVM(context.Background(), "some-vm")).To(MatchError(ContainSubstring("is already locked")))
+		})
+
+		It("should unregister the VM and its disk when destroyed", func() {
+			Expect(driver.CloneDisk(context.Background(), "some-src", "some-disk")).To(Succeed())
+			Expect(driver.AttachDisk("some-vm", "some-disk")).To(Succeed())
+
+			Expect(driver.DestroyVM(context.Background(), "some-vm")).To(Succeed())
+			Expect(driver.VMExists("some-vm")).To(BeFalse())
+			Expect(driver.Disks()).To(BeEmpty())
+		})
+
+		It("should keep track of settings and NAT rules", func() {
+			Expect(driver.SetMemory("some-vm", 4096)).To(Succeed())
+			Expect(driver.SetCPUs("some-vm", 2)).To(Succeed())
+			Expect(driver.ForwardPort("some-vm", "ssh", "2222", "22")).To(Succeed())
+
+			Expect(driver.GetMemory("some-vm")).To(Equal(uint64(4096)))
+			Expect(driver.GetHostForwardPort("some-vm", "ssh")).To(Equal("2222"))
+			_, err := driver.GetHostForwardPort("some-vm", "some-rule")
+			Expect(err).To(MatchError("could not find forwarded port"))
+		})
+
+		It("should return an error for an unknown VM", func() {
+			Expect(driver.StartVM("some-other-vm")).To(MatchError("VBoxManage: error: Could not find a registered machine named 'some-other-vm'"))
+		})
+	})
+
+	Describe("host-only interfaces", func() {
+		It("should create, configure and report interfaces", func() {
+			name, err := driver.CreateHostOnlyInterface("192.168.11.1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(name).To(Equal("vboxnet0"))
+			Expect(driver.ConfigureHostOnlyInterface("vboxnet0", "192.168.22.1")).To(Succeed())
+
+			Expect(driver.GetHostOnlyInterfaces()).To(Equal([]*network.Interface{
+				{Name: "vboxnet0", IP: "192.168.22.1", HardwareAddress: "0a:00:27:00:00:00", Exists: true},
+			}))
+			Expect(driver.IsInterfaceInUse("vboxnet0")).To(BeFalse())
+
+			Expect(driver.CreateVM("some-vm", "some-base-dir")).To(Succeed())
+			Expect(driver.AttachNetworkInterface("vboxnet0", "some-vm")).To(Succeed())
+			Expect(driver.IsInterfaceInUse("vboxnet0")).To(BeTrue())
+		})
+
+		It("should keep the name of interfaces that already exist on the host", func() {
+			driver.AddHostOnlyInterface(&network.Interface{Name: "vboxnet3", IP: "192.168.33.1", HardwareAddress: "some-hardware-address"})
+
+			name, err := driver.CreateHostOnlyInterface("192.168.11.1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(name).To(Equal("vboxnet1"))
+			Expect(driver.GetHostOnlyInterfaces()).To(Equal([]*network.Interface{
+				{Name: "vboxnet3", IP: "192.168.33.1", HardwareAddress: "some-hardware-address", Exists: true},
+				{Name: "vboxnet1", IP: "192.168.11.1", HardwareAddress: "0a:00:27:00:00:01", Exists: true},
+			}))
+		})
+
+		It("should create and configure host-only networks", func() {
+			Expect(driver.VBoxManage("hostonlynet", "add", "--name", "pcfdev-192.168.56.1", "--netmask", "255.255.255.0", "--lower-ip", "192.168.56.100", "--upper-ip", "192.168.56.254", "--enable")).To(BeEmpty())
+			Expect(driver.VBoxManage("hostonlynet", "modify", "--name", "pcfdev-192.168.56.1", "--netmask", "255.255.255.0", "--lower-ip", "192.168.57.100", "--upper-ip", "192.168.57.254", "--enable")).To(BeEmpty())
+
+			output, err := driver.VBoxManage("list", "hostonlynets")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(output)).To(ContainSubstring("Name:            pcfdev-192.168.56.1\nNetworkMask:     255.255.255.0\nLowerIP:         192.168.57.100\n"))
+
+			Expect(driver.CreateVM("some-vm", "some-base-dir")).To(Succeed())
+			Expect(driver.VBoxManage("modifyvm", "some-vm", "--nic2", "hostonlynet", "--nictype2", "virtio", "--host-only-net2", "pcfdev-192.168.56.1")).To(BeEmpty())
+			Expect(driver.IsInterfaceInUse("pcfdev-192.168.56.1")).To(BeTrue())
+		})
+
+		It("should return an error for an unknown interface", func() {
+			Expect(driver.ConfigureHostOnlyInterface("vboxnet9", "192.168.11.1")).To(MatchError(ContainSubstring("'vboxnet9' could not be found")))
+		})
+	})
+
+	Describe("#VBoxManage", func() {
+		It("should answer the commands used by vboxdriver.VBoxDriver from its state", func() {
+			Expect(driver.VBoxManage("createvm", "--name", "some-vm", "--ostype", "Ubuntu_64", "--basefolder", "some-base-dir", "--register")).To(BeEmpty())
+			Expect(driver.VBoxManage("modifyvm", "some-vm", "--memory", "4096", "--cpus", "2")).To(BeEmpty())
+			Expect(driver.VBoxManage("modifyvm", "some-vm", "--natpf1", "ssh,tcp,127.0.0.1,2222,,22")).To(BeEmpty())
+			Expect(driver.VBoxManage("hostonlyif", "create")).To(Equal([]byte("Interface 'vboxnet0' was successfully created\n")))
+			Expect(driver.VBoxManage("hostonlyif", "ipconfig", "vboxnet0", "--ip", "192.168.11.1", "--netmask", "255.255.255.0")).To(BeEmpty())
+			Expect(driver.VBoxManage("modifyvm", "some-vm", "--nic2", "hostonly", "--nictype2", "virtio", "--hostonlyadapter2", "vboxnet0")).To(BeEmpty())
+			Expect(driver.VBoxManage("startvm", "some-vm", "--type", "headless")).To(BeEmpty())
+
+			output, err := driver.VBoxManage("list", "vms")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(output)).To(MatchRegexp(`^"some-vm" \{.*\}\n$`))
+
+			output, err = driver.VBoxManage("list", "runningvms")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(output)).To(ContainSubstring(`"some-vm"`))
+
+			output, err = driver.VBoxManage("list", "vms", "--long")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(output)).To(ContainSubstring("Attachment: Host-only Interface 'vboxnet0'"))
+
+			output, err = driver.VBoxManage("list", "hostonlyifs")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(output)).To(ContainSubstring("Name:            vboxnet0\nIPAddress:       192.168.11.1\n"))
+
+			output, err = driver.VBoxManage("showvminfo", "some-vm", "--machinereadable")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(output)).To(ContainSubstring(`VMState="running"`))
+			Expect(string(output)).To(ContainSubstring("memory=4096"))
+			Expect(string(output)).To(ContainSubstring(`hostonlyadapter2="vboxnet0"`))
+			Expect(string(output)).To(ContainSubstring(`Forwarding(0)="ssh,tcp,127.0.0.1,2222,,22"`))
+
+			info, err := driver.VMInfo("some-vm")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.State).To(Equal(vboxdriver.StateRunning))
+			nic, exists := info.NIC(2)
+			Expect(exists).To(BeTrue())
+			Expect(nic).To(Equal(vboxdriver.NIC{Index: 2, Type: "hostonly", HostOnlyAdapter: "vboxnet0"}))
+
+			Expect(driver.VBoxManage("controlvm", "some-vm", "savestate")).To(BeEmpty())
+			Expect(driver.VMState("some-vm")).To(Equal(vboxdriver.StateSaved))
+			Expect(driver.VBoxManage("unregistervm", "some-vm", "--delete")).To(BeEmpty())
+			Expect(driver.VMExists("some-vm")).To(BeFalse())
+
+			Expect(driver.VBoxManage("--version")).To(Equal([]byte("5.1.22r0\n")))
+		})
+
+		It("should return an error for unsupported commands", func() {
+			_, err := driver.VBoxManage("some-command", "some-arg")
+			Expect(err).To(MatchError("VBoxManage: error: unsupported command: some-command some-arg"))
+		})
+	})
+})
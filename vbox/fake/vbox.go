@@ -0,0 +1,286 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pivotal-cf/pcfdev-cli/address"
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/debug"
+	. "github.com/pivotal-cf/pcfdev-cli/helpers"
+	"github.com/pivotal-cf/pcfdev-cli/vbox"
+	"github.com/pivotal-cf/pcfdev-cli/vboxdriver"
+	"github.com/pivotal-cf/pcfdev-cli/vm"
+)
+
+var _ vm.VBox = &VBox{}
+
+// VBox is an in-memory stand-in for vbox.VBox backed by a fake Driver. It
+// performs the same driver operations as vbox.VBox, but keeps the VM config in
+// memory and skips the guest configuration that would need a running VM. Only
+// the secure key is handled like guest.InsertSecureKeypair does, when the
+// config has a private key path, so that the key on the host can be checked
+// against the one authorized on the VM.
+type VBox struct {
+	Config *config.Config
+	Driver *Driver
+
+	mutex          sync.Mutex
+	vmConfigs      map[string]*config.VMConfig
+	authorizedKeys map[string]string
+	nextPort       int
+	nextKey        int
+}
+
+func NewVBox(conf *config.Config) *VBox {
+	return &VBox{
+		Config:         conf,
+		Driver:         NewDriver(),
+		vmConfigs:      map[string]*config.VMConfig{},
+		authorizedKeys: map[string]string{},
+		nextPort:       2222,
+	}
+}
+
+func (v *VBox) ImportVM(ctx context.Context, vmConfig *config.VMConfig) error {
+	if err := v.Driver.CreateVM(vmConfig.Name, v.Config.VMDir); err != nil {
+		return err
+	}
+
+	disk := filepath.Join(v.Config.VMDir, vmConfig.Name, vmConfig.Name+"-disk1.vmdk")
+	if err := v.Driver.CloneDisk(ctx, vmConfig.OVAPath, disk); err != nil {
+		return err
+	}
+
+	if err := v.Driver.AttachDisk(vmConfig.Name, disk); err != nil {
+		return err
+	}
+
+	picker := &address.Picker{
+		Network:    v.Driver,
+		Driver:     v.Driver,
+		SubnetPool: v.Config.SubnetPool,
+	}
+	networkVMConfig, err := vbox.AttachNetwork(v.Driver, picker, vmConfig, v.generatePort())
+	if err != nil {
+		return err
+	}
+
+	if err := v.Driver.UseDNSProxy(vmConfig.Name); err != nil {
+		return err
+	}
+
+	if err := v.Driver.SetCPUs(vmConfig.Name, vmConfig.CPUs); err != nil {
+		return err
+	}
+
+	if err := v.Driver.SetMemory(vmConfig.Name, vmConfig.Memory); err != nil {
+		return err
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.vmConfigs[vmConfig.Name] = networkVMConfig
+	return nil
+}
+
+func (v *VBox) StartVM(ctx context.Context, vmConfig *config.VMConfig) error {
+	if err := v.Driver.StartVM(vmConfig.Name); err != nil {
+		return err
+	}
+	return v.insertSecureKeypair(vmConfig.Name)
+}
+
+// AuthorizedKey returns the secure key that was authorized on the VM when it
+// was first started.
+func (v *VBox) AuthorizedKey(vmName string) string {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	return v.authorizedKeys[vmName]
+}
+
+func (v *VBox) StopVM(ctx context.Context, vmConfig *config.VMConfig) error {
+	return v.Driver.StopVM(ctx, vmConfig.Name)
+}
+
+func (v *VBox) SuspendVM(ctx context.Context, vmConfig *config.VMConfig) error {
+	return v.Driver.SuspendVM(ctx, vmConfig.Name)
+}
+
+func (v *VBox) ResumeSavedVM(vmConfig *config.VMConfig) error {
+	return v.Driver.StartVM(vmConfig.Name)
+}
+
+func (v *VBox) ResumePausedVM(vmConfig *config.VMConfig) error {
+	return v.Driver.ResumeVM(vmConfig.Name)
+}
+
+func (v *VBox) PowerOffVM(vmConfig *config.VMConfig) error {
+	return v.Driver.PowerOffVM(vmConfig.Name)
+}
+
+func (v *VBox) DestroyVM(ctx context.Context, vmConfig *config.VMConfig) error {
+	if err := v.Driver.DestroyVM(ctx, vmConfig.Name); err != nil {
+		return err
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	delete(v.vmConfigs, vmConfig.Name)
+	delete(v.authorizedKeys, vmConfig.Name)
+	return nil
+}
+
+func (v *VBox) GetVMName() (name string, err error) {
+	vms, err := v.Driver.VMs()
+	if err != nil {
+		return "", err
+	}
+	for _, vm := range vms {
+		if strings.HasPrefix(vm, "pcfdev-") {
+			if name == "" {
+				name = vm
+			} else {
+				return "", errors.New("multiple PCF Dev VMs found")
+			}
+		}
+	}
+	return name, nil
+}
+
+func (v *VBox) DestroyPCFDevVMs(ctx context.Context) error {
+	vms, err := v.Driver.VMs()
+	if err != nil {
+		return err
+	}
+
+	for _, vm := range vms {
+		if strings.HasPrefix(vm, "pcfdev-") {
+			IgnoreErrorFrom(v.Driver.PowerOffVM(vm))
+			if err := v.DestroyVM(ctx, &config.VMConfig{Name: vm}); err != nil {
+				return err
+			}
+		}
+	}
+
+	disks, err := v.Driver.Disks()
+	if err != nil {
+		return err
+	}
+
+	for _, disk := range disks {
+		if strings.HasPrefix(filepath.Base(disk), "pcfdev-") {
+			IgnoreErrorFrom(v.Driver.DeleteDisk(disk))
+		}
+	}
+	return nil
+}
+
+func (v *VBox) VMConfig(vmName string) (*config.VMConfig, error) {
+	memory, err := v.Driver.GetMemory(vmName)
+	if err != nil {
+		return nil, err
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	storedConfig, exists := v.vmConfigs[vmName]
+	if !exists {
+		return nil, fmt.Errorf("no vm_config found for %s", vmName)
+	}
+
+	port := storedConfig.SSHPort
+	if port == "" {
+		if port, err = v.Driver.GetHostForwardPort(vmName, "ssh"); err != nil {
+			return nil, err
+		}
+	}
+
+	return &config.VMConfig{
+		Memory:      memory,
+		Name:        vmName,
+		SSHPort:     port,
+		IP:          storedConfig.IP,
+		GuestIP:     storedConfig.GuestIP,
+		Domain:      storedConfig.Domain,
+		Provider:    vbox.ProviderName,
+		NetworkMode: storedConfig.NetworkMode,
+	}, nil
+}
+
+func (v *VBox) VMStatus(vmName string) (status string, err error) {
+	exists, err := v.Driver.VMExists(vmName)
+	if err != nil {
+		return "", err
+	}
+
+	if !exists {
+		return vbox.StatusNotCreated, nil
+	}
+
+	state, err := v.Driver.VMState(vmName)
+	if err != nil {
+		return "", err
+	}
+
+	switch state {
+	case vboxdriver.StateRunning:
+		return vbox.StatusRunning, nil
+	case vboxdriver.StateStopped, vboxdriver.StateAborted:
+		return vbox.StatusStopped, nil
+	case vboxdriver.StateSaved:
+		return vbox.StatusSaved, nil
+	case vboxdriver.StatePaused:
+		return vbox.StatusPaused, nil
+	default:
+		return vbox.StatusUnknown, nil
+	}
+}
+
+func (v *VBox) Version() (version *vboxdriver.VBoxDriverVersion, err error) {
+	return v.Driver.Version()
+}
+
+func (v *VBox) HostLogs(vmConfig *config.VMConfig) ([]*debug.HostLog, error) {
+	return vbox.HostLogs(v.Driver, vmConfig)
+}
+
+func (v *VBox) insertSecureKeypair(vmName string) error {
+	if v.Config.PrivateKeyPath == "" {
+		return nil
+	}
+	if _, err := os.Stat(v.Config.PrivateKeyPath); err == nil {
+		return nil
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	v.mutex.Lock()
+	v.nextKey++
+	privateKey := fmt.Sprintf("some-private-key-%d", v.nextKey)
+	v.authorizedKeys[vmName] = privateKey
+	v.mutex.Unlock()
+
+	if v.Config.KnownHostsPath != "" {
+		if err := os.RemoveAll(v.Config.KnownHostsPath); err != nil {
+			return err
+		}
+	}
+	return ioutil.WriteFile(v.Config.PrivateKeyPath, []byte(privateKey), 0600)
+}
+
+func (v *VBox) generatePort() string {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	port := v.nextPort
+	v.nextPort++
+	return strconv.Itoa(port)
+}
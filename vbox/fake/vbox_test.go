@@ -0,0 +1,332 @@
+package fake_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/fs"
+	"github.com/pivotal-cf/pcfdev-cli/network"
+	"github.com/pivotal-cf/pcfdev-cli/plugin/cmd"
+	cmdMocks "github.com/pivotal-cf/pcfdev-cli/plugin/cmd/mocks"
+	"github.com/pivotal-cf/pcfdev-cli/provider"
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
+	"github.com/pivotal-cf/pcfdev-cli/vbox"
+	"github.com/pivotal-cf/pcfdev-cli/vbox/fake"
+	"github.com/pivotal-cf/pcfdev-cli/vboxdriver"
+	"github.com/pivotal-cf/pcfdev-cli/vm"
+	vmMocks "github.com/pivotal-cf/pcfdev-cli/vm/mocks"
+)
+
+var (
+	_ vm.VBox           = &fake.VBox{}
+	_ cmd.VBox          = &fake.VBox{}
+	_ provider.Provider = &fake.VBox{}
+)
+
+var _ = Describe("VBox", func() {
+	var (
+		vbx  *fake.VBox
+		conf *config.Config
+	)
+
+	BeforeEach(func() {
+		conf = &config.Config{
+			DefaultVMName: "pcfdev-some-vm",
+			VMDir:         "some-vm-dir",
+		}
+		vbx = fake.NewVBox(conf)
+	})
+
+	Describe("#ImportVM", func() {
+		It("should register and configure the VM", func() {
+			Expect(vbx.ImportVM(context.Background(), &config.VMConfig{Name: "pcfdev-some-vm", Memory: 4096, CPUs: 2})).To(Succeed())
+
+			Expect(vbx.VMStatus("pcfdev-some-vm")).To(Equal(vbox.StatusStopped))
+			Expect(vbx.VMConfig("pcfdev-some-vm")).To(Equal(&config.VMConfig{
+				Name:        "pcfdev-some-vm",
+				Memory:      4096,
+				SSHPort:     "2222",
+				IP:          "192.168.11.11",
+				Domain:      "local.pcfdev.io",
+				Provider:    "virtualbox",
+				NetworkMode: "hostonly",
+			}))
+			Expect(vbx.Driver.Disks()).To(Equal([]string{filepath.Join("some-vm-dir", "pcfdev-some-vm", "pcfdev-some-vm-disk1.vmdk")}))
+			Expect(vbx.Driver.IsInterfaceInUse("vboxnet0")).To(BeTrue())
+		})
+
+		It("should pick the next free network for a second VM", func() {
+			Expect(vbx.ImportVM(context.Background(), &config.VMConfig{Name: "pcfdev-some-vm"})).To(Succeed())
+			Expect(vbx.ImportVM(context.Background(), &config.VMConfig{Name: "pcfdev-some-other-vm"})).To(Succeed())
+
+			vmConfig, err := vbx.VMConfig("pcfdev-some-other-vm")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vmConfig.IP).To(Equal("192.168.22.11"))
+			Expect(vmConfig.SSHPort).To(Equal("2223"))
+
+			_, err = vbx.GetVMName()
+			Expect(err).To(MatchError("multiple PCF Dev VMs found"))
+		})
+
+		It("should bridge the VM to a host adapter in bridged mode", func() {
+			vbx.Driver.AddBridgedInterface(&network.Interface{Name: "en0: Wi-Fi (AirPort)", IP: "192.168.1.23"})
+			Expect(vbx.ImportVM(context.Background(), &config.VMConfig{Name: "pcfdev-some-vm", NetworkMode: "bridged"})).To(Succeed())
+
+			info, err := vbx.Driver.VMInfo("pcfdev-some-vm")
+			Expect(err).NotTo(HaveOccurred())
+			nic, exists := info.NIC(2)
+			Expect(exists).To(BeTrue())
+			Expect(nic.BridgeAdapter).To(Equal("en0: Wi-Fi (AirPort)"))
+
+			vmConfig, err := vbx.VMConfig("pcfdev-some-vm")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vmConfig.IP).To(BeEmpty())
+			Expect(vmConfig.NetworkMode).To(Equal("bridged"))
+		})
+
+		It("should attach the VM to a NAT network in natnetwork mode", func() {
+			Expect(vbx.ImportVM(context.Background(), &config.VMConfig{Name: "pcfdev-some-vm", NetworkMode: "natnetwork"})).To(Succeed())
+
+			Expect(vbx.Driver.GetNATNetworks()).To(Equal([]*network.Interface{
+				{Name: "pcfdev-nat-192.168.11.1", IP: "192.168.11.1", Exists: true},
+			}))
+			Expect(vbx.Driver.IsInterfaceInUse("pcfdev-nat-192.168.11.1")).To(BeTrue())
+
+			Expect(vbx.Driver.NATNetworkPortForwards("pcfdev-nat-192.168.11.1")).To(Equal(map[string]string{
+				"ssh":   "ssh:tcp:[127.0.0.1]:2222:[192.168.11.11]:22",
+				"http":  "http:tcp:[127.0.0.1]:80:[192.168.11.11]:80",
+				"https": "https:tcp:[127.0.0.1]:443:[192.168.11.11]:443",
+			}))
+
+			vmConfig, err := vbx.VMConfig("pcfdev-some-vm")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vmConfig.IP).To(Equal("127.0.0.1"))
+			Expect(vmConfig.Domain).To(Equal("127.0.0.1.xip.io"))
+			Expect(vmConfig.GuestIP).To(Equal("192.168.11.11"))
+			Expect(vmConfig.SSHPort).To(Equal("2222"))
+			Expect(vmConfig.NetworkMode).To(Equal("natnetwork"))
+		})
+	})
+
+	Describe("#DestroyPCFDevVMs", func() {
+		It("should remove all PCF Dev VMs and disks, even running ones", func() {
+			Expect(vbx.ImportVM(context.Background(), &config.VMConfig{Name: "pcfdev-some-vm"})).To(Succeed())
+			Expect(vbx.StartVM(context.Background(), &config.VMConfig{Name: "pcfdev-some-vm"})).To(Succeed())
+			Expect(vbx.Driver.CreateVM("some-other-vm", "some-vm-dir")).To(Succeed())
+
+			Expect(vbx.DestroyPCFDevVMs(context.Background())).To(Succeed())
+
+			Expect(vbx.Driver.VMs()).To(Equal([]string{"some-other-vm"}))
+			Expect(vbx.Driver.Disks()).To(BeEmpty())
+			Expect(vbx.GetVMName()).To(BeEmpty())
+		})
+	})
+
+	Describe("running the command graph end to end", func() {
+		var (
+			mockCtrl   *gomock.Controller
+			mockCmdUI  *cmdMocks.MockUI
+			mockVMUI   *vmMocks.MockUI
+			mockSSH    *vmMocks.MockSSH
+			mockClient *vmMocks.MockClient
+			builder    *cmd.Builder
+			tempDir    string
+		)
+
+		BeforeEach(func() {
+			var err error
+			tempDir, err = ioutil.TempDir("", "pcfdev-fake")
+			Expect(err).NotTo(HaveOccurred())
+			conf.VMDir = filepath.Join(tempDir, "vms")
+			conf.PrivateKeyPath = filepath.Join(tempDir, "key.pem")
+			conf.FreeMemory = 8192
+			conf.SSHTimeout = 5 * time.Minute
+			Expect(ioutil.WriteFile(conf.PrivateKeyPath, []byte("some-private-key"), 0600)).To(Succeed())
+
+			mockCtrl = gomock.NewController(GinkgoT())
+			mockCmdUI = cmdMocks.NewMockUI(mockCtrl)
+			mockVMUI = vmMocks.NewMockUI(mockCtrl)
+			mockSSH = vmMocks.NewMockSSH(mockCtrl)
+			mockClient = vmMocks.NewMockClient(mockCtrl)
+
+			builder = &cmd.Builder{
+				Config: conf,
+				FS:     &fs.FS{},
+				UI:     mockCmdUI,
+				VBox:   vbx,
+				VMBuilder: &vm.VBoxBuilder{
+					Config: conf,
+					VBox:   vbx,
+					FS:     &fs.FS{},
+					SSH:    mockSSH,
+					Client: mockClient,
+					UI:     mockVMUI,
+				},
+			}
+		})
+
+		AfterEach(func() {
+			mockCtrl.Finish()
+			os.RemoveAll(tempDir)
+		})
+
+		run := func(subcommand string) error {
+			command, err := builder.Cmd(subcommand)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(command.Parse([]string{})).To(Succeed())
+			return command.Run(context.Background())
+		}
+
+		It("should drive the VM through its states", func() {
+			mockCmdUI.EXPECT().Say("Not Created")
+			Expect(run("status")).To(Succeed())
+
+			Expect(vbx.ImportVM(context.Background(), &config.VMConfig{Name: "pcfdev-some-vm", Memory: 4096})).To(Succeed())
+			Expect(vbx.StartVM(context.Background(), &config.VMConfig{Name: "pcfdev-some-vm"})).To(Succeed())
+
+			mockClient.EXPECT().Status(gomock.Any(), gomock.Any(), []byte("some-private-key")).Return("Running", nil)
+			gomock.InOrder(
+				mockVMUI.EXPECT().Say("Suspending VM..."),
+				mockVMUI.EXPECT().Say("PCF Dev is now suspended."),
+			)
+			Expect(run("suspend")).To(Succeed())
+			Expect(vbx.Driver.VMState("pcfdev-some-vm")).To(Equal(vboxdriver.StateSaved))
+
+			mockCmdUI.EXPECT().Say("Suspended")
+			Expect(run("status")).To(Succeed())
+
+			mockVMUI.EXPECT().Say("Your VM is currently suspended. You must resume your VM with `cf dev resume` to shut it down.")
+			Expect(run("stop")).To(Succeed())
+
+			addresses := []ssh.SSHAddress{
+				{IP: "127.0.0.1", Port: "2222"},
+				{IP: "192.168.11.11", Port: "22"},
+			}
+			gomock.InOrder(
+				mockVMUI.EXPECT().Say("Resuming VM..."),
+				mockSSH.EXPECT().WaitForSSH(gomock.Any(), addresses, []byte("some-private-key"), 5*time.Minute),
+				mockVMUI.EXPECT().Say("PCF Dev is now running."),
+			)
+			Expect(run("resume")).To(Succeed())
+			Expect(vbx.Driver.VMState("pcfdev-some-vm")).To(Equal(vboxdriver.StateRunning))
+
+			mockClient.EXPECT().Status(gomock.Any(), gomock.Any(), []byte("some-private-key")).Return("Running", nil)
+			gomock.InOrder(
+				mockVMUI.EXPECT().Say("Stopping VM..."),
+				mockVMUI.EXPECT().Say("PCF Dev is now stopped."),
+			)
+			Expect(run("stop")).To(Succeed())
+			Expect(vbx.VMStatus("pcfdev-some-vm")).To(Equal(vbox.StatusStopped))
+		})
+	})
+
+	Describe("upgrading the VM", func() {
+		var (
+			mockCtrl         *gomock.Controller
+			mockUI           *cmdMocks.MockUI
+			mockVMBuilder    *cmdMocks.MockVMBuilder
+			mockDownloadCmd  *cmdMocks.MockCmd
+			mockBackupReader *cmdMocks.MockBackupReader
+			mockOldVM        *vmMocks.MockVM
+			mockNewVM        *vmMocks.MockVM
+			upgradeCmd       *cmd.UpgradeCmd
+			tempDir          string
+			oldKey           string
+		)
+
+		BeforeEach(func() {
+			var err error
+			tempDir, err = ioutil.TempDir("", "pcfdev-fake")
+			Expect(err).NotTo(HaveOccurred())
+			conf.VMDir = tempDir
+			conf.PrivateKeyPath = filepath.Join(tempDir, "key.pem")
+			conf.KnownHostsPath = filepath.Join(tempDir, "known_hosts")
+
+			Expect(vbx.ImportVM(context.Background(), &config.VMConfig{Name: "pcfdev-old-vm"})).To(Succeed())
+			Expect(vbx.StartVM(context.Background(), &config.VMConfig{Name: "pcfdev-old-vm"})).To(Succeed())
+			Expect(ioutil.WriteFile(conf.KnownHostsPath, []byte("some-old-host-key"), 0600)).To(Succeed())
+			oldKey = vbx.AuthorizedKey("pcfdev-old-vm")
+			Expect(ioutil.ReadFile(conf.PrivateKeyPath)).To(Equal([]byte(oldKey)))
+
+			mockCtrl = gomock.NewController(GinkgoT())
+			mockUI = cmdMocks.NewMockUI(mockCtrl)
+			mockVMBuilder = cmdMocks.NewMockVMBuilder(mockCtrl)
+			mockDownloadCmd = cmdMocks.NewMockCmd(mockCtrl)
+			mockBackupReader = cmdMocks.NewMockBackupReader(mockCtrl)
+			mockOldVM = vmMocks.NewMockVM(mockCtrl)
+			mockNewVM = vmMocks.NewMockVM(mockCtrl)
+
+			upgradeCmd = &cmd.UpgradeCmd{
+				VBox:         vbx,
+				VMBuilder:    mockVMBuilder,
+				Config:       conf,
+				UI:           mockUI,
+				FS:           &fs.FS{},
+				DownloadCmd:  mockDownloadCmd,
+				BackupReader: mockBackupReader,
+			}
+
+			mockDownloadCmd.EXPECT().Run(gomock.Any())
+			mockVMBuilder.EXPECT().VM(gomock.Any(), "pcfdev-old-vm").Return(mockOldVM, nil).Times(2)
+			mockOldVM.EXPECT().Start(gomock.Any(), &vm.StartOpts{})
+			mockOldVM.EXPECT().Backup(gomock.Any(), gomock.Any()).Do(func(_ context.Context, archivePath string) {
+				Expect(ioutil.WriteFile(archivePath, []byte("some-backup"), 0600)).To(Succeed())
+			})
+			mockOldVM.EXPECT().Stop(gomock.Any()).Do(func(ctx context.Context) {
+				Expect(vbx.StopVM(ctx, &config.VMConfig{Name: "pcfdev-old-vm"})).To(Succeed())
+			})
+			mockBackupReader.EXPECT().ReadProvisionConfig(gomock.Any()).Return(&config.ProvisionConfig{}, nil)
+			mockVMBuilder.EXPECT().VM(gomock.Any(), "pcfdev-some-vm").Return(mockNewVM, nil).AnyTimes()
+			mockNewVM.EXPECT().VerifyStartOpts(gomock.Any())
+		})
+
+		AfterEach(func() {
+			mockCtrl.Finish()
+			os.RemoveAll(tempDir)
+		})
+
+		startNewVM := func(ctx context.Context, _ *vm.StartOpts) {
+			Expect(vbx.ImportVM(ctx, &config.VMConfig{Name: "pcfdev-some-vm"})).To(Succeed())
+			Expect(vbx.StartVM(ctx, &config.VMConfig{Name: "pcfdev-some-vm"})).To(Succeed())
+		}
+
+		It("should reach the new VM with a new key", func() {
+			mockNewVM.EXPECT().Start(gomock.Any(), gomock.Any()).Do(startNewVM)
+			mockNewVM.EXPECT().Restore(gomock.Any(), gomock.Any())
+			mockUI.EXPECT().Say("PCF Dev has been upgraded.")
+
+			Expect(upgradeCmd.Run(context.Background())).To(Succeed())
+
+			Expect(vbx.Driver.VMs()).To(Equal([]string{"pcfdev-some-vm"}))
+			newKey := vbx.AuthorizedKey("pcfdev-some-vm")
+			Expect(newKey).NotTo(BeEmpty())
+			Expect(newKey).NotTo(Equal(oldKey))
+			Expect(ioutil.ReadFile(conf.PrivateKeyPath)).To(Equal([]byte(newKey)))
+			Expect(conf.KnownHostsPath).NotTo(BeAnExistingFile())
+			Expect(conf.PrivateKeyPath + ".old").NotTo(BeAnExistingFile())
+			Expect(conf.KnownHostsPath + ".old").NotTo(BeAnExistingFile())
+		})
+
+		Context("when starting the new VM fails", func() {
+			It("should keep the old VM reachable with its key", func() {
+				mockNewVM.EXPECT().Start(gomock.Any(), gomock.Any()).Do(startNewVM).Return(errors.New("some-error"))
+
+				Expect(upgradeCmd.Run(context.Background())).To(MatchError("failed to upgrade VM: some-error"))
+
+				Expect(vbx.Driver.VMs()).To(Equal([]string{"pcfdev-old-vm"}))
+				Expect(ioutil.ReadFile(conf.PrivateKeyPath)).To(Equal([]byte(oldKey)))
+				Expect(ioutil.ReadFile(conf.KnownHostsPath)).To(Equal([]byte("some-old-host-key")))
+				Expect(conf.PrivateKeyPath + ".old").NotTo(BeAnExistingFile())
+				Expect(conf.KnownHostsPath + ".old").NotTo(BeAnExistingFile())
+			})
+		})
+	})
+})
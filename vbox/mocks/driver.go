@@ -4,6 +4,7 @@
 package mocks
 
 import (
+	"context"
 	"github.com/golang/mock/gomock"
 	"github.com/pivotal-cf/pcfdev-cli/network"
 	"github.com/pivotal-cf/pcfdev-cli/vboxdriver"
@@ -30,6 +31,16 @@ func (_m *MockDriver) EXPECT() *_MockDriverRecorder {
 	return _m.recorder
 }
 
+func (_m *MockDriver) AttachBridgedInterface(_param0 string, _param1 string) error {
+	ret := _m.ctrl.Call(_m, "AttachBridgedInterface", _param0, _param1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockDriverRecorder) AttachBridgedInterface(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "AttachBridgedInterface", arg0, arg1)
+}
+
 func (_m *MockDriver) AttachDisk(_param0 string, _param1 string) error {
 	ret := _m.ctrl.Call(_m, "AttachDisk", _param0, _param1)
 	ret0, _ := ret[0].(error)
@@ -40,6 +51,16 @@ func (_mr *_MockDriverRecorder) AttachDisk(arg0, arg1 interface{}) *gomock.Call
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "AttachDisk", arg0, arg1)
 }
 
+func (_m *MockDriver) AttachNATNetwork(_param0 string, _param1 string) error {
+	ret := _m.ctrl.Call(_m, "AttachNATNetwork", _param0, _param1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockDriverRecorder) AttachNATNetwork(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "AttachNATNetwork", arg0, arg1)
+}
+
 func (_m *MockDriver) AttachNetworkInterface(_param0 string, _param1 string) error {
 	ret := _m.ctrl.Call(_m, "AttachNetworkInterface", _param0, _param1)
 	ret0, _ := ret[0].(error)
@@ -50,14 +71,14 @@ func (_mr *_MockDriverRecorder) AttachNetworkInterface(arg0, arg1 interface{}) *
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "AttachNetworkInterface", arg0, arg1)
 }
 
-func (_m *MockDriver) CloneDisk(_param0 string, _param1 string) error {
-	ret := _m.ctrl.Call(_m, "CloneDisk", _param0, _param1)
+func (_m *MockDriver) CloneDisk(_param0 context.Context, _param1 string, _param2 string) error {
+	ret := _m.ctrl.Call(_m, "CloneDisk", _param0, _param1, _param2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockDriverRecorder) CloneDisk(arg0, arg1 interface{}) *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "CloneDisk", arg0, arg1)
+func (_mr *_MockDriverRecorder) CloneDisk(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "CloneDisk", arg0, arg1, arg2)
 }
 
 func (_m *MockDriver) ConfigureHostOnlyInterface(_param0 string, _param1 string) error {
@@ -70,6 +91,16 @@ func (_mr *_MockDriverRecorder) ConfigureHostOnlyInterface(arg0, arg1 interface{
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "ConfigureHostOnlyInterface", arg0, arg1)
 }
 
+func (_m *MockDriver) ConfigureNATNetwork(_param0 string, _param1 string) error {
+	ret := _m.ctrl.Call(_m, "ConfigureNATNetwork", _param0, _param1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockDriverRecorder) ConfigureNATNetwork(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ConfigureNATNetwork", arg0, arg1)
+}
+
 func (_m *MockDriver) CreateHostOnlyInterface(_param0 string) (string, error) {
 	ret := _m.ctrl.Call(_m, "CreateHostOnlyInterface", _param0)
 	ret0, _ := ret[0].(string)
@@ -81,6 +112,17 @@ func (_mr *_MockDriverRecorder) CreateHostOnlyInterface(arg0 interface{}) *gomoc
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "CreateHostOnlyInterface", arg0)
 }
 
+func (_m *MockDriver) CreateNATNetwork(_param0 string) (string, error) {
+	ret := _m.ctrl.Call(_m, "CreateNATNetwork", _param0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockDriverRecorder) CreateNATNetwork(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "CreateNATNetwork", arg0)
+}
+
 func (_m *MockDriver) CreateVM(_param0 string, _param1 string) error {
 	ret := _m.ctrl.Call(_m, "CreateVM", _param0, _param1)
 	ret0, _ := ret[0].(error)
@@ -101,14 +143,14 @@ func (_mr *_MockDriverRecorder) DeleteDisk(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "DeleteDisk", arg0)
 }
 
-func (_m *MockDriver) DestroyVM(_param0 string) error {
-	ret := _m.ctrl.Call(_m, "DestroyVM", _param0)
+func (_m *MockDriver) DestroyVM(_param0 context.Context, _param1 string) error {
+	ret := _m.ctrl.Call(_m, "DestroyVM", _param0, _param1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockDriverRecorder) DestroyVM(arg0 interface{}) *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "DestroyVM", arg0)
+func (_mr *_MockDriverRecorder) DestroyVM(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "DestroyVM", arg0, arg1)
 }
 
 func (_m *MockDriver) Disks() ([]string, error) {
@@ -122,6 +164,16 @@ func (_mr *_MockDriverRecorder) Disks() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Disks")
 }
 
+func (_m *MockDriver) ForwardNATNetworkPort(_param0 string, _param1 string, _param2 string, _param3 string, _param4 string) error {
+	ret := _m.ctrl.Call(_m, "ForwardNATNetworkPort", _param0, _param1, _param2, _param3, _param4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockDriverRecorder) ForwardNATNetworkPort(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ForwardNATNetworkPort", arg0, arg1, arg2, arg3, arg4)
+}
+
 func (_m *MockDriver) ForwardPort(_param0 string, _param1 string, _param2 string, _param3 string) error {
 	ret := _m.ctrl.Call(_m, "ForwardPort", _param0, _param1, _param2, _param3)
 	ret0, _ := ret[0].(error)
@@ -132,6 +184,17 @@ func (_mr *_MockDriverRecorder) ForwardPort(arg0, arg1, arg2, arg3 interface{})
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "ForwardPort", arg0, arg1, arg2, arg3)
 }
 
+func (_m *MockDriver) GetBridgedInterfaces() ([]*network.Interface, error) {
+	ret := _m.ctrl.Call(_m, "GetBridgedInterfaces")
+	ret0, _ := ret[0].([]*network.Interface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockDriverRecorder) GetBridgedInterfaces() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetBridgedInterfaces")
+}
+
 func (_m *MockDriver) GetHostForwardPort(_param0 string, _param1 string) (string, error) {
 	ret := _m.ctrl.Call(_m, "GetHostForwardPort", _param0, _param1)
 	ret0, _ := ret[0].(string)
@@ -165,6 +228,17 @@ func (_mr *_MockDriverRecorder) GetMemory(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetMemory", arg0)
 }
 
+func (_m *MockDriver) GetNATNetworks() ([]*network.Interface, error) {
+	ret := _m.ctrl.Call(_m, "GetNATNetworks")
+	ret0, _ := ret[0].([]*network.Interface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockDriverRecorder) GetNATNetworks() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetNATNetworks")
+}
+
 func (_m *MockDriver) IsInterfaceInUse(_param0 string) (bool, error) {
 	ret := _m.ctrl.Call(_m, "IsInterfaceInUse", _param0)
 	ret0, _ := ret[0].(bool)
@@ -237,24 +311,24 @@ func (_mr *_MockDriverRecorder) StartVM(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "StartVM", arg0)
 }
 
-func (_m *MockDriver) StopVM(_param0 string) error {
-	ret := _m.ctrl.Call(_m, "StopVM", _param0)
+func (_m *MockDriver) StopVM(_param0 context.Context, _param1 string) error {
+	ret := _m.ctrl.Call(_m, "StopVM", _param0, _param1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockDriverRecorder) StopVM(arg0 interface{}) *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "StopVM", arg0)
+func (_mr *_MockDriverRecorder) StopVM(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "StopVM", arg0, arg1)
 }
 
-func (_m *MockDriver) SuspendVM(_param0 string) error {
-	ret := _m.ctrl.Call(_m, "SuspendVM", _param0)
+func (_m *MockDriver) SuspendVM(_param0 context.Context, _param1 string) error {
+	ret := _m.ctrl.Call(_m, "SuspendVM", _param0, _param1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockDriverRecorder) SuspendVM(arg0 interface{}) *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "SuspendVM", arg0)
+func (_mr *_MockDriverRecorder) SuspendVM(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "SuspendVM", arg0, arg1)
 }
 
 func (_m *MockDriver) UseDNSProxy(_param0 string) error {
@@ -293,6 +367,17 @@ func (_mr *_MockDriverRecorder) VMExists(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "VMExists", arg0)
 }
 
+func (_m *MockDriver) VMInfo(_param0 string) (*vboxdriver.VMInfo, error) {
+	ret := _m.ctrl.Call(_m, "VMInfo", _param0)
+	ret0, _ := ret[0].(*vboxdriver.VMInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockDriverRecorder) VMInfo(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "VMInfo", arg0)
+}
+
 func (_m *MockDriver) VMState(_param0 string) (string, error) {
 	ret := _m.ctrl.Call(_m, "VMState", _param0)
 	ret0, _ := ret[0].(string)
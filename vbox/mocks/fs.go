@@ -6,7 +6,7 @@ package mocks
 import (
 	gomock "github.com/golang/mock/gomock"
 	io "io"
-	os "os"
+	fs "io/fs"
 )
 
 // Mock of FS interface
@@ -30,7 +30,7 @@ func (_m *MockFS) EXPECT() *_MockFSRecorder {
 	return _m.recorder
 }
 
-func (_m *MockFS) Chmod(_param0 string, _param1 os.FileMode) error {
+func (_m *MockFS) Chmod(_param0 string, _param1 fs.FileMode) error {
 	ret := _m.ctrl.Call(_m, "Chmod", _param0, _param1)
 	ret0, _ := ret[0].(error)
 	return ret0
@@ -61,6 +61,16 @@ func (_mr *_MockFSRecorder) Extract(arg0, arg1, arg2 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Extract", arg0, arg1, arg2)
 }
 
+func (_m *MockFS) Move(_param0 string, _param1 string) error {
+	ret := _m.ctrl.Call(_m, "Move", _param0, _param1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockFSRecorder) Move(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Move", arg0, arg1)
+}
+
 func (_m *MockFS) Read(_param0 string) ([]byte, error) {
 	ret := _m.ctrl.Call(_m, "Read", _param0)
 	ret0, _ := ret[0].([]byte)
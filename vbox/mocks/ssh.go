@@ -4,6 +4,7 @@
 package mocks
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 	ssh "github.com/pivotal-cf/pcfdev-cli/ssh"
 	io "io"
@@ -43,24 +44,34 @@ func (_mr *_MockSSHRecorder) GenerateAddress() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GenerateAddress")
 }
 
-func (_m *MockSSH) GenerateKeypair() ([]byte, []byte, error) {
-	ret := _m.ctrl.Call(_m, "GenerateKeypair")
+func (_m *MockSSH) GenerateKeypair(_param0 string) ([]byte, []byte, error) {
+	ret := _m.ctrl.Call(_m, "GenerateKeypair", _param0)
 	ret0, _ := ret[0].([]byte)
 	ret1, _ := ret[1].([]byte)
 	ret2, _ := ret[2].(error)
 	return ret0, ret1, ret2
 }
 
-func (_mr *_MockSSHRecorder) GenerateKeypair() *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "GenerateKeypair")
+func (_mr *_MockSSHRecorder) GenerateKeypair(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GenerateKeypair", arg0)
 }
 
-func (_m *MockSSH) RunSSHCommand(_param0 string, _param1 []ssh.SSHAddress, _param2 []byte, _param3 time.Duration, _param4 io.Writer, _param5 io.Writer) error {
-	ret := _m.ctrl.Call(_m, "RunSSHCommand", _param0, _param1, _param2, _param3, _param4, _param5)
+func (_m *MockSSH) RunSSHCommand(_param0 context.Context, _param1 string, _param2 []ssh.SSHAddress, _param3 []byte, _param4 time.Duration, _param5 io.Writer, _param6 io.Writer) error {
+	ret := _m.ctrl.Call(_m, "RunSSHCommand", _param0, _param1, _param2, _param3, _param4, _param5, _param6)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockSSHRecorder) RunSSHCommand(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "RunSSHCommand", arg0, arg1, arg2, arg3, arg4, arg5)
+func (_mr *_MockSSHRecorder) RunSSHCommand(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RunSSHCommand", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+}
+
+func (_m *MockSSH) RunSSHCommandWithStdin(_param0 context.Context, _param1 string, _param2 []ssh.SSHAddress, _param3 []byte, _param4 time.Duration, _param5 io.Reader, _param6 io.Writer, _param7 io.Writer) error {
+	ret := _m.ctrl.Call(_m, "RunSSHCommandWithStdin", _param0, _param1, _param2, _param3, _param4, _param5, _param6, _param7)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockSSHRecorder) RunSSHCommandWithStdin(arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RunSSHCommandWithStdin", arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
 }
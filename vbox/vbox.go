@@ -2,18 +2,18 @@ package vbox
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
-	"io/ioutil"
 	"path/filepath"
 	"strings"
-	"text/template"
 	"time"
 
 	"github.com/pivotal-cf/pcfdev-cli/address"
 	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/debug"
+	"github.com/pivotal-cf/pcfdev-cli/guest"
 	. "github.com/pivotal-cf/pcfdev-cli/helpers"
 	"github.com/pivotal-cf/pcfdev-cli/network"
 	"github.com/pivotal-cf/pcfdev-cli/ssh"
@@ -27,10 +27,10 @@ type Driver interface {
 	StartVM(vmName string) error
 	VMExists(vmName string) (exists bool, err error)
 	PowerOffVM(vmName string) error
-	StopVM(vmName string) error
-	SuspendVM(vmName string) error
+	StopVM(ctx context.Context, vmName string) error
+	SuspendVM(ctx context.Context, vmName string) error
 	ResumeVM(vmName string) error
-	DestroyVM(vmName string) error
+	DestroyVM(ctx context.Context, vmName string) error
 	VMs() (vms []string, err error)
 	Disks() (disks []string, err error)
 	RunningVMs() (vms []string, err error)
@@ -41,15 +41,23 @@ type Driver interface {
 	IsInterfaceInUse(interfaceName string) (bool, error)
 	GetHostForwardPort(vmName string, ruleName string) (port string, err error)
 	GetHostOnlyInterfaces() (interfaces []*network.Interface, err error)
+	GetBridgedInterfaces() (interfaces []*network.Interface, err error)
+	AttachBridgedInterface(interfaceName string, vmName string) error
+	GetNATNetworks() (networks []*network.Interface, err error)
+	CreateNATNetwork(ip string) (networkName string, err error)
+	ConfigureNATNetwork(networkName string, ip string) error
+	AttachNATNetwork(networkName string, vmName string) error
+	ForwardNATNetworkPort(networkName string, ruleName string, hostPort string, guestIP string, guestPort string) error
 	SetCPUs(vmName string, cpuNumber int) error
 	SetMemory(vmName string, memory uint64) error
 	CreateVM(vmName string, baseDirectory string) error
 	AttachDisk(vmName string, diskPath string) error
-	CloneDisk(src string, dest string) error
+	CloneDisk(ctx context.Context, src string, dest string) error
 	DeleteDisk(diskPath string) error
 	UseDNSProxy(vmName string) error
 	GetMemory(vmName string) (uint64, error)
 	VMState(vmName string) (string, error)
+	VMInfo(vmName string) (info *vboxdriver.VMInfo, err error)
 	Version() (version *vboxdriver.VBoxDriverVersion, err error)
 }
 
@@ -61,13 +69,15 @@ type FS interface {
 	Write(path string, contents io.Reader, append bool) error
 	Read(path string) (contents []byte, err error)
 	Chmod(path string, mode os.FileMode) error
+	Move(source string, destination string) error
 }
 
 //go:generate mockgen -package mocks -destination mocks/ssh.go github.com/pivotal-cf/pcfdev-cli/vbox SSH
 type SSH interface {
 	GenerateAddress() (host string, port string, err error)
-	GenerateKeypair() (privateKey []byte, publicKey []byte, err error)
-	RunSSHCommand(command string, addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration, stdout io.Writer, stderr io.Writer) error
+	GenerateKeypair(keyType string) (privateKey []byte, publicKey []byte, err error)
+	RunSSHCommand(ctx context.Context, command string, addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration, stdout io.Writer, stderr io.Writer) error
+	RunSSHCommandWithStdin(ctx context.Context, command string, addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration, stdin io.Reader, stdout io.Writer, stderr io.Writer) error
 }
 
 //go:generate mockgen -package mocks -destination mocks/picker.go github.com/pivotal-cf/pcfdev-cli/vbox NetworkPicker
@@ -83,15 +93,7 @@ type VBox struct {
 	SSH    SSH
 }
 
-type VMProperties struct {
-	IPAddress string
-}
-
-type ProxyTypes struct {
-	HTTPProxy  string
-	HTTPSProxy string
-	NOProxy    string
-}
+const ProviderName = "virtualbox"
 
 const (
 	StatusRunning    = "Running"
@@ -102,278 +104,276 @@ const (
 	StatusUnknown    = "Unknown"
 )
 
-var (
-	networkTemplate = `
-auto lo
-iface lo inet loopback
-
-auto eth0
-iface eth0 inet dhcp
-
-auto eth1
-iface eth1 inet static
-address {{.IPAddress}}
-netmask 255.255.255.0`
-
-	proxyTemplate = `
-PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin:/usr/games:/usr/local/games
-{{if .HTTPProxy}}HTTP_PROXY={{.HTTPProxy}}{{end}}
-{{if .HTTPSProxy}}HTTPS_PROXY={{.HTTPSProxy}}{{end}}
-NO_PROXY={{.NOProxy}}
-{{if .HTTPProxy}}http_proxy={{.HTTPProxy}}{{end}}
-{{if .HTTPSProxy}}https_proxy={{.HTTPSProxy}}{{end}}
-no_proxy={{.NOProxy}}`
-)
-
-func (v *VBox) StartVM(vmConfig *config.VMConfig) error {
+func (v *VBox) StartVM(ctx context.Context, vmConfig *config.VMConfig) error {
 	if err := v.Driver.StartVM(vmConfig.Name); err != nil {
 		return err
 	}
 
-	if err := v.insertSecureKeypair(vmConfig); err != nil {
+	g := &guest.Guest{
+		Config: v.Config,
+		FS:     v.FS,
+		SSH:    v.SSH,
+	}
+	if err := g.InsertSecureKeypair(ctx, vmConfig); err != nil {
 		return err
 	}
 
-	if err := v.configureNetwork(vmConfig); err != nil {
+	if err := g.ConfigureNetwork(ctx, vmConfig); err != nil {
 		return err
 	}
-	if err := v.configureEnvironment(vmConfig); err != nil {
+	if err := g.ConfigureEnvironment(ctx, vmConfig); err != nil {
 		return err
 	}
 
-	if err := v.Driver.StopVM(vmConfig.Name); err != nil {
+	if err := v.Driver.StopVM(ctx, vmConfig.Name); err != nil {
 		return err
 	}
 
-	return v.Driver.StartVM(vmConfig.Name)
-}
-
-func (v *VBox) insertSecureKeypair(vmConfig *config.VMConfig) error {
-	exists, err := v.FS.Exists(v.Config.PrivateKeyPath)
-	if err != nil {
+	if err := v.Driver.StartVM(vmConfig.Name); err != nil {
 		return err
 	}
 
-	if exists {
-		return nil
+	if networkMode(vmConfig) == config.NetworkModeBridged {
+		return v.updateBridgedAddress(ctx, g, vmConfig)
 	}
+	return nil
+}
 
-	privateKey, publicKey, err := v.SSH.GenerateKeypair()
+// updateBridgedAddress records the address that a bridged VM was given on
+// the LAN, which may change each time that the VM boots.
+func (v *VBox) updateBridgedAddress(ctx context.Context, g *guest.Guest, vmConfig *config.VMConfig) error {
+	ip, err := g.BridgedIP(ctx, vmConfig)
 	if err != nil {
 		return err
 	}
+	vmConfig.IP = ip
+	vmConfig.Domain = address.DomainForIP(ip)
 
-	if err = v.SSH.RunSSHCommand(
-		fmt.Sprintf(`echo -n "%s" > /home/vcap/.ssh/authorized_keys`, publicKey),
-		[]ssh.SSHAddress{
-			{
-				IP:   "127.0.0.1",
-				Port: vmConfig.SSHPort,
-			},
-			{
-				IP:   vmConfig.IP,
-				Port: "22",
-			},
-		},
-		v.Config.InsecurePrivateKey,
-		5*time.Minute,
-		ioutil.Discard,
-		ioutil.Discard,
-	); err != nil {
+	if err := v.writeVMConfig(&vmConfigFile{IP: vmConfig.IP, Domain: vmConfig.Domain, NetworkMode: vmConfig.NetworkMode}); err != nil {
 		return err
 	}
 
-	return v.writePrivateKey(privateKey)
+	return g.ConfigureEnvironment(ctx, vmConfig)
 }
 
-func (v *VBox) writePrivateKey(privateKey []byte) error {
-	if err := v.FS.Write(v.Config.PrivateKeyPath, bytes.NewReader(privateKey), false); err != nil {
+func (v *VBox) ImportVM(ctx context.Context, vmConfig *config.VMConfig) error {
+	if err := v.Driver.CreateVM(vmConfig.Name, v.Config.VMDir); err != nil {
 		return err
 	}
-	return v.FS.Chmod(v.Config.PrivateKeyPath, 0600)
-}
 
-func (v *VBox) configureNetwork(vmConfig *config.VMConfig) error {
-	privateKeyBytes, err := v.FS.Read(v.Config.PrivateKeyPath)
-	if err != nil {
+	compressedDisk := filepath.Join(v.Config.VMDir, vmConfig.Name+"-disk1.vmdk") + ".compressed"
+	uncompressedDisk := filepath.Join(v.Config.VMDir, vmConfig.Name, vmConfig.Name+"-disk1.vmdk")
+	if err := v.FS.Extract(vmConfig.OVAPath, compressedDisk, `\w+\.vmdk`); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
 		return err
 	}
 
-	t, err := template.New("properties template").Parse(networkTemplate)
-	if err != nil {
+	if err := v.Driver.CloneDisk(ctx, compressedDisk, uncompressedDisk); err != nil {
 		return err
 	}
 
-	var sshCommand bytes.Buffer
-	if err = t.Execute(&sshCommand, VMProperties{IPAddress: vmConfig.IP}); err != nil {
+	if err := v.Driver.DeleteDisk(compressedDisk); err != nil {
 		return err
 	}
 
-	return v.SSH.RunSSHCommand(
-		fmt.Sprintf("echo -e '%s' | sudo tee /etc/network/interfaces", sshCommand.String()),
-		[]ssh.SSHAddress{
-			{
-				IP:   "127.0.0.1",
-				Port: vmConfig.SSHPort,
-			},
-			{
-				IP:   vmConfig.IP,
-				Port: "22",
-			},
-		},
-		privateKeyBytes,
-		5*time.Minute,
-		ioutil.Discard,
-		ioutil.Discard,
-	)
-}
+	if err := v.Driver.AttachDisk(vmConfig.Name, uncompressedDisk); err != nil {
+		return err
+	}
 
-func (v *VBox) configureEnvironment(vmConfig *config.VMConfig) error {
-	proxySettings, err := v.proxySettings(vmConfig)
+	_, sshPort, err := v.SSH.GenerateAddress()
 	if err != nil {
 		return err
 	}
 
-	privateKeyBytes, err := v.FS.Read(v.Config.PrivateKeyPath)
+	networkVMConfig, err := AttachNetwork(v.Driver, v.Picker, vmConfig, sshPort)
 	if err != nil {
 		return err
 	}
 
-	return v.SSH.RunSSHCommand(
-		fmt.Sprintf("echo -e '%s' | sudo tee /etc/environment", proxySettings),
-		[]ssh.SSHAddress{
-			{
-				IP:   "127.0.0.1",
-				Port: vmConfig.SSHPort,
-			},
-			{
-				IP:   vmConfig.IP,
-				Port: "22",
-			},
-		},
-		privateKeyBytes,
-		5*time.Minute,
-		ioutil.Discard,
-		ioutil.Discard,
-	)
-}
-
-func (v *VBox) proxySettings(vmConfig *config.VMConfig) (settings string, err error) {
-	subnet, err := address.SubnetForIP(vmConfig.IP)
-	if err != nil {
-		return "", err
+	if err := v.writeVMConfig(&vmConfigFile{
+		IP:          networkVMConfig.IP,
+		Domain:      networkVMConfig.Domain,
+		NetworkMode: networkVMConfig.NetworkMode,
+		GuestIP:     networkVMConfig.GuestIP,
+		SSHPort:     networkVMConfig.SSHPort,
+	}); err != nil {
+		return err
 	}
 
-	httpProxy := strings.Replace(v.Config.HTTPProxy, "127.0.0.1", subnet, -1)
-	httpsProxy := strings.Replace(v.Config.HTTPSProxy, "127.0.0.1", subnet, -1)
-	noProxy := strings.Join([]string{
-		"localhost",
-		"127.0.0.1",
-		subnet,
-		vmConfig.IP,
-		vmConfig.Domain,
-		"." + vmConfig.Domain,
-	}, ",")
-	if v.Config.NoProxy != "" {
-		noProxy = strings.Join([]string{noProxy, v.Config.NoProxy}, ",")
+	if err := v.Driver.UseDNSProxy(vmConfig.Name); err != nil {
+		return err
 	}
 
-	t, err := template.New("proxy template").Parse(proxyTemplate)
-	if err != nil {
-		return "", err
+	if err := v.Driver.SetCPUs(vmConfig.Name, vmConfig.CPUs); err != nil {
+		return err
 	}
 
-	var proxySettings bytes.Buffer
-	if err = t.Execute(&proxySettings, ProxyTypes{HTTPProxy: httpProxy, HTTPSProxy: httpsProxy, NOProxy: noProxy}); err != nil {
-		return "", err
+	if err := v.Driver.SetMemory(vmConfig.Name, vmConfig.Memory); err != nil {
+		return err
 	}
 
-	return proxySettings.String(), nil
+	return nil
 }
 
-func (v *VBox) ImportVM(vmConfig *config.VMConfig) error {
-	if err := v.Driver.CreateVM(vmConfig.Name, v.Config.VMDir); err != nil {
-		return err
-	}
+// vmConfigFile is the part of a VM's config that is only known once the VM
+// has been imported, which is kept in the vm_config file. The SSH port of a
+// VM on a NAT network is kept there too, as it is forwarded by the network
+// rather than by the VM's first NIC.
+type vmConfigFile struct {
+	IP          string `json:"ip"`
+	Domain      string `json:"domain"`
+	NetworkMode string `json:"networkMode"`
+	GuestIP     string `json:"guestIP,omitempty"`
+	SSHPort     string `json:"sshPort,omitempty"`
+}
 
-	compressedDisk := filepath.Join(v.Config.VMDir, vmConfig.Name+"-disk1.vmdk") + ".compressed"
-	uncompressedDisk := filepath.Join(v.Config.VMDir, vmConfig.Name, vmConfig.Name+"-disk1.vmdk")
-	if err := v.FS.Extract(vmConfig.OVAPath, compressedDisk, `\w+\.vmdk`); err != nil {
+func (v *VBox) writeVMConfig(file *vmConfigFile) error {
+	contents, err := json.Marshal(file)
+	if err != nil {
 		return err
 	}
+	return v.FS.Write(filepath.Join(v.Config.VMDir, "vm_config"), bytes.NewReader(contents), false)
+}
 
-	if err := v.Driver.CloneDisk(compressedDisk, uncompressedDisk); err != nil {
-		return err
+// AttachNetwork attaches the second NIC of the VM for its network mode and
+// forwards sshPort on the host to the VM's SSH server. It returns the
+// network part of the VM's config. VMs on a NAT network cannot be reached
+// from the host, so SSH, HTTP and HTTPS are forwarded to them from 127.0.0.1,
+// which becomes their address; the address on the NAT network is kept as
+// the guest IP.
+func AttachNetwork(driver Driver, picker NetworkPicker, vmConfig *config.VMConfig, sshPort string) (*config.VMConfig, error) {
+	var (
+		networkConfig *config.NetworkConfig
+		err           error
+	)
+	switch networkMode(vmConfig) {
+	case config.NetworkModeBridged:
+		networkConfig, err = attachBridgedInterface(driver, vmConfig)
+	case config.NetworkModeNATNetwork:
+		networkConfig, err = attachNATNetwork(driver, picker, vmConfig)
+	default:
+		networkConfig, err = attachHostOnlyInterface(driver, picker, vmConfig)
 	}
-
-	if err := v.Driver.DeleteDisk(compressedDisk); err != nil {
-		return err
+	if err != nil {
+		return nil, err
 	}
 
-	if err := v.Driver.AttachDisk(vmConfig.Name, uncompressedDisk); err != nil {
-		return err
+	if networkMode(vmConfig) != config.NetworkModeNATNetwork {
+		if err := driver.ForwardPort(vmConfig.Name, "ssh", sshPort, "22"); err != nil {
+			return nil, err
+		}
+		return &config.VMConfig{
+			IP:          networkConfig.VMIP,
+			Domain:      networkConfig.VMDomain,
+			NetworkMode: networkMode(vmConfig),
+		}, nil
+	}
+
+	for _, rule := range []struct{ name, hostPort, guestPort string }{
+		{"ssh", sshPort, "22"},
+		{"http", "80", "80"},
+		{"https", "443", "443"},
+	} {
+		if err := driver.ForwardNATNetworkPort(networkConfig.Interface.Name, rule.name, rule.hostPort, networkConfig.VMIP, rule.guestPort); err != nil {
+			return nil, err
+		}
 	}
+	return &config.VMConfig{
+		IP:          "127.0.0.1",
+		Domain:      address.DomainForIP("127.0.0.1"),
+		NetworkMode: config.NetworkModeNATNetwork,
+		GuestIP:     networkConfig.VMIP,
+		SSHPort:     sshPort,
+	}, nil
+}
 
-	vboxInterfaces, err := v.Driver.GetHostOnlyInterfaces()
+func attachHostOnlyInterface(driver Driver, picker NetworkPicker, vmConfig *config.VMConfig) (*config.NetworkConfig, error) {
+	vboxInterfaces, err := driver.GetHostOnlyInterfaces()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	networkConfig, err := v.Picker.SelectAvailableInterface(vboxInterfaces, vmConfig)
+	networkConfig, err := picker.SelectAvailableInterface(vboxInterfaces, vmConfig)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if networkConfig.Interface.Exists {
-		if err := v.Driver.ConfigureHostOnlyInterface(networkConfig.Interface.Name, networkConfig.Interface.IP); err != nil {
-			return err
+		if err := driver.ConfigureHostOnlyInterface(networkConfig.Interface.Name, networkConfig.Interface.IP); err != nil {
+			return nil, err
 		}
 	} else {
-		interfaceName, err := v.Driver.CreateHostOnlyInterface(networkConfig.Interface.IP)
+		interfaceName, err := driver.CreateHostOnlyInterface(networkConfig.Interface.IP)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		networkConfig.Interface.Name = interfaceName
 	}
 
-	if err := v.Driver.AttachNetworkInterface(networkConfig.Interface.Name, vmConfig.Name); err != nil {
-		return err
+	if err := driver.AttachNetworkInterface(networkConfig.Interface.Name, vmConfig.Name); err != nil {
+		return nil, err
 	}
+	return networkConfig, nil
+}
 
-	if err := v.FS.Write(
-		filepath.Join(v.Config.VMDir, "vm_config"),
-		strings.NewReader(fmt.Sprintf(`{"ip":"%s","domain":"%s"}`, networkConfig.VMIP, networkConfig.VMDomain)),
-		false,
-	); err != nil {
-		return err
+// attachBridgedInterface bridges the VM to the first host adapter that is up.
+// The VM takes its address from the LAN's DHCP server, so the address and
+// domain are only known once the VM has booted.
+func attachBridgedInterface(driver Driver, vmConfig *config.VMConfig) (*config.NetworkConfig, error) {
+	bridgedInterfaces, err := driver.GetBridgedInterfaces()
+	if err != nil {
+		return nil, err
+	}
+	if len(bridgedInterfaces) == 0 {
+		return nil, errors.New("failed to find a host network adapter that is up to bridge the VM to")
 	}
 
-	if err := v.Driver.UseDNSProxy(vmConfig.Name); err != nil {
-		return err
+	if err := driver.AttachBridgedInterface(bridgedInterfaces[0].Name, vmConfig.Name); err != nil {
+		return nil, err
 	}
+	return &config.NetworkConfig{Interface: bridgedInterfaces[0]}, nil
+}
 
-	_, sshPort, err := v.SSH.GenerateAddress()
+func attachNATNetwork(driver Driver, picker NetworkPicker, vmConfig *config.VMConfig) (*config.NetworkConfig, error) {
+	natNetworks, err := driver.GetNATNetworks()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := v.Driver.ForwardPort(vmConfig.Name, "ssh", sshPort, "22"); err != nil {
-		return err
+	networkConfig, err := picker.SelectAvailableInterface(natNetworks, vmConfig)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := v.Driver.SetCPUs(vmConfig.Name, vmConfig.CPUs); err != nil {
-		return err
+	if networkConfig.Interface.Exists {
+		if err := driver.ConfigureNATNetwork(networkConfig.Interface.Name, networkConfig.Interface.IP); err != nil {
+			return nil, err
+		}
+	} else {
+		networkName, err := driver.CreateNATNetwork(networkConfig.Interface.IP)
+		if err != nil {
+			return nil, err
+		}
+		networkConfig.Interface.Name = networkName
 	}
 
-	if err := v.Driver.SetMemory(vmConfig.Name, vmConfig.Memory); err != nil {
-		return err
+	if err := driver.AttachNATNetwork(networkConfig.Interface.Name, vmConfig.Name); err != nil {
+		return nil, err
 	}
+	return networkConfig, nil
+}
 
-	return nil
+func networkMode(vmConfig *config.VMConfig) string {
+	if vmConfig.NetworkMode == "" {
+		return config.NetworkModeHostOnly
+	}
+	return vmConfig.NetworkMode
 }
 
-func (v *VBox) DestroyVM(vmConfig *config.VMConfig) error {
-	return v.Driver.DestroyVM(vmConfig.Name)
+func (v *VBox) DestroyVM(ctx context.Context, vmConfig *config.VMConfig) error {
+	return v.Driver.DestroyVM(ctx, vmConfig.Name)
 }
 
 func (v *VBox) PowerOffVM(vmConfig *config.VMConfig) error {
@@ -397,12 +397,12 @@ func (v *VBox) GetVMName() (name string, err error) {
 	return name, nil
 }
 
-func (v *VBox) StopVM(vmConfig *config.VMConfig) error {
-	return v.Driver.StopVM(vmConfig.Name)
+func (v *VBox) StopVM(ctx context.Context, vmConfig *config.VMConfig) error {
+	return v.Driver.StopVM(ctx, vmConfig.Name)
 }
 
-func (v *VBox) SuspendVM(vmConfig *config.VMConfig) error {
-	return v.Driver.SuspendVM(vmConfig.Name)
+func (v *VBox) SuspendVM(ctx context.Context, vmConfig *config.VMConfig) error {
+	return v.Driver.SuspendVM(ctx, vmConfig.Name)
 }
 
 func (v *VBox) ResumePausedVM(vmConfig *config.VMConfig) error {
@@ -413,7 +413,7 @@ func (v *VBox) ResumeSavedVM(vmConfig *config.VMConfig) error {
 	return v.Driver.StartVM(vmConfig.Name)
 }
 
-func (v *VBox) DestroyPCFDevVMs() error {
+func (v *VBox) DestroyPCFDevVMs(ctx context.Context) error {
 	vms, err := v.Driver.VMs()
 	if err != nil {
 		return err
@@ -422,7 +422,7 @@ func (v *VBox) DestroyPCFDevVMs() error {
 	for _, vm := range vms {
 		if strings.HasPrefix(vm, "pcfdev-") {
 			IgnoreErrorFrom(v.Driver.PowerOffVM(vm))
-			IgnoreErrorFrom(v.Driver.DestroyVM(vm))
+			IgnoreErrorFrom(v.Driver.DestroyVM(ctx, vm))
 		}
 	}
 
@@ -464,11 +464,7 @@ func (v *VBox) DestroyPCFDevVMs() error {
 }
 
 func (v *VBox) VMConfig(vmName string) (*config.VMConfig, error) {
-	memory, err := v.Driver.GetMemory(vmName)
-	if err != nil {
-		return nil, err
-	}
-	port, err := v.Driver.GetHostForwardPort(vmName, "ssh")
+	info, err := v.Driver.VMInfo(vmName)
 	if err != nil {
 		return nil, err
 	}
@@ -478,14 +474,19 @@ func (v *VBox) VMConfig(vmName string) (*config.VMConfig, error) {
 	}
 
 	vmConfig := &config.VMConfig{
-		Memory:   memory,
+		Memory:   info.Memory,
 		Name:     vmName,
-		SSHPort:  port,
-		Provider: "virtualbox",
+		Provider: ProviderName,
+	}
+	if sshRule, exists := info.ForwardingRule("ssh"); exists {
+		vmConfig.SSHPort = sshRule.HostPort
 	}
 	if err := json.Unmarshal(vmConfigBytes, &vmConfig); err != nil {
 		return nil, err
 	}
+	if vmConfig.SSHPort == "" {
+		return nil, errors.New("could not find forwarded port")
+	}
 
 	return vmConfig, nil
 }
@@ -501,6 +502,9 @@ func (v *VBox) VMStatus(vmName string) (status string, err error) {
 	}
 
 	state, err := v.Driver.VMState(vmName)
+	if _, ok := err.(*vboxdriver.VMNotFoundError); ok {
+		return StatusNotCreated, nil
+	}
 	if err != nil {
 		return "", err
 	}
@@ -522,3 +526,30 @@ func (v *VBox) VMStatus(vmName string) (status string, err error) {
 func (v *VBox) Version() (version *vboxdriver.VBoxDriverVersion, err error) {
 	return v.Driver.Version()
 }
+
+func (v *VBox) HostLogs(vmConfig *config.VMConfig) ([]*debug.HostLog, error) {
+	return HostLogs(v.Driver, vmConfig)
+}
+
+// HostLogs returns what VirtualBox reports about the VMs and host-only
+// interfaces, for the debug logs of the VM.
+func HostLogs(driver Driver, vmConfig *config.VMConfig) ([]*debug.HostLog, error) {
+	commands := []struct {
+		filename string
+		args     []string
+	}{
+		{"vm-list", []string{"list", "vms", "--long"}},
+		{"vm-info", []string{"showvminfo", vmConfig.Name}},
+		{"vm-hostonlyifs", []string{"list", "hostonlyifs", "--long"}},
+	}
+
+	logs := []*debug.HostLog{}
+	for _, command := range commands {
+		output, err := driver.VBoxManage(command.args...)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, &debug.HostLog{Filename: command.filename, Output: output})
+	}
+	return logs, nil
+}
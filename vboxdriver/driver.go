@@ -1,26 +1,39 @@
 package vboxdriver
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pivotal-cf/pcfdev-cli/fs"
 	"github.com/pivotal-cf/pcfdev-cli/helpers"
 	"github.com/pivotal-cf/pcfdev-cli/network"
-	"github.com/pivotal-cf/pcfdev-cli/runner"
 )
 
 type VBoxDriverVersion struct {
 	Major, Minor, Build int
 }
 
+//go:generate mockgen -package mocks -destination mocks/cmd_runner.go github.com/pivotal-cf/pcfdev-cli/vboxdriver CmdRunner
+type CmdRunner interface {
+	RunContext(ctx context.Context, command string, args ...string) (output []byte, err error)
+}
+
 type VBoxDriver struct {
 	FS        *fs.FS
-	CmdRunner *runner.CmdRunner
+	CmdRunner CmdRunner
+
+	RetryAttempts int
+	RetryDelay    time.Duration
+
+	networkModelOnce sync.Once
+	networkModel     string
+	networkModelErr  error
 }
 
 const (
@@ -31,13 +44,49 @@ const (
 	StatePaused  = "paused"
 )
 
+const (
+	defaultRetryAttempts = 4
+	defaultRetryDelay    = 500 * time.Millisecond
+)
+
+// VBoxManage runs VBoxManage and returns its errors classified by
+// ClassifyError. Commands that fail with a transient error are run again,
+// waiting RetryDelay between each of the RetryAttempts. This is the only place
+// that VBoxManage commands are retried.
 func (v *VBoxDriver) VBoxManage(arg ...string) (output []byte, err error) {
+	return v.VBoxManageContext(context.Background(), arg...)
+}
+
+// VBoxManageContext runs VBoxManage like VBoxManage, and kills it and stops
+// retrying when ctx is done.
+func (v *VBoxDriver) VBoxManageContext(ctx context.Context, arg ...string) (output []byte, err error) {
 	vBoxManagePath, err := helpers.VBoxManagePath()
 	if err != nil {
 		return nil, errors.New("could not find VBoxManage executable")
 	}
 
-	return v.CmdRunner.Run(vBoxManagePath, arg...)
+	attempts := v.RetryAttempts
+	if attempts == 0 {
+		attempts = defaultRetryAttempts
+	}
+	delay := v.RetryDelay
+	if delay == 0 {
+		delay = defaultRetryDelay
+	}
+
+	retryErr := helpers.ExecuteWithAttempts(ctx, func() error {
+		output, err = v.CmdRunner.RunContext(ctx, vBoxManagePath, arg...)
+		err = ClassifyError(err)
+		if IsTransient(err) {
+			return err
+		}
+		return nil
+	}, attempts, delay)
+	if retryErr != nil && retryErr == ctx.Err() {
+		return nil, retryErr
+	}
+
+	return output, err
 }
 
 func (d *VBoxDriver) StartVM(vmName string) error {
@@ -84,32 +133,24 @@ func (d *VBoxDriver) VMExists(vmName string) (exists bool, err error) {
 }
 
 func (d *VBoxDriver) VMState(vmName string) (string, error) {
-	var output []byte
-	err := helpers.ExecuteWithAttempts(func() error {
-		var err error
-		output, err = d.VBoxManage("showvminfo", vmName, "--machinereadable")
-		return err
-	}, 3, time.Second)
-
+	info, err := d.VMInfo(vmName)
 	if err != nil {
 		return "", err
 	}
 
-	regex := regexp.MustCompile(`VMState="(.*)"`)
-	matches := regex.FindStringSubmatch(string(output))
-	if len(matches) <= 1 {
+	if info.State == "" {
 		return "", errors.New("no state identified for VM")
 	}
 
-	return matches[1], nil
+	return info.State, nil
 }
 
-func (d *VBoxDriver) StopVM(vmName string) error {
-	if _, err := d.VBoxManage("controlvm", vmName, "acpipowerbutton"); err != nil {
+func (d *VBoxDriver) StopVM(ctx context.Context, vmName string) error {
+	if _, err := d.VBoxManageContext(ctx, "controlvm", vmName, "acpipowerbutton"); err != nil {
 		return err
 	}
 
-	return helpers.ExecuteWithTimeout(func() error {
+	return helpers.ExecuteWithTimeout(ctx, func() error {
 		state, err := d.VMState(vmName)
 		if err != nil {
 			return fmt.Errorf("timed out waiting for vm to stop: %s", err)
@@ -124,12 +165,12 @@ func (d *VBoxDriver) StopVM(vmName string) error {
 	)
 }
 
-func (d *VBoxDriver) SuspendVM(vmName string) error {
-	if _, err := d.VBoxManage("controlvm", vmName, "savestate"); err != nil {
+func (d *VBoxDriver) SuspendVM(ctx context.Context, vmName string) error {
+	if _, err := d.VBoxManageContext(ctx, "controlvm", vmName, "savestate"); err != nil {
 		return err
 	}
 
-	return helpers.ExecuteWithTimeout(func() error {
+	return helpers.ExecuteWithTimeout(ctx, func() error {
 		state, err := d.VMState(vmName)
 		if err != nil {
 			return fmt.Errorf("timed out waiting for vm to suspend: %s", err)
@@ -154,12 +195,17 @@ func (d *VBoxDriver) PowerOffVM(vmName string) error {
 	return err
 }
 
-func (d *VBoxDriver) DestroyVM(vmName string) error {
-	return helpers.ExecuteWithTimeout(func() error {
-		if _, err := d.VBoxManage("unregistervm", vmName, "--delete"); err != nil {
+// DestroyVM unregisters the VM and deletes its files. A VM that is already
+// gone counts as destroyed.
+func (d *VBoxDriver) DestroyVM(ctx context.Context, vmName string) error {
+	return helpers.ExecuteWithTimeout(ctx, func() error {
+		_, err := d.VBoxManageContext(ctx, "unregistervm", vmName, "--delete")
+		switch err.(type) {
+		case nil, *VMNotFoundError:
+			return nil
+		default:
 			return fmt.Errorf("timed out waiting for vm to destroy: %s", err)
 		}
-		return nil
 	},
 		time.Minute,
 		time.Second,
@@ -167,27 +213,26 @@ func (d *VBoxDriver) DestroyVM(vmName string) error {
 }
 
 func (d *VBoxDriver) CreateHostOnlyInterface(ip string) (string, error) {
-	var interfaceName string
-	err := helpers.ExecuteWithAttempts(func() error {
-		output, err := d.VBoxManage("hostonlyif", "create")
-		if err != nil {
-			return err
-		}
-
-		regex := regexp.MustCompile(`Interface '(.*)' was successfully created`)
-		matches := regex.FindStringSubmatch(string(output))
-		if len(matches) <= 1 {
-			return errors.New("could not determine interface name")
-		}
-
-		interfaceName = matches[1]
-		return nil
-	}, 3, time.Second)
+	model, err := d.NetworkModel()
+	if err != nil {
+		return "", err
+	}
+	if model == network.ModelHostOnlyNetwork {
+		return d.createHostOnlyNetwork(ip)
+	}
 
+	output, err := d.VBoxManage("hostonlyif", "create")
 	if err != nil {
 		return "", err
 	}
 
+	regex := regexp.MustCompile(`Interface '(.*)' was successfully created`)
+	matches := regex.FindStringSubmatch(string(output))
+	if len(matches) <= 1 {
+		return "", errors.New("could not determine interface name")
+	}
+	interfaceName := matches[1]
+
 	if _, err := d.VBoxManage("hostonlyif", "ipconfig", interfaceName, "--ip", ip, "--netmask", "255.255.255.0"); err != nil {
 		return "", err
 	}
@@ -195,6 +240,14 @@ func (d *VBoxDriver) CreateHostOnlyInterface(ip string) (string, error) {
 }
 
 func (d *VBoxDriver) ConfigureHostOnlyInterface(interfaceName string, ip string) error {
+	model, err := d.NetworkModel()
+	if err != nil {
+		return err
+	}
+	if model == network.ModelHostOnlyNetwork {
+		return d.configureHostOnlyNetwork(interfaceName, ip)
+	}
+
 	if _, err := d.VBoxManage("hostonlyif", "ipconfig", interfaceName, "--ip", ip); err != nil {
 		return err
 	}
@@ -203,6 +256,14 @@ func (d *VBoxDriver) ConfigureHostOnlyInterface(interfaceName string, ip string)
 }
 
 func (d *VBoxDriver) GetHostOnlyInterfaces() (interfaces []*network.Interface, err error) {
+	model, err := d.NetworkModel()
+	if err != nil {
+		return nil, err
+	}
+	if model == network.ModelHostOnlyNetwork {
+		return d.getHostOnlyNetworks()
+	}
+
 	output, err := d.VBoxManage("list", "hostonlyifs")
 	if err != nil {
 		return nil, err
@@ -231,17 +292,16 @@ func (d *VBoxDriver) GetHostOnlyInterfaces() (interfaces []*network.Interface, e
 }
 
 func (d *VBoxDriver) GetMemory(vmName string) (uint64, error) {
-	output, err := d.VBoxManage("showvminfo", vmName, "--machinereadable")
+	info, err := d.VMInfo(vmName)
 	if err != nil {
 		return uint64(0), err
 	}
 
-	regex := regexp.MustCompile(`memory=(\d+)`)
-	if matches := regex.FindStringSubmatch(string(output)); len(matches) > 1 {
-		return strconv.ParseUint(matches[1], 10, 64)
+	if info.Memory == 0 {
+		return uint64(0), fmt.Errorf("failed to determine VM memory for '%s'", vmName)
 	}
 
-	return uint64(0), fmt.Errorf("failed to determine VM memory for '%s'", vmName)
+	return info.Memory, nil
 }
 
 func (d *VBoxDriver) SetMemory(vmName string, memory uint64) error {
@@ -255,7 +315,15 @@ func (d *VBoxDriver) SetCPUs(vmName string, cpus int) error {
 }
 
 func (d *VBoxDriver) AttachNetworkInterface(interfaceName string, vmName string) error {
-	_, err := d.VBoxManage("modifyvm", vmName, "--nic2", "hostonly", "--nictype2", "virtio", "--hostonlyadapter2", interfaceName)
+	model, err := d.NetworkModel()
+	if err != nil {
+		return err
+	}
+	if model == network.ModelHostOnlyNetwork {
+		return d.attachHostOnlyNetwork(interfaceName, vmName)
+	}
+
+	_, err = d.VBoxManage("modifyvm", vmName, "--nic2", "hostonly", "--nictype2", "virtio", "--hostonlyadapter2", interfaceName)
 	return err
 }
 
@@ -265,14 +333,13 @@ func (d *VBoxDriver) ForwardPort(vmName string, ruleName string, hostPort string
 }
 
 func (d *VBoxDriver) GetHostForwardPort(vmName string, ruleName string) (port string, err error) {
-	output, err := d.VBoxManage("showvminfo", vmName, "--machinereadable")
+	info, err := d.VMInfo(vmName)
 	if err != nil {
 		return "", err
 	}
 
-	regex := regexp.MustCompile(`Forwarding\(\d+\)="` + ruleName + `,tcp,127.0.0.1,(.*),,22"`)
-	if matches := regex.FindStringSubmatch(string(output)); len(matches) > 1 {
-		return matches[1], nil
+	if rule, exists := info.ForwardingRule(ruleName); exists {
+		return rule.HostPort, nil
 	}
 
 	return "", errors.New("could not find forwarded port")
@@ -312,27 +379,13 @@ func (d *VBoxDriver) RunningVMs() (vms []string, err error) {
 	return runningVMs, nil
 }
 
-func (d *VBoxDriver) getVBoxNetName(vmName string) (interfaceName string, err error) {
-	output, err := d.VBoxManage("showvminfo", vmName, "--machinereadable")
-	if err != nil {
-		return "", err
-	}
-
-	regex := regexp.MustCompile(`hostonlyadapter2="(.*)"`)
-	if matches := regex.FindStringSubmatch(string(output)); len(matches) > 1 {
-		return matches[1], nil
-	}
-
-	return "", nil
-}
-
 func (d *VBoxDriver) IsInterfaceInUse(interfaceName string) (bool, error) {
 	output, err := d.VBoxManage("list", "vms", "--long")
 	if err != nil {
 		return false, err
 	}
 
-	regex := regexp.MustCompile(`NIC\s.*Attachment: Host-only Interface '(` + interfaceName + `)'`)
+	regex := regexp.MustCompile(`NIC\s.*Attachment: (?:Host-only Interface|Host-only Network|NAT Network) '(` + regexp.QuoteMeta(interfaceName) + `)'`)
 	if matches := regex.FindStringSubmatch(string(output)); len(matches) > 1 {
 		return true, nil
 	}
@@ -340,8 +393,8 @@ func (d *VBoxDriver) IsInterfaceInUse(interfaceName string) (bool, error) {
 	return false, nil
 }
 
-func (d *VBoxDriver) CloneDisk(src, dst string) error {
-	if _, err := d.VBoxManage("clonemedium", "disk", src, dst); err != nil {
+func (d *VBoxDriver) CloneDisk(ctx context.Context, src, dst string) error {
+	if _, err := d.VBoxManageContext(ctx, "clonemedium", "disk", src, dst); err != nil {
 		return err
 	}
 	if _, err := d.VBoxManage("closemedium", "disk", src); err != nil {
@@ -2,6 +2,7 @@ package vboxdriver_test
 
 import (
 	"archive/tar"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -88,7 +89,7 @@ var _ = Describe("driver", func() {
 			Eventually(func() (string, error) { return driver.VMState(vmName) }, 120*time.Second).Should(Equal(vboxdriver.StateRunning))
 
 			stdout := gbytes.NewBuffer()
-			Expect(sshClient.RunSSHCommand("cat /etc/resolv.conf", []ssh.SSHAddress{{IP: "127.0.0.1", Port: port}}, privateKeyBytes, 5*time.Minute, stdout, ioutil.Discard)).To(Succeed())
+			Expect(sshClient.RunSSHCommand(context.Background(), "cat /etc/resolv.conf", []ssh.SSHAddress{{IP: "127.0.0.1", Port: port}}, privateKeyBytes, 5*time.Minute, stdout, ioutil.Discard)).To(Succeed())
 			Expect(string(stdout.Contents())).To(ContainSubstring("10.0.2.3"))
 		})
 
@@ -129,16 +130,16 @@ var _ = Describe("driver", func() {
 			Eventually(func() (string, error) { return driver.VMState(vmName) }, 120*time.Second).Should(Equal(vboxdriver.StateRunning))
 
 			stdout := gbytes.NewBuffer()
-			Expect(sshClient.RunSSHCommand("hostname", []ssh.SSHAddress{{IP: "127.0.0.1", Port: port}}, privateKeyBytes, 5*time.Minute, stdout, ioutil.Discard)).To(Succeed())
+			Expect(sshClient.RunSSHCommand(context.Background(), "hostname", []ssh.SSHAddress{{IP: "127.0.0.1", Port: port}}, privateKeyBytes, 5*time.Minute, stdout, ioutil.Discard)).To(Succeed())
 			Expect(string(stdout.Contents())).To(ContainSubstring("ubuntu-core-stable-15"))
 
-			Expect(driver.StopVM(vmName)).To(Succeed())
+			Expect(driver.StopVM(context.Background(), vmName)).To(Succeed())
 			Eventually(func() (string, error) { return driver.VMState(vmName) }, 120*time.Second).Should(Equal(vboxdriver.StateStopped))
 
 			Expect(driver.StartVM(vmName)).To(Succeed())
 			Eventually(func() (string, error) { return driver.VMState(vmName) }, 120*time.Second).Should(Equal(vboxdriver.StateRunning))
 
-			Expect(driver.SuspendVM(vmName)).To(Succeed())
+			Expect(driver.SuspendVM(context.Background(), vmName)).To(Succeed())
 			Eventually(func() (string, error) { return driver.VMState(vmName) }, 120*time.Second).Should(Equal(vboxdriver.StateSaved))
 
 			Expect(driver.StartVM(vmName)).To(Succeed())
@@ -154,7 +155,7 @@ var _ = Describe("driver", func() {
 			Expect(driver.PowerOffVM(vmName)).To(Succeed())
 			Eventually(func() (string, error) { return driver.VMState(vmName) }, 120*time.Second).Should(Equal(vboxdriver.StateStopped))
 
-			Expect(driver.DestroyVM(vmName)).To(Succeed())
+			Expect(driver.DestroyVM(context.Background(), vmName)).To(Succeed())
 
 			Eventually(func() bool {
 				exists, err := driver.VMExists(vmName)
@@ -245,7 +246,7 @@ var _ = Describe("driver", func() {
 				Expect(driver.ForwardPort(vmName, "some-rule-name", port, "22")).To(Succeed())
 
 				Expect(driver.StartVM(vmName)).To(Succeed())
-				Expect(driver.SuspendVM(vmName)).To(Succeed())
+				Expect(driver.SuspendVM(context.Background(), vmName)).To(Succeed())
 
 				Expect(driver.VMState(vmName)).To(Equal(vboxdriver.StateSaved))
 			})
@@ -269,7 +270,7 @@ var _ = Describe("driver", func() {
 	Describe("#StopVM", func() {
 		Context("when VM with the given name does not exist", func() {
 			It("should return an error", func() {
-				err := driver.StopVM("some-bad-vm-name")
+				err := driver.StopVM(context.Background(), "some-bad-vm-name")
 				Expect(err).To(MatchError(MatchRegexp("failed to execute '.* controlvm some-bad-vm-name acpipowerbutton': exit status 1")))
 				Expect(err).To(MatchError(ContainSubstring("Could not find a registered machine named 'some-bad-vm-name'")))
 			})
@@ -279,7 +280,7 @@ var _ = Describe("driver", func() {
 	Describe("#SuspendVM", func() {
 		Context("when VM with the given name does not exist", func() {
 			It("should return an error", func() {
-				err := driver.SuspendVM("some-bad-vm-name")
+				err := driver.SuspendVM(context.Background(), "some-bad-vm-name")
 				Expect(err).To(MatchError(MatchRegexp("failed to execute '.* controlvm some-bad-vm-name savestate': exit status 1")))
 				Expect(err).To(MatchError(ContainSubstring("Could not find a registered machine named 'some-bad-vm-name'")))
 			})
@@ -309,7 +310,7 @@ var _ = Describe("driver", func() {
 	Describe("#DestroyVM", func() {
 		Context("when VM with the given name does not exist", func() {
 			It("should return an error", func() {
-				err := driver.DestroyVM("some-bad-vm-name")
+				err := driver.DestroyVM(context.Background(), "some-bad-vm-name")
 				Expect(err).To(MatchError(MatchRegexp("failed to execute '.* unregistervm some-bad-vm-name --delete': exit status 1")))
 				Expect(err).To(MatchError(ContainSubstring("Could not find a registered machine named 'some-bad-vm-name'")))
 			})
@@ -569,7 +570,7 @@ var _ = Describe("driver", func() {
 			Expect(driver.StartVM(vmName)).To(Succeed())
 
 			stdout := gbytes.NewBuffer()
-			Expect(sshClient.RunSSHCommand("hostname", []ssh.SSHAddress{{IP: "127.0.0.1", Port: port}}, privateKeyBytes, 5*time.Minute, stdout, ioutil.Discard)).To(Succeed())
+			Expect(sshClient.RunSSHCommand(context.Background(), "hostname", []ssh.SSHAddress{{IP: "127.0.0.1", Port: port}}, privateKeyBytes, 5*time.Minute, stdout, ioutil.Discard)).To(Succeed())
 			Expect(string(stdout.Contents())).To(ContainSubstring("ubuntu-core-stable-15"))
 		})
 
@@ -736,7 +737,7 @@ var _ = Describe("driver", func() {
 		})
 
 		It("should clone a disk", func() {
-			Expect(driver.CloneDisk(filepath.Join(tmpDir, "compressed-Snappy-disk1.vmdk"), filepath.Join(tmpDir, "cloned-Snappy-disk1.vmdk"))).To(Succeed())
+			Expect(driver.CloneDisk(context.Background(), filepath.Join(tmpDir, "compressed-Snappy-disk1.vmdk"), filepath.Join(tmpDir, "cloned-Snappy-disk1.vmdk"))).To(Succeed())
 
 			command := exec.Command(vBoxManagePath, "showmediuminfo", "disk", filepath.Join(tmpDir, "cloned-Snappy-disk1.vmdk"))
 			session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
@@ -753,7 +754,7 @@ var _ = Describe("driver", func() {
 
 		Context("when cloning fails", func() {
 			It("should return an error", func() {
-				Expect(driver.CloneDisk("some-bad-src", "cloned-Snappy-disk1.vmdk")).To(
+				Expect(driver.CloneDisk(context.Background(), "some-bad-src", "cloned-Snappy-disk1.vmdk")).To(
 					MatchError(MatchRegexp("failed to execute '.* clonemedium disk some-bad-src cloned-Snappy-disk1.vmdk':")))
 			})
 		})
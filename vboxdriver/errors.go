@@ -0,0 +1,70 @@
+package vboxdriver
+
+import "strings"
+
+type SessionLockedError struct {
+	Err error
+}
+
+func (e *SessionLockedError) Error() string {
+	return e.Err.Error()
+}
+
+type InvalidObjectStateError struct {
+	Err error
+}
+
+func (e *InvalidObjectStateError) Error() string {
+	return e.Err.Error()
+}
+
+type InvalidVMStateError struct {
+	Err error
+}
+
+func (e *InvalidVMStateError) Error() string {
+	return e.Err.Error()
+}
+
+type VMNotFoundError struct {
+	Err error
+}
+
+func (e *VMNotFoundError) Error() string {
+	return e.Err.Error()
+}
+
+// ClassifyError converts an error returned by VBoxManage into one of the
+// typed errors above, based on the error text that VBoxManage prints. Errors
+// that it does not recognise are returned unchanged.
+func ClassifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "is already locked") || strings.Contains(message, "session is locked"):
+		return &SessionLockedError{err}
+	case strings.Contains(message, "VBOX_E_INVALID_OBJECT_STATE"):
+		return &InvalidObjectStateError{err}
+	case strings.Contains(message, "VBOX_E_INVALID_VM_STATE") || strings.Contains(message, "Invalid machine state"):
+		return &InvalidVMStateError{err}
+	case strings.Contains(message, "Could not find a registered machine") || strings.Contains(message, "VBOX_E_OBJECT_NOT_FOUND"):
+		return &VMNotFoundError{err}
+	default:
+		return err
+	}
+}
+
+// IsTransient reports whether the error is one that VBoxManage returns while
+// VirtualBox is still settling after a state change, so that the same command
+// is likely to succeed if it is run again.
+func IsTransient(err error) bool {
+	switch err.(type) {
+	case *SessionLockedError, *InvalidObjectStateError:
+		return true
+	default:
+		return false
+	}
+}
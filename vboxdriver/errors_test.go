@@ -0,0 +1,136 @@
+package vboxdriver_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/vboxdriver"
+	"github.com/pivotal-cf/pcfdev-cli/vboxdriver/mocks"
+)
+
+var _ = Describe("ClassifyError", func() {
+	It("should classify the errors printed by VBoxManage", func() {
+		lockedErr := errors.New("VBoxManage: error: The machine 'some-vm' is already locked for a session (or being unlocked)")
+		Expect(vboxdriver.ClassifyError(lockedErr)).To(Equal(&vboxdriver.SessionLockedError{lockedErr}))
+
+		objectStateErr := errors.New("VBoxManage: error: Details: code VBOX_E_INVALID_OBJECT_STATE (0x80bb0007), component SessionMachine")
+		Expect(vboxdriver.ClassifyError(objectStateErr)).To(Equal(&vboxdriver.InvalidObjectStateError{objectStateErr}))
+
+		vmStateErr := errors.New("VBoxManage: error: Details: code VBOX_E_INVALID_VM_STATE (0x80bb0002), component ConsoleWrap")
+		Expect(vboxdriver.ClassifyError(vmStateErr)).To(Equal(&vboxdriver.InvalidVMStateError{vmStateErr}))
+
+		notFoundErr := errors.New("VBoxManage: error: Could not find a registered machine named 'some-vm'")
+		Expect(vboxdriver.ClassifyError(notFoundErr)).To(Equal(&vboxdriver.VMNotFoundError{notFoundErr}))
+
+		otherErr := errors.New("some-error")
+		Expect(vboxdriver.ClassifyError(otherErr)).To(BeIdenticalTo(otherErr))
+		Expect(vboxdriver.ClassifyError(nil)).To(BeNil())
+	})
+
+	It("should keep the message of the original error", func() {
+		err := errors.New("VBoxManage: error: Invalid machine state: poweroff")
+		Expect(vboxdriver.ClassifyError(err)).To(MatchError("VBoxManage: error: Invalid machine state: poweroff"))
+	})
+})
+
+var _ = Describe("IsTransient", func() {
+	It("should only consider lock and object state errors transient", func() {
+		err := errors.New("some-error")
+		Expect(vboxdriver.IsTransient(&vboxdriver.SessionLockedError{err})).To(BeTrue())
+		Expect(vboxdriver.IsTransient(&vboxdriver.InvalidObjectStateError{err})).To(BeTrue())
+		Expect(vboxdriver.IsTransient(&vboxdriver.InvalidVMStateError{err})).To(BeFalse())
+		Expect(vboxdriver.IsTransient(&vboxdriver.VMNotFoundError{err})).To(BeFalse())
+		Expect(vboxdriver.IsTransient(err)).To(BeFalse())
+		Expect(vboxdriver.IsTransient(nil)).To(BeFalse())
+	})
+})
+
+var _ = Describe("VBoxDriver retries", func() {
+	var (
+		mockCtrl      *gomock.Controller
+		mockCmdRunner *mocks.MockCmdRunner
+		driver        *vboxdriver.VBoxDriver
+	)
+
+	BeforeEach(func() {
+		if runtime.GOOS == "windows" {
+			Skip("VBoxManage is looked up on the PATH on windows")
+		}
+
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockCmdRunner = mocks.NewMockCmdRunner(mockCtrl)
+		driver = &vboxdriver.VBoxDriver{
+			CmdRunner:     mockCmdRunner,
+			RetryAttempts: 3,
+			RetryDelay:    time.Millisecond,
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Context("when VBoxManage fails with a transient error", func() {
+		It("should run the command again", func() {
+			gomock.InOrder(
+				mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "modifyvm", "some-vm", "--cpus", "2").Return(nil, errors.New("some-vm is already locked")),
+				mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "modifyvm", "some-vm", "--cpus", "2").Return(nil, errors.New("code VBOX_E_INVALID_OBJECT_STATE")),
+				mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "modifyvm", "some-vm", "--cpus", "2").Return(nil, nil),
+			)
+
+			Expect(driver.SetCPUs("some-vm", 2)).To(Succeed())
+		})
+
+		It("should return the typed error once it runs out of attempts", func() {
+			mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "modifyvm", "some-vm", "--cpus", "2").Return(nil, errors.New("some-vm is already locked")).Times(3)
+
+			err := driver.SetCPUs("some-vm", 2)
+			Expect(err).To(BeAssignableToTypeOf(&vboxdriver.SessionLockedError{}))
+			Expect(err).To(MatchError("some-vm is already locked"))
+		})
+	})
+
+	Context("when the context is done while waiting to run the command again", func() {
+		It("should stop retrying and return the error of the context", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			driver.RetryDelay = time.Hour
+
+			mockCmdRunner.EXPECT().RunContext(ctx, "VBoxManage", "clonemedium", "disk", "some-src", "some-dst").Do(func(context.Context, string, ...string) {
+				cancel()
+			}).Return(nil, errors.New("some-src is already locked"))
+
+			Expect(driver.CloneDisk(ctx, "some-src", "some-dst")).To(MatchError(context.Canceled))
+		})
+	})
+
+	Context("when VBoxManage fails with any other error", func() {
+		It("should return the typed error without running the command again", func() {
+			mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "startvm", "some-vm", "--type", "headless").Return(nil, errors.New("Could not find a registered machine named 'some-vm'"))
+
+			err := driver.StartVM("some-vm")
+			Expect(err).To(BeAssignableToTypeOf(&vboxdriver.VMNotFoundError{}))
+		})
+	})
+
+	Context("when reading the state of the VM fails", func() {
+		It("should rely on VBoxManage to retry the command", func() {
+			mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "showvminfo", "some-vm", "--machinereadable").Return(nil, errors.New("some-vm is already locked")).Times(3)
+
+			_, err := driver.VMState("some-vm")
+			Expect(err).To(BeAssignableToTypeOf(&vboxdriver.SessionLockedError{}))
+		})
+	})
+
+	Context("when the VM to destroy does not exist", func() {
+		It("should succeed without trying again", func() {
+			mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "unregistervm", "some-vm", "--delete").Return(nil, errors.New("Could not find a registered machine named 'some-vm'"))
+
+			Expect(driver.DestroyVM(context.Background(), "some-vm")).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,107 @@
+package vboxdriver
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+	"strings"
+
+	"github.com/pivotal-cf/pcfdev-cli/network"
+)
+
+// NetworkModelForVersion returns the host-only networking model of a
+// VirtualBox version. VirtualBox 7 dropped host-only interfaces on macOS in
+// favour of host-only networks.
+func NetworkModelForVersion(version *VBoxDriverVersion) string {
+	if version.Major >= 7 && runtime.GOOS == "darwin" {
+		return network.ModelHostOnlyNetwork
+	}
+	return network.ModelHostOnlyInterface
+}
+
+// NetworkModel returns the host-only networking model of the installed
+// VirtualBox. It is only looked up once, as the driver is shared by commands
+// that run concurrently.
+func (d *VBoxDriver) NetworkModel() (string, error) {
+	d.networkModelOnce.Do(func() {
+		version, err := d.Version()
+		if err != nil {
+			d.networkModelErr = err
+			return
+		}
+		d.networkModel = NetworkModelForVersion(version)
+	})
+	return d.networkModel, d.networkModelErr
+}
+
+// HostOnlyNetworkName returns the name given to the host-only network whose
+// host address is ip.
+func HostOnlyNetworkName(ip string) string {
+	return "pcfdev-" + ip
+}
+
+// HostOnlyNetworkRange returns the DHCP range of the host-only network whose
+// host address is ip. It leaves out the addresses below .100 so that they
+// can be given to VMs statically.
+func HostOnlyNetworkRange(ip string) (lowerIP string, upperIP string) {
+	prefix := ip[:strings.LastIndex(ip, ".")]
+	return prefix + ".100", prefix + ".254"
+}
+
+func (d *VBoxDriver) createHostOnlyNetwork(ip string) (string, error) {
+	name := HostOnlyNetworkName(ip)
+	lowerIP, upperIP := HostOnlyNetworkRange(ip)
+	if _, err := d.VBoxManage("hostonlynet", "add", "--name", name, "--netmask", "255.255.255.0", "--lower-ip", lowerIP, "--upper-ip", upperIP, "--enable"); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+func (d *VBoxDriver) configureHostOnlyNetwork(name string, ip string) error {
+	lowerIP, upperIP := HostOnlyNetworkRange(ip)
+	_, err := d.VBoxManage("hostonlynet", "modify", "--name", name, "--netmask", "255.255.255.0", "--lower-ip", lowerIP, "--upper-ip", upperIP, "--enable")
+	return err
+}
+
+func (d *VBoxDriver) getHostOnlyNetworks() ([]*network.Interface, error) {
+	output, err := d.VBoxManage("list", "hostonlynets")
+	if err != nil {
+		return nil, err
+	}
+
+	networks := []*network.Interface{}
+	for _, fields := range parseListBlocks(output) {
+		if fields["Name"] == "" {
+			continue
+		}
+
+		hostIP, err := hostIPForRange(fields["LowerIP"], fields["NetworkMask"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine the address of host-only network '%s': %s", fields["Name"], err)
+		}
+		networks = append(networks, &network.Interface{
+			Name:   fields["Name"],
+			IP:     hostIP,
+			Exists: true,
+		})
+	}
+
+	return networks, nil
+}
+
+func (d *VBoxDriver) attachHostOnlyNetwork(name string, vmName string) error {
+	_, err := d.VBoxManage("modifyvm", vmName, "--nic2", "hostonlynet", "--nictype2", "virtio", "--host-only-net2", name)
+	return err
+}
+
+func hostIPForRange(lowerIP string, netmask string) (string, error) {
+	ip := net.ParseIP(lowerIP).To4()
+	mask := net.ParseIP(netmask).To4()
+	if ip == nil || mask == nil {
+		return "", fmt.Errorf("invalid range '%s/%s'", lowerIP, netmask)
+	}
+
+	hostIP := ip.Mask(net.IPMask(mask))
+	hostIP[3]++
+	return hostIP.String(), nil
+}
@@ -0,0 +1,116 @@
+package vboxdriver_test
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/network"
+	"github.com/pivotal-cf/pcfdev-cli/vboxdriver"
+	"github.com/pivotal-cf/pcfdev-cli/vboxdriver/mocks"
+)
+
+var _ = Describe("NetworkModelForVersion", func() {
+	It("should only use host-only networks for VirtualBox 7 on macOS", func() {
+		Expect(vboxdriver.NetworkModelForVersion(&vboxdriver.VBoxDriverVersion{Major: 5, Minor: 1})).To(Equal(network.ModelHostOnlyInterface))
+		Expect(vboxdriver.NetworkModelForVersion(&vboxdriver.VBoxDriverVersion{Major: 6, Minor: 1})).To(Equal(network.ModelHostOnlyInterface))
+
+		if runtime.GOOS == "darwin" {
+			Expect(vboxdriver.NetworkModelForVersion(&vboxdriver.VBoxDriverVersion{Major: 7})).To(Equal(network.ModelHostOnlyNetwork))
+		} else {
+			Expect(vboxdriver.NetworkModelForVersion(&vboxdriver.VBoxDriverVersion{Major: 7})).To(Equal(network.ModelHostOnlyInterface))
+		}
+	})
+})
+
+var _ = Describe("HostOnlyNetworkRange", func() {
+	It("should leave the addresses below .100 for static use", func() {
+		lowerIP, upperIP := vboxdriver.HostOnlyNetworkRange("192.168.56.1")
+		Expect(lowerIP).To(Equal("192.168.56.100"))
+		Expect(upperIP).To(Equal("192.168.56.254"))
+	})
+})
+
+var _ = Describe("VBoxDriver host-only networking", func() {
+	var (
+		mockCtrl      *gomock.Controller
+		mockCmdRunner *mocks.MockCmdRunner
+		driver        *vboxdriver.VBoxDriver
+	)
+
+	BeforeEach(func() {
+		if runtime.GOOS == "windows" {
+			Skip("VBoxManage is looked up on the PATH on windows")
+		}
+
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockCmdRunner = mocks.NewMockCmdRunner(mockCtrl)
+		driver = &vboxdriver.VBoxDriver{CmdRunner: mockCmdRunner}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Context("when VirtualBox uses host-only interfaces", func() {
+		It("should only look up the version once", func() {
+			gomock.InOrder(
+				mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "--version").Return([]byte("5.1.22r115126\n"), nil),
+				mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "modifyvm", "some-vm", "--nic2", "hostonly", "--nictype2", "virtio", "--hostonlyadapter2", "vboxnet0"),
+				mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "hostonlyif", "ipconfig", "vboxnet0", "--ip", "192.168.11.1"),
+			)
+
+			Expect(driver.AttachNetworkInterface("vboxnet0", "some-vm")).To(Succeed())
+			Expect(driver.ConfigureHostOnlyInterface("vboxnet0", "192.168.11.1")).To(Succeed())
+		})
+
+		It("should only look up the version once when asked concurrently", func() {
+			mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "--version").Return([]byte("5.1.22r115126\n"), nil)
+
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+
+					Expect(driver.NetworkModel()).To(Equal(network.ModelHostOnlyInterface))
+				}()
+			}
+			wg.Wait()
+		})
+	})
+
+	Context("when VirtualBox uses host-only networks", func() {
+		BeforeEach(func() {
+			if runtime.GOOS != "darwin" {
+				Skip("VirtualBox only uses host-only networks on macOS")
+			}
+
+			mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "--version").Return([]byte("7.0.10r158379\n"), nil)
+		})
+
+		It("should create, list and attach host-only networks", func() {
+			gomock.InOrder(
+				mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "hostonlynet", "add", "--name", "pcfdev-192.168.56.1", "--netmask", "255.255.255.0", "--lower-ip", "192.168.56.100", "--upper-ip", "192.168.56.254", "--enable"),
+				mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "list", "hostonlynets").Return([]byte(
+					"Name:            pcfdev-192.168.56.1\n"+
+						"GUID:            some-guid\n"+
+						"State:           Enabled\n"+
+						"NetworkMask:     255.255.255.0\n"+
+						"LowerIP:         192.168.56.100\n"+
+						"UpperIP:         192.168.56.254\n"+
+						"VBoxNetworkName: hostonly-pcfdev-192.168.56.1\n\n"), nil),
+				mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "modifyvm", "some-vm", "--nic2", "hostonlynet", "--nictype2", "virtio", "--host-only-net2", "pcfdev-192.168.56.1"),
+			)
+
+			Expect(driver.CreateHostOnlyInterface("192.168.56.1")).To(Equal("pcfdev-192.168.56.1"))
+			Expect(driver.GetHostOnlyInterfaces()).To(Equal([]*network.Interface{
+				{Name: "pcfdev-192.168.56.1", IP: "192.168.56.1", Exists: true},
+			}))
+			Expect(driver.AttachNetworkInterface("pcfdev-192.168.56.1", "some-vm")).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,136 @@
+package vboxdriver
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/pivotal-cf/pcfdev-cli/network"
+)
+
+// NATNetworkName returns the name given to the NAT network whose gateway
+// address is ip.
+func NATNetworkName(ip string) string {
+	return "pcfdev-nat-" + ip
+}
+
+// GetBridgedInterfaces returns the host adapters that a VM can be bridged to,
+// which are the ones that are up and have an IPv4 address.
+func (d *VBoxDriver) GetBridgedInterfaces() (interfaces []*network.Interface, err error) {
+	output, err := d.VBoxManage("list", "bridgedifs")
+	if err != nil {
+		return nil, err
+	}
+
+	interfaces = []*network.Interface{}
+	for _, fields := range parseListBlocks(output) {
+		if fields["Name"] == "" || fields["Status"] != "Up" {
+			continue
+		}
+		if ip := net.ParseIP(fields["IPAddress"]).To4(); ip == nil || ip.IsUnspecified() {
+			continue
+		}
+		interfaces = append(interfaces, &network.Interface{
+			Name:            fields["Name"],
+			IP:              fields["IPAddress"],
+			HardwareAddress: fields["HardwareAddress"],
+			Exists:          true,
+		})
+	}
+
+	return interfaces, nil
+}
+
+func (d *VBoxDriver) AttachBridgedInterface(interfaceName string, vmName string) error {
+	_, err := d.VBoxManage("modifyvm", vmName, "--nic2", "bridged", "--nictype2", "virtio", "--bridgeadapter2", interfaceName)
+	return err
+}
+
+// GetNATNetworks returns the NAT networks known to VirtualBox, with the
+// gateway address of each network as its IP.
+func (d *VBoxDriver) GetNATNetworks() (networks []*network.Interface, err error) {
+	output, err := d.VBoxManage("list", "natnets")
+	if err != nil {
+		return nil, err
+	}
+
+	networks = []*network.Interface{}
+	for _, fields := range parseListBlocks(output) {
+		name := fields["NetworkName"]
+		if name == "" {
+			name = fields["Name"]
+		}
+		if name == "" {
+			continue
+		}
+
+		gatewayIP := fields["IP"]
+		if gatewayIP == "" {
+			gatewayIP = fields["Gateway"]
+		}
+		if gatewayIP == "" {
+			_, cidr, err := net.ParseCIDR(fields["Network"])
+			if err != nil {
+				return nil, fmt.Errorf("failed to determine the address of NAT network '%s': %s", name, err)
+			}
+			ip := cidr.IP.To4()
+			ip[3]++
+			gatewayIP = ip.String()
+		}
+
+		networks = append(networks, &network.Interface{
+			Name:   name,
+			IP:     gatewayIP,
+			Exists: true,
+		})
+	}
+
+	return networks, nil
+}
+
+// CreateNATNetwork creates a NAT network for the /24 subnet of ip. DHCP is
+// turned off because PCF Dev VMs are given a static address on the network.
+func (d *VBoxDriver) CreateNATNetwork(ip string) (networkName string, err error) {
+	name := NATNetworkName(ip)
+	if _, err := d.VBoxManage("natnetwork", "add", "--netname", name, "--network", natNetworkCIDR(ip), "--enable", "--dhcp", "off"); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+func (d *VBoxDriver) ConfigureNATNetwork(networkName string, ip string) error {
+	_, err := d.VBoxManage("natnetwork", "modify", "--netname", networkName, "--network", natNetworkCIDR(ip), "--enable", "--dhcp", "off")
+	return err
+}
+
+func (d *VBoxDriver) AttachNATNetwork(networkName string, vmName string) error {
+	_, err := d.VBoxManage("modifyvm", vmName, "--nic2", "natnetwork", "--nictype2", "virtio", "--nat-network2", networkName)
+	return err
+}
+
+// ForwardNATNetworkPort forwards hostPort on the host's loopback address to
+// guestPort on guestIP, replacing any earlier rule with the same name.
+func (d *VBoxDriver) ForwardNATNetworkPort(networkName string, ruleName string, hostPort string, guestIP string, guestPort string) error {
+	// The rule may not exist yet, so failing to delete it is not an error.
+	d.VBoxManage("natnetwork", "modify", "--netname", networkName, "--port-forward-4", "delete", ruleName)
+	_, err := d.VBoxManage("natnetwork", "modify", "--netname", networkName, "--port-forward-4", fmt.Sprintf("%s:tcp:[127.0.0.1]:%s:[%s]:%s", ruleName, hostPort, guestIP, guestPort))
+	return err
+}
+
+func natNetworkCIDR(ip string) string {
+	return ip[:strings.LastIndex(ip, ".")] + ".0/24"
+}
+
+func parseListBlocks(output []byte) []map[string]string {
+	blocks := []map[string]string{}
+	for _, block := range strings.Split(strings.Replace(string(output), "\r\n", "\n", -1), "\n\n") {
+		fields := map[string]string{}
+		for _, line := range strings.Split(block, "\n") {
+			if parts := strings.SplitN(line, ":", 2); len(parts) == 2 {
+				fields[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+			}
+		}
+		blocks = append(blocks, fields)
+	}
+	return blocks
+}
@@ -0,0 +1,107 @@
+package vboxdriver_test
+
+import (
+	"errors"
+	"runtime"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/network"
+	"github.com/pivotal-cf/pcfdev-cli/vboxdriver"
+	"github.com/pivotal-cf/pcfdev-cli/vboxdriver/mocks"
+)
+
+var _ = Describe("VBoxDriver network modes", func() {
+	var (
+		mockCtrl      *gomock.Controller
+		mockCmdRunner *mocks.MockCmdRunner
+		driver        *vboxdriver.VBoxDriver
+	)
+
+	BeforeEach(func() {
+		if runtime.GOOS == "windows" {
+			Skip("VBoxManage is looked up on the PATH on windows")
+		}
+
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockCmdRunner = mocks.NewMockCmdRunner(mockCtrl)
+		driver = &vboxdriver.VBoxDriver{CmdRunner: mockCmdRunner}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("bridged networking", func() {
+		It("should only list the adapters that are up and have an address", func() {
+			mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "list", "bridgedifs").Return([]byte(
+				"Name:            en0: Wi-Fi (AirPort)\n"+
+					"IPAddress:       192.168.1.23\n"+
+					"HardwareAddress: 8c:85:90:00:00:01\n"+
+					"Status:          Up\n\n"+
+					"Name:            en1: Thunderbolt 1\n"+
+					"IPAddress:       0.0.0.0\n"+
+					"Status:          Up\n\n"+
+					"Name:            en2: Ethernet\n"+
+					"IPAddress:       10.0.0.5\n"+
+					"Status:          Down\n\n"), nil)
+
+			Expect(driver.GetBridgedInterfaces()).To(Equal([]*network.Interface{
+				{Name: "en0: Wi-Fi (AirPort)", IP: "192.168.1.23", HardwareAddress: "8c:85:90:00:00:01", Exists: true},
+			}))
+		})
+
+		It("should attach the second NIC to the adapter", func() {
+			mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "modifyvm", "some-vm", "--nic2", "bridged", "--nictype2", "virtio", "--bridgeadapter2", "en0: Wi-Fi (AirPort)")
+
+			Expect(driver.AttachBridgedInterface("en0: Wi-Fi (AirPort)", "some-vm")).To(Succeed())
+		})
+	})
+
+	Describe("NAT networks", func() {
+		It("should create, list and attach NAT networks", func() {
+			gomock.InOrder(
+				mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "natnetwork", "add", "--netname", "pcfdev-nat-192.168.11.1", "--network", "192.168.11.0/24", "--enable", "--dhcp", "off"),
+				mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "natnetwork", "modify", "--netname", "pcfdev-nat-192.168.11.1", "--network", "192.168.11.0/24", "--enable", "--dhcp", "off"),
+				mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "list", "natnets").Return([]byte(
+					"NetworkName:    pcfdev-nat-192.168.11.1\n"+
+						"IP:             192.168.11.1\n"+
+						"Network:        192.168.11.0/24\n"+
+						"Enabled:        Yes\n\n"+
+						"Name:           NatNetwork\n"+
+						"Network:        10.0.2.0/24\n"+
+						"Enabled:        Yes\n\n"), nil),
+				mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "modifyvm", "some-vm", "--nic2", "natnetwork", "--nictype2", "virtio", "--nat-network2", "pcfdev-nat-192.168.11.1"),
+			)
+
+			Expect(driver.CreateNATNetwork("192.168.11.1")).To(Equal("pcfdev-nat-192.168.11.1"))
+			Expect(driver.ConfigureNATNetwork("pcfdev-nat-192.168.11.1", "192.168.11.1")).To(Succeed())
+			Expect(driver.GetNATNetworks()).To(Equal([]*network.Interface{
+				{Name: "pcfdev-nat-192.168.11.1", IP: "192.168.11.1", Exists: true},
+				{Name: "NatNetwork", IP: "10.0.2.1", Exists: true},
+			}))
+			Expect(driver.AttachNATNetwork("pcfdev-nat-192.168.11.1", "some-vm")).To(Succeed())
+		})
+
+		It("should replace port forwarding rules from the host's loopback address to the guest", func() {
+			gomock.InOrder(
+				mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "natnetwork", "modify", "--netname", "pcfdev-nat-192.168.11.1", "--port-forward-4", "delete", "http").Return(nil, errors.New("some-error")),
+				mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "natnetwork", "modify", "--netname", "pcfdev-nat-192.168.11.1", "--port-forward-4", "http:tcp:[127.0.0.1]:80:[192.168.11.11]:80"),
+			)
+
+			Expect(driver.ForwardNATNetworkPort("pcfdev-nat-192.168.11.1", "http", "80", "192.168.11.11", "80")).To(Succeed())
+		})
+
+		Context("when adding the rule fails", func() {
+			It("should return the error", func() {
+				gomock.InOrder(
+					mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "natnetwork", "modify", "--netname", "pcfdev-nat-192.168.11.1", "--port-forward-4", "delete", "http"),
+					mockCmdRunner.EXPECT().RunContext(gomock.Any(), "VBoxManage", "natnetwork", "modify", "--netname", "pcfdev-nat-192.168.11.1", "--port-forward-4", "http:tcp:[127.0.0.1]:80:[192.168.11.11]:80").Return(nil, errors.New("some-error")),
+				)
+
+				Expect(driver.ForwardNATNetworkPort("pcfdev-nat-192.168.11.1", "http", "80", "192.168.11.11", "80")).To(MatchError("some-error"))
+			})
+		})
+	})
+})
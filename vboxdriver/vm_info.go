@@ -0,0 +1,224 @@
+package vboxdriver
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// VMInfo is the parsed output of 'VBoxManage showvminfo --machinereadable'.
+type VMInfo struct {
+	Name               string
+	State              string
+	Memory             uint64
+	CPUs               int
+	NICs               []NIC
+	ForwardingRules    []ForwardingRule
+	StorageAttachments []StorageAttachment
+	Snapshots          []Snapshot
+	CurrentSnapshot    string
+}
+
+type NIC struct {
+	Index           int
+	Type            string
+	HostOnlyAdapter string
+	HostOnlyNetwork string
+	BridgeAdapter   string
+	NATNetwork      string
+	MACAddress      string
+}
+
+type ForwardingRule struct {
+	Name      string
+	Protocol  string
+	HostIP    string
+	HostPort  string
+	GuestIP   string
+	GuestPort string
+}
+
+type StorageAttachment struct {
+	Controller string
+	Port       int
+	Device     int
+	Medium     string
+	ImageUUID  string
+}
+
+type Snapshot struct {
+	Name string
+	UUID string
+}
+
+var (
+	nicRegex               = regexp.MustCompile(`^(nic|hostonlyadapter|hostonly-network|bridgeadapter|nat-network|macaddress)(\d+)$`)
+	forwardingRegex        = regexp.MustCompile(`^Forwarding\(\d+\)$`)
+	storageAttachmentRegex = regexp.MustCompile(`^(.+?)-(ImageUUID-)?(\d+)-(\d+)$`)
+	snapshotRegex          = regexp.MustCompile(`^Snapshot(Name|UUID)(-[\d-]+)?$`)
+)
+
+func (d *VBoxDriver) VMInfo(vmName string) (*VMInfo, error) {
+	output, err := d.VBoxManage("showvminfo", vmName, "--machinereadable")
+	if err != nil {
+		return nil, err
+	}
+	return ParseVMInfo(output)
+}
+
+func ParseVMInfo(output []byte) (*VMInfo, error) {
+	info := &VMInfo{}
+	nics := map[int]*NIC{}
+	attachments := map[string]*StorageAttachment{}
+	snapshots := map[string]*Snapshot{}
+	var attachmentKeys, snapshotKeys []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key, value := unquote(parts[0]), unquote(parts[1])
+
+		switch {
+		case key == "name":
+			info.Name = value
+		case key == "VMState":
+			info.State = value
+		case key == "memory":
+			memory, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse memory '%s': %s", value, err)
+			}
+			info.Memory = memory
+		case key == "cpus":
+			cpus, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse cpus '%s': %s", value, err)
+			}
+			info.CPUs = cpus
+		case key == "CurrentSnapshotName":
+			info.CurrentSnapshot = value
+		case nicRegex.MatchString(key):
+			matches := nicRegex.FindStringSubmatch(key)
+			index, _ := strconv.Atoi(matches[2])
+			nic, exists := nics[index]
+			if !exists {
+				nic = &NIC{Index: index}
+				nics[index] = nic
+			}
+			switch matches[1] {
+			case "nic":
+				nic.Type = value
+			case "hostonlyadapter":
+				nic.HostOnlyAdapter = value
+			case "hostonly-network":
+				nic.HostOnlyNetwork = value
+			case "bridgeadapter":
+				nic.BridgeAdapter = value
+			case "nat-network":
+				nic.NATNetwork = value
+			case "macaddress":
+				nic.MACAddress = value
+			}
+		case forwardingRegex.MatchString(key):
+			fields := strings.Split(value, ",")
+			if len(fields) != 6 {
+				return nil, fmt.Errorf("failed to parse forwarding rule '%s'", value)
+			}
+			info.ForwardingRules = append(info.ForwardingRules, ForwardingRule{
+				Name:      fields[0],
+				Protocol:  fields[1],
+				HostIP:    fields[2],
+				HostPort:  fields[3],
+				GuestIP:   fields[4],
+				GuestPort: fields[5],
+			})
+		case snapshotRegex.MatchString(key):
+			matches := snapshotRegex.FindStringSubmatch(key)
+			snapshot, exists := snapshots[matches[2]]
+			if !exists {
+				snapshot = &Snapshot{}
+				snapshots[matches[2]] = snapshot
+				snapshotKeys = append(snapshotKeys, matches[2])
+			}
+			if matches[1] == "Name" {
+				snapshot.Name = value
+			} else {
+				snapshot.UUID = value
+			}
+		case storageAttachmentRegex.MatchString(key) && value != "none":
+			matches := storageAttachmentRegex.FindStringSubmatch(key)
+			attachmentKey := fmt.Sprintf("%s-%s-%s", matches[1], matches[3], matches[4])
+			attachment, exists := attachments[attachmentKey]
+			if !exists {
+				port, _ := strconv.Atoi(matches[3])
+				device, _ := strconv.Atoi(matches[4])
+				attachment = &StorageAttachment{Controller: matches[1], Port: port, Device: device}
+				attachments[attachmentKey] = attachment
+				attachmentKeys = append(attachmentKeys, attachmentKey)
+			}
+			if matches[2] == "" {
+				attachment.Medium = value
+			} else {
+				attachment.ImageUUID = value
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	indexes := []int{}
+	for index := range nics {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	for _, index := range indexes {
+		if nics[index].Type != "" && nics[index].Type != "none" {
+			info.NICs = append(info.NICs, *nics[index])
+		}
+	}
+	for _, key := range attachmentKeys {
+		if attachments[key].Medium != "" {
+			info.StorageAttachments = append(info.StorageAttachments, *attachments[key])
+		}
+	}
+	for _, key := range snapshotKeys {
+		info.Snapshots = append(info.Snapshots, *snapshots[key])
+	}
+
+	return info, nil
+}
+
+// NIC returns the network adapter in the given slot, counting from 1 like
+// VBoxManage does.
+func (i *VMInfo) NIC(index int) (nic NIC, exists bool) {
+	for _, nic := range i.NICs {
+		if nic.Index == index {
+			return nic, true
+		}
+	}
+	return NIC{}, false
+}
+
+func (i *VMInfo) ForwardingRule(name string) (rule ForwardingRule, exists bool) {
+	for _, rule := range i.ForwardingRules {
+		if rule.Name == name {
+			return rule, true
+		}
+	}
+	return ForwardingRule{}, false
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && strings.HasPrefix(s, `"`) && strings.HasSuffix(s, `"`) {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,95 @@
+package vboxdriver_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pcfdev-cli/vboxdriver"
+)
+
+var _ = Describe("ParseVMInfo", func() {
+	It("should parse the machine-readable output of showvminfo", func() {
+		info, err := vboxdriver.ParseVMInfo([]byte(`name="pcfdev-v0.0.0"
+groups="/"
+ostype="Ubuntu (64-bit)"
+memory=4096
+cpus=2
+VMState="running"
+VMStateChangeTime="2016-06-01T12:00:00.000000000"
+"SATA-0-0"="C:\Users\some-user\VirtualBox VMs\pcfdev-v0.0.0\pcfdev-disk1.vmdk"
+"SATA-ImageUUID-0-0"="5e8a7b2c-4d8f-4c2a-9b1e-3f0d2c1b0a99"
+"IDE-1-0"="none"
+natnet1="nat"
+macaddress1="080027A1B2C3"
+nic1="nat"
+Forwarding(0)="ssh,tcp,127.0.0.1,60001,,22"
+Forwarding(1)="some-rule,udp,,5353,10.0.2.15,53"
+hostonlyadapter2="vboxnet1"
+macaddress2="080027D4E5F6"
+nic2="hostonly"
+nic3="none"
+hostonly-network4="pcfdev-192.168.56.1"
+nic4="hostonlynet"
+bridgeadapter5="en0: Wi-Fi (AirPort)"
+nic5="bridged"
+nat-network6="pcfdev-nat-192.168.11.1"
+nic6="natnetwork"
+SnapshotName="some-snapshot"
+SnapshotUUID="11111111-1111-1111-1111-111111111111"
+SnapshotName-1="some-other-snapshot"
+SnapshotUUID-1="22222222-2222-2222-2222-222222222222"
+CurrentSnapshotName="some-other-snapshot"
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info).To(Equal(&vboxdriver.VMInfo{
+			Name:   "pcfdev-v0.0.0",
+			State:  vboxdriver.StateRunning,
+			Memory: 4096,
+			CPUs:   2,
+			NICs: []vboxdriver.NIC{
+				{Index: 1, Type: "nat", MACAddress: "080027A1B2C3"},
+				{Index: 2, Type: "hostonly", HostOnlyAdapter: "vboxnet1", MACAddress: "080027D4E5F6"},
+				{Index: 4, Type: "hostonlynet", HostOnlyNetwork: "pcfdev-192.168.56.1"},
+				{Index: 5, Type: "bridged", BridgeAdapter: "en0: Wi-Fi (AirPort)"},
+				{Index: 6, Type: "natnetwork", NATNetwork: "pcfdev-nat-192.168.11.1"},
+			},
+			ForwardingRules: []vboxdriver.ForwardingRule{
+				{Name: "ssh", Protocol: "tcp", HostIP: "127.0.0.1", HostPort: "60001", GuestPort: "22"},
+				{Name: "some-rule", Protocol: "udp", HostPort: "5353", GuestIP: "10.0.2.15", GuestPort: "53"},
+			},
+			StorageAttachments: []vboxdriver.StorageAttachment{
+				{
+					Controller: "SATA",
+					Medium:     `C:\Users\some-user\VirtualBox VMs\pcfdev-v0.0.0\pcfdev-disk1.vmdk`,
+					ImageUUID:  "5e8a7b2c-4d8f-4c2a-9b1e-3f0d2c1b0a99",
+				},
+			},
+			Snapshots: []vboxdriver.Snapshot{
+				{Name: "some-snapshot", UUID: "11111111-1111-1111-1111-111111111111"},
+				{Name: "some-other-snapshot", UUID: "22222222-2222-2222-2222-222222222222"},
+			},
+			CurrentSnapshot: "some-other-snapshot",
+		}))
+
+		rule, exists := info.ForwardingRule("ssh")
+		Expect(exists).To(BeTrue())
+		Expect(rule.HostPort).To(Equal("60001"))
+		_, exists = info.ForwardingRule("some-bad-rule")
+		Expect(exists).To(BeFalse())
+		_, exists = info.NIC(3)
+		Expect(exists).To(BeFalse())
+	})
+
+	Context("when the memory is not a number", func() {
+		It("should return an error", func() {
+			_, err := vboxdriver.ParseVMInfo([]byte("memory=some-memory\n"))
+			Expect(err).To(MatchError(ContainSubstring("failed to parse memory 'some-memory'")))
+		})
+	})
+
+	Context("when a forwarding rule is malformed", func() {
+		It("should return an error", func() {
+			_, err := vboxdriver.ParseVMInfo([]byte(`Forwarding(0)="ssh,tcp"` + "\n"))
+			Expect(err).To(MatchError("failed to parse forwarding rule 'ssh,tcp'"))
+		})
+	})
+})
@@ -1,29 +1,31 @@
 package vm
 
 import (
+	"context"
 	"errors"
+	"github.com/pivotal-cf/pcfdev-cli/backup"
 	"github.com/pivotal-cf/pcfdev-cli/cert"
 	"github.com/pivotal-cf/pcfdev-cli/config"
 	"github.com/pivotal-cf/pcfdev-cli/debug"
 	"github.com/pivotal-cf/pcfdev-cli/fs"
 	"github.com/pivotal-cf/pcfdev-cli/network"
-	"github.com/pivotal-cf/pcfdev-cli/runner"
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
 	"github.com/pivotal-cf/pcfdev-cli/ui"
 	"github.com/pivotal-cf/pcfdev-cli/vbox"
-	"github.com/pivotal-cf/pcfdev-cli/vboxdriver"
 	"path/filepath"
 )
 
 type VBoxBuilder struct {
-	Config *config.Config
-	VBox   VBox
-	FS     FS
-	SSH    SSH
-	Client Client
-	UI     UI
+	Config    *config.Config
+	VBox      VBox
+	FS        FS
+	SSH       SSH
+	Client    Client
+	UI        UI
+	CmdRunner CmdRunner
 }
 
-func (b *VBoxBuilder) VM(vmName string) (VM, error) {
+func (b *VBoxBuilder) VM(ctx context.Context, vmName string) (VM, error) {
 	status, err := b.VBox.VMStatus(vmName)
 	if err != nil {
 		return nil, err
@@ -48,14 +50,11 @@ func (b *VBoxBuilder) VM(vmName string) (VM, error) {
 		},
 		Client: b.Client,
 		LogFetcher: &debug.LogFetcher{
-			VMConfig: vmConfig,
-			Config:   b.Config,
-			FS:       b.FS,
-			SSH:      b.SSH,
-			Driver: &vboxdriver.VBoxDriver{
-				FS:        &fs.FS{},
-				CmdRunner: &runner.CmdRunner{},
-			},
+			VMConfig:   vmConfig,
+			Config:     b.Config,
+			FS:         b.FS,
+			SSH:        b.SSH,
+			HostLogger: b.VBox,
 		},
 	}
 	runningVm := &Running{
@@ -66,7 +65,7 @@ func (b *VBoxBuilder) VM(vmName string) (VM, error) {
 		VBox:      b.VBox,
 		SSHClient: b.SSH,
 		Builder:   b,
-		CmdRunner: &runner.CmdRunner{},
+		CmdRunner: b.CmdRunner,
 		HelpText: &ui.HelpText{
 			UI: b.UI,
 		},
@@ -74,18 +73,21 @@ func (b *VBoxBuilder) VM(vmName string) (VM, error) {
 			FS: b.FS,
 			SystemStore: &cert.ConcreteSystemStore{
 				FS:        b.FS,
-				CmdRunner: &runner.CmdRunner{},
+				CmdRunner: b.CmdRunner,
 			},
 		},
 		LogFetcher: &debug.LogFetcher{
+			VMConfig:   vmConfig,
+			Config:     b.Config,
+			FS:         b.FS,
+			SSH:        b.SSH,
+			HostLogger: b.VBox,
+		},
+		Archiver: &backup.Archiver{
 			VMConfig: vmConfig,
 			Config:   b.Config,
-			FS:       b.FS,
+			FS:       &fs.FS{},
 			SSH:      b.SSH,
-			Driver: &vboxdriver.VBoxDriver{
-				FS:        &fs.FS{},
-				CmdRunner: &runner.CmdRunner{},
-			},
 		},
 	}
 
@@ -119,7 +121,7 @@ func (b *VBoxBuilder) VM(vmName string) (VM, error) {
 			}, nil
 		}
 
-		output, err := b.Client.Status(vmConfig.IP, key)
+		output, err := b.Client.Status(ctx, ssh.SSHAddresses(vmConfig), key)
 
 		if output == "Unprovisioned" || err != nil {
 			return unprovisionedVm, nil
@@ -1,9 +1,12 @@
 package vm_test
 
 import (
+	"context"
 	"errors"
 	"github.com/golang/mock/gomock"
 	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/debug"
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
 	"github.com/pivotal-cf/pcfdev-cli/vbox"
 	"github.com/pivotal-cf/pcfdev-cli/vm"
 	"github.com/pivotal-cf/pcfdev-cli/vm/mocks"
@@ -22,6 +25,7 @@ var _ = Describe("Builder", func() {
 			mockSSH    *mocks.MockSSH
 			mockClient *mocks.MockClient
 			mockUI     *mocks.MockUI
+			mockRunner *mocks.MockCmdRunner
 			builder    *vm.VBoxBuilder
 			conf       *config.Config
 		)
@@ -33,6 +37,7 @@ var _ = Describe("Builder", func() {
 			mockSSH = mocks.NewMockSSH(mockCtrl)
 			mockClient = mocks.NewMockClient(mockCtrl)
 			mockUI = mocks.NewMockUI(mockCtrl)
+			mockRunner = mocks.NewMockCmdRunner(mockCtrl)
 			conf = &config.Config{
 				MinMemory:      100,
 				MaxMemory:      200,
@@ -41,12 +46,13 @@ var _ = Describe("Builder", func() {
 			}
 
 			builder = &vm.VBoxBuilder{
-				VBox:   mockVBox,
-				FS:     mockFS,
-				SSH:    mockSSH,
-				Client: mockClient,
-				UI:     mockUI,
-				Config: conf,
+				VBox:      mockVBox,
+				FS:        mockFS,
+				SSH:       mockSSH,
+				Client:    mockClient,
+				UI:        mockUI,
+				CmdRunner: mockRunner,
+				Config:    conf,
 			}
 		})
 
@@ -61,7 +67,7 @@ var _ = Describe("Builder", func() {
 					mockFS.EXPECT().Exists(filepath.Join("some-vm-dir", "some-vm")).Return(false, nil),
 				)
 
-				notCreatedVM, err := builder.VM("some-vm")
+				notCreatedVM, err := builder.VM(context.Background(), "some-vm")
 				Expect(err).NotTo(HaveOccurred())
 
 				switch u := notCreatedVM.(type) {
@@ -79,7 +85,7 @@ var _ = Describe("Builder", func() {
 						mockFS.EXPECT().Exists(filepath.Join("some-vm-dir", "some-vm")).Return(true, nil),
 					)
 
-					invalidVM, err := builder.VM("some-vm")
+					invalidVM, err := builder.VM(context.Background(), "some-vm")
 					Expect(err).NotTo(HaveOccurred())
 
 					switch u := invalidVM.(type) {
@@ -98,7 +104,7 @@ var _ = Describe("Builder", func() {
 						mockFS.EXPECT().Exists(filepath.Join("some-vm-dir", "some-vm")).Return(false, errors.New("some-error")),
 					)
 
-					_, err := builder.VM("some-vm")
+					_, err := builder.VM(context.Background(), "some-vm")
 					Expect(err).To(MatchError("some-error"))
 				})
 			})
@@ -113,7 +119,7 @@ var _ = Describe("Builder", func() {
 						mockVBox.EXPECT().VMConfig("some-vm").Return(expectedVMConfig, nil),
 					)
 
-					stoppedVM, err := builder.VM("some-vm")
+					stoppedVM, err := builder.VM(context.Background(), "some-vm")
 					Expect(err).NotTo(HaveOccurred())
 
 					switch u := stoppedVM.(type) {
@@ -135,7 +141,7 @@ var _ = Describe("Builder", func() {
 						mockVBox.EXPECT().VMConfig("some-vm").Return(nil, errors.New("some-error")),
 					)
 
-					invalidVM, err := builder.VM("some-vm")
+					invalidVM, err := builder.VM(context.Background(), "some-vm")
 					Expect(err).NotTo(HaveOccurred())
 
 					switch u := invalidVM.(type) {
@@ -160,10 +166,10 @@ var _ = Describe("Builder", func() {
 						mockVBox.EXPECT().VMStatus("some-vm").Return(vbox.StatusRunning, nil),
 						mockVBox.EXPECT().VMConfig("some-vm").Return(expectedVMConfig, nil),
 						mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-						mockClient.EXPECT().Status("192.168.11.11", []byte("some-private-key")).Return("Running", nil),
+						mockClient.EXPECT().Status(gomock.Any(), []ssh.SSHAddress{{IP: "127.0.0.1", Port: "some-port"}, {IP: "192.168.11.11", Port: "22"}}, []byte("some-private-key")).Return("Running", nil),
 					)
 
-					runningVM, err := builder.VM("some-vm")
+					runningVM, err := builder.VM(context.Background(), "some-vm")
 					Expect(err).NotTo(HaveOccurred())
 
 					switch u := runningVM.(type) {
@@ -174,10 +180,11 @@ var _ = Describe("Builder", func() {
 						Expect(u.UI).NotTo(BeNil())
 						Expect(u.SSHClient).NotTo(BeNil())
 						Expect(u.FS).NotTo(BeNil())
-						Expect(u.LogFetcher).NotTo(BeNil())
+						Expect(u.LogFetcher.(*debug.LogFetcher).HostLogger).To(BeIdenticalTo(mockVBox))
+						Expect(u.Archiver).NotTo(BeNil())
 						Expect(u.Builder).NotTo(BeNil())
 						Expect(u.CertStore).NotTo(BeNil())
-						Expect(u.CmdRunner).NotTo(BeNil())
+						Expect(u.CmdRunner).To(BeIdenticalTo(mockRunner))
 						Expect(u.HelpText).NotTo(BeNil())
 					default:
 						Fail("wrong type")
@@ -198,17 +205,17 @@ var _ = Describe("Builder", func() {
 						mockVBox.EXPECT().VMStatus("some-vm").Return(vbox.StatusRunning, nil),
 						mockVBox.EXPECT().VMConfig("some-vm").Return(expectedVMConfig, nil),
 						mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-						mockClient.EXPECT().Status("192.168.11.11", []byte("some-private-key")).Return("Unprovisioned", nil),
+						mockClient.EXPECT().Status(gomock.Any(), []ssh.SSHAddress{{IP: "127.0.0.1", Port: "some-port"}, {IP: "192.168.11.11", Port: "22"}}, []byte("some-private-key")).Return("Unprovisioned", nil),
 					)
 
-					unprovisionedVM, err := builder.VM("some-vm")
+					unprovisionedVM, err := builder.VM(context.Background(), "some-vm")
 					Expect(err).NotTo(HaveOccurred())
 
 					switch u := unprovisionedVM.(type) {
 					case *vm.Unprovisioned:
 						Expect(u.UI).NotTo(BeNil())
 						Expect(u.VBox).NotTo(BeNil())
-						Expect(u.LogFetcher).NotTo(BeNil())
+						Expect(u.LogFetcher.(*debug.LogFetcher).HostLogger).To(BeIdenticalTo(mockVBox))
 						Expect(u.VMConfig).To(BeIdenticalTo(expectedVMConfig))
 						Expect(u.Client).To(BeIdenticalTo(builder.Client))
 					default:
@@ -230,10 +237,10 @@ var _ = Describe("Builder", func() {
 						mockVBox.EXPECT().VMStatus("some-vm").Return(vbox.StatusRunning, nil),
 						mockVBox.EXPECT().VMConfig("some-vm").Return(expectedVMConfig, nil),
 						mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-						mockClient.EXPECT().Status("192.168.11.11", []byte("some-private-key")).Return("some-unexpected-status", nil),
+						mockClient.EXPECT().Status(gomock.Any(), []ssh.SSHAddress{{IP: "127.0.0.1", Port: "some-port"}, {IP: "192.168.11.11", Port: "22"}}, []byte("some-private-key")).Return("some-unexpected-status", nil),
 					)
 
-					invalidVM, err := builder.VM("some-vm")
+					invalidVM, err := builder.VM(context.Background(), "some-vm")
 					Expect(err).NotTo(HaveOccurred())
 
 					switch u := invalidVM.(type) {
@@ -260,7 +267,7 @@ var _ = Describe("Builder", func() {
 						mockFS.EXPECT().Read("some-private-key-path").Return(nil, errors.New("some-error")),
 					)
 
-					invalidVM, err := builder.VM("some-vm")
+					invalidVM, err := builder.VM(context.Background(), "some-vm")
 					Expect(err).NotTo(HaveOccurred())
 
 					switch u := invalidVM.(type) {
@@ -285,10 +292,10 @@ var _ = Describe("Builder", func() {
 						mockVBox.EXPECT().VMStatus("some-vm").Return(vbox.StatusRunning, nil),
 						mockVBox.EXPECT().VMConfig("some-vm").Return(expectedVMConfig, nil),
 						mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-						mockClient.EXPECT().Status("192.168.11.11", []byte("some-private-key")).Return("", errors.New("some-error")),
+						mockClient.EXPECT().Status(gomock.Any(), []ssh.SSHAddress{{IP: "127.0.0.1", Port: "some-port"}, {IP: "192.168.11.11", Port: "22"}}, []byte("some-private-key")).Return("", errors.New("some-error")),
 					)
 
-					unprovisionedVM, err := builder.VM("some-vm")
+					unprovisionedVM, err := builder.VM(context.Background(), "some-vm")
 					Expect(err).NotTo(HaveOccurred())
 
 					switch u := unprovisionedVM.(type) {
@@ -317,7 +324,7 @@ var _ = Describe("Builder", func() {
 						mockVBox.EXPECT().VMConfig("some-vm").Return(expectedVMConfig, nil),
 					)
 
-					pausedVM, err := builder.VM("some-vm")
+					pausedVM, err := builder.VM(context.Background(), "some-vm")
 					Expect(err).NotTo(HaveOccurred())
 
 					switch u := pausedVM.(type) {
@@ -346,7 +353,7 @@ var _ = Describe("Builder", func() {
 						mockVBox.EXPECT().VMConfig("some-vm").Return(expectedVMConfig, nil),
 					)
 
-					savedVM, err := builder.VM("some-vm")
+					savedVM, err := builder.VM(context.Background(), "some-vm")
 					Expect(err).NotTo(HaveOccurred())
 
 					switch u := savedVM.(type) {
@@ -375,7 +382,7 @@ var _ = Describe("Builder", func() {
 						mockVBox.EXPECT().VMConfig("some-vm").Return(expectedVMConfig, nil),
 					)
 
-					invalidVM, err := builder.VM("some-vm")
+					invalidVM, err := builder.VM(context.Background(), "some-vm")
 					Expect(err).NotTo(HaveOccurred())
 
 					switch u := invalidVM.(type) {
@@ -391,7 +398,7 @@ var _ = Describe("Builder", func() {
 				It("should return an error", func() {
 					mockVBox.EXPECT().VMStatus("some-vm").Return("", errors.New("some-error"))
 
-					_, err := builder.VM("some-vm")
+					_, err := builder.VM(context.Background(), "some-vm")
 					Expect(err).To(MatchError("some-error"))
 				})
 			})
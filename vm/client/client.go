@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -13,13 +14,14 @@ import (
 
 //go:generate mockgen -package mocks -destination mocks/ssh.go github.com/pivotal-cf/pcfdev-cli/vm/client SSH
 type SSH interface {
-	WithSSHTunnel(remoteAddress string, sshAddresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration, block func(forwardingAddress string)) error
+	WithSSHTunnel(ctx context.Context, remoteAddress string, sshAddresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration, block func(forwardingAddress string)) error
 }
 
 type Client struct {
-	Timeout    time.Duration
-	HttpClient *http.Client
-	SSHClient  SSH
+	Timeout       time.Duration
+	TunnelTimeout time.Duration
+	HttpClient    *http.Client
+	SSHClient     SSH
 }
 
 type StatusResponse struct {
@@ -28,17 +30,23 @@ type StatusResponse struct {
 
 const APIPort = 8090
 
-func (c *Client) Status(sshIP string, privateKey []byte) (string, error) {
+func (c *Client) Status(ctx context.Context, sshAddresses []ssh.SSHAddress, privateKey []byte) (string, error) {
 	var resp *http.Response
 	var errorInTunnel error
 	errorWithTunnel := c.SSHClient.WithSSHTunnel(
+		ctx,
 		fmt.Sprintf("127.0.0.1:%d", APIPort),
-		[]ssh.SSHAddress{{IP: sshIP, Port: "22"}},
+		sshAddresses,
 		privateKey,
-		time.Minute,
+		c.TunnelTimeout,
 		func(host string) {
-			var err error
-			resp, err = c.HttpClient.Get(fmt.Sprintf("%s/status", host))
+			req, err := http.NewRequest("GET", fmt.Sprintf("%s/status", host), nil)
+			if err != nil {
+				errorInTunnel = err
+				return
+			}
+
+			resp, err = c.HttpClient.Do(req.WithContext(ctx))
 			if err != nil {
 				errorInTunnel = &PCFDevVmUnreachableError{err}
 			}
@@ -73,14 +81,15 @@ func (c *Client) Status(sshIP string, privateKey []byte) (string, error) {
 	}
 }
 
-func (c *Client) ReplaceSecrets(sshIP, password string, privateKey []byte) error {
+func (c *Client) ReplaceSecrets(ctx context.Context, sshAddresses []ssh.SSHAddress, password string, privateKey []byte) error {
 	var resp *http.Response
 	var errorInTunnel error
 	errorWithTunnel := c.SSHClient.WithSSHTunnel(
+		ctx,
 		fmt.Sprintf("127.0.0.1:%d", APIPort),
-		[]ssh.SSHAddress{{IP: sshIP, Port: "22"}},
+		sshAddresses,
 		privateKey,
-		time.Minute,
+		c.TunnelTimeout,
 		func(host string) {
 			uri := fmt.Sprintf("%s/replace-secrets", host)
 
@@ -91,7 +100,7 @@ func (c *Client) ReplaceSecrets(sshIP, password string, privateKey []byte) error
 				return
 			}
 
-			resp, err = http.DefaultClient.Do(req)
+			resp, err = c.HttpClient.Do(req.WithContext(ctx))
 			if err != nil {
 				errorInTunnel = &PCFDevVmUnreachableError{err}
 			}
@@ -1,6 +1,7 @@
 package client_test
 
 import (
+	"context"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -28,9 +29,10 @@ var _ = Describe("PCF Dev Client", func() {
 		mockCtrl = gomock.NewController(GinkgoT())
 		mockSSH = mocks.NewMockSSH(mockCtrl)
 		client = &c.Client{
-			Timeout:    time.Millisecond,
-			HttpClient: http.DefaultClient,
-			SSHClient:  mockSSH,
+			Timeout:       time.Millisecond,
+			TunnelTimeout: 3 * time.Minute,
+			HttpClient:    http.DefaultClient,
+			SSHClient:     mockSSH,
 		}
 	})
 
@@ -57,17 +59,17 @@ var _ = Describe("PCF Dev Client", func() {
 
 			host := httptest.NewServer(http.HandlerFunc(handler)).URL
 
-			mockSSH.EXPECT().WithSSHTunnel(
+			mockSSH.EXPECT().WithSSHTunnel(gomock.Any(),
 				fmt.Sprintf("127.0.0.1:%d", c.APIPort),
 				[]ssh.SSHAddress{{IP: "some-ip", Port: "22"}},
 				[]byte("some-private-key"),
-				time.Minute,
+				3*time.Minute,
 				gomock.Any(),
-			).Do(func(_ string, _ []ssh.SSHAddress, _ []byte, _ time.Duration, block func(string)) {
+			).Do(func(_ context.Context, _ string, _ []ssh.SSHAddress, _ []byte, _ time.Duration, block func(string)) {
 				block(host)
 			})
 
-			status, err := client.Status("some-ip", []byte("some-private-key"))
+			status, err := client.Status(context.Background(), []ssh.SSHAddress{{IP: "some-ip", Port: "22"}}, []byte("some-private-key"))
 			Expect(err).NotTo(HaveOccurred())
 			Expect(status).To(Equal("some-status"))
 		})
@@ -76,17 +78,17 @@ var _ = Describe("PCF Dev Client", func() {
 			It("return an error", func() {
 				host := "http://some-bad-host"
 
-				mockSSH.EXPECT().WithSSHTunnel(
+				mockSSH.EXPECT().WithSSHTunnel(gomock.Any(),
 					fmt.Sprintf("127.0.0.1:%d", c.APIPort),
 					[]ssh.SSHAddress{{IP: "some-ip", Port: "22"}},
 					[]byte("some-private-key"),
-					time.Minute,
+					3*time.Minute,
 					gomock.Any(),
-				).Do(func(_ string, _ []ssh.SSHAddress, _ []byte, _ time.Duration, block func(string)) {
+				).Do(func(_ context.Context, _ string, _ []ssh.SSHAddress, _ []byte, _ time.Duration, block func(string)) {
 					block(host)
 				})
 
-				_, err := client.Status("some-ip", []byte("some-private-key"))
+				_, err := client.Status(context.Background(), []ssh.SSHAddress{{IP: "some-ip", Port: "22"}}, []byte("some-private-key"))
 				errorMessage := err.Error()
 				Expect(errorMessage).To(ContainSubstring("failed to talk to PCF Dev VM"))
 				Expect(errorMessage).To(MatchRegexp(`[nN]o such host`))
@@ -111,17 +113,17 @@ var _ = Describe("PCF Dev Client", func() {
 				}
 				host := httptest.NewServer(http.HandlerFunc(handler)).URL
 
-				mockSSH.EXPECT().WithSSHTunnel(
+				mockSSH.EXPECT().WithSSHTunnel(gomock.Any(),
 					fmt.Sprintf("127.0.0.1:%d", c.APIPort),
 					[]ssh.SSHAddress{{IP: "some-ip", Port: "22"}},
 					[]byte("some-private-key"),
-					time.Minute,
+					3*time.Minute,
 					gomock.Any(),
-				).Do(func(_ string, _ []ssh.SSHAddress, _ []byte, _ time.Duration, block func(string)) {
+				).Do(func(_ context.Context, _ string, _ []ssh.SSHAddress, _ []byte, _ time.Duration, block func(string)) {
 					block(host)
 				})
 
-				_, err := client.Status("some-ip", []byte("some-private-key"))
+				_, err := client.Status(context.Background(), []ssh.SSHAddress{{IP: "some-ip", Port: "22"}}, []byte("some-private-key"))
 				Expect(err).To(MatchError(ContainSubstring("failed to parse JSON response:")))
 
 			})
@@ -144,32 +146,32 @@ var _ = Describe("PCF Dev Client", func() {
 				}
 				host := httptest.NewServer(http.HandlerFunc(handler)).URL
 
-				mockSSH.EXPECT().WithSSHTunnel(
+				mockSSH.EXPECT().WithSSHTunnel(gomock.Any(),
 					fmt.Sprintf("127.0.0.1:%d", c.APIPort),
 					[]ssh.SSHAddress{{IP: "some-ip", Port: "22"}},
 					[]byte("some-private-key"),
-					time.Minute,
+					3*time.Minute,
 					gomock.Any(),
-				).Do(func(_ string, _ []ssh.SSHAddress, _ []byte, _ time.Duration, block func(string)) {
+				).Do(func(_ context.Context, _ string, _ []ssh.SSHAddress, _ []byte, _ time.Duration, block func(string)) {
 					block(host)
 				})
 
-				_, err := client.Status("some-ip", []byte("some-private-key"))
+				_, err := client.Status(context.Background(), []ssh.SSHAddress{{IP: "some-ip", Port: "22"}}, []byte("some-private-key"))
 				Expect(err).To(MatchError("failed to retrieve status: PCF Dev API returned: 500"))
 			})
 		})
 
 		Context("when there is an error establishing the SSH tunnel", func() {
 			It("should return the error", func() {
-				mockSSH.EXPECT().WithSSHTunnel(
+				mockSSH.EXPECT().WithSSHTunnel(gomock.Any(),
 					fmt.Sprintf("127.0.0.1:%d", c.APIPort),
 					[]ssh.SSHAddress{{IP: "some-ip", Port: "22"}},
 					[]byte("some-private-key"),
-					time.Minute,
+					3*time.Minute,
 					gomock.Any(),
 				).Return(errors.New("some-error"))
 
-				_, err := client.Status("some-ip", []byte("some-private-key"))
+				_, err := client.Status(context.Background(), []ssh.SSHAddress{{IP: "some-ip", Port: "22"}}, []byte("some-private-key"))
 				Expect(err).To(MatchError("some-error"))
 			})
 		})
@@ -194,34 +196,34 @@ var _ = Describe("PCF Dev Client", func() {
 
 			host := httptest.NewServer(http.HandlerFunc(handler)).URL
 
-			mockSSH.EXPECT().WithSSHTunnel(
+			mockSSH.EXPECT().WithSSHTunnel(gomock.Any(),
 				fmt.Sprintf("127.0.0.1:%d", c.APIPort),
 				[]ssh.SSHAddress{{IP: "some-ip", Port: "22"}},
 				[]byte("some-private-key"),
-				time.Minute,
+				3*time.Minute,
 				gomock.Any(),
-			).Do(func(_ string, _ []ssh.SSHAddress, _ []byte, _ time.Duration, block func(string)) {
+			).Do(func(_ context.Context, _ string, _ []ssh.SSHAddress, _ []byte, _ time.Duration, block func(string)) {
 				block(host)
 			})
 
-			Expect(client.ReplaceSecrets("some-ip", "some-master-password", []byte("some-private-key"))).To(Succeed())
+			Expect(client.ReplaceSecrets(context.Background(), []ssh.SSHAddress{{IP: "some-ip", Port: "22"}}, "some-master-password", []byte("some-private-key"))).To(Succeed())
 		})
 
 		Context("when there is a bad response from the api", func() {
 			It("should return an error", func() {
 				host := "http://some-bad-host"
 
-				mockSSH.EXPECT().WithSSHTunnel(
+				mockSSH.EXPECT().WithSSHTunnel(gomock.Any(),
 					fmt.Sprintf("127.0.0.1:%d", c.APIPort),
 					[]ssh.SSHAddress{{IP: "some-ip", Port: "22"}},
 					[]byte("some-private-key"),
-					time.Minute,
+					3*time.Minute,
 					gomock.Any(),
-				).Do(func(_ string, _ []ssh.SSHAddress, _ []byte, _ time.Duration, block func(string)) {
+				).Do(func(_ context.Context, _ string, _ []ssh.SSHAddress, _ []byte, _ time.Duration, block func(string)) {
 					block(host)
 				})
 
-				Expect(client.ReplaceSecrets("some-ip", "some-master-password", []byte("some-private-key"))).To(MatchError(ContainSubstring("failed to talk to PCF Dev VM:")))
+				Expect(client.ReplaceSecrets(context.Background(), []ssh.SSHAddress{{IP: "some-ip", Port: "22"}}, "some-master-password", []byte("some-private-key"))).To(MatchError(ContainSubstring("failed to talk to PCF Dev VM:")))
 			})
 		})
 
@@ -238,31 +240,31 @@ var _ = Describe("PCF Dev Client", func() {
 
 				host := httptest.NewServer(http.HandlerFunc(handler)).URL
 
-				mockSSH.EXPECT().WithSSHTunnel(
+				mockSSH.EXPECT().WithSSHTunnel(gomock.Any(),
 					fmt.Sprintf("127.0.0.1:%d", c.APIPort),
 					[]ssh.SSHAddress{{IP: "some-ip", Port: "22"}},
 					[]byte("some-private-key"),
-					time.Minute,
+					3*time.Minute,
 					gomock.Any(),
-				).Do(func(_ string, _ []ssh.SSHAddress, _ []byte, _ time.Duration, block func(string)) {
+				).Do(func(_ context.Context, _ string, _ []ssh.SSHAddress, _ []byte, _ time.Duration, block func(string)) {
 					block(host)
 				})
 
-				Expect(client.ReplaceSecrets("some-ip", "some-master-password", []byte("some-private-key"))).To(MatchError("failed to replace master password: PCF Dev API returned: 500"))
+				Expect(client.ReplaceSecrets(context.Background(), []ssh.SSHAddress{{IP: "some-ip", Port: "22"}}, "some-master-password", []byte("some-private-key"))).To(MatchError("failed to replace master password: PCF Dev API returned: 500"))
 			})
 		})
 
 		Context("when there is an error establishing the SSH tunnel", func() {
 			It("should return the error", func() {
-				mockSSH.EXPECT().WithSSHTunnel(
+				mockSSH.EXPECT().WithSSHTunnel(gomock.Any(),
 					fmt.Sprintf("127.0.0.1:%d", c.APIPort),
 					[]ssh.SSHAddress{{IP: "some-ip", Port: "22"}},
 					[]byte("some-private-key"),
-					time.Minute,
+					3*time.Minute,
 					gomock.Any(),
 				).Return(errors.New("some-error"))
 
-				Expect(client.ReplaceSecrets("some-ip", "some-master-password", []byte("some-private-key"))).To(MatchError("some-error"))
+				Expect(client.ReplaceSecrets(context.Background(), []ssh.SSHAddress{{IP: "some-ip", Port: "22"}}, "some-master-password", []byte("some-private-key"))).To(MatchError("some-error"))
 			})
 		})
 	})
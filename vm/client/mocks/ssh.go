@@ -4,6 +4,7 @@
 package mocks
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 	ssh "github.com/pivotal-cf/pcfdev-cli/ssh"
 	time "time"
@@ -30,12 +31,12 @@ func (_m *MockSSH) EXPECT() *_MockSSHRecorder {
 	return _m.recorder
 }
 
-func (_m *MockSSH) WithSSHTunnel(_param0 string, _param1 []ssh.SSHAddress, _param2 []byte, _param3 time.Duration, _param4 func(string)) error {
-	ret := _m.ctrl.Call(_m, "WithSSHTunnel", _param0, _param1, _param2, _param3, _param4)
+func (_m *MockSSH) WithSSHTunnel(_param0 context.Context, _param1 string, _param2 []ssh.SSHAddress, _param3 []byte, _param4 time.Duration, _param5 func(string)) error {
+	ret := _m.ctrl.Call(_m, "WithSSHTunnel", _param0, _param1, _param2, _param3, _param4, _param5)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockSSHRecorder) WithSSHTunnel(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "WithSSHTunnel", arg0, arg1, arg2, arg3, arg4)
+func (_mr *_MockSSHRecorder) WithSSHTunnel(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "WithSSHTunnel", arg0, arg1, arg2, arg3, arg4, arg5)
 }
@@ -81,3 +81,19 @@ type TargetError struct {
 func (e *TargetError) Error() string {
 	return fmt.Sprintf("failed to target PCF Dev: %s", e.Err)
 }
+
+type BackupError struct {
+	Err error
+}
+
+func (e *BackupError) Error() string {
+	return fmt.Sprintf("failed to back up PCF Dev: %s", e.Err)
+}
+
+type RestoreError struct {
+	Err error
+}
+
+func (e *RestoreError) Error() string {
+	return fmt.Sprintf("failed to restore PCF Dev: %s", e.Err)
+}
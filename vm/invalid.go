@@ -1,12 +1,17 @@
 package vm
 
-import "errors"
+import (
+	"context"
+	"errors"
+
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
+)
 
 type Invalid struct {
 	Err error
 }
 
-func (i *Invalid) Stop() error {
+func (i *Invalid) Stop(ctx context.Context) error {
 	return i.err()
 }
 
@@ -14,11 +19,11 @@ func (i *Invalid) VerifyStartOpts(opts *StartOpts) error {
 	return i.err()
 }
 
-func (i *Invalid) Start(opts *StartOpts) error {
+func (i *Invalid) Start(ctx context.Context, opts *StartOpts) error {
 	return i.err()
 }
 
-func (i *Invalid) Provision(opts *StartOpts) error {
+func (i *Invalid) Provision(ctx context.Context, opts *StartOpts) error {
 	return i.err()
 }
 
@@ -26,19 +31,19 @@ func (i *Invalid) Status() string {
 	return i.message()
 }
 
-func (i *Invalid) Suspend() error {
+func (i *Invalid) Suspend(ctx context.Context) error {
 	return i.err()
 }
 
-func (i *Invalid) Resume() error {
+func (i *Invalid) Resume(ctx context.Context) error {
 	return i.err()
 }
 
-func (i *Invalid) GetDebugLogs() error {
+func (i *Invalid) GetDebugLogs(ctx context.Context) error {
 	return i.err()
 }
 
-func (i *Invalid) Trust(startOps *StartOpts) error {
+func (i *Invalid) Trust(ctx context.Context, startOps *StartOpts) error {
 	return i.err()
 }
 
@@ -46,7 +51,15 @@ func (i *Invalid) Target(autoTarget bool) error {
 	return i.err()
 }
 
-func (i *Invalid) SSH() error {
+func (i *Invalid) SSH(context.Context, *ssh.SessionOptions) error {
+	return i.err()
+}
+
+func (i *Invalid) Backup(ctx context.Context, path string) error {
+	return i.err()
+}
+
+func (i *Invalid) Restore(ctx context.Context, path string) error {
 	return i.err()
 }
 
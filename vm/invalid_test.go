@@ -1,8 +1,10 @@
 package vm_test
 
 import (
+	"context"
 	"errors"
 
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
 	"github.com/pivotal-cf/pcfdev-cli/vm"
 
 	. "github.com/onsi/ginkgo"
@@ -20,7 +22,7 @@ var _ = Describe("Invalid", func() {
 
 	Describe("Stop", func() {
 		It("should say a message", func() {
-			Expect(invalid.Stop()).To(MatchError("some-error.\nPCF Dev is in an invalid state. Please run 'cf dev destroy'"))
+			Expect(invalid.Stop(context.Background())).To(MatchError("some-error.\nPCF Dev is in an invalid state. Please run 'cf dev destroy'"))
 		})
 	})
 
@@ -34,7 +36,7 @@ var _ = Describe("Invalid", func() {
 
 	Describe("Start", func() {
 		It("should start vm", func() {
-			Expect(invalid.Start(&vm.StartOpts{})).To(MatchError("some-error.\nPCF Dev is in an invalid state. Please run 'cf dev destroy'"))
+			Expect(invalid.Start(context.Background(), &vm.StartOpts{})).To(MatchError("some-error.\nPCF Dev is in an invalid state. Please run 'cf dev destroy'"))
 		})
 	})
 
@@ -46,25 +48,25 @@ var _ = Describe("Invalid", func() {
 
 	Describe("Suspend", func() {
 		It("should say a message", func() {
-			Expect(invalid.Suspend()).To(MatchError("some-error.\nPCF Dev is in an invalid state. Please run 'cf dev destroy'"))
+			Expect(invalid.Suspend(context.Background())).To(MatchError("some-error.\nPCF Dev is in an invalid state. Please run 'cf dev destroy'"))
 		})
 	})
 
 	Describe("Resume", func() {
 		It("should say a message", func() {
-			Expect(invalid.Resume()).To(MatchError("some-error.\nPCF Dev is in an invalid state. Please run 'cf dev destroy'"))
+			Expect(invalid.Resume(context.Background())).To(MatchError("some-error.\nPCF Dev is in an invalid state. Please run 'cf dev destroy'"))
 		})
 	})
 
 	Describe("GetDebugLogs", func() {
 		It("should say a message", func() {
-			Expect(invalid.GetDebugLogs()).To(MatchError("some-error.\nPCF Dev is in an invalid state. Please run 'cf dev destroy'"))
+			Expect(invalid.GetDebugLogs(context.Background())).To(MatchError("some-error.\nPCF Dev is in an invalid state. Please run 'cf dev destroy'"))
 		})
 	})
 
 	Describe("Trust", func() {
 		It("should say a message", func() {
-			Expect(invalid.Trust(&vm.StartOpts{})).To(MatchError("some-error.\nPCF Dev is in an invalid state. Please run 'cf dev destroy'"))
+			Expect(invalid.Trust(context.Background(), &vm.StartOpts{})).To(MatchError("some-error.\nPCF Dev is in an invalid state. Please run 'cf dev destroy'"))
 		})
 	})
 
@@ -76,7 +78,19 @@ var _ = Describe("Invalid", func() {
 
 	Describe("SSH", func() {
 		It("should say a message", func() {
-			Expect(invalid.SSH()).To(MatchError("some-error.\nPCF Dev is in an invalid state. Please run 'cf dev destroy'"))
+			Expect(invalid.SSH(context.Background(), &ssh.SessionOptions{})).To(MatchError("some-error.\nPCF Dev is in an invalid state. Please run 'cf dev destroy'"))
+		})
+	})
+
+	Describe("Backup", func() {
+		It("should return an error", func() {
+			Expect(invalid.Backup(context.Background(), "some-archive-path")).To(MatchError("some-error.\nPCF Dev is in an invalid state. Please run 'cf dev destroy'"))
+		})
+	})
+
+	Describe("Restore", func() {
+		It("should return an error", func() {
+			Expect(invalid.Restore(context.Background(), "some-archive-path")).To(MatchError("some-error.\nPCF Dev is in an invalid state. Please run 'cf dev destroy'"))
 		})
 	})
 })
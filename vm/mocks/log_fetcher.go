@@ -4,6 +4,7 @@
 package mocks
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -28,12 +29,12 @@ func (_m *MockLogFetcher) EXPECT() *_MockLogFetcherRecorder {
 	return _m.recorder
 }
 
-func (_m *MockLogFetcher) FetchLogs() error {
-	ret := _m.ctrl.Call(_m, "FetchLogs")
+func (_m *MockLogFetcher) FetchLogs(_param0 context.Context) error {
+	ret := _m.ctrl.Call(_m, "FetchLogs", _param0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockLogFetcherRecorder) FetchLogs() *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "FetchLogs")
+func (_mr *_MockLogFetcherRecorder) FetchLogs(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FetchLogs", arg0)
 }
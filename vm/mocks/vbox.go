@@ -4,8 +4,10 @@
 package mocks
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 	config "github.com/pivotal-cf/pcfdev-cli/config"
+	debug "github.com/pivotal-cf/pcfdev-cli/debug"
 )
 
 // Mock of VBox interface
@@ -29,14 +31,35 @@ func (_m *MockVBox) EXPECT() *_MockVBoxRecorder {
 	return _m.recorder
 }
 
-func (_m *MockVBox) ImportVM(_param0 *config.VMConfig) error {
-	ret := _m.ctrl.Call(_m, "ImportVM", _param0)
+func (_m *MockVBox) DestroyVM(_param0 context.Context, _param1 *config.VMConfig) error {
+	ret := _m.ctrl.Call(_m, "DestroyVM", _param0, _param1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockVBoxRecorder) ImportVM(arg0 interface{}) *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "ImportVM", arg0)
+func (_mr *_MockVBoxRecorder) DestroyVM(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "DestroyVM", arg0, arg1)
+}
+
+func (_m *MockVBox) HostLogs(_param0 *config.VMConfig) ([]*debug.HostLog, error) {
+	ret := _m.ctrl.Call(_m, "HostLogs", _param0)
+	ret0, _ := ret[0].([]*debug.HostLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVBoxRecorder) HostLogs(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "HostLogs", arg0)
+}
+
+func (_m *MockVBox) ImportVM(_param0 context.Context, _param1 *config.VMConfig) error {
+	ret := _m.ctrl.Call(_m, "ImportVM", _param0, _param1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockVBoxRecorder) ImportVM(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ImportVM", arg0, arg1)
 }
 
 func (_m *MockVBox) PowerOffVM(_param0 *config.VMConfig) error {
@@ -69,34 +92,34 @@ func (_mr *_MockVBoxRecorder) ResumeSavedVM(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "ResumeSavedVM", arg0)
 }
 
-func (_m *MockVBox) StartVM(_param0 *config.VMConfig) error {
-	ret := _m.ctrl.Call(_m, "StartVM", _param0)
+func (_m *MockVBox) StartVM(_param0 context.Context, _param1 *config.VMConfig) error {
+	ret := _m.ctrl.Call(_m, "StartVM", _param0, _param1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockVBoxRecorder) StartVM(arg0 interface{}) *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "StartVM", arg0)
+func (_mr *_MockVBoxRecorder) StartVM(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "StartVM", arg0, arg1)
 }
 
-func (_m *MockVBox) StopVM(_param0 *config.VMConfig) error {
-	ret := _m.ctrl.Call(_m, "StopVM", _param0)
+func (_m *MockVBox) StopVM(_param0 context.Context, _param1 *config.VMConfig) error {
+	ret := _m.ctrl.Call(_m, "StopVM", _param0, _param1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockVBoxRecorder) StopVM(arg0 interface{}) *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "StopVM", arg0)
+func (_mr *_MockVBoxRecorder) StopVM(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "StopVM", arg0, arg1)
 }
 
-func (_m *MockVBox) SuspendVM(_param0 *config.VMConfig) error {
-	ret := _m.ctrl.Call(_m, "SuspendVM", _param0)
+func (_m *MockVBox) SuspendVM(_param0 context.Context, _param1 *config.VMConfig) error {
+	ret := _m.ctrl.Call(_m, "SuspendVM", _param0, _param1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockVBoxRecorder) SuspendVM(arg0 interface{}) *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "SuspendVM", arg0)
+func (_mr *_MockVBoxRecorder) SuspendVM(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "SuspendVM", arg0, arg1)
 }
 
 func (_m *MockVBox) VMConfig(_param0 string) (*config.VMConfig, error) {
@@ -4,7 +4,9 @@
 package mocks
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
+	ssh "github.com/pivotal-cf/pcfdev-cli/ssh"
 	vm "github.com/pivotal-cf/pcfdev-cli/vm"
 )
 
@@ -29,54 +31,74 @@ func (_m *MockVM) EXPECT() *_MockVMRecorder {
 	return _m.recorder
 }
 
-func (_m *MockVM) GetDebugLogs() error {
-	ret := _m.ctrl.Call(_m, "GetDebugLogs")
+func (_m *MockVM) Backup(_param0 context.Context, _param1 string) error {
+	ret := _m.ctrl.Call(_m, "Backup", _param0, _param1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockVMRecorder) GetDebugLogs() *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetDebugLogs")
+func (_mr *_MockVMRecorder) Backup(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Backup", arg0, arg1)
 }
 
-func (_m *MockVM) Provision(_param0 *vm.StartOpts) error {
-	ret := _m.ctrl.Call(_m, "Provision", _param0)
+func (_m *MockVM) GetDebugLogs(_param0 context.Context) error {
+	ret := _m.ctrl.Call(_m, "GetDebugLogs", _param0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockVMRecorder) Provision(arg0 interface{}) *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "Provision", arg0)
+func (_mr *_MockVMRecorder) GetDebugLogs(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetDebugLogs", arg0)
 }
 
-func (_m *MockVM) Resume() error {
-	ret := _m.ctrl.Call(_m, "Resume")
+func (_m *MockVM) Provision(_param0 context.Context, _param1 *vm.StartOpts) error {
+	ret := _m.ctrl.Call(_m, "Provision", _param0, _param1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockVMRecorder) Resume() *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "Resume")
+func (_mr *_MockVMRecorder) Provision(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Provision", arg0, arg1)
 }
 
-func (_m *MockVM) SSH() error {
-	ret := _m.ctrl.Call(_m, "SSH")
+func (_m *MockVM) Restore(_param0 context.Context, _param1 string) error {
+	ret := _m.ctrl.Call(_m, "Restore", _param0, _param1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockVMRecorder) SSH() *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "SSH")
+func (_mr *_MockVMRecorder) Restore(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Restore", arg0, arg1)
 }
 
-func (_m *MockVM) Start(_param0 *vm.StartOpts) error {
-	ret := _m.ctrl.Call(_m, "Start", _param0)
+func (_m *MockVM) Resume(_param0 context.Context) error {
+	ret := _m.ctrl.Call(_m, "Resume", _param0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockVMRecorder) Start(arg0 interface{}) *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "Start", arg0)
+func (_mr *_MockVMRecorder) Resume(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Resume", arg0)
+}
+
+func (_m *MockVM) SSH(_param0 context.Context, _param1 *ssh.SessionOptions) error {
+	ret := _m.ctrl.Call(_m, "SSH", _param0, _param1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockVMRecorder) SSH(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "SSH", arg0, arg1)
+}
+
+func (_m *MockVM) Start(_param0 context.Context, _param1 *vm.StartOpts) error {
+	ret := _m.ctrl.Call(_m, "Start", _param0, _param1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockVMRecorder) Start(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Start", arg0, arg1)
 }
 
 func (_m *MockVM) Status() string {
@@ -89,24 +111,24 @@ func (_mr *_MockVMRecorder) Status() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Status")
 }
 
-func (_m *MockVM) Stop() error {
-	ret := _m.ctrl.Call(_m, "Stop")
+func (_m *MockVM) Stop(_param0 context.Context) error {
+	ret := _m.ctrl.Call(_m, "Stop", _param0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockVMRecorder) Stop() *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "Stop")
+func (_mr *_MockVMRecorder) Stop(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Stop", arg0)
 }
 
-func (_m *MockVM) Suspend() error {
-	ret := _m.ctrl.Call(_m, "Suspend")
+func (_m *MockVM) Suspend(_param0 context.Context) error {
+	ret := _m.ctrl.Call(_m, "Suspend", _param0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockVMRecorder) Suspend() *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "Suspend")
+func (_mr *_MockVMRecorder) Suspend(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Suspend", arg0)
 }
 
 func (_m *MockVM) Target(_param0 bool) error {
@@ -119,14 +141,14 @@ func (_mr *_MockVMRecorder) Target(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Target", arg0)
 }
 
-func (_m *MockVM) Trust(_param0 *vm.StartOpts) error {
-	ret := _m.ctrl.Call(_m, "Trust", _param0)
+func (_m *MockVM) Trust(_param0 context.Context, _param1 *vm.StartOpts) error {
+	ret := _m.ctrl.Call(_m, "Trust", _param0, _param1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockVMRecorder) Trust(arg0 interface{}) *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "Trust", arg0)
+func (_mr *_MockVMRecorder) Trust(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Trust", arg0, arg1)
 }
 
 func (_m *MockVM) VerifyStartOpts(_param0 *vm.StartOpts) error {
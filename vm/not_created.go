@@ -1,6 +1,7 @@
 package vm
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"path/filepath"
@@ -8,6 +9,8 @@ import (
 
 	"github.com/pivotal-cf/pcfdev-cli/address"
 	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/helpers"
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
 )
 
 type NotCreated struct {
@@ -20,7 +23,7 @@ type NotCreated struct {
 	Network  Network
 }
 
-func (n *NotCreated) Stop() error {
+func (n *NotCreated) Stop(ctx context.Context) error {
 	n.UI.Say("PCF Dev VM has not been created.")
 	return nil
 }
@@ -67,6 +70,16 @@ func (n *NotCreated) VerifyStartOpts(opts *StartOpts) error {
 		}
 	}
 
+	switch opts.NetworkMode {
+	case "", config.NetworkModeHostOnly, config.NetworkModeNATNetwork:
+	case config.NetworkModeBridged:
+		if opts.IP != "" || opts.Domain != "" {
+			return errors.New("the -i and -d flags cannot be used in bridged network mode, as the VM is given an address by the LAN")
+		}
+	default:
+		return fmt.Errorf("invalid network mode specified: %s", opts.NetworkMode)
+	}
+
 	if opts.IP == "" && opts.Domain != "" && !address.IsDomainAllowed(opts.Domain) {
 		return errors.New(fmt.Sprintf("%s is not one of the allowed PCF Dev domains", opts.Domain))
 	}
@@ -119,11 +132,11 @@ func (n *NotCreated) hasSCS(services string) bool {
 	return strings.Contains(services, "scs") || strings.Contains(services, "spring-cloud-services") || strings.Contains(services, "all")
 }
 
-func (n *NotCreated) Provision(opts *StartOpts) error {
+func (n *NotCreated) Provision(ctx context.Context, opts *StartOpts) error {
 	return nil
 }
 
-func (n *NotCreated) Start(opts *StartOpts) error {
+func (n *NotCreated) Start(ctx context.Context, opts *StartOpts) error {
 	var memory uint64
 	if opts.Memory != uint64(0) {
 		memory = opts.Memory
@@ -153,23 +166,27 @@ func (n *NotCreated) Start(opts *StartOpts) error {
 
 	n.UI.Say(fmt.Sprintf("Allocating %d MB out of %d MB total system memory (%d MB free).", memory, n.Config.TotalMemory, n.Config.FreeMemory))
 	n.UI.Say("Importing VM...")
-	if err := n.VBox.ImportVM(&config.VMConfig{
+	if err := n.VBox.ImportVM(ctx, &config.VMConfig{
 		Name:    n.VMConfig.Name,
 		Memory:  memory,
 		CPUs:    cpus,
 		OVAPath: ovaPath,
 		IP:      opts.IP,
 
-		Domain: opts.Domain,
+		Domain:      opts.Domain,
+		NetworkMode: opts.NetworkMode,
 	}); err != nil {
+		// The half-imported VM is removed even when ctx is done, so that an
+		// interrupted start leaves no VM behind.
+		helpers.IgnoreErrorFrom(n.VBox.DestroyVM(context.Background(), &config.VMConfig{Name: n.VMConfig.Name}))
 		return &ImportVMError{err}
 	}
 
-	stoppedVM, err := n.Builder.VM(n.VMConfig.Name)
+	stoppedVM, err := n.Builder.VM(ctx, n.VMConfig.Name)
 	if err != nil {
 		return &StartVMError{err}
 	}
-	if err := stoppedVM.Start(opts); err != nil {
+	if err := stoppedVM.Start(ctx, opts); err != nil {
 		return err
 	}
 	return nil
@@ -179,22 +196,22 @@ func (n *NotCreated) Status() string {
 	return "Not Created"
 }
 
-func (n *NotCreated) Suspend() error {
+func (n *NotCreated) Suspend(ctx context.Context) error {
 	n.UI.Say("No VM running, cannot suspend.")
 	return nil
 }
 
-func (n *NotCreated) Resume() error {
+func (n *NotCreated) Resume(ctx context.Context) error {
 	n.UI.Say("No VM suspended, cannot resume.")
 	return nil
 }
 
-func (n *NotCreated) GetDebugLogs() error {
+func (n *NotCreated) GetDebugLogs(ctx context.Context) error {
 	n.UI.Say("No VM created, cannot retrieve debug logs.")
 	return nil
 }
 
-func (n *NotCreated) Trust(startOps *StartOpts) error {
+func (n *NotCreated) Trust(ctx context.Context, startOps *StartOpts) error {
 	n.UI.Say("No VM created, cannot trust VM certificates.")
 	return nil
 }
@@ -204,7 +221,17 @@ func (n *NotCreated) Target(autoTarget bool) error {
 	return nil
 }
 
-func (n *NotCreated) SSH() error {
+func (n *NotCreated) SSH(context.Context, *ssh.SessionOptions) error {
 	n.UI.Say("No VM created, cannot SSH to PCF Dev.")
 	return nil
 }
+
+func (n *NotCreated) Backup(ctx context.Context, path string) error {
+	n.UI.Say("No VM created, cannot back up PCF Dev.")
+	return nil
+}
+
+func (n *NotCreated) Restore(ctx context.Context, path string) error {
+	n.UI.Say("No VM created, cannot restore PCF Dev.")
+	return nil
+}
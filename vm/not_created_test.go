@@ -1,11 +1,13 @@
 package vm_test
 
 import (
+	"context"
 	"errors"
 	"path/filepath"
 
 	"github.com/golang/mock/gomock"
 	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/ssh"
 	"github.com/pivotal-cf/pcfdev-cli/user"
 	"github.com/pivotal-cf/pcfdev-cli/vm"
 	"github.com/pivotal-cf/pcfdev-cli/vm/mocks"
@@ -61,11 +63,34 @@ var _ = Describe("Not Created", func() {
 		It("should print a message", func() {
 			mockUI.EXPECT().Say("PCF Dev VM has not been created.")
 
-			notCreatedVM.Stop()
+			notCreatedVM.Stop(context.Background())
 		})
 	})
 
 	Describe("VerifyStartOpts", func() {
+		Context("when a network mode is passed as an option", func() {
+			Context("when the network mode is not one of the supported modes", func() {
+				It("should return an error", func() {
+					Expect(notCreatedVM.VerifyStartOpts(&vm.StartOpts{
+						NetworkMode: "some-network-mode",
+					})).To(MatchError("invalid network mode specified: some-network-mode"))
+				})
+			})
+
+			Context("when the network mode is bridged and an IP or domain is passed", func() {
+				It("should return an error", func() {
+					Expect(notCreatedVM.VerifyStartOpts(&vm.StartOpts{
+						NetworkMode: "bridged",
+						IP:          "192.168.22.11",
+					})).To(MatchError("the -i and -d flags cannot be used in bridged network mode, as the VM is given an address by the LAN"))
+					Expect(notCreatedVM.VerifyStartOpts(&vm.StartOpts{
+						NetworkMode: "bridged",
+						Domain:      "local2.pcfdev.io",
+					})).To(MatchError("the -i and -d flags cannot be used in bridged network mode, as the VM is given an address by the LAN"))
+				})
+			})
+		})
+
 		Context("when memory is passed as an option", func() {
 			Context("when the desired memory is less than the minimum", func() {
 				It("should print an error", func() {
@@ -410,25 +435,27 @@ var _ = Describe("Not Created", func() {
 					IP:             "some-ip",
 					Domain:         "some-domain",
 					MasterPassword: "some-master-password",
+					NetworkMode:    "natnetwork",
 				}
 				gomock.InOrder(
 					mockUI.EXPECT().Say("Allocating 4000 MB out of 8000 MB total system memory (5000 MB free)."),
 					mockUI.EXPECT().Say("Importing VM..."),
-					mockVBox.EXPECT().ImportVM(&config.VMConfig{
-						Name:    "some-vm",
-						Memory:  uint64(4000),
-						CPUs:    3,
-						OVAPath: "some-ova-path",
-						IP:      "some-ip",
-						Domain:  "some-domain",
+					mockVBox.EXPECT().ImportVM(gomock.Any(), &config.VMConfig{
+						Name:        "some-vm",
+						Memory:      uint64(4000),
+						CPUs:        3,
+						OVAPath:     "some-ova-path",
+						IP:          "some-ip",
+						Domain:      "some-domain",
+						NetworkMode: "natnetwork",
 					}),
-					mockBuilder.EXPECT().VM("some-vm").Return(mockStopped, nil),
-					mockStopped.EXPECT().Start(startOpts),
+					mockBuilder.EXPECT().VM(gomock.Any(), "some-vm").Return(mockStopped, nil),
+					mockStopped.EXPECT().Start(gomock.Any(), startOpts),
 				)
 				conf.FreeMemory = uint64(5000)
 				conf.TotalMemory = uint64(8000)
 
-				notCreatedVM.Start(startOpts)
+				notCreatedVM.Start(context.Background(), startOpts)
 			})
 		})
 
@@ -442,20 +469,20 @@ var _ = Describe("Not Created", func() {
 				gomock.InOrder(
 					mockUI.EXPECT().Say("Allocating 6000 MB out of 8000 MB total system memory (7000 MB free)."),
 					mockUI.EXPECT().Say("Importing VM..."),
-					mockVBox.EXPECT().ImportVM(&config.VMConfig{
+					mockVBox.EXPECT().ImportVM(gomock.Any(), &config.VMConfig{
 						Name:    "some-vm",
 						Memory:  uint64(6000),
 						CPUs:    3,
 						OVAPath: "some-ova-path",
 					}),
-					mockBuilder.EXPECT().VM("some-vm").Return(mockStopped, nil),
-					mockStopped.EXPECT().Start(startOpts),
+					mockBuilder.EXPECT().VM(gomock.Any(), "some-vm").Return(mockStopped, nil),
+					mockStopped.EXPECT().Start(gomock.Any(), startOpts),
 				)
 				conf.SpringCloudDefaultMemory = uint64(6000)
 				conf.FreeMemory = uint64(7000)
 				conf.TotalMemory = uint64(8000)
 
-				notCreatedVM.Start(startOpts)
+				notCreatedVM.Start(context.Background(), startOpts)
 			})
 		})
 
@@ -469,20 +496,20 @@ var _ = Describe("Not Created", func() {
 				gomock.InOrder(
 					mockUI.EXPECT().Say("Allocating 6000 MB out of 8000 MB total system memory (7000 MB free)."),
 					mockUI.EXPECT().Say("Importing VM..."),
-					mockVBox.EXPECT().ImportVM(&config.VMConfig{
+					mockVBox.EXPECT().ImportVM(gomock.Any(), &config.VMConfig{
 						Name:    "some-vm",
 						Memory:  uint64(6000),
 						CPUs:    3,
 						OVAPath: "some-ova-path",
 					}),
-					mockBuilder.EXPECT().VM("some-vm").Return(mockStopped, nil),
-					mockStopped.EXPECT().Start(startOpts),
+					mockBuilder.EXPECT().VM(gomock.Any(), "some-vm").Return(mockStopped, nil),
+					mockStopped.EXPECT().Start(gomock.Any(), startOpts),
 				)
 				conf.SpringCloudDefaultMemory = uint64(6000)
 				conf.FreeMemory = uint64(7000)
 				conf.TotalMemory = uint64(8000)
 
-				notCreatedVM.Start(startOpts)
+				notCreatedVM.Start(context.Background(), startOpts)
 			})
 		})
 
@@ -496,20 +523,20 @@ var _ = Describe("Not Created", func() {
 				gomock.InOrder(
 					mockUI.EXPECT().Say("Allocating 6000 MB out of 8000 MB total system memory (7000 MB free)."),
 					mockUI.EXPECT().Say("Importing VM..."),
-					mockVBox.EXPECT().ImportVM(&config.VMConfig{
+					mockVBox.EXPECT().ImportVM(gomock.Any(), &config.VMConfig{
 						Name:    "some-vm",
 						Memory:  uint64(6000),
 						CPUs:    3,
 						OVAPath: "some-ova-path",
 					}),
-					mockBuilder.EXPECT().VM("some-vm").Return(mockStopped, nil),
-					mockStopped.EXPECT().Start(startOpts),
+					mockBuilder.EXPECT().VM(gomock.Any(), "some-vm").Return(mockStopped, nil),
+					mockStopped.EXPECT().Start(gomock.Any(), startOpts),
 				)
 				conf.SpringCloudDefaultMemory = uint64(6000)
 				conf.FreeMemory = uint64(7000)
 				conf.TotalMemory = uint64(8000)
 
-				notCreatedVM.Start(startOpts)
+				notCreatedVM.Start(context.Background(), startOpts)
 			})
 		})
 
@@ -518,14 +545,14 @@ var _ = Describe("Not Created", func() {
 				gomock.InOrder(
 					mockUI.EXPECT().Say("Allocating 3500 MB out of 8000 MB total system memory (5000 MB free)."),
 					mockUI.EXPECT().Say("Importing VM..."),
-					mockVBox.EXPECT().ImportVM(&config.VMConfig{
+					mockVBox.EXPECT().ImportVM(gomock.Any(), &config.VMConfig{
 						Name:    "some-vm",
 						Memory:  uint64(3500),
 						CPUs:    7,
 						OVAPath: filepath.Join("some-ova-dir", "some-vm.ova"),
 					}),
-					mockBuilder.EXPECT().VM("some-vm").Return(mockStopped, nil),
-					mockStopped.EXPECT().Start(&vm.StartOpts{}),
+					mockBuilder.EXPECT().VM(gomock.Any(), "some-vm").Return(mockStopped, nil),
+					mockStopped.EXPECT().Start(gomock.Any(), &vm.StartOpts{}),
 				)
 				conf.OVADir = "some-ova-dir"
 				conf.DefaultCPUs = func() (int, error) { return 7, nil }
@@ -533,13 +560,13 @@ var _ = Describe("Not Created", func() {
 				conf.FreeMemory = uint64(5000)
 				conf.TotalMemory = uint64(8000)
 
-				Expect(notCreatedVM.Start(&vm.StartOpts{})).To(Succeed())
+				Expect(notCreatedVM.Start(context.Background(), &vm.StartOpts{})).To(Succeed())
 			})
 
 			Context("and checking number of CPUs returns an error", func() {
 				It("should return an error", func() {
 					conf.DefaultCPUs = func() (int, error) { return 0, errors.New("some-error") }
-					Expect(notCreatedVM.Start(&vm.StartOpts{})).To(MatchError("some-error"))
+					Expect(notCreatedVM.Start(context.Background(), &vm.StartOpts{})).To(MatchError("some-error"))
 				})
 			})
 		})
@@ -549,18 +576,39 @@ var _ = Describe("Not Created", func() {
 				gomock.InOrder(
 					mockUI.EXPECT().Say("Allocating 3072 MB out of 0 MB total system memory (0 MB free)."),
 					mockUI.EXPECT().Say("Importing VM..."),
-					mockVBox.EXPECT().ImportVM(&config.VMConfig{
+					mockVBox.EXPECT().ImportVM(gomock.Any(), &config.VMConfig{
 						Name:    "some-vm",
 						Memory:  uint64(3072),
 						OVAPath: filepath.Join("some-ova-dir", "some-vm.ova"),
 					}).Return(errors.New("some-error")),
+					mockVBox.EXPECT().DestroyVM(gomock.Any(), &config.VMConfig{Name: "some-vm"}),
 				)
 				conf.OVADir = "some-ova-dir"
 
-				Expect(notCreatedVM.Start(&vm.StartOpts{
+				Expect(notCreatedVM.Start(context.Background(), &vm.StartOpts{
 					Memory: uint64(3072),
 				})).To(MatchError("failed to import VM: some-error"))
 			})
+
+			Context("because the start was interrupted", func() {
+				It("should destroy the half-imported VM with a fresh context", func() {
+					ctx, cancel := context.WithCancel(context.Background())
+					gomock.InOrder(
+						mockUI.EXPECT().Say("Allocating 3072 MB out of 0 MB total system memory (0 MB free)."),
+						mockUI.EXPECT().Say("Importing VM..."),
+						mockVBox.EXPECT().ImportVM(ctx, gomock.Any()).Do(func(context.Context, *config.VMConfig) {
+							cancel()
+						}).Return(context.Canceled),
+						mockVBox.EXPECT().DestroyVM(gomock.Any(), &config.VMConfig{Name: "some-vm"}).Do(func(ctx context.Context, _ *config.VMConfig) {
+							Expect(ctx.Err()).NotTo(HaveOccurred())
+						}),
+					)
+
+					Expect(notCreatedVM.Start(ctx, &vm.StartOpts{
+						Memory: uint64(3072),
+					})).To(MatchError("failed to import VM: context canceled"))
+				})
+			})
 		})
 
 		Context("when there is an error constructing a stopped VM", func() {
@@ -568,16 +616,16 @@ var _ = Describe("Not Created", func() {
 				gomock.InOrder(
 					mockUI.EXPECT().Say("Allocating 3072 MB out of 0 MB total system memory (0 MB free)."),
 					mockUI.EXPECT().Say("Importing VM..."),
-					mockVBox.EXPECT().ImportVM(&config.VMConfig{
+					mockVBox.EXPECT().ImportVM(gomock.Any(), &config.VMConfig{
 						Name:    "some-vm",
 						Memory:  uint64(3072),
 						OVAPath: filepath.Join("some-ova-dir", "some-vm.ova"),
 					}),
-					mockBuilder.EXPECT().VM("some-vm").Return(nil, errors.New("some-error")),
+					mockBuilder.EXPECT().VM(gomock.Any(), "some-vm").Return(nil, errors.New("some-error")),
 				)
 				conf.OVADir = "some-ova-dir"
 
-				Expect(notCreatedVM.Start(&vm.StartOpts{
+				Expect(notCreatedVM.Start(context.Background(), &vm.StartOpts{
 					Memory: uint64(3072),
 				})).To(MatchError("failed to start VM: some-error"))
 			})
@@ -591,17 +639,17 @@ var _ = Describe("Not Created", func() {
 				gomock.InOrder(
 					mockUI.EXPECT().Say("Allocating 3072 MB out of 0 MB total system memory (0 MB free)."),
 					mockUI.EXPECT().Say("Importing VM..."),
-					mockVBox.EXPECT().ImportVM(&config.VMConfig{
+					mockVBox.EXPECT().ImportVM(gomock.Any(), &config.VMConfig{
 						Name:    "some-vm",
 						Memory:  uint64(3072),
 						OVAPath: filepath.Join("some-ova-dir", "some-vm.ova"),
 					}),
-					mockBuilder.EXPECT().VM("some-vm").Return(mockStopped, nil),
-					mockStopped.EXPECT().Start(startOpts).Return(errors.New("failed to start VM: some-error")),
+					mockBuilder.EXPECT().VM(gomock.Any(), "some-vm").Return(mockStopped, nil),
+					mockStopped.EXPECT().Start(gomock.Any(), startOpts).Return(errors.New("failed to start VM: some-error")),
 				)
 				conf.OVADir = "some-ova-dir"
 
-				Expect(notCreatedVM.Start(startOpts)).To(MatchError("failed to start VM: some-error"))
+				Expect(notCreatedVM.Start(context.Background(), startOpts)).To(MatchError("failed to start VM: some-error"))
 			})
 		})
 	})
@@ -616,7 +664,7 @@ var _ = Describe("Not Created", func() {
 		It("should say message", func() {
 			mockUI.EXPECT().Say("No VM running, cannot suspend.")
 
-			Expect(notCreatedVM.Suspend()).To(Succeed())
+			Expect(notCreatedVM.Suspend(context.Background())).To(Succeed())
 		})
 	})
 
@@ -624,7 +672,7 @@ var _ = Describe("Not Created", func() {
 		It("should say message", func() {
 			mockUI.EXPECT().Say("No VM suspended, cannot resume.")
 
-			Expect(notCreatedVM.Resume()).To(Succeed())
+			Expect(notCreatedVM.Resume(context.Background())).To(Succeed())
 		})
 	})
 
@@ -632,7 +680,7 @@ var _ = Describe("Not Created", func() {
 		It("should say message", func() {
 			mockUI.EXPECT().Say("No VM created, cannot retrieve debug logs.")
 
-			Expect(notCreatedVM.GetDebugLogs()).To(Succeed())
+			Expect(notCreatedVM.GetDebugLogs(context.Background())).To(Succeed())
 		})
 	})
 
@@ -640,7 +688,7 @@ var _ = Describe("Not Created", func() {
 		It("should say message", func() {
 			mockUI.EXPECT().Say("No VM created, cannot trust VM certificates.")
 
-			Expect(notCreatedVM.Trust(&vm.StartOpts{})).To(Succeed())
+			Expect(notCreatedVM.Trust(context.Background(), &vm.StartOpts{})).To(Succeed())
 		})
 	})
 
@@ -656,7 +704,21 @@ var _ = Describe("Not Created", func() {
 		It("should say message", func() {
 			mockUI.EXPECT().Say("No VM created, cannot SSH to PCF Dev.")
 
-			Expect(notCreatedVM.SSH()).To(Succeed())
+			Expect(notCreatedVM.SSH(context.Background(), &ssh.SessionOptions{})).To(Succeed())
+		})
+	})
+
+	Describe("Backup", func() {
+		It("should say a message", func() {
+			mockUI.EXPECT().Say("No VM created, cannot back up PCF Dev.")
+			Expect(notCreatedVM.Backup(context.Background(), "some-archive-path")).To(Succeed())
+		})
+	})
+
+	Describe("Restore", func() {
+		It("should say a message", func() {
+			mockUI.EXPECT().Say("No VM created, cannot restore PCF Dev.")
+			Expect(notCreatedVM.Restore(context.Background(), "some-archive-path")).To(Succeed())
 		})
 	})
 })
@@ -1,8 +1,8 @@
 package vm
 
 import (
+	"context"
 	"errors"
-	"time"
 
 	"github.com/pivotal-cf/pcfdev-cli/config"
 	"github.com/pivotal-cf/pcfdev-cli/ssh"
@@ -18,7 +18,7 @@ type Paused struct {
 	FS        FS
 }
 
-func (p *Paused) Stop() error {
+func (p *Paused) Stop(ctx context.Context) error {
 	p.UI.Say("Your VM is currently suspended. You must resume your VM with `cf dev resume` to shut it down.")
 	return nil
 }
@@ -39,14 +39,17 @@ func (p *Paused) VerifyStartOpts(opts *StartOpts) error {
 	if opts.IP != "" {
 		return errors.New("the -i flag cannot be used if the VM has already been created")
 	}
+	if opts.NetworkMode != "" {
+		return errors.New("the --network-mode flag cannot be used if the VM has already been created")
+	}
 	return nil
 }
 
-func (p *Paused) Start(opts *StartOpts) error {
-	return p.Resume()
+func (p *Paused) Start(ctx context.Context, opts *StartOpts) error {
+	return p.Resume(ctx)
 }
 
-func (p *Paused) Provision(opts *StartOpts) error {
+func (p *Paused) Provision(ctx context.Context, opts *StartOpts) error {
 	return nil
 }
 
@@ -54,12 +57,12 @@ func (p *Paused) Status() string {
 	return "Suspended - system memory for the VM is still allocated. Resume and suspend to suspend pcfdev VM to the disk."
 }
 
-func (p *Paused) Suspend() error {
+func (p *Paused) Suspend(ctx context.Context) error {
 	p.UI.Say("Your VM is suspended and system memory for the VM is still allocated. Resume and suspend to suspend pcfdev VM to the disk.")
 	return nil
 }
 
-func (p *Paused) Resume() error {
+func (p *Paused) Resume(ctx context.Context) error {
 	p.UI.Say("Resuming VM...")
 	if err := p.VBox.ResumePausedVM(p.VMConfig); err != nil {
 		return &ResumeVMError{err}
@@ -70,11 +73,8 @@ func (p *Paused) Resume() error {
 		return &ResumeVMError{err}
 	}
 
-	addresses := []ssh.SSHAddress{
-		{IP: "127.0.0.1", Port: p.VMConfig.SSHPort},
-		{IP: p.VMConfig.IP, Port: "22"},
-	}
-	if err := p.SSHClient.WaitForSSH(addresses, privateKeyBytes, 5*time.Minute); err != nil {
+	addresses := ssh.SSHAddresses(p.VMConfig)
+	if err := p.SSHClient.WaitForSSH(ctx, addresses, privateKeyBytes, p.Config.SSHTimeout); err != nil {
 		return &ResumeVMError{err}
 	}
 
@@ -83,12 +83,12 @@ func (p *Paused) Resume() error {
 	return nil
 }
 
-func (p *Paused) GetDebugLogs() error {
+func (p *Paused) GetDebugLogs(ctx context.Context) error {
 	p.UI.Say("Your VM is suspended. Resume to retrieve debug logs.")
 	return nil
 }
 
-func (p *Paused) Trust(startOps *StartOpts) error {
+func (p *Paused) Trust(ctx context.Context, startOps *StartOpts) error {
 	p.UI.Say("Your VM is suspended. Resume to trust VM certificates.")
 	return nil
 }
@@ -98,7 +98,17 @@ func (p *Paused) Target(autoTarget bool) error {
 	return nil
 }
 
-func (p *Paused) SSH() error {
+func (p *Paused) SSH(context.Context, *ssh.SessionOptions) error {
 	p.UI.Say("Your VM is suspended. Resume to SSH to PCF Dev.")
 	return nil
 }
+
+func (p *Paused) Backup(ctx context.Context, path string) error {
+	p.UI.Say("Your VM is suspended. Resume to back up PCF Dev.")
+	return nil
+}
+
+func (p *Paused) Restore(ctx context.Context, path string) error {
+	p.UI.Say("Your VM is suspended. Resume to restore PCF Dev.")
+	return nil
+}
@@ -1,6 +1,7 @@
 package vm_test
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -44,6 +45,7 @@ var _ = Describe("Paused", func() {
 			FS:        mockFS,
 
 			Config: &config.Config{
+				SSHTimeout:     7 * time.Minute,
 				PrivateKeyPath: "some-private-key-path",
 			},
 		}
@@ -56,14 +58,14 @@ var _ = Describe("Paused", func() {
 	Describe("Suspend", func() {
 		It("should say a message", func() {
 			mockUI.EXPECT().Say("Your VM is suspended and system memory for the VM is still allocated. Resume and suspend to suspend pcfdev VM to the disk.")
-			Expect(pausedVM.Suspend()).To(Succeed())
+			Expect(pausedVM.Suspend(context.Background())).To(Succeed())
 		})
 	})
 
 	Describe("Stop", func() {
 		It("should say a message", func() {
 			mockUI.EXPECT().Say("Your VM is currently suspended. You must resume your VM with `cf dev resume` to shut it down.")
-			Expect(pausedVM.Stop()).To(Succeed())
+			Expect(pausedVM.Stop(context.Background())).To(Succeed())
 		})
 	})
 
@@ -77,11 +79,11 @@ var _ = Describe("Paused", func() {
 				mockUI.EXPECT().Say("Resuming VM..."),
 				mockVBox.EXPECT().ResumePausedVM(pausedVM.VMConfig),
 				mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-				mockSSH.EXPECT().WaitForSSH(addresses, []byte("some-private-key"), 5*time.Minute),
+				mockSSH.EXPECT().WaitForSSH(gomock.Any(), addresses, []byte("some-private-key"), 7*time.Minute),
 				mockUI.EXPECT().Say("PCF Dev is now running."),
 			)
 
-			Expect(pausedVM.Start(&vm.StartOpts{})).To(Succeed())
+			Expect(pausedVM.Start(context.Background(), &vm.StartOpts{})).To(Succeed())
 		})
 
 		Context("when starting the vm fails", func() {
@@ -91,7 +93,7 @@ var _ = Describe("Paused", func() {
 					mockVBox.EXPECT().ResumePausedVM(pausedVM.VMConfig).Return(errors.New("some-error")),
 				)
 
-				Expect(pausedVM.Start(&vm.StartOpts{})).To(MatchError("failed to resume VM: some-error"))
+				Expect(pausedVM.Start(context.Background(), &vm.StartOpts{})).To(MatchError("failed to resume VM: some-error"))
 			})
 		})
 
@@ -103,7 +105,7 @@ var _ = Describe("Paused", func() {
 					mockFS.EXPECT().Read("some-private-key-path").Return(nil, errors.New("some-error")),
 				)
 
-				Expect(pausedVM.Start(&vm.StartOpts{})).To(MatchError("failed to resume VM: some-error"))
+				Expect(pausedVM.Start(context.Background(), &vm.StartOpts{})).To(MatchError("failed to resume VM: some-error"))
 			})
 		})
 
@@ -117,10 +119,10 @@ var _ = Describe("Paused", func() {
 					mockUI.EXPECT().Say("Resuming VM..."),
 					mockVBox.EXPECT().ResumePausedVM(pausedVM.VMConfig),
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-					mockSSH.EXPECT().WaitForSSH(addresses, []byte("some-private-key"), 5*time.Minute).Return(errors.New("some-error")),
+					mockSSH.EXPECT().WaitForSSH(gomock.Any(), addresses, []byte("some-private-key"), 7*time.Minute).Return(errors.New("some-error")),
 				)
 
-				Expect(pausedVM.Start(&vm.StartOpts{})).To(MatchError("failed to resume VM: some-error"))
+				Expect(pausedVM.Start(context.Background(), &vm.StartOpts{})).To(MatchError("failed to resume VM: some-error"))
 			})
 		})
 	})
@@ -164,6 +166,14 @@ var _ = Describe("Paused", func() {
 			})
 		})
 
+		Context("when a network mode is passed", func() {
+			It("should return an error", func() {
+				Expect(pausedVM.VerifyStartOpts(&vm.StartOpts{
+					NetworkMode: "bridged",
+				})).To(MatchError("the --network-mode flag cannot be used if the VM has already been created"))
+			})
+		})
+
 		Context("when desired domain is passed", func() {
 			It("should return an error", func() {
 				Expect(pausedVM.VerifyStartOpts(&vm.StartOpts{
@@ -183,11 +193,11 @@ var _ = Describe("Paused", func() {
 				mockUI.EXPECT().Say("Resuming VM..."),
 				mockVBox.EXPECT().ResumePausedVM(pausedVM.VMConfig),
 				mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-				mockSSH.EXPECT().WaitForSSH(addresses, []byte("some-private-key"), 5*time.Minute),
+				mockSSH.EXPECT().WaitForSSH(gomock.Any(), addresses, []byte("some-private-key"), 7*time.Minute),
 				mockUI.EXPECT().Say("PCF Dev is now running."),
 			)
 
-			Expect(pausedVM.Resume()).To(Succeed())
+			Expect(pausedVM.Resume(context.Background())).To(Succeed())
 		})
 
 		Context("when waiting for SSH fails", func() {
@@ -200,10 +210,10 @@ var _ = Describe("Paused", func() {
 					mockUI.EXPECT().Say("Resuming VM..."),
 					mockVBox.EXPECT().ResumePausedVM(pausedVM.VMConfig),
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-					mockSSH.EXPECT().WaitForSSH(addresses, []byte("some-private-key"), 5*time.Minute).Return(errors.New("some-error")),
+					mockSSH.EXPECT().WaitForSSH(gomock.Any(), addresses, []byte("some-private-key"), 7*time.Minute).Return(errors.New("some-error")),
 				)
 
-				Expect(pausedVM.Resume()).To(MatchError("failed to resume VM: some-error"))
+				Expect(pausedVM.Resume(context.Background())).To(MatchError("failed to resume VM: some-error"))
 			})
 		})
 
@@ -215,7 +225,7 @@ var _ = Describe("Paused", func() {
 					mockFS.EXPECT().Read("some-private-key-path").Return(nil, errors.New("some-error")),
 				)
 
-				Expect(pausedVM.Resume()).To(MatchError("failed to resume VM: some-error"))
+				Expect(pausedVM.Resume(context.Background())).To(MatchError("failed to resume VM: some-error"))
 			})
 		})
 
@@ -226,7 +236,7 @@ var _ = Describe("Paused", func() {
 					mockVBox.EXPECT().ResumePausedVM(pausedVM.VMConfig).Return(errors.New("some-error")),
 				)
 
-				Expect(pausedVM.Resume()).To(MatchError("failed to resume VM: some-error"))
+				Expect(pausedVM.Resume(context.Background())).To(MatchError("failed to resume VM: some-error"))
 			})
 		})
 	})
@@ -240,14 +250,14 @@ var _ = Describe("Paused", func() {
 	Describe("GetDebugLogs", func() {
 		It("should say a message", func() {
 			mockUI.EXPECT().Say("Your VM is suspended. Resume to retrieve debug logs.")
-			Expect(pausedVM.GetDebugLogs()).To(Succeed())
+			Expect(pausedVM.GetDebugLogs(context.Background())).To(Succeed())
 		})
 	})
 
 	Describe("Trust", func() {
 		It("should say a message", func() {
 			mockUI.EXPECT().Say("Your VM is suspended. Resume to trust VM certificates.")
-			Expect(pausedVM.Trust(&vm.StartOpts{})).To(Succeed())
+			Expect(pausedVM.Trust(context.Background(), &vm.StartOpts{})).To(Succeed())
 		})
 	})
 
@@ -261,7 +271,21 @@ var _ = Describe("Paused", func() {
 	Describe("SSH", func() {
 		It("should say a message", func() {
 			mockUI.EXPECT().Say("Your VM is suspended. Resume to SSH to PCF Dev.")
-			Expect(pausedVM.SSH()).To(Succeed())
+			Expect(pausedVM.SSH(context.Background(), &ssh.SessionOptions{})).To(Succeed())
+		})
+	})
+
+	Describe("Backup", func() {
+		It("should say a message", func() {
+			mockUI.EXPECT().Say("Your VM is suspended. Resume to back up PCF Dev.")
+			Expect(pausedVM.Backup(context.Background(), "some-archive-path")).To(Succeed())
+		})
+	})
+
+	Describe("Restore", func() {
+		It("should say a message", func() {
+			mockUI.EXPECT().Say("Your VM is suspended. Resume to restore PCF Dev.")
+			Expect(pausedVM.Restore(context.Background(), "some-archive-path")).To(Succeed())
 		})
 	})
 })
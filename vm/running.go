@@ -1,9 +1,9 @@
 package vm
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/docker/docker/pkg/term"
 
@@ -21,14 +21,15 @@ type Running struct {
 	SSHClient  SSH
 	Builder    Builder
 	LogFetcher LogFetcher
+	Archiver   Archiver
 	CertStore  CertStore
 	CmdRunner  CmdRunner
 	HelpText   HelpText
 }
 
-func (r *Running) Stop() error {
+func (r *Running) Stop(ctx context.Context) error {
 	r.UI.Say("Stopping VM...")
-	err := r.VBox.StopVM(r.VMConfig)
+	err := r.VBox.StopVM(ctx, r.VMConfig)
 	if err != nil {
 		return &StopVMError{err}
 	}
@@ -36,31 +37,22 @@ func (r *Running) Stop() error {
 	return nil
 }
 
-func (r *Running) Provision(opts *StartOpts) error {
+func (r *Running) Provision(ctx context.Context, opts *StartOpts) error {
 	privateKeyBytes, err := r.FS.Read(r.Config.PrivateKeyPath)
 	if err != nil {
 		return err
 	}
 
-	addresses := []ssh.SSHAddress{
-		{
-			IP:   "127.0.0.1",
-			Port: r.VMConfig.SSHPort,
-		},
-		{
-			IP:   r.VMConfig.IP,
-			Port: "22",
-		},
-	}
+	addresses := ssh.SSHAddresses(r.VMConfig)
 
-	if _, err := r.SSHClient.GetSSHOutput("sudo rm -f /run/pcfdev-healthcheck", addresses, privateKeyBytes, 30*time.Second); err != nil {
+	if _, err := r.SSHClient.GetSSHOutput(ctx, "sudo rm -f /run/pcfdev-healthcheck", addresses, privateKeyBytes, r.Config.HealthCheckTimeout); err != nil {
 		return err
 	}
-	unprovisionedVM, err := r.Builder.VM(r.VMConfig.Name)
+	unprovisionedVM, err := r.Builder.VM(ctx, r.VMConfig.Name)
 	if err != nil {
 		return err
 	}
-	return unprovisionedVM.Provision(opts)
+	return unprovisionedVM.Provision(ctx, opts)
 }
 
 func (r *Running) VerifyStartOpts(opts *StartOpts) error {
@@ -79,10 +71,13 @@ func (r *Running) VerifyStartOpts(opts *StartOpts) error {
 	if opts.IP != "" {
 		return errors.New("the -i flag cannot be used if the VM has already been created")
 	}
+	if opts.NetworkMode != "" {
+		return errors.New("the --network-mode flag cannot be used if the VM has already been created")
+	}
 	return nil
 }
 
-func (r *Running) Start(opts *StartOpts) error {
+func (r *Running) Start(ctx context.Context, opts *StartOpts) error {
 	r.UI.Say("PCF Dev is running.")
 	return nil
 }
@@ -91,9 +86,9 @@ func (r *Running) Status() string {
 	return fmt.Sprintf("Running\nCLI Login: cf login -a https://api.%s --skip-ssl-validation\nApps Manager URL: https://apps.%s\nAdmin user => Email: admin / Password: admin\nRegular user => Email: user / Password: pass", r.VMConfig.Domain, r.VMConfig.Domain)
 }
 
-func (r *Running) Suspend() error {
+func (r *Running) Suspend(ctx context.Context) error {
 	r.UI.Say("Suspending VM...")
-	if err := r.VBox.SuspendVM(r.VMConfig); err != nil {
+	if err := r.VBox.SuspendVM(ctx, r.VMConfig); err != nil {
 		return &SuspendVMError{err}
 	}
 
@@ -101,30 +96,21 @@ func (r *Running) Suspend() error {
 	return nil
 }
 
-func (r *Running) Resume() error {
+func (r *Running) Resume(ctx context.Context) error {
 	r.UI.Say("PCF Dev is running.")
 
 	return nil
 }
 
-func (r *Running) Trust(startOpts *StartOpts) error {
+func (r *Running) Trust(ctx context.Context, startOpts *StartOpts) error {
 	privateKeyBytes, err := r.FS.Read(r.Config.PrivateKeyPath)
 	if err != nil {
 		return &TrustError{err}
 	}
 
-	addresses := []ssh.SSHAddress{
-		{
-			IP:   "127.0.0.1",
-			Port: r.VMConfig.SSHPort,
-		},
-		{
-			IP:   r.VMConfig.IP,
-			Port: "22",
-		},
-	}
+	addresses := ssh.SSHAddresses(r.VMConfig)
 
-	output, err := r.SSHClient.GetSSHOutput("cat /var/pcfdev/openssl/ca_cert.pem", addresses, privateKeyBytes, 5*time.Minute)
+	output, err := r.SSHClient.GetSSHOutput(ctx, "cat /var/pcfdev/openssl/ca_cert.pem", addresses, privateKeyBytes, r.Config.SSHTimeout)
 	if err != nil {
 		return &TrustError{err}
 	}
@@ -164,8 +150,8 @@ func (r *Running) Target(autoTarget bool) error {
 	return nil
 }
 
-func (r *Running) GetDebugLogs() error {
-	if err := r.LogFetcher.FetchLogs(); err != nil {
+func (r *Running) GetDebugLogs(ctx context.Context) error {
+	if err := r.LogFetcher.FetchLogs(ctx); err != nil {
 		return &FetchLogsError{err}
 	}
 
@@ -173,17 +159,38 @@ func (r *Running) GetDebugLogs() error {
 	return nil
 }
 
-func (r *Running) SSH() error {
+func (r *Running) SSH(ctx context.Context, options *ssh.SessionOptions) error {
 	privateKeyBytes, err := r.FS.Read(r.Config.PrivateKeyPath)
 	if err != nil {
 		return err
 	}
 
-	addresses := []ssh.SSHAddress{
-		{IP: "127.0.0.1", Port: r.VMConfig.SSHPort},
-		{IP: r.VMConfig.IP, Port: "22"},
-	}
+	addresses := ssh.SSHAddresses(r.VMConfig)
 
 	stdin, stdout, stderr := term.StdStreams()
-	return r.SSHClient.StartSSHSession(addresses, privateKeyBytes, 5*time.Minute, stdin, stdout, stderr)
+	return r.SSHClient.StartSSHSession(ctx, addresses, privateKeyBytes, r.Config.SSHTimeout, options, stdin, stdout, stderr)
+}
+
+func (r *Running) Backup(ctx context.Context, path string) error {
+	r.UI.Say("Backing up PCF Dev...")
+	if err := r.Archiver.Backup(ctx, path); err != nil {
+		return &BackupError{err}
+	}
+
+	r.UI.Say(fmt.Sprintf("PCF Dev backed up to %s.", path))
+	return nil
+}
+
+func (r *Running) Restore(ctx context.Context, path string) error {
+	r.UI.Say("Restoring PCF Dev...")
+	if err := r.Archiver.Restore(ctx, path); err != nil {
+		return &RestoreError{err}
+	}
+
+	if err := r.Provision(ctx, &StartOpts{}); err != nil {
+		return &RestoreError{err}
+	}
+
+	r.UI.Say(fmt.Sprintf("PCF Dev restored from %s.", path))
+	return nil
 }
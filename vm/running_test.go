@@ -1,6 +1,7 @@
 package vm_test
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -25,6 +26,7 @@ var _ = Describe("Running", func() {
 		mockSSH        *mocks.MockSSH
 		mockVM         *mocks.MockVM
 		mockLogFetcher *mocks.MockLogFetcher
+		mockArchiver   *mocks.MockArchiver
 		mockCertStore  *mocks.MockCertStore
 		mockCmdRunner  *mocks.MockCmdRunner
 
@@ -41,6 +43,7 @@ var _ = Describe("Running", func() {
 		mockVM = mocks.NewMockVM(mockCtrl)
 		mockBuilder = mocks.NewMockBuilder(mockCtrl)
 		mockLogFetcher = mocks.NewMockLogFetcher(mockCtrl)
+		mockArchiver = mocks.NewMockArchiver(mockCtrl)
 		mockCertStore = mocks.NewMockCertStore(mockCtrl)
 		mockCmdRunner = mocks.NewMockCmdRunner(mockCtrl)
 		config = &conf.VMConfig{}
@@ -53,7 +56,9 @@ var _ = Describe("Running", func() {
 				SSHPort: "some-port",
 			},
 			Config: &conf.Config{
-				PrivateKeyPath: "some-private-key-path",
+				SSHTimeout:         7 * time.Minute,
+				HealthCheckTimeout: 45 * time.Second,
+				PrivateKeyPath:     "some-private-key-path",
 			},
 
 			VBox:       mockVBox,
@@ -62,6 +67,7 @@ var _ = Describe("Running", func() {
 			Builder:    mockBuilder,
 			SSHClient:  mockSSH,
 			LogFetcher: mockLogFetcher,
+			Archiver:   mockArchiver,
 			CertStore:  mockCertStore,
 			CmdRunner:  mockCmdRunner,
 		}
@@ -75,21 +81,21 @@ var _ = Describe("Running", func() {
 		It("should stop the vm", func() {
 			gomock.InOrder(
 				mockUI.EXPECT().Say("Stopping VM..."),
-				mockVBox.EXPECT().StopVM(runningVM.VMConfig),
+				mockVBox.EXPECT().StopVM(gomock.Any(), runningVM.VMConfig),
 				mockUI.EXPECT().Say("PCF Dev is now stopped."),
 			)
 
-			runningVM.Stop()
+			runningVM.Stop(context.Background())
 		})
 
 		Context("when stopped the vm fails", func() {
 			It("should return an error", func() {
 				gomock.InOrder(
 					mockUI.EXPECT().Say("Stopping VM..."),
-					mockVBox.EXPECT().StopVM(runningVM.VMConfig).Return(errors.New("some-error")),
+					mockVBox.EXPECT().StopVM(gomock.Any(), runningVM.VMConfig).Return(errors.New("some-error")),
 				)
 
-				Expect(runningVM.Stop()).To(MatchError("failed to stop VM: some-error"))
+				Expect(runningVM.Stop(context.Background())).To(MatchError("failed to stop VM: some-error"))
 			})
 		})
 	})
@@ -133,6 +139,14 @@ var _ = Describe("Running", func() {
 			})
 		})
 
+		Context("when a network mode is passed", func() {
+			It("should return an error", func() {
+				Expect(runningVM.VerifyStartOpts(&vm.StartOpts{
+					NetworkMode: "bridged",
+				})).To(MatchError("the --network-mode flag cannot be used if the VM has already been created"))
+			})
+		})
+
 		Context("when desired domain is passed", func() {
 			It("should return an error", func() {
 				Expect(runningVM.VerifyStartOpts(&vm.StartOpts{
@@ -146,7 +160,7 @@ var _ = Describe("Running", func() {
 		It("should say a message", func() {
 			mockUI.EXPECT().Say("PCF Dev is running.")
 
-			runningVM.Start(&vm.StartOpts{})
+			runningVM.Start(context.Background(), &vm.StartOpts{})
 		})
 	})
 
@@ -159,12 +173,12 @@ var _ = Describe("Running", func() {
 
 			gomock.InOrder(
 				mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-				mockSSH.EXPECT().GetSSHOutput("sudo rm -f /run/pcfdev-healthcheck", sshAddresses, []byte("some-private-key"), 30*time.Second).Return("", nil),
-				mockBuilder.EXPECT().VM("some-vm").Return(mockVM, nil),
-				mockVM.EXPECT().Provision(&vm.StartOpts{}),
+				mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo rm -f /run/pcfdev-healthcheck", sshAddresses, []byte("some-private-key"), 45*time.Second).Return("", nil),
+				mockBuilder.EXPECT().VM(gomock.Any(), "some-vm").Return(mockVM, nil),
+				mockVM.EXPECT().Provision(gomock.Any(), &vm.StartOpts{}),
 			)
 
-			runningVM.Provision(&vm.StartOpts{})
+			runningVM.Provision(context.Background(), &vm.StartOpts{})
 		})
 
 		Context("when removing healthcheck file fails", func() {
@@ -175,10 +189,10 @@ var _ = Describe("Running", func() {
 				}
 				gomock.InOrder(
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-					mockSSH.EXPECT().GetSSHOutput("sudo rm -f /run/pcfdev-healthcheck", sshAddresses, []byte("some-private-key"), 30*time.Second).Return("", errors.New("some-error")),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo rm -f /run/pcfdev-healthcheck", sshAddresses, []byte("some-private-key"), 45*time.Second).Return("", errors.New("some-error")),
 				)
 
-				Expect(runningVM.Provision(&vm.StartOpts{})).To(MatchError("some-error"))
+				Expect(runningVM.Provision(context.Background(), &vm.StartOpts{})).To(MatchError("some-error"))
 			})
 		})
 
@@ -186,7 +200,7 @@ var _ = Describe("Running", func() {
 			It("should return an error", func() {
 				mockFS.EXPECT().Read("some-private-key-path").Return(nil, errors.New("some-error"))
 
-				Expect(runningVM.Provision(&vm.StartOpts{})).To(MatchError("some-error"))
+				Expect(runningVM.Provision(context.Background(), &vm.StartOpts{})).To(MatchError("some-error"))
 			})
 		})
 
@@ -198,11 +212,11 @@ var _ = Describe("Running", func() {
 				}
 				gomock.InOrder(
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-					mockSSH.EXPECT().GetSSHOutput("sudo rm -f /run/pcfdev-healthcheck", sshAddresses, []byte("some-private-key"), 30*time.Second).Return("", nil),
-					mockBuilder.EXPECT().VM("some-vm").Return(nil, errors.New("some-error")),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo rm -f /run/pcfdev-healthcheck", sshAddresses, []byte("some-private-key"), 45*time.Second).Return("", nil),
+					mockBuilder.EXPECT().VM(gomock.Any(), "some-vm").Return(nil, errors.New("some-error")),
 				)
 
-				Expect(runningVM.Provision(&vm.StartOpts{})).To(MatchError("some-error"))
+				Expect(runningVM.Provision(context.Background(), &vm.StartOpts{})).To(MatchError("some-error"))
 			})
 		})
 
@@ -214,12 +228,12 @@ var _ = Describe("Running", func() {
 				}
 				gomock.InOrder(
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-					mockSSH.EXPECT().GetSSHOutput("sudo rm -f /run/pcfdev-healthcheck", sshAddresses, []byte("some-private-key"), 30*time.Second).Return("", nil),
-					mockBuilder.EXPECT().VM("some-vm").Return(mockVM, nil),
-					mockVM.EXPECT().Provision(&vm.StartOpts{}).Return(errors.New("some-error")),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo rm -f /run/pcfdev-healthcheck", sshAddresses, []byte("some-private-key"), 45*time.Second).Return("", nil),
+					mockBuilder.EXPECT().VM(gomock.Any(), "some-vm").Return(mockVM, nil),
+					mockVM.EXPECT().Provision(gomock.Any(), &vm.StartOpts{}).Return(errors.New("some-error")),
 				)
 
-				Expect(runningVM.Provision(&vm.StartOpts{})).To(MatchError("some-error"))
+				Expect(runningVM.Provision(context.Background(), &vm.StartOpts{})).To(MatchError("some-error"))
 			})
 		})
 	})
@@ -233,18 +247,18 @@ var _ = Describe("Running", func() {
 	Describe("Suspend", func() {
 		It("should suspend the vm", func() {
 			mockUI.EXPECT().Say("Suspending VM...")
-			mockVBox.EXPECT().SuspendVM(runningVM.VMConfig)
+			mockVBox.EXPECT().SuspendVM(gomock.Any(), runningVM.VMConfig)
 			mockUI.EXPECT().Say("PCF Dev is now suspended.")
 
-			Expect(runningVM.Suspend()).To(Succeed())
+			Expect(runningVM.Suspend(context.Background())).To(Succeed())
 		})
 
 		Context("when suspending the vm fails", func() {
 			It("should return an error", func() {
 				mockUI.EXPECT().Say("Suspending VM...")
-				mockVBox.EXPECT().SuspendVM(runningVM.VMConfig).Return(errors.New("some-error"))
+				mockVBox.EXPECT().SuspendVM(gomock.Any(), runningVM.VMConfig).Return(errors.New("some-error"))
 
-				Expect(runningVM.Suspend()).To(MatchError("failed to suspend VM: some-error"))
+				Expect(runningVM.Suspend(context.Background())).To(MatchError("failed to suspend VM: some-error"))
 			})
 		})
 	})
@@ -253,25 +267,25 @@ var _ = Describe("Running", func() {
 		It("should say a message", func() {
 			mockUI.EXPECT().Say("PCF Dev is running.")
 
-			Expect(runningVM.Resume()).To(Succeed())
+			Expect(runningVM.Resume(context.Background())).To(Succeed())
 		})
 	})
 
 	Describe("GetDebugLogs", func() {
 		It("should succeed", func() {
 			gomock.InOrder(
-				mockLogFetcher.EXPECT().FetchLogs(),
+				mockLogFetcher.EXPECT().FetchLogs(gomock.Any()),
 				mockUI.EXPECT().Say("Debug logs written to pcfdev-debug.tgz. While some scrubbing has taken place, please remove any remaining sensitive information from these logs before sharing."),
 			)
 
-			Expect(runningVM.GetDebugLogs()).To(Succeed())
+			Expect(runningVM.GetDebugLogs(context.Background())).To(Succeed())
 		})
 
 		Context("when fetching logs fails", func() {
 			It("should return the error", func() {
-				mockLogFetcher.EXPECT().FetchLogs().Return(errors.New("some-error"))
+				mockLogFetcher.EXPECT().FetchLogs(gomock.Any()).Return(errors.New("some-error"))
 
-				Expect(runningVM.GetDebugLogs()).To(MatchError("failed to retrieve logs: some-error"))
+				Expect(runningVM.GetDebugLogs(context.Background())).To(MatchError("failed to retrieve logs: some-error"))
 			})
 		})
 	})
@@ -284,19 +298,19 @@ var _ = Describe("Running", func() {
 			}
 			gomock.InOrder(
 				mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-				mockSSH.EXPECT().GetSSHOutput("cat /var/pcfdev/openssl/ca_cert.pem", sshAddresses, []byte("some-private-key"), 5*time.Minute).Return("some-cert", nil),
+				mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "cat /var/pcfdev/openssl/ca_cert.pem", sshAddresses, []byte("some-private-key"), 7*time.Minute).Return("some-cert", nil),
 				mockCertStore.EXPECT().Store("some-cert"),
 				mockUI.EXPECT().Say("***Warning: a self-signed certificate for *.some-domain has been inserted into your OS certificate store. To remove this certificate, run: cf dev untrust***"),
 			)
 
-			Expect(runningVM.Trust(&vm.StartOpts{})).To(Succeed())
+			Expect(runningVM.Trust(context.Background(), &vm.StartOpts{})).To(Succeed())
 		})
 
 		Context("when there is an error reading the private key", func() {
 			It("should return the error", func() {
 				mockFS.EXPECT().Read("some-private-key-path").Return(nil, errors.New("some-error"))
 
-				Expect(runningVM.Trust(&vm.StartOpts{})).To(MatchError("failed to trust VM certificates: some-error"))
+				Expect(runningVM.Trust(context.Background(), &vm.StartOpts{})).To(MatchError("failed to trust VM certificates: some-error"))
 			})
 		})
 
@@ -308,10 +322,10 @@ var _ = Describe("Running", func() {
 				}
 				gomock.InOrder(
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-					mockSSH.EXPECT().GetSSHOutput("cat /var/pcfdev/openssl/ca_cert.pem", sshAddresses, []byte("some-private-key"), 5*time.Minute).Return("", errors.New("some-error")),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "cat /var/pcfdev/openssl/ca_cert.pem", sshAddresses, []byte("some-private-key"), 7*time.Minute).Return("", errors.New("some-error")),
 				)
 
-				Expect(runningVM.Trust(&vm.StartOpts{})).To(MatchError("failed to trust VM certificates: some-error"))
+				Expect(runningVM.Trust(context.Background(), &vm.StartOpts{})).To(MatchError("failed to trust VM certificates: some-error"))
 			})
 		})
 
@@ -323,11 +337,11 @@ var _ = Describe("Running", func() {
 				}
 				gomock.InOrder(
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-					mockSSH.EXPECT().GetSSHOutput("cat /var/pcfdev/openssl/ca_cert.pem", sshAddresses, []byte("some-private-key"), 5*time.Minute).Return("some-cert", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "cat /var/pcfdev/openssl/ca_cert.pem", sshAddresses, []byte("some-private-key"), 7*time.Minute).Return("some-cert", nil),
 					mockCertStore.EXPECT().Store("some-cert").Return(errors.New("some-error")),
 				)
 
-				Expect(runningVM.Trust(&vm.StartOpts{})).To(MatchError("failed to trust VM certificates: some-error"))
+				Expect(runningVM.Trust(context.Background(), &vm.StartOpts{})).To(MatchError("failed to trust VM certificates: some-error"))
 			})
 		})
 
@@ -339,16 +353,96 @@ var _ = Describe("Running", func() {
 				}
 				gomock.InOrder(
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-					mockSSH.EXPECT().GetSSHOutput("cat /var/pcfdev/openssl/ca_cert.pem", sshAddresses, []byte("some-private-key"), 5*time.Minute).Return("some-cert", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "cat /var/pcfdev/openssl/ca_cert.pem", sshAddresses, []byte("some-private-key"), 7*time.Minute).Return("some-cert", nil),
 					mockUI.EXPECT().Say("some-cert"),
 				)
 
-				Expect(runningVM.Trust(&vm.StartOpts{PrintCA: true})).To(Succeed())
+				Expect(runningVM.Trust(context.Background(), &vm.StartOpts{PrintCA: true})).To(Succeed())
+			})
+		})
+	})
+
+	Describe("Backup", func() {
+		It("should back up the VM to the archive", func() {
+			gomock.InOrder(
+				mockUI.EXPECT().Say("Backing up PCF Dev..."),
+				mockArchiver.EXPECT().Backup(gomock.Any(), "some-archive-path"),
+				mockUI.EXPECT().Say("PCF Dev backed up to some-archive-path."),
+			)
+
+			Expect(runningVM.Backup(context.Background(), "some-archive-path")).To(Succeed())
+		})
+
+		Context("when backing up fails", func() {
+			It("should return an error", func() {
+				gomock.InOrder(
+					mockUI.EXPECT().Say("Backing up PCF Dev..."),
+					mockArchiver.EXPECT().Backup(gomock.Any(), "some-archive-path").Return(errors.New("some-error")),
+				)
+
+				Expect(runningVM.Backup(context.Background(), "some-archive-path")).To(MatchError("failed to back up PCF Dev: some-error"))
+			})
+		})
+	})
+
+	Describe("Restore", func() {
+		var sshAddresses []ssh.SSHAddress
+
+		BeforeEach(func() {
+			sshAddresses = []ssh.SSHAddress{
+				{IP: "127.0.0.1", Port: "some-port"},
+				{IP: "some-ip", Port: "22"},
+			}
+		})
+
+		It("should restore the archive and reprovision the VM", func() {
+			gomock.InOrder(
+				mockUI.EXPECT().Say("Restoring PCF Dev..."),
+				mockArchiver.EXPECT().Restore(gomock.Any(), "some-archive-path"),
+				mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
+				mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo rm -f /run/pcfdev-healthcheck", sshAddresses, []byte("some-private-key"), 45*time.Second).Return("", nil),
+				mockBuilder.EXPECT().VM(gomock.Any(), "some-vm").Return(mockVM, nil),
+				mockVM.EXPECT().Provision(gomock.Any(), &vm.StartOpts{}),
+				mockUI.EXPECT().Say("PCF Dev restored from some-archive-path."),
+			)
+
+			Expect(runningVM.Restore(context.Background(), "some-archive-path")).To(Succeed())
+		})
+
+		Context("when restoring fails", func() {
+			It("should return an error", func() {
+				gomock.InOrder(
+					mockUI.EXPECT().Say("Restoring PCF Dev..."),
+					mockArchiver.EXPECT().Restore(gomock.Any(), "some-archive-path").Return(errors.New("some-error")),
+				)
+
+				Expect(runningVM.Restore(context.Background(), "some-archive-path")).To(MatchError("failed to restore PCF Dev: some-error"))
+			})
+		})
+
+		Context("when reprovisioning fails", func() {
+			It("should return an error", func() {
+				gomock.InOrder(
+					mockUI.EXPECT().Say("Restoring PCF Dev..."),
+					mockArchiver.EXPECT().Restore(gomock.Any(), "some-archive-path"),
+					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "sudo rm -f /run/pcfdev-healthcheck", sshAddresses, []byte("some-private-key"), 45*time.Second).Return("", nil),
+					mockBuilder.EXPECT().VM(gomock.Any(), "some-vm").Return(mockVM, nil),
+					mockVM.EXPECT().Provision(gomock.Any(), &vm.StartOpts{}).Return(errors.New("some-error")),
+				)
+
+				Expect(runningVM.Restore(context.Background(), "some-archive-path")).To(MatchError("failed to restore PCF Dev: some-error"))
 			})
 		})
 	})
 
 	Describe("SSH", func() {
+		var opts *ssh.SessionOptions
+
+		BeforeEach(func() {
+			opts = &ssh.SessionOptions{Command: "some-command", TTY: true}
+		})
+
 		It("should execute ssh on the client", func() {
 			addresses := []ssh.SSHAddress{
 				{IP: "127.0.0.1", Port: "some-port"},
@@ -358,10 +452,10 @@ var _ = Describe("Running", func() {
 
 			gomock.InOrder(
 				mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-				mockSSH.EXPECT().StartSSHSession(addresses, []byte("some-private-key"), 5*time.Minute, stdin, stdout, stderr),
+				mockSSH.EXPECT().StartSSHSession(gomock.Any(), addresses, []byte("some-private-key"), 7*time.Minute, opts, stdin, stdout, stderr),
 			)
 
-			Expect(runningVM.SSH()).To(Succeed())
+			Expect(runningVM.SSH(context.Background(), opts)).To(Succeed())
 		})
 
 		Context("when executing ssh fails", func() {
@@ -374,10 +468,10 @@ var _ = Describe("Running", func() {
 
 				gomock.InOrder(
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-					mockSSH.EXPECT().StartSSHSession(addresses, []byte("some-private-key"), 5*time.Minute, stdin, stdout, stderr).Return(errors.New("some-error")),
+					mockSSH.EXPECT().StartSSHSession(gomock.Any(), addresses, []byte("some-private-key"), 7*time.Minute, opts, stdin, stdout, stderr).Return(errors.New("some-error")),
 				)
 
-				Expect(runningVM.SSH()).To(MatchError("some-error"))
+				Expect(runningVM.SSH(context.Background(), opts)).To(MatchError("some-error"))
 			})
 		})
 
@@ -385,7 +479,7 @@ var _ = Describe("Running", func() {
 			It("should return an error", func() {
 				mockFS.EXPECT().Read("some-private-key-path").Return(nil, errors.New("some-error"))
 
-				Expect(runningVM.SSH()).To(MatchError("some-error"))
+				Expect(runningVM.SSH(context.Background(), opts)).To(MatchError("some-error"))
 			})
 		})
 	})
@@ -1,9 +1,9 @@
 package vm
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/pivotal-cf/pcfdev-cli/config"
 	"github.com/pivotal-cf/pcfdev-cli/ssh"
@@ -35,21 +35,24 @@ func (s *Saved) VerifyStartOpts(opts *StartOpts) error {
 	if opts.IP != "" {
 		return errors.New("the -i flag cannot be used if the VM has already been created")
 	}
+	if opts.NetworkMode != "" {
+		return errors.New("the --network-mode flag cannot be used if the VM has already been created")
+	}
 	if err := s.checkMemory(); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s *Saved) Start(opts *StartOpts) error {
-	return s.Resume()
+func (s *Saved) Start(ctx context.Context, opts *StartOpts) error {
+	return s.Resume(ctx)
 }
 
-func (s *Saved) Provision(opts *StartOpts) error {
+func (s *Saved) Provision(ctx context.Context, opts *StartOpts) error {
 	return nil
 }
 
-func (s *Saved) Stop() error {
+func (s *Saved) Stop(ctx context.Context) error {
 	s.UI.Say("Your VM is currently suspended. You must resume your VM with `cf dev resume` to shut it down.")
 	return nil
 }
@@ -58,12 +61,12 @@ func (s *Saved) Status() string {
 	return "Suspended"
 }
 
-func (s *Saved) Suspend() error {
+func (s *Saved) Suspend(ctx context.Context) error {
 	s.UI.Say("Your VM is suspended.")
 	return nil
 }
 
-func (s *Saved) Resume() error {
+func (s *Saved) Resume(ctx context.Context) error {
 	if err := s.checkMemory(); err != nil {
 		return err
 	}
@@ -77,11 +80,8 @@ func (s *Saved) Resume() error {
 		return &ResumeVMError{err}
 	}
 
-	addresses := []ssh.SSHAddress{
-		{IP: "127.0.0.1", Port: s.VMConfig.SSHPort},
-		{IP: s.VMConfig.IP, Port: "22"},
-	}
-	if err := s.SSHClient.WaitForSSH(addresses, privateKeyBytes, 5*time.Minute); err != nil {
+	addresses := ssh.SSHAddresses(s.VMConfig)
+	if err := s.SSHClient.WaitForSSH(ctx, addresses, privateKeyBytes, s.Config.SSHTimeout); err != nil {
 		return &ResumeVMError{err}
 	}
 
@@ -99,12 +99,12 @@ func (s *Saved) checkMemory() error {
 	return nil
 }
 
-func (s *Saved) GetDebugLogs() error {
+func (s *Saved) GetDebugLogs(ctx context.Context) error {
 	s.UI.Say("Your VM is suspended. Resume to retrieve debug logs.")
 	return nil
 }
 
-func (s *Saved) Trust(startOps *StartOpts) error {
+func (s *Saved) Trust(ctx context.Context, startOps *StartOpts) error {
 	s.UI.Say("Your VM is suspended. Resume to trust VM certificates.")
 	return nil
 }
@@ -114,7 +114,17 @@ func (s *Saved) Target(autoTarget bool) error {
 	return nil
 }
 
-func (s *Saved) SSH() error {
+func (s *Saved) SSH(context.Context, *ssh.SessionOptions) error {
 	s.UI.Say("Your VM is suspended. Resume to SSH to PCF Dev.")
 	return nil
 }
+
+func (s *Saved) Backup(ctx context.Context, path string) error {
+	s.UI.Say("Your VM is suspended. Resume to back up PCF Dev.")
+	return nil
+}
+
+func (s *Saved) Restore(ctx context.Context, path string) error {
+	s.UI.Say("Your VM is suspended. Resume to restore PCF Dev.")
+	return nil
+}
@@ -1,6 +1,7 @@
 package vm_test
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -44,6 +45,7 @@ var _ = Describe("Saved", func() {
 			FS:        mockFS,
 
 			Config: &config.Config{
+				SSHTimeout:     7 * time.Minute,
 				PrivateKeyPath: "some-private-key-path",
 			},
 		}
@@ -56,14 +58,14 @@ var _ = Describe("Saved", func() {
 	Describe("Suspend", func() {
 		It("should say a message", func() {
 			mockUI.EXPECT().Say("Your VM is suspended.")
-			Expect(savedVM.Suspend()).To(Succeed())
+			Expect(savedVM.Suspend(context.Background())).To(Succeed())
 		})
 	})
 
 	Describe("Stop", func() {
 		It("should say a message", func() {
 			mockUI.EXPECT().Say("Your VM is currently suspended. You must resume your VM with `cf dev resume` to shut it down.")
-			Expect(savedVM.Stop()).To(Succeed())
+			Expect(savedVM.Stop(context.Background())).To(Succeed())
 		})
 	})
 
@@ -77,11 +79,11 @@ var _ = Describe("Saved", func() {
 				mockUI.EXPECT().Say("Resuming VM..."),
 				mockVBox.EXPECT().ResumeSavedVM(savedVM.VMConfig),
 				mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-				mockSSH.EXPECT().WaitForSSH(addresses, []byte("some-private-key"), 5*time.Minute),
+				mockSSH.EXPECT().WaitForSSH(gomock.Any(), addresses, []byte("some-private-key"), 7*time.Minute),
 				mockUI.EXPECT().Say("PCF Dev is now running."),
 			)
 
-			Expect(savedVM.Start(&vm.StartOpts{})).To(Succeed())
+			Expect(savedVM.Start(context.Background(), &vm.StartOpts{})).To(Succeed())
 		})
 
 		Context("when starting the vm fails", func() {
@@ -91,7 +93,7 @@ var _ = Describe("Saved", func() {
 					mockVBox.EXPECT().ResumeSavedVM(savedVM.VMConfig).Return(errors.New("some-error")),
 				)
 
-				Expect(savedVM.Start(&vm.StartOpts{})).To(MatchError("failed to resume VM: some-error"))
+				Expect(savedVM.Start(context.Background(), &vm.StartOpts{})).To(MatchError("failed to resume VM: some-error"))
 			})
 		})
 
@@ -103,7 +105,7 @@ var _ = Describe("Saved", func() {
 					mockFS.EXPECT().Read("some-private-key-path").Return(nil, errors.New("some-error")),
 				)
 
-				Expect(savedVM.Start(&vm.StartOpts{})).To(MatchError("failed to resume VM: some-error"))
+				Expect(savedVM.Start(context.Background(), &vm.StartOpts{})).To(MatchError("failed to resume VM: some-error"))
 			})
 		})
 		Context("when waiting for SSH fails", func() {
@@ -116,10 +118,10 @@ var _ = Describe("Saved", func() {
 					mockUI.EXPECT().Say("Resuming VM..."),
 					mockVBox.EXPECT().ResumeSavedVM(savedVM.VMConfig),
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-					mockSSH.EXPECT().WaitForSSH(addresses, []byte("some-private-key"), 5*time.Minute).Return(errors.New("some-error")),
+					mockSSH.EXPECT().WaitForSSH(gomock.Any(), addresses, []byte("some-private-key"), 7*time.Minute).Return(errors.New("some-error")),
 				)
 
-				Expect(savedVM.Start(&vm.StartOpts{})).To(MatchError("failed to resume VM: some-error"))
+				Expect(savedVM.Start(context.Background(), &vm.StartOpts{})).To(MatchError("failed to resume VM: some-error"))
 			})
 		})
 	})
@@ -157,6 +159,14 @@ var _ = Describe("Saved", func() {
 			})
 		})
 
+		Context("when a network mode is passed", func() {
+			It("should return an error", func() {
+				Expect(savedVM.VerifyStartOpts(&vm.StartOpts{
+					NetworkMode: "bridged",
+				})).To(MatchError("the --network-mode flag cannot be used if the VM has already been created"))
+			})
+		})
+
 		Context("when desired domain is passed", func() {
 			It("should return an error", func() {
 				Expect(savedVM.VerifyStartOpts(&vm.StartOpts{
@@ -212,11 +222,11 @@ var _ = Describe("Saved", func() {
 				mockUI.EXPECT().Say("Resuming VM..."),
 				mockVBox.EXPECT().ResumeSavedVM(savedVM.VMConfig),
 				mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-				mockSSH.EXPECT().WaitForSSH(addresses, []byte("some-private-key"), 5*time.Minute),
+				mockSSH.EXPECT().WaitForSSH(gomock.Any(), addresses, []byte("some-private-key"), 7*time.Minute),
 				mockUI.EXPECT().Say("PCF Dev is now running."),
 			)
 
-			Expect(savedVM.Resume()).To(Succeed())
+			Expect(savedVM.Resume(context.Background())).To(Succeed())
 		})
 
 		Context("when waiting for SSH fails", func() {
@@ -231,10 +241,10 @@ var _ = Describe("Saved", func() {
 					mockUI.EXPECT().Say("Resuming VM..."),
 					mockVBox.EXPECT().ResumeSavedVM(savedVM.VMConfig),
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-					mockSSH.EXPECT().WaitForSSH(addresses, []byte("some-private-key"), 5*time.Minute).Return(errors.New("some-error")),
+					mockSSH.EXPECT().WaitForSSH(gomock.Any(), addresses, []byte("some-private-key"), 7*time.Minute).Return(errors.New("some-error")),
 				)
 
-				Expect(savedVM.Resume()).To(MatchError("failed to resume VM: some-error"))
+				Expect(savedVM.Resume(context.Background())).To(MatchError("failed to resume VM: some-error"))
 			})
 		})
 
@@ -248,7 +258,7 @@ var _ = Describe("Saved", func() {
 					mockFS.EXPECT().Read("some-private-key-path").Return(nil, errors.New("some-error")),
 				)
 
-				Expect(savedVM.Resume()).To(MatchError("failed to resume VM: some-error"))
+				Expect(savedVM.Resume(context.Background())).To(MatchError("failed to resume VM: some-error"))
 			})
 		})
 
@@ -261,7 +271,7 @@ var _ = Describe("Saved", func() {
 					mockVBox.EXPECT().ResumeSavedVM(savedVM.VMConfig).Return(errors.New("some-error")),
 				)
 
-				Expect(savedVM.Resume()).To(MatchError("failed to resume VM: some-error"))
+				Expect(savedVM.Resume(context.Background())).To(MatchError("failed to resume VM: some-error"))
 			})
 		})
 
@@ -280,11 +290,11 @@ var _ = Describe("Saved", func() {
 						mockUI.EXPECT().Say("Resuming VM..."),
 						mockVBox.EXPECT().ResumeSavedVM(savedVM.VMConfig),
 						mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-						mockSSH.EXPECT().WaitForSSH(addresses, []byte("some-private-key"), 5*time.Minute),
+						mockSSH.EXPECT().WaitForSSH(gomock.Any(), addresses, []byte("some-private-key"), 7*time.Minute),
 						mockUI.EXPECT().Say("PCF Dev is now running."),
 					)
 
-					Expect(savedVM.Resume()).To(Succeed())
+					Expect(savedVM.Resume(context.Background())).To(Succeed())
 				})
 			})
 
@@ -295,7 +305,7 @@ var _ = Describe("Saved", func() {
 
 					mockUI.EXPECT().Confirm("Less than 3000 MB of free memory detected, continue (y/N): ").Return(false)
 
-					Expect(savedVM.Resume()).To(MatchError("user declined to continue, exiting"))
+					Expect(savedVM.Resume(context.Background())).To(MatchError("user declined to continue, exiting"))
 				})
 			})
 		})
@@ -310,14 +320,14 @@ var _ = Describe("Saved", func() {
 	Describe("GetDebugLogs", func() {
 		It("should say a message", func() {
 			mockUI.EXPECT().Say("Your VM is suspended. Resume to retrieve debug logs.")
-			Expect(savedVM.GetDebugLogs()).To(Succeed())
+			Expect(savedVM.GetDebugLogs(context.Background())).To(Succeed())
 		})
 	})
 
 	Describe("Trust", func() {
 		It("should say a message", func() {
 			mockUI.EXPECT().Say("Your VM is suspended. Resume to trust VM certificates.")
-			Expect(savedVM.Trust(&vm.StartOpts{})).To(Succeed())
+			Expect(savedVM.Trust(context.Background(), &vm.StartOpts{})).To(Succeed())
 		})
 	})
 
@@ -331,7 +341,21 @@ var _ = Describe("Saved", func() {
 	Describe("SSH", func() {
 		It("should say a message", func() {
 			mockUI.EXPECT().Say("Your VM is suspended. Resume to SSH to PCF Dev.")
-			Expect(savedVM.SSH()).To(Succeed())
+			Expect(savedVM.SSH(context.Background(), &ssh.SessionOptions{})).To(Succeed())
+		})
+	})
+
+	Describe("Backup", func() {
+		It("should say a message", func() {
+			mockUI.EXPECT().Say("Your VM is suspended. Resume to back up PCF Dev.")
+			Expect(savedVM.Backup(context.Background(), "some-archive-path")).To(Succeed())
+		})
+	})
+
+	Describe("Restore", func() {
+		It("should say a message", func() {
+			mockUI.EXPECT().Say("Your VM is suspended. Resume to restore PCF Dev.")
+			Expect(savedVM.Restore(context.Background(), "some-archive-path")).To(Succeed())
 		})
 	})
 })
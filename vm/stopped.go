@@ -1,13 +1,13 @@
 package vm
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"sort"
 	"strings"
-	"time"
 
 	"github.com/pivotal-cf/pcfdev-cli/config"
 	"github.com/pivotal-cf/pcfdev-cli/helpers"
@@ -25,7 +25,7 @@ type Stopped struct {
 	Builder   Builder
 }
 
-func (s *Stopped) Stop() error {
+func (s *Stopped) Stop(ctx context.Context) error {
 	s.UI.Say("PCF Dev is stopped.")
 	return nil
 }
@@ -49,6 +49,9 @@ func (s *Stopped) VerifyStartOpts(opts *StartOpts) error {
 	if opts.IP != "" {
 		return errors.New("the -i flag cannot be used if the VM has already been created")
 	}
+	if opts.NetworkMode != "" {
+		return errors.New("the --network-mode flag cannot be used if the VM has already been created")
+	}
 	if s.VMConfig.Memory > s.Config.FreeMemory {
 		if !s.UI.Confirm(fmt.Sprintf("Less than %d MB of free memory detected, continue (y/N): ", s.VMConfig.Memory)) {
 			return errors.New("user declined to continue, exiting")
@@ -57,9 +60,9 @@ func (s *Stopped) VerifyStartOpts(opts *StartOpts) error {
 	return nil
 }
 
-func (s *Stopped) Start(opts *StartOpts) error {
+func (s *Stopped) Start(ctx context.Context, opts *StartOpts) error {
 	s.UI.Say("Starting VM...")
-	if err := s.VBox.StartVM(s.VMConfig); err != nil {
+	if err := s.VBox.StartVM(ctx, s.VMConfig); err != nil {
 		return &StartVMError{err}
 	}
 
@@ -111,18 +114,9 @@ func (s *Stopped) Start(opts *StartOpts) error {
 		return &StartVMError{err}
 	}
 
-	addresses := []ssh.SSHAddress{
-		{
-			IP:   "127.0.0.1",
-			Port: s.VMConfig.SSHPort,
-		},
-		{
-			IP:   s.VMConfig.IP,
-			Port: "22",
-		},
-	}
+	addresses := ssh.SSHAddresses(s.VMConfig)
 
-	output, err := s.SSHClient.GetSSHOutput("if [[ -f /var/pcfdev/provision-options.json ]]; then cat /var/pcfdev/provision-options.json; fi", addresses, privateKeyBytes, 5*time.Minute)
+	output, err := s.SSHClient.GetSSHOutput(ctx, "if [[ -f /var/pcfdev/provision-options.json ]]; then cat /var/pcfdev/provision-options.json; fi", addresses, privateKeyBytes, s.Config.SSHTimeout)
 	if err != nil {
 		return &StartVMError{err}
 	}
@@ -143,7 +137,7 @@ func (s *Stopped) Start(opts *StartOpts) error {
 		return &StartVMError{err}
 	}
 
-	if err := s.SSHClient.RunSSHCommand("echo '"+string(data)+"' | sudo tee /var/pcfdev/provision-options.json >/dev/null", addresses, privateKeyBytes, 5*time.Minute, os.Stdout, os.Stderr); err != nil {
+	if err := s.SSHClient.RunSSHCommand(ctx, "echo '"+string(data)+"' | sudo tee /var/pcfdev/provision-options.json >/dev/null", addresses, privateKeyBytes, s.Config.SSHTimeout, os.Stdout, os.Stderr); err != nil {
 		return &StartVMError{err}
 	}
 
@@ -152,15 +146,15 @@ func (s *Stopped) Start(opts *StartOpts) error {
 		return nil
 	}
 
-	unprovisionedVM, err := s.Builder.VM(s.VMConfig.Name)
+	unprovisionedVM, err := s.Builder.VM(ctx, s.VMConfig.Name)
 	if err != nil {
 		return &StartVMError{err}
 	}
 
-	return unprovisionedVM.Provision(opts)
+	return unprovisionedVM.Provision(ctx, opts)
 }
 
-func (s *Stopped) Provision(opts *StartOpts) error {
+func (s *Stopped) Provision(ctx context.Context, opts *StartOpts) error {
 	return nil
 }
 
@@ -168,22 +162,22 @@ func (s *Stopped) Status() string {
 	return "Stopped"
 }
 
-func (s *Stopped) Suspend() error {
+func (s *Stopped) Suspend(ctx context.Context) error {
 	s.UI.Say("Your VM is currently stopped and cannot be suspended.")
 	return nil
 }
 
-func (s *Stopped) Resume() error {
+func (s *Stopped) Resume(ctx context.Context) error {
 	s.UI.Say("Your VM is currently stopped. Only a suspended VM can be resumed.")
 	return nil
 }
 
-func (s *Stopped) GetDebugLogs() error {
+func (s *Stopped) GetDebugLogs(ctx context.Context) error {
 	s.UI.Say("Your VM is currently stopped. Start VM to retrieve debug logs.")
 	return nil
 }
 
-func (s *Stopped) Trust(startOps *StartOpts) error {
+func (s *Stopped) Trust(ctx context.Context, startOps *StartOpts) error {
 	s.UI.Say("Your VM is currently stopped. Start VM to trust VM certificates.")
 	return nil
 }
@@ -193,7 +187,17 @@ func (s *Stopped) Target(autoTarget bool) error {
 	return nil
 }
 
-func (s *Stopped) SSH() error {
+func (s *Stopped) SSH(context.Context, *ssh.SessionOptions) error {
 	s.UI.Say("Your VM is currently stopped. Start VM to SSH to PCF Dev.")
 	return nil
 }
+
+func (s *Stopped) Backup(ctx context.Context, path string) error {
+	s.UI.Say("Your VM is currently stopped. Start VM to back up PCF Dev.")
+	return nil
+}
+
+func (s *Stopped) Restore(ctx context.Context, path string) error {
+	s.UI.Say("Your VM is currently stopped. Start VM to restore PCF Dev.")
+	return nil
+}
@@ -1,6 +1,7 @@
 package vm_test
 
 import (
+	"context"
 	"errors"
 	"os"
 	"time"
@@ -51,6 +52,7 @@ var _ = Describe("Stopped", func() {
 			SSHClient: mockSSH,
 			Builder:   mockBuilder,
 			Config: &config.Config{
+				SSHTimeout:     7 * time.Minute,
 				PrivateKeyPath: "some-private-key-path",
 			},
 		}
@@ -63,7 +65,7 @@ var _ = Describe("Stopped", func() {
 	Describe("Stop", func() {
 		It("should say a message", func() {
 			mockUI.EXPECT().Say("PCF Dev is stopped.")
-			stoppedVM.Stop()
+			stoppedVM.Stop(context.Background())
 		})
 	})
 
@@ -76,6 +78,14 @@ var _ = Describe("Stopped", func() {
 			})
 		})
 
+		Context("when a network mode is passed", func() {
+			It("should return an error", func() {
+				Expect(stoppedVM.VerifyStartOpts(&vm.StartOpts{
+					NetworkMode: "bridged",
+				})).To(MatchError("the --network-mode flag cannot be used if the VM has already been created"))
+			})
+		})
+
 		Context("when desired domain is passed", func() {
 			It("should return an error", func() {
 				Expect(stoppedVM.VerifyStartOpts(&vm.StartOpts{
@@ -168,215 +178,215 @@ var _ = Describe("Stopped", func() {
 		})
 
 		allowHappyPathInteractions := func() {
-			mockSSH.EXPECT().RunSSHCommand(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
-			mockSSH.EXPECT().GetSSHOutput(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			mockSSH.EXPECT().RunSSHCommand(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			mockSSH.EXPECT().GetSSHOutput(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 			mockUI.EXPECT().Say(gomock.Any()).AnyTimes()
-			mockVBox.EXPECT().StartVM(gomock.Any()).AnyTimes()
-			mockBuilder.EXPECT().VM(gomock.Any()).AnyTimes().Return(mockUnprovisioned, nil)
+			mockVBox.EXPECT().StartVM(gomock.Any(), gomock.Any()).AnyTimes()
+			mockBuilder.EXPECT().VM(gomock.Any(), gomock.Any()).AnyTimes().Return(mockUnprovisioned, nil)
 			mockFS.EXPECT().Read(gomock.Any()).AnyTimes().Return([]byte("some-private-key"), nil)
-			mockUnprovisioned.EXPECT().Provision(gomock.Any()).AnyTimes()
+			mockUnprovisioned.EXPECT().Provision(gomock.Any(), gomock.Any()).AnyTimes()
 		}
 
 		Context("when 'none' services are specified", func() {
 			It("should start vm with no extra services", func() {
-				mockSSH.EXPECT().RunSSHCommand("echo "+
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), "echo "+
 					`'{"domain":"some-domain","ip":"some-ip","services":"","registries":[],"provider":"some-provider"}' | sudo tee /var/pcfdev/provision-options.json >/dev/null`,
-					addresses, []byte("some-private-key"), 5*time.Minute, os.Stdout, os.Stderr)
-				mockUnprovisioned.EXPECT().Provision(&vm.StartOpts{Services: "none"})
+					addresses, []byte("some-private-key"), 7*time.Minute, os.Stdout, os.Stderr)
+				mockUnprovisioned.EXPECT().Provision(gomock.Any(), &vm.StartOpts{Services: "none"})
 				allowHappyPathInteractions()
 
-				stoppedVM.Start(&vm.StartOpts{Services: "none"})
+				stoppedVM.Start(context.Background(), &vm.StartOpts{Services: "none"})
 			})
 		})
 
 		Context("when 'all' services are specified", func() {
 			It("should start the vm with services", func() {
-				mockSSH.EXPECT().RunSSHCommand("echo "+
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), "echo "+
 					`'{"domain":"some-domain","ip":"some-ip","services":"rabbitmq,redis,spring-cloud-services","registries":[],"provider":"some-provider"}' | sudo tee /var/pcfdev/provision-options.json >/dev/null`,
-					addresses, []byte("some-private-key"), 5*time.Minute, os.Stdout, os.Stderr)
-				mockUnprovisioned.EXPECT().Provision(&vm.StartOpts{Services: "all"})
+					addresses, []byte("some-private-key"), 7*time.Minute, os.Stdout, os.Stderr)
+				mockUnprovisioned.EXPECT().Provision(gomock.Any(), &vm.StartOpts{Services: "all"})
 				allowHappyPathInteractions()
 
-				stoppedVM.Start(&vm.StartOpts{Services: "all"})
+				stoppedVM.Start(context.Background(), &vm.StartOpts{Services: "all"})
 			})
 		})
 
 		Context("when 'default' services are specified", func() {
 			It("should start the vm with rabbitmq and redis", func() {
-				mockSSH.EXPECT().RunSSHCommand("echo "+
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), "echo "+
 					`'{"domain":"some-domain","ip":"some-ip","services":"rabbitmq,redis","registries":[],"provider":"some-provider"}' | sudo tee /var/pcfdev/provision-options.json >/dev/null`,
-					addresses, []byte("some-private-key"), 5*time.Minute, os.Stdout, os.Stderr)
-				mockUnprovisioned.EXPECT().Provision(&vm.StartOpts{Services: "default"})
+					addresses, []byte("some-private-key"), 7*time.Minute, os.Stdout, os.Stderr)
+				mockUnprovisioned.EXPECT().Provision(gomock.Any(), &vm.StartOpts{Services: "default"})
 				allowHappyPathInteractions()
 
-				stoppedVM.Start(&vm.StartOpts{Services: "default"})
+				stoppedVM.Start(context.Background(), &vm.StartOpts{Services: "default"})
 			})
 		})
 
 		Context("when 'spring-cloud-services' services are specified", func() {
 			It("should start the vm with spring-cloud-services and rabbitmq", func() {
-				mockSSH.EXPECT().RunSSHCommand("echo "+
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), "echo "+
 					`'{"domain":"some-domain","ip":"some-ip","services":"rabbitmq,spring-cloud-services","registries":[],"provider":"some-provider"}' | sudo tee /var/pcfdev/provision-options.json >/dev/null`,
-					addresses, []byte("some-private-key"), 5*time.Minute, os.Stdout, os.Stderr)
-				mockUnprovisioned.EXPECT().Provision(&vm.StartOpts{Services: "spring-cloud-services"})
+					addresses, []byte("some-private-key"), 7*time.Minute, os.Stdout, os.Stderr)
+				mockUnprovisioned.EXPECT().Provision(gomock.Any(), &vm.StartOpts{Services: "spring-cloud-services"})
 				allowHappyPathInteractions()
 
-				stoppedVM.Start(&vm.StartOpts{Services: "spring-cloud-services"})
+				stoppedVM.Start(context.Background(), &vm.StartOpts{Services: "spring-cloud-services"})
 			})
 		})
 
 		Context("when 'scs' is specified", func() {
 			It("should start the vm with spring-cloud-services and rabbitmq", func() {
-				mockSSH.EXPECT().RunSSHCommand("echo "+
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), "echo "+
 					`'{"domain":"some-domain","ip":"some-ip","services":"rabbitmq,spring-cloud-services","registries":[],"provider":"some-provider"}' | sudo tee /var/pcfdev/provision-options.json >/dev/null`,
-					addresses, []byte("some-private-key"), 5*time.Minute, os.Stdout, os.Stderr)
-				mockUnprovisioned.EXPECT().Provision(&vm.StartOpts{Services: "scs"})
+					addresses, []byte("some-private-key"), 7*time.Minute, os.Stdout, os.Stderr)
+				mockUnprovisioned.EXPECT().Provision(gomock.Any(), &vm.StartOpts{Services: "scs"})
 				allowHappyPathInteractions()
 
-				stoppedVM.Start(&vm.StartOpts{Services: "scs"})
+				stoppedVM.Start(context.Background(), &vm.StartOpts{Services: "scs"})
 			})
 		})
 
 		Context("when 'rabbitmq' services are specified", func() {
 			It("should start the vm with rabbitmq", func() {
-				mockSSH.EXPECT().RunSSHCommand("echo "+
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), "echo "+
 					`'{"domain":"some-domain","ip":"some-ip","services":"rabbitmq","registries":[],"provider":"some-provider"}' | sudo tee /var/pcfdev/provision-options.json >/dev/null`,
-					addresses, []byte("some-private-key"), 5*time.Minute, os.Stdout, os.Stderr)
-				mockUnprovisioned.EXPECT().Provision(&vm.StartOpts{Services: "rabbitmq"})
+					addresses, []byte("some-private-key"), 7*time.Minute, os.Stdout, os.Stderr)
+				mockUnprovisioned.EXPECT().Provision(gomock.Any(), &vm.StartOpts{Services: "rabbitmq"})
 				allowHappyPathInteractions()
 
-				stoppedVM.Start(&vm.StartOpts{Services: "rabbitmq"})
+				stoppedVM.Start(context.Background(), &vm.StartOpts{Services: "rabbitmq"})
 			})
 		})
 
 		Context("when 'redis' services are specified", func() {
 			It("should start the vm with redis", func() {
-				mockSSH.EXPECT().RunSSHCommand("echo "+
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), "echo "+
 					`'{"domain":"some-domain","ip":"some-ip","services":"redis","registries":[],"provider":"some-provider"}' | sudo tee /var/pcfdev/provision-options.json >/dev/null`,
-					addresses, []byte("some-private-key"), 5*time.Minute, os.Stdout, os.Stderr)
-				mockUnprovisioned.EXPECT().Provision(&vm.StartOpts{Services: "redis"})
+					addresses, []byte("some-private-key"), 7*time.Minute, os.Stdout, os.Stderr)
+				mockUnprovisioned.EXPECT().Provision(gomock.Any(), &vm.StartOpts{Services: "redis"})
 				allowHappyPathInteractions()
 
-				stoppedVM.Start(&vm.StartOpts{Services: "redis"})
+				stoppedVM.Start(context.Background(), &vm.StartOpts{Services: "redis"})
 			})
 		})
 
 		Context("when 'mysql' services are specified", func() {
 			It("should start the vm with no extra services", func() {
-				mockSSH.EXPECT().RunSSHCommand("echo "+
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), "echo "+
 					`'{"domain":"some-domain","ip":"some-ip","services":"","registries":[],"provider":"some-provider"}' | sudo tee /var/pcfdev/provision-options.json >/dev/null`,
-					addresses, []byte("some-private-key"), 5*time.Minute, os.Stdout, os.Stderr)
-				mockUnprovisioned.EXPECT().Provision(&vm.StartOpts{Services: "mysql"})
+					addresses, []byte("some-private-key"), 7*time.Minute, os.Stdout, os.Stderr)
+				mockUnprovisioned.EXPECT().Provision(gomock.Any(), &vm.StartOpts{Services: "mysql"})
 				allowHappyPathInteractions()
 
-				stoppedVM.Start(&vm.StartOpts{Services: "mysql"})
+				stoppedVM.Start(context.Background(), &vm.StartOpts{Services: "mysql"})
 			})
 		})
 
 		Context("when duplicate services are specified", func() {
 			It("should start the vm without duplicates services", func() {
-				mockSSH.EXPECT().RunSSHCommand("echo "+
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), "echo "+
 					`'{"domain":"some-domain","ip":"some-ip","services":"rabbitmq,redis,spring-cloud-services","registries":[],"provider":"some-provider"}' | sudo tee /var/pcfdev/provision-options.json >/dev/null`,
-					addresses, []byte("some-private-key"), 5*time.Minute, os.Stdout, os.Stderr)
-				mockUnprovisioned.EXPECT().Provision(&vm.StartOpts{Services: "default,spring-cloud-services,scs"})
+					addresses, []byte("some-private-key"), 7*time.Minute, os.Stdout, os.Stderr)
+				mockUnprovisioned.EXPECT().Provision(gomock.Any(), &vm.StartOpts{Services: "default,spring-cloud-services,scs"})
 				allowHappyPathInteractions()
 
-				stoppedVM.Start(&vm.StartOpts{Services: "default,spring-cloud-services,scs"})
+				stoppedVM.Start(context.Background(), &vm.StartOpts{Services: "default,spring-cloud-services,scs"})
 			})
 		})
 
 		Context("when '' services are specified", func() {
 			It("should start the vm with default services", func() {
-				mockSSH.EXPECT().RunSSHCommand("echo "+
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), "echo "+
 					`'{"domain":"some-domain","ip":"some-ip","services":"rabbitmq,redis","registries":[],"provider":"some-provider"}' | sudo tee /var/pcfdev/provision-options.json >/dev/null`,
-					addresses, []byte("some-private-key"), 5*time.Minute, os.Stdout, os.Stderr)
-				mockUnprovisioned.EXPECT().Provision(&vm.StartOpts{})
+					addresses, []byte("some-private-key"), 7*time.Minute, os.Stdout, os.Stderr)
+				mockUnprovisioned.EXPECT().Provision(gomock.Any(), &vm.StartOpts{})
 				allowHappyPathInteractions()
 
-				stoppedVM.Start(&vm.StartOpts{})
+				stoppedVM.Start(context.Background(), &vm.StartOpts{})
 			})
 		})
 
 		Context("when ip is specified", func() {
 			It("should start the vm with the custom ip", func() {
-				mockSSH.EXPECT().RunSSHCommand("echo "+
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), "echo "+
 					`'{"domain":"some-domain","ip":"some-custom-ip","services":"rabbitmq,redis","registries":[],"provider":"some-provider"}' | sudo tee /var/pcfdev/provision-options.json >/dev/null`,
-					addresses, []byte("some-private-key"), 5*time.Minute, os.Stdout, os.Stderr)
-				mockUnprovisioned.EXPECT().Provision(&vm.StartOpts{IP: "some-custom-ip"})
+					addresses, []byte("some-private-key"), 7*time.Minute, os.Stdout, os.Stderr)
+				mockUnprovisioned.EXPECT().Provision(gomock.Any(), &vm.StartOpts{IP: "some-custom-ip"})
 				allowHappyPathInteractions()
 
-				stoppedVM.Start(&vm.StartOpts{IP: "some-custom-ip"})
+				stoppedVM.Start(context.Background(), &vm.StartOpts{IP: "some-custom-ip"})
 			})
 		})
 
 		Context("when domain is specified", func() {
 			It("should start the vm with the custom domain", func() {
-				mockSSH.EXPECT().RunSSHCommand("echo "+
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), "echo "+
 					`'{"domain":"some-custom-domain","ip":"some-ip","services":"rabbitmq,redis","registries":[],"provider":"some-provider"}' | sudo tee /var/pcfdev/provision-options.json >/dev/null`,
-					addresses, []byte("some-private-key"), 5*time.Minute, os.Stdout, os.Stderr)
-				mockUnprovisioned.EXPECT().Provision(&vm.StartOpts{Domain: "some-custom-domain"})
+					addresses, []byte("some-private-key"), 7*time.Minute, os.Stdout, os.Stderr)
+				mockUnprovisioned.EXPECT().Provision(gomock.Any(), &vm.StartOpts{Domain: "some-custom-domain"})
 				allowHappyPathInteractions()
 
-				stoppedVM.Start(&vm.StartOpts{Domain: "some-custom-domain"})
+				stoppedVM.Start(context.Background(), &vm.StartOpts{Domain: "some-custom-domain"})
 			})
 		})
 
 		Context("when docker registries are specified", func() {
 			It("should start the vm with the registries accessible", func() {
-				mockSSH.EXPECT().RunSSHCommand("echo "+
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), "echo "+
 					`'{"domain":"some-domain","ip":"some-ip","services":"rabbitmq,redis","registries":["some-private-registry","some-other-private-registry"],"provider":"some-provider"}' | sudo tee /var/pcfdev/provision-options.json >/dev/null`,
-					addresses, []byte("some-private-key"), 5*time.Minute, os.Stdout, os.Stderr)
-				mockUnprovisioned.EXPECT().Provision(&vm.StartOpts{Registries: "some-private-registry,some-other-private-registry"})
+					addresses, []byte("some-private-key"), 7*time.Minute, os.Stdout, os.Stderr)
+				mockUnprovisioned.EXPECT().Provision(gomock.Any(), &vm.StartOpts{Registries: "some-private-registry,some-other-private-registry"})
 				allowHappyPathInteractions()
 
-				stoppedVM.Start(&vm.StartOpts{Registries: "some-private-registry,some-other-private-registry"})
+				stoppedVM.Start(context.Background(), &vm.StartOpts{Registries: "some-private-registry,some-other-private-registry"})
 			})
 		})
 
 		Context("when the master password is specified", func() {
 			It("should start the vm with the master password", func() {
-				mockSSH.EXPECT().RunSSHCommand("echo "+
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), "echo "+
 					`'{"domain":"some-domain","ip":"some-ip","services":"rabbitmq,redis","registries":[],"provider":"some-provider"}' | sudo tee /var/pcfdev/provision-options.json >/dev/null`,
-					addresses, []byte("some-private-key"), 5*time.Minute, os.Stdout, os.Stderr)
-				mockUnprovisioned.EXPECT().Provision(&vm.StartOpts{MasterPassword: "some-master-password"})
+					addresses, []byte("some-private-key"), 7*time.Minute, os.Stdout, os.Stderr)
+				mockUnprovisioned.EXPECT().Provision(gomock.Any(), &vm.StartOpts{MasterPassword: "some-master-password"})
 				allowHappyPathInteractions()
 
-				stoppedVM.Start(&vm.StartOpts{MasterPassword: "some-master-password"})
+				stoppedVM.Start(context.Background(), &vm.StartOpts{MasterPassword: "some-master-password"})
 			})
 		})
 
 		Context("when the provision-options.json already exists in the VM", func() {
 			It("should not overwrite the services or registries", func() {
-				mockSSH.EXPECT().GetSSHOutput("if [[ -f /var/pcfdev/provision-options.json ]]; then cat /var/pcfdev/provision-options.json; fi",
-					addresses, []byte("some-private-key"), 5*time.Minute).Return(`{"services":"some-existing-service", "registries":["some-existing-registry"]}`, nil)
-				mockSSH.EXPECT().RunSSHCommand("echo "+
+				mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "if [[ -f /var/pcfdev/provision-options.json ]]; then cat /var/pcfdev/provision-options.json; fi",
+					addresses, []byte("some-private-key"), 7*time.Minute).Return(`{"services":"some-existing-service", "registries":["some-existing-registry"]}`, nil)
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), "echo "+
 					`'{"domain":"some-domain","ip":"some-ip","services":"some-existing-service","registries":["some-existing-registry"],"provider":"some-provider"}' | sudo tee /var/pcfdev/provision-options.json >/dev/null`,
-					addresses, []byte("some-private-key"), 5*time.Minute, os.Stdout, os.Stderr)
-				mockUnprovisioned.EXPECT().Provision(&vm.StartOpts{})
+					addresses, []byte("some-private-key"), 7*time.Minute, os.Stdout, os.Stderr)
+				mockUnprovisioned.EXPECT().Provision(gomock.Any(), &vm.StartOpts{})
 				allowHappyPathInteractions()
 
-				stoppedVM.Start(&vm.StartOpts{})
+				stoppedVM.Start(context.Background(), &vm.StartOpts{})
 			})
 		})
 
 		Context("when '-n' (no-provision) flag is passed in", func() {
 			It("should not provision the vm", func() {
-				mockSSH.EXPECT().RunSSHCommand("echo "+
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), "echo "+
 					`'{"domain":"some-domain","ip":"some-ip","services":"rabbitmq,redis","registries":[],"provider":"some-provider"}' | sudo tee /var/pcfdev/provision-options.json >/dev/null`,
-					addresses, []byte("some-private-key"), 5*time.Minute, os.Stdout, os.Stderr)
+					addresses, []byte("some-private-key"), 7*time.Minute, os.Stdout, os.Stderr)
 				mockUI.EXPECT().Say("VM will not be provisioned because '-n' (no-provision) flag was specified.")
 				allowHappyPathInteractions()
 
-				stoppedVM.Start(&vm.StartOpts{NoProvision: true})
+				stoppedVM.Start(context.Background(), &vm.StartOpts{NoProvision: true})
 			})
 		})
 
 		Context("when starting the vm fails", func() {
 			It("should return an error", func() {
-				mockVBox.EXPECT().StartVM(stoppedVM.VMConfig).Return(errors.New("some-error"))
+				mockVBox.EXPECT().StartVM(gomock.Any(), stoppedVM.VMConfig).Return(errors.New("some-error"))
 				allowHappyPathInteractions()
 
-				Expect(stoppedVM.Start(&vm.StartOpts{})).To(MatchError("failed to start VM: some-error"))
+				Expect(stoppedVM.Start(context.Background(), &vm.StartOpts{})).To(MatchError("failed to start VM: some-error"))
 			})
 		})
 
@@ -385,54 +395,54 @@ var _ = Describe("Stopped", func() {
 				mockFS.EXPECT().Read("some-private-key-path").Return(nil, errors.New("some-error"))
 				allowHappyPathInteractions()
 
-				Expect(stoppedVM.Start(&vm.StartOpts{})).To(MatchError("failed to start VM: some-error"))
+				Expect(stoppedVM.Start(context.Background(), &vm.StartOpts{})).To(MatchError("failed to start VM: some-error"))
 			})
 		})
 
 		Context("when reading provision-options.json fails", func() {
 			It("returns an error", func() {
-				mockSSH.EXPECT().GetSSHOutput("if [[ -f /var/pcfdev/provision-options.json ]]; then cat /var/pcfdev/provision-options.json; fi",
-					addresses, []byte("some-private-key"), 5*time.Minute).Return("", errors.New("some-error"))
+				mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "if [[ -f /var/pcfdev/provision-options.json ]]; then cat /var/pcfdev/provision-options.json; fi",
+					addresses, []byte("some-private-key"), 7*time.Minute).Return("", errors.New("some-error"))
 				allowHappyPathInteractions()
 
-				Expect(stoppedVM.Start(&vm.StartOpts{})).To(MatchError("failed to start VM: some-error"))
+				Expect(stoppedVM.Start(context.Background(), &vm.StartOpts{})).To(MatchError("failed to start VM: some-error"))
 			})
 		})
 
 		Context("when unmarshaling provision-options.json fails", func() {
 			It("returns an error", func() {
-				mockSSH.EXPECT().GetSSHOutput("if [[ -f /var/pcfdev/provision-options.json ]]; then cat /var/pcfdev/provision-options.json; fi",
-					addresses, []byte("some-private-key"), 5*time.Minute).Return("some-invalid-json", nil)
+				mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "if [[ -f /var/pcfdev/provision-options.json ]]; then cat /var/pcfdev/provision-options.json; fi",
+					addresses, []byte("some-private-key"), 7*time.Minute).Return("some-invalid-json", nil)
 				allowHappyPathInteractions()
 
-				Expect(stoppedVM.Start(&vm.StartOpts{})).To(MatchError(HavePrefix("failed to start VM: ")))
+				Expect(stoppedVM.Start(context.Background(), &vm.StartOpts{})).To(MatchError(HavePrefix("failed to start VM: ")))
 			})
 		})
 
 		Context("when SSHing provisions into the vm fails", func() {
 			It("should return an error", func() {
-				mockSSH.EXPECT().RunSSHCommand("echo "+
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(), "echo "+
 					`'{"domain":"some-domain","ip":"some-ip","services":"rabbitmq,redis","registries":[],"provider":"some-provider"}' | sudo tee /var/pcfdev/provision-options.json >/dev/null`,
-					addresses, []byte("some-private-key"), 5*time.Minute, os.Stdout, os.Stderr).Return(errors.New("some-error"))
+					addresses, []byte("some-private-key"), 7*time.Minute, os.Stdout, os.Stderr).Return(errors.New("some-error"))
 				allowHappyPathInteractions()
 
-				Expect(stoppedVM.Start(&vm.StartOpts{})).To(MatchError("failed to start VM: some-error"))
+				Expect(stoppedVM.Start(context.Background(), &vm.StartOpts{})).To(MatchError("failed to start VM: some-error"))
 			})
 		})
 
 		Context("when retrieving the unprovisioned vm fails", func() {
 			It("should return an error", func() {
-				mockBuilder.EXPECT().VM("some-vm").Return(nil, errors.New("some-error"))
+				mockBuilder.EXPECT().VM(gomock.Any(), "some-vm").Return(nil, errors.New("some-error"))
 				allowHappyPathInteractions()
-				Expect(stoppedVM.Start(&vm.StartOpts{})).To(MatchError("failed to start VM: some-error"))
+				Expect(stoppedVM.Start(context.Background(), &vm.StartOpts{})).To(MatchError("failed to start VM: some-error"))
 			})
 		})
 
 		Context("when provisioning the unprovisioned vm fails", func() {
 			It("should return an error", func() {
-				mockUnprovisioned.EXPECT().Provision(&vm.StartOpts{}).Return(errors.New("some-error"))
+				mockUnprovisioned.EXPECT().Provision(gomock.Any(), &vm.StartOpts{}).Return(errors.New("some-error"))
 				allowHappyPathInteractions()
-				Expect(stoppedVM.Start(&vm.StartOpts{})).To(MatchError("some-error"))
+				Expect(stoppedVM.Start(context.Background(), &vm.StartOpts{})).To(MatchError("some-error"))
 			})
 		})
 	})
@@ -447,7 +457,7 @@ var _ = Describe("Stopped", func() {
 		It("should say a message", func() {
 			mockUI.EXPECT().Say("Your VM is currently stopped and cannot be suspended.")
 
-			Expect(stoppedVM.Suspend()).To(Succeed())
+			Expect(stoppedVM.Suspend(context.Background())).To(Succeed())
 		})
 	})
 
@@ -455,21 +465,21 @@ var _ = Describe("Stopped", func() {
 		It("should say a message", func() {
 			mockUI.EXPECT().Say("Your VM is currently stopped. Only a suspended VM can be resumed.")
 
-			Expect(stoppedVM.Resume()).To(Succeed())
+			Expect(stoppedVM.Resume(context.Background())).To(Succeed())
 		})
 	})
 
 	Describe("GetDebugLogs", func() {
 		It("should say a message", func() {
 			mockUI.EXPECT().Say("Your VM is currently stopped. Start VM to retrieve debug logs.")
-			Expect(stoppedVM.GetDebugLogs()).To(Succeed())
+			Expect(stoppedVM.GetDebugLogs(context.Background())).To(Succeed())
 		})
 	})
 
 	Describe("Trust", func() {
 		It("should say a message", func() {
 			mockUI.EXPECT().Say("Your VM is currently stopped. Start VM to trust VM certificates.")
-			Expect(stoppedVM.Trust(&vm.StartOpts{})).To(Succeed())
+			Expect(stoppedVM.Trust(context.Background(), &vm.StartOpts{})).To(Succeed())
 		})
 	})
 
@@ -483,7 +493,21 @@ var _ = Describe("Stopped", func() {
 	Describe("SSH", func() {
 		It("should say a message", func() {
 			mockUI.EXPECT().Say("Your VM is currently stopped. Start VM to SSH to PCF Dev.")
-			Expect(stoppedVM.SSH()).To(Succeed())
+			Expect(stoppedVM.SSH(context.Background(), &ssh.SessionOptions{})).To(Succeed())
+		})
+	})
+
+	Describe("Backup", func() {
+		It("should say a message", func() {
+			mockUI.EXPECT().Say("Your VM is currently stopped. Start VM to back up PCF Dev.")
+			Expect(stoppedVM.Backup(context.Background(), "some-archive-path")).To(Succeed())
+		})
+	})
+
+	Describe("Restore", func() {
+		It("should say a message", func() {
+			mockUI.EXPECT().Say("Your VM is currently stopped. Start VM to restore PCF Dev.")
+			Expect(stoppedVM.Restore(context.Background(), "some-archive-path")).To(Succeed())
 		})
 	})
 })
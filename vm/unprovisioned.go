@@ -1,15 +1,16 @@
 package vm
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"strings"
-	"time"
 
 	"github.com/docker/docker/pkg/term"
 	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/guest"
 	"github.com/pivotal-cf/pcfdev-cli/ssh"
 )
 
@@ -25,9 +26,9 @@ type Unprovisioned struct {
 	Client     Client
 }
 
-func (u *Unprovisioned) Stop() error {
+func (u *Unprovisioned) Stop(ctx context.Context) error {
 	u.UI.Say("Stopping VM...")
-	if err := u.VBox.StopVM(u.VMConfig); err != nil {
+	if err := u.VBox.StopVM(ctx, u.VMConfig); err != nil {
 		return err
 	}
 	u.UI.Say("PCF Dev is now stopped.")
@@ -38,7 +39,7 @@ func (u *Unprovisioned) VerifyStartOpts(opts *StartOpts) error {
 	return u.err()
 }
 
-func (u *Unprovisioned) Start(opts *StartOpts) error {
+func (u *Unprovisioned) Start(ctx context.Context, opts *StartOpts) error {
 	return u.err()
 }
 
@@ -46,14 +47,14 @@ func (u *Unprovisioned) Status() string {
 	return u.err().Error()
 }
 
-func (u *Unprovisioned) Provision(opts *StartOpts) error {
+func (u *Unprovisioned) Provision(ctx context.Context, opts *StartOpts) error {
 	if opts.MasterPassword != "" {
 		privateKey, err := u.FS.Read(u.Config.PrivateKeyPath)
 		if err != nil {
 			return err
 		}
 
-		if err := u.Client.ReplaceSecrets(u.VMConfig.IP, opts.MasterPassword, privateKey); err != nil {
+		if err := u.Client.ReplaceSecrets(ctx, ssh.SSHAddresses(u.VMConfig), opts.MasterPassword, privateKey); err != nil {
 			return err
 		}
 	}
@@ -63,22 +64,13 @@ func (u *Unprovisioned) Provision(opts *StartOpts) error {
 		return err
 	}
 
-	addresses := []ssh.SSHAddress{
-		{
-			IP:   "127.0.0.1",
-			Port: u.VMConfig.SSHPort,
-		},
-		{
-			IP:   u.VMConfig.IP,
-			Port: "22",
-		},
-	}
+	addresses := ssh.SSHAddresses(u.VMConfig)
 
-	if err := u.SSHClient.RunSSHCommand("if [ -e /var/pcfdev/provision-options.json ]; then exit 0; else exit 1; fi", addresses, privateKeyBytes, 30*time.Second, os.Stdout, os.Stderr); err != nil {
+	if err := u.SSHClient.RunSSHCommand(ctx, "if [ -e /var/pcfdev/provision-options.json ]; then exit 0; else exit 1; fi", addresses, privateKeyBytes, u.Config.SSHTimeout, os.Stdout, os.Stderr); err != nil {
 		return &ProvisionVMError{errors.New("missing provision configuration")}
 	}
 
-	data, err := u.SSHClient.GetSSHOutput("cat /var/pcfdev/provision-options.json", addresses, privateKeyBytes, 30*time.Second)
+	data, err := u.SSHClient.GetSSHOutput(ctx, "cat /var/pcfdev/provision-options.json", addresses, privateKeyBytes, u.Config.SSHTimeout)
 	if err != nil {
 		return &ProvisionVMError{err}
 	}
@@ -89,8 +81,15 @@ func (u *Unprovisioned) Provision(opts *StartOpts) error {
 	}
 
 	u.UI.Say("Provisioning VM...")
-	provisionCommand := fmt.Sprintf(`sudo -H /var/pcfdev/provision "%s" "%s" "%s" "%s" "%s"`, provisionConfig.Domain, provisionConfig.IP, provisionConfig.Services, strings.Join(provisionConfig.Registries, ","), provisionConfig.Provider)
-	if err := u.SSHClient.RunSSHCommand(provisionCommand, addresses, privateKeyBytes, 5*time.Minute, os.Stdout, os.Stderr); err != nil {
+	provisionCommand := guest.WithProxyCredentials(fmt.Sprintf(`/var/pcfdev/provision "%s" "%s" "%s" "%s" "%s"`, provisionConfig.Domain, provisionConfig.IP, provisionConfig.Services, strings.Join(provisionConfig.Registries, ","), provisionConfig.Provider))
+	// ProvisionTimeout bounds connecting to the VM to provision it, while
+	// ProvisionRunTimeout bounds the whole provision script.
+	provisionCtx, cancel := context.WithTimeout(ctx, u.Config.ProvisionRunTimeout)
+	defer cancel()
+	if err := u.SSHClient.RunSSHCommand(provisionCtx, provisionCommand, addresses, privateKeyBytes, u.Config.ProvisionTimeout, os.Stdout, os.Stderr); err != nil {
+		if ctx.Err() == nil && provisionCtx.Err() == context.DeadlineExceeded {
+			return &ProvisionVMError{fmt.Errorf("timed out after %s", u.Config.ProvisionRunTimeout)}
+		}
 		return &ProvisionVMError{err}
 	}
 
@@ -99,15 +98,15 @@ func (u *Unprovisioned) Provision(opts *StartOpts) error {
 	return nil
 }
 
-func (u *Unprovisioned) Suspend() error {
+func (u *Unprovisioned) Suspend(ctx context.Context) error {
 	return u.err()
 }
 
-func (u *Unprovisioned) Resume() error {
+func (u *Unprovisioned) Resume(ctx context.Context) error {
 	return u.err()
 }
 
-func (u *Unprovisioned) Trust(startOps *StartOpts) error {
+func (u *Unprovisioned) Trust(ctx context.Context, startOps *StartOpts) error {
 	return u.err()
 }
 
@@ -115,27 +114,32 @@ func (u *Unprovisioned) Target(autoTarget bool) error {
 	return u.err()
 }
 
-func (u *Unprovisioned) SSH() error {
+func (u *Unprovisioned) SSH(ctx context.Context, options *ssh.SessionOptions) error {
 	privateKeyBytes, err := u.FS.Read(u.Config.PrivateKeyPath)
 	if err != nil {
 		return err
 	}
 
-	addresses := []ssh.SSHAddress{
-		{IP: "127.0.0.1", Port: u.VMConfig.SSHPort},
-		{IP: u.VMConfig.IP, Port: "22"},
-	}
+	addresses := ssh.SSHAddresses(u.VMConfig)
 
 	stdin, stdout, stderr := term.StdStreams()
-	return u.SSHClient.StartSSHSession(addresses, privateKeyBytes, 5*time.Minute, stdin, stdout, stderr)
+	return u.SSHClient.StartSSHSession(ctx, addresses, privateKeyBytes, u.Config.SSHTimeout, options, stdin, stdout, stderr)
+}
+
+func (u *Unprovisioned) Backup(ctx context.Context, path string) error {
+	return u.err()
+}
+
+func (u *Unprovisioned) Restore(ctx context.Context, path string) error {
+	return u.err()
 }
 
 func (u *Unprovisioned) err() error {
 	return errors.New("PCF Dev is in an invalid state. Please run 'cf dev destroy' or 'cf dev stop'")
 }
 
-func (u *Unprovisioned) GetDebugLogs() error {
-	if err := u.LogFetcher.FetchLogs(); err != nil {
+func (u *Unprovisioned) GetDebugLogs(ctx context.Context) error {
+	if err := u.LogFetcher.FetchLogs(ctx); err != nil {
 		return &FetchLogsError{err}
 	}
 
@@ -1,7 +1,9 @@
 package vm_test
 
 import (
+	"context"
 	"errors"
+	"io"
 	"os"
 	"time"
 
@@ -48,7 +50,10 @@ var _ = Describe("Unprovisioned", func() {
 			HelpText:   mockHelpText,
 			Client:     mockClient,
 			Config: &conf.Config{
-				PrivateKeyPath: "some-private-key-path",
+				SSHTimeout:          7 * time.Minute,
+				ProvisionTimeout:    5 * time.Minute,
+				ProvisionRunTimeout: 10 * time.Minute,
+				PrivateKeyPath:      "some-private-key-path",
 			},
 			VMConfig: &conf.VMConfig{
 				Name:    "some-vm",
@@ -67,17 +72,17 @@ var _ = Describe("Unprovisioned", func() {
 		It("should stop the VM", func() {
 			gomock.InOrder(
 				mockUI.EXPECT().Say("Stopping VM..."),
-				mockVBox.EXPECT().StopVM(unprovisioned.VMConfig),
+				mockVBox.EXPECT().StopVM(gomock.Any(), unprovisioned.VMConfig),
 				mockUI.EXPECT().Say("PCF Dev is now stopped."),
 			)
 
-			Expect(unprovisioned.Stop()).To(Succeed())
+			Expect(unprovisioned.Stop(context.Background())).To(Succeed())
 		})
 
 		It("should bubble the error up if stopping fails", func() {
 			mockUI.EXPECT().Say("Stopping VM...")
-			mockVBox.EXPECT().StopVM(unprovisioned.VMConfig).Return(errors.New("some error"))
-			Expect(unprovisioned.Stop()).To(MatchError("some error"))
+			mockVBox.EXPECT().StopVM(gomock.Any(), unprovisioned.VMConfig).Return(errors.New("some error"))
+			Expect(unprovisioned.Stop(context.Background())).To(MatchError("some error"))
 		})
 	})
 
@@ -91,7 +96,7 @@ var _ = Describe("Unprovisioned", func() {
 
 	Describe("Start", func() {
 		It("should start vm", func() {
-			Expect(unprovisioned.Start(&vm.StartOpts{})).To(MatchError("PCF Dev is in an invalid state. Please run 'cf dev destroy' or 'cf dev stop'"))
+			Expect(unprovisioned.Start(context.Background(), &vm.StartOpts{})).To(MatchError("PCF Dev is in an invalid state. Please run 'cf dev destroy' or 'cf dev stop'"))
 		})
 	})
 
@@ -103,33 +108,54 @@ var _ = Describe("Unprovisioned", func() {
 			}
 			gomock.InOrder(
 				mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-				mockSSH.EXPECT().RunSSHCommand(
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(),
 					"if [ -e /var/pcfdev/provision-options.json ]; then exit 0; else exit 1; fi",
 					sshAddresses,
 					[]byte("some-private-key"),
-					30*time.Second,
+					7*time.Minute,
 					os.Stdout,
 					os.Stderr,
 				),
-				mockSSH.EXPECT().GetSSHOutput(
+				mockSSH.EXPECT().GetSSHOutput(gomock.Any(),
 					"cat /var/pcfdev/provision-options.json",
 					sshAddresses,
 					[]byte("some-private-key"),
-					30*time.Second,
+					7*time.Minute,
 				).Return(`{"domain":"some-domain","ip":"some-ip","services":"some-service,some-other-service","registries":["some-registry","some-other-registry"],"provider":"some-provider"}`, nil),
 				mockUI.EXPECT().Say("Provisioning VM..."),
-				mockSSH.EXPECT().RunSSHCommand(
-					`sudo -H /var/pcfdev/provision "some-domain" "some-ip" "some-service,some-other-service" "some-registry,some-other-registry" "some-provider"`,
+				mockSSH.EXPECT().RunSSHCommand(gomock.Any(),
+					`sudo -H sh -c 'if [ -e /var/pcfdev/proxy.env ]; then set -a; . /var/pcfdev/proxy.env; set +a; fi; /var/pcfdev/provision "some-domain" "some-ip" "some-service,some-other-service" "some-registry,some-other-registry" "some-provider"'`,
 					sshAddresses,
 					[]byte("some-private-key"),
 					5*time.Minute,
 					os.Stdout,
 					os.Stderr,
-				),
+				).Do(func(ctx context.Context, _ string, _ []ssh.SSHAddress, _ []byte, _ time.Duration, _ io.Writer, _ io.Writer) {
+					deadline, ok := ctx.Deadline()
+					Expect(ok).To(BeTrue())
+					Expect(deadline).To(BeTemporally("~", time.Now().Add(10*time.Minute), time.Minute))
+				}),
 				mockHelpText.EXPECT().Print("some-domain", false),
 			)
 
-			Expect(unprovisioned.Provision(&vm.StartOpts{})).To(Succeed())
+			Expect(unprovisioned.Provision(context.Background(), &vm.StartOpts{})).To(Succeed())
+		})
+
+		Context("when provisioning runs past the provision run timeout", func() {
+			It("should return an error", func() {
+				unprovisioned.Config.ProvisionRunTimeout = 10 * time.Millisecond
+				gomock.InOrder(
+					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
+					mockSSH.EXPECT().RunSSHCommand(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(`{"domain":"some-domain"}`, nil),
+					mockUI.EXPECT().Say("Provisioning VM..."),
+					mockSSH.EXPECT().RunSSHCommand(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), 5*time.Minute, gomock.Any(), gomock.Any()).Do(func(ctx context.Context, _ string, _ []ssh.SSHAddress, _ []byte, _ time.Duration, _ io.Writer, _ io.Writer) {
+						<-ctx.Done()
+					}).Return(errors.New("some-error")),
+				)
+
+				Expect(unprovisioned.Provision(context.Background(), &vm.StartOpts{})).To(MatchError("failed to provision VM: timed out after 10ms"))
+			})
 		})
 
 		Context("when the user passes in a master password", func() {
@@ -140,25 +166,25 @@ var _ = Describe("Unprovisioned", func() {
 				}
 				gomock.InOrder(
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-					mockClient.EXPECT().ReplaceSecrets("some-ip", "some-master-password", []byte("some-private-key")),
+					mockClient.EXPECT().ReplaceSecrets(gomock.Any(), []ssh.SSHAddress{{IP: "127.0.0.1", Port: "some-port"}, {IP: "some-ip", Port: "22"}}, "some-master-password", []byte("some-private-key")),
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-					mockSSH.EXPECT().RunSSHCommand(
+					mockSSH.EXPECT().RunSSHCommand(gomock.Any(),
 						"if [ -e /var/pcfdev/provision-options.json ]; then exit 0; else exit 1; fi",
 						sshAddresses,
 						[]byte("some-private-key"),
-						30*time.Second,
+						7*time.Minute,
 						os.Stdout,
 						os.Stderr,
 					),
-					mockSSH.EXPECT().GetSSHOutput(
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(),
 						"cat /var/pcfdev/provision-options.json",
 						sshAddresses,
 						[]byte("some-private-key"),
-						30*time.Second,
+						7*time.Minute,
 					).Return(`{"domain":"some-domain","ip":"some-ip","services":"some-service,some-other-service","registries":["some-registry","some-other-registry"],"provider":"some-provider"}`, nil),
 					mockUI.EXPECT().Say("Provisioning VM..."),
-					mockSSH.EXPECT().RunSSHCommand(
-						`sudo -H /var/pcfdev/provision "some-domain" "some-ip" "some-service,some-other-service" "some-registry,some-other-registry" "some-provider"`,
+					mockSSH.EXPECT().RunSSHCommand(gomock.Any(),
+						`sudo -H sh -c 'if [ -e /var/pcfdev/proxy.env ]; then set -a; . /var/pcfdev/proxy.env; set +a; fi; /var/pcfdev/provision "some-domain" "some-ip" "some-service,some-other-service" "some-registry,some-other-registry" "some-provider"'`,
 						sshAddresses,
 						[]byte("some-private-key"),
 						5*time.Minute,
@@ -168,7 +194,7 @@ var _ = Describe("Unprovisioned", func() {
 					mockHelpText.EXPECT().Print("some-domain", false),
 				)
 
-				Expect(unprovisioned.Provision(&vm.StartOpts{MasterPassword: "some-master-password"})).To(Succeed())
+				Expect(unprovisioned.Provision(context.Background(), &vm.StartOpts{MasterPassword: "some-master-password"})).To(Succeed())
 			})
 		})
 
@@ -176,10 +202,10 @@ var _ = Describe("Unprovisioned", func() {
 			It("should return the error", func() {
 				gomock.InOrder(
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-					mockClient.EXPECT().ReplaceSecrets("some-ip", "some-master-password", []byte("some-private-key")).Return(errors.New("some-error")),
+					mockClient.EXPECT().ReplaceSecrets(gomock.Any(), []ssh.SSHAddress{{IP: "127.0.0.1", Port: "some-port"}, {IP: "some-ip", Port: "22"}}, "some-master-password", []byte("some-private-key")).Return(errors.New("some-error")),
 				)
 
-				Expect(unprovisioned.Provision(&vm.StartOpts{MasterPassword: "some-master-password"})).To(MatchError("some-error"))
+				Expect(unprovisioned.Provision(context.Background(), &vm.StartOpts{MasterPassword: "some-master-password"})).To(MatchError("some-error"))
 			})
 		})
 
@@ -187,7 +213,7 @@ var _ = Describe("Unprovisioned", func() {
 			It("should return the error", func() {
 				mockFS.EXPECT().Read("some-private-key-path").Return(nil, errors.New("some-error"))
 
-				Expect(unprovisioned.Provision(&vm.StartOpts{MasterPassword: "some-master-password"})).To(MatchError("some-error"))
+				Expect(unprovisioned.Provision(context.Background(), &vm.StartOpts{MasterPassword: "some-master-password"})).To(MatchError("some-error"))
 			})
 		})
 
@@ -200,22 +226,22 @@ var _ = Describe("Unprovisioned", func() {
 				gomock.InOrder(
 
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-					mockSSH.EXPECT().RunSSHCommand("if [ -e /var/pcfdev/provision-options.json ]; then exit 0; else exit 1; fi",
+					mockSSH.EXPECT().RunSSHCommand(gomock.Any(), "if [ -e /var/pcfdev/provision-options.json ]; then exit 0; else exit 1; fi",
 						sshAddresses,
 						[]byte("some-private-key"),
-						30*time.Second,
+						7*time.Minute,
 						os.Stdout,
 						os.Stderr,
 					),
-					mockSSH.EXPECT().GetSSHOutput(
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(),
 						"cat /var/pcfdev/provision-options.json",
 						sshAddresses,
 						[]byte("some-private-key"),
-						30*time.Second,
+						7*time.Minute,
 					).Return(`{"domain":"some-domain","ip":"some-ip","services":"some-service,some-other-service","registries":["some-registry","some-other-registry"],"provider":"some-provider"}`, nil),
 					mockUI.EXPECT().Say("Provisioning VM..."),
-					mockSSH.EXPECT().RunSSHCommand(
-						`sudo -H /var/pcfdev/provision "some-domain" "some-ip" "some-service,some-other-service" "some-registry,some-other-registry" "some-provider"`,
+					mockSSH.EXPECT().RunSSHCommand(gomock.Any(),
+						`sudo -H sh -c 'if [ -e /var/pcfdev/proxy.env ]; then set -a; . /var/pcfdev/proxy.env; set +a; fi; /var/pcfdev/provision "some-domain" "some-ip" "some-service,some-other-service" "some-registry,some-other-registry" "some-provider"'`,
 						sshAddresses,
 						[]byte("some-private-key"),
 						5*time.Minute,
@@ -225,7 +251,7 @@ var _ = Describe("Unprovisioned", func() {
 					mockHelpText.EXPECT().Print("some-domain", true),
 				)
 
-				Expect(unprovisioned.Provision(&vm.StartOpts{Target: true})).To(Succeed())
+				Expect(unprovisioned.Provision(context.Background(), &vm.StartOpts{Target: true})).To(Succeed())
 			})
 		})
 
@@ -233,7 +259,7 @@ var _ = Describe("Unprovisioned", func() {
 			It("should return an error", func() {
 				mockFS.EXPECT().Read("some-private-key-path").Return(nil, errors.New("some-error"))
 
-				Expect(unprovisioned.Provision(&vm.StartOpts{})).To(MatchError("some-error"))
+				Expect(unprovisioned.Provision(context.Background(), &vm.StartOpts{})).To(MatchError("some-error"))
 			})
 		})
 
@@ -245,15 +271,15 @@ var _ = Describe("Unprovisioned", func() {
 				}
 				gomock.InOrder(
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-					mockSSH.EXPECT().RunSSHCommand("if [ -e /var/pcfdev/provision-options.json ]; then exit 0; else exit 1; fi",
+					mockSSH.EXPECT().RunSSHCommand(gomock.Any(), "if [ -e /var/pcfdev/provision-options.json ]; then exit 0; else exit 1; fi",
 						sshAddresses,
 						[]byte("some-private-key"),
-						30*time.Second,
+						7*time.Minute,
 						os.Stdout,
 						os.Stderr).Return(errors.New("some-error")),
 				)
 
-				Expect(unprovisioned.Provision(&vm.StartOpts{})).To(MatchError("failed to provision VM: missing provision configuration"))
+				Expect(unprovisioned.Provision(context.Background(), &vm.StartOpts{})).To(MatchError("failed to provision VM: missing provision configuration"))
 			})
 		})
 
@@ -265,16 +291,16 @@ var _ = Describe("Unprovisioned", func() {
 				}
 				gomock.InOrder(
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-					mockSSH.EXPECT().RunSSHCommand("if [ -e /var/pcfdev/provision-options.json ]; then exit 0; else exit 1; fi",
+					mockSSH.EXPECT().RunSSHCommand(gomock.Any(), "if [ -e /var/pcfdev/provision-options.json ]; then exit 0; else exit 1; fi",
 						sshAddresses,
 						[]byte("some-private-key"),
-						30*time.Second,
+						7*time.Minute,
 						os.Stdout,
 						os.Stderr),
-					mockSSH.EXPECT().GetSSHOutput("cat /var/pcfdev/provision-options.json", sshAddresses, []byte("some-private-key"), 30*time.Second).Return("", errors.New("some-error")),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "cat /var/pcfdev/provision-options.json", sshAddresses, []byte("some-private-key"), 7*time.Minute).Return("", errors.New("some-error")),
 				)
 
-				Expect(unprovisioned.Provision(&vm.StartOpts{})).To(MatchError("failed to provision VM: some-error"))
+				Expect(unprovisioned.Provision(context.Background(), &vm.StartOpts{})).To(MatchError("failed to provision VM: some-error"))
 			})
 		})
 
@@ -286,16 +312,16 @@ var _ = Describe("Unprovisioned", func() {
 				}
 				gomock.InOrder(
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-					mockSSH.EXPECT().RunSSHCommand("if [ -e /var/pcfdev/provision-options.json ]; then exit 0; else exit 1; fi",
+					mockSSH.EXPECT().RunSSHCommand(gomock.Any(), "if [ -e /var/pcfdev/provision-options.json ]; then exit 0; else exit 1; fi",
 						sshAddresses,
 						[]byte("some-private-key"),
-						30*time.Second,
+						7*time.Minute,
 						os.Stdout,
 						os.Stderr),
-					mockSSH.EXPECT().GetSSHOutput("cat /var/pcfdev/provision-options.json", sshAddresses, []byte("some-private-key"), 30*time.Second).Return("{some-bad-json}", nil),
+					mockSSH.EXPECT().GetSSHOutput(gomock.Any(), "cat /var/pcfdev/provision-options.json", sshAddresses, []byte("some-private-key"), 7*time.Minute).Return("{some-bad-json}", nil),
 				)
 
-				Expect(unprovisioned.Provision(&vm.StartOpts{})).To(MatchError(ContainSubstring(`failed to provision VM: invalid character 's'`)))
+				Expect(unprovisioned.Provision(context.Background(), &vm.StartOpts{})).To(MatchError(ContainSubstring(`failed to provision VM: invalid character 's'`)))
 			})
 		})
 	})
@@ -308,19 +334,19 @@ var _ = Describe("Unprovisioned", func() {
 
 	Describe("Suspend", func() {
 		It("should return an error", func() {
-			Expect(unprovisioned.Suspend()).To(MatchError("PCF Dev is in an invalid state. Please run 'cf dev destroy' or 'cf dev stop'"))
+			Expect(unprovisioned.Suspend(context.Background())).To(MatchError("PCF Dev is in an invalid state. Please run 'cf dev destroy' or 'cf dev stop'"))
 		})
 	})
 
 	Describe("Resume", func() {
 		It("should return an error", func() {
-			Expect(unprovisioned.Resume()).To(MatchError("PCF Dev is in an invalid state. Please run 'cf dev destroy' or 'cf dev stop'"))
+			Expect(unprovisioned.Resume(context.Background())).To(MatchError("PCF Dev is in an invalid state. Please run 'cf dev destroy' or 'cf dev stop'"))
 		})
 	})
 
 	Describe("Trust", func() {
 		It("should return an error", func() {
-			Expect(unprovisioned.Trust(&vm.StartOpts{})).To(MatchError("PCF Dev is in an invalid state. Please run 'cf dev destroy' or 'cf dev stop'"))
+			Expect(unprovisioned.Trust(context.Background(), &vm.StartOpts{})).To(MatchError("PCF Dev is in an invalid state. Please run 'cf dev destroy' or 'cf dev stop'"))
 		})
 	})
 
@@ -331,6 +357,12 @@ var _ = Describe("Unprovisioned", func() {
 	})
 
 	Describe("SSH", func() {
+		var opts *ssh.SessionOptions
+
+		BeforeEach(func() {
+			opts = &ssh.SessionOptions{Command: "some-command", TTY: true}
+		})
+
 		It("should execute ssh on the client", func() {
 			addresses := []ssh.SSHAddress{
 				{IP: "127.0.0.1", Port: "some-port"},
@@ -340,17 +372,17 @@ var _ = Describe("Unprovisioned", func() {
 
 			gomock.InOrder(
 				mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-				mockSSH.EXPECT().StartSSHSession(addresses, []byte("some-private-key"), 5*time.Minute, stdin, stdout, stderr),
+				mockSSH.EXPECT().StartSSHSession(gomock.Any(), addresses, []byte("some-private-key"), 7*time.Minute, opts, stdin, stdout, stderr),
 			)
 
-			Expect(unprovisioned.SSH()).To(Succeed())
+			Expect(unprovisioned.SSH(context.Background(), opts)).To(Succeed())
 		})
 
 		Context("when reading the private key fails", func() {
 			It("should return an error", func() {
 				mockFS.EXPECT().Read("some-private-key-path").Return(nil, errors.New("some-error"))
 
-				Expect(unprovisioned.SSH()).To(MatchError("some-error"))
+				Expect(unprovisioned.SSH(context.Background(), opts)).To(MatchError("some-error"))
 			})
 		})
 
@@ -364,28 +396,39 @@ var _ = Describe("Unprovisioned", func() {
 
 				gomock.InOrder(
 					mockFS.EXPECT().Read("some-private-key-path").Return([]byte("some-private-key"), nil),
-					mockSSH.EXPECT().StartSSHSession(addresses, []byte("some-private-key"), 5*time.Minute, stdin, stdout, stderr).Return(errors.New("some-error")),
+					mockSSH.EXPECT().StartSSHSession(gomock.Any(), addresses, []byte("some-private-key"), 7*time.Minute, opts, stdin, stdout, stderr).Return(errors.New("some-error")),
 				)
 
-				Expect(unprovisioned.SSH()).To(MatchError("some-error"))
+				Expect(unprovisioned.SSH(context.Background(), opts)).To(MatchError("some-error"))
 			})
 		})
 	})
 
 	Describe("GetDebugLogs", func() {
 		It("should succeed", func() {
-			mockLogFetcher.EXPECT().FetchLogs()
+			mockLogFetcher.EXPECT().FetchLogs(gomock.Any())
 
-			Expect(unprovisioned.GetDebugLogs()).To(Succeed())
+			Expect(unprovisioned.GetDebugLogs(context.Background())).To(Succeed())
 		})
 
 		Context("when fetching logs fails", func() {
 			It("should return the error", func() {
-				mockLogFetcher.EXPECT().FetchLogs().Return(errors.New("some-error"))
+				mockLogFetcher.EXPECT().FetchLogs(gomock.Any()).Return(errors.New("some-error"))
 
-				Expect(unprovisioned.GetDebugLogs()).To(MatchError("failed to retrieve logs: some-error"))
+				Expect(unprovisioned.GetDebugLogs(context.Background())).To(MatchError("failed to retrieve logs: some-error"))
 			})
 		})
 	})
 
+	Describe("Backup", func() {
+		It("should return an error", func() {
+			Expect(unprovisioned.Backup(context.Background(), "some-archive-path")).To(MatchError("PCF Dev is in an invalid state. Please run 'cf dev destroy' or 'cf dev stop'"))
+		})
+	})
+
+	Describe("Restore", func() {
+		It("should return an error", func() {
+			Expect(unprovisioned.Restore(context.Background(), "some-archive-path")).To(MatchError("PCF Dev is in an invalid state. Please run 'cf dev destroy' or 'cf dev stop'"))
+		})
+	})
 })
@@ -1,24 +1,28 @@
 package vm
 
 import (
+	"context"
 	"io"
 	"time"
 
 	"github.com/pivotal-cf/pcfdev-cli/config"
+	"github.com/pivotal-cf/pcfdev-cli/debug"
 	"github.com/pivotal-cf/pcfdev-cli/ssh"
 )
 
 //go:generate mockgen -package mocks -destination mocks/vbox.go github.com/pivotal-cf/pcfdev-cli/vm VBox
 type VBox interface {
-	StartVM(vmConfig *config.VMConfig) error
-	StopVM(vmConfig *config.VMConfig) error
+	StartVM(ctx context.Context, vmConfig *config.VMConfig) error
+	StopVM(ctx context.Context, vmConfig *config.VMConfig) error
 	ResumeSavedVM(vmConfig *config.VMConfig) error
 	ResumePausedVM(vmConfig *config.VMConfig) error
-	SuspendVM(vmConfig *config.VMConfig) error
+	SuspendVM(ctx context.Context, vmConfig *config.VMConfig) error
 	PowerOffVM(vmConfig *config.VMConfig) error
-	ImportVM(vmConfig *config.VMConfig) error
+	ImportVM(ctx context.Context, vmConfig *config.VMConfig) error
+	DestroyVM(ctx context.Context, vmConfig *config.VMConfig) error
 	VMStatus(vmName string) (state string, err error)
 	VMConfig(vmName string) (vmConfig *config.VMConfig, err error)
+	HostLogs(vmConfig *config.VMConfig) (logs []*debug.HostLog, err error)
 }
 
 //go:generate mockgen -package mocks -destination mocks/ui.go github.com/pivotal-cf/pcfdev-cli/vm UI
@@ -32,31 +36,34 @@ type UI interface {
 //go:generate mockgen -package mocks -destination mocks/ssh.go github.com/pivotal-cf/pcfdev-cli/vm SSH
 type SSH interface {
 	GenerateAddress() (host string, port string, err error)
-	StartSSHSession(addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration, stdin io.Reader, stdout io.Writer, stderr io.Writer) error
-	WaitForSSH(addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration) error
-	RunSSHCommand(command string, addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration, stdout io.Writer, stderr io.Writer) error
-	GetSSHOutput(command string, addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration) (combinedOutput string, err error)
+	StartSSHSession(ctx context.Context, addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration, options *ssh.SessionOptions, stdin io.Reader, stdout io.Writer, stderr io.Writer) error
+	WaitForSSH(ctx context.Context, addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration) error
+	RunSSHCommand(ctx context.Context, command string, addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration, stdout io.Writer, stderr io.Writer) error
+	RunSSHCommandWithStdin(ctx context.Context, command string, addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration, stdin io.Reader, stdout io.Writer, stderr io.Writer) error
+	GetSSHOutput(ctx context.Context, command string, addresses []ssh.SSHAddress, privateKey []byte, timeout time.Duration) (combinedOutput string, err error)
 }
 
 //go:generate mockgen -package mocks -destination mocks/vm.go github.com/pivotal-cf/pcfdev-cli/vm VM
 type VM interface {
-	Start(*StartOpts) error
-	Provision(*StartOpts) error
-	Stop() error
+	Start(context.Context, *StartOpts) error
+	Provision(context.Context, *StartOpts) error
+	Stop(context.Context) error
 	Status() string
-	Suspend() error
-	Resume() error
-	GetDebugLogs() error
-	Trust(*StartOpts) error
+	Suspend(context.Context) error
+	Resume(context.Context) error
+	GetDebugLogs(context.Context) error
+	Trust(context.Context, *StartOpts) error
 	Target(autoTarget bool) error
-	SSH() error
+	SSH(context.Context, *ssh.SessionOptions) error
+	Backup(ctx context.Context, path string) error
+	Restore(ctx context.Context, path string) error
 
 	VerifyStartOpts(*StartOpts) error
 }
 
 //go:generate mockgen -package mocks -destination mocks/builder.go github.com/pivotal-cf/pcfdev-cli/vm Builder
 type Builder interface {
-	VM(name string) (vm VM, err error)
+	VM(ctx context.Context, name string) (vm VM, err error)
 }
 
 //go:generate mockgen -package mocks -destination mocks/cert_store.go github.com/pivotal-cf/pcfdev-cli/vm CertStore
@@ -66,8 +73,8 @@ type CertStore interface {
 
 //go:generate mockgen -package mocks -destination mocks/client.go github.com/pivotal-cf/pcfdev-cli/vm Client
 type Client interface {
-	Status(host string, privateKey []byte) (string, error)
-	ReplaceSecrets(host, password string, privateKey []byte) error
+	Status(ctx context.Context, sshAddresses []ssh.SSHAddress, privateKey []byte) (string, error)
+	ReplaceSecrets(ctx context.Context, sshAddresses []ssh.SSHAddress, password string, privateKey []byte) error
 }
 
 //go:generate mockgen -package mocks -destination mocks/fs.go github.com/pivotal-cf/pcfdev-cli/vm FS
@@ -82,7 +89,13 @@ type FS interface {
 
 //go:generate mockgen -package mocks -destination mocks/log_fetcher.go github.com/pivotal-cf/pcfdev-cli/vm LogFetcher
 type LogFetcher interface {
-	FetchLogs() error
+	FetchLogs(ctx context.Context) error
+}
+
+//go:generate mockgen -package mocks -destination mocks/archiver.go github.com/pivotal-cf/pcfdev-cli/vm Archiver
+type Archiver interface {
+	Backup(ctx context.Context, path string) error
+	Restore(ctx context.Context, path string) error
 }
 
 //go:generate mockgen -package mocks -destination mocks/driver.go github.com/pivotal-cf/pcfdev-cli/vm Driver
@@ -93,6 +106,7 @@ type Driver interface {
 //go:generate mockgen -package mocks -destination mocks/cmd_runner.go github.com/pivotal-cf/pcfdev-cli/vm CmdRunner
 type CmdRunner interface {
 	Run(command string, args ...string) (output []byte, err error)
+	RunContext(ctx context.Context, command string, args ...string) (output []byte, err error)
 }
 
 //go:generate mockgen -package mocks -destination mocks/help_text.go github.com/pivotal-cf/pcfdev-cli/vm HelpText
@@ -118,4 +132,5 @@ type StartOpts struct {
 	IP             string
 	Domain         string
 	MasterPassword string
+	NetworkMode    string
 }